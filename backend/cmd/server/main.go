@@ -8,8 +8,14 @@ import (
 	"DewaSRY/sociomile-app/internal/handlers"
 	"DewaSRY/sociomile-app/internal/routers"
 	serviceImpl "DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/internal/workers"
 	jwtUtils "DewaSRY/sociomile-app/pkg/lib/jwt"
 	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/lib/mail"
+	"DewaSRY/sociomile-app/pkg/lib/outbound"
+	"DewaSRY/sociomile-app/pkg/lib/ratelimit"
+	"DewaSRY/sociomile-app/pkg/models"
+	"log"
 )
 
 // @title           Sociomile API
@@ -25,12 +31,35 @@ func main() {
 	// setup
 	cfg := config.Load()
 	logger.Init()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
 
 	db := database.Connect()
 
 	// app context
 	jwtSvc := jwtUtils.NewJwtService()
+	var mailer mail.Mailer
+	switch cfg.MailDriver {
+	case "log":
+		mailer = mail.NewLogMailer(cfg.SMTPFromAddress)
+	case "file":
+		mailer = mail.NewFileMailer(cfg.MailFileDir, cfg.SMTPFromAddress)
+	default:
+		mailer = mail.NewSmtpMailer(
+			cfg.SMTPRelayHost,
+			cfg.SMTPRelayPort,
+			cfg.SMTPRelayUsername,
+			cfg.SMTPRelayPassword,
+			cfg.SMTPFromAddress,
+		)
+	}
 	authServiceSvc := serviceImpl.NewAuthService(db,jwtSvc)
+	magicLinkSvc := serviceImpl.NewMagicLinkService(db, jwtSvc, mailer, serviceImpl.MagicLinkOptions{
+		URL:     cfg.MagicLinkURL,
+		TTL:     cfg.MagicLinkTTL,
+		PerHour: cfg.MagicLinkPerHour,
+	})
 
 	authorizeSvc := serviceImpl.NewAuthorizeService(db)
 	hubSvc := serviceImpl.NewHubServiceImpl(db)
@@ -38,28 +67,80 @@ func main() {
 	organizationConversationSvc := serviceImpl.NewConversationService(db)
 	organizationCrudSvc := serviceImpl.NewOrganizationCrudService(db)
 	tickerSvc := serviceImpl.NewTicketService(db)
+	ticketSLASvc := serviceImpl.NewTicketSLAService(db, mailer)
+	ticketFieldSvc := serviceImpl.NewTicketFieldService(db)
+	ticketTimeSvc := serviceImpl.NewTicketTimeService(db)
+	ticketStatusSvc := serviceImpl.NewTicketStatusService(db, serviceImpl.TicketStatusOptions{
+		URL:    cfg.TicketStatusURL,
+		Secret: cfg.TicketStatusLinkSecret,
+		TTL:    cfg.TicketStatusLinkTTL,
+	})
+	bulkSvc := serviceImpl.NewBulkService(db, serviceImpl.BulkOptions{
+		SyncLimit: cfg.BulkSyncLimit,
+		ChunkSize: cfg.BulkChunkSize,
+		MaxItems:  cfg.BulkMaxItems,
+	})
 	organizationSvc := serviceImpl.NewOrganizationService(db)
 	
 	guestConversationSvc := serviceImpl.NewGuestConversationService(db)
 	guestMessageSvc := serviceImpl.NewGuestMessageService(db)
 
 	webHookSvc := serviceImpl.NewWebHookConversationService(db)
-	
+	emailConversationSvc := serviceImpl.NewEmailConversationService(db)
+
+	outboundRegistry := outbound.NewRegistry(
+		outbound.NewEmailDispatcher(models.ConversationChannelEmail, mailer),
+		outbound.NewWebhookDispatcher(models.ConversationChannelWebhook, cfg.OutboundWebhookTimeout),
+	)
+	outboundDeliverySvc := serviceImpl.NewOutboundDeliveryService(db, outboundRegistry, cfg.OutboundMaxAttempts)
+	rateLimitSvc := serviceImpl.NewRateLimitService(db, ratelimit.NewMemoryStore(), serviceImpl.RateLimitDefaults{
+		OrganizationPerMinute: cfg.RateLimitOrganizationPerMinute,
+		ContactPerMinute:      cfg.RateLimitContactPerMinute,
+		IPPerMinute:           cfg.RateLimitIPPerMinute,
+	})
+	contactSvc := serviceImpl.NewContactService(db)
+	contactAttributeSvc := serviceImpl.NewContactAttributeService(db)
+	widgetSvc := serviceImpl.NewWidgetService(db, cfg.WidgetSessionTTL)
+	eventSubscriptionSvc := serviceImpl.NewEventSubscriptionService(
+		db,
+		outbound.NewWebhookSender(cfg.OutboundWebhookTimeout),
+		cfg.EventMaxAttempts,
+	)
+
 	authHandler := handlers.NewAuthHandler(authServiceSvc, jwtSvc)
+	magicLinkHandler := handlers.NewMagicLinkHandler(magicLinkSvc)
 	organizationHandler := handlers.NewOrganizationHandler(organizationCrudSvc)
 	orgStaffHandler := handlers.NewOrganizationStaffHandler(jwtSvc, organizationSvc)
-	organizationTicketHandler := handlers.NewOrganizationTicketHandler(tickerSvc)
-	OrganizationConversationHandler := handlers.NewOrganizationConversationHandler(jwtSvc, organizationConversationSvc)
+	organizationTicketHandler := handlers.NewOrganizationTicketHandler(jwtSvc, tickerSvc)
+	orgTicketSLAHandler := handlers.NewOrganizationTicketSLAHandler(jwtSvc, ticketSLASvc)
+	orgBulkHandler := handlers.NewOrganizationBulkHandler(jwtSvc, bulkSvc)
+	orgSettingsHandler := handlers.NewOrganizationSettingsHandler(jwtSvc, organizationSvc)
+	orgTicketFieldHandler := handlers.NewOrganizationTicketFieldHandler(jwtSvc, ticketFieldSvc)
+	orgTicketTimeHandler := handlers.NewOrganizationTicketTimeHandler(jwtSvc, ticketTimeSvc)
+	orgTicketStatusHandler := handlers.NewOrganizationTicketStatusHandler(jwtSvc, ticketStatusSvc)
+	OrganizationConversationHandler := handlers.NewOrganizationConversationHandler(jwtSvc, organizationConversationSvc, outboundDeliverySvc)
+
+	orgEventSubscriptionHandler := handlers.NewOrganizationEventSubscriptionHandler(jwtSvc, eventSubscriptionSvc)
+	orgContactHandler := handlers.NewOrganizationContactHandler(jwtSvc, contactSvc)
+	orgContactAttributeHandler := handlers.NewOrganizationContactAttributeHandler(jwtSvc, contactAttributeSvc)
+	orgWidgetHandler := handlers.NewOrganizationWidgetHandler(jwtSvc, widgetSvc)
+	orgWebhookHandler := handlers.NewOrganizationWebhookHandler(jwtSvc, webHookSvc)
 
 	hubHandler := handlers.NewHubHandler(hubSvc)
+	hubWebhookInboxHandler := handlers.NewHubWebhookInboxHandler(webHookSvc)
+	hubRateLimitHandler := handlers.NewHubRateLimitHandler(rateLimitSvc)
 	guestConversationHandler := handlers.NewGuestConversationHandler(jwtSvc, guestConversationSvc)
 	guestMessageHandler := handlers.NewGuestMessageHandler(jwtSvc, guestMessageSvc)
+	guestTicketHandler := handlers.NewGuestTicketHandler(jwtSvc, ticketStatusSvc)
 
-	webHookHandler := handlers.NewWebHookHandler(webHookSvc)
+	webHookHandler := handlers.NewWebHookHandler(webHookSvc, outboundDeliverySvc)
+	widgetHandler := handlers.NewWidgetHandler(widgetSvc)
 
 	authRouter := routers.AuthRouter{
 		JwtService:  jwtSvc,
 		AuthHandler: *authHandler,
+		MagicLinkHandler: *magicLinkHandler,
+		RateLimitService: rateLimitSvc,
 	}
 
 	organizationRouter := routers.OrganizationRouter{
@@ -69,22 +150,103 @@ func main() {
 		OrgStaffHandler:     *orgStaffHandler,
 		OrgTicketHandler: *organizationTicketHandler,
 		OrgConversationHandler: *OrganizationConversationHandler,
+		OrgEventSubscriptionHandler: *orgEventSubscriptionHandler,
+		OrgContactHandler:           *orgContactHandler,
+		OrgContactAttributeHandler:  *orgContactAttributeHandler,
+		OrgWidgetHandler:            *orgWidgetHandler,
+		OrgWebhookHandler:           *orgWebhookHandler,
+		OrgTicketSLAHandler:         *orgTicketSLAHandler,
+		OrgBulkHandler:              *orgBulkHandler,
+		OrgSettingsHandler:          *orgSettingsHandler,
+		OrgTicketFieldHandler:       *orgTicketFieldHandler,
+		OrgTicketTimeHandler:        *orgTicketTimeHandler,
+		OrgTicketStatusHandler:      *orgTicketStatusHandler,
 	}
 
 	hubRouter := routers.HubRouter{
 		JwtService:       jwtSvc,
 		AuthorizeService: authorizeSvc,
 		HubHandler:       *hubHandler,
+		WebhookInboxHandler: *hubWebhookInboxHandler,
+		RateLimitHandler:    *hubRateLimitHandler,
 	}
 
 	guestRoute := routers.GuestRouter{
 		JwtService:               jwtSvc,
 		GuestConversationHandler: *guestConversationHandler,
 		GuestMessageHandler:      *guestMessageHandler,
+		GuestTicketHandler:       *guestTicketHandler,
+		RateLimitService:         rateLimitSvc,
 	}
 
 	webHookRoute := routers.WebHook{
 		WebHookHandler : *webHookHandler,
+		RateLimitService: rateLimitSvc,
+		WebHookService:   webHookSvc,
+	}
+
+	widgetRoute := routers.WidgetRouter{
+		WidgetService:    widgetSvc,
+		WidgetHandler:    *widgetHandler,
+		RateLimitService: rateLimitSvc,
+	}
+
+	backgroundWorkers := []workers.Worker{
+		workers.NewTickerWorker("outbound-delivery", cfg.OutboundPollInterval, func() {
+			if _, err := outboundDeliverySvc.ProcessDueDeliveries(cfg.OutboundBatchSize); err != nil {
+				logger.ErrorLog("Failed to process outbound deliveries", map[string]any{
+					"error": err.Error(),
+				})
+			}
+		}),
+		workers.NewTickerWorker("webhook-inbox", cfg.WebhookInboxPollInterval, func() {
+			if _, err := webHookSvc.ProcessInbox(cfg.WebhookInboxWorkers, cfg.WebhookInboxBatchSize); err != nil {
+				logger.ErrorLog("Failed to process webhook inbox", map[string]any{
+					"error": err.Error(),
+				})
+			}
+		}),
+		workers.NewTickerWorker("rate-limit-hits", cfg.RateLimitFlushInterval, func() {
+			if err := rateLimitSvc.FlushHits(); err != nil {
+				logger.ErrorLog("Failed to flush rate limit hits", map[string]any{
+					"error": err.Error(),
+				})
+			}
+		}),
+		workers.NewTickerWorker("ticket-sla", cfg.TicketSLAPollInterval, func() {
+			if _, err := ticketSLASvc.ProcessDueTickets(cfg.TicketSLABatchSize); err != nil {
+				logger.ErrorLog("Failed to process ticket SLAs", map[string]any{
+					"error": err.Error(),
+				})
+			}
+		}),
+		workers.NewTickerWorker("bulk-jobs", cfg.BulkPollInterval, func() {
+			if _, err := bulkSvc.ProcessPendingJobs(cfg.BulkJobBatchSize); err != nil {
+				logger.ErrorLog("Failed to process bulk jobs", map[string]any{
+					"error": err.Error(),
+				})
+			}
+		}),
+		workers.NewTickerWorker("event-delivery", cfg.EventPollInterval, func() {
+			if _, err := eventSubscriptionSvc.ProcessDueDeliveries(cfg.EventBatchSize); err != nil {
+				logger.ErrorLog("Failed to process event deliveries", map[string]any{
+					"error": err.Error(),
+				})
+			}
+		}),
+	}
+	if cfg.SMTPInboundEnabled {
+		backgroundWorkers = append(backgroundWorkers, mail.NewInboundServer(
+			mail.InboundOptions{
+				Addr:            cfg.SMTPInboundAddr,
+				Hostname:        cfg.SMTPInboundHostname,
+				MaxMessageBytes: cfg.SMTPInboundMaxBytes,
+				MaxRecipients:   cfg.SMTPInboundMaxRecipients,
+				ReadTimeout:     cfg.SMTPInboundReadTimeout,
+				SessionTimeout:  cfg.SMTPInboundSessionTimeout,
+			},
+			emailConversationSvc,
+		))
 	}
 
 	restAPIConfig := &config.RestAPIConfig{
@@ -94,6 +256,8 @@ func main() {
 		OrganizationRouter: organizationRouter,
 		GuestRouter:        guestRoute,
 		WebHookRouter: webHookRoute,
+		WidgetRouter:  widgetRoute,
+		Workers:       backgroundWorkers,
 	}
 
 	restAPIConfig.Run()
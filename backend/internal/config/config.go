@@ -1,7 +1,12 @@
 package config
 
 import (
+	"errors"
 	"os"
+	"strconv"
+	"time"
+
+	"DewaSRY/sociomile-app/pkg/utils"
 
 	"github.com/joho/godotenv"
 )
@@ -12,6 +17,78 @@ type Config struct {
 	DatabaseURL string
 	JWTSecret   string
 	AppEnv      string
+
+	// embedded smtp listener for the email channel, the read timeout is
+	// per line and the session timeout per connection
+	SMTPInboundEnabled        bool
+	SMTPInboundAddr           string
+	SMTPInboundHostname       string
+	SMTPInboundMaxBytes       int
+	SMTPInboundMaxRecipients  int
+	SMTPInboundReadTimeout    time.Duration
+	SMTPInboundSessionTimeout time.Duration
+
+	// outgoing mail relay, point it to mailpit/mailhog on local
+	SMTPRelayHost     string
+	SMTPRelayPort     string
+	SMTPRelayUsername string
+	SMTPRelayPassword string
+	SMTPFromAddress   string
+
+	// where outgoing mail goes: smtp, log or file (written to MailFileDir)
+	MailDriver  string
+	MailFileDir string
+
+	// passwordless login for customers, the link opens MagicLinkURL?token=...
+	MagicLinkURL     string
+	MagicLinkTTL     time.Duration
+	MagicLinkPerHour int
+
+	// signed public ticket status links, the link opens
+	// TicketStatusURL?token=... and the secret is required, see Validate
+	TicketStatusURL        string
+	TicketStatusLinkSecret string
+	TicketStatusLinkTTL    time.Duration
+
+	// how long an anonymous chat widget session lives
+	WidgetSessionTTL time.Duration
+
+	// outbound delivery queue for replies to external channels
+	OutboundPollInterval   time.Duration
+	OutboundBatchSize      int
+	OutboundMaxAttempts    int
+	OutboundWebhookTimeout time.Duration
+
+	// event subscriptions of tenant integrations
+	EventPollInterval time.Duration
+	EventBatchSize    int
+	EventMaxAttempts  int
+
+	// scheduler of ticket SLA warnings and breaches
+	TicketSLAPollInterval time.Duration
+	TicketSLABatchSize    int
+
+	// bulk ticket and conversation actions, batches above the sync limit
+	// run as background jobs
+	BulkSyncLimit    int
+	BulkChunkSize    int
+	BulkMaxItems     int
+	BulkPollInterval time.Duration
+	BulkJobBatchSize int
+
+	// webhook inbox workers
+	WebhookInboxWorkers      int
+	WebhookInboxBatchSize    int
+	WebhookInboxPollInterval time.Duration
+
+	// ingest rate limits in requests per minute, 0 disables the scope
+	RateLimitOrganizationPerMinute int
+	RateLimitContactPerMinute      int
+	RateLimitIPPerMinute           int
+	RateLimitFlushInterval         time.Duration
+
+	// how long background workers get to drain during shutdown
+	ShutdownTimeout time.Duration
 }
 
 func Load() *Config {
@@ -22,5 +99,96 @@ func Load() *Config {
 		Host:      os.Getenv("HOST"),
 		JWTSecret: os.Getenv("JWT_SECRET"),
 		AppEnv:    os.Getenv("APP_ENV"),
+
+		SMTPInboundEnabled:        getEnvBool("SMTP_INBOUND_ENABLED", false),
+		SMTPInboundAddr:           utils.GetEnv("SMTP_INBOUND_ADDR", ":2525"),
+		SMTPInboundHostname:       utils.GetEnv("SMTP_INBOUND_HOSTNAME", "localhost"),
+		SMTPInboundMaxBytes:       getEnvInt("SMTP_INBOUND_MAX_BYTES", 10*1024*1024),
+		SMTPInboundMaxRecipients:  getEnvInt("SMTP_INBOUND_MAX_RECIPIENTS", 50),
+		SMTPInboundReadTimeout:    getEnvDuration("SMTP_INBOUND_READ_TIMEOUT", time.Minute),
+		SMTPInboundSessionTimeout: getEnvDuration("SMTP_INBOUND_SESSION_TIMEOUT", 10*time.Minute),
+
+		SMTPRelayHost:     utils.GetEnv("SMTP_RELAY_HOST", "127.0.0.1"),
+		SMTPRelayPort:     utils.GetEnv("SMTP_RELAY_PORT", "1025"),
+		SMTPRelayUsername: os.Getenv("SMTP_RELAY_USERNAME"),
+		SMTPRelayPassword: os.Getenv("SMTP_RELAY_PASSWORD"),
+		SMTPFromAddress:   utils.GetEnv("SMTP_FROM_ADDRESS", "no-reply@sociomile.local"),
+
+		MailDriver:  utils.GetEnv("MAIL_DRIVER", "smtp"),
+		MailFileDir: utils.GetEnv("MAIL_FILE_DIR", "./tmp/mail"),
+
+		MagicLinkURL:     utils.GetEnv("MAGIC_LINK_URL", "http://localhost:3000/auth/magic-link"),
+		MagicLinkTTL:     getEnvDuration("MAGIC_LINK_TTL", 15*time.Minute),
+		MagicLinkPerHour: getEnvInt("MAGIC_LINK_PER_HOUR", 5),
+
+		TicketStatusURL:        utils.GetEnv("TICKET_STATUS_URL", "http://localhost:3000/ticket-status"),
+		TicketStatusLinkSecret: os.Getenv("TICKET_STATUS_LINK_SECRET"),
+		TicketStatusLinkTTL:    getEnvDuration("TICKET_STATUS_LINK_TTL", 7*24*time.Hour),
+
+		WidgetSessionTTL: getEnvDuration("WIDGET_SESSION_TTL", 30*24*time.Hour),
+
+		OutboundPollInterval:   getEnvDuration("OUTBOUND_POLL_INTERVAL", 5*time.Second),
+		OutboundBatchSize:      getEnvInt("OUTBOUND_BATCH_SIZE", 20),
+		OutboundMaxAttempts:    getEnvInt("OUTBOUND_MAX_ATTEMPTS", 6),
+		OutboundWebhookTimeout: getEnvDuration("OUTBOUND_WEBHOOK_TIMEOUT", 10*time.Second),
+
+		EventPollInterval: getEnvDuration("EVENT_POLL_INTERVAL", 5*time.Second),
+		EventBatchSize:    getEnvInt("EVENT_BATCH_SIZE", 50),
+		EventMaxAttempts:  getEnvInt("EVENT_MAX_ATTEMPTS", 8),
+
+		TicketSLAPollInterval: getEnvDuration("TICKET_SLA_POLL_INTERVAL", time.Minute),
+		TicketSLABatchSize:    getEnvInt("TICKET_SLA_BATCH_SIZE", 100),
+
+		BulkSyncLimit:    getEnvInt("BULK_SYNC_LIMIT", 100),
+		BulkChunkSize:    getEnvInt("BULK_CHUNK_SIZE", 50),
+		BulkMaxItems:     getEnvInt("BULK_MAX_ITEMS", 10000),
+		BulkPollInterval: getEnvDuration("BULK_POLL_INTERVAL", 2*time.Second),
+		BulkJobBatchSize: getEnvInt("BULK_JOB_BATCH_SIZE", 2),
+
+		WebhookInboxWorkers:      getEnvInt("WEBHOOK_INBOX_WORKERS", 4),
+		WebhookInboxBatchSize:    getEnvInt("WEBHOOK_INBOX_BATCH_SIZE", 100),
+		WebhookInboxPollInterval: getEnvDuration("WEBHOOK_INBOX_POLL_INTERVAL", time.Second),
+
+		RateLimitOrganizationPerMinute: getEnvInt("RATE_LIMIT_ORGANIZATION_PER_MINUTE", 600),
+		RateLimitContactPerMinute:      getEnvInt("RATE_LIMIT_CONTACT_PER_MINUTE", 30),
+		RateLimitIPPerMinute:           getEnvInt("RATE_LIMIT_IP_PER_MINUTE", 120),
+		RateLimitFlushInterval:         getEnvDuration("RATE_LIMIT_FLUSH_INTERVAL", 30*time.Second),
+
+		ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+	}
+}
+
+// Validate reports settings the server can not start without.
+func (c *Config) Validate() error {
+	if c.TicketStatusLinkSecret == "" {
+		return errors.New("TICKET_STATUS_LINK_SECRET is required")
+	}
+	if c.TicketStatusLinkSecret == c.JWTSecret {
+		return errors.New("TICKET_STATUS_LINK_SECRET must differ from JWT_SECRET")
+	}
+	return nil
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return defaultValue
 	}
+	return value
 }
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"net/http"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"DewaSRY/sociomile-app/internal/routers"
+	"DewaSRY/sociomile-app/internal/workers"
 	"DewaSRY/sociomile-app/pkg/lib/logger"
 
 	"github.com/go-chi/chi/v5"
@@ -23,9 +25,11 @@ type RestAPIConfig struct {
 	OrganizationRouter routers.OrganizationRouter
 	GuestRouter	routers.GuestRouter
 	WebHookRouter routers.WebHook
+	WidgetRouter  routers.WidgetRouter
+	Workers       []workers.Worker
 }
 
-func gracefulShutdown(apiServer *http.Server, done chan bool) {
+func gracefulShutdown(apiServer *http.Server, backgroundWorkers []workers.Worker, drainTimeout time.Duration, done chan bool) {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 	<-ctx.Done()
@@ -38,6 +42,24 @@ func gracefulShutdown(apiServer *http.Server, done chan bool) {
 			"server": err.Error(),
 		})
 	}
+
+	// the server stopped accepting requests, give the workers their own
+	// deadline to finish what they already claimed
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer drainCancel()
+	var wg sync.WaitGroup
+	for _, worker := range backgroundWorkers {
+		wg.Add(1)
+		go func(worker workers.Worker) {
+			defer wg.Done()
+			if err := worker.Shutdown(drainCtx); err != nil {
+				logger.ErrorLog("Worker forced to shutdown with error", map[string]any{
+					"worker": err.Error(),
+				})
+			}
+		}(worker)
+	}
+	wg.Wait()
 	logger.InfoLog("Server exiting", map[string]any{})
 	done <- true
 }
@@ -48,7 +70,6 @@ func (cfg *RestAPIConfig) Run() {
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.RequestID)
-	r.Use(middleware.AllowContentType("application/json"))
 
 	// Swagger documentation
 	r.Get("/swagger/*", httpSwagger.Handler(
@@ -61,6 +82,7 @@ func (cfg *RestAPIConfig) Run() {
 		cfg.OrganizationRouter.Register(r)
 		cfg.GuestRouter.Register(r)
 		cfg.WebHookRouter.Register(r)
+		cfg.WidgetRouter.Register(r)
 	})
 
 	server := &http.Server{
@@ -71,8 +93,12 @@ func (cfg *RestAPIConfig) Run() {
 		WriteTimeout: 30 * time.Second,
 	}
 
+	for _, worker := range cfg.Workers {
+		worker.Start()
+	}
+
 	done := make(chan bool, 1)
-	go gracefulShutdown(server, done)
+	go gracefulShutdown(server, cfg.Workers, cfg.Config.ShutdownTimeout, done)
 
 	logger.InfoLog(fmt.Sprintf("json documentation:  %s/swagger/doc.json", cfg.Config.Host), map[string]any{
 		"message": fmt.Sprintf("Server running in : %s", cfg.Config.Host),
@@ -12,11 +12,131 @@ func ClearAllInitTables() error {
 	db := database.DB
 	log.Println("Starting to clear all tables...")
 
+	if err := db.Exec("DELETE FROM ticket_time_entries").Error; err != nil {
+		return fmt.Errorf("failed to clear ticket_time_entries: %v", err)
+	}
+	log.Println("Cleared ticket_time_entries table")
+
+	if err := db.Exec("DELETE FROM ticket_field_values").Error; err != nil {
+		return fmt.Errorf("failed to clear ticket_field_values: %v", err)
+	}
+	log.Println("Cleared ticket_field_values table")
+
+	if err := db.Exec("DELETE FROM ticket_field_definitions").Error; err != nil {
+		return fmt.Errorf("failed to clear ticket_field_definitions: %v", err)
+	}
+	log.Println("Cleared ticket_field_definitions table")
+
+	if err := db.Exec("DELETE FROM bulk_jobs").Error; err != nil {
+		return fmt.Errorf("failed to clear bulk_jobs: %v", err)
+	}
+	log.Println("Cleared bulk_jobs table")
+
+	if err := db.Exec("DELETE FROM tags").Error; err != nil {
+		return fmt.Errorf("failed to clear tags: %v", err)
+	}
+	log.Println("Cleared tags table")
+
+	if err := db.Exec("DELETE FROM widget_sessions").Error; err != nil {
+		return fmt.Errorf("failed to clear widget_sessions: %v", err)
+	}
+	log.Println("Cleared widget_sessions table")
+
+	if err := db.Exec("DELETE FROM magic_link_tokens").Error; err != nil {
+		return fmt.Errorf("failed to clear magic_link_tokens: %v", err)
+	}
+	log.Println("Cleared magic_link_tokens table")
+
+	if err := db.Exec("DELETE FROM magic_link_requests").Error; err != nil {
+		return fmt.Errorf("failed to clear magic_link_requests: %v", err)
+	}
+	log.Println("Cleared magic_link_requests table")
+
+	if err := db.Exec("DELETE FROM status_changes").Error; err != nil {
+		return fmt.Errorf("failed to clear status_changes: %v", err)
+	}
+	log.Println("Cleared status_changes table")
+
+	if err := db.Exec("DELETE FROM ticket_links").Error; err != nil {
+		return fmt.Errorf("failed to clear ticket_links: %v", err)
+	}
+	log.Println("Cleared ticket_links table")
+
+	if err := db.Exec("DELETE FROM ticket_conversations").Error; err != nil {
+		return fmt.Errorf("failed to clear ticket_conversations: %v", err)
+	}
+	log.Println("Cleared ticket_conversations table")
+
+	if err := db.Exec("DELETE FROM ticket_escalations").Error; err != nil {
+		return fmt.Errorf("failed to clear ticket_escalations: %v", err)
+	}
+	log.Println("Cleared ticket_escalations table")
+
+	if err := db.Exec("DELETE FROM ticket_sla_policies").Error; err != nil {
+		return fmt.Errorf("failed to clear ticket_sla_policies: %v", err)
+	}
+	log.Println("Cleared ticket_sla_policies table")
+
+	if err := db.Exec("DELETE FROM ticket_workflows").Error; err != nil {
+		return fmt.Errorf("failed to clear ticket_workflows: %v", err)
+	}
+	log.Println("Cleared ticket_workflows table")
+
+	if err := db.Exec("DELETE FROM ticket_activities").Error; err != nil {
+		return fmt.Errorf("failed to clear ticket_activities: %v", err)
+	}
+	log.Println("Cleared ticket_activities table")
+
+	if err := db.Exec("DELETE FROM ticket_comments").Error; err != nil {
+		return fmt.Errorf("failed to clear ticket_comments: %v", err)
+	}
+	log.Println("Cleared ticket_comments table")
+
+	if err := db.Exec("DELETE FROM ticket_sequences").Error; err != nil {
+		return fmt.Errorf("failed to clear ticket_sequences: %v", err)
+	}
+	log.Println("Cleared ticket_sequences table")
+
 	if err := db.Exec("DELETE FROM tickets").Error; err != nil {
 		return fmt.Errorf("failed to clear tickets: %v", err)
 	}
 	log.Println("Cleared tickets table")
 
+	if err := db.Exec("DELETE FROM rate_limit_hits").Error; err != nil {
+		return fmt.Errorf("failed to clear rate_limit_hits: %v", err)
+	}
+	log.Println("Cleared rate_limit_hits table")
+
+	if err := db.Exec("DELETE FROM organization_rate_limits").Error; err != nil {
+		return fmt.Errorf("failed to clear organization_rate_limits: %v", err)
+	}
+	log.Println("Cleared organization_rate_limits table")
+
+	if err := db.Exec("DELETE FROM webhook_inbox_events").Error; err != nil {
+		return fmt.Errorf("failed to clear webhook_inbox_events: %v", err)
+	}
+	log.Println("Cleared webhook_inbox_events table")
+
+	if err := db.Exec("DELETE FROM event_deliveries").Error; err != nil {
+		return fmt.Errorf("failed to clear event_deliveries: %v", err)
+	}
+	log.Println("Cleared event_deliveries table")
+
+	if err := db.Exec("DELETE FROM event_subscriptions").Error; err != nil {
+		return fmt.Errorf("failed to clear event_subscriptions: %v", err)
+	}
+	log.Println("Cleared event_subscriptions table")
+
+	if err := db.Exec("DELETE FROM outbound_deliveries").Error; err != nil {
+		return fmt.Errorf("failed to clear outbound_deliveries: %v", err)
+	}
+	log.Println("Cleared outbound_deliveries table")
+
+	if err := db.Exec("DELETE FROM conversation_message_attachments").Error; err != nil {
+		return fmt.Errorf("failed to clear conversation_message_attachments: %v", err)
+	}
+	log.Println("Cleared conversation_message_attachments table")
+
 	if err := db.Exec("DELETE FROM conversation_messages").Error; err != nil {
 		return fmt.Errorf("failed to clear conversation_messages: %v", err)
 	}
@@ -27,6 +147,36 @@ func ClearAllInitTables() error {
 	}
 	log.Println("Cleared conversations table")
 
+	if err := db.Exec("DELETE FROM contact_merges").Error; err != nil {
+		return fmt.Errorf("failed to clear contact_merges: %v", err)
+	}
+	log.Println("Cleared contact_merges table")
+
+	if err := db.Exec("DELETE FROM contact_notes").Error; err != nil {
+		return fmt.Errorf("failed to clear contact_notes: %v", err)
+	}
+	log.Println("Cleared contact_notes table")
+
+	if err := db.Exec("DELETE FROM contact_attribute_values").Error; err != nil {
+		return fmt.Errorf("failed to clear contact_attribute_values: %v", err)
+	}
+	log.Println("Cleared contact_attribute_values table")
+
+	if err := db.Exec("DELETE FROM contact_attribute_definitions").Error; err != nil {
+		return fmt.Errorf("failed to clear contact_attribute_definitions: %v", err)
+	}
+	log.Println("Cleared contact_attribute_definitions table")
+
+	if err := db.Exec("DELETE FROM contact_identities").Error; err != nil {
+		return fmt.Errorf("failed to clear contact_identities: %v", err)
+	}
+	log.Println("Cleared contact_identities table")
+
+	if err := db.Exec("DELETE FROM contacts").Error; err != nil {
+		return fmt.Errorf("failed to clear contacts: %v", err)
+	}
+	log.Println("Cleared contacts table")
+
 	if err := db.Exec("DELETE FROM organizations").Error; err != nil {
 		return fmt.Errorf("failed to clear organizations: %v", err)
 	}
@@ -37,7 +187,7 @@ func ClearAllInitTables() error {
 	}
 	log.Println("Cleared users table")
 
-	tables := []string{"tickets", "conversation_messages", "conversations", "organizations", "users"}
+	tables := []string{"ticket_time_entries", "ticket_field_values", "ticket_field_definitions", "bulk_jobs", "tags", "widget_sessions", "magic_link_tokens", "magic_link_requests", "status_changes", "ticket_links", "ticket_conversations", "ticket_escalations", "ticket_sla_policies", "ticket_workflows", "ticket_activities", "ticket_comments", "ticket_sequences", "tickets", "rate_limit_hits", "organization_rate_limits", "webhook_inbox_events", "event_deliveries", "event_subscriptions", "outbound_deliveries", "conversation_message_attachments", "conversation_messages", "conversations", "contact_merges", "contact_notes", "contact_attribute_values", "contact_attribute_definitions", "contact_identities", "contacts", "organizations", "users"}
 	for _, table := range tables {
 		if err := db.Exec(fmt.Sprintf("ALTER TABLE %s AUTO_INCREMENT = 1", table)).Error; err != nil {
 			log.Printf("Warning: Could not reset auto-increment for %s: %v", table, err)
@@ -92,9 +242,12 @@ func SeedInitialData() error {
 	}
 	log.Printf("Created Organization Owner: %s", orgOwner.Email)
 
+	slug := "techcorp-solutions"
 	organization := models.OrganizationModel{
-		Name:    "TechCorp Solutions",
-		OwnerID: orgOwner.ID,
+		Name:                 "TechCorp Solutions",
+		OwnerID:              orgOwner.ID,
+		Slug:                 &slug,
+		WidgetAllowedOrigins: []string{"http://localhost:3000"},
 	}
 	if err := db.Create(&organization).Error; err != nil {
 		return fmt.Errorf("failed to create organization: %v", err)
@@ -168,9 +321,58 @@ func SeedInitialData() error {
 	log.Printf("✓ Created Guest User: %s", guest3.Email)
 	//##############################################################################################
 
+	contacts := make([]models.ContactModel, 0, 3)
+	for _, guest := range []models.UserModel{guest1, guest2, guest3} {
+		matchKey := "email:" + guest.Email
+		contact := models.ContactModel{
+			OrganizationID: organization.ID,
+			Name:           guest.Name,
+			UserID:         &guest.ID,
+			Identities: []models.ContactIdentityModel{
+				{
+					OrganizationID: organization.ID,
+					Type:           models.ContactIdentityTypeEmail,
+					Value:          guest.Email,
+					MatchKey:       &matchKey,
+				},
+			},
+		}
+		if err := db.Create(&contact).Error; err != nil {
+			return fmt.Errorf("failed to create contact for %s: %v", guest.Email, err)
+		}
+		contacts = append(contacts, contact)
+	}
+	log.Printf("Created %d Contacts", len(contacts))
+
+	tier := models.ContactAttributeDefinitionModel{
+		OrganizationID: organization.ID,
+		Key:            "tier",
+		Label:          "Customer tier",
+		Type:           models.ContactAttributeTypeEnum,
+		Options:        []string{"bronze", "silver", "gold"},
+	}
+	city := models.ContactAttributeDefinitionModel{
+		OrganizationID: organization.ID,
+		Key:            "city",
+		Label:          "City",
+		Type:           models.ContactAttributeTypeString,
+	}
+	if err := db.Create(&[]*models.ContactAttributeDefinitionModel{&tier, &city}).Error; err != nil {
+		return fmt.Errorf("failed to create contact attributes: %v", err)
+	}
+	if err := db.Create(&[]models.ContactAttributeValueModel{
+		{OrganizationID: organization.ID, ContactID: contacts[0].ID, DefinitionID: tier.ID, Value: "gold"},
+		{OrganizationID: organization.ID, ContactID: contacts[0].ID, DefinitionID: city.ID, Value: "Jakarta"},
+	}).Error; err != nil {
+		return fmt.Errorf("failed to create contact attribute values: %v", err)
+	}
+	log.Println("Created contact attributes tier and city")
+	//##############################################################################################
+
 	conv1 := models.ConversationModel{
 		OrganizationID: organization.ID,
-		GuestID:        guest1.ID,
+		GuestID:        &guest1.ID,
+		ContactID:      contacts[0].ID,
 		Status:         models.ConversationStatusPending,
 	}
 	if err := db.Create(&conv1).Error; err != nil {
@@ -180,7 +382,8 @@ func SeedInitialData() error {
 
 	conv2 := models.ConversationModel{
 		OrganizationID:      organization.ID,
-		GuestID:             guest2.ID,
+		GuestID:             &guest2.ID,
+		ContactID:           contacts[1].ID,
 		OrganizationStaffID: &salesStaff1.ID,
 		Status:              models.ConversationStatusInProgress,
 	}
@@ -191,7 +394,8 @@ func SeedInitialData() error {
 
 	conv3 := models.ConversationModel{
 		OrganizationID:      organization.ID,
-		GuestID:             guest3.ID,
+		GuestID:             &guest3.ID,
+		ContactID:           contacts[2].ID,
 		OrganizationStaffID: &salesStaff2.ID,
 		Status:              models.ConversationStatusInProgress,
 	}
@@ -202,7 +406,8 @@ func SeedInitialData() error {
 
 	conv4 := models.ConversationModel{
 		OrganizationID:      organization.ID,
-		GuestID:             guest1.ID,
+		GuestID:             &guest1.ID,
+		ContactID:           contacts[0].ID,
 		OrganizationStaffID: &salesStaff1.ID,
 		Status:              models.ConversationStatusDone,
 	}
@@ -215,7 +420,8 @@ func SeedInitialData() error {
 		{
 			OrganizationID: organization.ID,
 			ConversationID: conv1.ID,
-			CreatedByID:    guest1.ID,
+			CreatedByID:    &guest1.ID,
+			ContactID:      &contacts[0].ID,
 			Message:        "Hello, I need help with your product pricing.",
 		},
 	
@@ -232,9 +438,11 @@ func SeedInitialData() error {
 		OrganizationID: organization.ID,
 		ConversationID: conv2.ID,
 		CreatedByID:    salesStaff1.ID,
-		TicketNumber:   fmt.Sprintf("TKT-%d-20260214-0001", organization.ID),
+		TicketNumber:   "TKT-20260214-0001",
 		Name:           "Account Login Issue",
 		Status:         models.TicketStatusInProgress,
+		AssigneeID:     &salesStaff1.ID,
+		Priority:       models.TicketPriorityHigh,
 	}
 	if err := db.Create(&ticket1).Error; err != nil {
 		return fmt.Errorf("failed to create ticket 1: %v", err)
@@ -245,9 +453,11 @@ func SeedInitialData() error {
 		OrganizationID: organization.ID,
 		ConversationID: conv3.ID,
 		CreatedByID:    salesStaff2.ID,
-		TicketNumber:   fmt.Sprintf("TKT-%d-20260214-0002", organization.ID),
+		TicketNumber:   "TKT-20260214-0002",
 		Name:           "Enterprise Plan Inquiry",
 		Status:         models.TicketStatusPending,
+		AssigneeID:     &salesStaff2.ID,
+		Priority:       models.TicketPriorityNormal,
 	}
 	if err := db.Create(&ticket2).Error; err != nil {
 		return fmt.Errorf("failed to create ticket 2: %v", err)
@@ -258,12 +468,77 @@ func SeedInitialData() error {
 		OrganizationID: organization.ID,
 		ConversationID: conv4.ID,
 		CreatedByID:    salesStaff1.ID,
-		TicketNumber:   fmt.Sprintf("TKT-%d-20260214-0003", organization.ID),
+		TicketNumber:   "TKT-20260214-0003",
 		Name:           "Product Setup Assistance",
 		Status:         models.TicketStatusDone,
+		StatusCategory: models.TicketStatusCategoryClosed,
+		AssigneeID:     &salesStaff1.ID,
+		Priority:       models.TicketPriorityLow,
 	}
 	if err := db.Create(&ticket3).Error; err != nil {
 		return fmt.Errorf("failed to create ticket 3: %v", err)
 	}
+
+	sequence := models.TicketSequenceModel{
+		OrganizationID: organization.ID,
+		Prefix:         models.TicketNumberDefaultPrefix,
+		DatePart:       models.TicketDatePartDay,
+		Padding:        models.TicketNumberDefaultPadding,
+		Period:         "20260214",
+		LastValue:      3,
+	}
+	if err := db.Create(&sequence).Error; err != nil {
+		return fmt.Errorf("failed to create ticket sequence: %v", err)
+	}
+
+	for _, ticket := range []models.TicketModel{ticket1, ticket2, ticket3} {
+		activity := models.TicketActivityModel{
+			OrganizationID: ticket.OrganizationID,
+			TicketID:       ticket.ID,
+			ActorID:        &ticket.CreatedByID,
+			Action:         models.TicketActivityCreated,
+		}
+		if err := db.Create(&activity).Error; err != nil {
+			return fmt.Errorf("failed to create ticket activity: %v", err)
+		}
+
+		if err := db.Create(&models.TicketConversationModel{
+			OrganizationID: ticket.OrganizationID,
+			TicketID:       ticket.ID,
+			ConversationID: ticket.ConversationID,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to create ticket conversation: %v", err)
+		}
+	}
+
+	if err := db.Create(&models.TicketLinkModel{
+		OrganizationID: organization.ID,
+		TicketID:       ticket1.ID,
+		LinkedTicketID: ticket2.ID,
+		Type:           models.TicketLinkRelated,
+		CreatedByID:    &salesStaff1.ID,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to create ticket link: %v", err)
+	}
+
+	comment := models.TicketCommentModel{
+		OrganizationID: organization.ID,
+		TicketID:       ticket1.ID,
+		AuthorID:       salesStaff1.ID,
+		Visibility:     models.TicketCommentInternal,
+		Body:           "Password reset link was sent twice, checking the mail logs.",
+	}
+	if err := db.Create(&comment).Error; err != nil {
+		return fmt.Errorf("failed to create ticket comment: %v", err)
+	}
+	if err := db.Create(&models.TicketActivityModel{
+		OrganizationID: organization.ID,
+		TicketID:       ticket1.ID,
+		ActorID:        &salesStaff1.ID,
+		Action:         models.TicketActivityCommented,
+		CommentID:      &comment.ID,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to create ticket activity: %v", err)
+	}
 	return nil
 }
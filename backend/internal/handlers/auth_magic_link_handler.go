@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+type MagicLinkHandler struct {
+	service services.MagicLinkService
+}
+
+func NewMagicLinkHandler(service services.MagicLinkService) *MagicLinkHandler {
+	return &MagicLinkHandler{
+		service: service,
+	}
+}
+
+// RequestLink godoc
+// @Summary      Request a magic link
+// @Description  Mail a single-use sign-in link to a customer, the answer is the same whether the email is unknown, belongs to staff or asked too often
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body requestdto.MagicLinkRequest true "Magic Link Request"
+// @Success      202  {object}  responsedto.CommonResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      429  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Router       /auth/magic-link [post]
+func (h *MagicLinkHandler) RequestLink(w http.ResponseWriter, r *http.Request) {
+	var req requestdto.MagicLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := h.service.RequestLink(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to send magic link",
+			Error:   err.Error(),
+			Code:    http.StatusInternalServerError,
+		}
+		logger.ErrorLog("Failed to send magic link", errorData)
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, errorData)
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusAccepted, responsedto.CommonResponse{
+		Message: "If the email belongs to a customer, a sign-in link is on its way",
+		Code:    http.StatusAccepted,
+	})
+}
+
+// VerifyLink godoc
+// @Summary      Sign in with a magic link
+// @Description  Exchange the token of a magic link for a JWT, the link cannot be used again
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body requestdto.VerifyMagicLinkRequest true "Verify Magic Link Request"
+// @Success      200  {object}  responsedto.AuthResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      401  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Router       /auth/magic-link/verify [post]
+func (h *MagicLinkHandler) VerifyLink(w http.ResponseWriter, r *http.Request) {
+	var req requestdto.VerifyMagicLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	result, err := h.service.VerifyLink(req)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, impl.ErrMagicLinkInvalid) {
+			code = http.StatusUnauthorized
+		}
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to sign in",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to verify magic link", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("User signed in with magic link", map[string]any{
+		"user_id": result.User.ID,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
@@ -36,6 +36,7 @@ func NewGuestConversationHandler(
 // @Param        request body requestdto.CreateConversationRequest true "Create Conversation Request"
 // @Success      201  {object}  responsedto.CommonResponse
 // @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      429  {object}  responsedto.ErrorResponse
 // @Failure      500  {object}  responsedto.ErrorResponse
 // @Security     BearerAuth
 // @Router       /guest/conversations [post]
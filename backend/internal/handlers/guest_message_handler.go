@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/internal/services/impl"
 	_ "DewaSRY/sociomile-app/pkg/dtos/filtersdto"
 	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
 	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
@@ -9,6 +10,7 @@ import (
 	"DewaSRY/sociomile-app/pkg/lib/logger"
 	"DewaSRY/sociomile-app/pkg/utils"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -39,6 +41,8 @@ func NewGuestMessageHandler(
 // @Param        request body requestdto.CreateConversationMessageRequest true "Send Message Request"
 // @Success      201  {object}  responsedto.CommonResponse
 // @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      429  {object}  responsedto.ErrorResponse
 // @Failure      500  {object}  responsedto.ErrorResponse
 // @Security     BearerAuth
 // @Router       /guest/conversations/messages [post]
@@ -69,13 +73,17 @@ func (t *GuestMessageHandler) SendConversationMessage(w http.ResponseWriter, r *
 	user, _ := t.jwtService.GetUserFromContext(r.Context())
 	err := t.guestMessageSvc.SendConversationMessage(user, req)
 	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, impl.ErrGuestConversationNotFound) {
+			code = http.StatusNotFound
+		}
 		errorData := responsedto.ErrorResponse{
 			Message: "failed to send message",
 			Error:   err.Error(),
-			Code:    http.StatusInternalServerError,
+			Code:    code,
 		}
 		logger.ErrorLog("Failed to send message", errorData)
-		utils.WriteJSONResponse(w, http.StatusInternalServerError, errorData)
+		utils.WriteJSONResponse(w, code, errorData)
 		return
 	}
 
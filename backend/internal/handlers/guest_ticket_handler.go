@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/internal/services/impl"
+	_ "DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/utils"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type GuestTicketHandler struct {
+	jwtService jwtLib.JwtService
+	service    services.TicketStatusService
+}
+
+func NewGuestTicketHandler(
+	jwtService jwtLib.JwtService,
+	service services.TicketStatusService,
+) *GuestTicketHandler {
+	return &GuestTicketHandler{
+		jwtService: jwtService,
+		service:    service,
+	}
+}
+
+// GetTickets godoc
+// @Summary      List guest tickets
+// @Description  List the tickets covering any of the guest's conversations with their number, name and current status, newest change first (Guest user)
+// @Tags         guest-ticket
+// @Accept       json
+// @Produce      json
+// @Param        request  query  filtersdto.FiltersDto  false  "Pagination query"
+// @Success      200  {object}  responsedto.GuestTicketListResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /guest/tickets [get]
+func (h *GuestTicketHandler) GetTickets(w http.ResponseWriter, r *http.Request) {
+	filter := utils.ParsePagination(r)
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.GetGuestTickets(user, filter)
+	if err != nil {
+		code := guestTicketErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch tickets",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch guest tickets", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// GetTicket godoc
+// @Summary      Get a guest ticket
+// @Description  Get a ticket covering one of the guest's conversations with its public comments, oldest first (Guest user)
+// @Tags         guest-ticket
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Ticket ID"
+// @Success      200  {object}  responsedto.GuestTicketResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /guest/tickets/{id} [get]
+func (h *GuestTicketHandler) GetTicket(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid ticket id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid ticket ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.GetGuestTicket(user, uint(id))
+	if err != nil {
+		code := guestTicketErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch ticket",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch guest ticket", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// GetTicketStatus godoc
+// @Summary      Ticket status page
+// @Description  Read a ticket by the token of its signed status link, no account needed. Shows the number, name, current status and public comments
+// @Tags         ticket-status
+// @Accept       json
+// @Produce      json
+// @Param        token path string true "Status link token"
+// @Success      200  {object}  responsedto.GuestTicketResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      429  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Router       /ticket-status/{token} [get]
+func (h *GuestTicketHandler) GetTicketStatus(w http.ResponseWriter, r *http.Request) {
+	result, err := h.service.GetTicketStatus(chi.URLParam(r, "token"))
+	if err != nil {
+		code := guestTicketErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch ticket status",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch ticket status", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+func guestTicketErrorCode(err error) int {
+	switch {
+	case errors.Is(err, impl.ErrTicketNotFound),
+		errors.Is(err, impl.ErrTicketStatusLinkInvalid):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
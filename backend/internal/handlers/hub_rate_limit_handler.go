@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/internal/services/impl"
+	_ "DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type HubRateLimitHandler struct {
+	service services.RateLimitService
+}
+
+func NewHubRateLimitHandler(
+	service services.RateLimitService,
+) *HubRateLimitHandler {
+	return &HubRateLimitHandler{
+		service: service,
+	}
+}
+
+// GetRateLimitHits godoc
+// @Summary      List rate limit hits
+// @Description  Rejected ingest requests per minute, scope and route (Super Admin only)
+// @Tags         Hub
+// @Accept       json
+// @Produce      json
+// @Param        request  query  filtersdto.FiltersDto  false  "Pagination query"
+// @Param        organizationId  query  int  false  "Organization ID"
+// @Success      200  {object}  responsedto.RateLimitHitPaginateResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /hub/rate-limit-hits [get]
+func (h *HubRateLimitHandler) GetRateLimitHits(w http.ResponseWriter, r *http.Request) {
+	filter := utils.ParsePagination(r)
+
+	var organizationID *uint
+	if value := r.URL.Query().Get("organizationId"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			errorData := responsedto.ErrorResponse{
+				Message: "invalid organization id",
+				Error:   err.Error(),
+				Code:    http.StatusBadRequest,
+			}
+			logger.ErrorLog("Invalid organization ID", errorData)
+			utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+			return
+		}
+		parsed := uint(id)
+		organizationID = &parsed
+	}
+
+	result, err := h.service.GetHits(filter, organizationID)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch rate limit hits",
+			Error:   err.Error(),
+			Code:    http.StatusInternalServerError,
+		}
+		logger.ErrorLog("Failed to fetch rate limit hits", errorData)
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, errorData)
+		return
+	}
+
+	logger.InfoLog("Rate limit hits fetched successfully", map[string]any{
+		"count": len(result.Data),
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// GetOrganizationRateLimits godoc
+// @Summary      Get organization rate limits
+// @Description  Effective ingest limits of an organization (Super Admin only)
+// @Tags         Hub
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Organization ID"
+// @Success      200  {object}  responsedto.OrganizationRateLimitResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /hub/organizations/{id}/rate-limits [get]
+func (h *HubRateLimitHandler) GetOrganizationRateLimits(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid organization id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid organization ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	result, err := h.service.GetOrganizationLimits(uint(id))
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, impl.ErrOrganizationNotFound) {
+			code = http.StatusNotFound
+		}
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch rate limits",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch rate limits", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// UpdateOrganizationRateLimits godoc
+// @Summary      Update organization rate limits
+// @Description  Override the default ingest limits of an organization, omit a field to use the default (Super Admin only)
+// @Tags         Hub
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Organization ID"
+// @Param        request body requestdto.UpdateOrganizationRateLimitRequest true "Rate limits"
+// @Success      200  {object}  responsedto.OrganizationRateLimitResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /hub/organizations/{id}/rate-limits [put]
+func (h *HubRateLimitHandler) UpdateOrganizationRateLimits(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid organization id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid organization ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.UpdateOrganizationRateLimitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	result, err := h.service.UpdateOrganizationLimits(uint(id), req)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, impl.ErrOrganizationNotFound) {
+			code = http.StatusNotFound
+		}
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to update rate limits",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to update rate limits", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Rate limits updated successfully", map[string]any{
+		"organization_id": id,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/internal/services/impl"
+	_ "DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/utils"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type HubWebhookInboxHandler struct {
+	service services.WebHookConversationService
+}
+
+func NewHubWebhookInboxHandler(
+	service services.WebHookConversationService,
+) *HubWebhookInboxHandler {
+	return &HubWebhookInboxHandler{
+		service: service,
+	}
+}
+
+// GetInboxEvents godoc
+// @Summary      List webhook inbox events
+// @Description  Inspect the stored webhook events, filter by status=parked to see the ones that need attention (Super Admin only)
+// @Tags         Hub
+// @Accept       json
+// @Produce      json
+// @Param        request  query  filtersdto.FiltersDto  false  "Pagination query"
+// @Param        status   query  string  false  "Event status" Enums(pending, processing, processed, failed, parked)
+// @Success      200  {object}  responsedto.WebhookInboxEventPaginateResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /hub/webhook-inbox [get]
+func (h *HubWebhookInboxHandler) GetInboxEvents(w http.ResponseWriter, r *http.Request) {
+	filter := utils.ParsePagination(r)
+
+	result, err := h.service.GetInboxEvents(filter, r.URL.Query().Get("status"))
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch webhook events",
+			Error:   err.Error(),
+			Code:    http.StatusInternalServerError,
+		}
+		logger.ErrorLog("Failed to fetch webhook events", errorData)
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, errorData)
+		return
+	}
+
+	logger.InfoLog("Webhook events fetched successfully", map[string]any{
+		"count": len(result.Data),
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// RetryInboxEvent godoc
+// @Summary      Retry a parked webhook event
+// @Description  Put a parked or failed webhook event back in the queue (Super Admin only)
+// @Tags         Hub
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Event ID"
+// @Success      200  {object}  responsedto.CommonResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      409  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /hub/webhook-inbox/{id}/retry [post]
+func (h *HubWebhookInboxHandler) RetryInboxEvent(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid event id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid event ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := h.service.RetryInboxEvent(uint(id)); err != nil {
+		code := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, impl.ErrInboxEventNotFound):
+			code = http.StatusNotFound
+		case errors.Is(err, impl.ErrInboxEventNotParked):
+			code = http.StatusConflict
+		}
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to retry webhook event",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to retry webhook event", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	result := responsedto.CommonResponse{
+		Message: "Webhook event queued for retry",
+		Code:    http.StatusOK,
+	}
+	logger.InfoLog("Webhook event queued for retry", map[string]any{
+		"event_id": id,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/models"
+	"DewaSRY/sociomile-app/pkg/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type OrganizationBulkHandler struct {
+	jwtService jwtLib.JwtService
+	service    services.BulkService
+}
+
+func NewOrganizationBulkHandler(
+	jwtService jwtLib.JwtService,
+	service services.BulkService,
+) *OrganizationBulkHandler {
+	return &OrganizationBulkHandler{
+		jwtService: jwtService,
+		service:    service,
+	}
+}
+
+// BulkTickets godoc
+// @Summary      Apply an action to many tickets
+// @Description  Change the status, assign, add or remove a tag, or close the tickets given by ids or by a filter taking the same fields as the ticket list. Every item succeeds or fails on its own, small batches finish right away with 200 and large ones are queued with 202, poll the job until it is completed
+// @Tags         organization-bulk
+// @Accept       json
+// @Produce      json
+// @Param        request body requestdto.BulkTicketRequest true "Bulk Ticket Request"
+// @Success      200  {object}  responsedto.BulkJobResponse
+// @Success      202  {object}  responsedto.BulkJobResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket/bulk [post]
+func (h *OrganizationBulkHandler) BulkTickets(w http.ResponseWriter, r *http.Request) {
+	var req requestdto.BulkTicketRequest
+	if !decodeBulkRequest(w, r, &req) {
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.BulkTickets(user, req)
+	if err != nil {
+		code := bulkErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to run bulk ticket action",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to run bulk ticket action", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Bulk ticket action submitted successfully", map[string]any{
+		"job_id": result.ID,
+		"total":  result.Total,
+	})
+	utils.WriteJSONResponse(w, bulkJobStatusCode(result), result)
+}
+
+// BulkConversations godoc
+// @Summary      Apply an action to many conversations
+// @Description  Change the status, assign, add or remove a tag, or close the conversations given by ids or by a filter. Every item succeeds or fails on its own, small batches finish right away with 200 and large ones are queued with 202, poll the job until it is completed
+// @Tags         organization-bulk
+// @Accept       json
+// @Produce      json
+// @Param        request body requestdto.BulkConversationRequest true "Bulk Conversation Request"
+// @Success      200  {object}  responsedto.BulkJobResponse
+// @Success      202  {object}  responsedto.BulkJobResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/conversations/bulk [post]
+func (h *OrganizationBulkHandler) BulkConversations(w http.ResponseWriter, r *http.Request) {
+	var req requestdto.BulkConversationRequest
+	if !decodeBulkRequest(w, r, &req) {
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.BulkConversations(user, req)
+	if err != nil {
+		code := bulkErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to run bulk conversation action",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to run bulk conversation action", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Bulk conversation action submitted successfully", map[string]any{
+		"job_id": result.ID,
+		"total":  result.Total,
+	})
+	utils.WriteJSONResponse(w, bulkJobStatusCode(result), result)
+}
+
+// GetJob godoc
+// @Summary      Get a bulk job
+// @Description  Get the progress of a bulk job and the result of every item handled so far
+// @Tags         organization-bulk
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Job ID"
+// @Success      200  {object}  responsedto.BulkJobResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/bulk-jobs/{id} [get]
+func (h *OrganizationBulkHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid job id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid job ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.GetJob(user, uint(id))
+	if err != nil {
+		code := bulkErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch bulk job",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch bulk job", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Bulk job fetched successfully", map[string]any{
+		"job_id": result.ID,
+		"status": result.Status,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+func decodeBulkRequest(w http.ResponseWriter, r *http.Request, req any) bool {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return false
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return false
+	}
+
+	return true
+}
+
+// bulkJobStatusCode tells a job that finished during the request apart
+// from one left for the worker.
+func bulkJobStatusCode(job *responsedto.BulkJobResponse) int {
+	if job.Status == models.BulkJobPending {
+		return http.StatusAccepted
+	}
+	return http.StatusOK
+}
+
+func bulkErrorCode(err error) int {
+	switch {
+	case errors.Is(err, impl.ErrOrganizationNotFound),
+		errors.Is(err, impl.ErrBulkJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, impl.ErrBulkRequestInvalid),
+		errors.Is(err, impl.ErrBulkTooManyItems),
+		errors.Is(err, impl.ErrTagInvalid),
+		errors.Is(err, impl.ErrTicketAssigneeInvalid),
+		errors.Is(err, impl.ErrTicketSortInvalid),
+		errors.Is(err, impl.ErrTicketFieldUnknown),
+		errors.Is(err, impl.ErrTicketFieldInvalid):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type OrganizationContactAttributeHandler struct {
+	jwtService jwtLib.JwtService
+	service    services.ContactAttributeService
+}
+
+func NewOrganizationContactAttributeHandler(
+	jwtService jwtLib.JwtService,
+	service services.ContactAttributeService,
+) *OrganizationContactAttributeHandler {
+	return &OrganizationContactAttributeHandler{
+		jwtService: jwtService,
+		service:    service,
+	}
+}
+
+// GetAttributes godoc
+// @Summary      List contact attributes
+// @Description  List the custom attributes the organization stores on its contacts
+// @Tags         organization-contact-attributes
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   responsedto.ContactAttributeResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contact-attributes [get]
+func (h *OrganizationContactAttributeHandler) GetAttributes(w http.ResponseWriter, r *http.Request) {
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.GetAttributes(user)
+	if err != nil {
+		code := contactAttributeErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch contact attributes",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch contact attributes", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Contact attributes fetched successfully", map[string]any{
+		"count": len(result),
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// CreateAttribute godoc
+// @Summary      Create a contact attribute
+// @Description  Define a custom contact attribute of type string, number, date, enum or boolean, enums need their options
+// @Tags         organization-contact-attributes
+// @Accept       json
+// @Produce      json
+// @Param        request body requestdto.CreateContactAttributeRequest true "Create Contact Attribute Request"
+// @Success      201  {object}  responsedto.ContactAttributeResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      409  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contact-attributes [post]
+func (h *OrganizationContactAttributeHandler) CreateAttribute(w http.ResponseWriter, r *http.Request) {
+	var req requestdto.CreateContactAttributeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.CreateAttribute(user, req)
+	if err != nil {
+		code := contactAttributeErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to create contact attribute",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to create contact attribute", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Contact attribute created successfully", map[string]any{
+		"attribute_id": result.ID,
+		"key":          result.Key,
+	})
+	utils.WriteJSONResponse(w, http.StatusCreated, result)
+}
+
+// UpdateAttribute godoc
+// @Summary      Update a contact attribute
+// @Description  Update the label or the enum options of a contact attribute, options still set on contacts cannot be removed
+// @Tags         organization-contact-attributes
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Attribute ID"
+// @Param        request body requestdto.UpdateContactAttributeRequest true "Update Contact Attribute Request"
+// @Success      200  {object}  responsedto.ContactAttributeResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      409  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contact-attributes/{id} [put]
+func (h *OrganizationContactAttributeHandler) UpdateAttribute(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid attribute id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid attribute ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.UpdateContactAttributeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.UpdateAttribute(user, uint(id), req)
+	if err != nil {
+		code := contactAttributeErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to update contact attribute",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to update contact attribute", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Contact attribute updated successfully", map[string]any{
+		"attribute_id": result.ID,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// DeleteAttribute godoc
+// @Summary      Delete a contact attribute
+// @Description  Delete a contact attribute together with its values on every contact
+// @Tags         organization-contact-attributes
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Attribute ID"
+// @Success      200  {object}  responsedto.CommonResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contact-attributes/{id} [delete]
+func (h *OrganizationContactAttributeHandler) DeleteAttribute(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid attribute id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid attribute ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	if err := h.service.DeleteAttribute(user, uint(id)); err != nil {
+		code := contactAttributeErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to delete contact attribute",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to delete contact attribute", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	result := responsedto.CommonResponse{
+		Message: "Contact attribute deleted successfully",
+		Code:    http.StatusOK,
+	}
+	logger.InfoLog("Contact attribute deleted successfully", map[string]any{
+		"attribute_id": id,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+func contactAttributeErrorCode(err error) int {
+	switch {
+	case errors.Is(err, impl.ErrContactAttributeNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, impl.ErrContactAttributeKeyTaken),
+		errors.Is(err, impl.ErrContactAttributeOptionInUse):
+		return http.StatusConflict
+	case errors.Is(err, impl.ErrContactAttributeKeyInvalid),
+		errors.Is(err, impl.ErrContactAttributeNoOptions):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
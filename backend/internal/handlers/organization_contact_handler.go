@@ -0,0 +1,1130 @@
+package handlers
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/internal/services/impl"
+	_ "DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type OrganizationContactHandler struct {
+	jwtService jwtLib.JwtService
+	service    services.ContactService
+}
+
+func NewOrganizationContactHandler(
+	jwtService jwtLib.JwtService,
+	service services.ContactService,
+) *OrganizationContactHandler {
+	return &OrganizationContactHandler{
+		jwtService: jwtService,
+		service:    service,
+	}
+}
+
+// GetContacts godoc
+// @Summary      List contacts
+// @Description  List the contacts of the organization, search matches the name, emails, phones and channel ids
+// @Tags         organization-contacts
+// @Accept       json
+// @Produce      json
+// @Param        request  query  filtersdto.FiltersDto  false  "Pagination query"
+// @Param        search   query  string  false  "Search"
+// @Success      200  {object}  responsedto.ContactPaginateResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contacts [get]
+func (h *OrganizationContactHandler) GetContacts(w http.ResponseWriter, r *http.Request) {
+	filter := utils.ParsePagination(r)
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.GetContacts(user, filter, r.URL.Query().Get("search"))
+	if err != nil {
+		code := contactErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch contacts",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch contacts", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Contacts fetched successfully", map[string]any{
+		"count": len(result.Data),
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// CreateContact godoc
+// @Summary      Create a contact
+// @Description  Create a contact with its emails, phones and channel identities
+// @Tags         organization-contacts
+// @Accept       json
+// @Produce      json
+// @Param        request body requestdto.CreateContactRequest true "Create Contact Request"
+// @Success      201  {object}  responsedto.ContactResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      409  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contacts [post]
+func (h *OrganizationContactHandler) CreateContact(w http.ResponseWriter, r *http.Request) {
+	var req requestdto.CreateContactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.CreateContact(user, req)
+	if err != nil {
+		code := contactErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to create contact",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to create contact", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Contact created successfully", map[string]any{
+		"contact_id": result.ID,
+	})
+	utils.WriteJSONResponse(w, http.StatusCreated, result)
+}
+
+// GetContactByID godoc
+// @Summary      Get a contact
+// @Description  Get a contact with its identities and the linked guest user
+// @Tags         organization-contacts
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Contact ID"
+// @Success      200  {object}  responsedto.ContactResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contacts/{id} [get]
+func (h *OrganizationContactHandler) GetContactByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid contact id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid contact ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.GetContactByID(user, uint(id))
+	if err != nil {
+		code := contactErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch contact",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch contact", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// UpdateContact godoc
+// @Summary      Update a contact
+// @Description  Change the name or the avatar of a contact
+// @Tags         organization-contacts
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Contact ID"
+// @Param        request body requestdto.UpdateContactRequest true "Update Contact Request"
+// @Success      200  {object}  responsedto.ContactResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contacts/{id} [put]
+func (h *OrganizationContactHandler) UpdateContact(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid contact id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid contact ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.UpdateContactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.UpdateContact(user, uint(id), req)
+	if err != nil {
+		code := contactErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to update contact",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to update contact", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Contact updated successfully", map[string]any{
+		"contact_id": result.ID,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// AddIdentity godoc
+// @Summary      Add a contact identity
+// @Description  Add an email, a phone or a channel id to a contact
+// @Tags         organization-contacts
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Contact ID"
+// @Param        request body requestdto.ContactIdentityRequest true "Contact Identity Request"
+// @Success      200  {object}  responsedto.ContactResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      409  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contacts/{id}/identities [post]
+func (h *OrganizationContactHandler) AddIdentity(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid contact id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid contact ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.ContactIdentityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.AddIdentity(user, uint(id), req)
+	if err != nil {
+		code := contactErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to add contact identity",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to add contact identity", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Contact identity added successfully", map[string]any{
+		"contact_id": result.ID,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// RemoveIdentity godoc
+// @Summary      Remove a contact identity
+// @Description  Remove an email, a phone or a channel id from a contact
+// @Tags         organization-contacts
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Contact ID"
+// @Param        identityId path int true "Identity ID"
+// @Success      200  {object}  responsedto.CommonResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contacts/{id}/identities/{identityId} [delete]
+func (h *OrganizationContactHandler) RemoveIdentity(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid contact id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid contact ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	identityID, err := strconv.ParseUint(chi.URLParam(r, "identityId"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid identity id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid identity ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	if err := h.service.RemoveIdentity(user, uint(id), uint(identityID)); err != nil {
+		code := contactErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to remove contact identity",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to remove contact identity", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	result := responsedto.CommonResponse{
+		Message: "Contact identity removed successfully",
+		Code:    http.StatusOK,
+	}
+	logger.InfoLog("Contact identity removed successfully", map[string]any{
+		"contact_id":  id,
+		"identity_id": identityID,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// LinkUser godoc
+// @Summary      Link a contact to a guest user
+// @Description  Link the contact to the registered guest user with this email, the guest then sees the contact's conversations
+// @Tags         organization-contacts
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Contact ID"
+// @Param        request body requestdto.LinkContactUserRequest true "Link Contact User Request"
+// @Success      200  {object}  responsedto.ContactResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contacts/{id}/user [put]
+func (h *OrganizationContactHandler) LinkUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid contact id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid contact ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.LinkContactUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.LinkUser(user, uint(id), req)
+	if err != nil {
+		code := contactErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to link contact",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to link contact", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Contact linked successfully", map[string]any{
+		"contact_id": result.ID,
+		"user_id":    result.UserID,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// UnlinkUser godoc
+// @Summary      Unlink a contact from its guest user
+// @Description  Remove the link between the contact and the registered guest user
+// @Tags         organization-contacts
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Contact ID"
+// @Success      200  {object}  responsedto.CommonResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contacts/{id}/user [delete]
+func (h *OrganizationContactHandler) UnlinkUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid contact id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid contact ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	if err := h.service.UnlinkUser(user, uint(id)); err != nil {
+		code := contactErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to unlink contact",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to unlink contact", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	result := responsedto.CommonResponse{
+		Message: "Contact unlinked successfully",
+		Code:    http.StatusOK,
+	}
+	logger.InfoLog("Contact unlinked successfully", map[string]any{
+		"contact_id": id,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// SetAttributes godoc
+// @Summary      Set contact attributes
+// @Description  Set custom attribute values by key, values are validated against the attribute type and a null value removes the attribute
+// @Tags         organization-contacts
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Contact ID"
+// @Param        request body requestdto.SetContactAttributesRequest true "Set Contact Attributes Request"
+// @Success      200  {object}  responsedto.ContactResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contacts/{id}/attributes [put]
+func (h *OrganizationContactHandler) SetAttributes(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid contact id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid contact ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.SetContactAttributesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.SetAttributes(user, uint(id), req)
+	if err != nil {
+		code := contactErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to set contact attributes",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to set contact attributes", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Contact attributes set successfully", map[string]any{
+		"contact_id": result.ID,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// GetNotes godoc
+// @Summary      List contact notes
+// @Description  List the notes staff kept on the contact, newest first
+// @Tags         organization-contacts
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Contact ID"
+// @Param        request  query  filtersdto.FiltersDto  false  "Pagination query"
+// @Success      200  {object}  responsedto.ContactNotePaginateResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contacts/{id}/notes [get]
+func (h *OrganizationContactHandler) GetNotes(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid contact id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid contact ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	filter := utils.ParsePagination(r)
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.GetNotes(user, uint(id), filter)
+	if err != nil {
+		code := contactErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch contact notes",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch contact notes", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Contact notes fetched successfully", map[string]any{
+		"contact_id": id,
+		"count":      len(result.Data),
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// GetTimeline godoc
+// @Summary      Get the contact timeline
+// @Description  Conversations, messages, tickets and status changes of the contact in one feed, newest first. Sales only see the conversations and tickets assigned to them. Pass next_cursor back as cursor for the next page.
+// @Tags         organization-contacts
+// @Accept       json
+// @Produce      json
+// @Param        id      path   int     true   "Contact ID"
+// @Param        cursor  query  string  false  "Cursor of the next page"
+// @Param        limit   query  int     false  "Entries per page, at most 100"
+// @Success      200  {object}  responsedto.ContactTimelineResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contacts/{id}/timeline [get]
+func (h *OrganizationContactHandler) GetTimeline(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid contact id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid contact ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	limit := 20
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil {
+			errorData := responsedto.ErrorResponse{
+				Message: "invalid limit",
+				Error:   err.Error(),
+				Code:    http.StatusBadRequest,
+			}
+			logger.ErrorLog("Invalid timeline limit", errorData)
+			utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+			return
+		}
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.GetTimeline(user, uint(id), r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		code := contactErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch contact timeline",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch contact timeline", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Contact timeline fetched successfully", map[string]any{
+		"contact_id": id,
+		"count":      len(result.Data),
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// CreateNote godoc
+// @Summary      Add a contact note
+// @Description  Add a free-form note to the contact, the author is the current user
+// @Tags         organization-contacts
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Contact ID"
+// @Param        request body requestdto.ContactNoteRequest true "Contact Note Request"
+// @Success      201  {object}  responsedto.ContactNoteResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contacts/{id}/notes [post]
+func (h *OrganizationContactHandler) CreateNote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid contact id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid contact ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.ContactNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.CreateNote(user, uint(id), req)
+	if err != nil {
+		code := contactErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to create contact note",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to create contact note", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Contact note created successfully", map[string]any{
+		"contact_id": id,
+		"note_id":    result.ID,
+	})
+	utils.WriteJSONResponse(w, http.StatusCreated, result)
+}
+
+// UpdateNote godoc
+// @Summary      Update a contact note
+// @Description  Replace the body of a contact note
+// @Tags         organization-contacts
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Contact ID"
+// @Param        noteId path int true "Note ID"
+// @Param        request body requestdto.ContactNoteRequest true "Contact Note Request"
+// @Success      200  {object}  responsedto.ContactNoteResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contacts/{id}/notes/{noteId} [put]
+func (h *OrganizationContactHandler) UpdateNote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid contact id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid contact ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	noteID, err := strconv.ParseUint(chi.URLParam(r, "noteId"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid note id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid note ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.ContactNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.UpdateNote(user, uint(id), uint(noteID), req)
+	if err != nil {
+		code := contactErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to update contact note",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to update contact note", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Contact note updated successfully", map[string]any{
+		"contact_id": id,
+		"note_id":    result.ID,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// DeleteNote godoc
+// @Summary      Delete a contact note
+// @Description  Delete a note from the contact
+// @Tags         organization-contacts
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Contact ID"
+// @Param        noteId path int true "Note ID"
+// @Success      200  {object}  responsedto.CommonResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contacts/{id}/notes/{noteId} [delete]
+func (h *OrganizationContactHandler) DeleteNote(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid contact id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid contact ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	noteID, err := strconv.ParseUint(chi.URLParam(r, "noteId"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid note id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid note ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	if err := h.service.DeleteNote(user, uint(id), uint(noteID)); err != nil {
+		code := contactErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to delete contact note",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to delete contact note", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	result := responsedto.CommonResponse{
+		Message: "Contact note deleted successfully",
+		Code:    http.StatusOK,
+	}
+	logger.InfoLog("Contact note deleted successfully", map[string]any{
+		"contact_id": id,
+		"note_id":    noteID,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// GetMergeSuggestions godoc
+// @Summary      List merge suggestions
+// @Description  List groups of contacts sharing an email or a phone number, phone numbers and numeric channel ids match on their last digits
+// @Tags         organization-contacts
+// @Accept       json
+// @Produce      json
+// @Param        request  query  filtersdto.FiltersDto  false  "Pagination query"
+// @Success      200  {object}  responsedto.ContactMergeSuggestionPaginateResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contacts/merge-suggestions [get]
+func (h *OrganizationContactHandler) GetMergeSuggestions(w http.ResponseWriter, r *http.Request) {
+	filter := utils.ParsePagination(r)
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.GetMergeSuggestions(user, filter)
+	if err != nil {
+		code := contactErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch merge suggestions",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch merge suggestions", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Merge suggestions fetched successfully", map[string]any{
+		"count": len(result.Data),
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// MergeContact godoc
+// @Summary      Merge a contact into this one
+// @Description  Move the identities, conversations and messages of the source contact to this contact and remove the source, the merge is logged and can be undone
+// @Tags         organization-contacts
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Contact ID"
+// @Param        request body requestdto.MergeContactRequest true "Merge Contact Request"
+// @Success      201  {object}  responsedto.ContactMergeResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contacts/{id}/merge [post]
+func (h *OrganizationContactHandler) MergeContact(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid contact id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid contact ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.MergeContactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.MergeContact(user, uint(id), req)
+	if err != nil {
+		code := contactErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to merge contact",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to merge contact", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Contact merged successfully", map[string]any{
+		"merge_id":          result.ID,
+		"target_contact_id": result.TargetContactID,
+		"source_contact_id": result.SourceContactID,
+	})
+	utils.WriteJSONResponse(w, http.StatusCreated, result)
+}
+
+// GetContactMerges godoc
+// @Summary      List the merges of a contact
+// @Description  List the merges the contact took part in, as target or as source, newest first
+// @Tags         organization-contacts
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Contact ID"
+// @Success      200  {array}   responsedto.ContactMergeResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contacts/{id}/merges [get]
+func (h *OrganizationContactHandler) GetContactMerges(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid contact id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid contact ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.GetContactMerges(user, uint(id))
+	if err != nil {
+		code := contactErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch contact merges",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch contact merges", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Contact merges fetched successfully", map[string]any{
+		"contact_id": id,
+		"count":      len(result),
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// UndoMerge godoc
+// @Summary      Undo a contact merge
+// @Description  Restore the source contact and move its identities, conversations and messages back
+// @Tags         organization-contacts
+// @Accept       json
+// @Produce      json
+// @Param        mergeId path int true "Contact Merge ID"
+// @Success      200  {object}  responsedto.ContactMergeResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      409  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/contacts/merges/{mergeId}/undo [post]
+func (h *OrganizationContactHandler) UndoMerge(w http.ResponseWriter, r *http.Request) {
+	mergeID, err := strconv.ParseUint(chi.URLParam(r, "mergeId"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid merge id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid merge ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.UndoMerge(user, uint(mergeID))
+	if err != nil {
+		code := contactErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to undo contact merge",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to undo contact merge", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Contact merge undone successfully", map[string]any{
+		"merge_id":          result.ID,
+		"source_contact_id": result.SourceContactID,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+func contactErrorCode(err error) int {
+	switch {
+	case errors.Is(err, impl.ErrContactNotFound),
+		errors.Is(err, impl.ErrContactIdentityNotFound),
+		errors.Is(err, impl.ErrGuestUserNotFound),
+		errors.Is(err, impl.ErrContactMergeNotFound),
+		errors.Is(err, impl.ErrContactNoteNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, impl.ErrContactIdentityTaken),
+		errors.Is(err, impl.ErrContactMergeNotUndoable):
+		return http.StatusConflict
+	case errors.Is(err, impl.ErrContactIdentityInvalid),
+		errors.Is(err, impl.ErrContactMergeSelf),
+		errors.Is(err, impl.ErrContactAttributeUnknown),
+		errors.Is(err, impl.ErrContactAttributeInvalid),
+		errors.Is(err, impl.ErrTimelineCursorInvalid):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
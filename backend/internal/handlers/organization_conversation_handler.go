@@ -2,42 +2,52 @@ package handlers
 
 import (
 	"DewaSRY/sociomile-app/internal/services"
-	_ "DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
 	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
 	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
 	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
 	"DewaSRY/sociomile-app/pkg/lib/logger"
 	"DewaSRY/sociomile-app/pkg/utils"
 	"encoding/json"
+	"errors"
+	"mime"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 )
 
 type OrganizationConversationHandler struct {
-	jwtService jwtLib.JwtService
-	service    services.OrganizationConversationService
+	jwtService      jwtLib.JwtService
+	service         services.OrganizationConversationService
+	deliveryService services.OutboundDeliveryService
 }
 
 func NewOrganizationConversationHandler(
 	jwtService jwtLib.JwtService,
 	service services.OrganizationConversationService,
+	deliveryService services.OutboundDeliveryService,
 ) *OrganizationConversationHandler {
 	return &OrganizationConversationHandler{
-		jwtService: jwtService,
-		service:    service,
+		jwtService:      jwtService,
+		service:         service,
+		deliveryService: deliveryService,
 	}
 }
 
 // GetConversationsList godoc
 // @Summary      Get conversations list with pagination
-// @Description  Retrieve list of conversations for organization with pagination support
+// @Description  Retrieve list of conversations for organization with pagination support. Owners see every conversation and may pick the mine, unassigned or all view, sales only see the conversations assigned to them. Filter on contact attributes with attr.<key>=<value> query params, e.g. attr.tier=gold
 // @Tags         organization-conversations
 // @Accept       json
 // @Produce      json
 // @Param        request  query  filtersdto.FiltersDto  false  "Pagination query"
+// @Param        view     query  string  false  "Inbox view, sales may only use mine"  Enums(mine, unassigned, all)
 // @Success      200      {object}  responsedto.ConversationListResponse
+// @Failure      400      {object}  responsedto.ErrorResponse
+// @Failure      403      {object}  responsedto.ErrorResponse
 // @Failure      500      {object}  responsedto.ErrorResponse
 // @Security     BearerAuth
 // @Router       /organizations/conversations [get]
@@ -45,15 +55,39 @@ func (h *OrganizationConversationHandler) GetConversationsList(w http.ResponseWr
 	filter := utils.ParsePagination(r)
 	user, _ := h.jwtService.GetUserFromContext(r.Context())
 
-	result, err := h.service.GetConversationsList(user, filter)
+	conversationFilter := filtersdto.ConversationFiltersDto{
+		View:       r.URL.Query().Get("view"),
+		Attributes: map[string]string{},
+	}
+	for key, values := range r.URL.Query() {
+		if name, ok := strings.CutPrefix(key, "attr."); ok && name != "" && len(values) > 0 {
+			conversationFilter.Attributes[name] = values[0]
+		}
+	}
+	if err := utils.ValidateStruct(conversationFilter); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	result, err := h.service.GetConversationsList(user, filter, conversationFilter)
 	if err != nil {
+		code := conversationErrorCode(err)
+		if errors.Is(err, impl.ErrContactAttributeUnknown) || errors.Is(err, impl.ErrContactAttributeInvalid) {
+			code = http.StatusBadRequest
+		}
 		errorData := responsedto.ErrorResponse{
 			Message: "failed to fetch conversations",
 			Error:   err.Error(),
-			Code:    http.StatusInternalServerError,
+			Code:    code,
 		}
 		logger.ErrorLog("Failed to fetch conversations", errorData)
-		utils.WriteJSONResponse(w, http.StatusInternalServerError, errorData)
+		utils.WriteJSONResponse(w, code, errorData)
 		return
 	}
 
@@ -73,6 +107,7 @@ func (h *OrganizationConversationHandler) GetConversationsList(w http.ResponseWr
 // @Success      200  {object}  responsedto.ConversationResponse
 // @Failure      400  {object}  responsedto.ErrorResponse
 // @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
 // @Security     BearerAuth
 // @Router       /organizations/conversations/{id} [get]
 func (h *OrganizationConversationHandler) GetConversationByID(w http.ResponseWriter, r *http.Request) {
@@ -89,15 +124,18 @@ func (h *OrganizationConversationHandler) GetConversationByID(w http.ResponseWri
 		return
 	}
 
-	result, err := h.service.GetConversationByID(uint(id))
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.GetConversationByID(user, uint(id))
 	if err != nil {
+		code := conversationErrorCode(err)
 		errorData := responsedto.ErrorResponse{
-			Message: "conversation not found",
+			Message: "failed to fetch conversation",
 			Error:   err.Error(),
-			Code:    http.StatusNotFound,
+			Code:    code,
 		}
-		logger.ErrorLog("Conversation not found", errorData)
-		utils.WriteJSONResponse(w, http.StatusNotFound, errorData)
+		logger.ErrorLog("Failed to fetch conversation", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
 		return
 	}
 
@@ -158,15 +196,18 @@ func (h *OrganizationConversationHandler) AssignConversation(w http.ResponseWrit
 		return
 	}
 
-	result, err := h.service.AssignConversation(uint(id), req)
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.AssignConversation(user, uint(id), req)
 	if err != nil {
+		code := conversationErrorCode(err)
 		errorData := responsedto.ErrorResponse{
 			Message: "failed to assign conversation",
 			Error:   err.Error(),
-			Code:    http.StatusInternalServerError,
+			Code:    code,
 		}
 		logger.ErrorLog("Failed to assign conversation", errorData)
-		utils.WriteJSONResponse(w, http.StatusInternalServerError, errorData)
+		utils.WriteJSONResponse(w, code, errorData)
 		return
 	}
 
@@ -228,15 +269,18 @@ func (h *OrganizationConversationHandler) UpdateConversationStatus(w http.Respon
 		return
 	}
 
-	err = h.service.UpdateConversationStatus(uint(id), req)
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	err = h.service.UpdateConversationStatus(user, uint(id), req)
 	if err != nil {
+		code := conversationErrorCode(err)
 		errorData := responsedto.ErrorResponse{
 			Message: "failed to update conversation status",
 			Error:   err.Error(),
-			Code:    http.StatusInternalServerError,
+			Code:    code,
 		}
 		logger.ErrorLog("Failed to update conversation status", errorData)
-		utils.WriteJSONResponse(w, http.StatusInternalServerError, errorData)
+		utils.WriteJSONResponse(w, code, errorData)
 		return
 	}
 
@@ -249,3 +293,229 @@ func (h *OrganizationConversationHandler) UpdateConversationStatus(w http.Respon
 	})
 	utils.WriteJSONResponse(w, http.StatusOK, result)
 }
+
+// SendMessage godoc
+// @Summary      Send a staff message in a conversation
+// @Description  Organization staff replies to the guest, replies to email and webhook conversations are queued for delivery to the origin channel
+// @Tags         organization-conversations
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Conversation ID"
+// @Param        request body requestdto.CreateStaffMessageRequest true "Send Message Request"
+// @Success      201  {object}  responsedto.CommonResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/conversations/{id}/messages [post]
+func (h *OrganizationConversationHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid conversation id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid conversation ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.CreateStaffMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	if err := h.service.SendMessage(user, uint(id), req); err != nil {
+		code := conversationErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to send message",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to send message", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	result := responsedto.CommonResponse{
+		Message: "Message sent successfully",
+		Code:    http.StatusCreated,
+	}
+	logger.InfoLog("Message sent successfully", map[string]any{
+		"conversation_id": id,
+	})
+	utils.WriteJSONResponse(w, http.StatusCreated, result)
+}
+
+// DownloadAttachment godoc
+// @Summary      Download a message attachment
+// @Description  Download an attachment received through the email channel
+// @Tags         organization-conversations
+// @Produce      octet-stream
+// @Param        id path int true "Conversation ID"
+// @Param        attachmentId path int true "Attachment ID"
+// @Success      200  {file}    file
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/conversations/{id}/attachments/{attachmentId} [get]
+func (h *OrganizationConversationHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid conversation id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid conversation ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	attachmentID, err := strconv.ParseUint(chi.URLParam(r, "attachmentId"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid attachment id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid attachment ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.GetAttachment(user, uint(id), uint(attachmentID))
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "attachment not found",
+			Error:   err.Error(),
+			Code:    http.StatusNotFound,
+		}
+		logger.ErrorLog("Attachment not found", errorData)
+		utils.WriteJSONResponse(w, http.StatusNotFound, errorData)
+		return
+	}
+
+	// the content comes from outside senders, it is always downloaded and
+	// the browser may not guess another type for it
+	disposition := mime.FormatMediaType("attachment", map[string]string{
+		"filename": result.FileName,
+	})
+	if disposition == "" {
+		disposition = "attachment"
+	}
+	contentType := result.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", disposition)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	w.WriteHeader(http.StatusOK)
+	w.Write(result.Content)
+}
+
+// RetryMessageDelivery godoc
+// @Summary      Retry a failed reply delivery
+// @Description  Requeue the delivery of a staff reply that failed or was moved to the dead letter state
+// @Tags         organization-conversations
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Conversation ID"
+// @Param        messageId path int true "Message ID"
+// @Success      200  {object}  responsedto.CommonResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      409  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/conversations/{id}/messages/{messageId}/retry [post]
+func (h *OrganizationConversationHandler) RetryMessageDelivery(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid conversation id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid conversation ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	messageID, err := strconv.ParseUint(chi.URLParam(r, "messageId"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid message id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid message ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	if err := h.deliveryService.RetryDelivery(user, uint(id), uint(messageID)); err != nil {
+		code := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, impl.ErrDeliveryNotFound):
+			code = http.StatusNotFound
+		case errors.Is(err, impl.ErrDeliveryNotRetryable):
+			code = http.StatusConflict
+		}
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to retry delivery",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to retry delivery", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	result := responsedto.CommonResponse{
+		Message: "Delivery queued for retry",
+		Code:    http.StatusOK,
+	}
+	logger.InfoLog("Delivery queued for retry", map[string]any{
+		"conversation_id": id,
+		"message_id":      messageID,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+func conversationErrorCode(err error) int {
+	switch {
+	case errors.Is(err, impl.ErrConversationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, impl.ErrConversationViewNotPermitted):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
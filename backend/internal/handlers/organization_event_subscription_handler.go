@@ -0,0 +1,363 @@
+package handlers
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/internal/services/impl"
+	_ "DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type OrganizationEventSubscriptionHandler struct {
+	jwtService jwtLib.JwtService
+	service    services.EventSubscriptionService
+}
+
+func NewOrganizationEventSubscriptionHandler(
+	jwtService jwtLib.JwtService,
+	service services.EventSubscriptionService,
+) *OrganizationEventSubscriptionHandler {
+	return &OrganizationEventSubscriptionHandler{
+		jwtService: jwtService,
+		service:    service,
+	}
+}
+
+// CreateSubscription godoc
+// @Summary      Register an event subscription
+// @Description  Register an http endpoint that receives signed event payloads, the signing secret is only returned in this response
+// @Tags         organization-event-subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        request body requestdto.CreateEventSubscriptionRequest true "Create Event Subscription Request"
+// @Success      201  {object}  responsedto.EventSubscriptionResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/event-subscriptions [post]
+func (h *OrganizationEventSubscriptionHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	var req requestdto.CreateEventSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.CreateSubscription(user, req)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to create event subscription",
+			Error:   err.Error(),
+			Code:    http.StatusInternalServerError,
+		}
+		logger.ErrorLog("Failed to create event subscription", errorData)
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, errorData)
+		return
+	}
+
+	logger.InfoLog("Event subscription created successfully", map[string]any{
+		"subscription_id": result.ID,
+	})
+	utils.WriteJSONResponse(w, http.StatusCreated, result)
+}
+
+// GetSubscriptions godoc
+// @Summary      List event subscriptions
+// @Description  List the event subscriptions of the organization
+// @Tags         organization-event-subscriptions
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  responsedto.EventSubscriptionListResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/event-subscriptions [get]
+func (h *OrganizationEventSubscriptionHandler) GetSubscriptions(w http.ResponseWriter, r *http.Request) {
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.GetSubscriptions(user)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch event subscriptions",
+			Error:   err.Error(),
+			Code:    http.StatusInternalServerError,
+		}
+		logger.ErrorLog("Failed to fetch event subscriptions", errorData)
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, errorData)
+		return
+	}
+
+	logger.InfoLog("Event subscriptions fetched successfully", map[string]any{
+		"count": len(result.Data),
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// UpdateSubscription godoc
+// @Summary      Update an event subscription
+// @Description  Change the url, the subscribed event types or disable the subscription
+// @Tags         organization-event-subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Subscription ID"
+// @Param        request body requestdto.UpdateEventSubscriptionRequest true "Update Event Subscription Request"
+// @Success      200  {object}  responsedto.EventSubscriptionResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/event-subscriptions/{id} [put]
+func (h *OrganizationEventSubscriptionHandler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid subscription id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid subscription ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.UpdateEventSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.UpdateSubscription(user, uint(id), req)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, impl.ErrSubscriptionNotFound) {
+			code = http.StatusNotFound
+		}
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to update event subscription",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to update event subscription", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Event subscription updated successfully", map[string]any{
+		"subscription_id": result.ID,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// DeleteSubscription godoc
+// @Summary      Delete an event subscription
+// @Description  Delete the subscription, its pending deliveries are cancelled
+// @Tags         organization-event-subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Subscription ID"
+// @Success      200  {object}  responsedto.CommonResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/event-subscriptions/{id} [delete]
+func (h *OrganizationEventSubscriptionHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid subscription id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid subscription ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	if err := h.service.DeleteSubscription(user, uint(id)); err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, impl.ErrSubscriptionNotFound) {
+			code = http.StatusNotFound
+		}
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to delete event subscription",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to delete event subscription", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	result := responsedto.CommonResponse{
+		Message: "Event subscription deleted successfully",
+		Code:    http.StatusOK,
+	}
+	logger.InfoLog("Event subscription deleted successfully", map[string]any{
+		"subscription_id": id,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// GetDeliveries godoc
+// @Summary      Event delivery log
+// @Description  List the deliveries of a subscription with their attempts and the last endpoint answer
+// @Tags         organization-event-subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Subscription ID"
+// @Param        request  query  filtersdto.FiltersDto  false  "Pagination query"
+// @Success      200  {object}  responsedto.EventDeliveryPaginateResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/event-subscriptions/{id}/deliveries [get]
+func (h *OrganizationEventSubscriptionHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid subscription id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid subscription ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	filter := utils.ParsePagination(r)
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.GetDeliveries(user, uint(id), filter)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, impl.ErrSubscriptionNotFound) {
+			code = http.StatusNotFound
+		}
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch event deliveries",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch event deliveries", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Event deliveries fetched successfully", map[string]any{
+		"count": len(result.Data),
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// ReplayDelivery godoc
+// @Summary      Replay an event delivery
+// @Description  Queue a new delivery of the same event, the event id is kept so the receiver can deduplicate it
+// @Tags         organization-event-subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Subscription ID"
+// @Param        deliveryId path int true "Delivery ID"
+// @Success      201  {object}  responsedto.EventDeliveryResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/event-subscriptions/{id}/deliveries/{deliveryId}/replay [post]
+func (h *OrganizationEventSubscriptionHandler) ReplayDelivery(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid subscription id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid subscription ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	deliveryID, err := strconv.ParseUint(chi.URLParam(r, "deliveryId"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid delivery id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid delivery ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.ReplayDelivery(user, uint(id), uint(deliveryID))
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, impl.ErrSubscriptionNotFound) || errors.Is(err, impl.ErrEventDeliveryNotFound) {
+			code = http.StatusNotFound
+		}
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to replay event delivery",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to replay event delivery", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Event delivery replayed successfully", map[string]any{
+		"delivery_id": result.ID,
+		"replay_of":   deliveryID,
+	})
+	utils.WriteJSONResponse(w, http.StatusCreated, result)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+type OrganizationSettingsHandler struct {
+	jwtService jwtLib.JwtService
+	service    services.OrganizationService
+}
+
+func NewOrganizationSettingsHandler(
+	jwtService jwtLib.JwtService,
+	service services.OrganizationService,
+) *OrganizationSettingsHandler {
+	return &OrganizationSettingsHandler{
+		jwtService: jwtService,
+		service:    service,
+	}
+}
+
+// GetSettings godoc
+// @Summary      Get organization settings
+// @Description  Get the settings of the organization, such as the timezone exports use
+// @Tags         organization-settings
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  responsedto.OrganizationSettingsResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/settings [get]
+func (h *OrganizationSettingsHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.GetSettings(user)
+	if err != nil {
+		code := organizationSettingsErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch organization settings",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch organization settings", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Organization settings fetched successfully", result)
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// UpdateSettings godoc
+// @Summary      Update organization settings
+// @Description  Change the settings of the organization, the timezone is an IANA zone name such as Asia/Jakarta
+// @Tags         organization-settings
+// @Accept       json
+// @Produce      json
+// @Param        request body requestdto.UpdateOrganizationSettingsRequest true "Update Organization Settings Request"
+// @Success      200  {object}  responsedto.OrganizationSettingsResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/settings [put]
+func (h *OrganizationSettingsHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var req requestdto.UpdateOrganizationSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.UpdateSettings(user, req)
+	if err != nil {
+		code := organizationSettingsErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to update organization settings",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to update organization settings", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Organization settings updated successfully", result)
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+func organizationSettingsErrorCode(err error) int {
+	switch {
+	case errors.Is(err, impl.ErrOrganizationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, impl.ErrOrganizationTimezoneInvalid):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
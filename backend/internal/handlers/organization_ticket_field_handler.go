@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type OrganizationTicketFieldHandler struct {
+	jwtService jwtLib.JwtService
+	service    services.TicketFieldService
+}
+
+func NewOrganizationTicketFieldHandler(
+	jwtService jwtLib.JwtService,
+	service services.TicketFieldService,
+) *OrganizationTicketFieldHandler {
+	return &OrganizationTicketFieldHandler{
+		jwtService: jwtService,
+		service:    service,
+	}
+}
+
+// GetFields godoc
+// @Summary      List ticket fields
+// @Description  List the custom fields the organization tracks on its tickets
+// @Tags         organization-ticket-fields
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   responsedto.TicketFieldResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket-fields [get]
+func (h *OrganizationTicketFieldHandler) GetFields(w http.ResponseWriter, r *http.Request) {
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.GetFields(user)
+	if err != nil {
+		code := ticketFieldErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch ticket fields",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch ticket fields", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Ticket fields fetched successfully", map[string]any{
+		"count": len(result),
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// CreateField godoc
+// @Summary      Create a ticket field
+// @Description  Define a custom ticket field of type string, number, date, enum or boolean, enums need their options. Min and max bound a number or the length of a string, pattern is a regular expression a string must match, a required field must be given when a ticket is created
+// @Tags         organization-ticket-fields
+// @Accept       json
+// @Produce      json
+// @Param        request body requestdto.CreateTicketFieldRequest true "Create Ticket Field Request"
+// @Success      201  {object}  responsedto.TicketFieldResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      409  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket-fields [post]
+func (h *OrganizationTicketFieldHandler) CreateField(w http.ResponseWriter, r *http.Request) {
+	var req requestdto.CreateTicketFieldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.CreateField(user, req)
+	if err != nil {
+		code := ticketFieldErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to create ticket field",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to create ticket field", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Ticket field created successfully", map[string]any{
+		"field_id": result.ID,
+		"key":      result.Key,
+	})
+	utils.WriteJSONResponse(w, http.StatusCreated, result)
+}
+
+// UpdateField godoc
+// @Summary      Update a ticket field
+// @Description  Update the label, the required flag, the enum options and the rules of a ticket field, options still set on tickets cannot be removed. Values already on tickets are checked against new rules when they change
+// @Tags         organization-ticket-fields
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Field ID"
+// @Param        request body requestdto.UpdateTicketFieldRequest true "Update Ticket Field Request"
+// @Success      200  {object}  responsedto.TicketFieldResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      409  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket-fields/{id} [put]
+func (h *OrganizationTicketFieldHandler) UpdateField(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid field id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid field ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.UpdateTicketFieldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.UpdateField(user, uint(id), req)
+	if err != nil {
+		code := ticketFieldErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to update ticket field",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to update ticket field", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Ticket field updated successfully", map[string]any{
+		"field_id": result.ID,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// DeleteField godoc
+// @Summary      Delete a ticket field
+// @Description  Delete a ticket field together with its values on every ticket
+// @Tags         organization-ticket-fields
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Field ID"
+// @Success      200  {object}  responsedto.CommonResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket-fields/{id} [delete]
+func (h *OrganizationTicketFieldHandler) DeleteField(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid field id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid field ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	if err := h.service.DeleteField(user, uint(id)); err != nil {
+		code := ticketFieldErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to delete ticket field",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to delete ticket field", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	result := responsedto.CommonResponse{
+		Message: "Ticket field deleted successfully",
+		Code:    http.StatusOK,
+	}
+	logger.InfoLog("Ticket field deleted successfully", map[string]any{
+		"field_id": id,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+func ticketFieldErrorCode(err error) int {
+	switch {
+	case errors.Is(err, impl.ErrOrganizationNotFound),
+		errors.Is(err, impl.ErrTicketFieldNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, impl.ErrTicketFieldKeyTaken),
+		errors.Is(err, impl.ErrTicketFieldOptionInUse):
+		return http.StatusConflict
+	case errors.Is(err, impl.ErrTicketFieldKeyInvalid),
+		errors.Is(err, impl.ErrTicketFieldNoOptions),
+		errors.Is(err, impl.ErrTicketFieldRuleInvalid):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
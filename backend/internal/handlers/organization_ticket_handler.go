@@ -2,11 +2,17 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"DewaSRY/sociomile-app/internal/services"
-	_ "DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
 	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
 	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
 	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
@@ -16,16 +22,21 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
+// maxTicketImportBytes caps the CSV file of a ticket import.
+const maxTicketImportBytes = 5 << 20
+
 type OrganizationTicketHandler struct {
 	jwtService jwtLib.JwtService
 	service    services.OrganizationTicketService
 }
 
 func NewOrganizationTicketHandler(
+	jwtService jwtLib.JwtService,
 	service services.OrganizationTicketService,
 ) *OrganizationTicketHandler {
 	return &OrganizationTicketHandler{
-		service: service,
+		jwtService: jwtService,
+		service:    service,
 	}
 }
 
@@ -69,13 +80,14 @@ func (t *OrganizationTicketHandler) CreateTicket(w http.ResponseWriter, r *http.
 
 	err := t.service.CreateTicket(user, req)
 	if err != nil {
+		code := ticketErrorCode(err)
 		errorData := responsedto.ErrorResponse{
 			Message: "failed to create ticket",
 			Error:   err.Error(),
-			Code:    http.StatusInternalServerError,
+			Code:    code,
 		}
 		logger.ErrorLog("Failed to create ticket", errorData)
-		utils.WriteJSONResponse(w, http.StatusInternalServerError, errorData)
+		utils.WriteJSONResponse(w, code, errorData)
 		return
 	}
 
@@ -88,12 +100,29 @@ func (t *OrganizationTicketHandler) CreateTicket(w http.ResponseWriter, r *http.
 
 // GetTicketsList godoc
 // @Summary      Get tickets list with pagination
-// @Description  Retrieve list of tickets for organization with pagination support
+// @Description  Retrieve a page of the organization's tickets with filters and sorting, the total counts every ticket matching the filters. Sales only ever see the tickets assigned to them
 // @Tags         organization-tickets
 // @Accept       json
 // @Produce      json
-// @Param        request  query  filtersdto.FiltersDto  false  "Pagination query"
+// @Param        request  query  filtersdto.FiltersDto  false  "Pagination query, at most 100 tickets a page"
+// @Param        assigneeId      query  int     false  "Assignee user ID"
+// @Param        createdById     query  int     false  "Creator user ID"
+// @Param        conversationId  query  int     false  "Only tickets covering the conversation"
+// @Param        status          query  string  false  "Comma separated workflow statuses"
+// @Param        statusCategory  query  string  false  "Status category" Enums(open, closed)
+// @Param        priority        query  string  false  "Priority" Enums(low, normal, high, urgent)
+// @Param        category        query  string  false  "Category"
+// @Param        overdue         query  bool    false  "Only tickets past their due date that are not done"
+// @Param        numberPrefix    query  string  false  "Start of the ticket number"
+// @Param        tag             query  string  false  "Only tickets with the tag"
+// @Param        field.{key}     query  string  false  "Only tickets whose custom field has the value, e.g. field.order_number=A-1001"
+// @Param        createdFrom     query  string  false  "Created at or after, RFC 3339 or YYYY-MM-DD"
+// @Param        createdTo       query  string  false  "Created before, RFC 3339 or YYYY-MM-DD"
+// @Param        dueFrom         query  string  false  "Due at or after, RFC 3339 or YYYY-MM-DD"
+// @Param        dueTo           query  string  false  "Due before, RFC 3339 or YYYY-MM-DD"
+// @Param        sort            query  string  false  "Comma separated createdAt, updatedAt, ticketNumber, name, status, priority, dueAt or slaDueAt, a leading - sorts descending" default(-createdAt)
 // @Success      200      {object}  responsedto.TicketListResponse
+// @Failure      400      {object}  responsedto.ErrorResponse
 // @Failure      500      {object}  responsedto.ErrorResponse
 // @Security     BearerAuth
 // @Router       /organizations/ticket [get]
@@ -101,40 +130,318 @@ func (t *OrganizationTicketHandler) GetTicketsList(w http.ResponseWriter, r *htt
 	filter := utils.ParsePagination(r)
 	user, _ := t.jwtService.GetUserFromContext(r.Context())
 
-	result, err := t.service.GetTicketsList(user, filter)
+	ticketFilter, err := parseTicketFilters(r)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid ticket filter",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid ticket filter", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+	if err := utils.ValidateStruct(ticketFilter); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	result, err := t.service.GetTicketsList(user, filter, ticketFilter)
 	if err != nil {
+		code := ticketErrorCode(err)
 		errorData := responsedto.ErrorResponse{
 			Message: "failed to fetch tickets",
 			Error:   err.Error(),
-			Code:    http.StatusInternalServerError,
+			Code:    code,
 		}
 		logger.ErrorLog("Failed to fetch tickets", errorData)
-		utils.WriteJSONResponse(w, http.StatusInternalServerError, errorData)
+		utils.WriteJSONResponse(w, code, errorData)
 		return
 	}
 
 	logger.InfoLog("Tickets fetched successfully", map[string]any{
 		"count": len(result.Tickets),
+		"total": result.Metadata.Total,
 	})
 	utils.WriteJSONResponse(w, http.StatusOK, result)
 }
 
-// UpdateTicket godoc
-// @Summary      Update ticket
-// @Description  Update a ticket's details
+// parseTicketFilters reads the ticket list filters from the query string.
+func parseTicketFilters(r *http.Request) (filtersdto.TicketFiltersDto, error) {
+	query := r.URL.Query()
+	ticketFilter := filtersdto.TicketFiltersDto{
+		StatusCategory: query.Get("statusCategory"),
+		Priority:       query.Get("priority"),
+		Category:       query.Get("category"),
+		NumberPrefix:   query.Get("numberPrefix"),
+		Tag:            query.Get("tag"),
+		Sort:           query.Get("sort"),
+	}
+	for key, values := range query {
+		if name, ok := strings.CutPrefix(key, "field."); ok && name != "" && len(values) > 0 {
+			if ticketFilter.Fields == nil {
+				ticketFilter.Fields = map[string]string{}
+			}
+			ticketFilter.Fields[name] = values[0]
+		}
+	}
+	if value := query.Get("status"); value != "" {
+		for _, status := range strings.Split(value, ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				ticketFilter.Status = append(ticketFilter.Status, status)
+			}
+		}
+	}
+
+	ids := map[string]**uint{
+		"assigneeId":     &ticketFilter.AssigneeID,
+		"createdById":    &ticketFilter.CreatedByID,
+		"conversationId": &ticketFilter.ConversationID,
+	}
+	for name, target := range ids {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return ticketFilter, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		parsed := uint(id)
+		*target = &parsed
+	}
+
+	times := map[string]**time.Time{
+		"createdFrom": &ticketFilter.CreatedFrom,
+		"createdTo":   &ticketFilter.CreatedTo,
+		"dueFrom":     &ticketFilter.DueFrom,
+		"dueTo":       &ticketFilter.DueTo,
+	}
+	for name, target := range times {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			if parsed, err = time.Parse(time.DateOnly, value); err != nil {
+				return ticketFilter, fmt.Errorf("invalid %s: expected RFC 3339 or YYYY-MM-DD", name)
+			}
+		}
+		*target = &parsed
+	}
+
+	if value := query.Get("overdue"); value != "" {
+		overdue, err := strconv.ParseBool(value)
+		if err != nil {
+			return ticketFilter, fmt.Errorf("invalid overdue: %w", err)
+		}
+		ticketFilter.Overdue = overdue
+	}
+
+	return ticketFilter, nil
+}
+
+// ExportTickets godoc
+// @Summary      Export tickets as CSV
+// @Description  Stream every ticket matching the filters of the ticket list as CSV, with a field.{key} column for each custom field. Times are written as YYYY-MM-DD HH:MM:SS in the timezone of the organization. Sales only get the tickets assigned to them
+// @Tags         organization-tickets
+// @Produce      text/csv
+// @Param        assigneeId      query  int     false  "Assignee user ID"
+// @Param        createdById     query  int     false  "Creator user ID"
+// @Param        conversationId  query  int     false  "Only tickets covering the conversation"
+// @Param        status          query  string  false  "Comma separated workflow statuses"
+// @Param        statusCategory  query  string  false  "Status category" Enums(open, closed)
+// @Param        priority        query  string  false  "Priority" Enums(low, normal, high, urgent)
+// @Param        category        query  string  false  "Category"
+// @Param        overdue         query  bool    false  "Only tickets past their due date that are not done"
+// @Param        numberPrefix    query  string  false  "Start of the ticket number"
+// @Param        tag             query  string  false  "Only tickets with the tag"
+// @Param        field.{key}     query  string  false  "Only tickets whose custom field has the value, e.g. field.order_number=A-1001"
+// @Param        createdFrom     query  string  false  "Created at or after, RFC 3339 or YYYY-MM-DD"
+// @Param        createdTo       query  string  false  "Created before, RFC 3339 or YYYY-MM-DD"
+// @Param        dueFrom         query  string  false  "Due at or after, RFC 3339 or YYYY-MM-DD"
+// @Param        dueTo           query  string  false  "Due before, RFC 3339 or YYYY-MM-DD"
+// @Param        sort            query  string  false  "Comma separated createdAt, updatedAt, ticketNumber, name, status, priority, dueAt or slaDueAt, a leading - sorts descending" default(-createdAt)
+// @Success      200      {file}    file
+// @Failure      400      {object}  responsedto.ErrorResponse
+// @Failure      500      {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket/export [get]
+func (t *OrganizationTicketHandler) ExportTickets(w http.ResponseWriter, r *http.Request) {
+	user, _ := t.jwtService.GetUserFromContext(r.Context())
+
+	ticketFilter, err := parseTicketFilters(r)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid ticket filter",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid ticket filter", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+	if err := utils.ValidateStruct(ticketFilter); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	// a large export streams for longer than the write timeout of the
+	// server, writers that can not lift it keep the timeout
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	stream := &csvStreamWriter{ResponseWriter: w, filename: "tickets-" + time.Now().UTC().Format("20060102") + ".csv"}
+	if err := t.service.ExportTickets(user, ticketFilter, stream); err != nil {
+		code := ticketErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to export tickets",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to export tickets", errorData)
+		// the status is gone once rows were streamed, the file is cut short
+		if !stream.started {
+			utils.WriteJSONResponse(w, code, errorData)
+		}
+		return
+	}
+
+	logger.InfoLog("Tickets exported successfully", map[string]any{
+		"user_id": user.UserID,
+	})
+}
+
+// csvStreamWriter sends the CSV headers with the first bytes, so an error
+// before any row can still be answered with JSON.
+type csvStreamWriter struct {
+	http.ResponseWriter
+	filename string
+	started  bool
+}
+
+func (s *csvStreamWriter) Write(p []byte) (int, error) {
+	if !s.started {
+		s.started = true
+		s.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		s.Header().Set("Content-Disposition", `attachment; filename="`+s.filename+`"`)
+		s.WriteHeader(http.StatusOK)
+	}
+	return s.ResponseWriter.Write(p)
+}
+
+func (s *csvStreamWriter) Flush() {
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// ImportTickets godoc
+// @Summary      Import tickets from CSV
+// @Description  Create tickets from a CSV file sent as the body or as the file field of a form. The header names the columns conversation_id and name, and optionally priority, category, description, due_at, assignee_email and field.{key} for a custom field, other columns are ignored so an export can be edited and sent back. A row whose ticket_number is already a ticket of the organization is skipped and listed under skipped, the created tickets get new numbers. Every row is checked against the conversations and staff of the organization, due_at without an offset is in the timezone of the organization. A dry run, the default, only reports the row errors, otherwise the tickets are created in one go when no row has errors
+// @Tags         organization-tickets
+// @Accept       text/csv
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        dryRun  query     bool  false  "Only check the rows" default(true)
+// @Param        file    formData  file  false  "CSV file"
+// @Success      200  {object}  responsedto.TicketImportResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      409  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket/import [post]
+func (t *OrganizationTicketHandler) ImportTickets(w http.ResponseWriter, r *http.Request) {
+	dryRun := true
+	if value := r.URL.Query().Get("dryRun"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			errorData := responsedto.ErrorResponse{
+				Message: "invalid dryRun",
+				Error:   err.Error(),
+				Code:    http.StatusBadRequest,
+			}
+			logger.ErrorLog("Invalid dryRun", errorData)
+			utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+			return
+		}
+		dryRun = parsed
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxTicketImportBytes)
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			errorData := responsedto.ErrorResponse{
+				Message: "invalid request",
+				Error:   err.Error(),
+				Code:    http.StatusBadRequest,
+			}
+			logger.ErrorLog("Failed to read import file", errorData)
+			utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	user, _ := t.jwtService.GetUserFromContext(r.Context())
+
+	result, err := t.service.ImportTickets(user, body, dryRun)
+	if err != nil {
+		code := ticketErrorCode(err)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			code = http.StatusRequestEntityTooLarge
+		}
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to import tickets",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to import tickets", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Tickets imported successfully", map[string]any{
+		"dry_run": result.DryRun,
+		"total":   result.Total,
+		"errors":  len(result.Errors),
+		"created": result.Created,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// GetTicket godoc
+// @Summary      Get ticket
+// @Description  Get a ticket with its comments, the history of its changes and its SLA escalations, oldest first, its linked tickets and the conversations it covers
 // @Tags         organization-tickets
 // @Accept       json
 // @Produce      json
 // @Param        id path int true "Ticket ID"
-// @Param        request body requestdto.UpdateTicketRequest true "Update Ticket Request"
-// @Success      200  {object}  responsedto.TicketResponse
+// @Success      200  {object}  responsedto.TicketDetailResponse
 // @Failure      400  {object}  responsedto.ErrorResponse
 // @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
 // @Security     BearerAuth
-// @Router       /organizations/ticket/{id} [put]
-func (t *OrganizationTicketHandler) UpdateTicket(w http.ResponseWriter, r *http.Request) {
-	idStr := chi.URLParam(r, "id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
+// @Router       /organizations/ticket/{id} [get]
+func (t *OrganizationTicketHandler) GetTicket(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
 	if err != nil {
 		errorData := responsedto.ErrorResponse{
 			Message: "invalid ticket id",
@@ -146,7 +453,105 @@ func (t *OrganizationTicketHandler) UpdateTicket(w http.ResponseWriter, r *http.
 		return
 	}
 
-	var req requestdto.UpdateTicketRequest
+	user, _ := t.jwtService.GetUserFromContext(r.Context())
+
+	result, err := t.service.GetTicket(user, uint(id))
+	if err != nil {
+		code := ticketErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch ticket",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch ticket", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Ticket fetched successfully", map[string]any{
+		"ticketId": id,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// GetComments godoc
+// @Summary      List ticket comments
+// @Description  List the internal and public comments of a ticket, newest first
+// @Tags         organization-tickets
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Ticket ID"
+// @Param        request  query  filtersdto.FiltersDto  false  "Pagination query"
+// @Success      200  {object}  responsedto.TicketCommentPaginateResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket/{id}/comments [get]
+func (t *OrganizationTicketHandler) GetComments(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid ticket id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid ticket ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	filter := utils.ParsePagination(r)
+	user, _ := t.jwtService.GetUserFromContext(r.Context())
+
+	result, err := t.service.GetComments(user, uint(id), filter)
+	if err != nil {
+		code := ticketErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch ticket comments",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch ticket comments", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Ticket comments fetched successfully", map[string]any{
+		"ticketId": id,
+		"count":    len(result.Data),
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// CreateComment godoc
+// @Summary      Comment on a ticket
+// @Description  Add an internal or a public comment to a ticket, public comments are sent to the customer as a reply in the ticket's conversation
+// @Tags         organization-tickets
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Ticket ID"
+// @Param        request body requestdto.CreateTicketCommentRequest true "Create Ticket Comment Request"
+// @Success      201  {object}  responsedto.TicketCommentResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket/{id}/comments [post]
+func (t *OrganizationTicketHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid ticket id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid ticket ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.CreateTicketCommentRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		errorData := responsedto.ErrorResponse{
 			Message: "invalid request",
@@ -171,21 +576,570 @@ func (t *OrganizationTicketHandler) UpdateTicket(w http.ResponseWriter, r *http.
 
 	user, _ := t.jwtService.GetUserFromContext(r.Context())
 
-	err = t.service.UpdateTicket(user, uint(id), req)
+	result, err := t.service.CreateComment(user, uint(id), req)
 	if err != nil {
+		code := ticketErrorCode(err)
 		errorData := responsedto.ErrorResponse{
-			Message: "failed to update ticket",
+			Message: "failed to create ticket comment",
 			Error:   err.Error(),
-			Code:    http.StatusInternalServerError,
+			Code:    code,
 		}
-		logger.ErrorLog("Failed to update ticket", errorData)
-		utils.WriteJSONResponse(w, http.StatusInternalServerError, errorData)
+		logger.ErrorLog("Failed to create ticket comment", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
 		return
 	}
 
-	result := responsedto.CommonResponse{
-		Message: "Ticket updated successfully",
+	logger.InfoLog("Ticket comment created successfully", result)
+	utils.WriteJSONResponse(w, http.StatusCreated, result)
+}
+
+// CreateLink godoc
+// @Summary      Link two tickets
+// @Description  Link the ticket to another ticket of the organization as its parent, child, duplicate or related ticket. A ticket has one parent at most and is a duplicate of one ticket at most
+// @Tags         organization-tickets
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Ticket ID"
+// @Param        request body requestdto.CreateTicketLinkRequest true "Create Ticket Link Request"
+// @Success      201  {object}  responsedto.TicketLinkResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      409  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket/{id}/links [post]
+func (t *OrganizationTicketHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid ticket id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid ticket ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.CreateTicketLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := t.jwtService.GetUserFromContext(r.Context())
+
+	result, err := t.service.CreateLink(user, uint(id), req)
+	if err != nil {
+		code := ticketErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to link tickets",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to link tickets", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Tickets linked successfully", result)
+	utils.WriteJSONResponse(w, http.StatusCreated, result)
+}
+
+// DeleteLink godoc
+// @Summary      Unlink two tickets
+// @Description  Remove a link of the ticket
+// @Tags         organization-tickets
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Ticket ID"
+// @Param        linkId path int true "Link ID"
+// @Success      200  {object}  responsedto.CommonResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket/{id}/links/{linkId} [delete]
+func (t *OrganizationTicketHandler) DeleteLink(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid ticket id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid ticket ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	linkID, err := strconv.ParseUint(chi.URLParam(r, "linkId"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid link id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid link ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := t.jwtService.GetUserFromContext(r.Context())
+
+	if err := t.service.DeleteLink(user, uint(id), uint(linkID)); err != nil {
+		code := ticketErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to unlink tickets",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to unlink tickets", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	result := responsedto.CommonResponse{
+		Message: "Tickets unlinked successfully",
+		Code:    http.StatusOK,
+	}
+	logger.InfoLog("Tickets unlinked successfully", map[string]any{
+		"ticket_id": id,
+		"link_id":   linkID,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// AddConversation godoc
+// @Summary      Add a conversation to a ticket
+// @Description  Make the ticket cover another conversation of the organization, its customer is told when the ticket closes
+// @Tags         organization-tickets
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Ticket ID"
+// @Param        request body requestdto.AddTicketConversationRequest true "Add Ticket Conversation Request"
+// @Success      201  {object}  responsedto.ConversationResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      409  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket/{id}/conversations [post]
+func (t *OrganizationTicketHandler) AddConversation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid ticket id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid ticket ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.AddTicketConversationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := t.jwtService.GetUserFromContext(r.Context())
+
+	result, err := t.service.AddConversation(user, uint(id), req)
+	if err != nil {
+		code := ticketErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to add ticket conversation",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to add ticket conversation", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Ticket conversation added successfully", map[string]any{
+		"ticket_id":       id,
+		"conversation_id": result.ID,
+	})
+	utils.WriteJSONResponse(w, http.StatusCreated, result)
+}
+
+// RemoveConversation godoc
+// @Summary      Remove a conversation from a ticket
+// @Description  Stop the ticket from covering a conversation, the conversation the ticket was created from stays
+// @Tags         organization-tickets
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Ticket ID"
+// @Param        conversationId path int true "Conversation ID"
+// @Success      200  {object}  responsedto.CommonResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      409  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket/{id}/conversations/{conversationId} [delete]
+func (t *OrganizationTicketHandler) RemoveConversation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid ticket id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid ticket ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	conversationID, err := strconv.ParseUint(chi.URLParam(r, "conversationId"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid conversation id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid conversation ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := t.jwtService.GetUserFromContext(r.Context())
+
+	if err := t.service.RemoveConversation(user, uint(id), uint(conversationID)); err != nil {
+		code := ticketErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to remove ticket conversation",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to remove ticket conversation", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	result := responsedto.CommonResponse{
+		Message: "Ticket conversation removed successfully",
+		Code:    http.StatusOK,
+	}
+	logger.InfoLog("Ticket conversation removed successfully", map[string]any{
+		"ticket_id":       id,
+		"conversation_id": conversationID,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// UpdateTicket godoc
+// @Summary      Update ticket
+// @Description  Update a ticket's details. cascadeToChildren moves the child tickets to the new status as well, and notifyCustomers tells the customers of the ticket, its children and its duplicates when it closes
+// @Tags         organization-tickets
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Ticket ID"
+// @Param        request body requestdto.UpdateTicketRequest true "Update Ticket Request"
+// @Success      200  {object}  responsedto.TicketResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      409  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket/{id} [put]
+func (t *OrganizationTicketHandler) UpdateTicket(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid ticket id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid ticket ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.UpdateTicketRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := t.jwtService.GetUserFromContext(r.Context())
+
+	err = t.service.UpdateTicket(user, uint(id), req)
+	if err != nil {
+		code := ticketErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to update ticket",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to update ticket", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	result := responsedto.CommonResponse{
+		Message: "Ticket updated successfully",
+	}
+	logger.InfoLog("Ticket updated successfully", result)
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// GetNumberFormat godoc
+// @Summary      Get the ticket number format
+// @Description  Get how the organization's ticket numbers are built and the number the next ticket gets
+// @Tags         organization-tickets
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  responsedto.TicketNumberFormatResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket-numbering [get]
+func (t *OrganizationTicketHandler) GetNumberFormat(w http.ResponseWriter, r *http.Request) {
+	user, _ := t.jwtService.GetUserFromContext(r.Context())
+
+	result, err := t.service.GetNumberFormat(user)
+	if err != nil {
+		code := ticketErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch ticket number format",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch ticket number format", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Ticket number format fetched successfully", result)
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// UpdateNumberFormat godoc
+// @Summary      Update the ticket number format
+// @Description  Set the prefix, the date part and the zero padding of new ticket numbers, existing tickets keep their numbers
+// @Tags         organization-tickets
+// @Accept       json
+// @Produce      json
+// @Param        request body requestdto.UpdateTicketNumberFormatRequest true "Update Ticket Number Format Request"
+// @Success      200  {object}  responsedto.TicketNumberFormatResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket-numbering [put]
+func (t *OrganizationTicketHandler) UpdateNumberFormat(w http.ResponseWriter, r *http.Request) {
+	var req requestdto.UpdateTicketNumberFormatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := t.jwtService.GetUserFromContext(r.Context())
+
+	result, err := t.service.UpdateNumberFormat(user, req)
+	if err != nil {
+		code := ticketErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to update ticket number format",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to update ticket number format", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Ticket number format updated successfully", result)
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// GetWorkflow godoc
+// @Summary      Get the ticket workflow
+// @Description  Get the statuses of the organization's tickets, the status changes allowed between them and the status new tickets start in
+// @Tags         organization-tickets
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  responsedto.TicketWorkflowResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket-workflow [get]
+func (t *OrganizationTicketHandler) GetWorkflow(w http.ResponseWriter, r *http.Request) {
+	user, _ := t.jwtService.GetUserFromContext(r.Context())
+
+	result, err := t.service.GetWorkflow(user)
+	if err != nil {
+		code := ticketErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch ticket workflow",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch ticket workflow", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Ticket workflow fetched successfully", result)
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// UpdateWorkflow godoc
+// @Summary      Update the ticket workflow
+// @Description  Replace the statuses and allowed status changes of the organization's tickets, statuses still used by tickets can not be removed
+// @Tags         organization-tickets
+// @Accept       json
+// @Produce      json
+// @Param        request body requestdto.UpdateTicketWorkflowRequest true "Update Ticket Workflow Request"
+// @Success      200  {object}  responsedto.TicketWorkflowResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      409  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket-workflow [put]
+func (t *OrganizationTicketHandler) UpdateWorkflow(w http.ResponseWriter, r *http.Request) {
+	var req requestdto.UpdateTicketWorkflowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := t.jwtService.GetUserFromContext(r.Context())
+
+	result, err := t.service.UpdateWorkflow(user, req)
+	if err != nil {
+		code := ticketErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to update ticket workflow",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to update ticket workflow", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Ticket workflow updated successfully", result)
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+func ticketErrorCode(err error) int {
+	switch {
+	case errors.Is(err, impl.ErrOrganizationNotFound),
+		errors.Is(err, impl.ErrTicketNotFound),
+		errors.Is(err, impl.ErrTicketLinkNotFound),
+		errors.Is(err, impl.ErrTicketConversationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, impl.ErrTicketNumberFormatInvalid),
+		errors.Is(err, impl.ErrTicketAssigneeInvalid),
+		errors.Is(err, impl.ErrTicketCommentEmpty),
+		errors.Is(err, impl.ErrTicketWorkflowInvalid),
+		errors.Is(err, impl.ErrTicketStatusInvalid),
+		errors.Is(err, impl.ErrTicketResolutionNoteRequired),
+		errors.Is(err, impl.ErrTicketLinkInvalid),
+		errors.Is(err, impl.ErrTicketSortInvalid),
+		errors.Is(err, impl.ErrTicketImportInvalid),
+		errors.Is(err, impl.ErrTicketFieldUnknown),
+		errors.Is(err, impl.ErrTicketFieldInvalid),
+		errors.Is(err, impl.ErrTicketFieldRequired):
+		return http.StatusBadRequest
+	case errors.Is(err, impl.ErrTicketTransitionNotAllowed),
+		errors.Is(err, impl.ErrTicketWorkflowStatusInUse),
+		errors.Is(err, impl.ErrTicketLinkExists),
+		errors.Is(err, impl.ErrTicketConversationExists),
+		errors.Is(err, impl.ErrTicketConversationPrimary):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
 	}
-	logger.InfoLog("Ticket updated successfully", result)
-	utils.WriteJSONResponse(w, http.StatusOK, result)
 }
@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type OrganizationTicketSLAHandler struct {
+	jwtService jwtLib.JwtService
+	service    services.TicketSLAService
+}
+
+func NewOrganizationTicketSLAHandler(
+	jwtService jwtLib.JwtService,
+	service services.TicketSLAService,
+) *OrganizationTicketSLAHandler {
+	return &OrganizationTicketSLAHandler{
+		jwtService: jwtService,
+		service:    service,
+	}
+}
+
+// GetPolicies godoc
+// @Summary      List ticket SLA policies
+// @Description  List the SLA policies of the organization, a ticket gets the policy matching both its priority and category first, then its category, then its priority, then the policy without either
+// @Tags         organization-ticket-sla
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  responsedto.TicketSLAPolicyListResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket-sla-policies [get]
+func (h *OrganizationTicketSLAHandler) GetPolicies(w http.ResponseWriter, r *http.Request) {
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.GetPolicies(user)
+	if err != nil {
+		code := ticketSLAErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch ticket SLA policies",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch ticket SLA policies", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Ticket SLA policies fetched successfully", map[string]any{
+		"count": len(result.Policies),
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// CreatePolicy godoc
+// @Summary      Create a ticket SLA policy
+// @Description  Create an SLA policy with the actions to run when a ticket gets close to its deadline and when it breaches it, open tickets pick up the new policy right away
+// @Tags         organization-ticket-sla
+// @Accept       json
+// @Produce      json
+// @Param        request body requestdto.TicketSLAPolicyRequest true "Ticket SLA Policy Request"
+// @Success      201  {object}  responsedto.TicketSLAPolicyResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      409  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket-sla-policies [post]
+func (h *OrganizationTicketSLAHandler) CreatePolicy(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeTicketSLAPolicyRequest(w, r)
+	if !ok {
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.CreatePolicy(user, req)
+	if err != nil {
+		code := ticketSLAErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to create ticket SLA policy",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to create ticket SLA policy", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Ticket SLA policy created successfully", map[string]any{
+		"policy_id": result.ID,
+	})
+	utils.WriteJSONResponse(w, http.StatusCreated, result)
+}
+
+// UpdatePolicy godoc
+// @Summary      Update a ticket SLA policy
+// @Description  Replace an SLA policy, the deadlines of open tickets are computed again from their creation time
+// @Tags         organization-ticket-sla
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Policy ID"
+// @Param        request body requestdto.TicketSLAPolicyRequest true "Ticket SLA Policy Request"
+// @Success      200  {object}  responsedto.TicketSLAPolicyResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      409  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket-sla-policies/{id} [put]
+func (h *OrganizationTicketSLAHandler) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid policy id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid policy ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	req, ok := decodeTicketSLAPolicyRequest(w, r)
+	if !ok {
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.UpdatePolicy(user, uint(id), req)
+	if err != nil {
+		code := ticketSLAErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to update ticket SLA policy",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to update ticket SLA policy", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Ticket SLA policy updated successfully", map[string]any{
+		"policy_id": result.ID,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// DeletePolicy godoc
+// @Summary      Delete a ticket SLA policy
+// @Description  Delete an SLA policy, its open tickets move to the next matching policy or lose their deadline
+// @Tags         organization-ticket-sla
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Policy ID"
+// @Success      200  {object}  responsedto.CommonResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket-sla-policies/{id} [delete]
+func (h *OrganizationTicketSLAHandler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid policy id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid policy ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	if err := h.service.DeletePolicy(user, uint(id)); err != nil {
+		code := ticketSLAErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to delete ticket SLA policy",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to delete ticket SLA policy", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	result := responsedto.CommonResponse{
+		Message: "Ticket SLA policy deleted successfully",
+		Code:    http.StatusOK,
+	}
+	logger.InfoLog("Ticket SLA policy deleted successfully", map[string]any{
+		"policy_id": id,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+func decodeTicketSLAPolicyRequest(w http.ResponseWriter, r *http.Request) (requestdto.TicketSLAPolicyRequest, bool) {
+	var req requestdto.TicketSLAPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return req, false
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return req, false
+	}
+
+	return req, true
+}
+
+func ticketSLAErrorCode(err error) int {
+	switch {
+	case errors.Is(err, impl.ErrOrganizationNotFound),
+		errors.Is(err, impl.ErrTicketSLAPolicyNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, impl.ErrTicketSLAPolicyInvalid),
+		errors.Is(err, impl.ErrTicketAssigneeInvalid):
+		return http.StatusBadRequest
+	case errors.Is(err, impl.ErrTicketSLAPolicyDuplicate):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
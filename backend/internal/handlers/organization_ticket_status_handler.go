@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/utils"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type OrganizationTicketStatusHandler struct {
+	jwtService jwtLib.JwtService
+	service    services.TicketStatusService
+}
+
+func NewOrganizationTicketStatusHandler(
+	jwtService jwtLib.JwtService,
+	service services.TicketStatusService,
+) *OrganizationTicketStatusHandler {
+	return &OrganizationTicketStatusHandler{
+		jwtService: jwtService,
+		service:    service,
+	}
+}
+
+// CreateStatusLink godoc
+// @Summary      Create a ticket status link
+// @Description  Make a signed link to the public status page of the ticket for customers without an account. With send the link is also posted to the customer on the channel of the ticket's conversation, e.g. WhatsApp or email. With revokePrevious the links made before stop working
+// @Tags         organization-tickets
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Ticket ID"
+// @Param        request body requestdto.CreateTicketStatusLinkRequest false "Create Ticket Status Link Request"
+// @Success      201  {object}  responsedto.TicketStatusLinkResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket/{id}/status-link [post]
+func (h *OrganizationTicketStatusHandler) CreateStatusLink(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid ticket id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid ticket ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.CreateTicketStatusLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.CreateStatusLink(user, uint(id), req)
+	if err != nil {
+		code := ticketStatusLinkErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to create ticket status link",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to create ticket status link", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Ticket status link created successfully", map[string]any{
+		"ticket_id":  id,
+		"expires_at": result.ExpiresAt,
+		"sent":       result.MessageID != nil,
+	})
+	utils.WriteJSONResponse(w, http.StatusCreated, result)
+}
+
+func ticketStatusLinkErrorCode(err error) int {
+	switch {
+	case errors.Is(err, impl.ErrOrganizationNotFound),
+		errors.Is(err, impl.ErrTicketNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
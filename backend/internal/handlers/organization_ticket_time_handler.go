@@ -0,0 +1,464 @@
+package handlers
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type OrganizationTicketTimeHandler struct {
+	jwtService jwtLib.JwtService
+	service    services.TicketTimeService
+}
+
+func NewOrganizationTicketTimeHandler(
+	jwtService jwtLib.JwtService,
+	service services.TicketTimeService,
+) *OrganizationTicketTimeHandler {
+	return &OrganizationTicketTimeHandler{
+		jwtService: jwtService,
+		service:    service,
+	}
+}
+
+// GetEntries godoc
+// @Summary      List ticket time entries
+// @Description  List the time logged on the ticket, newest first, with the totals of the stopped entries
+// @Tags         organization-ticket-time
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Ticket ID"
+// @Success      200  {object}  responsedto.TicketTimeEntryListResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket/{id}/time-entries [get]
+func (h *OrganizationTicketTimeHandler) GetEntries(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid ticket id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid ticket ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.GetEntries(user, uint(id))
+	if err != nil {
+		code := ticketTimeErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch ticket time entries",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch ticket time entries", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Ticket time entries fetched successfully", map[string]any{
+		"ticket_id": id,
+		"count":     len(result.Entries),
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// CreateEntry godoc
+// @Summary      Log time on a ticket
+// @Description  Log time the caller spent on the ticket. startedAt defaults to durationMinutes before now, the entry can not end in the future
+// @Tags         organization-ticket-time
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Ticket ID"
+// @Param        request body requestdto.CreateTicketTimeEntryRequest true "Create Ticket Time Entry Request"
+// @Success      201  {object}  responsedto.TicketTimeEntryResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket/{id}/time-entries [post]
+func (h *OrganizationTicketTimeHandler) CreateEntry(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid ticket id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid ticket ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.CreateTicketTimeEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.CreateEntry(user, uint(id), req)
+	if err != nil {
+		code := ticketTimeErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to log ticket time",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to log ticket time", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Ticket time logged successfully", map[string]any{
+		"ticket_id": id,
+		"entry_id":  result.ID,
+		"seconds":   result.DurationSeconds,
+	})
+	utils.WriteJSONResponse(w, http.StatusCreated, result)
+}
+
+// DeleteEntry godoc
+// @Summary      Delete a ticket time entry
+// @Description  Delete a time entry the caller logged on the ticket, deleting a running timer cancels it
+// @Tags         organization-ticket-time
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Ticket ID"
+// @Param        entryId path int true "Time Entry ID"
+// @Success      200  {object}  responsedto.CommonResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket/{id}/time-entries/{entryId} [delete]
+func (h *OrganizationTicketTimeHandler) DeleteEntry(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid ticket id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid ticket ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	entryID, err := strconv.ParseUint(chi.URLParam(r, "entryId"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid time entry id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid time entry ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	if err := h.service.DeleteEntry(user, uint(id), uint(entryID)); err != nil {
+		code := ticketTimeErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to delete ticket time entry",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to delete ticket time entry", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	result := responsedto.CommonResponse{
+		Message: "Ticket time entry deleted successfully",
+		Code:    http.StatusOK,
+	}
+	logger.InfoLog("Ticket time entry deleted successfully", map[string]any{
+		"ticket_id": id,
+		"entry_id":  entryID,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// StartTimer godoc
+// @Summary      Start a timer on a ticket
+// @Description  Start the caller's timer on the ticket. A staff member runs one timer at a time, starting a second one fails until the first is stopped
+// @Tags         organization-ticket-time
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Ticket ID"
+// @Param        request body requestdto.StartTicketTimerRequest false "Start Ticket Timer Request"
+// @Success      201  {object}  responsedto.TicketTimeEntryResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      409  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket/{id}/timer/start [post]
+func (h *OrganizationTicketTimeHandler) StartTimer(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid ticket id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid ticket ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.StartTicketTimerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.StartTimer(user, uint(id), req)
+	if err != nil {
+		code := ticketTimeErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to start timer",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to start timer", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Timer started successfully", map[string]any{
+		"ticket_id": id,
+		"entry_id":  result.ID,
+	})
+	utils.WriteJSONResponse(w, http.StatusCreated, result)
+}
+
+// StopTimer godoc
+// @Summary      Stop the timer on a ticket
+// @Description  Stop the caller's running timer on the ticket and record its duration, a given note or billable flag replaces the one set at the start
+// @Tags         organization-ticket-time
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "Ticket ID"
+// @Param        request body requestdto.StopTicketTimerRequest false "Stop Ticket Timer Request"
+// @Success      200  {object}  responsedto.TicketTimeEntryResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      409  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/ticket/{id}/timer/stop [post]
+func (h *OrganizationTicketTimeHandler) StopTimer(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid ticket id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid ticket ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.StopTicketTimerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.StopTimer(user, uint(id), req)
+	if err != nil {
+		code := ticketTimeErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to stop timer",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to stop timer", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Timer stopped successfully", map[string]any{
+		"ticket_id": id,
+		"entry_id":  result.ID,
+		"seconds":   result.DurationSeconds,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// GetReport godoc
+// @Summary      Time report
+// @Description  Sum the stopped time entries that started between from and to, inclusive dates in the timezone of the organization, for the organization, per agent and per ticket. The range can be up to 366 days
+// @Tags         organization-ticket-time
+// @Accept       json
+// @Produce      json
+// @Param        from      query  string  true   "First day, YYYY-MM-DD"
+// @Param        to        query  string  true   "Last day, YYYY-MM-DD"
+// @Param        userId    query  int     false  "Only the entries of the staff member"
+// @Param        ticketId  query  int     false  "Only the entries of the ticket"
+// @Success      200  {object}  responsedto.TicketTimeReportResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/time-report [get]
+func (h *OrganizationTicketTimeHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseTicketTimeReportFilters(r)
+	if err == nil {
+		err = utils.ValidateStruct(filter)
+	}
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid time report filter",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid time report filter", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := h.jwtService.GetUserFromContext(r.Context())
+
+	result, err := h.service.GetReport(user, filter)
+	if err != nil {
+		code := ticketTimeErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to build time report",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to build time report", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Time report built successfully", map[string]any{
+		"from":    result.From,
+		"to":      result.To,
+		"entries": result.Totals.Entries,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+func parseTicketTimeReportFilters(r *http.Request) (filtersdto.TicketTimeReportFiltersDto, error) {
+	query := r.URL.Query()
+	filter := filtersdto.TicketTimeReportFiltersDto{
+		From: query.Get("from"),
+		To:   query.Get("to"),
+	}
+
+	ids := map[string]**uint{
+		"userId":   &filter.UserID,
+		"ticketId": &filter.TicketID,
+	}
+	for name, target := range ids {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return filter, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		parsed := uint(id)
+		*target = &parsed
+	}
+
+	return filter, nil
+}
+
+func ticketTimeErrorCode(err error) int {
+	switch {
+	case errors.Is(err, impl.ErrOrganizationNotFound),
+		errors.Is(err, impl.ErrTicketNotFound),
+		errors.Is(err, impl.ErrTicketTimeEntryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, impl.ErrTicketTimerRunning),
+		errors.Is(err, impl.ErrTicketTimerNotRunning):
+		return http.StatusConflict
+	case errors.Is(err, impl.ErrTicketTimeEntryInvalid),
+		errors.Is(err, impl.ErrTicketTimeReportInvalid):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
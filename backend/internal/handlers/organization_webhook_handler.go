@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+type OrganizationWebhookHandler struct {
+	jwtService jwtLib.JwtService
+	webhookSvc services.WebHookConversationService
+}
+
+func NewOrganizationWebhookHandler(
+	jwtService jwtLib.JwtService,
+	webhookSvc services.WebHookConversationService,
+) *OrganizationWebhookHandler {
+	return &OrganizationWebhookHandler{
+		jwtService: jwtService,
+		webhookSvc: webhookSvc,
+	}
+}
+
+// GetSettings godoc
+// @Summary      Get webhook settings
+// @Description  Get whether the webhook secret is set and the https hosts staff replies may be sent to
+// @Tags         organization-webhook
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  responsedto.WebhookSettingsResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/webhook [get]
+func (t *OrganizationWebhookHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	user, _ := t.jwtService.GetUserFromContext(r.Context())
+
+	result, err := t.webhookSvc.GetSettings(user)
+	if err != nil {
+		code := webhookSettingsErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch webhook settings",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch webhook settings", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Webhook settings fetched successfully", result)
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// UpdateSettings godoc
+// @Summary      Update webhook settings
+// @Description  Replace the https hosts staff replies may be sent to, *.example.com allows every subdomain. Set signatureRequired once the integration signs every request with the rotated secret, unsigned requests are refused from then on. It needs a secret and is left as it is when omitted.
+// @Tags         organization-webhook
+// @Accept       json
+// @Produce      json
+// @Param        request body requestdto.UpdateWebhookSettingsRequest true "Update Webhook Settings Request"
+// @Success      200  {object}  responsedto.WebhookSettingsResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/webhook [put]
+func (t *OrganizationWebhookHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var req requestdto.UpdateWebhookSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := t.jwtService.GetUserFromContext(r.Context())
+
+	result, err := t.webhookSvc.UpdateSettings(user, req)
+	if err != nil {
+		code := webhookSettingsErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to update webhook settings",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to update webhook settings", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Webhook settings updated successfully", result)
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// RotateSecret godoc
+// @Summary      Rotate webhook secret
+// @Description  Create a new secret for signing webhook requests, it is only shown in this response and the previous one stops working at once
+// @Tags         organization-webhook
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  responsedto.WebhookSettingsResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/webhook/secret [post]
+func (t *OrganizationWebhookHandler) RotateSecret(w http.ResponseWriter, r *http.Request) {
+	user, _ := t.jwtService.GetUserFromContext(r.Context())
+
+	result, err := t.webhookSvc.RotateSecret(user)
+	if err != nil {
+		code := webhookSettingsErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to rotate webhook secret",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to rotate webhook secret", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Webhook secret rotated", map[string]any{
+		"organization_id": result.OrganizationID,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+func webhookSettingsErrorCode(err error) int {
+	switch {
+	case errors.Is(err, impl.ErrOrganizationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, impl.ErrWebhookCallbackHostInvalid), errors.Is(err, impl.ErrWebhookSecretNotConfigured):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/utils"
+	"encoding/json"
+	"net/http"
+)
+
+type OrganizationWidgetHandler struct {
+	jwtService jwtLib.JwtService
+	widgetSvc  services.WidgetService
+}
+
+func NewOrganizationWidgetHandler(
+	jwtService jwtLib.JwtService,
+	widgetSvc services.WidgetService,
+) *OrganizationWidgetHandler {
+	return &OrganizationWidgetHandler{
+		jwtService: jwtService,
+		widgetSvc:  widgetSvc,
+	}
+}
+
+// GetSettings godoc
+// @Summary      Get widget settings
+// @Description  Get the slug and the websites allowed to embed the organization's chat widget
+// @Tags         organization-widget
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  responsedto.WidgetSettingsResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/widget [get]
+func (t *OrganizationWidgetHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	user, _ := t.jwtService.GetUserFromContext(r.Context())
+
+	result, err := t.widgetSvc.GetSettings(user)
+	if err != nil {
+		code := widgetErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch widget settings",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch widget settings", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Widget settings fetched successfully", result)
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// UpdateSettings godoc
+// @Summary      Update widget settings
+// @Description  Change the slug of the widget url and the websites allowed to embed it, origins are kept as scheme://host[:port] and *.example.com allows every subdomain
+// @Tags         organization-widget
+// @Accept       json
+// @Produce      json
+// @Param        request body requestdto.UpdateWidgetSettingsRequest true "Update Widget Settings Request"
+// @Success      200  {object}  responsedto.WidgetSettingsResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      409  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /organizations/widget [put]
+func (t *OrganizationWidgetHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var req requestdto.UpdateWidgetSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	user, _ := t.jwtService.GetUserFromContext(r.Context())
+
+	result, err := t.widgetSvc.UpdateSettings(user, req)
+	if err != nil {
+		code := widgetErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to update widget settings",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to update widget settings", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Widget settings updated successfully", result)
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
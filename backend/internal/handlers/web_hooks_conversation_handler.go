@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"DewaSRY/sociomile-app/internal/middleware"
 	"DewaSRY/sociomile-app/internal/services"
 	"DewaSRY/sociomile-app/internal/services/impl"
 	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
@@ -13,26 +14,33 @@ import (
 )
 
 type WebHookHandler struct {
-	service services.WebHookConversationService
+	service         services.WebHookConversationService
+	deliveryService services.OutboundDeliveryService
 }
 
 func NewWebHookHandler(
 	service services.WebHookConversationService,
+	deliveryService services.OutboundDeliveryService,
 ) *WebHookHandler {
 	return &WebHookHandler{
-		service: service,
+		service:         service,
+		deliveryService: deliveryService,
 	}
 }
 
 // CreateOrganization godoc
 // @Summary      Create message conversation
-// @Description  Create message conversation
+// @Description  Accept a message for a conversation, the event is stored in the inbox and processed in the background. A signed request is checked against the organization's webhook secret: X-Sociomile-Signature is t=<unix time>,v1=<hex hmac-sha256 of "<t>.<body>">. Unsigned requests are accepted until the organization sets signatureRequired, to move over rotate a secret, sign every request with it and then turn signatureRequired on in the webhook settings. The callback url must be https on one of the organization's callback hosts.
 // @Tags         webhooks-conversation
 // @Accept       json
 // @Produce      json
+// @Param        X-Sociomile-Organization header string false "Organization id the request is signed for, defaults to organizationId of the body"
+// @Param        X-Sociomile-Signature header string false "t=<unix time>,v1=<signature>, required once the organization sets signatureRequired"
 // @Param        request body requestdto.WebHooksRequest true "Create message conversation"
-// @Success      201  {object}  responsedto.CommonResponse
+// @Success      202  {object}  responsedto.CommonResponse{data=responsedto.WebhookAcceptedResponse}
 // @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      401  {object}  responsedto.ErrorResponse
+// @Failure      429  {object}  responsedto.ErrorResponse
 // @Failure      500  {object}  responsedto.ErrorResponse
 // @Router       /webhooks/conversations [post]
 func (h *WebHookHandler) CreateConversation(w http.ResponseWriter, r *http.Request) {
@@ -60,9 +68,30 @@ func (h *WebHookHandler) CreateConversation(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	err := h.service.ProcessConversation(req)
+	if organizationID, _ := middleware.GetWebhookOrganization(r.Context()); organizationID != req.OrganizationID {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   "organizationId does not match the signed organization",
+			Code:    http.StatusUnauthorized,
+		}
+		logger.ErrorLog("Webhook organization mismatch", errorData)
+		utils.WriteJSONResponse(w, http.StatusUnauthorized, errorData)
+		return
+	}
+
+	accepted, err := h.service.EnqueueConversation(req)
 	if err != nil {
 
+		if errors.Is(err, impl.ErrWebhookCallbackNotAllowed) {
+			errorData := responsedto.ErrorResponse{
+				Message: "invalid request",
+				Error:   err.Error(),
+				Code:    http.StatusBadRequest,
+			}
+			logger.ErrorLog("Webhook callback url rejected", errorData)
+			utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+			return
+		}
 		if errors.Is(err, impl.ErrOrganizationNotFound) {
 			errorData := responsedto.ErrorResponse{
 				Message: "Organization Is not found",
@@ -84,10 +113,78 @@ func (h *WebHookHandler) CreateConversation(w http.ResponseWriter, r *http.Reque
 	}
 
 	result := responsedto.CommonResponse{
-		Message: "Success Create message conversation ",
-		Code:    http.StatusCreated,
+		Message: "Message conversation accepted",
+		Data:    accepted,
+		Code:    http.StatusAccepted,
+	}
+
+	logger.InfoLog("Webhook event accepted", result)
+	utils.WriteJSONResponse(w, http.StatusAccepted, result)
+}
+
+// DeliveryReceipt godoc
+// @Summary      Delivery receipt for a staff reply
+// @Description  Integrations confirm or reject a reply sent to their callback url, using the receipt token from the payload
+// @Tags         webhooks-conversation
+// @Accept       json
+// @Produce      json
+// @Param        request body requestdto.DeliveryReceiptRequest true "Delivery receipt"
+// @Success      200  {object}  responsedto.CommonResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      401  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Router       /webhooks/deliveries/receipt [post]
+func (h *WebHookHandler) DeliveryReceipt(w http.ResponseWriter, r *http.Request) {
+	var req requestdto.DeliveryReceiptRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
 	}
 
-	logger.InfoLog("Organization created successfully", result)
-	utils.WriteJSONResponse(w, http.StatusCreated, result)
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := h.deliveryService.HandleReceipt(req); err != nil {
+		code := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, impl.ErrDeliveryNotFound):
+			code = http.StatusNotFound
+		case errors.Is(err, impl.ErrInvalidReceiptToken):
+			code = http.StatusUnauthorized
+		}
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to process delivery receipt",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to process delivery receipt", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	result := responsedto.CommonResponse{
+		Message: "Delivery receipt processed",
+		Code:    http.StatusOK,
+	}
+	logger.InfoLog("Delivery receipt processed", map[string]any{
+		"delivery_id": req.DeliveryID,
+		"status":      req.Status,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
 }
@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"DewaSRY/sociomile-app/internal/middleware"
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/internal/services/impl"
+	_ "DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/utils"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type WidgetHandler struct {
+	widgetSvc services.WidgetService
+}
+
+func NewWidgetHandler(widgetSvc services.WidgetService) *WidgetHandler {
+	return &WidgetHandler{
+		widgetSvc: widgetSvc,
+	}
+}
+
+// StartSession godoc
+// @Summary      Start a widget session
+// @Description  Issue an anonymous visitor token for the organization's chat widget. Sending a still valid token extends it and keeps the visitor, the body is optional
+// @Tags         widget
+// @Accept       json
+// @Produce      json
+// @Param        orgSlug path string true "Organization slug"
+// @Param        request body requestdto.StartWidgetSessionRequest false "Start Widget Session Request"
+// @Success      201  {object}  responsedto.WidgetSessionResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      403  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      429  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Router       /widget/{orgSlug}/session [post]
+func (t *WidgetHandler) StartSession(w http.ResponseWriter, r *http.Request) {
+	var req requestdto.StartWidgetSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	currentToken := ""
+	if parts := strings.Fields(r.Header.Get("Authorization")); len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") {
+		currentToken = parts[1]
+	}
+
+	result, err := t.widgetSvc.StartSession(chi.URLParam(r, "orgSlug"), currentToken, req)
+	if err != nil {
+		code := widgetErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to start widget session",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to start widget session", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Widget session started", map[string]any{
+		"organization_id": result.Organization.ID,
+		"contact_id":      result.ContactID,
+	})
+	utils.WriteJSONResponse(w, http.StatusCreated, result)
+}
+
+// Identify godoc
+// @Summary      Identify the widget visitor
+// @Description  Attach an email to the anonymous visitor, it is only kept as an unproven claim the staff may act on with a merge
+// @Tags         widget
+// @Accept       json
+// @Produce      json
+// @Param        orgSlug path string true "Organization slug"
+// @Param        request body requestdto.IdentifyWidgetVisitorRequest true "Identify Widget Visitor Request"
+// @Success      200  {object}  responsedto.WidgetSessionResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      401  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /widget/{orgSlug}/session/identify [post]
+func (t *WidgetHandler) Identify(w http.ResponseWriter, r *http.Request) {
+	var req requestdto.IdentifyWidgetVisitorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	session, _ := middleware.GetWidgetSession(r.Context())
+	token := strings.Fields(r.Header.Get("Authorization"))[1]
+
+	result, err := t.widgetSvc.Identify(session, token, req)
+	if err != nil {
+		code := widgetErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to identify visitor",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to identify widget visitor", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Widget visitor identified", map[string]any{
+		"contact_id": result.ContactID,
+		"identified": result.Identified,
+	})
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// GetConversations godoc
+// @Summary      Get the visitor's conversations
+// @Description  Retrieve the conversations the widget visitor started, newest first
+// @Tags         widget
+// @Accept       json
+// @Produce      json
+// @Param        orgSlug path string true "Organization slug"
+// @Param        request  query  filtersdto.FiltersDto  false  "Pagination query"
+// @Success      200  {object}  responsedto.WidgetConversationPaginateResponse
+// @Failure      401  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /widget/{orgSlug}/conversations [get]
+func (t *WidgetHandler) GetConversations(w http.ResponseWriter, r *http.Request) {
+	filter := utils.ParsePagination(r)
+	session, _ := middleware.GetWidgetSession(r.Context())
+
+	result, err := t.widgetSvc.GetConversations(session, filter)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch conversations",
+			Error:   err.Error(),
+			Code:    http.StatusInternalServerError,
+		}
+		logger.ErrorLog("Failed to fetch widget conversations", errorData)
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, errorData)
+		return
+	}
+
+	logger.InfoLog("Widget conversations fetched successfully", result.Metadata)
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// CreateConversation godoc
+// @Summary      Start a widget conversation
+// @Description  Create a conversation with its first message for the widget visitor
+// @Tags         widget
+// @Accept       json
+// @Produce      json
+// @Param        orgSlug path string true "Organization slug"
+// @Param        request body requestdto.CreateWidgetConversationRequest true "Create Widget Conversation Request"
+// @Success      201  {object}  responsedto.WidgetConversationResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      401  {object}  responsedto.ErrorResponse
+// @Failure      429  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /widget/{orgSlug}/conversations [post]
+func (t *WidgetHandler) CreateConversation(w http.ResponseWriter, r *http.Request) {
+	var req requestdto.CreateWidgetConversationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	session, _ := middleware.GetWidgetSession(r.Context())
+
+	result, err := t.widgetSvc.CreateConversation(session, req)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to create conversation",
+			Error:   err.Error(),
+			Code:    http.StatusInternalServerError,
+		}
+		logger.ErrorLog("Failed to create widget conversation", errorData)
+		utils.WriteJSONResponse(w, http.StatusInternalServerError, errorData)
+		return
+	}
+
+	logger.InfoLog("Widget conversation created", result)
+	utils.WriteJSONResponse(w, http.StatusCreated, result)
+}
+
+// GetMessages godoc
+// @Summary      Get widget conversation messages
+// @Description  Retrieve the messages of one of the visitor's conversations, oldest first
+// @Tags         widget
+// @Accept       json
+// @Produce      json
+// @Param        orgSlug path string true "Organization slug"
+// @Param        id path int true "Conversation ID"
+// @Param        request  query  filtersdto.FiltersDto  false  "Pagination query"
+// @Success      200  {object}  responsedto.WidgetMessagePaginateResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      401  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /widget/{orgSlug}/conversations/{id}/messages [get]
+func (t *WidgetHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid conversation id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid conversation ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	filter := utils.ParsePagination(r)
+	session, _ := middleware.GetWidgetSession(r.Context())
+
+	result, err := t.widgetSvc.GetMessages(session, uint(id), filter)
+	if err != nil {
+		code := widgetErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to fetch messages",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to fetch widget messages", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Widget messages fetched successfully", result.Metadata)
+	utils.WriteJSONResponse(w, http.StatusOK, result)
+}
+
+// SendMessage godoc
+// @Summary      Send a widget message
+// @Description  The widget visitor writes in one of their conversations
+// @Tags         widget
+// @Accept       json
+// @Produce      json
+// @Param        orgSlug path string true "Organization slug"
+// @Param        id path int true "Conversation ID"
+// @Param        request body requestdto.WidgetMessageRequest true "Widget Message Request"
+// @Success      201  {object}  responsedto.WidgetMessageResponse
+// @Failure      400  {object}  responsedto.ErrorResponse
+// @Failure      401  {object}  responsedto.ErrorResponse
+// @Failure      404  {object}  responsedto.ErrorResponse
+// @Failure      429  {object}  responsedto.ErrorResponse
+// @Failure      500  {object}  responsedto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /widget/{orgSlug}/conversations/{id}/messages [post]
+func (t *WidgetHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid conversation id",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Invalid conversation ID", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	var req requestdto.WidgetMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to decode request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	if err := utils.ValidateStruct(req); err != nil {
+		errorData := responsedto.ErrorResponse{
+			Message: "invalid request",
+			Error:   err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+		logger.ErrorLog("Failed to validate request", errorData)
+		utils.WriteJSONResponse(w, http.StatusBadRequest, errorData)
+		return
+	}
+
+	session, _ := middleware.GetWidgetSession(r.Context())
+
+	result, err := t.widgetSvc.SendMessage(session, uint(id), req)
+	if err != nil {
+		code := widgetErrorCode(err)
+		errorData := responsedto.ErrorResponse{
+			Message: "failed to send message",
+			Error:   err.Error(),
+			Code:    code,
+		}
+		logger.ErrorLog("Failed to send widget message", errorData)
+		utils.WriteJSONResponse(w, code, errorData)
+		return
+	}
+
+	logger.InfoLog("Widget message sent successfully", result)
+	utils.WriteJSONResponse(w, http.StatusCreated, result)
+}
+
+func widgetErrorCode(err error) int {
+	switch {
+	case errors.Is(err, impl.ErrWidgetNotFound),
+		errors.Is(err, impl.ErrWidgetConversationNotFound),
+		errors.Is(err, impl.ErrOrganizationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, impl.ErrWidgetSessionInvalid):
+		return http.StatusUnauthorized
+	case errors.Is(err, impl.ErrWidgetSlugInvalid),
+		errors.Is(err, impl.ErrWidgetOriginInvalid):
+		return http.StatusBadRequest
+	case errors.Is(err, impl.ErrWidgetSlugTaken):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
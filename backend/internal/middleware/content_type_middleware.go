@@ -0,0 +1,14 @@
+package middleware
+
+import (
+	"net/http"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// AllowContentType answers a request body of any other content type with
+// 415, requests without a body pass. Route groups that read JSON use it
+// with application/json, upload routes name the types they read.
+func AllowContentType(contentTypes ...string) func(http.Handler) http.Handler {
+	return chimiddleware.AllowContentType(contentTypes...)
+}
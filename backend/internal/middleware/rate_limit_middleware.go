@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtutil "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/lib/ratelimit"
+	"DewaSRY/sociomile-app/pkg/utils"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxPeekBytes caps how much of the body is read to find the organization
+// and the contact, the handler still gets the full body.
+const maxPeekBytes = 1 << 20
+
+// SubjectResolver tells the limiter who is calling the route.
+type SubjectResolver func(r *http.Request) ratelimit.Subject
+
+func RateLimit(limiter services.RateLimitService, resolve SubjectResolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			subject := resolve(r)
+
+			decision, scope := limiter.Allow(subject)
+			if !decision.Allowed {
+				retryAfter := decision.RetryAfterSeconds()
+				errorResponse := responsedto.ErrorResponse{
+					Message: "Too many requests",
+					Error:   fmt.Sprintf("%s rate limit exceeded, retry after %d seconds", scope, retryAfter),
+					Code:    http.StatusTooManyRequests,
+				}
+				logger.ErrorLog("Rate limit exceeded", map[string]any{
+					"route":           subject.Route,
+					"scope":           scope,
+					"organization_id": subject.OrganizationID,
+					"ip":              subject.IP,
+				})
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				utils.WriteJSONResponse(w, http.StatusTooManyRequests, errorResponse)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WebhookSubject limits the webhook ingest by the organization whose
+// secret signed the request and the customer email of the signed payload.
+// It runs after WebhookSignature, the caller's address is limited before
+// the signature is checked with IPSubject.
+func WebhookSubject(route string) SubjectResolver {
+	return func(r *http.Request) ratelimit.Subject {
+		organizationID, ok := GetWebhookOrganization(r.Context())
+		if !ok {
+			return ratelimit.Subject{Route: route, IP: clientIP(r)}
+		}
+
+		var body struct {
+			Email string `json:"email"`
+		}
+		peekJSONBody(r, &body)
+
+		return ratelimit.Subject{
+			Route:          route,
+			OrganizationID: organizationID,
+			ContactKey:     strings.ToLower(strings.TrimSpace(body.Email)),
+		}
+	}
+}
+
+// GuestSubject limits the guest routes by the logged in guest and the
+// organization of the target conversation. The organization is only taken
+// from a conversation of the guest, otherwise the caller's address is all
+// that is limited.
+func GuestSubject(route string, jwtSvc jwtutil.JwtService, limiter services.RateLimitService) SubjectResolver {
+	return func(r *http.Request) ratelimit.Subject {
+		subject := ratelimit.Subject{
+			Route: route,
+			IP:    clientIP(r),
+		}
+
+		user, err := jwtSvc.GetUserFromContext(r.Context())
+		if err || user == nil {
+			return subject
+		}
+		subject.ContactKey = fmt.Sprintf("user:%d", user.UserID)
+
+		var body struct {
+			OrganizationID uint `json:"organizationId"`
+			ConversationID uint `json:"conversationId"`
+		}
+		peekJSONBody(r, &body)
+
+		subject.OrganizationID = body.OrganizationID
+		if subject.OrganizationID == 0 && body.ConversationID != 0 {
+			subject.OrganizationID, _ = limiter.GuestConversationOrganizationID(user, body.ConversationID)
+		}
+
+		return subject
+	}
+}
+
+// IPSubject limits public routes that have no organization by the caller's
+// address only.
+func IPSubject(route string) SubjectResolver {
+	return func(r *http.Request) ratelimit.Subject {
+		return ratelimit.Subject{
+			Route: route,
+			IP:    clientIP(r),
+		}
+	}
+}
+
+func peekJSONBody(r *http.Request, dest any) {
+	if r.Body == nil {
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPeekBytes))
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+	if err != nil {
+		return
+	}
+
+	_ = json.Unmarshal(body, dest)
+}
+
+// clientIP is the address of the connection. Forwarding headers such as
+// X-Forwarded-For are not read since any caller can set them, so the
+// server is expected to face the clients directly. Behind a reverse proxy
+// every caller shares the proxy's address and the proxy has to limit per
+// client itself.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
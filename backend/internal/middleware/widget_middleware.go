@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/lib/ratelimit"
+	"DewaSRY/sociomile-app/pkg/models"
+	"DewaSRY/sociomile-app/pkg/utils"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type widgetSessionContextKey struct{}
+
+// WidgetCORS only lets the websites the organization allowed call its
+// widget routes from a browser. Requests without an Origin header are not
+// cross-site and pass through.
+func WidgetCORS(widgetSvc services.WidgetService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Origin")
+
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			allowed, err := widgetSvc.AllowedOrigins(chi.URLParam(r, "orgSlug"))
+			if err != nil || !widgetOriginAllowed(origin, allowed) {
+				errorResponse := responsedto.ErrorResponse{
+					Message: "Origin not allowed",
+					Error:   fmt.Sprintf("%s may not embed this widget", origin),
+					Code:    http.StatusForbidden,
+				}
+				logger.ErrorLog("Widget origin rejected", map[string]any{
+					"slug":   chi.URLParam(r, "orgSlug"),
+					"origin": origin,
+				})
+				utils.WriteJSONResponse(w, http.StatusForbidden, errorResponse)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Max-Age", "600")
+
+			if r.Method == http.MethodOptions {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// widgetOriginAllowed compares the browser origin with the allowed ones, an
+// allowed host starting with *. matches every subdomain of it.
+func widgetOriginAllowed(origin string, allowed []string) bool {
+	parsed, err := url.Parse(strings.ToLower(origin))
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+
+	for _, entry := range allowed {
+		if entry == parsed.Scheme+"://"+parsed.Host {
+			return true
+		}
+
+		pattern, err := url.Parse(entry)
+		if err != nil || pattern.Scheme != parsed.Scheme || pattern.Port() != parsed.Port() {
+			continue
+		}
+		if suffix, ok := strings.CutPrefix(pattern.Hostname(), "*."); ok &&
+			strings.HasSuffix(parsed.Hostname(), "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// WidgetAuth checks the visitor token of the widget routes against the
+// organization in the path.
+func WidgetAuth(widgetSvc services.WidgetService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			parts := strings.Fields(r.Header.Get("Authorization"))
+			if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+				utils.WriteJSONResponse(w, http.StatusUnauthorized, map[string]any{
+					"path":    r.URL.Path,
+					"error":   "unauthorized",
+					"message": "missing or invalid authorization header",
+				})
+				return
+			}
+
+			session, err := widgetSvc.Authenticate(chi.URLParam(r, "orgSlug"), parts[1])
+			if err != nil {
+				utils.WriteJSONResponse(w, http.StatusUnauthorized, map[string]any{
+					"path":    r.URL.Path,
+					"error":   "unauthorized",
+					"message": "invalid or expired widget session",
+				})
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), widgetSessionContextKey{}, session)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetWidgetSession returns the session stored by WidgetAuth.
+func GetWidgetSession(ctx context.Context) (*models.WidgetSessionModel, bool) {
+	session, ok := ctx.Value(widgetSessionContextKey{}).(*models.WidgetSessionModel)
+	return session, ok
+}
+
+// WidgetSubject limits the widget routes by the organization and the
+// visitor of the session, it must run after WidgetAuth.
+func WidgetSubject(route string) SubjectResolver {
+	return func(r *http.Request) ratelimit.Subject {
+		subject := ratelimit.Subject{
+			Route: route,
+			IP:    clientIP(r),
+		}
+		if session, ok := GetWidgetSession(r.Context()); ok {
+			subject.OrganizationID = session.OrganizationID
+			subject.ContactKey = fmt.Sprintf("widget:%d", session.ContactID)
+		}
+		return subject
+	}
+}
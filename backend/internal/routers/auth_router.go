@@ -3,6 +3,7 @@ package routers
 import (
 	"DewaSRY/sociomile-app/internal/handlers"
 	"DewaSRY/sociomile-app/internal/middleware"
+	"DewaSRY/sociomile-app/internal/services"
 	jwtUtils "DewaSRY/sociomile-app/pkg/lib/jwt"
 
 	"github.com/go-chi/chi/v5"
@@ -12,14 +13,23 @@ import (
 type AuthRouter struct{
 	JwtService  jwtUtils.JwtService
 	AuthHandler handlers.AuthHandler
+	MagicLinkHandler handlers.MagicLinkHandler
+	RateLimitService services.RateLimitService
 }
 
 func(t *AuthRouter) Register(r chi.Router) {
 	r.Route("/auth", func(r chi.Router) {
+		r.Use(middleware.AllowContentType("application/json"))
 		r.Post("/register", t.AuthHandler.Register)
 		r.Post("/login", t.AuthHandler.Login)
 		r.Post("/refresh", t.AuthHandler.RefreshToken)
 
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.RateLimit(t.RateLimitService, middleware.IPSubject("auth_magic_link")))
+			r.Post("/magic-link", t.MagicLinkHandler.RequestLink)
+			r.Post("/magic-link/verify", t.MagicLinkHandler.VerifyLink)
+		})
+
 		r.Group(func(r chi.Router) {
 			r.Use(middleware.JWTAuth(t.JwtService))
 			r.Get("/profile", t.AuthHandler.GetProfile)
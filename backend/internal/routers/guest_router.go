@@ -3,6 +3,7 @@ package routers
 import (
 	"DewaSRY/sociomile-app/internal/handlers"
 	"DewaSRY/sociomile-app/internal/middleware"
+	"DewaSRY/sociomile-app/internal/services"
 	jwtUtils "DewaSRY/sociomile-app/pkg/lib/jwt"
 
 	"github.com/go-chi/chi/v5"
@@ -12,21 +13,41 @@ type GuestRouter struct {
 	JwtService               jwtUtils.JwtService
 	GuestConversationHandler handlers.GuestConversationHandler
 	GuestMessageHandler      handlers.GuestMessageHandler
+	GuestTicketHandler       handlers.GuestTicketHandler
+	RateLimitService         services.RateLimitService
 }
 
 func (t *GuestRouter) Register(r chi.Router) {
 	r.Route("/guest", func(r chi.Router) {
 		r.Use(middleware.JWTAuth(t.JwtService))
+		r.Use(middleware.AllowContentType("application/json"))
 
 		r.Route("/conversations", func(r chi.Router) {
 			r.Get("/", t.GuestConversationHandler.GetConversation)
-			r.Post("/", t.GuestConversationHandler.CreateConversation)
+			r.With(middleware.RateLimit(
+				t.RateLimitService,
+				middleware.GuestSubject("guest_conversations", t.JwtService, t.RateLimitService),
+			)).Post("/", t.GuestConversationHandler.CreateConversation)
 
-			r.Post("/messages", t.GuestMessageHandler.SendConversationMessage)
+			r.With(middleware.RateLimit(
+				t.RateLimitService,
+				middleware.GuestSubject("guest_messages", t.JwtService, t.RateLimitService),
+			)).Post("/messages", t.GuestMessageHandler.SendConversationMessage)
 			r.Route("/{id}", func(r chi.Router) {
 				r.Get("/messages", t.GuestMessageHandler.GetConversationMessageList)
 			})
 		})
 
+		r.Route("/tickets", func(r chi.Router) {
+			r.Get("/", t.GuestTicketHandler.GetTickets)
+			r.Get("/{id}", t.GuestTicketHandler.GetTicket)
+		})
+
 	})
+
+	// status links are opened by customers without an account
+	r.With(middleware.RateLimit(
+		t.RateLimitService,
+		middleware.IPSubject("ticket_status"),
+	)).Get("/ticket-status/{token}", t.GuestTicketHandler.GetTicketStatus)
 }
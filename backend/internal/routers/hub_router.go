@@ -14,16 +14,27 @@ type HubRouter struct {
 	JwtService       jwtUtils.JwtService
 	AuthorizeService services.AuthorizeService
 	HubHandler       handlers.HubHandler
+
+	WebhookInboxHandler handlers.HubWebhookInboxHandler
+	RateLimitHandler    handlers.HubRateLimitHandler
 }
 
 func (t *HubRouter) Register(r chi.Router) {
 	r.Route("/hub", func(r chi.Router) {
 		r.Use(middleware.JWTAuth(t.JwtService))
+		r.Use(middleware.AllowContentType("application/json"))
 		r.Use(middleware.Authorize(t.JwtService, t.AuthorizeService, []string{
 			models.RoleSuperAdmin,
 		}))
 
 		r.Get("/organizations", t.HubHandler.GetOrganizationPagination)
 		r.Post("/organizations", t.HubHandler.CreateOrganization)
+
+		r.Get("/webhook-inbox", t.WebhookInboxHandler.GetInboxEvents)
+		r.Post("/webhook-inbox/{id}/retry", t.WebhookInboxHandler.RetryInboxEvent)
+
+		r.Get("/rate-limit-hits", t.RateLimitHandler.GetRateLimitHits)
+		r.Get("/organizations/{id}/rate-limits", t.RateLimitHandler.GetOrganizationRateLimits)
+		r.Put("/organizations/{id}/rate-limits", t.RateLimitHandler.UpdateOrganizationRateLimits)
 	})
 }
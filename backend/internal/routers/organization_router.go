@@ -18,6 +18,18 @@ type OrganizationRouter struct {
 	OrgStaffHandler        handlers.OrganizationStaffHandler
 	OrgTicketHandler       handlers.OrganizationTicketHandler
 	OrgConversationHandler handlers.OrganizationConversationHandler
+
+	OrgEventSubscriptionHandler handlers.OrganizationEventSubscriptionHandler
+	OrgContactHandler           handlers.OrganizationContactHandler
+	OrgContactAttributeHandler  handlers.OrganizationContactAttributeHandler
+	OrgWidgetHandler            handlers.OrganizationWidgetHandler
+	OrgWebhookHandler           handlers.OrganizationWebhookHandler
+	OrgTicketSLAHandler         handlers.OrganizationTicketSLAHandler
+	OrgBulkHandler              handlers.OrganizationBulkHandler
+	OrgSettingsHandler          handlers.OrganizationSettingsHandler
+	OrgTicketFieldHandler       handlers.OrganizationTicketFieldHandler
+	OrgTicketTimeHandler        handlers.OrganizationTicketTimeHandler
+	OrgTicketStatusHandler      handlers.OrganizationTicketStatusHandler
 }
 
 func (t *OrganizationRouter) Register(r chi.Router) {
@@ -25,39 +37,350 @@ func (t *OrganizationRouter) Register(r chi.Router) {
 	r.Route("/organizations", func(r chi.Router) {
 		r.Use(middleware.JWTAuth(t.JwtService))
 
-		r.Route("/staff", func(r chi.Router) {
-			r.With(middleware.Authorize(
+		// the import reads a file, every other route reads JSON
+		r.With(
+			middleware.Authorize(
 				t.JwtService,
 				t.AuthorizeService,
 				[]string{
 					models.RoleOrganizationOwner,
 				},
-			)).Post("/", t.OrgStaffHandler.CreateOrganizationStaff)
+			),
+			middleware.AllowContentType("text/csv", "multipart/form-data"),
+		).Post("/ticket/import", t.OrgTicketHandler.ImportTickets)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.AllowContentType("application/json"))
+
+			r.Route("/staff", func(r chi.Router) {
+				r.With(middleware.Authorize(
+					t.JwtService,
+					t.AuthorizeService,
+					[]string{
+						models.RoleOrganizationOwner,
+					},
+				)).Post("/", t.OrgStaffHandler.CreateOrganizationStaff)
+
+				r.With(middleware.Authorize(
+					t.JwtService,
+					t.AuthorizeService,
+					[]string{
+						models.RoleOrganizationOwner,
+						models.RoleOrganizationSales,
+					},
+				)).Get("/", t.OrgStaffHandler.GetStaffListPagination)
+			})
+
+			r.Route("/ticket", func(r chi.Router) {
+				r.Use(middleware.Authorize(
+					t.JwtService,
+					t.AuthorizeService,
+					[]string{
+						models.RoleOrganizationOwner,
+						models.RoleOrganizationSales,
+					},
+				))
+
+				r.Get("/", t.OrgTicketHandler.GetTicketsList)
+				r.Post("/", t.OrgTicketHandler.CreateTicket)
+				r.Get("/export", t.OrgTicketHandler.ExportTickets)
+				r.With(middleware.Authorize(
+					t.JwtService,
+					t.AuthorizeService,
+					[]string{
+						models.RoleOrganizationOwner,
+					},
+				)).Post("/bulk", t.OrgBulkHandler.BulkTickets)
+				r.Route("/{id}", func(r chi.Router) {
+					r.Get("/", t.OrgTicketHandler.GetTicket)
+					r.Put("/", t.OrgTicketHandler.UpdateTicket)
+					r.Get("/comments", t.OrgTicketHandler.GetComments)
+					r.Post("/comments", t.OrgTicketHandler.CreateComment)
+					r.Post("/links", t.OrgTicketHandler.CreateLink)
+					r.Delete("/links/{linkId}", t.OrgTicketHandler.DeleteLink)
+					r.Post("/conversations", t.OrgTicketHandler.AddConversation)
+					r.Delete("/conversations/{conversationId}", t.OrgTicketHandler.RemoveConversation)
+					r.Get("/time-entries", t.OrgTicketTimeHandler.GetEntries)
+					r.Post("/time-entries", t.OrgTicketTimeHandler.CreateEntry)
+					r.Delete("/time-entries/{entryId}", t.OrgTicketTimeHandler.DeleteEntry)
+					r.Post("/timer/start", t.OrgTicketTimeHandler.StartTimer)
+					r.Post("/timer/stop", t.OrgTicketTimeHandler.StopTimer)
+					r.Post("/status-link", t.OrgTicketStatusHandler.CreateStatusLink)
+				})
+			})
+
+			r.Route("/settings", func(r chi.Router) {
+				r.With(middleware.Authorize(
+					t.JwtService,
+					t.AuthorizeService,
+					[]string{
+						models.RoleOrganizationOwner,
+						models.RoleOrganizationSales,
+					},
+				)).Get("/", t.OrgSettingsHandler.GetSettings)
+				r.With(middleware.Authorize(
+					t.JwtService,
+					t.AuthorizeService,
+					[]string{
+						models.RoleOrganizationOwner,
+					},
+				)).Put("/", t.OrgSettingsHandler.UpdateSettings)
+			})
+
+			r.Route("/ticket-numbering", func(r chi.Router) {
+				r.Use(middleware.Authorize(
+					t.JwtService,
+					t.AuthorizeService,
+					[]string{
+						models.RoleOrganizationOwner,
+					},
+				))
+
+				r.Get("/", t.OrgTicketHandler.GetNumberFormat)
+				r.Put("/", t.OrgTicketHandler.UpdateNumberFormat)
+			})
+
+			r.Route("/ticket-workflow", func(r chi.Router) {
+				r.With(middleware.Authorize(
+					t.JwtService,
+					t.AuthorizeService,
+					[]string{
+						models.RoleOrganizationOwner,
+						models.RoleOrganizationSales,
+					},
+				)).Get("/", t.OrgTicketHandler.GetWorkflow)
+				r.With(middleware.Authorize(
+					t.JwtService,
+					t.AuthorizeService,
+					[]string{
+						models.RoleOrganizationOwner,
+					},
+				)).Put("/", t.OrgTicketHandler.UpdateWorkflow)
+			})
+
+			r.Route("/ticket-fields", func(r chi.Router) {
+				r.With(middleware.Authorize(
+					t.JwtService,
+					t.AuthorizeService,
+					[]string{
+						models.RoleOrganizationOwner,
+						models.RoleOrganizationSales,
+					},
+				)).Get("/", t.OrgTicketFieldHandler.GetFields)
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.Authorize(
+						t.JwtService,
+						t.AuthorizeService,
+						[]string{
+							models.RoleOrganizationOwner,
+						},
+					))
+
+					r.Post("/", t.OrgTicketFieldHandler.CreateField)
+					r.Put("/{id}", t.OrgTicketFieldHandler.UpdateField)
+					r.Delete("/{id}", t.OrgTicketFieldHandler.DeleteField)
+				})
+			})
+
+			r.Route("/ticket-sla-policies", func(r chi.Router) {
+				r.With(middleware.Authorize(
+					t.JwtService,
+					t.AuthorizeService,
+					[]string{
+						models.RoleOrganizationOwner,
+						models.RoleOrganizationSales,
+					},
+				)).Get("/", t.OrgTicketSLAHandler.GetPolicies)
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.Authorize(
+						t.JwtService,
+						t.AuthorizeService,
+						[]string{
+							models.RoleOrganizationOwner,
+						},
+					))
+
+					r.Post("/", t.OrgTicketSLAHandler.CreatePolicy)
+					r.Put("/{id}", t.OrgTicketSLAHandler.UpdatePolicy)
+					r.Delete("/{id}", t.OrgTicketSLAHandler.DeletePolicy)
+				})
+			})
 
 			r.With(middleware.Authorize(
 				t.JwtService,
 				t.AuthorizeService,
 				[]string{
 					models.RoleOrganizationOwner,
-					models.RoleOrganizationSales,
 				},
-			)).Get("/", t.OrgStaffHandler.GetStaffListPagination)
-		})
+			)).Get("/time-report", t.OrgTicketTimeHandler.GetReport)
 
-		r.Route("/ticket", func(r chi.Router) {
-			r.Get("/", t.OrgTicketHandler.GetTicketsList)
-			r.Post("/", t.OrgTicketHandler.CreateTicket)
-			r.Route("/{id}", func(r chi.Router) {
-				r.Put("/", t.OrgTicketHandler.UpdateTicket)
+			r.Route("/bulk-jobs", func(r chi.Router) {
+				r.Use(middleware.Authorize(
+					t.JwtService,
+					t.AuthorizeService,
+					[]string{
+						models.RoleOrganizationOwner,
+					},
+				))
+
+				r.Get("/{id}", t.OrgBulkHandler.GetJob)
 			})
-		})
 
-		r.Route("/conversations", func(r chi.Router) {
-			r.Get("/", t.OrgConversationHandler.GetConversationsList)
-			r.Route("/{id}", func(r chi.Router) {
-				r.Get("/", t.OrgConversationHandler.GetConversationByID)
-				r.Put("/assign", t.OrgConversationHandler.AssignConversation)
-				r.Put("/status", t.OrgConversationHandler.UpdateConversationStatus)
+			r.Route("/conversations", func(r chi.Router) {
+				r.Use(middleware.Authorize(
+					t.JwtService,
+					t.AuthorizeService,
+					[]string{
+						models.RoleOrganizationOwner,
+						models.RoleOrganizationSales,
+					},
+				))
+
+				r.Get("/", t.OrgConversationHandler.GetConversationsList)
+				r.With(middleware.Authorize(
+					t.JwtService,
+					t.AuthorizeService,
+					[]string{
+						models.RoleOrganizationOwner,
+					},
+				)).Post("/bulk", t.OrgBulkHandler.BulkConversations)
+				r.Route("/{id}", func(r chi.Router) {
+					r.Get("/", t.OrgConversationHandler.GetConversationByID)
+					r.Put("/assign", t.OrgConversationHandler.AssignConversation)
+					r.Put("/status", t.OrgConversationHandler.UpdateConversationStatus)
+					r.Post("/messages", t.OrgConversationHandler.SendMessage)
+					r.Post("/messages/{messageId}/retry", t.OrgConversationHandler.RetryMessageDelivery)
+					r.Get("/attachments/{attachmentId}", t.OrgConversationHandler.DownloadAttachment)
+				})
+			})
+
+			r.Route("/contacts", func(r chi.Router) {
+				r.Use(middleware.Authorize(
+					t.JwtService,
+					t.AuthorizeService,
+					[]string{
+						models.RoleOrganizationOwner,
+						models.RoleOrganizationSales,
+					},
+				))
+
+				r.Get("/", t.OrgContactHandler.GetContacts)
+				r.Post("/", t.OrgContactHandler.CreateContact)
+
+				// a merge moves every conversation of the contact, whoever
+				// they are assigned to
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.Authorize(
+						t.JwtService,
+						t.AuthorizeService,
+						[]string{
+							models.RoleOrganizationOwner,
+						},
+					))
+
+					r.Get("/merge-suggestions", t.OrgContactHandler.GetMergeSuggestions)
+					r.Post("/merges/{mergeId}/undo", t.OrgContactHandler.UndoMerge)
+				})
+
+				r.Route("/{id}", func(r chi.Router) {
+					r.Get("/", t.OrgContactHandler.GetContactByID)
+					r.Put("/", t.OrgContactHandler.UpdateContact)
+					r.Post("/identities", t.OrgContactHandler.AddIdentity)
+					r.Delete("/identities/{identityId}", t.OrgContactHandler.RemoveIdentity)
+					r.Put("/user", t.OrgContactHandler.LinkUser)
+					r.Delete("/user", t.OrgContactHandler.UnlinkUser)
+					r.With(middleware.Authorize(
+						t.JwtService,
+						t.AuthorizeService,
+						[]string{
+							models.RoleOrganizationOwner,
+						},
+					)).Post("/merge", t.OrgContactHandler.MergeContact)
+					r.With(middleware.Authorize(
+						t.JwtService,
+						t.AuthorizeService,
+						[]string{
+							models.RoleOrganizationOwner,
+						},
+					)).Get("/merges", t.OrgContactHandler.GetContactMerges)
+					r.Put("/attributes", t.OrgContactHandler.SetAttributes)
+					r.Get("/notes", t.OrgContactHandler.GetNotes)
+					r.Post("/notes", t.OrgContactHandler.CreateNote)
+					r.Put("/notes/{noteId}", t.OrgContactHandler.UpdateNote)
+					r.Delete("/notes/{noteId}", t.OrgContactHandler.DeleteNote)
+					r.Get("/timeline", t.OrgContactHandler.GetTimeline)
+				})
+			})
+
+			r.Route("/contact-attributes", func(r chi.Router) {
+				r.With(middleware.Authorize(
+					t.JwtService,
+					t.AuthorizeService,
+					[]string{
+						models.RoleOrganizationOwner,
+						models.RoleOrganizationSales,
+					},
+				)).Get("/", t.OrgContactAttributeHandler.GetAttributes)
+
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.Authorize(
+						t.JwtService,
+						t.AuthorizeService,
+						[]string{
+							models.RoleOrganizationOwner,
+						},
+					))
+
+					r.Post("/", t.OrgContactAttributeHandler.CreateAttribute)
+					r.Put("/{id}", t.OrgContactAttributeHandler.UpdateAttribute)
+					r.Delete("/{id}", t.OrgContactAttributeHandler.DeleteAttribute)
+				})
+			})
+
+			r.Route("/widget", func(r chi.Router) {
+				r.Use(middleware.Authorize(
+					t.JwtService,
+					t.AuthorizeService,
+					[]string{
+						models.RoleOrganizationOwner,
+					},
+				))
+
+				r.Get("/", t.OrgWidgetHandler.GetSettings)
+				r.Put("/", t.OrgWidgetHandler.UpdateSettings)
+			})
+
+			r.Route("/webhook", func(r chi.Router) {
+				r.Use(middleware.Authorize(
+					t.JwtService,
+					t.AuthorizeService,
+					[]string{
+						models.RoleOrganizationOwner,
+					},
+				))
+
+				r.Get("/", t.OrgWebhookHandler.GetSettings)
+				r.Put("/", t.OrgWebhookHandler.UpdateSettings)
+				r.Post("/secret", t.OrgWebhookHandler.RotateSecret)
+			})
+
+			r.Route("/event-subscriptions", func(r chi.Router) {
+				r.Use(middleware.Authorize(
+					t.JwtService,
+					t.AuthorizeService,
+					[]string{
+						models.RoleOrganizationOwner,
+					},
+				))
+
+				r.Get("/", t.OrgEventSubscriptionHandler.GetSubscriptions)
+				r.Post("/", t.OrgEventSubscriptionHandler.CreateSubscription)
+				r.Route("/{id}", func(r chi.Router) {
+					r.Put("/", t.OrgEventSubscriptionHandler.UpdateSubscription)
+					r.Delete("/", t.OrgEventSubscriptionHandler.DeleteSubscription)
+					r.Get("/deliveries", t.OrgEventSubscriptionHandler.GetDeliveries)
+					r.Post("/deliveries/{deliveryId}/replay", t.OrgEventSubscriptionHandler.ReplayDelivery)
+				})
 			})
 		})
 	})
@@ -2,16 +2,34 @@ package routers
 
 import (
 	"DewaSRY/sociomile-app/internal/handlers"
+	"DewaSRY/sociomile-app/internal/middleware"
+	"DewaSRY/sociomile-app/internal/services"
 
 	"github.com/go-chi/chi/v5"
 )
 
 type WebHook struct {
 	WebHookHandler       handlers.WebHookHandler
+	RateLimitService     services.RateLimitService
+	WebHookService       services.WebHookConversationService
 }
 
 func (t *WebHook) Register(r chi.Router) {
 	r.Route("/webhooks", func(r chi.Router) {
-		r.Post("/conversations", t.WebHookHandler.CreateConversation)
+		r.Use(middleware.AllowContentType("application/json"))
+		// the address is limited before the signature is checked, the
+		// organization and the customer once the request is verified
+		r.With(
+			middleware.RateLimit(
+				t.RateLimitService,
+				middleware.IPSubject("webhook_conversations"),
+			),
+			middleware.WebhookSignature(t.WebHookService),
+			middleware.RateLimit(
+				t.RateLimitService,
+				middleware.WebhookSubject("webhook_conversations"),
+			),
+		).Post("/conversations", t.WebHookHandler.CreateConversation)
+		r.Post("/deliveries/receipt", t.WebHookHandler.DeliveryReceipt)
 	})
 }
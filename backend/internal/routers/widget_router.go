@@ -0,0 +1,47 @@
+package routers
+
+import (
+	"DewaSRY/sociomile-app/internal/handlers"
+	"DewaSRY/sociomile-app/internal/middleware"
+	"DewaSRY/sociomile-app/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type WidgetRouter struct {
+	WidgetService    services.WidgetService
+	WidgetHandler    handlers.WidgetHandler
+	RateLimitService services.RateLimitService
+}
+
+func (t *WidgetRouter) Register(r chi.Router) {
+	r.Route("/widget/{orgSlug}", func(r chi.Router) {
+		r.Use(middleware.WidgetCORS(t.WidgetService))
+		r.Use(middleware.AllowContentType("application/json"))
+
+		r.With(middleware.RateLimit(
+			t.RateLimitService,
+			middleware.IPSubject("widget_session"),
+		)).Post("/session", t.WidgetHandler.StartSession)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.WidgetAuth(t.WidgetService))
+
+			r.Post("/session/identify", t.WidgetHandler.Identify)
+			r.Route("/conversations", func(r chi.Router) {
+				r.Get("/", t.WidgetHandler.GetConversations)
+				r.With(middleware.RateLimit(
+					t.RateLimitService,
+					middleware.WidgetSubject("widget_conversations"),
+				)).Post("/", t.WidgetHandler.CreateConversation)
+				r.Route("/{id}", func(r chi.Router) {
+					r.Get("/messages", t.WidgetHandler.GetMessages)
+					r.With(middleware.RateLimit(
+						t.RateLimitService,
+						middleware.WidgetSubject("widget_messages"),
+					)).Post("/messages", t.WidgetHandler.SendMessage)
+				})
+			})
+		})
+	})
+}
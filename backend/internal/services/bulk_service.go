@@ -0,0 +1,17 @@
+package services
+
+import (
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+)
+
+type BulkService interface {
+	BulkTickets(user *jwtLib.Claims, req requestdto.BulkTicketRequest) (*responsedto.BulkJobResponse, error)
+	BulkConversations(user *jwtLib.Claims, req requestdto.BulkConversationRequest) (*responsedto.BulkJobResponse, error)
+	GetJob(user *jwtLib.Claims, jobID uint) (*responsedto.BulkJobResponse, error)
+
+	// ProcessPendingJobs runs the queued jobs and returns how many it
+	// finished.
+	ProcessPendingJobs(limit int) (int, error)
+}
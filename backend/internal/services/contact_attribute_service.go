@@ -0,0 +1,14 @@
+package services
+
+import (
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/lib/jwt"
+)
+
+type ContactAttributeService interface {
+	GetAttributes(user *jwt.Claims) ([]responsedto.ContactAttributeResponse, error)
+	CreateAttribute(user *jwt.Claims, req requestdto.CreateContactAttributeRequest) (*responsedto.ContactAttributeResponse, error)
+	UpdateAttribute(user *jwt.Claims, attributeID uint, req requestdto.UpdateContactAttributeRequest) (*responsedto.ContactAttributeResponse, error)
+	DeleteAttribute(user *jwt.Claims, attributeID uint) error
+}
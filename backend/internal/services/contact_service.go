@@ -0,0 +1,32 @@
+package services
+
+import (
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/lib/jwt"
+)
+
+type ContactService interface {
+	GetContacts(user *jwt.Claims, filter filtersdto.FiltersDto, search string) (*responsedto.ContactPaginateResponse, error)
+	GetContactByID(user *jwt.Claims, contactID uint) (*responsedto.ContactResponse, error)
+	CreateContact(user *jwt.Claims, req requestdto.CreateContactRequest) (*responsedto.ContactResponse, error)
+	UpdateContact(user *jwt.Claims, contactID uint, req requestdto.UpdateContactRequest) (*responsedto.ContactResponse, error)
+
+	AddIdentity(user *jwt.Claims, contactID uint, req requestdto.ContactIdentityRequest) (*responsedto.ContactResponse, error)
+	RemoveIdentity(user *jwt.Claims, contactID uint, identityID uint) error
+	LinkUser(user *jwt.Claims, contactID uint, req requestdto.LinkContactUserRequest) (*responsedto.ContactResponse, error)
+	UnlinkUser(user *jwt.Claims, contactID uint) error
+	SetAttributes(user *jwt.Claims, contactID uint, req requestdto.SetContactAttributesRequest) (*responsedto.ContactResponse, error)
+
+	GetNotes(user *jwt.Claims, contactID uint, filter filtersdto.FiltersDto) (*responsedto.ContactNotePaginateResponse, error)
+	CreateNote(user *jwt.Claims, contactID uint, req requestdto.ContactNoteRequest) (*responsedto.ContactNoteResponse, error)
+	UpdateNote(user *jwt.Claims, contactID uint, noteID uint, req requestdto.ContactNoteRequest) (*responsedto.ContactNoteResponse, error)
+	DeleteNote(user *jwt.Claims, contactID uint, noteID uint) error
+	GetTimeline(user *jwt.Claims, contactID uint, cursor string, limit int) (*responsedto.ContactTimelineResponse, error)
+
+	GetMergeSuggestions(user *jwt.Claims, filter filtersdto.FiltersDto) (*responsedto.ContactMergeSuggestionPaginateResponse, error)
+	MergeContact(user *jwt.Claims, contactID uint, req requestdto.MergeContactRequest) (*responsedto.ContactMergeResponse, error)
+	GetContactMerges(user *jwt.Claims, contactID uint) ([]responsedto.ContactMergeResponse, error)
+	UndoMerge(user *jwt.Claims, mergeID uint) (*responsedto.ContactMergeResponse, error)
+}
@@ -0,0 +1,8 @@
+package services
+
+import "DewaSRY/sociomile-app/pkg/lib/mail"
+
+type EmailConversationService interface {
+	mail.InboundHandler
+	ProcessInboundEmail(recipient string, inbound *mail.InboundMail) error
+}
@@ -0,0 +1,19 @@
+package services
+
+import (
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/lib/jwt"
+)
+
+type EventSubscriptionService interface {
+	CreateSubscription(user *jwt.Claims, req requestdto.CreateEventSubscriptionRequest) (*responsedto.EventSubscriptionResponse, error)
+	GetSubscriptions(user *jwt.Claims) (*responsedto.EventSubscriptionListResponse, error)
+	UpdateSubscription(user *jwt.Claims, subscriptionID uint, req requestdto.UpdateEventSubscriptionRequest) (*responsedto.EventSubscriptionResponse, error)
+	DeleteSubscription(user *jwt.Claims, subscriptionID uint) error
+
+	GetDeliveries(user *jwt.Claims, subscriptionID uint, filter filtersdto.FiltersDto) (*responsedto.EventDeliveryPaginateResponse, error)
+	ReplayDelivery(user *jwt.Claims, subscriptionID uint, deliveryID uint) (*responsedto.EventDeliveryResponse, error)
+	ProcessDueDeliveries(limit int) (int, error)
+}
@@ -60,7 +60,7 @@ func (t *authServiceImpl) Register(req requestdto.RegisterRequest) (*responsedto
 
 	return &responsedto.AuthResponse{
 		Token: token,
-		User:  t.mappedToUserProfile(&user),
+		User:  mapToUserProfile(&user),
 	}, nil
 }
 
@@ -92,7 +92,7 @@ func (t *authServiceImpl) Login(req requestdto.LoginRequest) (*responsedto.AuthR
 
 	return &responsedto.AuthResponse{
 		Token: token,
-		User:  t.mappedToUserProfile(&user),
+		User:  mapToUserProfile(&user),
 	}, nil
 }
 
@@ -112,11 +112,11 @@ func (t *authServiceImpl) GetUserByID(userID uint) (*responsedto.UserProfileData
 		return nil, errors.New("failed to load user relations")
 	}
 
-	result := t.mappedToUserProfile(&user)
+	result := mapToUserProfile(&user)
 	return &result, nil
 }
 
-func (t *authServiceImpl) mappedToUserProfile(user *models.UserModel) responsedto.UserProfileData {
+func mapToUserProfile(user *models.UserModel) responsedto.UserProfileData {
 	result := responsedto.UserProfileData{
 		ID:       user.ID,
 		Email:    user.Email,
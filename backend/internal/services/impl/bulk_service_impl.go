@@ -0,0 +1,485 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrBulkRequestInvalid = errors.New("invalid bulk request")
+	ErrBulkTooManyItems   = errors.New("too many items for one bulk request")
+	ErrBulkJobNotFound    = errors.New("bulk job not found")
+)
+
+// bulkJobStaleAfter is how long a running job may go without a heartbeat
+// before another worker takes it over, e.g. after a restart.
+const bulkJobStaleAfter = 10 * time.Minute
+
+// BulkOptions configure bulk jobs. Jobs up to SyncLimit items run during
+// the request, larger ones are queued for the worker. MaxItems caps what
+// one request may select.
+type BulkOptions struct {
+	SyncLimit int
+	ChunkSize int
+	MaxItems  int
+}
+
+type bulkServiceImpl struct {
+	db      *gorm.DB
+	options BulkOptions
+}
+
+// BulkTickets implements services.BulkService.
+func (t *bulkServiceImpl) BulkTickets(user *jwtLib.Claims, req requestdto.BulkTicketRequest) (*responsedto.BulkJobResponse, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+	if (len(req.IDs) == 0) == (req.Filter == nil) {
+		return nil, fmt.Errorf("%w: give either ids or a filter", ErrBulkRequestInvalid)
+	}
+
+	payload := models.BulkJobPayload{
+		Status:         req.Status,
+		AssigneeID:     req.AssigneeID,
+		ResolutionNote: req.ResolutionNote,
+	}
+	switch req.Action {
+	case models.BulkActionStatus:
+		if req.Status == "" {
+			return nil, fmt.Errorf("%w: status is required", ErrBulkRequestInvalid)
+		}
+	case models.BulkActionAssign:
+		if req.AssigneeID == nil {
+			return nil, fmt.Errorf("%w: assigneeId is required", ErrBulkRequestInvalid)
+		}
+		if *req.AssigneeID != 0 {
+			if err := validateTicketAssignee(t.db, *user.OrganizationId, *req.AssigneeID); err != nil {
+				return nil, err
+			}
+		}
+	case models.BulkActionAddTag, models.BulkActionRemoveTag:
+		tag, err := normalizeTag(req.Tag)
+		if err != nil {
+			return nil, err
+		}
+		payload.Tag = tag
+	}
+
+	var ids []uint
+	if req.Filter != nil {
+		query, err := filterTickets(t.db, *user.OrganizationId, *req.Filter, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if err := query.Order("tickets.id ASC").
+			Limit(t.options.MaxItems+1).
+			Pluck("tickets.id", &ids).Error; err != nil {
+			return nil, errors.New("failed to fetch tickets")
+		}
+	} else {
+		ids = uniqueIDs(req.IDs)
+	}
+
+	return t.submit(user, models.TagSubjectTicket, req.Action, payload, ids)
+}
+
+// BulkConversations implements services.BulkService.
+func (t *bulkServiceImpl) BulkConversations(user *jwtLib.Claims, req requestdto.BulkConversationRequest) (*responsedto.BulkJobResponse, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+	if (len(req.IDs) == 0) == (req.Filter == nil) {
+		return nil, fmt.Errorf("%w: give either ids or a filter", ErrBulkRequestInvalid)
+	}
+
+	payload := models.BulkJobPayload{Status: req.Status, AssigneeID: req.OrganizationStaffID}
+	switch req.Action {
+	case models.BulkActionStatus:
+		if req.Status == "" {
+			return nil, fmt.Errorf("%w: status is required", ErrBulkRequestInvalid)
+		}
+	case models.BulkActionAssign:
+		if req.OrganizationStaffID == nil || *req.OrganizationStaffID == 0 {
+			return nil, fmt.Errorf("%w: organizationStaffId is required", ErrBulkRequestInvalid)
+		}
+		if err := validateTicketAssignee(t.db, *user.OrganizationId, *req.OrganizationStaffID); err != nil {
+			return nil, err
+		}
+	case models.BulkActionAddTag, models.BulkActionRemoveTag:
+		tag, err := normalizeTag(req.Tag)
+		if err != nil {
+			return nil, err
+		}
+		payload.Tag = tag
+	}
+
+	var ids []uint
+	if req.Filter != nil {
+		query := t.db.Model(&models.ConversationModel{}).
+			Scopes(organizationScope(user))
+		if req.Filter.Status != "" {
+			query = query.Where("status = ?", req.Filter.Status)
+		}
+		if req.Filter.Channel != "" {
+			query = query.Where("channel = ?", req.Filter.Channel)
+		}
+		if req.Filter.OrganizationStaffID != nil {
+			query = query.Where("organization_staff_id = ?", *req.Filter.OrganizationStaffID)
+		}
+		if req.Filter.Unassigned {
+			query = query.Where("organization_staff_id IS NULL")
+		}
+		if req.Filter.Tag != "" {
+			query = query.Where("id IN (?)", taggedWith(t.db, models.TagSubjectConversation, req.Filter.Tag))
+		}
+		if err := query.Order("id ASC").
+			Limit(t.options.MaxItems+1).
+			Pluck("id", &ids).Error; err != nil {
+			return nil, errors.New("failed to fetch conversations")
+		}
+	} else {
+		ids = uniqueIDs(req.IDs)
+	}
+
+	return t.submit(user, models.TagSubjectConversation, req.Action, payload, ids)
+}
+
+// GetJob implements services.BulkService.
+func (t *bulkServiceImpl) GetJob(user *jwtLib.Claims, jobID uint) (*responsedto.BulkJobResponse, error) {
+	var job models.BulkJobModel
+	if err := t.db.Scopes(organizationScope(user)).
+		First(&job, jobID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBulkJobNotFound
+		}
+		return nil, errors.New("failed to fetch bulk job")
+	}
+	return t.mapToJobResponse(&job), nil
+}
+
+// ProcessPendingJobs implements services.BulkService. A job is claimed in
+// its own transaction so several workers never run the same job.
+func (t *bulkServiceImpl) ProcessPendingJobs(limit int) (int, error) {
+	finished := 0
+	for i := 0; i < limit; i++ {
+		var job models.BulkJobModel
+		err := t.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where("status = ? OR (status = ? AND heartbeat_at < ?)", models.BulkJobPending, models.BulkJobRunning, time.Now().Add(-bulkJobStaleAfter)).
+				Order("id ASC").
+				First(&job).Error; err != nil {
+				return err
+			}
+
+			now := time.Now()
+			job.Status = models.BulkJobRunning
+			if job.StartedAt == nil {
+				job.StartedAt = &now
+			}
+			job.HeartbeatAt = &now
+			return tx.Model(&job).Select("status", "started_at", "heartbeat_at", "updated_at").Updates(&job).Error
+		})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			break
+		}
+		if err != nil {
+			return finished, errors.New("failed to claim bulk job")
+		}
+
+		t.run(&job)
+		finished++
+	}
+	return finished, nil
+}
+
+// submit stores the job for the items, which must belong to the caller's
+// organization, and runs it right away when it is small.
+func (t *bulkServiceImpl) submit(user *jwtLib.Claims, subjectType string, action string, payload models.BulkJobPayload, ids []uint) (*responsedto.BulkJobResponse, error) {
+	if len(ids) > t.options.MaxItems {
+		return nil, fmt.Errorf("%w: at most %d", ErrBulkTooManyItems, t.options.MaxItems)
+	}
+
+	job := models.BulkJobModel{
+		OrganizationID: *user.OrganizationId,
+		CreatedByID:    user.UserID,
+		SubjectType:    subjectType,
+		Action:         action,
+		Payload:        payload,
+		ItemIDs:        ids,
+		Status:         models.BulkJobPending,
+		Total:          len(ids),
+		Results:        []models.BulkJobResult{},
+	}
+
+	sync := len(ids) <= t.options.SyncLimit
+	if sync {
+		now := time.Now()
+		job.Status = models.BulkJobRunning
+		job.StartedAt = &now
+		job.HeartbeatAt = &now
+	}
+	if err := t.db.Create(&job).Error; err != nil {
+		return nil, errors.New("failed to create bulk job")
+	}
+
+	if sync {
+		t.run(&job)
+	}
+
+	return t.mapToJobResponse(&job), nil
+}
+
+// run handles the items of the job from where it stopped, a chunk at a
+// time. Each chunk is one transaction and every item a savepoint in it, so
+// a failing item is reported without undoing the others.
+func (t *bulkServiceImpl) run(job *models.BulkJobModel) {
+	var creator models.UserModel
+	if err := t.db.First(&creator, job.CreatedByID).Error; err != nil {
+		t.fail(job, errors.New("the user who started the job no longer exists"))
+		return
+	}
+	claims := &jwtLib.Claims{
+		UserID:         creator.ID,
+		Email:          creator.Email,
+		RoleID:         creator.RoleID,
+		OrganizationId: &job.OrganizationID,
+	}
+
+	chunkSize := t.options.ChunkSize
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
+
+	for job.Processed < len(job.ItemIDs) {
+		end := job.Processed + chunkSize
+		if end > len(job.ItemIDs) {
+			end = len(job.ItemIDs)
+		}
+
+		progress := *job
+		err := t.db.Transaction(func(tx *gorm.DB) error {
+			for _, id := range job.ItemIDs[job.Processed:end] {
+				result := models.BulkJobResult{ID: id}
+				if err := tx.Transaction(func(item *gorm.DB) error {
+					return t.apply(item, claims, job, id)
+				}); err != nil {
+					message := err.Error()
+					result.Error = &message
+					progress.Failed++
+				} else {
+					progress.Succeeded++
+				}
+				progress.Results = append(progress.Results, result)
+			}
+
+			now := time.Now()
+			progress.Processed = end
+			progress.HeartbeatAt = &now
+			return tx.Model(&progress).
+				Select("processed", "succeeded", "failed", "results", "heartbeat_at", "updated_at").
+				Updates(&progress).Error
+		})
+		if err != nil {
+			t.fail(job, err)
+			return
+		}
+		*job = progress
+	}
+
+	now := time.Now()
+	job.Status = models.BulkJobCompleted
+	job.FinishedAt = &now
+	if err := t.db.Model(job).Select("status", "finished_at").Updates(job).Error; err != nil {
+		logger.ErrorLog("Failed to complete bulk job", map[string]any{
+			"jobId": job.ID,
+			"error": err.Error(),
+		})
+	}
+}
+
+func (t *bulkServiceImpl) fail(job *models.BulkJobModel, cause error) {
+	now := time.Now()
+	message := cause.Error()
+	job.Status = models.BulkJobFailed
+	job.Error = &message
+	job.FinishedAt = &now
+
+	logger.ErrorLog("Bulk job failed", map[string]any{
+		"jobId": job.ID,
+		"error": message,
+	})
+	if err := t.db.Model(job).Select("status", "error", "finished_at").Updates(job).Error; err != nil {
+		logger.ErrorLog("Failed to record bulk job failure", map[string]any{
+			"jobId": job.ID,
+			"error": err.Error(),
+		})
+	}
+}
+
+// apply runs the action of the job on one item with the services the
+// single item endpoints use, bound to the item's savepoint.
+func (t *bulkServiceImpl) apply(tx *gorm.DB, claims *jwtLib.Claims, job *models.BulkJobModel, id uint) error {
+	if job.SubjectType == models.TagSubjectConversation {
+		return t.applyConversation(tx, claims, job, id)
+	}
+	return t.applyTicket(tx, claims, job, id)
+}
+
+func (t *bulkServiceImpl) applyTicket(tx *gorm.DB, claims *jwtLib.Claims, job *models.BulkJobModel, id uint) error {
+	var ticket models.TicketModel
+	if err := tx.Where("organization_id = ?", job.OrganizationID).First(&ticket, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTicketNotFound
+		}
+		return errors.New("failed to fetch ticket")
+	}
+
+	tickets := &OrganizationTicketServiceImpl{db: tx}
+	payload := job.Payload
+
+	switch job.Action {
+	case models.BulkActionStatus:
+		return tickets.UpdateTicket(claims, ticket.ID, requestdto.UpdateTicketRequest{
+			Status:         payload.Status,
+			ResolutionNote: payload.ResolutionNote,
+		})
+
+	case models.BulkActionAssign:
+		return tickets.UpdateTicket(claims, ticket.ID, requestdto.UpdateTicketRequest{AssigneeID: payload.AssigneeID})
+
+	case models.BulkActionClose:
+		if ticket.StatusCategory == models.TicketStatusCategoryClosed {
+			return nil
+		}
+		workflow, err := loadTicketWorkflow(tx, ticket.OrganizationID)
+		if err != nil {
+			return err
+		}
+		for _, status := range workflow.Statuses {
+			if status.Category == models.TicketStatusCategoryClosed && workflowAllows(workflow, ticket.Status, status.Key) {
+				return tickets.UpdateTicket(claims, ticket.ID, requestdto.UpdateTicketRequest{
+					Status:         status.Key,
+					ResolutionNote: payload.ResolutionNote,
+				})
+			}
+		}
+		return fmt.Errorf("%w: no closed status can be reached from %s", ErrTicketTransitionNotAllowed, ticket.Status)
+
+	case models.BulkActionAddTag, models.BulkActionRemoveTag:
+		field := "tag"
+		activity := &models.TicketActivityModel{
+			OrganizationID: ticket.OrganizationID,
+			TicketID:       ticket.ID,
+			ActorID:        &claims.UserID,
+			Action:         models.TicketActivityUpdated,
+			Field:          &field,
+		}
+
+		var changed bool
+		var err error
+		if job.Action == models.BulkActionAddTag {
+			changed, err = addTag(tx, ticket.OrganizationID, models.TagSubjectTicket, ticket.ID, payload.Tag)
+			activity.NewValue = &payload.Tag
+		} else {
+			changed, err = removeTag(tx, models.TagSubjectTicket, ticket.ID, payload.Tag)
+			activity.OldValue = &payload.Tag
+		}
+		if err != nil || !changed {
+			return err
+		}
+		return recordTicketActivity(tx, activity)
+	}
+
+	return fmt.Errorf("%w: unknown action %s", ErrBulkRequestInvalid, job.Action)
+}
+
+func (t *bulkServiceImpl) applyConversation(tx *gorm.DB, claims *jwtLib.Claims, job *models.BulkJobModel, id uint) error {
+	var conversation models.ConversationModel
+	if err := tx.Where("organization_id = ?", job.OrganizationID).First(&conversation, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("conversation not found")
+		}
+		return errors.New("failed to fetch conversation")
+	}
+
+	conversations := &organizationConversationServiceImpl{db: tx}
+	payload := job.Payload
+
+	switch job.Action {
+	case models.BulkActionStatus:
+		return conversations.UpdateConversationStatus(claims, conversation.ID, requestdto.UpdateConversationRequest{Status: payload.Status})
+
+	case models.BulkActionClose:
+		return conversations.UpdateConversationStatus(claims, conversation.ID, requestdto.UpdateConversationRequest{Status: models.ConversationStatusDone})
+
+	case models.BulkActionAssign:
+		_, err := conversations.AssignConversation(claims, conversation.ID, requestdto.AssignConversationRequest{OrganizationStaffID: *payload.AssigneeID})
+		return err
+
+	case models.BulkActionAddTag:
+		_, err := addTag(tx, conversation.OrganizationID, models.TagSubjectConversation, conversation.ID, payload.Tag)
+		return err
+
+	case models.BulkActionRemoveTag:
+		_, err := removeTag(tx, models.TagSubjectConversation, conversation.ID, payload.Tag)
+		return err
+	}
+
+	return fmt.Errorf("%w: unknown action %s", ErrBulkRequestInvalid, job.Action)
+}
+
+func (t *bulkServiceImpl) mapToJobResponse(job *models.BulkJobModel) *responsedto.BulkJobResponse {
+	response := &responsedto.BulkJobResponse{
+		ID:          job.ID,
+		SubjectType: job.SubjectType,
+		Action:      job.Action,
+		Status:      job.Status,
+		Total:       job.Total,
+		Processed:   job.Processed,
+		Succeeded:   job.Succeeded,
+		Failed:      job.Failed,
+		Results:     make([]responsedto.BulkJobResultResponse, 0, len(job.Results)),
+		Error:       job.Error,
+		CreatedAt:   job.CreatedAt,
+		StartedAt:   job.StartedAt,
+		FinishedAt:  job.FinishedAt,
+	}
+	for _, result := range job.Results {
+		response.Results = append(response.Results, responsedto.BulkJobResultResponse{
+			ID:      result.ID,
+			Success: result.Error == nil,
+			Error:   result.Error,
+		})
+	}
+	return response
+}
+
+// uniqueIDs drops repeated ids and keeps the order of the first ones.
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func NewBulkService(db *gorm.DB, options BulkOptions) services.BulkService {
+	return &bulkServiceImpl{
+		db:      db,
+		options: options,
+	}
+}
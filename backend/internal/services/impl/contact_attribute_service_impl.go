@@ -0,0 +1,200 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrContactAttributeNotFound    = errors.New("contact attribute not found")
+	ErrContactAttributeKeyInvalid  = errors.New("attribute key must start with a letter and contain only lowercase letters, digits and underscores")
+	ErrContactAttributeKeyTaken    = errors.New("attribute key already exists")
+	ErrContactAttributeNoOptions   = errors.New("enum attribute needs at least one option")
+	ErrContactAttributeOptionInUse = errors.New("option is still set on contacts")
+)
+
+var contactAttributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+type contactAttributeServiceImpl struct {
+	db *gorm.DB
+}
+
+// GetAttributes implements services.ContactAttributeService.
+func (t *contactAttributeServiceImpl) GetAttributes(user *jwt.Claims) ([]responsedto.ContactAttributeResponse, error) {
+	var definitions []models.ContactAttributeDefinitionModel
+	if err := t.db.Scopes(organizationScope(user)).
+		Order("id ASC").
+		Find(&definitions).Error; err != nil {
+		return nil, errors.New("failed to fetch contact attributes")
+	}
+
+	responses := make([]responsedto.ContactAttributeResponse, 0, len(definitions))
+	for i := range definitions {
+		responses = append(responses, *t.mapToContactAttributeResponse(&definitions[i]))
+	}
+
+	return responses, nil
+}
+
+// CreateAttribute implements services.ContactAttributeService.
+func (t *contactAttributeServiceImpl) CreateAttribute(user *jwt.Claims, req requestdto.CreateContactAttributeRequest) (*responsedto.ContactAttributeResponse, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+	if !contactAttributeKeyPattern.MatchString(req.Key) {
+		return nil, ErrContactAttributeKeyInvalid
+	}
+
+	definition := models.ContactAttributeDefinitionModel{
+		OrganizationID: *user.OrganizationId,
+		Key:            req.Key,
+		Label:          req.Label,
+		Type:           req.Type,
+	}
+	if req.Type == models.ContactAttributeTypeEnum {
+		definition.Options = normalizeContactAttributeOptions(req.Options)
+		if len(definition.Options) == 0 {
+			return nil, ErrContactAttributeNoOptions
+		}
+	}
+
+	var count int64
+	if err := t.db.Model(&models.ContactAttributeDefinitionModel{}).
+		Where("organization_id = ? AND `key` = ?", definition.OrganizationID, definition.Key).
+		Count(&count).Error; err != nil {
+		return nil, errors.New("failed to check contact attribute")
+	}
+	if count > 0 {
+		return nil, ErrContactAttributeKeyTaken
+	}
+
+	if err := t.db.Create(&definition).Error; err != nil {
+		return nil, errors.New("failed to create contact attribute")
+	}
+
+	return t.mapToContactAttributeResponse(&definition), nil
+}
+
+// UpdateAttribute implements services.ContactAttributeService.
+func (t *contactAttributeServiceImpl) UpdateAttribute(user *jwt.Claims, attributeID uint, req requestdto.UpdateContactAttributeRequest) (*responsedto.ContactAttributeResponse, error) {
+	definition, err := t.findAttribute(user, attributeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Label != "" {
+		definition.Label = req.Label
+	}
+
+	if definition.Type == models.ContactAttributeTypeEnum && req.Options != nil {
+		options := normalizeContactAttributeOptions(req.Options)
+		if len(options) == 0 {
+			return nil, ErrContactAttributeNoOptions
+		}
+
+		var removed []string
+		for _, option := range definition.Options {
+			kept := false
+			for _, next := range options {
+				if next == option {
+					kept = true
+					break
+				}
+			}
+			if !kept {
+				removed = append(removed, option)
+			}
+		}
+
+		if len(removed) > 0 {
+			var inUse int64
+			if err := t.db.Model(&models.ContactAttributeValueModel{}).
+				Where("definition_id = ? AND value IN ?", definition.ID, removed).
+				Count(&inUse).Error; err != nil {
+				return nil, errors.New("failed to check contact attribute")
+			}
+			if inUse > 0 {
+				return nil, ErrContactAttributeOptionInUse
+			}
+		}
+
+		definition.Options = options
+	}
+
+	if err := t.db.Save(definition).Error; err != nil {
+		return nil, errors.New("failed to update contact attribute")
+	}
+
+	return t.mapToContactAttributeResponse(definition), nil
+}
+
+// DeleteAttribute implements services.ContactAttributeService.
+func (t *contactAttributeServiceImpl) DeleteAttribute(user *jwt.Claims, attributeID uint) error {
+	definition, err := t.findAttribute(user, attributeID)
+	if err != nil {
+		return err
+	}
+
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("definition_id = ?", definition.ID).
+			Delete(&models.ContactAttributeValueModel{}).Error; err != nil {
+			return errors.New("failed to delete contact attribute values")
+		}
+		if err := tx.Delete(definition).Error; err != nil {
+			return errors.New("failed to delete contact attribute")
+		}
+		return nil
+	})
+}
+
+func (t *contactAttributeServiceImpl) findAttribute(user *jwt.Claims, attributeID uint) (*models.ContactAttributeDefinitionModel, error) {
+	var definition models.ContactAttributeDefinitionModel
+	if err := t.db.Scopes(organizationScope(user)).
+		First(&definition, attributeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrContactAttributeNotFound
+		}
+		return nil, errors.New("failed to fetch contact attribute")
+	}
+	return &definition, nil
+}
+
+func (t *contactAttributeServiceImpl) mapToContactAttributeResponse(definition *models.ContactAttributeDefinitionModel) *responsedto.ContactAttributeResponse {
+	return &responsedto.ContactAttributeResponse{
+		ID:        definition.ID,
+		Key:       definition.Key,
+		Label:     definition.Label,
+		Type:      definition.Type,
+		Options:   definition.Options,
+		CreatedAt: definition.CreatedAt,
+		UpdatedAt: definition.UpdatedAt,
+	}
+}
+
+// normalizeContactAttributeOptions trims the options and drops empty and
+// duplicate ones, keeping the order staff entered them in.
+func normalizeContactAttributeOptions(options []string) []string {
+	normalized := make([]string, 0, len(options))
+	seen := map[string]bool{}
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		if option == "" || seen[strings.ToLower(option)] {
+			continue
+		}
+		seen[strings.ToLower(option)] = true
+		normalized = append(normalized, option)
+	}
+	return normalized
+}
+
+func NewContactAttributeService(db *gorm.DB) services.ContactAttributeService {
+	return &contactAttributeServiceImpl{db: db}
+}
@@ -0,0 +1,226 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrContactAttributeUnknown = errors.New("unknown contact attribute")
+	ErrContactAttributeInvalid = errors.New("invalid contact attribute value")
+)
+
+// contactAttributeMaxLength is the longest string value a contact attribute
+// can hold, it matches the value column.
+const contactAttributeMaxLength = 1000
+
+// normalizeContactAttributeValue validates a raw JSON value against the
+// definition and returns the text stored in the value column. Numbers,
+// booleans and dates may also be sent as strings, webhook senders rarely
+// type their payloads.
+func normalizeContactAttributeValue(definition *models.ContactAttributeDefinitionModel, raw any) (string, error) {
+	invalid := fmt.Errorf("%w: %s must be a %s", ErrContactAttributeInvalid, definition.Key, definition.Type)
+
+	switch definition.Type {
+	case models.ContactAttributeTypeNumber:
+		switch value := raw.(type) {
+		case float64:
+			return strconv.FormatFloat(value, 'f', -1, 64), nil
+		case string:
+			number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return "", invalid
+			}
+			return strconv.FormatFloat(number, 'f', -1, 64), nil
+		}
+
+	case models.ContactAttributeTypeBoolean:
+		switch value := raw.(type) {
+		case bool:
+			return strconv.FormatBool(value), nil
+		case string:
+			boolean, err := strconv.ParseBool(strings.TrimSpace(value))
+			if err != nil {
+				return "", invalid
+			}
+			return strconv.FormatBool(boolean), nil
+		}
+
+	case models.ContactAttributeTypeDate:
+		if value, ok := raw.(string); ok {
+			value = strings.TrimSpace(value)
+			for _, layout := range []string{time.DateOnly, time.RFC3339} {
+				if date, err := time.Parse(layout, value); err == nil {
+					return date.Format(time.DateOnly), nil
+				}
+			}
+		}
+
+	case models.ContactAttributeTypeEnum:
+		if value, ok := raw.(string); ok {
+			value = strings.TrimSpace(value)
+			for _, option := range definition.Options {
+				if strings.EqualFold(option, value) {
+					return option, nil
+				}
+			}
+			return "", fmt.Errorf("%w: %s must be one of %s", ErrContactAttributeInvalid, definition.Key, strings.Join(definition.Options, ", "))
+		}
+
+	default:
+		if value, ok := raw.(string); ok {
+			value = strings.TrimSpace(value)
+			if len(value) > contactAttributeMaxLength {
+				return "", fmt.Errorf("%w: %s must not exceed %d characters", ErrContactAttributeInvalid, definition.Key, contactAttributeMaxLength)
+			}
+			return value, nil
+		}
+	}
+
+	return "", invalid
+}
+
+// contactAttributeOutput turns a stored value back into its JSON type.
+func contactAttributeOutput(definition *models.ContactAttributeDefinitionModel, value string) any {
+	switch definition.Type {
+	case models.ContactAttributeTypeNumber:
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number
+		}
+	case models.ContactAttributeTypeBoolean:
+		if boolean, err := strconv.ParseBool(value); err == nil {
+			return boolean
+		}
+	}
+	return value
+}
+
+func loadContactAttributeDefinitions(tx *gorm.DB, organizationID uint) (map[string]*models.ContactAttributeDefinitionModel, error) {
+	var definitions []models.ContactAttributeDefinitionModel
+	if err := tx.Where("organization_id = ?", organizationID).
+		Find(&definitions).Error; err != nil {
+		return nil, errors.New("failed to fetch contact attributes")
+	}
+
+	byKey := make(map[string]*models.ContactAttributeDefinitionModel, len(definitions))
+	for i := range definitions {
+		byKey[definitions[i].Key] = &definitions[i]
+	}
+	return byKey, nil
+}
+
+// setContactAttributes stores the values by attribute key, a nil value
+// removes the attribute from the contact. With skipInvalid unknown keys and
+// invalid values are logged and dropped instead of failing, ingest must not
+// lose a customer message over a badly typed attribute.
+func setContactAttributes(tx *gorm.DB, organizationID uint, contactID uint, values map[string]any, skipInvalid bool) error {
+	definitions, err := loadContactAttributeDefinitions(tx, organizationID)
+	if err != nil {
+		return err
+	}
+
+	for key, raw := range values {
+		definition, ok := definitions[key]
+		if ok && raw == nil {
+			if err := tx.Where("contact_id = ? AND definition_id = ?", contactID, definition.ID).
+				Delete(&models.ContactAttributeValueModel{}).Error; err != nil {
+				return errors.New("failed to remove contact attribute")
+			}
+			continue
+		}
+
+		var value string
+		err := fmt.Errorf("%w: %s", ErrContactAttributeUnknown, key)
+		if ok {
+			value, err = normalizeContactAttributeValue(definition, raw)
+		}
+		if err != nil {
+			if !skipInvalid {
+				return err
+			}
+			logger.ErrorLog("Skipped contact attribute", map[string]any{
+				"contact_id": contactID,
+				"key":        key,
+				"error":      err.Error(),
+			})
+			continue
+		}
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "contact_id"}, {Name: "definition_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+		}).Create(&models.ContactAttributeValueModel{
+			OrganizationID: organizationID,
+			ContactID:      contactID,
+			DefinitionID:   definition.ID,
+			Value:          value,
+		}).Error; err != nil {
+			return errors.New("failed to store contact attribute")
+		}
+	}
+
+	return nil
+}
+
+// contactAttributeFilters narrows a query to rows whose contact, found in
+// contactColumn, has every attribute set to the given value. The filter
+// values are normalized like stored values so "TRUE" finds true.
+func contactAttributeFilters(tx *gorm.DB, organizationID uint, contactColumn string, filters map[string]string) (func(*gorm.DB) *gorm.DB, error) {
+	if len(filters) == 0 {
+		return func(db *gorm.DB) *gorm.DB { return db }, nil
+	}
+
+	definitions, err := loadContactAttributeDefinitions(tx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	type condition struct {
+		definitionID uint
+		value        string
+	}
+	conditions := make([]condition, 0, len(filters))
+	for key, raw := range filters {
+		definition, ok := definitions[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrContactAttributeUnknown, key)
+		}
+		value, err := normalizeContactAttributeValue(definition, raw)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition{definitionID: definition.ID, value: value})
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		for _, c := range conditions {
+			db = db.Where(`EXISTS (
+				SELECT 1 FROM contact_attribute_values
+				WHERE contact_attribute_values.contact_id = `+contactColumn+`
+				AND contact_attribute_values.definition_id = ?
+				AND contact_attribute_values.value = ?
+			)`, c.definitionID, c.value)
+		}
+		return db
+	}, nil
+}
+
+// mapToContactAttributes keys the values by attribute, the definitions must
+// be preloaded.
+func mapToContactAttributes(values []models.ContactAttributeValueModel) map[string]any {
+	attributes := make(map[string]any, len(values))
+	for _, value := range values {
+		if value.Definition != nil {
+			attributes[value.Definition.Key] = contactAttributeOutput(value.Definition, value.Value)
+		}
+	}
+	return attributes
+}
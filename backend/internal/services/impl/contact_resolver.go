@@ -0,0 +1,309 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// contactIdentity is a normalized (type, value) pair used to find a contact.
+type contactIdentity struct {
+	Type  string
+	Value string
+}
+
+// newContactIdentity normalizes the value so the same address written in a
+// different way still resolves to the same contact.
+func newContactIdentity(identityType string, value string) contactIdentity {
+	identityType = strings.ToLower(strings.TrimSpace(identityType))
+	value = strings.TrimSpace(value)
+
+	switch identityType {
+	case models.ContactIdentityTypeEmail:
+		value = strings.ToLower(value)
+	case models.ContactIdentityTypePhone:
+		var b strings.Builder
+		for i, r := range value {
+			if (r >= '0' && r <= '9') || (r == '+' && i == 0) {
+				b.WriteRune(r)
+			}
+		}
+		value = b.String()
+	}
+
+	return contactIdentity{Type: identityType, Value: value}
+}
+
+// contactMatchDigits is how many trailing digits two phone numbers share to
+// be suggested as the same person, enough to match 0812... with +62812...
+const contactMatchDigits = 9
+
+// matchKey groups identities that probably belong to the same person. Emails
+// match without their +tag, phone numbers and numeric channel ids such as a
+// whatsapp number match on their trailing digits. Nil means no match key.
+func (i contactIdentity) matchKey() *string {
+	if i.Type == models.ContactIdentityTypeEmail {
+		local, domain, found := strings.Cut(i.Value, "@")
+		if !found || local == "" || domain == "" {
+			return nil
+		}
+		if local, _, _ = strings.Cut(local, "+"); local == "" {
+			return nil
+		}
+		key := "email:" + local + "@" + domain
+		return &key
+	}
+
+	var digits strings.Builder
+	for j, r := range i.Value {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' && j == 0:
+		default:
+			// not a number, e.g. an instagram handle
+			return nil
+		}
+	}
+	if digits.Len() < contactMatchDigits {
+		return nil
+	}
+	number := digits.String()
+	key := "phone:" + number[len(number)-contactMatchDigits:]
+	return &key
+}
+
+// resolveContact finds the contact owning one of the identities or creates
+// it, and attaches the identities nobody owns yet. It must run inside a
+// transaction.
+func resolveContact(tx *gorm.DB, organizationID uint, name string, avatarURL *string, identities []contactIdentity) (*models.ContactModel, error) {
+	pairs := make([][]any, 0, len(identities))
+	for _, identity := range identities {
+		if identity.Type != "" && identity.Value != "" {
+			pairs = append(pairs, []any{identity.Type, identity.Value})
+		}
+	}
+	if len(pairs) == 0 {
+		return nil, errors.New("contact has no identity")
+	}
+
+	var known []models.ContactIdentityModel
+	if err := tx.Where("organization_id = ?", organizationID).
+		Where("(type, value) IN ?", pairs).
+		Order("id ASC").
+		Find(&known).Error; err != nil {
+		return nil, errors.New("failed to find contact")
+	}
+
+	var contact models.ContactModel
+	if len(known) > 0 {
+		if err := tx.First(&contact, known[0].ContactID).Error; err != nil {
+			return nil, errors.New("failed to find contact")
+		}
+
+		updates := map[string]any{}
+		if contact.Name == "" && name != "" {
+			contact.Name = name
+			updates["name"] = name
+		}
+		if contact.AvatarURL == nil && avatarURL != nil {
+			contact.AvatarURL = avatarURL
+			updates["avatar_url"] = avatarURL
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&contact).Updates(updates).Error; err != nil {
+				return nil, errors.New("failed to update contact")
+			}
+		}
+	} else {
+		contact = models.ContactModel{
+			OrganizationID: organizationID,
+			Name:           name,
+			AvatarURL:      avatarURL,
+		}
+		if err := tx.Create(&contact).Error; err != nil {
+			return nil, errors.New("failed to create contact")
+		}
+	}
+
+	for _, pair := range pairs {
+		owned := false
+		for _, identity := range known {
+			if identity.Type == pair[0] && identity.Value == pair[1] {
+				owned = true
+				break
+			}
+		}
+		if owned {
+			continue
+		}
+
+		// an identity owned by another contact is left alone, merging the
+		// two contacts is a decision for the staff
+		identity := models.ContactIdentityModel{
+			OrganizationID: organizationID,
+			ContactID:      contact.ID,
+			Type:           pair[0].(string),
+			Value:          pair[1].(string),
+			MatchKey:       contactIdentity{Type: pair[0].(string), Value: pair[1].(string)}.matchKey(),
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&identity).Error; err != nil {
+			return nil, errors.New("failed to store contact identity")
+		}
+	}
+
+	if len(known) == 0 {
+		return settleNewContact(tx, &contact, pairs)
+	}
+	return &contact, nil
+}
+
+// settleNewContact handles a contact created for the same identities at the
+// same time. The unique key of the identities lets only one of them keep
+// each identity, the other one is left without any and is removed in favour
+// of the winner. The locking read sees rows committed after this
+// transaction started.
+func settleNewContact(tx *gorm.DB, contact *models.ContactModel, pairs [][]any) (*models.ContactModel, error) {
+	var owners []models.ContactIdentityModel
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("organization_id = ?", contact.OrganizationID).
+		Where("(type, value) IN ?", pairs).
+		Order("id ASC").
+		Find(&owners).Error; err != nil {
+		return nil, errors.New("failed to find contact")
+	}
+	if len(owners) == 0 {
+		return nil, errors.New("failed to store contact identity")
+	}
+	for _, owner := range owners {
+		if owner.ContactID == contact.ID {
+			return contact, nil
+		}
+	}
+
+	if err := tx.Unscoped().Delete(contact).Error; err != nil {
+		return nil, errors.New("failed to remove duplicate contact")
+	}
+
+	var winner models.ContactModel
+	if err := tx.First(&winner, owners[0].ContactID).Error; err != nil {
+		return nil, errors.New("failed to find contact")
+	}
+	return &winner, nil
+}
+
+// findOrCreateUserContact returns the contact of a registered guest in the
+// organization. Registering does not prove the email, so only a verified
+// guest, one who signed in by magic link, is linked to the contact that has
+// their email. An unverified guest gets a contact of their own with the
+// email only claimed: mail and webhook messages from the address do not end
+// up there, and the staff decide on the merge suggestion.
+func findOrCreateUserContact(tx *gorm.DB, organizationID uint, user *models.UserModel, verified bool) (*models.ContactModel, error) {
+	identity := newContactIdentity(models.ContactIdentityTypeEmail, user.Email)
+
+	if verified {
+		resolved, err := resolveContact(tx, organizationID, user.Name, nil, []contactIdentity{identity})
+		if err != nil {
+			return nil, err
+		}
+
+		if resolved.UserID == nil {
+			resolved.UserID = &user.ID
+			if err := tx.Model(resolved).Update("user_id", user.ID).Error; err != nil {
+				return nil, errors.New("failed to link contact")
+			}
+		}
+		return resolved, nil
+	}
+
+	var contact models.ContactModel
+	err := tx.Where("organization_id = ?", organizationID).
+		Where("user_id = ?", user.ID).
+		Order("id ASC").
+		First(&contact).Error
+	if err == nil {
+		return &contact, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("failed to find contact")
+	}
+
+	contact = models.ContactModel{
+		OrganizationID: organizationID,
+		Name:           user.Name,
+		UserID:         &user.ID,
+	}
+	if err := tx.Create(&contact).Error; err != nil {
+		return nil, errors.New("failed to create contact")
+	}
+
+	claimed := models.ContactIdentityModel{
+		OrganizationID: organizationID,
+		ContactID:      contact.ID,
+		Type:           models.ContactIdentityTypeClaimedEmail,
+		Value:          identity.Value,
+		MatchKey:       identity.matchKey(),
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&claimed).Error; err != nil {
+		return nil, errors.New("failed to store contact identity")
+	}
+
+	return &contact, nil
+}
+
+// contactIdentityValue returns the first identity of the given type, the
+// identities must be preloaded.
+func contactIdentityValue(contact *models.ContactModel, identityType string) *string {
+	if contact == nil {
+		return nil
+	}
+	for _, identity := range contact.Identities {
+		if identity.Type == identityType {
+			value := identity.Value
+			return &value
+		}
+	}
+	return nil
+}
+
+// contactEmail is the address staff replies to, empty when the contact has
+// no email identity.
+func contactEmail(contact *models.ContactModel) string {
+	if email := contactIdentityValue(contact, models.ContactIdentityTypeEmail); email != nil {
+		return *email
+	}
+	return ""
+}
+
+func mapToContactData(contact *models.ContactModel) *responsedto.ContactData {
+	if contact == nil {
+		return nil
+	}
+	data := &responsedto.ContactData{
+		ID:        contact.ID,
+		Name:      contact.Name,
+		Email:     contactIdentityValue(contact, models.ContactIdentityTypeEmail),
+		Phone:     contactIdentityValue(contact, models.ContactIdentityTypePhone),
+		AvatarURL: contact.AvatarURL,
+	}
+
+	// every channel the customer writes from, merged contacts show up once
+	for _, identity := range contact.Identities {
+		data.Identities = append(data.Identities, responsedto.ContactIdentityResponse{
+			ID:    identity.ID,
+			Type:  identity.Type,
+			Value: identity.Value,
+		})
+	}
+
+	// attributes are only preloaded for staff views
+	if len(contact.AttributeValues) > 0 {
+		data.Attributes = mapToContactAttributes(contact.AttributeValues)
+	}
+
+	return data
+}
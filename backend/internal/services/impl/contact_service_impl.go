@@ -0,0 +1,782 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrContactNotFound         = errors.New("contact not found")
+	ErrContactIdentityNotFound = errors.New("contact identity not found")
+	ErrContactIdentityTaken    = errors.New("identity already belongs to another contact")
+	ErrContactIdentityInvalid  = errors.New("identity value is empty after normalization")
+	ErrGuestUserNotFound       = errors.New("guest user not found")
+	ErrContactMergeSelf        = errors.New("a contact cannot be merged into itself")
+	ErrContactMergeNotFound    = errors.New("contact merge not found")
+	ErrContactMergeNotUndoable = errors.New("merge was already undone or the contact was merged again")
+	ErrContactNoteNotFound     = errors.New("contact note not found")
+)
+
+type contactServiceImpl struct {
+	db *gorm.DB
+}
+
+// GetContacts implements services.ContactService.
+func (t *contactServiceImpl) GetContacts(user *jwt.Claims, filter filtersdto.FiltersDto, search string) (*responsedto.ContactPaginateResponse, error) {
+	var contacts []models.ContactModel
+	var total int64
+	offset := (*filter.Page - 1) * *filter.Limit
+
+	query := t.db.Model(&models.ContactModel{}).
+		Scopes(organizationScope(user))
+
+	if search = strings.TrimSpace(search); search != "" {
+		pattern := "%" + strings.ToLower(search) + "%"
+		query = query.Where(`LOWER(name) LIKE ? OR EXISTS (
+			SELECT 1 FROM contact_identities
+			WHERE contact_identities.contact_id = contacts.id
+			AND contact_identities.value LIKE ?
+		)`, pattern, pattern)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, errors.New("failed to count contacts")
+	}
+
+	if err := query.Offset(offset).Limit(*filter.Limit).
+		Preload("Identities").
+		Preload("AttributeValues.Definition").
+		Preload("User").
+		Order("created_at DESC").
+		Find(&contacts).Error; err != nil {
+		return nil, errors.New("failed to fetch contacts")
+	}
+
+	data := make([]responsedto.ContactResponse, 0, len(contacts))
+	for i := range contacts {
+		data = append(data, *t.mapToContactResponse(&contacts[i]))
+	}
+
+	return &responsedto.ContactPaginateResponse{
+		Data: data,
+		Metadata: responsedto.PaginateMetaData{
+			Total: int(total),
+			Page:  *filter.Page,
+			Limit: *filter.Limit,
+		},
+	}, nil
+}
+
+// GetContactByID implements services.ContactService.
+func (t *contactServiceImpl) GetContactByID(user *jwt.Claims, contactID uint) (*responsedto.ContactResponse, error) {
+	contact, err := t.findContact(t.db, user, contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.mapToContactResponse(contact), nil
+}
+
+// CreateContact implements services.ContactService.
+func (t *contactServiceImpl) CreateContact(user *jwt.Claims, req requestdto.CreateContactRequest) (*responsedto.ContactResponse, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	contact := models.ContactModel{
+		OrganizationID: *user.OrganizationId,
+		Name:           req.Name,
+		AvatarURL:      req.AvatarURL,
+	}
+
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&contact).Error; err != nil {
+			return errors.New("failed to create contact")
+		}
+
+		for _, identity := range req.Identities {
+			if err := t.addIdentity(tx, &contact, newContactIdentity(identity.Type, identity.Value)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t.GetContactByID(user, contact.ID)
+}
+
+// UpdateContact implements services.ContactService.
+func (t *contactServiceImpl) UpdateContact(user *jwt.Claims, contactID uint, req requestdto.UpdateContactRequest) (*responsedto.ContactResponse, error) {
+	contact, err := t.findContact(t.db, user, contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		contact.Name = req.Name
+	}
+	if req.AvatarURL != nil {
+		contact.AvatarURL = req.AvatarURL
+	}
+
+	if err := t.db.Omit("Identities", "AttributeValues", "User").Save(contact).Error; err != nil {
+		return nil, errors.New("failed to update contact")
+	}
+
+	return t.mapToContactResponse(contact), nil
+}
+
+// AddIdentity implements services.ContactService.
+func (t *contactServiceImpl) AddIdentity(user *jwt.Claims, contactID uint, req requestdto.ContactIdentityRequest) (*responsedto.ContactResponse, error) {
+	contact, err := t.findContact(t.db, user, contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.addIdentity(t.db, contact, newContactIdentity(req.Type, req.Value)); err != nil {
+		return nil, err
+	}
+
+	return t.GetContactByID(user, contact.ID)
+}
+
+// RemoveIdentity implements services.ContactService.
+func (t *contactServiceImpl) RemoveIdentity(user *jwt.Claims, contactID uint, identityID uint) error {
+	contact, err := t.findContact(t.db, user, contactID)
+	if err != nil {
+		return err
+	}
+
+	result := t.db.Where("contact_id = ?", contact.ID).
+		Delete(&models.ContactIdentityModel{}, identityID)
+	if result.Error != nil {
+		return errors.New("failed to remove contact identity")
+	}
+	if result.RowsAffected == 0 {
+		return ErrContactIdentityNotFound
+	}
+
+	return nil
+}
+
+// LinkUser implements services.ContactService.
+func (t *contactServiceImpl) LinkUser(user *jwt.Claims, contactID uint, req requestdto.LinkContactUserRequest) (*responsedto.ContactResponse, error) {
+	contact, err := t.findContact(t.db, user, contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	var guest models.UserModel
+	if err := t.db.Joins("JOIN user_roles ON user_roles.id = users.role_id").
+		Where("user_roles.name = ?", models.RoleGuest).
+		Where("users.email = ?", strings.ToLower(strings.TrimSpace(req.Email))).
+		First(&guest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGuestUserNotFound
+		}
+		return nil, errors.New("failed to fetch user")
+	}
+
+	if err := t.db.Model(contact).Update("user_id", guest.ID).Error; err != nil {
+		return nil, errors.New("failed to link contact")
+	}
+
+	return t.GetContactByID(user, contact.ID)
+}
+
+// UnlinkUser implements services.ContactService.
+func (t *contactServiceImpl) UnlinkUser(user *jwt.Claims, contactID uint) error {
+	contact, err := t.findContact(t.db, user, contactID)
+	if err != nil {
+		return err
+	}
+
+	if err := t.db.Model(contact).Update("user_id", nil).Error; err != nil {
+		return errors.New("failed to unlink contact")
+	}
+
+	return nil
+}
+
+// SetAttributes implements services.ContactService.
+func (t *contactServiceImpl) SetAttributes(user *jwt.Claims, contactID uint, req requestdto.SetContactAttributesRequest) (*responsedto.ContactResponse, error) {
+	contact, err := t.findContact(t.db, user, contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.db.Transaction(func(tx *gorm.DB) error {
+		return setContactAttributes(tx, contact.OrganizationID, contact.ID, req.Attributes, false)
+	}); err != nil {
+		return nil, err
+	}
+
+	return t.GetContactByID(user, contact.ID)
+}
+
+// GetNotes implements services.ContactService.
+func (t *contactServiceImpl) GetNotes(user *jwt.Claims, contactID uint, filter filtersdto.FiltersDto) (*responsedto.ContactNotePaginateResponse, error) {
+	contact, err := t.findContact(t.db, user, contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	var notes []models.ContactNoteModel
+	var total int64
+	offset := (*filter.Page - 1) * *filter.Limit
+
+	query := t.db.Model(&models.ContactNoteModel{}).
+		Where("contact_id = ?", contact.ID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, errors.New("failed to count contact notes")
+	}
+
+	if err := query.Offset(offset).Limit(*filter.Limit).
+		Preload("Author").
+		Order("created_at DESC").
+		Find(&notes).Error; err != nil {
+		return nil, errors.New("failed to fetch contact notes")
+	}
+
+	data := make([]responsedto.ContactNoteResponse, 0, len(notes))
+	for i := range notes {
+		data = append(data, *t.mapToContactNoteResponse(&notes[i]))
+	}
+
+	return &responsedto.ContactNotePaginateResponse{
+		Data: data,
+		Metadata: responsedto.PaginateMetaData{
+			Total: int(total),
+			Page:  *filter.Page,
+			Limit: *filter.Limit,
+		},
+	}, nil
+}
+
+// CreateNote implements services.ContactService.
+func (t *contactServiceImpl) CreateNote(user *jwt.Claims, contactID uint, req requestdto.ContactNoteRequest) (*responsedto.ContactNoteResponse, error) {
+	contact, err := t.findContact(t.db, user, contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	note := models.ContactNoteModel{
+		OrganizationID: contact.OrganizationID,
+		ContactID:      contact.ID,
+		AuthorID:       user.UserID,
+		Body:           strings.TrimSpace(req.Body),
+	}
+	if err := t.db.Create(&note).Error; err != nil {
+		return nil, errors.New("failed to create contact note")
+	}
+
+	return t.findNote(user, contact.ID, note.ID)
+}
+
+// UpdateNote implements services.ContactService.
+func (t *contactServiceImpl) UpdateNote(user *jwt.Claims, contactID uint, noteID uint, req requestdto.ContactNoteRequest) (*responsedto.ContactNoteResponse, error) {
+	if _, err := t.findNote(user, contactID, noteID); err != nil {
+		return nil, err
+	}
+
+	if err := t.db.Model(&models.ContactNoteModel{}).
+		Where("id = ?", noteID).
+		Update("body", strings.TrimSpace(req.Body)).Error; err != nil {
+		return nil, errors.New("failed to update contact note")
+	}
+
+	return t.findNote(user, contactID, noteID)
+}
+
+// DeleteNote implements services.ContactService.
+func (t *contactServiceImpl) DeleteNote(user *jwt.Claims, contactID uint, noteID uint) error {
+	if _, err := t.findNote(user, contactID, noteID); err != nil {
+		return err
+	}
+
+	if err := t.db.Delete(&models.ContactNoteModel{}, noteID).Error; err != nil {
+		return errors.New("failed to delete contact note")
+	}
+
+	return nil
+}
+
+// GetTimeline implements services.ContactService.
+func (t *contactServiceImpl) GetTimeline(user *jwt.Claims, contactID uint, cursor string, limit int) (*responsedto.ContactTimelineResponse, error) {
+	contact, err := t.findContact(t.db, user, contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	assigneeID, err := staffAssigneeID(t.db, user)
+	if err != nil {
+		return nil, err
+	}
+	return loadContactTimeline(t.db, contact.OrganizationID, contact.ID, assigneeID, cursor, limit)
+}
+
+// GetMergeSuggestions implements services.ContactService.
+func (t *contactServiceImpl) GetMergeSuggestions(user *jwt.Claims, filter filtersdto.FiltersDto) (*responsedto.ContactMergeSuggestionPaginateResponse, error) {
+	var total int64
+	offset := (*filter.Page - 1) * *filter.Limit
+
+	// match keys shared by more than one contact, newest first
+	sharedKeys := func() *gorm.DB {
+		return t.db.Model(&models.ContactIdentityModel{}).
+			Scopes(organizationScope(user)).
+			Where("match_key IS NOT NULL").
+			Group("match_key").
+			Having("COUNT(DISTINCT contact_id) > 1")
+	}
+
+	if err := t.db.Table("(?) AS suggestions", sharedKeys().Select("match_key")).
+		Count(&total).Error; err != nil {
+		return nil, errors.New("failed to count merge suggestions")
+	}
+
+	var keys []string
+	if err := sharedKeys().
+		Order("MAX(id) DESC").
+		Offset(offset).Limit(*filter.Limit).
+		Pluck("match_key", &keys).Error; err != nil {
+		return nil, errors.New("failed to fetch merge suggestions")
+	}
+
+	data := make([]responsedto.ContactMergeSuggestionResponse, 0, len(keys))
+	if len(keys) > 0 {
+		var identities []models.ContactIdentityModel
+		if err := t.db.Scopes(organizationScope(user)).
+			Where("match_key IN ?", keys).
+			Order("contact_id ASC").
+			Find(&identities).Error; err != nil {
+			return nil, errors.New("failed to fetch merge suggestions")
+		}
+
+		contactIDs := make([]uint, 0, len(identities))
+		for _, identity := range identities {
+			contactIDs = append(contactIDs, identity.ContactID)
+		}
+
+		var contacts []models.ContactModel
+		if err := t.db.Where("id IN ?", contactIDs).
+			Preload("Identities", func(db *gorm.DB) *gorm.DB {
+				return db.Order("id ASC")
+			}).
+			Preload("AttributeValues.Definition").
+			Preload("User").
+			Find(&contacts).Error; err != nil {
+			return nil, errors.New("failed to fetch contacts")
+		}
+		contactsByID := make(map[uint]*models.ContactModel, len(contacts))
+		for i := range contacts {
+			contactsByID[contacts[i].ID] = &contacts[i]
+		}
+
+		for _, key := range keys {
+			reason, _, _ := strings.Cut(key, ":")
+			suggestion := responsedto.ContactMergeSuggestionResponse{
+				MatchKey: key,
+				Reason:   reason,
+				Contacts: []responsedto.ContactResponse{},
+			}
+
+			seen := map[uint]bool{}
+			for _, identity := range identities {
+				contact, ok := contactsByID[identity.ContactID]
+				if identity.MatchKey == nil || *identity.MatchKey != key || !ok || seen[contact.ID] {
+					continue
+				}
+				seen[contact.ID] = true
+				suggestion.Contacts = append(suggestion.Contacts, *t.mapToContactResponse(contact))
+			}
+			data = append(data, suggestion)
+		}
+	}
+
+	return &responsedto.ContactMergeSuggestionPaginateResponse{
+		Data: data,
+		Metadata: responsedto.PaginateMetaData{
+			Total: int(total),
+			Page:  *filter.Page,
+			Limit: *filter.Limit,
+		},
+	}, nil
+}
+
+// MergeContact implements services.ContactService.
+func (t *contactServiceImpl) MergeContact(user *jwt.Claims, contactID uint, req requestdto.MergeContactRequest) (*responsedto.ContactMergeResponse, error) {
+	if req.SourceContactID == contactID {
+		return nil, ErrContactMergeSelf
+	}
+
+	var merge models.ContactMergeModel
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		target, err := t.findContact(tx, user, contactID)
+		if err != nil {
+			return err
+		}
+		source, err := t.findContact(tx, user, req.SourceContactID)
+		if err != nil {
+			return err
+		}
+
+		// deleting first makes a concurrent merge of the same source wait
+		// for this one and then find nothing to delete
+		result := tx.Delete(&models.ContactModel{}, source.ID)
+		if result.Error != nil {
+			return errors.New("failed to merge contact")
+		}
+		if result.RowsAffected == 0 {
+			return ErrContactNotFound
+		}
+
+		merge = models.ContactMergeModel{
+			OrganizationID:    target.OrganizationID,
+			TargetContactID:   target.ID,
+			SourceContactID:   source.ID,
+			MergedByID:        user.UserID,
+			IdentityIDs:       []uint{},
+			ConversationIDs:   []uint{},
+			NoteIDs:           []uint{},
+			AttributeValueIDs: []uint{},
+		}
+
+		if err := tx.Model(&models.ContactIdentityModel{}).
+			Where("contact_id = ?", source.ID).
+			Pluck("id", &merge.IdentityIDs).Error; err != nil {
+			return errors.New("failed to fetch contact identities")
+		}
+		if err := tx.Model(&models.ConversationModel{}).
+			Where("contact_id = ?", source.ID).
+			Pluck("id", &merge.ConversationIDs).Error; err != nil {
+			return errors.New("failed to fetch conversations")
+		}
+		if err := tx.Model(&models.ContactNoteModel{}).
+			Where("contact_id = ?", source.ID).
+			Pluck("id", &merge.NoteIDs).Error; err != nil {
+			return errors.New("failed to fetch contact notes")
+		}
+		// attributes the target already has keep the target value
+		if err := tx.Model(&models.ContactAttributeValueModel{}).
+			Where("contact_id = ?", source.ID).
+			Where("definition_id NOT IN (?)", tx.Model(&models.ContactAttributeValueModel{}).
+				Select("definition_id").
+				Where("contact_id = ?", target.ID)).
+			Pluck("id", &merge.AttributeValueIDs).Error; err != nil {
+			return errors.New("failed to fetch contact attributes")
+		}
+
+		if err := tx.Model(&models.ContactIdentityModel{}).
+			Where("contact_id = ?", source.ID).
+			Update("contact_id", target.ID).Error; err != nil {
+			return errors.New("failed to move contact identities")
+		}
+		if err := tx.Model(&models.ConversationModel{}).
+			Where("contact_id = ?", source.ID).
+			Update("contact_id", target.ID).Error; err != nil {
+			return errors.New("failed to move conversations")
+		}
+		if err := tx.Model(&models.ConversationMessageModel{}).
+			Where("contact_id = ?", source.ID).
+			Update("contact_id", target.ID).Error; err != nil {
+			return errors.New("failed to move messages")
+		}
+		if err := tx.Model(&models.ContactNoteModel{}).
+			Where("contact_id = ?", source.ID).
+			Update("contact_id", target.ID).Error; err != nil {
+			return errors.New("failed to move contact notes")
+		}
+		if len(merge.AttributeValueIDs) > 0 {
+			if err := tx.Model(&models.ContactAttributeValueModel{}).
+				Where("id IN ?", merge.AttributeValueIDs).
+				Update("contact_id", target.ID).Error; err != nil {
+				return errors.New("failed to move contact attributes")
+			}
+		}
+
+		if target.UserID == nil && source.UserID != nil {
+			if err := tx.Model(target).Update("user_id", *source.UserID).Error; err != nil {
+				return errors.New("failed to link contact")
+			}
+			merge.UserMoved = true
+		}
+
+		if err := tx.Create(&merge).Error; err != nil {
+			return errors.New("failed to store contact merge")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t.mapToContactMergeResponse(&merge), nil
+}
+
+// GetContactMerges implements services.ContactService.
+func (t *contactServiceImpl) GetContactMerges(user *jwt.Claims, contactID uint) ([]responsedto.ContactMergeResponse, error) {
+	contact, err := t.findContact(t.db, user, contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	var merges []models.ContactMergeModel
+	if err := t.db.Scopes(organizationScope(user)).
+		Where("target_contact_id = ? OR source_contact_id = ?", contact.ID, contact.ID).
+		Order("id DESC").
+		Find(&merges).Error; err != nil {
+		return nil, errors.New("failed to fetch contact merges")
+	}
+
+	responses := make([]responsedto.ContactMergeResponse, 0, len(merges))
+	for i := range merges {
+		responses = append(responses, *t.mapToContactMergeResponse(&merges[i]))
+	}
+
+	return responses, nil
+}
+
+// UndoMerge implements services.ContactService.
+func (t *contactServiceImpl) UndoMerge(user *jwt.Claims, mergeID uint) (*responsedto.ContactMergeResponse, error) {
+	var merge models.ContactMergeModel
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Scopes(organizationScope(user)).
+			First(&merge, mergeID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrContactMergeNotFound
+			}
+			return errors.New("failed to fetch contact merge")
+		}
+		if merge.UndoneAt != nil {
+			return ErrContactMergeNotUndoable
+		}
+
+		// a target merged into another contact since then has to be
+		// restored first
+		var target models.ContactModel
+		if err := tx.First(&target, merge.TargetContactID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrContactMergeNotUndoable
+			}
+			return errors.New("failed to fetch contact")
+		}
+
+		var source models.ContactModel
+		if err := tx.Unscoped().First(&source, merge.SourceContactID).Error; err != nil {
+			return errors.New("failed to fetch contact")
+		}
+
+		result := tx.Unscoped().Model(&models.ContactModel{}).
+			Where("id = ? AND deleted_at IS NOT NULL", source.ID).
+			Update("deleted_at", nil)
+		if result.Error != nil {
+			return errors.New("failed to restore contact")
+		}
+		if result.RowsAffected == 0 {
+			return ErrContactMergeNotUndoable
+		}
+
+		// only rows still on the target move back, anything the staff
+		// changed after the merge stays where it is
+		if len(merge.IdentityIDs) > 0 {
+			if err := tx.Model(&models.ContactIdentityModel{}).
+				Where("id IN ? AND contact_id = ?", merge.IdentityIDs, target.ID).
+				Update("contact_id", source.ID).Error; err != nil {
+				return errors.New("failed to move contact identities")
+			}
+		}
+		if len(merge.ConversationIDs) > 0 {
+			if err := tx.Model(&models.ConversationModel{}).
+				Where("id IN ? AND contact_id = ?", merge.ConversationIDs, target.ID).
+				Update("contact_id", source.ID).Error; err != nil {
+				return errors.New("failed to move conversations")
+			}
+			if err := tx.Model(&models.ConversationMessageModel{}).
+				Where("conversation_id IN ? AND contact_id = ?", merge.ConversationIDs, target.ID).
+				Update("contact_id", source.ID).Error; err != nil {
+				return errors.New("failed to move messages")
+			}
+		}
+		if len(merge.NoteIDs) > 0 {
+			if err := tx.Model(&models.ContactNoteModel{}).
+				Where("id IN ? AND contact_id = ?", merge.NoteIDs, target.ID).
+				Update("contact_id", source.ID).Error; err != nil {
+				return errors.New("failed to move contact notes")
+			}
+		}
+		if len(merge.AttributeValueIDs) > 0 {
+			if err := tx.Model(&models.ContactAttributeValueModel{}).
+				Where("id IN ? AND contact_id = ?", merge.AttributeValueIDs, target.ID).
+				Update("contact_id", source.ID).Error; err != nil {
+				return errors.New("failed to move contact attributes")
+			}
+		}
+
+		if merge.UserMoved && target.UserID != nil && source.UserID != nil && *target.UserID == *source.UserID {
+			if err := tx.Model(&target).Update("user_id", nil).Error; err != nil {
+				return errors.New("failed to unlink contact")
+			}
+		}
+
+		now := time.Now()
+		merge.UndoneAt = &now
+		merge.UndoneByID = &user.UserID
+		if err := tx.Save(&merge).Error; err != nil {
+			return errors.New("failed to update contact merge")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t.mapToContactMergeResponse(&merge), nil
+}
+
+func (t *contactServiceImpl) addIdentity(tx *gorm.DB, contact *models.ContactModel, identity contactIdentity) error {
+	if identity.Type == "" || identity.Value == "" {
+		return ErrContactIdentityInvalid
+	}
+
+	var existing models.ContactIdentityModel
+	err := tx.Where("organization_id = ?", contact.OrganizationID).
+		Where("type = ? AND value = ?", identity.Type, identity.Value).
+		First(&existing).Error
+	if err == nil {
+		if existing.ContactID == contact.ID {
+			return nil
+		}
+		return ErrContactIdentityTaken
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New("failed to check contact identity")
+	}
+
+	if err := tx.Create(&models.ContactIdentityModel{
+		OrganizationID: contact.OrganizationID,
+		ContactID:      contact.ID,
+		Type:           identity.Type,
+		Value:          identity.Value,
+		MatchKey:       identity.matchKey(),
+	}).Error; err != nil {
+		return errors.New("failed to store contact identity")
+	}
+
+	return nil
+}
+
+func (t *contactServiceImpl) findContact(tx *gorm.DB, user *jwt.Claims, contactID uint) (*models.ContactModel, error) {
+	var contact models.ContactModel
+	if err := tx.Scopes(organizationScope(user)).
+		Preload("Identities", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		Preload("AttributeValues.Definition").
+		Preload("User").
+		First(&contact, contactID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrContactNotFound
+		}
+		return nil, errors.New("failed to fetch contact")
+	}
+	return &contact, nil
+}
+
+func (t *contactServiceImpl) mapToContactResponse(contact *models.ContactModel) *responsedto.ContactResponse {
+	response := &responsedto.ContactResponse{
+		ID:             contact.ID,
+		OrganizationID: contact.OrganizationID,
+		Name:           contact.Name,
+		AvatarURL:      contact.AvatarURL,
+		UserID:         contact.UserID,
+		Identities:     make([]responsedto.ContactIdentityResponse, 0, len(contact.Identities)),
+		Attributes:     mapToContactAttributes(contact.AttributeValues),
+		CreatedAt:      contact.CreatedAt,
+		UpdatedAt:      contact.UpdatedAt,
+	}
+
+	if contact.User != nil {
+		response.User = &responsedto.UserData{
+			ID:    contact.User.ID,
+			Email: contact.User.Email,
+			Name:  contact.User.Name,
+		}
+	}
+
+	for _, identity := range contact.Identities {
+		response.Identities = append(response.Identities, responsedto.ContactIdentityResponse{
+			ID:    identity.ID,
+			Type:  identity.Type,
+			Value: identity.Value,
+		})
+	}
+
+	return response
+}
+
+func (t *contactServiceImpl) findNote(user *jwt.Claims, contactID uint, noteID uint) (*responsedto.ContactNoteResponse, error) {
+	var note models.ContactNoteModel
+	if err := t.db.Scopes(organizationScope(user)).
+		Where("contact_id = ?", contactID).
+		Preload("Author").
+		First(&note, noteID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrContactNoteNotFound
+		}
+		return nil, errors.New("failed to fetch contact note")
+	}
+	return t.mapToContactNoteResponse(&note), nil
+}
+
+func (t *contactServiceImpl) mapToContactNoteResponse(note *models.ContactNoteModel) *responsedto.ContactNoteResponse {
+	response := &responsedto.ContactNoteResponse{
+		ID:        note.ID,
+		ContactID: note.ContactID,
+		AuthorID:  note.AuthorID,
+		Body:      note.Body,
+		CreatedAt: note.CreatedAt,
+		UpdatedAt: note.UpdatedAt,
+	}
+	if note.Author != nil {
+		response.Author = &responsedto.UserData{
+			ID:    note.Author.ID,
+			Email: note.Author.Email,
+			Name:  note.Author.Name,
+		}
+	}
+	return response
+}
+
+func (t *contactServiceImpl) mapToContactMergeResponse(merge *models.ContactMergeModel) *responsedto.ContactMergeResponse {
+	return &responsedto.ContactMergeResponse{
+		ID:                merge.ID,
+		TargetContactID:   merge.TargetContactID,
+		SourceContactID:   merge.SourceContactID,
+		MergedByID:        merge.MergedByID,
+		IdentityIDs:       merge.IdentityIDs,
+		ConversationIDs:   merge.ConversationIDs,
+		NoteIDs:           merge.NoteIDs,
+		AttributeValueIDs: merge.AttributeValueIDs,
+		UserMoved:         merge.UserMoved,
+		CreatedAt:         merge.CreatedAt,
+		UndoneAt:          merge.UndoneAt,
+		UndoneByID:        merge.UndoneByID,
+	}
+}
+
+func NewContactService(db *gorm.DB) services.ContactService {
+	return &contactServiceImpl{db: db}
+}
@@ -0,0 +1,324 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/models"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrTimelineCursorInvalid = errors.New("invalid timeline cursor")
+
+// Constants for the contact timeline entry types
+const (
+	timelineConversationCreated = "conversation_created"
+	timelineMessage             = "message"
+	timelineTicketCreated       = "ticket_created"
+	timelineStatusChanged       = "status_changed"
+)
+
+// contactTimelineMaxLimit caps one page of the timeline.
+const contactTimelineMaxLimit = 100
+
+// contactConversationIDsQuery selects the ids of the contact's conversations
+// in the organization.
+const contactConversationIDsQuery = `
+	SELECT conversations.id FROM conversations
+	WHERE conversations.organization_id = @organization AND conversations.contact_id = @contact
+		AND conversations.deleted_at IS NULL`
+
+// contactTimelineQuery lists every event of the contact in the organization
+// as (kind, id, occurred_at). Everything hangs off the contact's
+// conversations, so a merge or an undo moves the whole history along.
+// Tickets count when they were created from one of the conversations or
+// cover one through ticket_conversations, each is listed once. A
+// non zero @assignee keeps the conversations and tickets assigned to that
+// staff member, the way staffAccessScope does.
+const contactTimelineQuery = `
+	SELECT 'conversation_created' AS kind, conversations.id AS id, conversations.created_at AS occurred_at
+	FROM conversations
+	WHERE conversations.organization_id = @organization AND conversations.contact_id = @contact
+		AND conversations.deleted_at IS NULL
+		AND (@assignee = 0 OR conversations.organization_staff_id = @assignee)
+	UNION ALL
+	SELECT 'message', conversation_messages.id, conversation_messages.created_at
+	FROM conversation_messages
+	JOIN conversations ON conversations.id = conversation_messages.conversation_id
+	WHERE conversations.organization_id = @organization AND conversations.contact_id = @contact
+		AND conversations.deleted_at IS NULL AND conversation_messages.deleted_at IS NULL
+		AND (@assignee = 0 OR conversations.organization_staff_id = @assignee)
+	UNION ALL
+	SELECT 'ticket_created', tickets.id, tickets.created_at
+	FROM tickets
+	WHERE tickets.organization_id = @organization AND tickets.deleted_at IS NULL
+		AND (tickets.conversation_id IN (` + contactConversationIDsQuery + `)
+			OR tickets.id IN (
+				SELECT ticket_conversations.ticket_id FROM ticket_conversations
+				WHERE ticket_conversations.conversation_id IN (` + contactConversationIDsQuery + `)))
+		AND (@assignee = 0 OR tickets.assignee_id = @assignee)
+	UNION ALL
+	SELECT 'status_changed', status_changes.id, status_changes.created_at
+	FROM status_changes
+	JOIN conversations ON conversations.id = status_changes.conversation_id
+	WHERE conversations.organization_id = @organization AND conversations.contact_id = @contact
+		AND conversations.deleted_at IS NULL
+		AND (@assignee = 0 OR conversations.organization_staff_id = @assignee)`
+
+type timelineRow struct {
+	Kind         string
+	ID           uint
+	OccurredAt   time.Time
+	OccurredUnix int64
+}
+
+// encodeTimelineCursor points after the row. Entries are ordered by the
+// second they happened, as stored, then by kind and id, since the ids of
+// different kinds come from different tables. The cursor keeps the stored
+// second so the next page continues exactly where this one stopped.
+func encodeTimelineCursor(row timelineRow) string {
+	raw := fmt.Sprintf("%d|%s|%d", row.OccurredUnix, row.Kind, row.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeTimelineCursor(cursor string) (*timelineRow, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrTimelineCursorInvalid
+	}
+
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 {
+		return nil, ErrTimelineCursorInvalid
+	}
+	seconds, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, ErrTimelineCursorInvalid
+	}
+	id, err := strconv.ParseUint(parts[2], 10, 64)
+	if err != nil {
+		return nil, ErrTimelineCursorInvalid
+	}
+
+	return &timelineRow{Kind: parts[1], ID: uint(id), OccurredUnix: seconds}, nil
+}
+
+// loadContactTimeline returns one page of the contact's history, newest
+// first, and the cursor of the next page. A non zero assigneeID limits it
+// to what is assigned to that staff member, see staffAssigneeID.
+func loadContactTimeline(db *gorm.DB, organizationID uint, contactID uint, assigneeID uint, cursor string, limit int) (*responsedto.ContactTimelineResponse, error) {
+	if limit < 1 || limit > contactTimelineMaxLimit {
+		limit = contactTimelineMaxLimit
+	}
+
+	params := map[string]any{
+		"organization": organizationID,
+		"contact":      contactID,
+		"assignee":     assigneeID,
+		"limit":        limit + 1,
+	}
+	where := ""
+	if cursor != "" {
+		after, err := decodeTimelineCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		where = "WHERE (UNIX_TIMESTAMP(timeline.occurred_at), timeline.kind, timeline.id) < (@at, @kind, @id)"
+		params["at"] = after.OccurredUnix
+		params["kind"] = after.Kind
+		params["id"] = after.ID
+	}
+
+	var rows []timelineRow
+	if err := db.Raw(`SELECT timeline.kind, timeline.id, timeline.occurred_at,
+			UNIX_TIMESTAMP(timeline.occurred_at) AS occurred_unix
+		FROM (`+contactTimelineQuery+`) AS timeline
+		`+where+`
+		ORDER BY timeline.occurred_at DESC, timeline.kind DESC, timeline.id DESC
+		LIMIT @limit`, params).
+		Scan(&rows).Error; err != nil {
+		return nil, errors.New("failed to fetch contact timeline")
+	}
+
+	response := &responsedto.ContactTimelineResponse{
+		Data: make([]responsedto.ContactTimelineEntry, 0, len(rows)),
+	}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		response.NextCursor = encodeTimelineCursor(rows[len(rows)-1])
+	}
+
+	ids := map[string][]uint{}
+	for _, row := range rows {
+		ids[row.Kind] = append(ids[row.Kind], row.ID)
+	}
+
+	conversations := map[uint]models.ConversationModel{}
+	messages := map[uint]models.ConversationMessageModel{}
+	tickets := map[uint]models.TicketModel{}
+	changes := map[uint]models.StatusChangeModel{}
+
+	if err := findTimelineRows(db, ids[timelineConversationCreated], conversations, func(m models.ConversationModel) uint { return m.ID }); err != nil {
+		return nil, err
+	}
+	if err := findTimelineRows(db.Preload("CreatedBy"), ids[timelineMessage], messages, func(m models.ConversationMessageModel) uint { return m.ID }); err != nil {
+		return nil, err
+	}
+	if err := findTimelineRows(db, ids[timelineTicketCreated], tickets, func(m models.TicketModel) uint { return m.ID }); err != nil {
+		return nil, err
+	}
+	if err := findTimelineRows(db.Preload("ChangedBy"), ids[timelineStatusChanged], changes, func(m models.StatusChangeModel) uint { return m.ID }); err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		entry := responsedto.ContactTimelineEntry{Type: row.Kind, OccurredAt: row.OccurredAt}
+
+		switch row.Kind {
+		case timelineConversationCreated:
+			conversation, ok := conversations[row.ID]
+			if !ok {
+				continue
+			}
+			entry.ConversationID = conversation.ID
+			entry.Conversation = &responsedto.TimelineConversation{
+				ID:      conversation.ID,
+				Channel: conversation.Channel,
+				Status:  conversation.Status,
+				Subject: conversation.Subject,
+			}
+		case timelineMessage:
+			message, ok := messages[row.ID]
+			if !ok {
+				continue
+			}
+			entry.ConversationID = message.ConversationID
+			entry.Message = &responsedto.TimelineMessage{
+				ID:          message.ID,
+				Message:     message.Message,
+				FromContact: message.ContactID != nil,
+				CreatedBy:   timelineUser(message.CreatedBy),
+			}
+		case timelineTicketCreated:
+			ticket, ok := tickets[row.ID]
+			if !ok {
+				continue
+			}
+			entry.ConversationID = ticket.ConversationID
+			entry.Ticket = &responsedto.TimelineTicket{
+				ID:           ticket.ID,
+				TicketNumber: ticket.TicketNumber,
+				Name:         ticket.Name,
+				Status:       ticket.Status,
+			}
+		case timelineStatusChanged:
+			change, ok := changes[row.ID]
+			if !ok {
+				continue
+			}
+			entry.ConversationID = change.ConversationID
+			entry.StatusChange = &responsedto.TimelineStatusChange{
+				SubjectType: change.SubjectType,
+				SubjectID:   change.SubjectID,
+				FromStatus:  change.FromStatus,
+				ToStatus:    change.ToStatus,
+				ChangedBy:   timelineUser(change.ChangedBy),
+			}
+		}
+
+		response.Data = append(response.Data, entry)
+	}
+
+	return response, nil
+}
+
+func findTimelineRows[T any](db *gorm.DB, ids []uint, into map[uint]T, key func(T) uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var rows []T
+	if err := db.Where("id IN ?", ids).Find(&rows).Error; err != nil {
+		return errors.New("failed to fetch contact timeline")
+	}
+	for _, row := range rows {
+		into[key(row)] = row
+	}
+	return nil
+}
+
+func timelineUser(user *models.UserModel) *responsedto.UserData {
+	if user == nil {
+		return nil
+	}
+	return &responsedto.UserData{
+		ID:    user.ID,
+		Email: user.Email,
+		Name:  user.Name,
+	}
+}
+
+// loadContactSummary counts the contact's conversations and open tickets
+// in the organization for the conversation detail. A non zero assigneeID
+// counts only what is assigned to that staff member, see staffAssigneeID.
+func loadContactSummary(db *gorm.DB, organizationID uint, contactID uint, assigneeID uint) (*responsedto.ContactSummaryData, error) {
+	summary := &responsedto.ContactSummaryData{}
+
+	conversations := func() *gorm.DB {
+		query := db.Model(&models.ConversationModel{}).
+			Where("organization_id = ? AND contact_id = ?", organizationID, contactID)
+		if assigneeID != 0 {
+			query = query.Where("organization_staff_id = ?", assigneeID)
+		}
+		return query
+	}
+
+	if err := conversations().Count(&summary.TotalConversations).Error; err != nil {
+		return nil, errors.New("failed to count contact conversations")
+	}
+	if err := conversations().
+		Where("status <> ?", models.ConversationStatusDone).
+		Count(&summary.OpenConversations).Error; err != nil {
+		return nil, errors.New("failed to count contact conversations")
+	}
+
+	// a ticket counts once, whether it was created from one of the
+	// conversations or covers one of them
+	contactConversations := db.Model(&models.ConversationModel{}).
+		Select("id").
+		Where("organization_id = ? AND contact_id = ?", organizationID, contactID)
+	tickets := db.Model(&models.TicketModel{}).
+		Where("organization_id = ?", organizationID).
+		Where("conversation_id IN (?) OR id IN (?)",
+			contactConversations,
+			db.Model(&models.TicketConversationModel{}).
+				Select("ticket_id").
+				Where("conversation_id IN (?)", contactConversations)).
+		Where("status_category = ?", models.TicketStatusCategoryOpen)
+	if assigneeID != 0 {
+		tickets = tickets.Where("assignee_id = ?", assigneeID)
+	}
+	if err := tickets.Count(&summary.OpenTickets).Error; err != nil {
+		return nil, errors.New("failed to count contact tickets")
+	}
+
+	var lastContactAt sql.NullTime
+	if err := db.Model(&models.ConversationMessageModel{}).
+		Select("MAX(created_at)").
+		Where("organization_id = ? AND contact_id = ?", organizationID, contactID).
+		Where("conversation_id IN (?)", conversations().Select("id")).
+		Row().Scan(&lastContactAt); err != nil {
+		return nil, errors.New("failed to fetch contact activity")
+	}
+	if lastContactAt.Valid {
+		summary.LastContactAt = &lastContactAt.Time
+	}
+
+	return summary, nil
+}
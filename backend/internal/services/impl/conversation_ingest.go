@@ -0,0 +1,153 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// inboundMessage is the channel agnostic shape every external channel
+// (webhook, email, ...) is converted to before it lands in a conversation.
+type inboundMessage struct {
+	OrganizationID uint
+	Channel        string
+	Email          string
+	Name           string
+	Phone          string
+	AvatarURL      *string
+	// Identities are extra channel identities of the sender, e.g. a whatsapp id
+	Identities []contactIdentity
+	// Attributes are custom contact attributes by key, invalid ones are skipped
+	Attributes        map[string]any
+	Subject           *string
+	Message           string
+	HTMLMessage       *string
+	ExternalMessageID *string
+	ContentHash       *string
+	// CallbackURL is where staff replies are delivered for webhook integrations,
+	// it is only stored on a new conversation and never replaced afterwards
+	CallbackURL *string
+	// ConversationID is set when the channel already resolved the thread, it
+	// is only used when the conversation belongs to the sender's contact
+	ConversationID *uint
+	// NewConversation skips reusing an open conversation of the same sender
+	NewConversation bool
+	Attachments     []models.ConversationMessageAttachmentModel
+}
+
+// ingestInboundMessage finds or creates the sender's contact and the
+// conversation and stores the message. It must run inside a transaction.
+func ingestInboundMessage(tx *gorm.DB, in inboundMessage) (*models.ConversationMessageModel, error) {
+	var organization models.OrganizationModel
+
+	if err := tx.Model(&models.OrganizationModel{}).
+		First(&organization, in.OrganizationID).Error; err != nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	identities := append([]contactIdentity{
+		newContactIdentity(models.ContactIdentityTypeEmail, in.Email),
+		newContactIdentity(models.ContactIdentityTypePhone, in.Phone),
+	}, in.Identities...)
+
+	contact, err := resolveContact(tx, organization.ID, in.Name, in.AvatarURL, identities)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(in.Attributes) > 0 {
+		if err := setContactAttributes(tx, organization.ID, contact.ID, in.Attributes, true); err != nil {
+			return nil, err
+		}
+	}
+
+	var conversation *models.ConversationModel
+	if in.ConversationID != nil {
+		conversation = &models.ConversationModel{}
+		if err := tx.Where("organization_id = ?", organization.ID).
+			First(conversation, *in.ConversationID).Error; err != nil {
+			return nil, errors.New("failed to find conversation")
+		}
+		// the thread headers are set by the sender, someone who learned a
+		// message id must not write into another customer's conversation
+		if conversation.ContactID != contact.ID {
+			conversation = nil
+		}
+	}
+	if conversation == nil {
+		conversation, err = findOrCreateConversation(tx, contact.ID, organization.ID, in)
+		if err != nil {
+			return nil, errors.New("failed to create or find conversation")
+		}
+	}
+
+	newMessages := models.ConversationMessageModel{
+		OrganizationID:    conversation.OrganizationID,
+		ContactID:         &contact.ID,
+		Message:           in.Message,
+		HTMLMessage:       in.HTMLMessage,
+		ExternalMessageID: in.ExternalMessageID,
+		ContentHash:       in.ContentHash,
+		ConversationID:    conversation.ID,
+	}
+
+	if err := tx.Omit("Attachments").Create(&newMessages).Error; err != nil {
+		return nil, errors.New("failed to create message")
+	}
+
+	if err := publishEvent(tx, newMessages.OrganizationID, models.EventMessageCreated, messageEvent(&newMessages)); err != nil {
+		return nil, err
+	}
+
+	for i := range in.Attachments {
+		attachment := in.Attachments[i]
+		attachment.OrganizationID = conversation.OrganizationID
+		attachment.MessageID = newMessages.ID
+		if err := tx.Create(&attachment).Error; err != nil {
+			return nil, errors.New("failed to store attachment")
+		}
+		newMessages.Attachments = append(newMessages.Attachments, attachment)
+	}
+
+	return &newMessages, nil
+}
+
+func findOrCreateConversation(tx *gorm.DB, contactId uint, organizationId uint, in inboundMessage) (*models.ConversationModel, error) {
+	var conversation models.ConversationModel
+
+	err := gorm.ErrRecordNotFound
+	if !in.NewConversation {
+		err = tx.
+			Where("contact_id = ?", contactId).
+			Where("organization_id = ?", organizationId).
+			Where("channel = ?", in.Channel).
+			Where("status != ?", models.ConversationStatusDone).
+			First(&conversation).Error
+	}
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			conversation = models.ConversationModel{
+				OrganizationID: organizationId,
+				ContactID:      contactId,
+				Status:         models.ConversationStatusPending,
+				Channel:        in.Channel,
+				Subject:        in.Subject,
+				CallbackURL:    in.CallbackURL,
+			}
+
+			if err := tx.Create(&conversation).Error; err != nil {
+				return nil, err
+			}
+
+			if err := publishEvent(tx, organizationId, models.EventConversationCreated, conversationEvent(&conversation, nil)); err != nil {
+				return nil, err
+			}
+		} else {
+			return nil, err
+		}
+	}
+
+	return &conversation, nil
+}
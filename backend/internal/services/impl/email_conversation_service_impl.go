@@ -0,0 +1,157 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/pkg/lib/mail"
+	"DewaSRY/sociomile-app/pkg/models"
+	"bytes"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type emailConversationServiceImpl struct {
+	db *gorm.DB
+}
+
+// AcceptRecipient implements mail.InboundHandler.
+func (t *emailConversationServiceImpl) AcceptRecipient(address string) bool {
+	_, err := t.findOrganizationByInboundEmail(t.db, address)
+	return err == nil
+}
+
+// HandleEnvelope implements mail.InboundHandler. Every recipient is stored
+// in one transaction, a failure leaves nothing behind for the retry of the
+// sending MTA to duplicate.
+func (t *emailConversationServiceImpl) HandleEnvelope(envelope mail.Envelope) error {
+	inbound, err := mail.ParseInboundMail(bytes.NewReader(envelope.Data))
+	if err != nil {
+		return err
+	}
+
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		for _, recipient := range envelope.Recipients {
+			if err := t.processInboundEmail(tx, recipient, inbound); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ProcessInboundEmail implements services.EmailConversationService.
+func (t *emailConversationServiceImpl) ProcessInboundEmail(recipient string, inbound *mail.InboundMail) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		return t.processInboundEmail(tx, recipient, inbound)
+	})
+}
+
+func (t *emailConversationServiceImpl) processInboundEmail(tx *gorm.DB, recipient string, inbound *mail.InboundMail) error {
+	organization, err := t.findOrganizationByInboundEmail(tx, recipient)
+	if err != nil {
+		return err
+	}
+
+	// mta retries deliver the same mail again, keep the ingest idempotent.
+	// The Message-ID names it, a mail without one is known by its content.
+	duplicate := tx.Model(&models.ConversationMessageModel{}).
+		Where("organization_id = ?", organization.ID)
+	switch {
+	case inbound.MessageID != "":
+		duplicate = duplicate.Where("external_message_id = ?", inbound.MessageID)
+	case inbound.ContentHash != "":
+		duplicate = duplicate.Where("content_hash = ?", inbound.ContentHash)
+	default:
+		duplicate = nil
+	}
+	if duplicate != nil {
+		var count int64
+		if err := duplicate.Count(&count).Error; err != nil {
+			return errors.New("failed to check message")
+		}
+		if count > 0 {
+			return nil
+		}
+	}
+
+	in := inboundMessage{
+		OrganizationID:  organization.ID,
+		Channel:         models.ConversationChannelEmail,
+		Email:           inbound.FromAddress,
+		Name:            inbound.FromName,
+		Message:         inbound.Text,
+		NewConversation: true,
+	}
+	if inbound.Subject != "" {
+		in.Subject = &inbound.Subject
+	}
+	if inbound.HTML != "" {
+		in.HTMLMessage = &inbound.HTML
+	}
+	if inbound.MessageID != "" {
+		in.ExternalMessageID = &inbound.MessageID
+	} else if inbound.ContentHash != "" {
+		in.ContentHash = &inbound.ContentHash
+	}
+	if in.Message == "" {
+		in.Message = "(no text content)"
+	}
+	for _, attachment := range inbound.Attachments {
+		in.Attachments = append(in.Attachments, models.ConversationMessageAttachmentModel{
+			FileName:    attachment.FileName,
+			ContentType: attachment.ContentType,
+			Size:        len(attachment.Content),
+			Content:     attachment.Content,
+		})
+	}
+
+	conversationID, err := t.findThreadConversation(tx, organization.ID, inbound)
+	if err != nil {
+		return err
+	}
+	in.ConversationID = conversationID
+
+	_, err = ingestInboundMessage(tx, in)
+	return err
+}
+
+// findThreadConversation resolves the conversation a reply belongs to using
+// the In-Reply-To and References headers, ingestInboundMessage only keeps it
+// when it belongs to the sender.
+func (t *emailConversationServiceImpl) findThreadConversation(tx *gorm.DB, organizationID uint, inbound *mail.InboundMail) (*uint, error) {
+	threadIDs := append([]string{}, inbound.References...)
+	if inbound.InReplyTo != "" {
+		threadIDs = append(threadIDs, inbound.InReplyTo)
+	}
+	if len(threadIDs) == 0 {
+		return nil, nil
+	}
+
+	var message models.ConversationMessageModel
+	err := tx.Where("organization_id = ?", organizationID).
+		Where("external_message_id IN ?", threadIDs).
+		Order("created_at DESC").
+		First(&message).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errors.New("failed to find email thread")
+	}
+
+	return &message.ConversationID, nil
+}
+
+func (t *emailConversationServiceImpl) findOrganizationByInboundEmail(tx *gorm.DB, address string) (*models.OrganizationModel, error) {
+	var organization models.OrganizationModel
+	if err := tx.Where("inbound_email = ?", strings.ToLower(address)).
+		First(&organization).Error; err != nil {
+		return nil, ErrOrganizationNotFound
+	}
+	return &organization, nil
+}
+
+func NewEmailConversationService(db *gorm.DB) services.EmailConversationService {
+	return &emailConversationServiceImpl{db: db}
+}
@@ -0,0 +1,107 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/pkg/dtos/eventdto"
+	"DewaSRY/sociomile-app/pkg/models"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// publishEvent fans an event out to every active subscription of the
+// organization. Deliveries are written with the caller's transaction so an
+// event is only sent when the change that produced it was committed.
+func publishEvent(tx *gorm.DB, organizationID uint, eventType string, data any) error {
+	var subscriptions []models.EventSubscriptionModel
+	if err := tx.Where("organization_id = ?", organizationID).
+		Where("active = ?", true).
+		Where("JSON_CONTAINS(event_types, JSON_QUOTE(?))", eventType).
+		Find(&subscriptions).Error; err != nil {
+		return errors.New("failed to load event subscriptions")
+	}
+
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return errors.New("failed to create event id")
+	}
+
+	now := time.Now()
+	envelope := eventdto.Envelope{
+		ID:             "evt_" + hex.EncodeToString(id),
+		Type:           eventType,
+		Version:        models.EventPayloadVersion,
+		OrganizationID: organizationID,
+		OccurredAt:     now,
+		Data:           data,
+	}
+
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return errors.New("failed to encode event")
+	}
+
+	deliveries := make([]models.EventDeliveryModel, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		deliveries = append(deliveries, models.EventDeliveryModel{
+			OrganizationID: organizationID,
+			SubscriptionID: subscription.ID,
+			EventID:        envelope.ID,
+			EventType:      eventType,
+			Payload:        payload,
+			Status:         models.EventDeliveryStatusPending,
+			NextAttemptAt:  now,
+		})
+	}
+
+	if err := tx.Create(&deliveries).Error; err != nil {
+		return errors.New("failed to queue event deliveries")
+	}
+
+	return nil
+}
+
+func conversationEvent(conversation *models.ConversationModel, previousStatus *string) eventdto.ConversationEvent {
+	return eventdto.ConversationEvent{
+		ID:                  conversation.ID,
+		ContactID:           conversation.ContactID,
+		GuestID:             conversation.GuestID,
+		OrganizationStaffID: conversation.OrganizationStaffID,
+		Channel:             conversation.Channel,
+		Status:              conversation.Status,
+		PreviousStatus:      previousStatus,
+	}
+}
+
+func messageEvent(message *models.ConversationMessageModel) eventdto.MessageEvent {
+	return eventdto.MessageEvent{
+		ID:             message.ID,
+		ConversationID: message.ConversationID,
+		CreatedByID:    message.CreatedByID,
+		ContactID:      message.ContactID,
+		Message:        message.Message,
+		CreatedAt:      message.CreatedAt,
+	}
+}
+
+func ticketEvent(ticket *models.TicketModel, previousStatus *string) eventdto.TicketEvent {
+	return eventdto.TicketEvent{
+		ID:             ticket.ID,
+		ConversationID: ticket.ConversationID,
+		TicketNumber:   ticket.TicketNumber,
+		Name:           ticket.Name,
+		Status:         ticket.Status,
+		PreviousStatus: previousStatus,
+		AssigneeID:     ticket.AssigneeID,
+		Priority:       ticket.Priority,
+		DueAt:          ticket.DueAt,
+		Category:       ticket.Category,
+	}
+}
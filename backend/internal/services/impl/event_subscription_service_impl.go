@@ -0,0 +1,352 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/lib/outbound"
+	"DewaSRY/sociomile-app/pkg/models"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrSubscriptionNotFound  = errors.New("event subscription not found")
+	ErrEventDeliveryNotFound = errors.New("event delivery not found")
+)
+
+type eventSubscriptionServiceImpl struct {
+	db          *gorm.DB
+	sender      outbound.WebhookSender
+	maxAttempts int
+}
+
+// CreateSubscription implements services.EventSubscriptionService.
+func (t *eventSubscriptionServiceImpl) CreateSubscription(user *jwt.Claims, req requestdto.CreateEventSubscriptionRequest) (*responsedto.EventSubscriptionResponse, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, errors.New("failed to create subscription secret")
+	}
+
+	subscription := models.EventSubscriptionModel{
+		OrganizationID: *user.OrganizationId,
+		URL:            req.URL,
+		Secret:         "whsec_" + hex.EncodeToString(secret),
+		EventTypes:     uniqueEventTypes(req.EventTypes),
+		Active:         true,
+	}
+
+	if err := t.db.Create(&subscription).Error; err != nil {
+		return nil, errors.New("failed to create event subscription")
+	}
+
+	response := t.mapToSubscriptionResponse(&subscription)
+	response.Secret = subscription.Secret
+	return response, nil
+}
+
+// GetSubscriptions implements services.EventSubscriptionService.
+func (t *eventSubscriptionServiceImpl) GetSubscriptions(user *jwt.Claims) (*responsedto.EventSubscriptionListResponse, error) {
+	var subscriptions []models.EventSubscriptionModel
+	if err := t.db.Scopes(organizationScope(user)).
+		Order("created_at DESC").
+		Find(&subscriptions).Error; err != nil {
+		return nil, errors.New("failed to fetch event subscriptions")
+	}
+
+	data := make([]responsedto.EventSubscriptionResponse, 0, len(subscriptions))
+	for i := range subscriptions {
+		data = append(data, *t.mapToSubscriptionResponse(&subscriptions[i]))
+	}
+
+	return &responsedto.EventSubscriptionListResponse{Data: data}, nil
+}
+
+// UpdateSubscription implements services.EventSubscriptionService.
+func (t *eventSubscriptionServiceImpl) UpdateSubscription(user *jwt.Claims, subscriptionID uint, req requestdto.UpdateEventSubscriptionRequest) (*responsedto.EventSubscriptionResponse, error) {
+	subscription, err := t.findSubscription(user, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != "" {
+		subscription.URL = req.URL
+	}
+	if len(req.EventTypes) > 0 {
+		subscription.EventTypes = uniqueEventTypes(req.EventTypes)
+	}
+	if req.Active != nil {
+		subscription.Active = *req.Active
+	}
+
+	if err := t.db.Save(subscription).Error; err != nil {
+		return nil, errors.New("failed to update event subscription")
+	}
+
+	return t.mapToSubscriptionResponse(subscription), nil
+}
+
+// DeleteSubscription implements services.EventSubscriptionService.
+func (t *eventSubscriptionServiceImpl) DeleteSubscription(user *jwt.Claims, subscriptionID uint) error {
+	subscription, err := t.findSubscription(user, subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		// pending deliveries of a removed endpoint must not be sent anymore
+		if err := tx.Model(&models.EventDeliveryModel{}).
+			Where("subscription_id = ?", subscription.ID).
+			Where("status IN ?", []string{models.EventDeliveryStatusPending, models.EventDeliveryStatusFailed}).
+			Updates(map[string]any{
+				"status":     models.EventDeliveryStatusDeadLetter,
+				"last_error": "subscription deleted",
+			}).Error; err != nil {
+			return errors.New("failed to cancel pending deliveries")
+		}
+
+		if err := tx.Delete(subscription).Error; err != nil {
+			return errors.New("failed to delete event subscription")
+		}
+		return nil
+	})
+}
+
+// GetDeliveries implements services.EventSubscriptionService.
+func (t *eventSubscriptionServiceImpl) GetDeliveries(user *jwt.Claims, subscriptionID uint, filter filtersdto.FiltersDto) (*responsedto.EventDeliveryPaginateResponse, error) {
+	if _, err := t.findSubscription(user, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	var deliveries []models.EventDeliveryModel
+	var total int64
+	offset := (*filter.Page - 1) * *filter.Limit
+
+	if err := t.db.Model(&models.EventDeliveryModel{}).
+		Where("subscription_id = ?", subscriptionID).
+		Count(&total).Error; err != nil {
+		return nil, errors.New("failed to count event deliveries")
+	}
+
+	if err := t.db.Where("subscription_id = ?", subscriptionID).
+		Offset(offset).Limit(*filter.Limit).
+		Order("created_at DESC").
+		Find(&deliveries).Error; err != nil {
+		return nil, errors.New("failed to fetch event deliveries")
+	}
+
+	data := make([]responsedto.EventDeliveryResponse, 0, len(deliveries))
+	for i := range deliveries {
+		data = append(data, *t.mapToDeliveryResponse(&deliveries[i]))
+	}
+
+	return &responsedto.EventDeliveryPaginateResponse{
+		Data: data,
+		Metadata: responsedto.PaginateMetaData{
+			Total: int(total),
+			Page:  *filter.Page,
+			Limit: *filter.Limit,
+		},
+	}, nil
+}
+
+// ReplayDelivery implements services.EventSubscriptionService.
+func (t *eventSubscriptionServiceImpl) ReplayDelivery(user *jwt.Claims, subscriptionID uint, deliveryID uint) (*responsedto.EventDeliveryResponse, error) {
+	if _, err := t.findSubscription(user, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	var original models.EventDeliveryModel
+	if err := t.db.Where("subscription_id = ?", subscriptionID).
+		First(&original, deliveryID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEventDeliveryNotFound
+		}
+		return nil, errors.New("failed to fetch event delivery")
+	}
+
+	// the replay keeps the event id so receivers can deduplicate it
+	replay := models.EventDeliveryModel{
+		OrganizationID: original.OrganizationID,
+		SubscriptionID: original.SubscriptionID,
+		EventID:        original.EventID,
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		Status:         models.EventDeliveryStatusPending,
+		NextAttemptAt:  time.Now(),
+		ReplayOfID:     &original.ID,
+	}
+
+	if err := t.db.Create(&replay).Error; err != nil {
+		return nil, errors.New("failed to replay event delivery")
+	}
+
+	return t.mapToDeliveryResponse(&replay), nil
+}
+
+// ProcessDueDeliveries implements services.EventSubscriptionService.
+func (t *eventSubscriptionServiceImpl) ProcessDueDeliveries(limit int) (int, error) {
+	var due []models.EventDeliveryModel
+	now := time.Now()
+
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ?", []string{models.EventDeliveryStatusPending, models.EventDeliveryStatusFailed}).
+			Where("next_attempt_at <= ?", now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&due).Error; err != nil {
+			return err
+		}
+
+		if len(due) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(due))
+		for _, delivery := range due {
+			ids = append(ids, delivery.ID)
+		}
+
+		return tx.Model(&models.EventDeliveryModel{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(outboundClaimLease)).Error
+	})
+	if err != nil {
+		return 0, errors.New("failed to claim event deliveries")
+	}
+
+	for i := range due {
+		t.deliver(&due[i])
+	}
+
+	return len(due), nil
+}
+
+func (t *eventSubscriptionServiceImpl) deliver(delivery *models.EventDeliveryModel) {
+	var subscription models.EventSubscriptionModel
+	err := t.db.First(&subscription, delivery.SubscriptionID).Error
+
+	var code int
+	switch {
+	case err != nil:
+		err = errors.New("subscription no longer exists")
+	case !subscription.Active:
+		err = errors.New("subscription is disabled")
+	default:
+		code, err = t.sender.Send(outbound.SignedRequest{
+			URL:    subscription.URL,
+			Secret: subscription.Secret,
+			Headers: map[string]string{
+				"X-Sociomile-Event":         delivery.EventType,
+				"X-Sociomile-Event-Id":      delivery.EventID,
+				"X-Sociomile-Event-Version": models.EventPayloadVersion,
+				"X-Sociomile-Delivery":      fmt.Sprint(delivery.ID),
+			},
+			Body: delivery.Payload,
+		})
+	}
+
+	delivery.Attempts++
+	if code != 0 {
+		delivery.ResponseCode = &code
+	}
+
+	if err == nil {
+		now := time.Now()
+		delivery.Status = models.EventDeliveryStatusSucceeded
+		delivery.DeliveredAt = &now
+		delivery.LastError = nil
+	} else {
+		reason := err.Error()
+		delivery.LastError = &reason
+		if delivery.Attempts >= t.maxAttempts {
+			delivery.Status = models.EventDeliveryStatusDeadLetter
+		} else {
+			delivery.Status = models.EventDeliveryStatusFailed
+			delivery.NextAttemptAt = time.Now().Add(outboundBackoff(delivery.Attempts))
+		}
+	}
+
+	if err := t.db.Save(delivery).Error; err != nil {
+		logger.ErrorLog("Failed to update event delivery", map[string]any{
+			"delivery_id": delivery.ID,
+			"error":       err.Error(),
+		})
+	}
+}
+
+func (t *eventSubscriptionServiceImpl) findSubscription(user *jwt.Claims, subscriptionID uint) (*models.EventSubscriptionModel, error) {
+	var subscription models.EventSubscriptionModel
+	if err := t.db.Scopes(organizationScope(user)).
+		First(&subscription, subscriptionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, errors.New("failed to fetch event subscription")
+	}
+	return &subscription, nil
+}
+
+func (t *eventSubscriptionServiceImpl) mapToSubscriptionResponse(subscription *models.EventSubscriptionModel) *responsedto.EventSubscriptionResponse {
+	return &responsedto.EventSubscriptionResponse{
+		ID:             subscription.ID,
+		OrganizationID: subscription.OrganizationID,
+		URL:            subscription.URL,
+		EventTypes:     subscription.EventTypes,
+		Active:         subscription.Active,
+		CreatedAt:      subscription.CreatedAt,
+		UpdatedAt:      subscription.UpdatedAt,
+	}
+}
+
+func (t *eventSubscriptionServiceImpl) mapToDeliveryResponse(delivery *models.EventDeliveryModel) *responsedto.EventDeliveryResponse {
+	return &responsedto.EventDeliveryResponse{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		ResponseCode:   delivery.ResponseCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		ReplayOfID:     delivery.ReplayOfID,
+		Payload:        delivery.Payload,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
+
+func uniqueEventTypes(eventTypes []string) []string {
+	seen := map[string]bool{}
+	result := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !seen[eventType] {
+			seen[eventType] = true
+			result = append(result, eventType)
+		}
+	}
+	return result
+}
+
+func NewEventSubscriptionService(db *gorm.DB, sender outbound.WebhookSender, maxAttempts int) services.EventSubscriptionService {
+	return &eventSubscriptionServiceImpl{
+		db:          db,
+		sender:      sender,
+		maxAttempts: maxAttempts,
+	}
+}
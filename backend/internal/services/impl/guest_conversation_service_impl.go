@@ -12,6 +12,8 @@ import (
 	"gorm.io/gorm"
 )
 
+var ErrGuestConversationNotFound = errors.New("conversation not found")
+
 type guestConversationServiceImpl struct {
 	db *gorm.DB
 }
@@ -26,17 +28,31 @@ func (t *guestConversationServiceImpl) CreateConversation(user *jwt.Claims, req
 		return errors.New("failed to fetch organization")
 	}
 
-	conversation := models.ConversationModel{
-		OrganizationID: req.OrganizationID,
-		GuestID:        user.UserID,
-		Status:         models.ConversationStatusPending,
+	var guest models.UserModel
+	if err := t.db.First(&guest, user.UserID).Error; err != nil {
+		return errors.New("failed to fetch user")
 	}
 
-	if err := t.db.Create(&conversation).Error; err != nil {
-		return errors.New("failed to create conversation")
-	}
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		contact, err := findOrCreateUserContact(tx, organization.ID, &guest, false)
+		if err != nil {
+			return err
+		}
+
+		conversation := models.ConversationModel{
+			OrganizationID: req.OrganizationID,
+			GuestID:        &guest.ID,
+			ContactID:      contact.ID,
+			Status:         models.ConversationStatusPending,
+			Channel:        models.ConversationChannelWeb,
+		}
+
+		if err := tx.Create(&conversation).Error; err != nil {
+			return errors.New("failed to create conversation")
+		}
 
-	return nil
+		return publishEvent(tx, conversation.OrganizationID, models.EventConversationCreated, conversationEvent(&conversation, nil))
+	})
 }
 
 // GetConversation implements services.GuestConversationService.
@@ -45,15 +61,16 @@ func (t *guestConversationServiceImpl) GetConversation(user *jwt.Claims, filter
 	var total int64
 	offset := (*filter.Page - 1) * *filter.Limit
 
+	// conversations of every contact linked to the guest are theirs as well
 	if err := t.db.Model(&models.ConversationModel{}).
-		Where("guest_id = ?", user.UserID).
+		Scopes(guestConversationScope(user)).
 		Count(&total).Error; err != nil {
 		return nil, errors.New("failed to count organizations")
 	}
 
-	if err := t.db.Where("guest_id = ?", user.UserID).
+	if err := t.db.Scopes(guestConversationScope(user)).
 		Offset(offset).Limit(*filter.Limit).
-		Preload("Organization").Preload("Guest").
+		Preload("Organization").Preload("Guest").Preload("Contact.Identities").
 		Order("created_at DESC").
 		Find(&conversations).Error; err != nil {
 		return nil, errors.New("failed to fetch conversations")
@@ -83,7 +100,11 @@ func (t *guestConversationServiceImpl) mapToConversationResponse(conv *models.Co
 		ID:             conv.ID,
 		OrganizationID: conv.OrganizationID,
 		GuestID:        conv.GuestID,
+		ContactID:      conv.ContactID,
+		Contact:        mapToContactData(conv.Contact),
 		Status:         conv.Status,
+		Channel:        conv.Channel,
+		Subject:        conv.Subject,
 		CreatedAt:      conv.CreatedAt,
 		UpdatedAt:      conv.UpdatedAt,
 	}
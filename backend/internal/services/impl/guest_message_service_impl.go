@@ -21,6 +21,17 @@ func (t *guestMessageServiceImpl) GetConversationMessageList(user *jwt.Claims, f
 	var messages []models.ConversationMessageModel
 	var total int64
 	offset := (*filter.Page - 1) * *filter.Limit
+
+	var conversation models.ConversationModel
+	if err := t.db.Scopes(guestConversationScope(user)).
+		Select("id").
+		First(&conversation, conversationId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGuestConversationNotFound
+		}
+		return nil, errors.New("failed to fetch conversation")
+	}
+
 	if err := t.db.Model(&models.ConversationMessageModel{}).
 		Where("conversation_id = ?", conversationId).
 		Count(&total).Error; err != nil {
@@ -31,6 +42,10 @@ func (t *guestMessageServiceImpl) GetConversationMessageList(user *jwt.Claims, f
 		Offset(offset).
 		Limit(*filter.Limit).
 		Preload("CreatedBy").
+		Preload("Contact.Identities").
+		Preload("Attachments", func(db *gorm.DB) *gorm.DB {
+			return db.Omit("Content")
+		}).
 		Order("created_at ASC").
 		Find(&messages).Error; err != nil {
 		return nil, errors.New("failed to fetch messages")
@@ -54,25 +69,28 @@ func (t *guestMessageServiceImpl) GetConversationMessageList(user *jwt.Claims, f
 // SendConversationMessage implements services.GuestMessageService.
 func (t *guestMessageServiceImpl) SendConversationMessage(user *jwt.Claims, req requestdto.CreateConversationMessageRequest) error {
 	var conversation models.ConversationModel
-	if err := t.db.First(&conversation, req.ConversationID).Error; err != nil {
+	if err := t.db.Scopes(guestConversationScope(user)).First(&conversation, req.ConversationID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("conversation not found")
+			return ErrGuestConversationNotFound
 		}
 		return errors.New("failed to fetch organization")
 	}
 
 	newMessages := models.ConversationMessageModel{
 		OrganizationID: conversation.OrganizationID,
-		CreatedByID:    user.UserID,
+		CreatedByID:    &user.UserID,
+		ContactID:      &conversation.ContactID,
 		Message:        req.Message,
 		ConversationID: conversation.ID,
 	}
-	
-	if err := t.db.Create(&newMessages).Error; err != nil {
-		return errors.New("failed to create message")
-	}
 
-	return nil
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newMessages).Error; err != nil {
+			return errors.New("failed to create message")
+		}
+
+		return publishEvent(tx, newMessages.OrganizationID, models.EventMessageCreated, messageEvent(&newMessages))
+	})
 }
 
 func (t *guestMessageServiceImpl) mapToMessageResponse(msg *models.ConversationMessageModel) *responsedto.ConversationMessageResponse {
@@ -81,7 +99,10 @@ func (t *guestMessageServiceImpl) mapToMessageResponse(msg *models.ConversationM
 		OrganizationID: msg.OrganizationID,
 		ConversationID: msg.ConversationID,
 		CreatedByID:    msg.CreatedByID,
+		ContactID:      msg.ContactID,
+		Contact:        mapToContactData(msg.Contact),
 		Message:        msg.Message,
+		HTMLMessage:    msg.HTMLMessage,
 		CreatedAt:      msg.CreatedAt,
 		UpdatedAt:      msg.UpdatedAt,
 	}
@@ -94,6 +115,15 @@ func (t *guestMessageServiceImpl) mapToMessageResponse(msg *models.ConversationM
 		}
 	}
 
+	for _, attachment := range msg.Attachments {
+		response.Attachments = append(response.Attachments, responsedto.AttachmentResponse{
+			ID:          attachment.ID,
+			FileName:    attachment.FileName,
+			ContentType: attachment.ContentType,
+			Size:        attachment.Size,
+		})
+	}
+
 	return response
 }
 
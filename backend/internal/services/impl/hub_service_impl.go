@@ -7,6 +7,7 @@ import (
 	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
 	"DewaSRY/sociomile-app/pkg/models"
 	"errors"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -41,9 +42,26 @@ func (t *hubServiceImpl) CreateOrganization(req requestdto.RegisterOrganizationR
 			return errors.New("failed to create user")
 		}
 
+		slug, err := uniqueOrganizationSlug(tx, req.Name)
+		if err != nil {
+			return err
+		}
+
 		organization := models.OrganizationModel{
 			Name:    req.Name,
 			OwnerID: userOwner.ID,
+			Slug:    &slug,
+		}
+
+		if req.InboundEmail != "" {
+			inboundEmail := strings.ToLower(req.InboundEmail)
+			var count int64
+			if err := tx.Model(&models.OrganizationModel{}).
+				Where("inbound_email = ?", inboundEmail).
+				Count(&count).Error; err != nil || count > 0 {
+				return errors.New("inbound email already used by another organization")
+			}
+			organization.InboundEmail = &inboundEmail
 		}
 
 		if err := tx.Create(&organization).Error; err != nil {
@@ -96,10 +114,11 @@ func (t *hubServiceImpl) GetOrganizationPagination(filter filtersdto.FiltersDto)
 
 func (t *hubServiceImpl) mapToOrganizationResponse(org *models.OrganizationModel) *responsedto.HubOrganizationRecord {
 	response := &responsedto.HubOrganizationRecord{
-		ID:        org.ID,
-		Name:      org.Name,
-		CreatedAt: org.CreatedAt,
-		UpdatedAt: org.UpdatedAt,
+		ID:           org.ID,
+		Name:         org.Name,
+		InboundEmail: org.InboundEmail,
+		CreatedAt:    org.CreatedAt,
+		UpdatedAt:    org.UpdatedAt,
 	}
 
 	if org.Owner != nil {
@@ -0,0 +1,330 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/lib/mail"
+	"DewaSRY/sociomile-app/pkg/models"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrMagicLinkInvalid = errors.New("magic link is invalid or expired")
+
+// MagicLinkOptions configure the login links, URL is the portal page that
+// reads the token from the query string.
+type MagicLinkOptions struct {
+	URL     string
+	TTL     time.Duration
+	PerHour int
+}
+
+type magicLinkServiceImpl struct {
+	db         *gorm.DB
+	jwtService jwtLib.JwtService
+	mailer     mail.Mailer
+	options    MagicLinkOptions
+}
+
+// RequestLink implements services.MagicLinkService. Every request gets the
+// same answer, whether the address is unknown, belongs to staff or asked
+// too often, so the endpoint does not tell who is a customer. The mail
+// goes out in the background for the same reason, a known address answers
+// as fast as an unknown one.
+func (t *magicLinkServiceImpl) RequestLink(req requestdto.MagicLinkRequest) error {
+	email := newContactIdentity(models.ContactIdentityTypeEmail, req.Email).Value
+
+	if t.options.PerHour > 0 {
+		allowed, err := t.allowRequest(email)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			logger.InfoLog("Magic link rate limited", map[string]any{
+				"email": email,
+			})
+			return nil
+		}
+	}
+
+	known, err := t.isKnownEmail(email)
+	if err != nil {
+		return err
+	}
+	if !known {
+		logger.InfoLog("Magic link requested for unknown email", map[string]any{
+			"email": email,
+		})
+		return nil
+	}
+
+	token, err := newSecretToken()
+	if err != nil {
+		return err
+	}
+
+	link := models.MagicLinkTokenModel{
+		Email:     email,
+		TokenHash: hashSecretToken(token),
+		ExpiresAt: time.Now().Add(t.options.TTL),
+	}
+	if err := t.db.Create(&link).Error; err != nil {
+		return errors.New("failed to create magic link")
+	}
+
+	go t.sendLink(link, token)
+
+	return nil
+}
+
+// allowRequest records a request of the address unless it already asked
+// PerHour times in the last hour. Unknown addresses are counted the same
+// way, the limit does not depend on who is a customer.
+func (t *magicLinkServiceImpl) allowRequest(email string) (bool, error) {
+	since := time.Now().Add(-time.Hour)
+
+	if err := t.db.Where("email = ? AND created_at <= ?", email, since).
+		Delete(&models.MagicLinkRequestModel{}).Error; err != nil {
+		return false, errors.New("failed to count magic link requests")
+	}
+
+	var recent int64
+	if err := t.db.Model(&models.MagicLinkRequestModel{}).
+		Where("email = ? AND created_at > ?", email, since).
+		Count(&recent).Error; err != nil {
+		return false, errors.New("failed to count magic link requests")
+	}
+	if recent >= int64(t.options.PerHour) {
+		return false, nil
+	}
+
+	if err := t.db.Create(&models.MagicLinkRequestModel{Email: email}).Error; err != nil {
+		return false, errors.New("failed to record magic link request")
+	}
+	return true, nil
+}
+
+// sendLink mails the stored link, a link that could not be sent is spent
+// right away so it never works.
+func (t *magicLinkServiceImpl) sendLink(link models.MagicLinkTokenModel, token string) {
+	if err := t.mailer.Send(mail.OutgoingMail{
+		To:      []string{link.Email},
+		Subject: "Your sign-in link",
+		Text: fmt.Sprintf(
+			"Open this link to sign in and follow up on your conversations:\n\n%s\n\nThe link works once and expires in %s. If you did not ask for it, ignore this mail.\n",
+			t.options.URL+"?token="+url.QueryEscape(token), t.options.TTL,
+		),
+	}); err != nil {
+		logger.ErrorLog("Failed to send magic link", map[string]any{
+			"email": link.Email,
+			"error": err.Error(),
+		})
+		if err := t.db.Model(&link).Update("used_at", time.Now()).Error; err != nil {
+			logger.ErrorLog("Failed to spend unsent magic link", map[string]any{
+				"email": link.Email,
+				"error": err.Error(),
+			})
+		}
+	}
+}
+
+// VerifyLink implements services.MagicLinkService. The token is spent
+// together with every other open link of the address, the customer gets a
+// guest account on first use and is linked to their contacts.
+func (t *magicLinkServiceImpl) VerifyLink(req requestdto.VerifyMagicLinkRequest) (*responsedto.AuthResponse, error) {
+	var user models.UserModel
+
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		var link models.MagicLinkTokenModel
+		if err := tx.Where("token_hash = ?", hashSecretToken(strings.TrimSpace(req.Token))).
+			First(&link).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrMagicLinkInvalid
+			}
+			return errors.New("failed to fetch magic link")
+		}
+
+		now := time.Now()
+		if link.UsedAt != nil || !now.Before(link.ExpiresAt) {
+			return ErrMagicLinkInvalid
+		}
+
+		// the used_at condition makes a concurrent second use lose
+		spent := tx.Model(&models.MagicLinkTokenModel{}).
+			Where("id = ? AND used_at IS NULL", link.ID).
+			Update("used_at", now)
+		if spent.Error != nil {
+			return errors.New("failed to use magic link")
+		}
+		if spent.RowsAffected == 0 {
+			return ErrMagicLinkInvalid
+		}
+
+		if err := tx.Model(&models.MagicLinkTokenModel{}).
+			Where("email = ? AND used_at IS NULL", link.Email).
+			Update("used_at", now).Error; err != nil {
+			return errors.New("failed to use magic link")
+		}
+
+		found, err := t.findOrCreateUser(tx, link.Email)
+		if err != nil {
+			return err
+		}
+		user = *found
+
+		return t.linkContacts(tx, &user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.db.
+		Preload("Role").
+		Preload("Organization").
+		First(&user, user.ID).Error; err != nil {
+		return nil, errors.New("failed to load user relations")
+	}
+
+	token, err := t.jwtService.GenerateToken(user.ID, user.Email, user.RoleID, user.OrganizationID)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	return &responsedto.AuthResponse{
+		Token: token,
+		User:  mapToUserProfile(&user),
+	}, nil
+}
+
+// isKnownEmail tells whether the address belongs to a guest or to a contact
+// of any organization. Staff sign in with their password only, a link
+// would hand out their token to whoever reads the mailbox.
+func (t *magicLinkServiceImpl) isKnownEmail(email string) (bool, error) {
+	var user models.UserModel
+	err := t.db.Select("id", "role_id").Where("email = ?", email).First(&user).Error
+	if err == nil {
+		return t.isGuest(t.db, &user)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, errors.New("failed to fetch user")
+	}
+
+	var contacts int64
+	if err := t.contactIdentities(t.db, email).
+		Count(&contacts).Error; err != nil {
+		return false, errors.New("failed to fetch contact")
+	}
+	return contacts > 0, nil
+}
+
+// findOrCreateUser returns the user of the address, a customer who only
+// exists as a contact gets a guest account named after that contact. The
+// password is random, the customer keeps signing in by link.
+func (t *magicLinkServiceImpl) findOrCreateUser(tx *gorm.DB, email string) (*models.UserModel, error) {
+	var user models.UserModel
+	err := tx.Where("email = ?", email).First(&user).Error
+	if err == nil {
+		guest, err := t.isGuest(tx, &user)
+		if err != nil {
+			return nil, err
+		}
+		if !guest {
+			return nil, ErrMagicLinkInvalid
+		}
+		return &user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("failed to fetch user")
+	}
+
+	var contact models.ContactModel
+	if err := tx.Where("id IN (?)", t.contactIdentities(tx, email).Select("contact_identities.contact_id")).
+		Order("id ASC").
+		First(&contact).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("failed to fetch contact")
+	}
+
+	name := strings.TrimSpace(contact.Name)
+	if name == "" {
+		name = email[:strings.Index(email, "@")]
+	}
+
+	var guestRole models.UserRoleModel
+	if err := tx.Where("name = ?", models.RoleGuest).First(&guestRole).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("guest role not found")
+		}
+		return nil, errors.New("failed to fetch guest role")
+	}
+
+	password := make([]byte, 24)
+	if _, err := rand.Read(password); err != nil {
+		return nil, errors.New("failed to create user")
+	}
+
+	user = models.UserModel{
+		Email:    email,
+		Name:     name,
+		Password: hex.EncodeToString(password),
+		RoleID:   guestRole.ID,
+	}
+	if err := tx.Create(&user).Error; err != nil {
+		return nil, errors.New("failed to create user")
+	}
+
+	return &user, nil
+}
+
+func (t *magicLinkServiceImpl) isGuest(tx *gorm.DB, user *models.UserModel) (bool, error) {
+	roleName, err := userRoleName(tx, user.RoleID)
+	if err != nil {
+		return false, err
+	}
+	return roleName == models.RoleGuest, nil
+}
+
+// linkContacts links the user to the contact with their email in every
+// organization, so the guest portal lists the conversations they started
+// through the webhook or by mail.
+func (t *magicLinkServiceImpl) linkContacts(tx *gorm.DB, user *models.UserModel) error {
+	email := newContactIdentity(models.ContactIdentityTypeEmail, user.Email).Value
+
+	var organizationIDs []uint
+	if err := t.contactIdentities(tx, email).
+		Distinct().
+		Pluck("contact_identities.organization_id", &organizationIDs).Error; err != nil {
+		return errors.New("failed to fetch contacts")
+	}
+
+	for _, organizationID := range organizationIDs {
+		if _, err := findOrCreateUserContact(tx, organizationID, user, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *magicLinkServiceImpl) contactIdentities(tx *gorm.DB, email string) *gorm.DB {
+	return tx.Model(&models.ContactIdentityModel{}).
+		Joins("JOIN contacts ON contacts.id = contact_identities.contact_id AND contacts.deleted_at IS NULL").
+		Where("contact_identities.type = ? AND contact_identities.value = ?", models.ContactIdentityTypeEmail, email)
+}
+
+func NewMagicLinkService(db *gorm.DB, jwtService jwtLib.JwtService, mailer mail.Mailer, options MagicLinkOptions) services.MagicLinkService {
+	return &magicLinkServiceImpl{
+		db:         db,
+		jwtService: jwtService,
+		mailer:     mailer,
+		options:    options,
+	}
+}
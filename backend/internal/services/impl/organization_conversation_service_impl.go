@@ -12,22 +12,38 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	ErrConversationNotFound         = errors.New("conversation not found")
+	ErrConversationViewNotPermitted = errors.New("conversation view not permitted")
+)
+
+const (
+	conversationViewMine       = "mine"
+	conversationViewUnassigned = "unassigned"
+	conversationViewAll        = "all"
+)
+
 type organizationConversationServiceImpl struct {
 	db *gorm.DB
 }
 
 // AssignConversation implements services.ConversationService.
-func (t *organizationConversationServiceImpl) AssignConversation(conversationID uint, req requestdto.AssignConversationRequest) (*responsedto.ConversationResponse, error) {
+func (t *organizationConversationServiceImpl) AssignConversation(user *jwt.Claims, conversationID uint, req requestdto.AssignConversationRequest) (*responsedto.ConversationResponse, error) {
+	access, err := conversationAccessScope(t.db, user)
+	if err != nil {
+		return nil, err
+	}
+
 	var conversation models.ConversationModel
-	if err := t.db.First(&conversation, conversationID).Error; err != nil {
+	if err := t.db.Scopes(access).First(&conversation, conversationID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("conversation not found")
+			return nil, ErrConversationNotFound
 		}
 		return nil, errors.New("failed to fetch conversation")
 	}
 
 	var staff models.UserModel
-	if err := t.db.First(&staff, req.OrganizationStaffID).Error; err != nil {
+	if err := t.db.Scopes(organizationScope(user)).First(&staff, req.OrganizationStaffID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("staff user not found")
 		}
@@ -38,14 +54,31 @@ func (t *organizationConversationServiceImpl) AssignConversation(conversationID
 		return nil, errors.New("staff does not belong to this organization")
 	}
 
+	previousStatus := conversation.Status
 	conversation.OrganizationStaffID = &req.OrganizationStaffID
 	conversation.Status = models.ConversationStatusInProgress
 
-	if err := t.db.Save(&conversation).Error; err != nil {
-		return nil, errors.New("failed to assign conversation")
+	if err := t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&conversation).Error; err != nil {
+			return errors.New("failed to assign conversation")
+		}
+
+		if err := publishEvent(tx, conversation.OrganizationID, models.EventConversationAssigned, conversationEvent(&conversation, nil)); err != nil {
+			return err
+		}
+
+		if previousStatus != conversation.Status {
+			if err := recordStatusChange(tx, conversation.OrganizationID, models.StatusChangeSubjectConversation, conversation.ID, conversation.ID, previousStatus, conversation.Status, &user.UserID); err != nil {
+				return err
+			}
+			return publishEvent(tx, conversation.OrganizationID, models.EventConversationStatusChanged, conversationEvent(&conversation, &previousStatus))
+		}
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 
-	if err := t.db.Preload("Organization").Preload("Guest").Preload("OrganizationStaff").First(&conversation, conversation.ID).Error; err != nil {
+	if err := t.db.Preload("Organization").Preload("Guest").Preload("Contact.Identities").Preload("OrganizationStaff").First(&conversation, conversation.ID).Error; err != nil {
 		return nil, errors.New("failed to load conversation details")
 	}
 
@@ -53,75 +86,157 @@ func (t *organizationConversationServiceImpl) AssignConversation(conversationID
 }
 
 // GetConversationByID implements services.ConversationService.
-func (t *organizationConversationServiceImpl) GetConversationByID(id uint) (*responsedto.ConversationResponse, error) {
+func (t *organizationConversationServiceImpl) GetConversationByID(user *jwt.Claims, id uint) (*responsedto.ConversationResponse, error) {
+	access, err := conversationAccessScope(t.db, user)
+	if err != nil {
+		return nil, err
+	}
+
 	var conversation models.ConversationModel
-	if err := t.db.Preload("Organization").
+	if err := t.db.Scopes(access).
+		Preload("Organization").
 		Preload("Guest").
+		Preload("Contact.Identities").
+		Preload("Contact.AttributeValues.Definition").
 		Preload("OrganizationStaff").
-		Preload("ConversationMessages").
+		Preload("ConversationMessages", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("ConversationMessages.CreatedBy").
+		Preload("ConversationMessages.Contact.Identities").
+		Preload("ConversationMessages.Attachments", func(db *gorm.DB) *gorm.DB {
+			return db.Omit("Content")
+		}).
+		Preload("ConversationMessages.Delivery").
 		First(&conversation, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("conversation not found")
+			return nil, ErrConversationNotFound
 		}
 		return nil, errors.New("failed to fetch conversation")
 	}
 
-	return t.mapToConversationResponse(&conversation), nil
+	assigneeID, err := staffAssigneeID(t.db, user)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := loadContactSummary(t.db, conversation.OrganizationID, conversation.ContactID, assigneeID)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := loadTags(t.db, models.TagSubjectConversation, []uint{conversation.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	response := t.mapToConversationResponse(&conversation)
+	response.ContactSummary = summary
+	response.Tags = tags[conversation.ID]
+	return response, nil
 }
 
 // GetConversationsByOrganization implements services.ConversationService.
-func (t *organizationConversationServiceImpl) GetConversationsList(user *jwt.Claims, filter filtersdto.FiltersDto) (*responsedto.ConversationListResponse, error) {
+func (t *organizationConversationServiceImpl) GetConversationsList(user *jwt.Claims, filter filtersdto.FiltersDto, conversationFilter filtersdto.ConversationFiltersDto) (*responsedto.ConversationListResponse, error) {
 	var conversations []models.ConversationModel
 	var total int64
 	offset := (*filter.Page - 1) * *filter.Limit
 
-	if err := t.db.Model(&models.OrganizationModel{}).
-		Count(&total).Error; err != nil {
-		return nil, errors.New("failed to count organizations")
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	view, err := t.conversationView(user, conversationFilter.View)
+	if err != nil {
+		return nil, err
+	}
+
+	attributeFilters, err := contactAttributeFilters(t.db, *user.OrganizationId, "conversations.contact_id", conversationFilter.Attributes)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := t.db.Where("organization_id = ?", user.OrganizationId).
-		Offset(offset).Limit(*filter.Limit).
+	query := t.db.Model(&models.ConversationModel{}).
+		Scopes(organizationScope(user)).
+		Scopes(view).
+		Scopes(attributeFilters)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, errors.New("failed to count conversations")
+	}
+
+	if err := query.Offset(offset).Limit(*filter.Limit).
 		Preload("Guest").
+		Preload("Contact.Identities").
+		Preload("Contact.AttributeValues.Definition").
 		Preload("OrganizationStaff").
 		Order("created_at DESC").
 		Find(&conversations).Error; err != nil {
 		return nil, errors.New("failed to fetch conversations")
 	}
 
+	ids := make([]uint, 0, len(conversations))
+	for _, conv := range conversations {
+		ids = append(ids, conv.ID)
+	}
+	tags, err := loadTags(t.db, models.TagSubjectConversation, ids)
+	if err != nil {
+		return nil, err
+	}
+
 	var conversationResponses []responsedto.ConversationResponse
 	for _, conv := range conversations {
-		conversationResponses = append(conversationResponses, *t.mapToConversationResponse(&conv))
+		response := t.mapToConversationResponse(&conv)
+		response.Tags = tags[conv.ID]
+		conversationResponses = append(conversationResponses, *response)
 	}
 
 	return &responsedto.ConversationListResponse{
 		Conversations: conversationResponses,
 		Metadata: responsedto.PaginateMetaData{
 			Total: int(total),
-			Page:  1,
-			Limit: len(conversationResponses),
+			Page:  *filter.Page,
+			Limit: *filter.Limit,
 		},
 	}, nil
 }
 
 // UpdateConversationStatus implements services.ConversationService.
-func (t *organizationConversationServiceImpl) UpdateConversationStatus(conversationID uint, req requestdto.UpdateConversationRequest) error {
+func (t *organizationConversationServiceImpl) UpdateConversationStatus(user *jwt.Claims, conversationID uint, req requestdto.UpdateConversationRequest) error {
+	access, err := conversationAccessScope(t.db, user)
+	if err != nil {
+		return err
+	}
+
 	var conversation models.ConversationModel
-	if err := t.db.First(&conversation, conversationID).Error; err != nil {
+	if err := t.db.Scopes(access).First(&conversation, conversationID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("conversation not found")
+			return ErrConversationNotFound
 		}
 		return errors.New("failed to fetch conversation")
 	}
 
+	previousStatus := conversation.Status
 	conversation.Status = req.Status
 
-	if err := t.db.Save(&conversation).Error; err != nil {
-		return errors.New("failed to update conversation")
+	if err := t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&conversation).Error; err != nil {
+			return errors.New("failed to update conversation")
+		}
+
+		if previousStatus != conversation.Status {
+			if err := recordStatusChange(tx, conversation.OrganizationID, models.StatusChangeSubjectConversation, conversation.ID, conversation.ID, previousStatus, conversation.Status, &user.UserID); err != nil {
+				return err
+			}
+			return publishEvent(tx, conversation.OrganizationID, models.EventConversationStatusChanged, conversationEvent(&conversation, &previousStatus))
+		}
+		return nil
+	}); err != nil {
+		return err
 	}
 
 	if err := t.db.Preload("Organization").
 		Preload("Guest").
+		Preload("Contact.Identities").
 		Preload("OrganizationStaff").First(&conversation, conversation.ID).Error; err != nil {
 		return errors.New("failed to load conversation details")
 	}
@@ -129,12 +244,107 @@ func (t *organizationConversationServiceImpl) UpdateConversationStatus(conversat
 	return nil
 }
 
+// SendMessage implements services.OrganizationConversationService.
+func (t *organizationConversationServiceImpl) SendMessage(user *jwt.Claims, conversationID uint, req requestdto.CreateStaffMessageRequest) error {
+	access, err := conversationAccessScope(t.db, user)
+	if err != nil {
+		return err
+	}
+
+	var conversation models.ConversationModel
+	if err := t.db.Scopes(access).
+		Preload("Organization").
+		Preload("Guest").
+		Preload("Contact.Identities").
+		First(&conversation, conversationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrConversationNotFound
+		}
+		return errors.New("failed to fetch conversation")
+	}
+
+	message, reply, err := prepareStaffReply(t.db, &conversation, user.UserID, req.Message)
+	if err != nil {
+		return err
+	}
+
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		return deliverStaffReply(tx, &conversation, message, reply)
+	})
+}
+
+// GetAttachment implements services.OrganizationConversationService.
+func (t *organizationConversationServiceImpl) GetAttachment(user *jwt.Claims, conversationID uint, attachmentID uint) (*responsedto.AttachmentFileResponse, error) {
+	access, err := conversationAccessScope(t.db, user)
+	if err != nil {
+		return nil, err
+	}
+
+	var attachment models.ConversationMessageAttachmentModel
+	if err := t.db.
+		Joins("JOIN conversation_messages ON conversation_messages.id = conversation_message_attachments.message_id").
+		Scopes(organizationScope(user)).
+		Where("conversation_messages.conversation_id = ?", conversationID).
+		Where("conversation_messages.conversation_id IN (?)", t.db.Model(&models.ConversationModel{}).Select("id").Scopes(access)).
+		First(&attachment, attachmentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("attachment not found")
+		}
+		return nil, errors.New("failed to fetch attachment")
+	}
+
+	return &responsedto.AttachmentFileResponse{
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Content:     attachment.Content,
+	}, nil
+}
+
+// conversationView is the scope of the inbox view. Owners see every
+// conversation unless they pick a view, sales only ever see their own.
+func (t *organizationConversationServiceImpl) conversationView(user *jwt.Claims, view string) (func(*gorm.DB) *gorm.DB, error) {
+	role, err := userRoleName(t.db, user.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch role {
+	case models.RoleOrganizationOwner:
+		if view == "" {
+			view = conversationViewAll
+		}
+	case models.RoleOrganizationSales:
+		if view == "" {
+			view = conversationViewMine
+		}
+		if view != conversationViewMine {
+			return nil, ErrConversationViewNotPermitted
+		}
+	default:
+		return nil, ErrConversationViewNotPermitted
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		switch view {
+		case conversationViewMine:
+			return db.Where("conversations.organization_staff_id = ?", user.UserID)
+		case conversationViewUnassigned:
+			return db.Where("conversations.organization_staff_id IS NULL")
+		}
+		return db
+	}, nil
+}
+
 func (t *organizationConversationServiceImpl) mapToConversationResponse(conv *models.ConversationModel) *responsedto.ConversationResponse {
 	response := &responsedto.ConversationResponse{
 		ID:             conv.ID,
 		OrganizationID: conv.OrganizationID,
 		GuestID:        conv.GuestID,
+		ContactID:      conv.ContactID,
+		Contact:        mapToContactData(conv.Contact),
 		Status:         conv.Status,
+		Channel:        conv.Channel,
+		Subject:        conv.Subject,
 		CreatedAt:      conv.CreatedAt,
 		UpdatedAt:      conv.UpdatedAt,
 	}
@@ -185,7 +395,10 @@ func (t *organizationConversationServiceImpl) mapToMessageResponse(msg *models.C
 		OrganizationID: msg.OrganizationID,
 		ConversationID: msg.ConversationID,
 		CreatedByID:    msg.CreatedByID,
+		ContactID:      msg.ContactID,
+		Contact:        mapToContactData(msg.Contact),
 		Message:        msg.Message,
+		HTMLMessage:    msg.HTMLMessage,
 		CreatedAt:      msg.CreatedAt,
 		UpdatedAt:      msg.UpdatedAt,
 	}
@@ -198,6 +411,27 @@ func (t *organizationConversationServiceImpl) mapToMessageResponse(msg *models.C
 		}
 	}
 
+	for _, attachment := range msg.Attachments {
+		response.Attachments = append(response.Attachments, responsedto.AttachmentResponse{
+			ID:          attachment.ID,
+			FileName:    attachment.FileName,
+			ContentType: attachment.ContentType,
+			Size:        attachment.Size,
+		})
+	}
+
+	if msg.Delivery != nil {
+		response.Delivery = &responsedto.MessageDeliveryResponse{
+			Channel:       msg.Delivery.Channel,
+			Status:        msg.Delivery.Status,
+			Attempts:      msg.Delivery.Attempts,
+			LastError:     msg.Delivery.LastError,
+			NextAttemptAt: msg.Delivery.NextAttemptAt,
+			SentAt:        msg.Delivery.SentAt,
+			DeliveredAt:   msg.Delivery.DeliveredAt,
+		}
+	}
+
 	return response
 }
 
@@ -8,10 +8,15 @@ import (
 	"DewaSRY/sociomile-app/pkg/lib/jwt"
 	"DewaSRY/sociomile-app/pkg/models"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+var ErrOrganizationTimezoneInvalid = errors.New("invalid organization timezone")
+
 type organizationServiceImpl struct {
 	db *gorm.DB
 }
@@ -51,13 +56,13 @@ func (t *organizationServiceImpl) GetStaffList(filter filtersdto.FiltersDto, use
 	var total int64
 	offset := (*filter.Page - 1) * *filter.Limit
 	if err := t.db.Model(&models.UserModel{}).
-		Where("organization_id = ?", user.OrganizationId).
+		Scopes(organizationScope(user)).
 		Count(&total).Error; err != nil {
 		return nil, errors.New("failed to count user staff")
 	}
 
  if err:= t.db.Model(&models.UserModel{}).
-		Where("organization_id = ?", user.OrganizationId).
+		Scopes(organizationScope(user)).
 		Preload("Role").
 		Offset(offset).Limit(*filter.Limit).Find(&staffList).Error; err!= nil{
 			return  nil, errors.New("failed to populate user")
@@ -90,6 +95,65 @@ func (t *organizationServiceImpl) mappedToOrganizationStaffRecord(user *models.U
 
 
 
+// GetSettings implements services.OrganizationService.
+func (t *organizationServiceImpl) GetSettings(user *jwt.Claims) (*responsedto.OrganizationSettingsResponse, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	var organization models.OrganizationModel
+	if err := t.db.First(&organization, *user.OrganizationId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, errors.New("failed to fetch organization")
+	}
+
+	return &responsedto.OrganizationSettingsResponse{Timezone: organization.Timezone}, nil
+}
+
+// UpdateSettings implements services.OrganizationService.
+func (t *organizationServiceImpl) UpdateSettings(user *jwt.Claims, req requestdto.UpdateOrganizationSettingsRequest) (*responsedto.OrganizationSettingsResponse, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	location, err := time.LoadLocation(strings.TrimSpace(req.Timezone))
+	if err != nil || location.String() == "Local" {
+		return nil, fmt.Errorf("%w: %s", ErrOrganizationTimezoneInvalid, req.Timezone)
+	}
+
+	result := t.db.Model(&models.OrganizationModel{}).
+		Where("id = ?", *user.OrganizationId).
+		Update("timezone", location.String())
+	if result.Error != nil {
+		return nil, errors.New("failed to update organization settings")
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrOrganizationNotFound
+	}
+
+	return &responsedto.OrganizationSettingsResponse{Timezone: location.String()}, nil
+}
+
+// organizationLocation is the timezone of the organization, UTC when it is
+// not set or no longer known.
+func organizationLocation(db *gorm.DB, organizationID uint) (*time.Location, error) {
+	var organization models.OrganizationModel
+	if err := db.Select("id", "timezone").First(&organization, organizationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, errors.New("failed to fetch organization")
+	}
+
+	location, err := time.LoadLocation(organization.Timezone)
+	if err != nil {
+		return time.UTC, nil
+	}
+	return location, nil
+}
+
 func NewOrganizationService(db *gorm.DB) services.OrganizationService {
 	return &organizationServiceImpl{db: db}
 }
@@ -0,0 +1,48 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// uniqueOrganizationSlug derives a slug from the organization name, a
+// number is appended while the slug is taken.
+func uniqueOrganizationSlug(tx *gorm.DB, name string) (string, error) {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	base := strings.TrimSuffix(b.String(), "-")
+	if len(base) > 56 {
+		base = strings.TrimSuffix(base[:56], "-")
+	}
+	if base == "" {
+		base = "organization"
+	}
+
+	slug := base
+	for n := 2; ; n++ {
+		var taken int64
+		if err := tx.Model(&models.OrganizationModel{}).
+			Unscoped().
+			Where("slug = ?", slug).
+			Count(&taken).Error; err != nil {
+			return "", errors.New("failed to check slug")
+		}
+		if taken == 0 {
+			return slug, nil
+		}
+		slug = base + "-" + strconv.Itoa(n)
+	}
+}
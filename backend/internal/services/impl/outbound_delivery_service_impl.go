@@ -0,0 +1,268 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/lib/outbound"
+	"DewaSRY/sociomile-app/pkg/models"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrDeliveryNotFound     = errors.New("delivery not found")
+	ErrInvalidReceiptToken  = errors.New("invalid receipt token")
+	ErrDeliveryNotRetryable = errors.New("only failed or dead letter deliveries can be retried")
+)
+
+const (
+	outboundBaseBackoff = 30 * time.Second
+	outboundMaxBackoff  = time.Hour
+	// outboundClaimLease keeps other workers away from a claimed delivery
+	// while the dispatcher is still talking to the provider
+	outboundClaimLease = 5 * time.Minute
+)
+
+type outboundDeliveryServiceImpl struct {
+	db          *gorm.DB
+	registry    *outbound.Registry
+	maxAttempts int
+}
+
+// ProcessDueDeliveries implements services.OutboundDeliveryService.
+func (t *outboundDeliveryServiceImpl) ProcessDueDeliveries(limit int) (int, error) {
+	var due []models.OutboundDeliveryModel
+	now := time.Now()
+
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ?", []string{models.OutboundStatusQueued, models.OutboundStatusFailed}).
+			Where("next_attempt_at <= ?", now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&due).Error; err != nil {
+			return err
+		}
+
+		if len(due) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(due))
+		for _, delivery := range due {
+			ids = append(ids, delivery.ID)
+		}
+
+		return tx.Model(&models.OutboundDeliveryModel{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(outboundClaimLease)).Error
+	})
+	if err != nil {
+		return 0, errors.New("failed to claim outbound deliveries")
+	}
+
+	for i := range due {
+		t.dispatch(&due[i])
+	}
+
+	return len(due), nil
+}
+
+// HandleReceipt implements services.OutboundDeliveryService.
+func (t *outboundDeliveryServiceImpl) HandleReceipt(req requestdto.DeliveryReceiptRequest) error {
+	var delivery models.OutboundDeliveryModel
+	if err := t.db.First(&delivery, req.DeliveryID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrDeliveryNotFound
+		}
+		return errors.New("failed to fetch delivery")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(delivery.ReceiptToken), []byte(req.Token)) != 1 {
+		return ErrInvalidReceiptToken
+	}
+
+	if req.Status == models.OutboundStatusDelivered {
+		now := time.Now()
+		delivery.Status = models.OutboundStatusDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = nil
+		if err := t.db.Save(&delivery).Error; err != nil {
+			return errors.New("failed to update delivery")
+		}
+		return nil
+	}
+
+	reason := req.Error
+	if reason == "" {
+		reason = "provider reported a failed delivery"
+	}
+	if err := t.markFailed(&delivery, reason); err != nil {
+		return errors.New("failed to update delivery")
+	}
+	return nil
+}
+
+// RetryDelivery implements services.OutboundDeliveryService.
+func (t *outboundDeliveryServiceImpl) RetryDelivery(user *jwt.Claims, conversationID uint, messageID uint) error {
+	access, err := conversationAccessScope(t.db, user)
+	if err != nil {
+		return err
+	}
+
+	var delivery models.OutboundDeliveryModel
+	if err := t.db.Scopes(organizationScope(user)).
+		Where("conversation_id IN (?)", t.db.Model(&models.ConversationModel{}).Select("id").Scopes(access)).
+		Where("conversation_id = ?", conversationID).
+		Where("message_id = ?", messageID).
+		First(&delivery).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrDeliveryNotFound
+		}
+		return errors.New("failed to fetch delivery")
+	}
+
+	if delivery.Status != models.OutboundStatusFailed && delivery.Status != models.OutboundStatusDeadLetter {
+		return ErrDeliveryNotRetryable
+	}
+
+	if err := t.db.Model(&delivery).Updates(map[string]any{
+		"status":          models.OutboundStatusQueued,
+		"attempts":        0,
+		"next_attempt_at": time.Now(),
+	}).Error; err != nil {
+		return errors.New("failed to requeue delivery")
+	}
+
+	return nil
+}
+
+func (t *outboundDeliveryServiceImpl) dispatch(delivery *models.OutboundDeliveryModel) {
+	result, err := t.registry.Dispatch(outbound.Delivery{
+		ID:          delivery.ID,
+		Channel:     delivery.Channel,
+		Destination: delivery.Destination,
+		Payload:     delivery.Payload,
+	})
+
+	if err != nil {
+		logger.ErrorLog("Outbound delivery failed", map[string]any{
+			"delivery_id": delivery.ID,
+			"channel":     delivery.Channel,
+			"error":       err.Error(),
+		})
+		if err := t.markFailed(delivery, err.Error()); err != nil {
+			logger.ErrorLog("Failed to update outbound delivery", map[string]any{
+				"delivery_id": delivery.ID,
+				"error":       err.Error(),
+			})
+		}
+		return
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.Status = models.OutboundStatusSent
+	delivery.SentAt = &now
+	delivery.LastError = nil
+	if result.ProviderMessageID != "" {
+		delivery.ProviderMessageID = &result.ProviderMessageID
+	}
+	if result.Delivered {
+		delivery.Status = models.OutboundStatusDelivered
+		delivery.DeliveredAt = &now
+	}
+
+	if err := t.db.Save(delivery).Error; err != nil {
+		logger.ErrorLog("Failed to update outbound delivery", map[string]any{
+			"delivery_id": delivery.ID,
+			"error":       err.Error(),
+		})
+	}
+}
+
+// markFailed records the attempt and schedules the next one with an
+// exponential backoff, or parks the delivery once max attempts is reached.
+func (t *outboundDeliveryServiceImpl) markFailed(delivery *models.OutboundDeliveryModel, reason string) error {
+	delivery.Attempts++
+	delivery.LastError = &reason
+
+	if delivery.Attempts >= t.maxAttempts {
+		delivery.Status = models.OutboundStatusDeadLetter
+	} else {
+		delivery.Status = models.OutboundStatusFailed
+		delivery.NextAttemptAt = time.Now().Add(outboundBackoff(delivery.Attempts))
+	}
+
+	return t.db.Save(delivery).Error
+}
+
+func outboundBackoff(attempts int) time.Duration {
+	backoff := outboundBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= outboundMaxBackoff {
+			return outboundMaxBackoff
+		}
+	}
+	return backoff
+}
+
+// queueOutboundDelivery stores the delivery next to the staff message, it
+// must run inside the same transaction so a reply is never lost.
+func queueOutboundDelivery(tx *gorm.DB, message *models.ConversationMessageModel, channel string, destination string, buildPayload func(delivery *models.OutboundDeliveryModel) ([]byte, error)) (*models.OutboundDeliveryModel, error) {
+	token := make([]byte, 24)
+	if _, err := rand.Read(token); err != nil {
+		return nil, errors.New("failed to create receipt token")
+	}
+
+	delivery := models.OutboundDeliveryModel{
+		OrganizationID: message.OrganizationID,
+		ConversationID: message.ConversationID,
+		MessageID:      message.ID,
+		Channel:        channel,
+		Destination:    destination,
+		Payload:        []byte("{}"),
+		Status:         models.OutboundStatusQueued,
+		NextAttemptAt:  time.Now(),
+		ReceiptToken:   hex.EncodeToString(token),
+	}
+
+	if destination == "" {
+		reason := "conversation has no reply destination"
+		delivery.Status = models.OutboundStatusDeadLetter
+		delivery.LastError = &reason
+	}
+
+	if err := tx.Create(&delivery).Error; err != nil {
+		return nil, errors.New("failed to queue delivery")
+	}
+
+	payload, err := buildPayload(&delivery)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Model(&delivery).Update("payload", payload).Error; err != nil {
+		return nil, errors.New("failed to queue delivery")
+	}
+	delivery.Payload = payload
+
+	return &delivery, nil
+}
+
+func NewOutboundDeliveryService(db *gorm.DB, registry *outbound.Registry, maxAttempts int) services.OutboundDeliveryService {
+	return &outboundDeliveryServiceImpl{
+		db:          db,
+		registry:    registry,
+		maxAttempts: maxAttempts,
+	}
+}
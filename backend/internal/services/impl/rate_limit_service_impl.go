@@ -0,0 +1,324 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/lib/ratelimit"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// organizationLimitTTL is how long an organization override is cached, so
+// the ingest routes do not hit the database on every request.
+const organizationLimitTTL = time.Minute
+
+// RateLimitDefaults are the requests per minute used when an organization
+// has no override.
+type RateLimitDefaults struct {
+	OrganizationPerMinute int
+	ContactPerMinute      int
+	IPPerMinute           int
+}
+
+type cachedOrganizationLimit struct {
+	organizationPerMinute int
+	contactPerMinute      int
+	loadedAt              time.Time
+}
+
+type rateLimitHitKey struct {
+	organizationID uint
+	scope          string
+	route          string
+	windowStart    time.Time
+}
+
+type rateLimitServiceImpl struct {
+	db       *gorm.DB
+	store    ratelimit.Store
+	defaults RateLimitDefaults
+
+	mu     sync.Mutex
+	limits map[uint]cachedOrganizationLimit
+	hits   map[rateLimitHitKey]int
+}
+
+// Allow implements services.RateLimitService. The ip bucket is checked
+// first, then the contact and the organization buckets. A contact over its
+// own limit is turned away before it takes a token of the organization, so
+// one sender can not use up the limit of everyone else.
+func (t *rateLimitServiceImpl) Allow(subject ratelimit.Subject) (ratelimit.Decision, string) {
+	if subject.IP != "" {
+		decision := t.store.Take(
+			fmt.Sprintf("ip:%s:%s", subject.Route, subject.IP),
+			perMinute(t.defaults.IPPerMinute),
+		)
+		if !decision.Allowed {
+			t.recordHit(subject, models.RateLimitScopeIP)
+			return decision, models.RateLimitScopeIP
+		}
+	}
+
+	if subject.OrganizationID == 0 {
+		return ratelimit.Decision{Allowed: true}, ""
+	}
+
+	limit := t.organizationLimit(subject.OrganizationID)
+
+	if subject.ContactKey != "" {
+		decision := t.store.Take(
+			fmt.Sprintf("contact:%d:%s:%s", subject.OrganizationID, subject.Route, subject.ContactKey),
+			perMinute(limit.contactPerMinute),
+		)
+		if !decision.Allowed {
+			t.recordHit(subject, models.RateLimitScopeContact)
+			return decision, models.RateLimitScopeContact
+		}
+	}
+
+	decision := t.store.Take(
+		fmt.Sprintf("org:%d:%s", subject.OrganizationID, subject.Route),
+		perMinute(limit.organizationPerMinute),
+	)
+	if !decision.Allowed {
+		t.recordHit(subject, models.RateLimitScopeOrganization)
+		return decision, models.RateLimitScopeOrganization
+	}
+
+	return decision, ""
+}
+
+// GuestConversationOrganizationID implements services.RateLimitService. Only
+// conversations of the guest count, naming another one does not charge its
+// organization.
+func (t *rateLimitServiceImpl) GuestConversationOrganizationID(user *jwt.Claims, conversationID uint) (uint, error) {
+	var conversation models.ConversationModel
+	if err := t.db.Scopes(guestConversationScope(user)).
+		Select("conversations.id", "conversations.organization_id").
+		First(&conversation, conversationID).Error; err != nil {
+		return 0, errors.New("conversation not found")
+	}
+	return conversation.OrganizationID, nil
+}
+
+// FlushHits implements services.RateLimitService.
+func (t *rateLimitServiceImpl) FlushHits() error {
+	t.mu.Lock()
+	pending := t.hits
+	t.hits = map[rateLimitHitKey]int{}
+	t.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	rows := make([]models.RateLimitHitModel, 0, len(pending))
+	for key, hits := range pending {
+		rows = append(rows, models.RateLimitHitModel{
+			OrganizationID: key.organizationID,
+			Scope:          key.scope,
+			Route:          key.route,
+			WindowStart:    key.windowStart,
+			Hits:           hits,
+		})
+	}
+
+	if err := t.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"hits":       gorm.Expr("hits + VALUES(hits)"),
+			"updated_at": time.Now(),
+		}),
+	}).Create(&rows).Error; err != nil {
+		// put the counters back so they are written on the next flush
+		t.mu.Lock()
+		for key, hits := range pending {
+			t.hits[key] += hits
+		}
+		t.mu.Unlock()
+		return errors.New("failed to store rate limit hits")
+	}
+
+	return nil
+}
+
+// GetHits implements services.RateLimitService.
+func (t *rateLimitServiceImpl) GetHits(filter filtersdto.FiltersDto, organizationID *uint) (*responsedto.RateLimitHitPaginateResponse, error) {
+	var hits []models.RateLimitHitModel
+	var total int64
+	offset := (*filter.Page - 1) * *filter.Limit
+
+	query := t.db.Model(&models.RateLimitHitModel{})
+	if organizationID != nil {
+		query = query.Where("organization_id = ?", *organizationID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, errors.New("failed to count rate limit hits")
+	}
+
+	if err := query.Offset(offset).Limit(*filter.Limit).
+		Order("window_start DESC").
+		Find(&hits).Error; err != nil {
+		return nil, errors.New("failed to fetch rate limit hits")
+	}
+
+	data := make([]responsedto.RateLimitHitResponse, 0, len(hits))
+	for _, hit := range hits {
+		data = append(data, responsedto.RateLimitHitResponse{
+			OrganizationID: hit.OrganizationID,
+			Scope:          hit.Scope,
+			Route:          hit.Route,
+			WindowStart:    hit.WindowStart,
+			Hits:           hit.Hits,
+		})
+	}
+
+	return &responsedto.RateLimitHitPaginateResponse{
+		Data: data,
+		Metadata: responsedto.PaginateMetaData{
+			Total: int(total),
+			Page:  *filter.Page,
+			Limit: *filter.Limit,
+		},
+	}, nil
+}
+
+// GetOrganizationLimits implements services.RateLimitService.
+func (t *rateLimitServiceImpl) GetOrganizationLimits(organizationID uint) (*responsedto.OrganizationRateLimitResponse, error) {
+	var organization models.OrganizationModel
+	if err := t.db.Select("id").First(&organization, organizationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, errors.New("failed to fetch organization")
+	}
+
+	var override models.OrganizationRateLimitModel
+	err := t.db.Where("organization_id = ?", organizationID).First(&override).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("failed to fetch rate limits")
+	}
+
+	return t.mapToLimitResponse(organizationID, &override, err == nil), nil
+}
+
+// UpdateOrganizationLimits implements services.RateLimitService.
+func (t *rateLimitServiceImpl) UpdateOrganizationLimits(organizationID uint, req requestdto.UpdateOrganizationRateLimitRequest) (*responsedto.OrganizationRateLimitResponse, error) {
+	var organization models.OrganizationModel
+	if err := t.db.Select("id").First(&organization, organizationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, errors.New("failed to fetch organization")
+	}
+
+	override := models.OrganizationRateLimitModel{OrganizationID: organizationID}
+	if err := t.db.Where("organization_id = ?", organizationID).FirstOrInit(&override).Error; err != nil {
+		return nil, errors.New("failed to fetch rate limits")
+	}
+
+	override.OrganizationPerMinute = req.OrganizationPerMinute
+	override.ContactPerMinute = req.ContactPerMinute
+
+	if err := t.db.Save(&override).Error; err != nil {
+		return nil, errors.New("failed to update rate limits")
+	}
+
+	t.mu.Lock()
+	delete(t.limits, organizationID)
+	t.mu.Unlock()
+
+	return t.mapToLimitResponse(organizationID, &override, true), nil
+}
+
+func (t *rateLimitServiceImpl) organizationLimit(organizationID uint) cachedOrganizationLimit {
+	t.mu.Lock()
+	cached, ok := t.limits[organizationID]
+	t.mu.Unlock()
+	if ok && time.Since(cached.loadedAt) < organizationLimitTTL {
+		return cached
+	}
+
+	cached = cachedOrganizationLimit{
+		organizationPerMinute: t.defaults.OrganizationPerMinute,
+		contactPerMinute:      t.defaults.ContactPerMinute,
+		loadedAt:              time.Now(),
+	}
+
+	var override models.OrganizationRateLimitModel
+	if err := t.db.Where("organization_id = ?", organizationID).First(&override).Error; err == nil {
+		if override.OrganizationPerMinute != nil {
+			cached.organizationPerMinute = *override.OrganizationPerMinute
+		}
+		if override.ContactPerMinute != nil {
+			cached.contactPerMinute = *override.ContactPerMinute
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.ErrorLog("Failed to load organization rate limits", map[string]any{
+			"organization_id": organizationID,
+			"error":           err.Error(),
+		})
+	}
+
+	t.mu.Lock()
+	t.limits[organizationID] = cached
+	t.mu.Unlock()
+
+	return cached
+}
+
+func (t *rateLimitServiceImpl) recordHit(subject ratelimit.Subject, scope string) {
+	key := rateLimitHitKey{
+		organizationID: subject.OrganizationID,
+		scope:          scope,
+		route:          subject.Route,
+		windowStart:    time.Now().Truncate(time.Minute),
+	}
+
+	t.mu.Lock()
+	t.hits[key]++
+	t.mu.Unlock()
+}
+
+func (t *rateLimitServiceImpl) mapToLimitResponse(organizationID uint, override *models.OrganizationRateLimitModel, custom bool) *responsedto.OrganizationRateLimitResponse {
+	response := &responsedto.OrganizationRateLimitResponse{
+		OrganizationID:        organizationID,
+		OrganizationPerMinute: t.defaults.OrganizationPerMinute,
+		ContactPerMinute:      t.defaults.ContactPerMinute,
+		IPPerMinute:           t.defaults.IPPerMinute,
+		Custom:                custom,
+	}
+
+	if override.OrganizationPerMinute != nil {
+		response.OrganizationPerMinute = *override.OrganizationPerMinute
+	}
+	if override.ContactPerMinute != nil {
+		response.ContactPerMinute = *override.ContactPerMinute
+	}
+
+	return response
+}
+
+func perMinute(requests int) ratelimit.Limit {
+	return ratelimit.Limit{Requests: requests, Per: time.Minute}
+}
+
+func NewRateLimitService(db *gorm.DB, store ratelimit.Store, defaults RateLimitDefaults) services.RateLimitService {
+	return &rateLimitServiceImpl{
+		db:       db,
+		store:    store,
+		defaults: defaults,
+		limits:   map[uint]cachedOrganizationLimit{},
+		hits:     map[rateLimitHitKey]int{},
+	}
+}
@@ -0,0 +1,24 @@
+package impl
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+)
+
+// newSecretToken returns a random url safe token for links and sessions,
+// only its hashSecretToken is stored.
+func newSecretToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", errors.New("failed to generate token")
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func hashSecretToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
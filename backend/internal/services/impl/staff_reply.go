@@ -0,0 +1,142 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/pkg/lib/mail"
+	"DewaSRY/sociomile-app/pkg/models"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// prepareStaffReply builds a staff message to the customer of the
+// conversation, which needs its Organization and Contact.Identities loaded.
+// Email replies get their mail here so the thread is read outside of the
+// transaction.
+func prepareStaffReply(db *gorm.DB, conversation *models.ConversationModel, authorID uint, text string) (*models.ConversationMessageModel, *mail.OutgoingMail, error) {
+	message := &models.ConversationMessageModel{
+		OrganizationID: conversation.OrganizationID,
+		ConversationID: conversation.ID,
+		CreatedByID:    &authorID,
+		Message:        text,
+	}
+
+	var reply *mail.OutgoingMail
+	if conversation.Channel == models.ConversationChannelEmail {
+		var err error
+		if reply, err = buildEmailReply(db, conversation, text); err != nil {
+			return nil, nil, err
+		}
+		message.ExternalMessageID = &reply.MessageID
+	}
+
+	return message, reply, nil
+}
+
+// deliverStaffReply stores the message and queues it to the channel of the
+// conversation, it must run in a transaction.
+func deliverStaffReply(tx *gorm.DB, conversation *models.ConversationModel, message *models.ConversationMessageModel, reply *mail.OutgoingMail) error {
+	if err := tx.Create(message).Error; err != nil {
+		return errors.New("failed to create message")
+	}
+
+	if err := publishEvent(tx, message.OrganizationID, models.EventMessageCreated, messageEvent(message)); err != nil {
+		return err
+	}
+
+	switch conversation.Channel {
+	case models.ConversationChannelEmail:
+		_, err := queueOutboundDelivery(tx, message, conversation.Channel, strings.Join(reply.To, ","),
+			func(*models.OutboundDeliveryModel) ([]byte, error) {
+				return json.Marshal(reply)
+			})
+		return err
+	case models.ConversationChannelWebhook:
+		// a url no longer on the callback hosts is left out, the delivery
+		// then fails instead of posting the reply to it
+		destination := ""
+		if conversation.CallbackURL != nil {
+			hosts, err := organizationCallbackHosts(tx, conversation.OrganizationID)
+			if err != nil {
+				return err
+			}
+			if webhookCallbackAllowed(hosts, *conversation.CallbackURL) {
+				destination = *conversation.CallbackURL
+			}
+		}
+		_, err := queueOutboundDelivery(tx, message, conversation.Channel, destination,
+			func(delivery *models.OutboundDeliveryModel) ([]byte, error) {
+				return json.Marshal(webhookReplyPayload{
+					Event:          "message.reply",
+					DeliveryID:     delivery.ID,
+					ReceiptToken:   delivery.ReceiptToken,
+					OrganizationID: message.OrganizationID,
+					ConversationID: message.ConversationID,
+					MessageID:      message.ID,
+					Email:          contactEmail(conversation.Contact),
+					Message:        message.Message,
+					CreatedAt:      message.CreatedAt,
+				})
+			})
+		return err
+	}
+	return nil
+}
+
+// webhookReplyPayload is what the integration's callback url receives for
+// every staff reply, the receipt token is used to confirm the delivery.
+type webhookReplyPayload struct {
+	Event          string    `json:"event"`
+	DeliveryID     uint      `json:"deliveryId"`
+	ReceiptToken   string    `json:"receiptToken"`
+	OrganizationID uint      `json:"organizationId"`
+	ConversationID uint      `json:"conversationId"`
+	MessageID      uint      `json:"messageId"`
+	Email          string    `json:"email"`
+	Message        string    `json:"message"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// buildEmailReply prepares the outgoing mail so it threads with the
+// customer's previous mails in the same conversation.
+func buildEmailReply(db *gorm.DB, conversation *models.ConversationModel, message string) (*mail.OutgoingMail, error) {
+	recipient := contactEmail(conversation.Contact)
+	if recipient == "" {
+		return nil, errors.New("conversation has no email recipient")
+	}
+
+	var threadIDs []string
+	if err := db.Model(&models.ConversationMessageModel{}).
+		Where("conversation_id = ?", conversation.ID).
+		Where("external_message_id IS NOT NULL").
+		Order("created_at ASC").
+		Pluck("external_message_id", &threadIDs).Error; err != nil {
+		return nil, errors.New("failed to load email thread")
+	}
+
+	reply := &mail.OutgoingMail{
+		To:         []string{recipient},
+		Subject:    "Re: your message",
+		Text:       message,
+		References: threadIDs,
+	}
+
+	if conversation.Organization != nil && conversation.Organization.InboundEmail != nil {
+		reply.From = *conversation.Organization.InboundEmail
+	}
+	reply.MessageID = mail.NewMessageID(reply.From)
+
+	if conversation.Subject != nil && *conversation.Subject != "" {
+		reply.Subject = *conversation.Subject
+		if !strings.HasPrefix(strings.ToLower(reply.Subject), "re:") {
+			reply.Subject = "Re: " + reply.Subject
+		}
+	}
+	if len(threadIDs) > 0 {
+		reply.InReplyTo = threadIDs[len(threadIDs)-1]
+	}
+
+	return reply, nil
+}
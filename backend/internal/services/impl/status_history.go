@@ -0,0 +1,29 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// recordStatusChange keeps the transition for the contact timeline, it
+// must run in the transaction that changes the status.
+func recordStatusChange(tx *gorm.DB, organizationID uint, subjectType string, subjectID uint, conversationID uint, from string, to string, changedByID *uint) error {
+	if from == to {
+		return nil
+	}
+
+	if err := tx.Create(&models.StatusChangeModel{
+		OrganizationID: organizationID,
+		SubjectType:    subjectType,
+		SubjectID:      subjectID,
+		ConversationID: conversationID,
+		FromStatus:     from,
+		ToStatus:       to,
+		ChangedByID:    changedByID,
+	}).Error; err != nil {
+		return errors.New("failed to record status change")
+	}
+	return nil
+}
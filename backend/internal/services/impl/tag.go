@@ -0,0 +1,74 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrTagInvalid = errors.New("tag must not be empty")
+
+func normalizeTag(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return "", ErrTagInvalid
+	}
+	return name, nil
+}
+
+// addTag tags the subject, tagging it twice is not an error. It reports
+// whether the tag is new.
+func addTag(tx *gorm.DB, organizationID uint, subjectType string, subjectID uint, name string) (bool, error) {
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.TagModel{
+		OrganizationID: organizationID,
+		SubjectType:    subjectType,
+		SubjectID:      subjectID,
+		Name:           name,
+	})
+	if result.Error != nil {
+		return false, errors.New("failed to add tag")
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// removeTag removes the tag from the subject and reports whether it was
+// there.
+func removeTag(tx *gorm.DB, subjectType string, subjectID uint, name string) (bool, error) {
+	result := tx.Where("subject_type = ? AND subject_id = ? AND name = ?", subjectType, subjectID, name).
+		Delete(&models.TagModel{})
+	if result.Error != nil {
+		return false, errors.New("failed to remove tag")
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// loadTags returns the tag names of the subjects by subject id.
+func loadTags(db *gorm.DB, subjectType string, subjectIDs []uint) (map[uint][]string, error) {
+	tags := map[uint][]string{}
+	if len(subjectIDs) == 0 {
+		return tags, nil
+	}
+
+	var rows []models.TagModel
+	if err := db.Where("subject_type = ? AND subject_id IN ?", subjectType, subjectIDs).
+		Order("name ASC").
+		Find(&rows).Error; err != nil {
+		return nil, errors.New("failed to fetch tags")
+	}
+	for _, row := range rows {
+		tags[row.SubjectID] = append(tags[row.SubjectID], row.Name)
+	}
+	return tags, nil
+}
+
+// taggedWith selects the ids of the subjects carrying the tag, for use as
+// a subquery.
+func taggedWith(db *gorm.DB, subjectType string, name string) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Model(&models.TagModel{}).
+		Select("subject_id").
+		Where("subject_type = ? AND name = ?", subjectType, strings.ToLower(strings.TrimSpace(name)))
+}
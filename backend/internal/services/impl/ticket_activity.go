@@ -0,0 +1,83 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ticketField struct {
+	name  string
+	value *string
+}
+
+// ticketFields are the fields of a ticket the activity log follows, as
+// text so changes of any type fit in the same columns.
+func ticketFields(ticket *models.TicketModel) []ticketField {
+	var assignee, dueAt *string
+	if ticket.AssigneeID != nil {
+		value := strconv.FormatUint(uint64(*ticket.AssigneeID), 10)
+		assignee = &value
+	}
+	if ticket.DueAt != nil {
+		value := ticket.DueAt.UTC().Format(time.RFC3339)
+		dueAt = &value
+	}
+	name, status, priority := ticket.Name, ticket.Status, ticket.Priority
+
+	return []ticketField{
+		{"name", &name},
+		{"status", &status},
+		{"assignee_id", assignee},
+		{"priority", &priority},
+		{"due_at", dueAt},
+		{"category", ticket.Category},
+		{"description", ticket.Description},
+		{"resolution_note", ticket.ResolutionNote},
+	}
+}
+
+// recordTicketActivity adds an entry to the ticket history, it must run in
+// the transaction of the change.
+func recordTicketActivity(tx *gorm.DB, activity *models.TicketActivityModel) error {
+	if err := tx.Create(activity).Error; err != nil {
+		return errors.New("failed to record ticket activity")
+	}
+	return nil
+}
+
+// recordTicketChanges adds an update entry for every followed field that
+// differs between before and after.
+func recordTicketChanges(tx *gorm.DB, before *models.TicketModel, after *models.TicketModel, actorID *uint) error {
+	oldFields := ticketFields(before)
+	for i, field := range ticketFields(after) {
+		oldValue := oldFields[i].value
+		if sameTicketValue(oldValue, field.value) {
+			continue
+		}
+
+		name := field.name
+		if err := recordTicketActivity(tx, &models.TicketActivityModel{
+			OrganizationID: after.OrganizationID,
+			TicketID:       after.ID,
+			ActorID:        actorID,
+			Action:         models.TicketActivityUpdated,
+			Field:          &name,
+			OldValue:       oldValue,
+			NewValue:       field.value,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sameTicketValue(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
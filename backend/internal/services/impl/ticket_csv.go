@@ -0,0 +1,511 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/models"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrTicketImportInvalid = errors.New("invalid ticket import")
+
+const (
+	// ticketExportBatchSize is how many tickets are loaded and written at a
+	// time while streaming an export.
+	ticketExportBatchSize = 500
+
+	// maxTicketImportRows caps one import so it fits in one transaction.
+	maxTicketImportRows = 1000
+
+	// ticketCSVTimeLayout is how exports write times and one of the
+	// layouts imports read, in the timezone of the organization.
+	ticketCSVTimeLayout = "2006-01-02 15:04:05"
+
+	// ticketCSVFieldPrefix starts the column of a custom field, followed by
+	// its key.
+	ticketCSVFieldPrefix = "field."
+)
+
+var ticketExportHeader = []string{
+	"ticket_number", "name", "status", "status_category", "priority", "category",
+	"assignee_email", "created_by_email", "conversation_id", "due_at", "sla_due_at",
+	"tags", "resolution_note", "description", "created_at", "updated_at",
+}
+
+// ExportTickets implements services.OrganizationTicketService. The tickets
+// matching the list filters are written as CSV in batches, so the sort is
+// checked before anything is written.
+func (t *OrganizationTicketServiceImpl) ExportTickets(user *jwtLib.Claims, ticketFilter filtersdto.TicketFiltersDto, w io.Writer) error {
+	if user.OrganizationId == nil {
+		return ErrOrganizationNotFound
+	}
+	access, err := ticketAccessScope(t.db, user)
+	if err != nil {
+		return err
+	}
+
+	location, err := organizationLocation(t.db, *user.OrganizationId)
+	if err != nil {
+		return err
+	}
+
+	filtered, err := filterTickets(t.db, *user.OrganizationId, ticketFilter, time.Now())
+	if err != nil {
+		return err
+	}
+	query, err := applyTicketSort(filtered.Scopes(access), ticketFilter.Sort)
+	if err != nil {
+		return err
+	}
+
+	var definitions []models.TicketFieldDefinitionModel
+	if err := t.db.Scopes(organizationScope(user)).
+		Order("id ASC").
+		Find(&definitions).Error; err != nil {
+		return errors.New("failed to fetch ticket fields")
+	}
+
+	header := slices.Clone(ticketExportHeader)
+	for _, definition := range definitions {
+		header = append(header, ticketCSVFieldPrefix+definition.Key)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for offset := 0; ; offset += ticketExportBatchSize {
+		var tickets []models.TicketModel
+		if err := query.Session(&gorm.Session{}).
+			Preload("CreatedBy").Preload("Assignee").
+			Offset(offset).Limit(ticketExportBatchSize).
+			Find(&tickets).Error; err != nil {
+			return errors.New("failed to fetch tickets")
+		}
+
+		ids := make([]uint, 0, len(tickets))
+		for _, ticket := range tickets {
+			ids = append(ids, ticket.ID)
+		}
+		tags, err := loadTags(t.db, models.TagSubjectTicket, ids)
+		if err != nil {
+			return err
+		}
+		var values []models.TicketFieldValueModel
+		if len(ids) > 0 {
+			if err := t.db.Where("ticket_id IN ?", ids).Find(&values).Error; err != nil {
+				return errors.New("failed to fetch ticket fields")
+			}
+		}
+		fields := make(map[uint]map[uint]string, len(tickets))
+		for _, value := range values {
+			if fields[value.TicketID] == nil {
+				fields[value.TicketID] = map[uint]string{}
+			}
+			fields[value.TicketID][value.DefinitionID] = value.Value
+		}
+
+		for i := range tickets {
+			record := ticketExportRecord(&tickets[i], tags[tickets[i].ID], location)
+			for _, definition := range definitions {
+				record = append(record, csvCell(fields[tickets[i].ID][definition.ID]))
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+		if flusher, ok := w.(interface{ Flush() }); ok {
+			flusher.Flush()
+		}
+
+		if len(tickets) < ticketExportBatchSize {
+			return nil
+		}
+	}
+}
+
+func ticketExportRecord(ticket *models.TicketModel, tags []string, location *time.Location) []string {
+	var assignee, createdBy string
+	if ticket.Assignee != nil {
+		assignee = ticket.Assignee.Email
+	}
+	if ticket.CreatedBy != nil {
+		createdBy = ticket.CreatedBy.Email
+	}
+
+	return []string{
+		csvCell(ticket.TicketNumber),
+		csvCell(ticket.Name),
+		ticket.Status,
+		ticket.StatusCategory,
+		ticket.Priority,
+		csvCell(stringValue(ticket.Category)),
+		csvCell(assignee),
+		csvCell(createdBy),
+		strconv.FormatUint(uint64(ticket.ConversationID), 10),
+		csvTime(ticket.DueAt, location),
+		csvTime(ticket.SLADueAt, location),
+		csvCell(strings.Join(tags, ";")),
+		csvCell(stringValue(ticket.ResolutionNote)),
+		csvCell(stringValue(ticket.Description)),
+		ticket.CreatedAt.In(location).Format(ticketCSVTimeLayout),
+		ticket.UpdatedAt.In(location).Format(ticketCSVTimeLayout),
+	}
+}
+
+// csvCell keeps spreadsheets from reading text typed by customers or staff
+// as a formula.
+func csvCell(value string) string {
+	if value != "" && strings.ContainsAny(value[:1], "=+-@\t\r") {
+		return "'" + value
+	}
+	return value
+}
+
+// csvCellValue takes back the quote csvCell put in front of a value, so an
+// exported file imports unchanged.
+func csvCellValue(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsAny(value[1:2], "=+-@\t\r") {
+		return value[1:]
+	}
+	return value
+}
+
+func csvTime(value *time.Time, location *time.Location) string {
+	if value == nil {
+		return ""
+	}
+	return value.In(location).Format(ticketCSVTimeLayout)
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// ticketImportRow is a row that passed validation.
+type ticketImportRow struct {
+	row     int
+	request requestdto.CreateTicketRequest
+}
+
+// ImportTickets implements services.OrganizationTicketService. Every row is
+// checked first, the tickets are only created when no row has errors and
+// it is not a dry run, all of them in one transaction. Their numbers are
+// reserved up front, so the sequence is not locked while they are created.
+// A row whose ticket_number is already a ticket of the organization is
+// skipped, so importing an export again creates no copies.
+func (t *OrganizationTicketServiceImpl) ImportTickets(user *jwtLib.Claims, r io.Reader, dryRun bool) (*responsedto.TicketImportResponse, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+	organizationID := *user.OrganizationId
+
+	location, err := organizationLocation(t.db, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: the file has no header", ErrTicketImportInvalid)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	for _, required := range []string{"conversation_id", "name"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: missing column %s", ErrTicketImportInvalid, required)
+		}
+	}
+
+	definitions, err := loadTicketFieldDefinitions(t.db, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	for column := range columns {
+		if key, ok := strings.CutPrefix(column, ticketCSVFieldPrefix); ok && definitions[key] == nil {
+			return nil, fmt.Errorf("%w: unknown column %s", ErrTicketImportInvalid, column)
+		}
+	}
+	fieldKeys := slices.Sorted(maps.Keys(definitions))
+
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrTicketImportInvalid, err)
+		}
+		records = append(records, record)
+		if len(records) > maxTicketImportRows {
+			return nil, fmt.Errorf("%w: at most %d rows", ErrTicketImportInvalid, maxTicketImportRows)
+		}
+	}
+
+	conversations, staff, existing, err := t.loadTicketImportLookups(organizationID, records, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &responsedto.TicketImportResponse{
+		DryRun:  dryRun,
+		Skipped: []responsedto.TicketImportRowSkipped{},
+		Errors:  []responsedto.TicketImportRowError{},
+	}
+	var rows []ticketImportRow
+	numberRows := make(map[string]int)
+	for i, record := range records {
+		if ticketImportBlank(record) {
+			continue
+		}
+		result.Total++
+
+		row := ticketImportRow{row: i + 2}
+		errorCount := len(result.Errors)
+		fail := func(column string, message string) {
+			result.Errors = append(result.Errors, responsedto.TicketImportRowError{Row: row.row, Column: column, Message: message})
+		}
+		cell := func(column string) string {
+			index, ok := columns[column]
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return csvCellValue(strings.TrimSpace(record[index]))
+		}
+
+		if number := cell("ticket_number"); number != "" {
+			if ticketID, ok := existing[number]; ok {
+				result.Skipped = append(result.Skipped, responsedto.TicketImportRowSkipped{Row: row.row, TicketNumber: number, TicketID: ticketID})
+				continue
+			}
+			if first, ok := numberRows[number]; ok {
+				fail("ticket_number", fmt.Sprintf("repeats row %d", first))
+			}
+			numberRows[number] = row.row
+		}
+
+		conversationID, err := strconv.ParseUint(cell("conversation_id"), 10, 32)
+		if err != nil {
+			fail("conversation_id", "must be a conversation id")
+		} else if !conversations[uint(conversationID)] {
+			fail("conversation_id", "conversation not found")
+		}
+		row.request.ConversationID = uint(conversationID)
+
+		row.request.Name = cell("name")
+		if length := len([]rune(row.request.Name)); length < 3 || length > 200 {
+			fail("name", "must be between 3 and 200 characters")
+		}
+
+		row.request.Priority = strings.ToLower(cell("priority"))
+		if row.request.Priority != "" {
+			if !slices.Contains(ticketPriorities, row.request.Priority) {
+				fail("priority", "must be low, normal, high or urgent")
+			}
+		}
+
+		if category := cell("category"); category != "" {
+			if len([]rune(category)) > 100 {
+				fail("category", "must be at most 100 characters")
+			}
+			row.request.Category = &category
+		}
+		if description := cell("description"); description != "" {
+			if len([]rune(description)) > 10000 {
+				fail("description", "must be at most 10000 characters")
+			}
+			row.request.Description = &description
+		}
+
+		if value := cell("due_at"); value != "" {
+			dueAt, err := parseTicketImportTime(value, location)
+			if err != nil {
+				fail("due_at", "must be YYYY-MM-DD, YYYY-MM-DD HH:MM:SS or RFC 3339")
+			}
+			row.request.DueAt = dueAt
+		}
+
+		if email := strings.ToLower(cell("assignee_email")); email != "" {
+			assigneeID, ok := staff[email]
+			if !ok {
+				fail("assignee_email", "not a staff member of the organization")
+			}
+			row.request.AssigneeID = &assigneeID
+		}
+
+		for _, key := range fieldKeys {
+			definition := definitions[key]
+			column := ticketCSVFieldPrefix + key
+			value := cell(column)
+			if value == "" {
+				if definition.Required {
+					fail(column, "is required")
+				}
+				continue
+			}
+			if _, err := normalizeTicketFieldValue(definition, value); err != nil {
+				fail(column, err.Error())
+				continue
+			}
+			if row.request.Fields == nil {
+				row.request.Fields = map[string]any{}
+			}
+			row.request.Fields[key] = value
+		}
+
+		if len(result.Errors) == errorCount {
+			rows = append(rows, row)
+		}
+	}
+	result.Valid = len(rows)
+
+	if dryRun || len(result.Errors) > 0 || len(rows) == 0 {
+		return result, nil
+	}
+
+	numbers, err := reserveTicketNumbers(t.db, organizationID, len(rows), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.db.Transaction(func(tx *gorm.DB) error {
+		tickets := &OrganizationTicketServiceImpl{db: tx}
+		for i, row := range rows {
+			conversation, initial, err := tickets.prepareTicket(user, row.request)
+			if err == nil {
+				err = insertTicket(tx, user, conversation, initial, row.request, numbers[i])
+			}
+			if err != nil {
+				return fmt.Errorf("row %d: %w", row.row, err)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	result.Created = len(rows)
+
+	return result, nil
+}
+
+// loadTicketImportLookups loads the conversations of the organization the
+// rows point at, its staff by email and the ids of the ticket numbers the
+// rows name, so rows are checked without a query each.
+func (t *OrganizationTicketServiceImpl) loadTicketImportLookups(organizationID uint, records [][]string, columns map[string]int) (map[uint]bool, map[string]uint, map[string]uint, error) {
+	var ids []uint
+	index := columns["conversation_id"]
+	for _, record := range records {
+		if index >= len(record) {
+			continue
+		}
+		if id, err := strconv.ParseUint(strings.TrimSpace(record[index]), 10, 32); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+
+	conversations := make(map[uint]bool, len(ids))
+	if len(ids) > 0 {
+		var found []uint
+		if err := t.db.Model(&models.ConversationModel{}).
+			Where("organization_id = ? AND id IN ?", organizationID, uniqueIDs(ids)).
+			Pluck("id", &found).Error; err != nil {
+			return nil, nil, nil, errors.New("failed to fetch conversations")
+		}
+		for _, id := range found {
+			conversations[id] = true
+		}
+	}
+
+	staff := make(map[string]uint)
+	if _, ok := columns["assignee_email"]; ok {
+		var users []models.UserModel
+		if err := t.db.Model(&models.UserModel{}).
+			Joins("JOIN user_roles ON user_roles.id = users.role_id").
+			Where("users.organization_id = ?", organizationID).
+			Where("user_roles.name IN ?", []string{models.RoleOrganizationOwner, models.RoleOrganizationSales}).
+			Select("users.id", "users.email").
+			Find(&users).Error; err != nil {
+			return nil, nil, nil, errors.New("failed to fetch staff")
+		}
+		for _, user := range users {
+			staff[strings.ToLower(user.Email)] = user.ID
+		}
+	}
+
+	existing := make(map[string]uint)
+	if index, ok := columns["ticket_number"]; ok {
+		var numbers []string
+		for _, record := range records {
+			if index < len(record) {
+				if number := csvCellValue(strings.TrimSpace(record[index])); number != "" {
+					numbers = append(numbers, number)
+				}
+			}
+		}
+		if len(numbers) > 0 {
+			var tickets []models.TicketModel
+			if err := t.db.Model(&models.TicketModel{}).
+				Where("organization_id = ? AND ticket_number IN ?", organizationID, numbers).
+				Select("id", "ticket_number").
+				Find(&tickets).Error; err != nil {
+				return nil, nil, nil, errors.New("failed to fetch tickets")
+			}
+			for _, ticket := range tickets {
+				existing[ticket.TicketNumber] = ticket.ID
+			}
+		}
+	}
+
+	return conversations, staff, existing, nil
+}
+
+func ticketImportBlank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseTicketImportTime reads the layouts an export writes, times without
+// an offset are in the timezone of the organization.
+func parseTicketImportTime(value string, location *time.Location) (*time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsed, nil
+	}
+	for _, layout := range []string{ticketCSVTimeLayout, "2006-01-02 15:04", "2006-01-02"} {
+		if parsed, err := time.ParseInLocation(layout, value, location); err == nil {
+			return &parsed, nil
+		}
+	}
+	return nil, errors.New("invalid time")
+}
@@ -0,0 +1,216 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTicketFieldNotFound    = errors.New("ticket field not found")
+	ErrTicketFieldKeyInvalid  = errors.New("field key must start with a letter and contain only lowercase letters, digits and underscores")
+	ErrTicketFieldKeyTaken    = errors.New("field key already exists")
+	ErrTicketFieldNoOptions   = errors.New("enum field needs at least one option")
+	ErrTicketFieldOptionInUse = errors.New("option is still set on tickets")
+	ErrTicketFieldRuleInvalid = errors.New("invalid ticket field rule")
+)
+
+type ticketFieldServiceImpl struct {
+	db *gorm.DB
+}
+
+// GetFields implements services.TicketFieldService.
+func (t *ticketFieldServiceImpl) GetFields(user *jwt.Claims) ([]responsedto.TicketFieldResponse, error) {
+	var definitions []models.TicketFieldDefinitionModel
+	if err := t.db.Scopes(organizationScope(user)).
+		Order("id ASC").
+		Find(&definitions).Error; err != nil {
+		return nil, errors.New("failed to fetch ticket fields")
+	}
+
+	responses := make([]responsedto.TicketFieldResponse, 0, len(definitions))
+	for i := range definitions {
+		responses = append(responses, *t.mapToTicketFieldResponse(&definitions[i]))
+	}
+
+	return responses, nil
+}
+
+// CreateField implements services.TicketFieldService.
+func (t *ticketFieldServiceImpl) CreateField(user *jwt.Claims, req requestdto.CreateTicketFieldRequest) (*responsedto.TicketFieldResponse, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+	if !contactAttributeKeyPattern.MatchString(req.Key) {
+		return nil, ErrTicketFieldKeyInvalid
+	}
+
+	definition := models.TicketFieldDefinitionModel{
+		OrganizationID: *user.OrganizationId,
+		Key:            req.Key,
+		Label:          req.Label,
+		Type:           req.Type,
+		Required:       req.Required,
+	}
+	if err := t.fillRules(&definition, req.Options, req.Min, req.Max, req.Pattern); err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := t.db.Model(&models.TicketFieldDefinitionModel{}).
+		Where("organization_id = ? AND `key` = ?", definition.OrganizationID, definition.Key).
+		Count(&count).Error; err != nil {
+		return nil, errors.New("failed to check ticket field")
+	}
+	if count > 0 {
+		return nil, ErrTicketFieldKeyTaken
+	}
+
+	if err := t.db.Create(&definition).Error; err != nil {
+		return nil, errors.New("failed to create ticket field")
+	}
+
+	return t.mapToTicketFieldResponse(&definition), nil
+}
+
+// UpdateField implements services.TicketFieldService. Tickets keep values
+// that no longer follow new rules until the field is changed on them, only
+// removed enum options must be unused.
+func (t *ticketFieldServiceImpl) UpdateField(user *jwt.Claims, fieldID uint, req requestdto.UpdateTicketFieldRequest) (*responsedto.TicketFieldResponse, error) {
+	definition, err := t.findField(user, fieldID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Label != "" {
+		definition.Label = req.Label
+	}
+	definition.Required = req.Required
+
+	options := req.Options
+	if options == nil {
+		options = definition.Options
+	}
+	previous := definition.Options
+	if err := t.fillRules(definition, options, req.Min, req.Max, req.Pattern); err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, option := range previous {
+		if !slices.Contains(definition.Options, option) {
+			removed = append(removed, option)
+		}
+	}
+	if len(removed) > 0 {
+		var inUse int64
+		if err := t.db.Model(&models.TicketFieldValueModel{}).
+			Where("definition_id = ? AND value IN ?", definition.ID, removed).
+			Count(&inUse).Error; err != nil {
+			return nil, errors.New("failed to check ticket field")
+		}
+		if inUse > 0 {
+			return nil, ErrTicketFieldOptionInUse
+		}
+	}
+
+	if err := t.db.Save(definition).Error; err != nil {
+		return nil, errors.New("failed to update ticket field")
+	}
+
+	return t.mapToTicketFieldResponse(definition), nil
+}
+
+// DeleteField implements services.TicketFieldService.
+func (t *ticketFieldServiceImpl) DeleteField(user *jwt.Claims, fieldID uint) error {
+	definition, err := t.findField(user, fieldID)
+	if err != nil {
+		return err
+	}
+
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("definition_id = ?", definition.ID).
+			Delete(&models.TicketFieldValueModel{}).Error; err != nil {
+			return errors.New("failed to delete ticket field values")
+		}
+		if err := tx.Delete(definition).Error; err != nil {
+			return errors.New("failed to delete ticket field")
+		}
+		return nil
+	})
+}
+
+// fillRules sets the options and the validation rules the type allows.
+func (t *ticketFieldServiceImpl) fillRules(definition *models.TicketFieldDefinitionModel, options []string, min *float64, max *float64, pattern *string) error {
+	definition.Options = nil
+	if definition.Type == models.TicketFieldTypeEnum {
+		definition.Options = normalizeContactAttributeOptions(options)
+		if len(definition.Options) == 0 {
+			return ErrTicketFieldNoOptions
+		}
+	}
+
+	bounded := definition.Type == models.TicketFieldTypeNumber || definition.Type == models.TicketFieldTypeString
+	if (min != nil || max != nil) && !bounded {
+		return fmt.Errorf("%w: min and max only apply to number and string fields", ErrTicketFieldRuleInvalid)
+	}
+	if min != nil && max != nil && *min > *max {
+		return fmt.Errorf("%w: min is greater than max", ErrTicketFieldRuleInvalid)
+	}
+	definition.Min = min
+	definition.Max = max
+
+	definition.Pattern = nil
+	if pattern != nil && strings.TrimSpace(*pattern) != "" {
+		if definition.Type != models.TicketFieldTypeString {
+			return fmt.Errorf("%w: pattern only applies to string fields", ErrTicketFieldRuleInvalid)
+		}
+		if _, err := regexp.Compile(*pattern); err != nil {
+			return fmt.Errorf("%w: pattern is not a valid regular expression", ErrTicketFieldRuleInvalid)
+		}
+		definition.Pattern = pattern
+	}
+
+	return nil
+}
+
+func (t *ticketFieldServiceImpl) findField(user *jwt.Claims, fieldID uint) (*models.TicketFieldDefinitionModel, error) {
+	var definition models.TicketFieldDefinitionModel
+	if err := t.db.Scopes(organizationScope(user)).
+		First(&definition, fieldID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTicketFieldNotFound
+		}
+		return nil, errors.New("failed to fetch ticket field")
+	}
+	return &definition, nil
+}
+
+func (t *ticketFieldServiceImpl) mapToTicketFieldResponse(definition *models.TicketFieldDefinitionModel) *responsedto.TicketFieldResponse {
+	return &responsedto.TicketFieldResponse{
+		ID:        definition.ID,
+		Key:       definition.Key,
+		Label:     definition.Label,
+		Type:      definition.Type,
+		Required:  definition.Required,
+		Options:   definition.Options,
+		Min:       definition.Min,
+		Max:       definition.Max,
+		Pattern:   definition.Pattern,
+		CreatedAt: definition.CreatedAt,
+		UpdatedAt: definition.UpdatedAt,
+	}
+}
+
+func NewTicketFieldService(db *gorm.DB) services.TicketFieldService {
+	return &ticketFieldServiceImpl{db: db}
+}
@@ -0,0 +1,298 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTicketFieldUnknown  = errors.New("unknown ticket field")
+	ErrTicketFieldInvalid  = errors.New("invalid ticket field value")
+	ErrTicketFieldRequired = errors.New("ticket field is required")
+)
+
+// normalizeTicketFieldValue validates a raw JSON value against the
+// definition and its rules and returns the text stored in the value
+// column. The types read like contact attributes.
+func normalizeTicketFieldValue(definition *models.TicketFieldDefinitionModel, raw any) (string, error) {
+	value, err := normalizeContactAttributeValue(&models.ContactAttributeDefinitionModel{
+		Key:     definition.Key,
+		Type:    definition.Type,
+		Options: definition.Options,
+	}, raw)
+	if err != nil {
+		if definition.Type == models.TicketFieldTypeEnum {
+			return "", fmt.Errorf("%w: %s must be one of %s", ErrTicketFieldInvalid, definition.Key, strings.Join(definition.Options, ", "))
+		}
+		return "", fmt.Errorf("%w: %s must be a %s", ErrTicketFieldInvalid, definition.Key, definition.Type)
+	}
+
+	switch definition.Type {
+	case models.TicketFieldTypeNumber:
+		number, _ := strconv.ParseFloat(value, 64)
+		if definition.Min != nil && number < *definition.Min {
+			return "", fmt.Errorf("%w: %s must be at least %v", ErrTicketFieldInvalid, definition.Key, *definition.Min)
+		}
+		if definition.Max != nil && number > *definition.Max {
+			return "", fmt.Errorf("%w: %s must be at most %v", ErrTicketFieldInvalid, definition.Key, *definition.Max)
+		}
+
+	case models.TicketFieldTypeString:
+		length := float64(len([]rune(value)))
+		if definition.Min != nil && length < *definition.Min {
+			return "", fmt.Errorf("%w: %s must be at least %v characters", ErrTicketFieldInvalid, definition.Key, *definition.Min)
+		}
+		if definition.Max != nil && length > *definition.Max {
+			return "", fmt.Errorf("%w: %s must be at most %v characters", ErrTicketFieldInvalid, definition.Key, *definition.Max)
+		}
+		if definition.Pattern != nil {
+			pattern, err := regexp.Compile(*definition.Pattern)
+			if err != nil || !pattern.MatchString(value) {
+				return "", fmt.Errorf("%w: %s does not match %s", ErrTicketFieldInvalid, definition.Key, *definition.Pattern)
+			}
+		}
+	}
+
+	return value, nil
+}
+
+func loadTicketFieldDefinitions(tx *gorm.DB, organizationID uint) (map[string]*models.TicketFieldDefinitionModel, error) {
+	var definitions []models.TicketFieldDefinitionModel
+	if err := tx.Where("organization_id = ?", organizationID).
+		Order("id ASC").
+		Find(&definitions).Error; err != nil {
+		return nil, errors.New("failed to fetch ticket fields")
+	}
+
+	byKey := make(map[string]*models.TicketFieldDefinitionModel, len(definitions))
+	for i := range definitions {
+		byKey[definitions[i].Key] = &definitions[i]
+	}
+	return byKey, nil
+}
+
+// validateTicketFields checks the values by field key and returns them
+// normalized, nil for a field to clear. A new ticket must have every
+// required field, an existing one may only not clear them.
+func validateTicketFields(definitions map[string]*models.TicketFieldDefinitionModel, values map[string]any, creating bool) (map[string]*string, error) {
+	normalized := make(map[string]*string, len(values))
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		definition, ok := definitions[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTicketFieldUnknown, key)
+		}
+
+		raw := values[key]
+		if text, isText := raw.(string); isText && strings.TrimSpace(text) == "" {
+			raw = nil
+		}
+		if raw == nil {
+			if definition.Required {
+				return nil, fmt.Errorf("%w: %s", ErrTicketFieldRequired, key)
+			}
+			normalized[key] = nil
+			continue
+		}
+
+		value, err := normalizeTicketFieldValue(definition, raw)
+		if err != nil {
+			return nil, err
+		}
+		normalized[key] = &value
+	}
+
+	if creating {
+		missing := make([]string, 0)
+		for key, definition := range definitions {
+			if definition.Required && normalized[key] == nil {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return nil, fmt.Errorf("%w: %s", ErrTicketFieldRequired, strings.Join(missing, ", "))
+		}
+	}
+
+	return normalized, nil
+}
+
+// setTicketFields stores the custom field values of the ticket. Changes of
+// an existing ticket go to its activity log like its other fields.
+func setTicketFields(tx *gorm.DB, ticket *models.TicketModel, values map[string]any, creating bool, actorID *uint) error {
+	definitions, err := loadTicketFieldDefinitions(tx, ticket.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if len(values) == 0 && (!creating || len(definitions) == 0) {
+		return nil
+	}
+
+	normalized, err := validateTicketFields(definitions, values, creating)
+	if err != nil {
+		return err
+	}
+
+	current := map[uint]string{}
+	if !creating {
+		var stored []models.TicketFieldValueModel
+		if err := tx.Where("ticket_id = ?", ticket.ID).Find(&stored).Error; err != nil {
+			return errors.New("failed to fetch ticket fields")
+		}
+		for _, value := range stored {
+			current[value.DefinitionID] = value.Value
+		}
+	}
+
+	keys := make([]string, 0, len(normalized))
+	for key := range normalized {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		definition := definitions[key]
+		value := normalized[key]
+		previous, had := current[definition.ID]
+
+		if value == nil {
+			if !had {
+				continue
+			}
+			if err := tx.Where("ticket_id = ? AND definition_id = ?", ticket.ID, definition.ID).
+				Delete(&models.TicketFieldValueModel{}).Error; err != nil {
+				return errors.New("failed to clear ticket field")
+			}
+		} else {
+			if had && previous == *value {
+				continue
+			}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "ticket_id"}, {Name: "definition_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+			}).Create(&models.TicketFieldValueModel{
+				OrganizationID: ticket.OrganizationID,
+				TicketID:       ticket.ID,
+				DefinitionID:   definition.ID,
+				Value:          *value,
+			}).Error; err != nil {
+				return errors.New("failed to store ticket field")
+			}
+		}
+
+		if creating {
+			continue
+		}
+		field := "fields." + key
+		activity := &models.TicketActivityModel{
+			OrganizationID: ticket.OrganizationID,
+			TicketID:       ticket.ID,
+			ActorID:        actorID,
+			Action:         models.TicketActivityUpdated,
+			Field:          &field,
+			NewValue:       value,
+		}
+		if had {
+			activity.OldValue = &previous
+		}
+		if err := recordTicketActivity(tx, activity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ticketFieldOutput turns a stored value back into its JSON type.
+func ticketFieldOutput(definition *models.TicketFieldDefinitionModel, value string) any {
+	return contactAttributeOutput(&models.ContactAttributeDefinitionModel{Type: definition.Type}, value)
+}
+
+// loadTicketFields returns the custom field values of the tickets keyed by
+// ticket and field key.
+func loadTicketFields(db *gorm.DB, ticketIDs []uint) (map[uint]map[string]any, error) {
+	fields := make(map[uint]map[string]any, len(ticketIDs))
+	if len(ticketIDs) == 0 {
+		return fields, nil
+	}
+
+	var values []models.TicketFieldValueModel
+	if err := db.Preload("Definition").
+		Where("ticket_id IN ?", ticketIDs).
+		Find(&values).Error; err != nil {
+		return nil, errors.New("failed to fetch ticket fields")
+	}
+
+	for _, value := range values {
+		if value.Definition == nil {
+			continue
+		}
+		if fields[value.TicketID] == nil {
+			fields[value.TicketID] = map[string]any{}
+		}
+		fields[value.TicketID][value.Definition.Key] = ticketFieldOutput(value.Definition, value.Value)
+	}
+	return fields, nil
+}
+
+// ticketFieldFilters narrows a ticket query to the tickets having every
+// field set to the given value, normalized like stored values so "TRUE"
+// finds true.
+func ticketFieldFilters(tx *gorm.DB, organizationID uint, filters map[string]string) (func(*gorm.DB) *gorm.DB, error) {
+	if len(filters) == 0 {
+		return func(db *gorm.DB) *gorm.DB { return db }, nil
+	}
+
+	definitions, err := loadTicketFieldDefinitions(tx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	type condition struct {
+		definitionID uint
+		value        string
+	}
+	conditions := make([]condition, 0, len(filters))
+	for key, raw := range filters {
+		definition, ok := definitions[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTicketFieldUnknown, key)
+		}
+		value, err := normalizeContactAttributeValue(&models.ContactAttributeDefinitionModel{
+			Key:     definition.Key,
+			Type:    definition.Type,
+			Options: definition.Options,
+		}, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be a %s", ErrTicketFieldInvalid, key, definition.Type)
+		}
+		conditions = append(conditions, condition{definitionID: definition.ID, value: value})
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		for _, c := range conditions {
+			db = db.Where(`EXISTS (
+				SELECT 1 FROM ticket_field_values
+				WHERE ticket_field_values.ticket_id = tickets.id
+				AND ticket_field_values.definition_id = ?
+				AND ticket_field_values.value = ?
+			)`, c.definitionID, c.value)
+		}
+		return db
+	}, nil
+}
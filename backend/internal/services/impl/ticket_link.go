@@ -0,0 +1,248 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTicketLinkInvalid          = errors.New("invalid ticket link")
+	ErrTicketLinkExists           = errors.New("the tickets are already linked")
+	ErrTicketLinkNotFound         = errors.New("ticket link not found")
+	ErrTicketConversationExists   = errors.New("the conversation is already part of the ticket")
+	ErrTicketConversationPrimary  = errors.New("the conversation the ticket was created from can not be removed")
+	ErrTicketConversationNotFound = errors.New("conversation not found")
+)
+
+// storedTicketLink turns a link type read from ticketID into the row that
+// is stored, parent and duplicate rows always point from the parent and
+// from the duplicate.
+func storedTicketLink(ticketID uint, otherID uint, view string) (uint, uint, string) {
+	switch view {
+	case models.TicketLinkParentOf:
+		return ticketID, otherID, models.TicketLinkParent
+	case models.TicketLinkChildOf:
+		return otherID, ticketID, models.TicketLinkParent
+	case models.TicketLinkDuplicateOf:
+		return ticketID, otherID, models.TicketLinkDuplicate
+	case models.TicketLinkDuplicatedBy:
+		return otherID, ticketID, models.TicketLinkDuplicate
+	}
+	return ticketID, otherID, models.TicketLinkRelated
+}
+
+// ticketLinkView is the other ticket of the link and the link type read
+// from ticketID, both tickets must be loaded.
+func ticketLinkView(link *models.TicketLinkModel, ticketID uint) (string, *models.TicketModel) {
+	outgoing := link.TicketID == ticketID
+	switch link.Type {
+	case models.TicketLinkParent:
+		if outgoing {
+			return models.TicketLinkParentOf, link.LinkedTicket
+		}
+		return models.TicketLinkChildOf, link.Ticket
+	case models.TicketLinkDuplicate:
+		if outgoing {
+			return models.TicketLinkDuplicateOf, link.LinkedTicket
+		}
+		return models.TicketLinkDuplicatedBy, link.Ticket
+	}
+	if outgoing {
+		return models.TicketLinkRelatedTo, link.LinkedTicket
+	}
+	return models.TicketLinkRelatedTo, link.Ticket
+}
+
+// validateTicketLink checks the new link against the links the tickets
+// already have. A ticket has at most one parent and is a duplicate of at
+// most one ticket, and parents never form a cycle.
+func validateTicketLink(tx *gorm.DB, link *models.TicketLinkModel) error {
+	if link.TicketID == link.LinkedTicketID {
+		return fmt.Errorf("%w: a ticket can not be linked to itself", ErrTicketLinkInvalid)
+	}
+
+	var count int64
+	if err := tx.Model(&models.TicketLinkModel{}).
+		Where("(ticket_id = ? AND linked_ticket_id = ?) OR (ticket_id = ? AND linked_ticket_id = ?)",
+			link.TicketID, link.LinkedTicketID, link.LinkedTicketID, link.TicketID).
+		Count(&count).Error; err != nil {
+		return errors.New("failed to check ticket links")
+	}
+	if count > 0 {
+		return ErrTicketLinkExists
+	}
+
+	switch link.Type {
+	case models.TicketLinkParent:
+		if err := tx.Model(&models.TicketLinkModel{}).
+			Where("type = ? AND linked_ticket_id = ?", models.TicketLinkParent, link.LinkedTicketID).
+			Count(&count).Error; err != nil {
+			return errors.New("failed to check ticket links")
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: the child ticket already has a parent", ErrTicketLinkInvalid)
+		}
+
+		seen := map[uint]bool{}
+		for current := link.TicketID; !seen[current]; {
+			seen[current] = true
+			var parentIDs []uint
+			if err := tx.Model(&models.TicketLinkModel{}).
+				Where("type = ? AND linked_ticket_id = ?", models.TicketLinkParent, current).
+				Pluck("ticket_id", &parentIDs).Error; err != nil {
+				return errors.New("failed to check ticket links")
+			}
+			if len(parentIDs) == 0 {
+				break
+			}
+			if parentIDs[0] == link.LinkedTicketID {
+				return fmt.Errorf("%w: the child ticket is already an ancestor of the parent", ErrTicketLinkInvalid)
+			}
+			current = parentIDs[0]
+		}
+
+	case models.TicketLinkDuplicate:
+		if err := tx.Model(&models.TicketLinkModel{}).
+			Where("type = ? AND ticket_id = ?", models.TicketLinkDuplicate, link.TicketID).
+			Count(&count).Error; err != nil {
+			return errors.New("failed to check ticket links")
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: the ticket is already a duplicate of another ticket", ErrTicketLinkInvalid)
+		}
+	}
+
+	return nil
+}
+
+// cascadeTicketStatus moves every descendant of the ticket to its status
+// and returns the tickets that changed. The workflow transitions are not
+// checked, the parent decides for its children. The children are locked
+// and only the columns the cascade changes are written.
+func cascadeTicketStatus(tx *gorm.DB, parent *models.TicketModel, actorID uint) ([]models.TicketModel, error) {
+	var changed []models.TicketModel
+	seen := map[uint]bool{parent.ID: true}
+	queue := []uint{parent.ID}
+
+	for len(queue) > 0 {
+		var childIDs []uint
+		if err := tx.Model(&models.TicketLinkModel{}).
+			Where("type = ? AND ticket_id IN ?", models.TicketLinkParent, queue).
+			Pluck("linked_ticket_id", &childIDs).Error; err != nil {
+			return nil, errors.New("failed to fetch child tickets")
+		}
+		queue = nil
+		if len(childIDs) == 0 {
+			break
+		}
+
+		var children []models.TicketModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("organization_id = ? AND id IN ?", parent.OrganizationID, childIDs).
+			Order("id").
+			Find(&children).Error; err != nil {
+			return nil, errors.New("failed to fetch child tickets")
+		}
+
+		for _, child := range children {
+			if seen[child.ID] {
+				continue
+			}
+			seen[child.ID] = true
+			queue = append(queue, child.ID)
+
+			if child.Status == parent.Status {
+				continue
+			}
+
+			before := child
+			child.Status = parent.Status
+			child.StatusCategory = parent.StatusCategory
+			if child.ResolutionNote == nil {
+				child.ResolutionNote = parent.ResolutionNote
+			}
+			if before.StatusCategory == models.TicketStatusCategoryClosed && child.StatusCategory == models.TicketStatusCategoryOpen {
+				if err := restartTicketSLA(tx, &child, time.Now()); err != nil {
+					return nil, err
+				}
+			}
+
+			if err := tx.Model(&child).
+				Select(ticketUpdateColumns).
+				Updates(&child).Error; err != nil {
+				return nil, errors.New("failed to update child ticket")
+			}
+			if err := recordTicketChanges(tx, &before, &child, &actorID); err != nil {
+				return nil, err
+			}
+			if err := recordStatusChange(tx, child.OrganizationID, models.StatusChangeSubjectTicket, child.ID, child.ConversationID, before.Status, child.Status, &actorID); err != nil {
+				return nil, err
+			}
+			if err := publishEvent(tx, child.OrganizationID, models.EventTicketUpdated, ticketEvent(&child, &before.Status)); err != nil {
+				return nil, err
+			}
+
+			changed = append(changed, child)
+		}
+	}
+
+	return changed, nil
+}
+
+// notifyTicketCustomers tells the customers of the closed tickets and of
+// their duplicates that the problem is resolved, once per conversation. A
+// conversation the reply can not be prepared for is skipped. The resolution
+// note is written for staff and stays out of the message.
+func notifyTicketCustomers(tx *gorm.DB, ticket *models.TicketModel, ticketIDs []uint, authorID uint) error {
+	var duplicateIDs []uint
+	if err := tx.Model(&models.TicketLinkModel{}).
+		Where("type = ? AND linked_ticket_id IN ?", models.TicketLinkDuplicate, ticketIDs).
+		Pluck("ticket_id", &duplicateIDs).Error; err != nil {
+		return errors.New("failed to fetch duplicate tickets")
+	}
+
+	var conversationIDs []uint
+	if err := tx.Model(&models.TicketConversationModel{}).
+		Where("ticket_id IN ?", append(ticketIDs, duplicateIDs...)).
+		Distinct().
+		Pluck("conversation_id", &conversationIDs).Error; err != nil {
+		return errors.New("failed to fetch ticket conversations")
+	}
+	if len(conversationIDs) == 0 {
+		return nil
+	}
+
+	var conversations []models.ConversationModel
+	if err := tx.Where("organization_id = ? AND id IN ?", ticket.OrganizationID, conversationIDs).
+		Preload("Organization").
+		Preload("Guest").
+		Preload("Contact.Identities").
+		Find(&conversations).Error; err != nil {
+		return errors.New("failed to fetch ticket conversations")
+	}
+
+	text := fmt.Sprintf("Ticket %s \"%s\" covering your request has been resolved.", ticket.TicketNumber, ticket.Name)
+
+	for i := range conversations {
+		message, reply, err := prepareStaffReply(tx, &conversations[i], authorID, text)
+		if err != nil {
+			logger.ErrorLog("Failed to notify customer of resolved ticket", map[string]any{
+				"ticketId":       ticket.ID,
+				"conversationId": conversations[i].ID,
+				"error":          err.Error(),
+			})
+			continue
+		}
+		if err := deliverStaffReply(tx, &conversations[i], message, reply); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrTicketSortInvalid = errors.New("invalid ticket sort")
+
+// maxTicketPageSize caps the page so one request can not load every
+// ticket of a large organization.
+const maxTicketPageSize = 100
+
+// ticketSortField is a column the ticket list can be sorted on, nullable
+// columns put the tickets without a value last in both directions.
+type ticketSortField struct {
+	column   string
+	nullable bool
+}
+
+var ticketSortFields = map[string]ticketSortField{
+	"createdAt":    {column: "tickets.created_at"},
+	"updatedAt":    {column: "tickets.updated_at"},
+	"ticketNumber": {column: "tickets.ticket_number"},
+	"name":         {column: "tickets.name"},
+	"status":       {column: "tickets.status"},
+	"priority":     {column: "FIELD(tickets.priority, 'low', 'normal', 'high', 'urgent')"},
+	"dueAt":        {column: "tickets.due_at", nullable: true},
+	"slaDueAt":     {column: "tickets.sla_due_at", nullable: true},
+}
+
+// applyTicketSort orders the query by the sort of the filter, newest first
+// by default. The id comes last so pages never overlap.
+func applyTicketSort(query *gorm.DB, sort string) (*gorm.DB, error) {
+	if strings.TrimSpace(sort) == "" {
+		sort = "-createdAt"
+	}
+
+	seen := map[string]bool{}
+	for _, part := range strings.Split(sort, ",") {
+		name := strings.TrimSpace(part)
+		direction := "ASC"
+		if strings.HasPrefix(name, "-") {
+			name, direction = name[1:], "DESC"
+		}
+
+		field, ok := ticketSortFields[name]
+		if !ok || seen[name] {
+			return nil, fmt.Errorf("%w: %q", ErrTicketSortInvalid, part)
+		}
+		seen[name] = true
+
+		if field.nullable {
+			query = query.Order(field.column + " IS NULL")
+		}
+		query = query.Order(field.column + " " + direction)
+	}
+
+	return query.Order("tickets.id DESC"), nil
+}
+
+// filterTickets is the query of the tickets of the organization matching
+// the filter, custom fields included.
+func filterTickets(db *gorm.DB, organizationID uint, filter filtersdto.TicketFiltersDto, now time.Time) (*gorm.DB, error) {
+	fields, err := ticketFieldFilters(db, organizationID, filter.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return applyTicketFilters(
+		db.Model(&models.TicketModel{}).Where("tickets.organization_id = ?", organizationID),
+		filter,
+		now,
+	).Scopes(fields), nil
+}
+
+// applyTicketFilters narrows the ticket query to the filter.
+func applyTicketFilters(query *gorm.DB, filter filtersdto.TicketFiltersDto, now time.Time) *gorm.DB {
+	if filter.AssigneeID != nil {
+		query = query.Where("tickets.assignee_id = ?", *filter.AssigneeID)
+	}
+	if filter.CreatedByID != nil {
+		query = query.Where("tickets.created_by_id = ?", *filter.CreatedByID)
+	}
+	if filter.ConversationID != nil {
+		query = query.Where("tickets.id IN (?)", query.Session(&gorm.Session{NewDB: true}).
+			Model(&models.TicketConversationModel{}).
+			Select("ticket_id").
+			Where("conversation_id = ?", *filter.ConversationID))
+	}
+	if len(filter.Status) > 0 {
+		query = query.Where("tickets.status IN ?", filter.Status)
+	}
+	if filter.StatusCategory != "" {
+		query = query.Where("tickets.status_category = ?", filter.StatusCategory)
+	}
+	if filter.Priority != "" {
+		query = query.Where("tickets.priority = ?", filter.Priority)
+	}
+	if filter.Category != "" {
+		query = query.Where("tickets.category = ?", filter.Category)
+	}
+	if filter.Tag != "" {
+		query = query.Where("tickets.id IN (?)", taggedWith(query, models.TagSubjectTicket, filter.Tag))
+	}
+	if filter.Overdue {
+		query = query.Where("tickets.due_at < ? AND tickets.status_category = ?", now, models.TicketStatusCategoryOpen)
+	}
+	if filter.NumberPrefix != "" {
+		query = query.Where("tickets.ticket_number LIKE ?", escapeLike(filter.NumberPrefix)+"%")
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("tickets.created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("tickets.created_at < ?", *filter.CreatedTo)
+	}
+	if filter.DueFrom != nil {
+		query = query.Where("tickets.due_at >= ?", *filter.DueFrom)
+	}
+	if filter.DueTo != nil {
+		query = query.Where("tickets.due_at < ?", *filter.DueTo)
+	}
+	return query
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike keeps the wildcards of user input literal in a LIKE pattern.
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
@@ -0,0 +1,146 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrTicketNumberFormatInvalid = errors.New("ticket number prefix must be letters and digits separated by dashes")
+
+var ticketPrefixPattern = regexp.MustCompile(`^[A-Za-z0-9]+(-[A-Za-z0-9]+)*$`)
+
+// ticketNumberPeriod is the date part of a ticket number issued at now.
+func ticketNumberPeriod(datePart string, now time.Time) string {
+	switch datePart {
+	case models.TicketDatePartYear:
+		return now.Format("2006")
+	case models.TicketDatePartMonth:
+		return now.Format("200601")
+	case models.TicketDatePartDay:
+		return now.Format("20060102")
+	default:
+		return ""
+	}
+}
+
+func formatTicketNumber(sequence *models.TicketSequenceModel, period string, value uint) string {
+	parts := []string{sequence.Prefix}
+	if period != "" {
+		parts = append(parts, period)
+	}
+	parts = append(parts, fmt.Sprintf("%0*d", sequence.Padding, value))
+	return strings.Join(parts, "-")
+}
+
+// ensureTicketSequence creates the organization's sequence with the default
+// format, inside the caller's transaction. The insert of a sequence that
+// already exists updates the row to itself instead of being skipped: a
+// skipped insert would hold a shared lock on the row and two tickets created
+// at once would deadlock on the locking read that follows, the update takes
+// the exclusive lock right away and the second ticket waits for the first.
+func ensureTicketSequence(tx *gorm.DB, organizationID uint) error {
+	var count int64
+	if err := tx.Model(&models.TicketSequenceModel{}).
+		Where("organization_id = ?", organizationID).
+		Count(&count).Error; err != nil {
+		return errors.New("failed to fetch ticket sequence")
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if err := tx.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{"organization_id": gorm.Expr("organization_id")}),
+	}).Create(&models.TicketSequenceModel{
+		OrganizationID: organizationID,
+		Prefix:         models.TicketNumberDefaultPrefix,
+		DatePart:       models.TicketDatePartDay,
+		Padding:        models.TicketNumberDefaultPadding,
+	}).Error; err != nil {
+		return errors.New("failed to create ticket sequence")
+	}
+	return nil
+}
+
+// nextTicketNumber takes the next number of the organization. The locking
+// read holds the sequence row until the transaction ends, so tickets created
+// at the same time wait for each other and never share a number.
+func nextTicketNumber(tx *gorm.DB, organizationID uint, now time.Time) (string, error) {
+	var sequence models.TicketSequenceModel
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("organization_id = ?", organizationID).
+		First(&sequence).Error; err != nil {
+		return "", errors.New("failed to fetch ticket sequence")
+	}
+
+	// the date part follows the calendar of the organization
+	location, err := organizationLocation(tx, organizationID)
+	if err != nil {
+		return "", err
+	}
+	period := ticketNumberPeriod(sequence.DatePart, now.In(location))
+	if sequence.Period != period {
+		sequence.Period = period
+		sequence.LastValue = 0
+	}
+
+	// a number can already exist when the format went back to an earlier
+	// one, soft deleted tickets keep theirs as well
+	var number string
+	for {
+		sequence.LastValue++
+		number = formatTicketNumber(&sequence, sequence.Period, sequence.LastValue)
+
+		var taken int64
+		if err := tx.Model(&models.TicketModel{}).
+			Unscoped().
+			Where("organization_id = ? AND ticket_number = ?", organizationID, number).
+			Count(&taken).Error; err != nil {
+			return "", errors.New("failed to check ticket number")
+		}
+		if taken == 0 {
+			break
+		}
+	}
+
+	if err := tx.Model(&sequence).Updates(map[string]any{
+		"period":     sequence.Period,
+		"last_value": sequence.LastValue,
+	}).Error; err != nil {
+		return "", errors.New("failed to take ticket number")
+	}
+
+	return number, nil
+}
+
+// reserveTicketNumbers takes count numbers of the organization in a
+// transaction of its own, so a long import does not hold the sequence row
+// and keep every other ticket of the organization waiting. The numbers of
+// an import that fails afterwards are not given back.
+func reserveTicketNumbers(db *gorm.DB, organizationID uint, count int, now time.Time) ([]string, error) {
+	numbers := make([]string, 0, count)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureTicketSequence(tx, organizationID); err != nil {
+			return err
+		}
+		for range count {
+			number, err := nextTicketNumber(tx, organizationID, now)
+			if err != nil {
+				return err
+			}
+			numbers = append(numbers, number)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return numbers, nil
+}
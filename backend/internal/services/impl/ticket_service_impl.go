@@ -6,12 +6,21 @@ import (
 	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
 	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
 	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/mail"
 	"DewaSRY/sociomile-app/pkg/models"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTicketNotFound        = errors.New("ticket not found")
+	ErrTicketAssigneeInvalid = errors.New("assignee must be a staff member of the ticket's organization")
+	ErrTicketCommentEmpty    = errors.New("comment body must not be empty")
 )
 
 type OrganizationTicketServiceImpl struct {
@@ -20,15 +29,76 @@ type OrganizationTicketServiceImpl struct {
 
 // CreateTicket implements services.TicketService.
 func (t *OrganizationTicketServiceImpl) CreateTicket(user *jwtLib.Claims, req requestdto.CreateTicketRequest) error {
+	// sales only see the tickets assigned to them, so an unassigned ticket
+	// they create is assigned to them
+	if req.AssigneeID == nil {
+		role, err := userRoleName(t.db, user.RoleID)
+		if err != nil {
+			return err
+		}
+		if role == models.RoleOrganizationSales {
+			req.AssigneeID = &user.UserID
+		}
+	}
+
+	conversation, initial, err := t.prepareTicket(user, req)
+	if err != nil {
+		return err
+	}
+
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureTicketSequence(tx, conversation.OrganizationID); err != nil {
+			return err
+		}
+		ticketNumber, err := nextTicketNumber(tx, conversation.OrganizationID, time.Now())
+		if err != nil {
+			return err
+		}
+		return insertTicket(tx, user, conversation, initial, req, ticketNumber)
+	})
+}
+
+// prepareTicket checks a new ticket against the conversations and the
+// staff of the caller, and returns its conversation and the status it
+// starts in.
+func (t *OrganizationTicketServiceImpl) prepareTicket(user *jwtLib.Claims, req requestdto.CreateTicketRequest) (*models.ConversationModel, *models.TicketWorkflowStatus, error) {
+	access, err := conversationAccessScope(t.db, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var conversation models.ConversationModel
-	if err := t.db.First(&conversation, req.ConversationID).Error; err != nil {
+	if err := t.db.Scopes(access).First(&conversation, req.ConversationID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("conversation not found")
+			return nil, nil, ErrTicketConversationNotFound
 		}
-		return errors.New("failed to fetch conversation")
+		return nil, nil, errors.New("failed to fetch conversation")
+	}
+
+	if req.AssigneeID != nil {
+		if err := validateTicketAssignee(t.db, conversation.OrganizationID, *req.AssigneeID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	workflow, err := loadTicketWorkflow(t.db, conversation.OrganizationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	initial, ok := workflowStatus(workflow, workflow.InitialStatus)
+	if !ok {
+		return nil, nil, ErrTicketStatusInvalid
 	}
 
-	ticketNumber := t.generateTicketNumber(conversation.OrganizationID)
+	return &conversation, initial, nil
+}
+
+// insertTicket creates a prepared ticket under the given number.
+func insertTicket(tx *gorm.DB, user *jwtLib.Claims, conversation *models.ConversationModel, initial *models.TicketWorkflowStatus, req requestdto.CreateTicketRequest, ticketNumber string) error {
+	priority := req.Priority
+	if priority == "" {
+		priority = models.TicketPriorityNormal
+	}
 
 	ticket := models.TicketModel{
 		OrganizationID: conversation.OrganizationID,
@@ -36,62 +106,967 @@ func (t *OrganizationTicketServiceImpl) CreateTicket(user *jwtLib.Claims, req re
 		CreatedByID:    user.UserID,
 		TicketNumber:   ticketNumber,
 		Name:           req.Name,
-		Status:         models.TicketStatusPending,
+		Status:         initial.Key,
+		StatusCategory: initial.Category,
+		AssigneeID:     req.AssigneeID,
+		Priority:       priority,
+		DueAt:          req.DueAt,
+		Category:       req.Category,
+		Description:    req.Description,
 	}
-
-	if err := t.db.Create(&ticket).Error; err != nil {
+	if err := applyTicketSLA(tx, &ticket, time.Now()); err != nil {
+		return err
+	}
+	if err := tx.Create(&ticket).Error; err != nil {
 		return errors.New("failed to create ticket")
 	}
-	return nil
+	if err := setTicketFields(tx, &ticket, req.Fields, true, &user.UserID); err != nil {
+		return err
+	}
+	if err := tx.Create(&models.TicketConversationModel{
+		OrganizationID: ticket.OrganizationID,
+		TicketID:       ticket.ID,
+		ConversationID: ticket.ConversationID,
+	}).Error; err != nil {
+		return errors.New("failed to add ticket conversation")
+	}
+
+	if err := recordTicketActivity(tx, &models.TicketActivityModel{
+		OrganizationID: ticket.OrganizationID,
+		TicketID:       ticket.ID,
+		ActorID:        &user.UserID,
+		Action:         models.TicketActivityCreated,
+	}); err != nil {
+		return err
+	}
+
+	return publishEvent(tx, ticket.OrganizationID, models.EventTicketCreated, ticketEvent(&ticket, nil))
 }
 
-// GetTicketsList implements services.TicketService.
-func (t *OrganizationTicketServiceImpl) GetTicketsList(user *jwtLib.Claims, filter filtersdto.FiltersDto) (*responsedto.TicketListResponse, error) {
+// GetTicketsList implements services.TicketService. The total is counted
+// with the filters only, the page and the sort apply to the tickets.
+func (t *OrganizationTicketServiceImpl) GetTicketsList(user *jwtLib.Claims, filter filtersdto.FiltersDto, ticketFilter filtersdto.TicketFiltersDto) (*responsedto.TicketListResponse, error) {
+	limit := *filter.Limit
+	if limit > maxTicketPageSize {
+		limit = maxTicketPageSize
+	}
+	offset := (*filter.Page - 1) * limit
+
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+	access, err := ticketAccessScope(t.db, user)
+	if err != nil {
+		return nil, err
+	}
+	filtered, err := filterTickets(t.db, *user.OrganizationId, ticketFilter, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	query := filtered.Scopes(access)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, errors.New("failed to count tickets")
+	}
+
+	sorted, err := applyTicketSort(query.Session(&gorm.Session{}), ticketFilter.Sort)
+	if err != nil {
+		return nil, err
+	}
+
 	var tickets []models.TicketModel
-	if err := t.db.Where("organization_id = ?", user.OrganizationId).
-		Preload("Conversation").Preload("CreatedBy").
-		Order("created_at DESC").
+	if err := sorted.
+		Preload("Conversation").Preload("CreatedBy").Preload("Assignee").
+		Offset(offset).Limit(limit).
 		Find(&tickets).Error; err != nil {
 		return nil, errors.New("failed to fetch tickets")
 	}
 
-	return t.buildTicketListResponse(tickets), nil
+	ids := make([]uint, 0, len(tickets))
+	for _, ticket := range tickets {
+		ids = append(ids, ticket.ID)
+	}
+	tags, err := loadTags(t.db, models.TagSubjectTicket, ids)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := loadTicketFields(t.db, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.buildTicketListResponse(tickets, tags, fields, int(total), *filter.Page, limit), nil
 }
 
-// UpdateTicket implements services.TicketService.
+// UpdateTicket implements services.TicketService. The ticket is read again
+// with a lock inside the transaction and only the columns the request can
+// change are written, so an SLA escalation running at the same time is not
+// undone.
 func (t *OrganizationTicketServiceImpl) UpdateTicket(user *jwtLib.Claims, ticketID uint, req requestdto.UpdateTicketRequest) error {
+	access, err := ticketAccessScope(t.db, user)
+	if err != nil {
+		return err
+	}
+
+	if req.AssigneeID != nil && *req.AssigneeID != 0 {
+		if user.OrganizationId == nil {
+			return ErrTicketNotFound
+		}
+		if err := validateTicketAssignee(t.db, *user.OrganizationId, *req.AssigneeID); err != nil {
+			return err
+		}
+	}
+
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		// the workflow is locked before the ticket, in the same order as
+		// UpdateWorkflow, so a status it removes can not be saved meanwhile
+		var workflow *models.TicketWorkflowModel
+		if req.Status != "" {
+			if user.OrganizationId == nil {
+				return ErrTicketNotFound
+			}
+			var err error
+			if workflow, err = lockTicketWorkflow(tx, *user.OrganizationId); err != nil {
+				return err
+			}
+		}
+
+		var ticket models.TicketModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Scopes(access).
+			First(&ticket, ticketID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrTicketNotFound
+			}
+			return errors.New("failed to fetch ticket")
+		}
+
+		before := ticket
+		previousStatus := ticket.Status
+		if req.Name != "" {
+			ticket.Name = req.Name
+		}
+		if req.Status != "" && req.Status != ticket.Status {
+			status, ok := workflowStatus(workflow, req.Status)
+			if !ok {
+				return ErrTicketStatusInvalid
+			}
+			if !workflowAllows(workflow, ticket.Status, req.Status) {
+				return ErrTicketTransitionNotAllowed
+			}
+			if status.RequiresResolutionNote && (req.ResolutionNote == nil || strings.TrimSpace(*req.ResolutionNote) == "") {
+				return ErrTicketResolutionNoteRequired
+			}
+			ticket.Status = status.Key
+			ticket.StatusCategory = status.Category
+		}
+		if req.ResolutionNote != nil {
+			note := strings.TrimSpace(*req.ResolutionNote)
+			ticket.ResolutionNote = &note
+			if note == "" {
+				ticket.ResolutionNote = nil
+			}
+		}
+		if req.AssigneeID != nil {
+			ticket.AssigneeID = req.AssigneeID
+			if *req.AssigneeID == 0 {
+				ticket.AssigneeID = nil
+			}
+		}
+		if req.Priority != "" {
+			ticket.Priority = req.Priority
+		}
+		if req.ClearDueAt {
+			ticket.DueAt = nil
+		} else if req.DueAt != nil {
+			ticket.DueAt = req.DueAt
+		}
+		if req.Category != nil {
+			ticket.Category = req.Category
+			if *req.Category == "" {
+				ticket.Category = nil
+			}
+		}
+		if req.Description != nil {
+			ticket.Description = req.Description
+			if *req.Description == "" {
+				ticket.Description = nil
+			}
+		}
+
+		now := time.Now()
+		reopened := before.StatusCategory == models.TicketStatusCategoryClosed && ticket.StatusCategory == models.TicketStatusCategoryOpen
+		if reopened {
+			if err := restartTicketSLA(tx, &ticket, now); err != nil {
+				return err
+			}
+		} else if ticket.Priority != before.Priority || !sameTicketValue(ticket.Category, before.Category) {
+			if err := applyTicketSLA(tx, &ticket, now); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&ticket).
+			Select(ticketUpdateColumns).
+			Updates(&ticket).Error; err != nil {
+			return errors.New("failed to update ticket")
+		}
+
+		if err := recordTicketChanges(tx, &before, &ticket, &user.UserID); err != nil {
+			return err
+		}
+		if err := setTicketFields(tx, &ticket, req.Fields, false, &user.UserID); err != nil {
+			return err
+		}
+
+		var statusChangedFrom *string
+		if previousStatus != ticket.Status {
+			statusChangedFrom = &previousStatus
+			if err := recordStatusChange(tx, ticket.OrganizationID, models.StatusChangeSubjectTicket, ticket.ID, ticket.ConversationID, previousStatus, ticket.Status, &user.UserID); err != nil {
+				return err
+			}
+		}
+		if err := publishEvent(tx, ticket.OrganizationID, models.EventTicketUpdated, ticketEvent(&ticket, statusChangedFrom)); err != nil {
+			return err
+		}
+
+		var cascaded []models.TicketModel
+		if req.CascadeToChildren && previousStatus != ticket.Status {
+			var err error
+			if cascaded, err = cascadeTicketStatus(tx, &ticket, user.UserID); err != nil {
+				return err
+			}
+		}
+
+		closed := before.StatusCategory == models.TicketStatusCategoryOpen && ticket.StatusCategory == models.TicketStatusCategoryClosed
+		if closed && req.NotifyCustomers {
+			ticketIDs := []uint{ticket.ID}
+			for _, child := range cascaded {
+				if child.StatusCategory == models.TicketStatusCategoryClosed {
+					ticketIDs = append(ticketIDs, child.ID)
+				}
+			}
+			return notifyTicketCustomers(tx, &ticket, ticketIDs, user.UserID)
+		}
+		return nil
+	})
+}
+
+// ticketUpdateColumns are the columns a ticket update writes, the rest of
+// the row such as the escalation stamps belongs to other writers.
+var ticketUpdateColumns = []string{
+	"name", "status", "status_category", "resolution_note", "assignee_id",
+	"priority", "due_at", "category", "description",
+	"sla_policy_id", "sla_warn_at", "sla_due_at", "sla_warned_at", "sla_breached_at", "sla_started_at",
+}
+
+// validateTicketAssignee checks that the assignee is an owner or a sales of
+// the organization.
+func validateTicketAssignee(db *gorm.DB, organizationID uint, assigneeID uint) error {
+	var count int64
+	if err := db.Model(&models.UserModel{}).
+		Joins("JOIN user_roles ON user_roles.id = users.role_id").
+		Where("users.id = ? AND users.organization_id = ?", assigneeID, organizationID).
+		Where("user_roles.name IN ?", []string{models.RoleOrganizationOwner, models.RoleOrganizationSales}).
+		Count(&count).Error; err != nil {
+		return errors.New("failed to fetch assignee")
+	}
+	if count == 0 {
+		return ErrTicketAssigneeInvalid
+	}
+	return nil
+}
+
+// GetNumberFormat implements services.OrganizationTicketService.
+func (t *OrganizationTicketServiceImpl) GetNumberFormat(user *jwtLib.Claims) (*responsedto.TicketNumberFormatResponse, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	if err := ensureTicketSequence(t.db, *user.OrganizationId); err != nil {
+		return nil, err
+	}
+
+	var sequence models.TicketSequenceModel
+	if err := t.db.Scopes(organizationScope(user)).First(&sequence).Error; err != nil {
+		return nil, errors.New("failed to fetch ticket sequence")
+	}
+
+	location, err := organizationLocation(t.db, *user.OrganizationId)
+	if err != nil {
+		return nil, err
+	}
+	return t.mapToNumberFormatResponse(&sequence, time.Now().In(location)), nil
+}
+
+// UpdateNumberFormat implements services.OrganizationTicketService. The
+// counter carries on under the new format, it only starts over when the
+// date part rolls over.
+func (t *OrganizationTicketServiceImpl) UpdateNumberFormat(user *jwtLib.Claims, req requestdto.UpdateTicketNumberFormatRequest) (*responsedto.TicketNumberFormatResponse, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+	if !ticketPrefixPattern.MatchString(req.Prefix) {
+		return nil, ErrTicketNumberFormatInvalid
+	}
+
+	location, err := organizationLocation(t.db, *user.OrganizationId)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().In(location)
+
+	var sequence models.TicketSequenceModel
+	err = t.db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureTicketSequence(tx, *user.OrganizationId); err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Scopes(organizationScope(user)).
+			First(&sequence).Error; err != nil {
+			return errors.New("failed to fetch ticket sequence")
+		}
+
+		sequence.Prefix = req.Prefix
+		sequence.Padding = req.Padding
+		if sequence.DatePart != req.DatePart {
+			sequence.DatePart = req.DatePart
+			sequence.Period = ticketNumberPeriod(req.DatePart, now)
+		}
+
+		if err := tx.Model(&sequence).Select("prefix", "date_part", "padding", "period").
+			Updates(&sequence).Error; err != nil {
+			return errors.New("failed to update ticket number format")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t.mapToNumberFormatResponse(&sequence, now), nil
+}
+
+// GetWorkflow implements services.OrganizationTicketService.
+func (t *OrganizationTicketServiceImpl) GetWorkflow(user *jwtLib.Claims) (*responsedto.TicketWorkflowResponse, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	workflow, err := loadTicketWorkflow(t.db, *user.OrganizationId)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.mapToWorkflowResponse(workflow), nil
+}
+
+// UpdateWorkflow implements services.OrganizationTicketService. Tickets
+// keep their status, so a status can only be removed once no ticket uses
+// it, and tickets follow when the category of their status changes.
+func (t *OrganizationTicketServiceImpl) UpdateWorkflow(user *jwtLib.Claims, req requestdto.UpdateTicketWorkflowRequest) (*responsedto.TicketWorkflowResponse, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	statuses, transitions, err := buildTicketWorkflow(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := loadTicketWorkflow(t.db, *user.OrganizationId); err != nil {
+		return nil, err
+	}
+
+	var workflow models.TicketWorkflowModel
+	err = t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Scopes(organizationScope(user)).
+			First(&workflow).Error; err != nil {
+			return errors.New("failed to fetch ticket workflow")
+		}
+
+		updated := &models.TicketWorkflowModel{Statuses: statuses}
+		var removed []string
+		for _, status := range workflow.Statuses {
+			if _, ok := workflowStatus(updated, status.Key); !ok {
+				removed = append(removed, status.Key)
+			}
+		}
+		if len(removed) > 0 {
+			var inUse []string
+			if err := tx.Model(&models.TicketModel{}).
+				Clauses(clause.Locking{Strength: "SHARE"}).
+				Where("organization_id = ? AND status IN ?", workflow.OrganizationID, removed).
+				Distinct().Pluck("status", &inUse).Error; err != nil {
+				return errors.New("failed to check ticket statuses")
+			}
+			if len(inUse) > 0 {
+				return fmt.Errorf("%w: %s", ErrTicketWorkflowStatusInUse, strings.Join(inUse, ", "))
+			}
+		}
+
+		for _, status := range statuses {
+			previous, ok := workflowStatus(&workflow, status.Key)
+			if ok && previous.Category == status.Category {
+				continue
+			}
+			if err := tx.Model(&models.TicketModel{}).
+				Where("organization_id = ? AND status = ?", workflow.OrganizationID, status.Key).
+				Update("status_category", status.Category).Error; err != nil {
+				return errors.New("failed to update ticket statuses")
+			}
+		}
+
+		workflow.InitialStatus = req.InitialStatus
+		workflow.Statuses = statuses
+		workflow.Transitions = transitions
+		if err := tx.Save(&workflow).Error; err != nil {
+			return errors.New("failed to update ticket workflow")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t.mapToWorkflowResponse(&workflow), nil
+}
+
+// GetTicket implements services.OrganizationTicketService.
+func (t *OrganizationTicketServiceImpl) GetTicket(user *jwtLib.Claims, ticketID uint) (*responsedto.TicketDetailResponse, error) {
+	ticket, err := t.findTicket(user, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []models.TicketCommentModel
+	if err := t.db.Where("ticket_id = ?", ticket.ID).
+		Preload("Author").
+		Order("created_at ASC, id ASC").
+		Find(&comments).Error; err != nil {
+		return nil, errors.New("failed to fetch ticket comments")
+	}
+
+	var activities []models.TicketActivityModel
+	if err := t.db.Where("ticket_id = ?", ticket.ID).
+		Preload("Actor").
+		Order("created_at ASC, id ASC").
+		Find(&activities).Error; err != nil {
+		return nil, errors.New("failed to fetch ticket activity")
+	}
+
+	var escalations []models.TicketEscalationModel
+	if err := t.db.Where("ticket_id = ?", ticket.ID).
+		Order("created_at ASC, id ASC").
+		Find(&escalations).Error; err != nil {
+		return nil, errors.New("failed to fetch ticket escalations")
+	}
+
+	var links []models.TicketLinkModel
+	if err := t.db.Where("ticket_id = ? OR linked_ticket_id = ?", ticket.ID, ticket.ID).
+		Preload("Ticket").Preload("LinkedTicket").
+		Order("created_at ASC, id ASC").
+		Find(&links).Error; err != nil {
+		return nil, errors.New("failed to fetch ticket links")
+	}
+
+	var conversations []models.TicketConversationModel
+	if err := t.db.Where("ticket_id = ?", ticket.ID).
+		Preload("Conversation").
+		Order("created_at ASC, id ASC").
+		Find(&conversations).Error; err != nil {
+		return nil, errors.New("failed to fetch ticket conversations")
+	}
+
+	tags, err := loadTags(t.db, models.TagSubjectTicket, []uint{ticket.ID})
+	if err != nil {
+		return nil, err
+	}
+	fields, err := loadTicketFields(t.db, []uint{ticket.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &responsedto.TicketDetailResponse{
+		Ticket:        *t.mapToTicketResponse(ticket),
+		Comments:      make([]responsedto.TicketCommentResponse, 0, len(comments)),
+		Activity:      make([]responsedto.TicketActivityResponse, 0, len(activities)),
+		Escalations:   make([]responsedto.TicketEscalationResponse, 0, len(escalations)),
+		Links:         make([]responsedto.TicketLinkResponse, 0, len(links)),
+		Conversations: make([]responsedto.ConversationResponse, 0, len(conversations)),
+	}
+	if names, ok := tags[ticket.ID]; ok {
+		response.Ticket.Tags = names
+	}
+	if values, ok := fields[ticket.ID]; ok {
+		response.Ticket.Fields = values
+	}
+	for i := range comments {
+		response.Comments = append(response.Comments, *t.mapToCommentResponse(&comments[i]))
+	}
+	for i := range activities {
+		response.Activity = append(response.Activity, *t.mapToActivityResponse(&activities[i]))
+	}
+	for i := range escalations {
+		response.Escalations = append(response.Escalations, *t.mapToEscalationResponse(&escalations[i]))
+	}
+	for i := range links {
+		if link := t.mapToLinkResponse(&links[i], ticket.ID); link != nil {
+			response.Links = append(response.Links, *link)
+		}
+	}
+	for _, conversation := range conversations {
+		if conversation.Conversation != nil {
+			response.Conversations = append(response.Conversations, *t.mapToConversationResponse(conversation.Conversation))
+		}
+	}
+
+	return response, nil
+}
+
+// GetComments implements services.OrganizationTicketService.
+func (t *OrganizationTicketServiceImpl) GetComments(user *jwtLib.Claims, ticketID uint, filter filtersdto.FiltersDto) (*responsedto.TicketCommentPaginateResponse, error) {
+	ticket, err := t.findTicket(user, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []models.TicketCommentModel
+	var total int64
+	offset := (*filter.Page - 1) * *filter.Limit
+
+	query := t.db.Model(&models.TicketCommentModel{}).
+		Where("ticket_id = ?", ticket.ID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, errors.New("failed to count ticket comments")
+	}
+
+	if err := query.Offset(offset).Limit(*filter.Limit).
+		Preload("Author").
+		Order("created_at DESC, id DESC").
+		Find(&comments).Error; err != nil {
+		return nil, errors.New("failed to fetch ticket comments")
+	}
+
+	data := make([]responsedto.TicketCommentResponse, 0, len(comments))
+	for i := range comments {
+		data = append(data, *t.mapToCommentResponse(&comments[i]))
+	}
+
+	return &responsedto.TicketCommentPaginateResponse{
+		Data: data,
+		Metadata: responsedto.PaginateMetaData{
+			Total: int(total),
+			Page:  *filter.Page,
+			Limit: *filter.Limit,
+		},
+	}, nil
+}
+
+// CreateComment implements services.OrganizationTicketService. A public
+// comment is sent to the customer as a staff message of the ticket's
+// conversation, on the conversation's channel.
+func (t *OrganizationTicketServiceImpl) CreateComment(user *jwtLib.Claims, ticketID uint, req requestdto.CreateTicketCommentRequest) (*responsedto.TicketCommentResponse, error) {
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, ErrTicketCommentEmpty
+	}
+
+	ticket, err := t.findTicket(user, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := models.TicketCommentModel{
+		OrganizationID: ticket.OrganizationID,
+		TicketID:       ticket.ID,
+		AuthorID:       user.UserID,
+		Visibility:     req.Visibility,
+		Body:           body,
+	}
+
+	var conversation models.ConversationModel
+	var message *models.ConversationMessageModel
+	var reply *mail.OutgoingMail
+	if comment.Visibility == models.TicketCommentPublic {
+		if err := t.db.Preload("Organization").
+			Preload("Guest").
+			Preload("Contact.Identities").
+			First(&conversation, ticket.ConversationID).Error; err != nil {
+			return nil, errors.New("failed to fetch conversation")
+		}
+		if message, reply, err = prepareStaffReply(t.db, &conversation, user.UserID, comment.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := t.db.Transaction(func(tx *gorm.DB) error {
+		if message != nil {
+			if err := deliverStaffReply(tx, &conversation, message, reply); err != nil {
+				return err
+			}
+			comment.MessageID = &message.ID
+		}
+
+		if err := tx.Create(&comment).Error; err != nil {
+			return errors.New("failed to create ticket comment")
+		}
+
+		return recordTicketActivity(tx, &models.TicketActivityModel{
+			OrganizationID: ticket.OrganizationID,
+			TicketID:       ticket.ID,
+			ActorID:        &user.UserID,
+			Action:         models.TicketActivityCommented,
+			CommentID:      &comment.ID,
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := t.db.Preload("Author").First(&comment, comment.ID).Error; err != nil {
+		return nil, errors.New("failed to load ticket comment")
+	}
+
+	return t.mapToCommentResponse(&comment), nil
+}
+
+// CreateLink implements services.OrganizationTicketService.
+func (t *OrganizationTicketServiceImpl) CreateLink(user *jwtLib.Claims, ticketID uint, req requestdto.CreateTicketLinkRequest) (*responsedto.TicketLinkResponse, error) {
+	ticket, err := t.findTicket(user, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	other, err := t.findTicket(user, req.TicketID)
+	if err != nil {
+		return nil, err
+	}
+
+	source, target, linkType := storedTicketLink(ticket.ID, other.ID, req.Type)
+	link := models.TicketLinkModel{
+		OrganizationID: ticket.OrganizationID,
+		TicketID:       source,
+		LinkedTicketID: target,
+		Type:           linkType,
+		CreatedByID:    &user.UserID,
+	}
+
+	if err := t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", []uint{ticket.ID, other.ID}).
+			Find(&[]models.TicketModel{}).Error; err != nil {
+			return errors.New("failed to lock tickets")
+		}
+		if err := validateTicketLink(tx, &link); err != nil {
+			return err
+		}
+		if err := tx.Create(&link).Error; err != nil {
+			return errors.New("failed to create ticket link")
+		}
+
+		link.Ticket, link.LinkedTicket = ticket, other
+		if source != ticket.ID {
+			link.Ticket, link.LinkedTicket = other, ticket
+		}
+		return t.recordLinkActivity(tx, &link, models.TicketActivityLinked, user.UserID)
+	}); err != nil {
+		return nil, err
+	}
+
+	return t.mapToLinkResponse(&link, ticket.ID), nil
+}
+
+// DeleteLink implements services.OrganizationTicketService.
+func (t *OrganizationTicketServiceImpl) DeleteLink(user *jwtLib.Claims, ticketID uint, linkID uint) error {
+	ticket, err := t.findTicket(user, ticketID)
+	if err != nil {
+		return err
+	}
+
+	var link models.TicketLinkModel
+	if err := t.db.Where("ticket_id = ? OR linked_ticket_id = ?", ticket.ID, ticket.ID).
+		Preload("Ticket").Preload("LinkedTicket").
+		First(&link, linkID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTicketLinkNotFound
+		}
+		return errors.New("failed to fetch ticket link")
+	}
+
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&link).Error; err != nil {
+			return errors.New("failed to delete ticket link")
+		}
+		return t.recordLinkActivity(tx, &link, models.TicketActivityUnlinked, user.UserID)
+	})
+}
+
+// recordLinkActivity adds the link to the history of both tickets, each
+// with the link type read from that ticket.
+func (t *OrganizationTicketServiceImpl) recordLinkActivity(tx *gorm.DB, link *models.TicketLinkModel, action string, actorID uint) error {
+	for _, ticketID := range []uint{link.TicketID, link.LinkedTicketID} {
+		view, other := ticketLinkView(link, ticketID)
+		activity := &models.TicketActivityModel{
+			OrganizationID: link.OrganizationID,
+			TicketID:       ticketID,
+			ActorID:        &actorID,
+			Action:         action,
+			Field:          &view,
+		}
+		if action == models.TicketActivityLinked {
+			activity.NewValue = &other.TicketNumber
+		} else {
+			activity.OldValue = &other.TicketNumber
+		}
+		if err := recordTicketActivity(tx, activity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddConversation implements services.OrganizationTicketService. The
+// customer of the conversation is told when the ticket closes.
+func (t *OrganizationTicketServiceImpl) AddConversation(user *jwtLib.Claims, ticketID uint, req requestdto.AddTicketConversationRequest) (*responsedto.ConversationResponse, error) {
+	ticket, err := t.findTicket(user, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := conversationAccessScope(t.db, user)
+	if err != nil {
+		return nil, err
+	}
+
+	var conversation models.ConversationModel
+	if err := t.db.Scopes(access).
+		First(&conversation, req.ConversationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTicketConversationNotFound
+		}
+		return nil, errors.New("failed to fetch conversation")
+	}
+
+	if err := t.db.Transaction(func(tx *gorm.DB) error {
+		// the unique pair decides between concurrent adds, the one that
+		// inserts nothing gets the conflict
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.TicketConversationModel{
+			OrganizationID: ticket.OrganizationID,
+			TicketID:       ticket.ID,
+			ConversationID: conversation.ID,
+		})
+		if result.Error != nil {
+			return errors.New("failed to add ticket conversation")
+		}
+		if result.RowsAffected == 0 {
+			return ErrTicketConversationExists
+		}
+
+		field := "conversation"
+		value := fmt.Sprint(conversation.ID)
+		return recordTicketActivity(tx, &models.TicketActivityModel{
+			OrganizationID: ticket.OrganizationID,
+			TicketID:       ticket.ID,
+			ActorID:        &user.UserID,
+			Action:         models.TicketActivityLinked,
+			Field:          &field,
+			NewValue:       &value,
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	return t.mapToConversationResponse(&conversation), nil
+}
+
+// RemoveConversation implements services.OrganizationTicketService.
+func (t *OrganizationTicketServiceImpl) RemoveConversation(user *jwtLib.Claims, ticketID uint, conversationID uint) error {
+	ticket, err := t.findTicket(user, ticketID)
+	if err != nil {
+		return err
+	}
+	if conversationID == ticket.ConversationID {
+		return ErrTicketConversationPrimary
+	}
+
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("ticket_id = ? AND conversation_id = ?", ticket.ID, conversationID).
+			Delete(&models.TicketConversationModel{})
+		if result.Error != nil {
+			return errors.New("failed to remove ticket conversation")
+		}
+		if result.RowsAffected == 0 {
+			return ErrTicketConversationNotFound
+		}
+
+		field := "conversation"
+		value := fmt.Sprint(conversationID)
+		return recordTicketActivity(tx, &models.TicketActivityModel{
+			OrganizationID: ticket.OrganizationID,
+			TicketID:       ticket.ID,
+			ActorID:        &user.UserID,
+			Action:         models.TicketActivityUnlinked,
+			Field:          &field,
+			OldValue:       &value,
+		})
+	})
+}
+
+// findTicket loads a ticket the caller may work on.
+func (t *OrganizationTicketServiceImpl) findTicket(user *jwtLib.Claims, ticketID uint) (*models.TicketModel, error) {
+	access, err := ticketAccessScope(t.db, user)
+	if err != nil {
+		return nil, err
+	}
+
 	var ticket models.TicketModel
-	if err := t.db.First(&ticket, ticketID).Error; err != nil {
+	if err := t.db.Scopes(access).
+		Preload("Organization").Preload("Conversation").Preload("CreatedBy").Preload("Assignee").
+		First(&ticket, ticketID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("ticket not found")
+			return nil, ErrTicketNotFound
+		}
+		return nil, errors.New("failed to fetch ticket")
+	}
+	return &ticket, nil
+}
+
+func (t *OrganizationTicketServiceImpl) mapToCommentResponse(comment *models.TicketCommentModel) *responsedto.TicketCommentResponse {
+	response := &responsedto.TicketCommentResponse{
+		ID:         comment.ID,
+		TicketID:   comment.TicketID,
+		AuthorID:   comment.AuthorID,
+		Visibility: comment.Visibility,
+		Body:       comment.Body,
+		MessageID:  comment.MessageID,
+		CreatedAt:  comment.CreatedAt,
+	}
+
+	if comment.Author != nil {
+		response.Author = &responsedto.UserData{
+			ID:    comment.Author.ID,
+			Email: comment.Author.Email,
+			Name:  comment.Author.Name,
 		}
-		return errors.New("failed to fetch ticket")
 	}
 
-	if req.Name != "" {
-		ticket.Name = req.Name
+	return response
+}
+
+func (t *OrganizationTicketServiceImpl) mapToActivityResponse(activity *models.TicketActivityModel) *responsedto.TicketActivityResponse {
+	response := &responsedto.TicketActivityResponse{
+		ID:        activity.ID,
+		ActorID:   activity.ActorID,
+		Action:    activity.Action,
+		Field:     activity.Field,
+		OldValue:  activity.OldValue,
+		NewValue:  activity.NewValue,
+		CommentID: activity.CommentID,
+		CreatedAt: activity.CreatedAt,
 	}
-	if req.Status != "" {
-		ticket.Status = req.Status
+
+	if activity.Actor != nil {
+		response.Actor = &responsedto.UserData{
+			ID:    activity.Actor.ID,
+			Email: activity.Actor.Email,
+			Name:  activity.Actor.Name,
+		}
 	}
 
-	if err := t.db.Save(&ticket).Error; err != nil {
-		return errors.New("failed to update ticket")
+	return response
+}
+
+func (t *OrganizationTicketServiceImpl) mapToEscalationResponse(escalation *models.TicketEscalationModel) *responsedto.TicketEscalationResponse {
+	response := &responsedto.TicketEscalationResponse{
+		ID:        escalation.ID,
+		PolicyID:  escalation.PolicyID,
+		Stage:     escalation.Stage,
+		SLADueAt:  escalation.SLADueAt,
+		Results:   make([]responsedto.TicketEscalationResultResponse, 0, len(escalation.Results)),
+		CreatedAt: escalation.CreatedAt,
+	}
+	for _, result := range escalation.Results {
+		response.Results = append(response.Results, responsedto.TicketEscalationResultResponse{
+			Type:   result.Type,
+			Detail: result.Detail,
+			Error:  result.Error,
+		})
 	}
+	return response
+}
 
-	if err := t.db.Preload("Organization").Preload("Conversation").Preload("CreatedBy").First(&ticket, ticket.ID).Error; err != nil {
-		return errors.New("failed to load ticket details")
+func (t *OrganizationTicketServiceImpl) mapToLinkResponse(link *models.TicketLinkModel, ticketID uint) *responsedto.TicketLinkResponse {
+	view, other := ticketLinkView(link, ticketID)
+	if other == nil {
+		return nil
 	}
 
-	return nil
+	return &responsedto.TicketLinkResponse{
+		ID:   link.ID,
+		Type: view,
+		Ticket: responsedto.TicketSummaryResponse{
+			ID:             other.ID,
+			TicketNumber:   other.TicketNumber,
+			Name:           other.Name,
+			Status:         other.Status,
+			StatusCategory: other.StatusCategory,
+		},
+		CreatedAt: link.CreatedAt,
+	}
 }
 
-func (t *OrganizationTicketServiceImpl) generateTicketNumber(organizationID uint) string {
-	var count int64
-	t.db.Model(&models.TicketModel{}).Where("organization_id = ?", organizationID).Count(&count)
+func (t *OrganizationTicketServiceImpl) mapToConversationResponse(conversation *models.ConversationModel) *responsedto.ConversationResponse {
+	return &responsedto.ConversationResponse{
+		ID:             conversation.ID,
+		OrganizationID: conversation.OrganizationID,
+		GuestID:        conversation.GuestID,
+		ContactID:      conversation.ContactID,
+		Status:         conversation.Status,
+		Channel:        conversation.Channel,
+		Subject:        conversation.Subject,
+	}
+}
+
+func (t *OrganizationTicketServiceImpl) mapToWorkflowResponse(workflow *models.TicketWorkflowModel) *responsedto.TicketWorkflowResponse {
+	response := &responsedto.TicketWorkflowResponse{
+		InitialStatus: workflow.InitialStatus,
+		Statuses:      make([]responsedto.TicketWorkflowStatusResponse, 0, len(workflow.Statuses)),
+		Transitions:   make([]responsedto.TicketWorkflowTransitionResponse, 0, len(workflow.Transitions)),
+	}
+	for _, status := range workflow.Statuses {
+		response.Statuses = append(response.Statuses, responsedto.TicketWorkflowStatusResponse{
+			Key:                    status.Key,
+			Name:                   status.Name,
+			Category:               status.Category,
+			RequiresResolutionNote: status.RequiresResolutionNote,
+		})
+	}
+	for _, transition := range workflow.Transitions {
+		response.Transitions = append(response.Transitions, responsedto.TicketWorkflowTransitionResponse{
+			From: transition.From,
+			To:   transition.To,
+		})
+	}
+	return response
+}
 
-	timestamp := time.Now().Format("20060102")
-	return fmt.Sprintf("TKT-%d-%s-%04d", organizationID, timestamp, count+1)
+// mapToNumberFormatResponse previews the next number as of now, which is
+// in the timezone of the organization.
+func (t *OrganizationTicketServiceImpl) mapToNumberFormatResponse(sequence *models.TicketSequenceModel, now time.Time) *responsedto.TicketNumberFormatResponse {
+	period := ticketNumberPeriod(sequence.DatePart, now)
+	next := sequence.LastValue + 1
+	if sequence.Period != period {
+		next = 1
+	}
+
+	return &responsedto.TicketNumberFormatResponse{
+		Prefix:     sequence.Prefix,
+		DatePart:   sequence.DatePart,
+		Padding:    sequence.Padding,
+		NextNumber: formatTicketNumber(sequence, period, next),
+	}
 }
 
 func (t *OrganizationTicketServiceImpl) mapToTicketResponse(ticket *models.TicketModel) *responsedto.TicketResponse {
@@ -103,6 +1078,19 @@ func (t *OrganizationTicketServiceImpl) mapToTicketResponse(ticket *models.Ticke
 		TicketNumber:   ticket.TicketNumber,
 		Name:           ticket.Name,
 		Status:         ticket.Status,
+		AssigneeID:     ticket.AssigneeID,
+		Priority:       ticket.Priority,
+		DueAt:          ticket.DueAt,
+		StatusCategory: ticket.StatusCategory,
+		ResolutionNote: ticket.ResolutionNote,
+		Overdue:        ticket.DueAt != nil && ticket.DueAt.Before(time.Now()) && ticket.StatusCategory == models.TicketStatusCategoryOpen,
+		Category:       ticket.Category,
+		Description:    ticket.Description,
+		SLAPolicyID:    ticket.SLAPolicyID,
+		SLADueAt:       ticket.SLADueAt,
+		SLABreached:    ticket.SLABreachedAt != nil,
+		Tags:           []string{},
+		Fields:         map[string]any{},
 		CreatedAt:      ticket.CreatedAt,
 		UpdatedAt:      ticket.UpdatedAt,
 	}
@@ -119,6 +1107,7 @@ func (t *OrganizationTicketServiceImpl) mapToTicketResponse(ticket *models.Ticke
 			ID:             ticket.Conversation.ID,
 			OrganizationID: ticket.Conversation.OrganizationID,
 			GuestID:        ticket.Conversation.GuestID,
+			ContactID:      ticket.Conversation.ContactID,
 			Status:         ticket.Conversation.Status,
 		}
 	}
@@ -131,21 +1120,36 @@ func (t *OrganizationTicketServiceImpl) mapToTicketResponse(ticket *models.Ticke
 		}
 	}
 
+	if ticket.Assignee != nil {
+		response.Assignee = &responsedto.UserData{
+			ID:    ticket.Assignee.ID,
+			Email: ticket.Assignee.Email,
+			Name:  ticket.Assignee.Name,
+		}
+	}
+
 	return response
 }
 
-func (t *OrganizationTicketServiceImpl) buildTicketListResponse(tickets []models.TicketModel) *responsedto.TicketListResponse {
-	var ticketResponses []responsedto.TicketResponse
-	for _, ticket := range tickets {
-		ticketResponses = append(ticketResponses, *t.mapToTicketResponse(&ticket))
+func (t *OrganizationTicketServiceImpl) buildTicketListResponse(tickets []models.TicketModel, tags map[uint][]string, fields map[uint]map[string]any, total int, page int, limit int) *responsedto.TicketListResponse {
+	ticketResponses := make([]responsedto.TicketResponse, 0, len(tickets))
+	for i := range tickets {
+		response := t.mapToTicketResponse(&tickets[i])
+		if names, ok := tags[tickets[i].ID]; ok {
+			response.Tags = names
+		}
+		if values, ok := fields[tickets[i].ID]; ok {
+			response.Fields = values
+		}
+		ticketResponses = append(ticketResponses, *response)
 	}
 
 	return &responsedto.TicketListResponse{
 		Tickets: ticketResponses,
 		Metadata: responsedto.PaginateMetaData{
-			Total: len(ticketResponses),
-			Page:  1,
-			Limit: len(ticketResponses),
+			Total: total,
+			Page:  page,
+			Limit: limit,
 		},
 	}
 }
@@ -0,0 +1,169 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ticketPriorities is the order a bump_priority escalation moves through.
+var ticketPriorities = []string{
+	models.TicketPriorityLow,
+	models.TicketPriorityNormal,
+	models.TicketPriorityHigh,
+	models.TicketPriorityUrgent,
+}
+
+func nextTicketPriority(priority string) (string, bool) {
+	for i, value := range ticketPriorities[:len(ticketPriorities)-1] {
+		if value == priority {
+			return ticketPriorities[i+1], true
+		}
+	}
+	return priority, false
+}
+
+// matchTicketSLAPolicy picks the active policy of the ticket. A policy for
+// the category and the priority wins over one for the category only, which
+// wins over one for the priority only and then over a catch-all one.
+func matchTicketSLAPolicy(policies []models.TicketSLAPolicyModel, ticket *models.TicketModel) *models.TicketSLAPolicyModel {
+	var best *models.TicketSLAPolicyModel
+	bestScore := -1
+	for i := range policies {
+		policy := &policies[i]
+		if !policy.Active {
+			continue
+		}
+
+		score := 0
+		if policy.Priority != nil {
+			if *policy.Priority != ticket.Priority {
+				continue
+			}
+			score++
+		}
+		if policy.Category != nil {
+			if ticket.Category == nil || !strings.EqualFold(*policy.Category, *ticket.Category) {
+				continue
+			}
+			score += 2
+		}
+
+		if score > bestScore {
+			best, bestScore = policy, score
+		}
+	}
+	return best
+}
+
+// setTicketSLA moves the deadlines of the ticket to the policy, counted from
+// the creation of the ticket or from its last reopen. A stage whose deadline
+// moved into the future is handled again by the scheduler.
+func setTicketSLA(ticket *models.TicketModel, policy *models.TicketSLAPolicyModel, now time.Time) {
+	if policy == nil {
+		ticket.SLAPolicyID = nil
+		ticket.SLAWarnAt = nil
+		ticket.SLADueAt = nil
+		return
+	}
+
+	start := ticket.CreatedAt
+	if ticket.SLAStartedAt != nil {
+		start = *ticket.SLAStartedAt
+	}
+	if start.IsZero() {
+		start = now
+	}
+	due := start.Add(time.Duration(policy.ResolveMinutes) * time.Minute)
+
+	policyID := policy.ID
+	ticket.SLAPolicyID = &policyID
+	ticket.SLADueAt = &due
+	ticket.SLAWarnAt = nil
+	if policy.WarnMinutes > 0 {
+		warn := due.Add(-time.Duration(policy.WarnMinutes) * time.Minute)
+		ticket.SLAWarnAt = &warn
+	}
+
+	if due.After(now) {
+		ticket.SLABreachedAt = nil
+	}
+	if ticket.SLAWarnAt == nil || ticket.SLAWarnAt.After(now) {
+		ticket.SLAWarnedAt = nil
+	}
+}
+
+// applyTicketSLA matches the ticket against the policies of its
+// organization and sets its deadlines.
+func applyTicketSLA(db *gorm.DB, ticket *models.TicketModel, now time.Time) error {
+	var policies []models.TicketSLAPolicyModel
+	if err := db.Where("organization_id = ? AND active = ?", ticket.OrganizationID, true).
+		Find(&policies).Error; err != nil {
+		return errors.New("failed to fetch ticket SLA policies")
+	}
+
+	setTicketSLA(ticket, matchTicketSLAPolicy(policies, ticket), now)
+	return nil
+}
+
+// restartTicketSLA starts the clock of a reopened ticket again and matches
+// it against the policies as they are now, so the scheduler warns and
+// escalates it like a new one.
+func restartTicketSLA(db *gorm.DB, ticket *models.TicketModel, now time.Time) error {
+	ticket.SLAStartedAt = &now
+	ticket.SLAWarnedAt = nil
+	ticket.SLABreachedAt = nil
+	return applyTicketSLA(db, ticket, now)
+}
+
+// reapplyTicketSLA sets the deadlines of the open tickets of the
+// organization again after its policies changed. Only the tickets whose
+// deadlines move are written.
+func reapplyTicketSLA(tx *gorm.DB, organizationID uint, now time.Time) error {
+	var policies []models.TicketSLAPolicyModel
+	if err := tx.Where("organization_id = ? AND active = ?", organizationID, true).
+		Find(&policies).Error; err != nil {
+		return errors.New("failed to fetch ticket SLA policies")
+	}
+
+	var tickets []models.TicketModel
+	if err := tx.Where("organization_id = ? AND status_category = ?", organizationID, models.TicketStatusCategoryOpen).
+		FindInBatches(&tickets, 200, func(batch *gorm.DB, _ int) error {
+			for i := range tickets {
+				before := tickets[i]
+				setTicketSLA(&tickets[i], matchTicketSLAPolicy(policies, &tickets[i]), now)
+				if sameTicketSLA(&before, &tickets[i]) {
+					continue
+				}
+				if err := tx.Model(&tickets[i]).
+					Select("sla_policy_id", "sla_warn_at", "sla_due_at", "sla_warned_at", "sla_breached_at").
+					Updates(&tickets[i]).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error; err != nil {
+		return errors.New("failed to update ticket SLA deadlines")
+	}
+	return nil
+}
+
+func sameTicketSLA(a *models.TicketModel, b *models.TicketModel) bool {
+	samePolicy := (a.SLAPolicyID == nil && b.SLAPolicyID == nil) ||
+		(a.SLAPolicyID != nil && b.SLAPolicyID != nil && *a.SLAPolicyID == *b.SLAPolicyID)
+	return samePolicy &&
+		sameTicketTime(a.SLAWarnAt, b.SLAWarnAt) &&
+		sameTicketTime(a.SLADueAt, b.SLADueAt) &&
+		sameTicketTime(a.SLAWarnedAt, b.SLAWarnedAt) &&
+		sameTicketTime(a.SLABreachedAt, b.SLABreachedAt)
+}
+
+func sameTicketTime(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
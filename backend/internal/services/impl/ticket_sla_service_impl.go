@@ -0,0 +1,465 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/pkg/dtos/eventdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/lib/mail"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTicketSLAPolicyNotFound  = errors.New("ticket SLA policy not found")
+	ErrTicketSLAPolicyInvalid   = errors.New("invalid ticket SLA policy")
+	ErrTicketSLAPolicyDuplicate = errors.New("another policy already covers this priority and category")
+)
+
+type ticketSLAServiceImpl struct {
+	db     *gorm.DB
+	mailer mail.Mailer
+}
+
+// GetPolicies implements services.TicketSLAService.
+func (t *ticketSLAServiceImpl) GetPolicies(user *jwtLib.Claims) (*responsedto.TicketSLAPolicyListResponse, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	var policies []models.TicketSLAPolicyModel
+	if err := t.db.Scopes(organizationScope(user)).
+		Order("name ASC").
+		Find(&policies).Error; err != nil {
+		return nil, errors.New("failed to fetch ticket SLA policies")
+	}
+
+	response := &responsedto.TicketSLAPolicyListResponse{
+		Policies: make([]responsedto.TicketSLAPolicyResponse, 0, len(policies)),
+	}
+	for i := range policies {
+		response.Policies = append(response.Policies, *t.mapToPolicyResponse(&policies[i]))
+	}
+	return response, nil
+}
+
+// CreatePolicy implements services.TicketSLAService.
+func (t *ticketSLAServiceImpl) CreatePolicy(user *jwtLib.Claims, req requestdto.TicketSLAPolicyRequest) (*responsedto.TicketSLAPolicyResponse, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	policy := models.TicketSLAPolicyModel{OrganizationID: *user.OrganizationId}
+	if err := t.fillPolicy(&policy, req); err != nil {
+		return nil, err
+	}
+
+	if err := t.db.Transaction(func(tx *gorm.DB) error {
+		if err := t.checkDuplicate(tx, &policy); err != nil {
+			return err
+		}
+		if err := tx.Create(&policy).Error; err != nil {
+			return errors.New("failed to create ticket SLA policy")
+		}
+		return reapplyTicketSLA(tx, policy.OrganizationID, time.Now())
+	}); err != nil {
+		return nil, err
+	}
+
+	return t.mapToPolicyResponse(&policy), nil
+}
+
+// UpdatePolicy implements services.TicketSLAService. Open tickets move to
+// the new deadlines right away.
+func (t *ticketSLAServiceImpl) UpdatePolicy(user *jwtLib.Claims, policyID uint, req requestdto.TicketSLAPolicyRequest) (*responsedto.TicketSLAPolicyResponse, error) {
+	policy, err := t.findPolicy(user, policyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.fillPolicy(policy, req); err != nil {
+		return nil, err
+	}
+
+	if err := t.db.Transaction(func(tx *gorm.DB) error {
+		if err := t.checkDuplicate(tx, policy); err != nil {
+			return err
+		}
+		if err := tx.Save(policy).Error; err != nil {
+			return errors.New("failed to update ticket SLA policy")
+		}
+		return reapplyTicketSLA(tx, policy.OrganizationID, time.Now())
+	}); err != nil {
+		return nil, err
+	}
+
+	return t.mapToPolicyResponse(policy), nil
+}
+
+// DeletePolicy implements services.TicketSLAService.
+func (t *ticketSLAServiceImpl) DeletePolicy(user *jwtLib.Claims, policyID uint) error {
+	policy, err := t.findPolicy(user, policyID)
+	if err != nil {
+		return err
+	}
+
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(policy).Error; err != nil {
+			return errors.New("failed to delete ticket SLA policy")
+		}
+		return reapplyTicketSLA(tx, policy.OrganizationID, time.Now())
+	})
+}
+
+// ProcessDueTickets implements services.TicketSLAService. Every ticket is
+// handled in its own transaction holding the ticket row, so schedulers on
+// several servers never run the same stage twice.
+func (t *ticketSLAServiceImpl) ProcessDueTickets(limit int) (int, error) {
+	now := time.Now()
+
+	var ids []uint
+	if err := t.db.Model(&models.TicketModel{}).
+		Where("status_category = ?", models.TicketStatusCategoryOpen).
+		Where("((sla_warn_at <= ? AND sla_warned_at IS NULL) OR (sla_due_at <= ? AND sla_breached_at IS NULL))", now, now).
+		Order("sla_due_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, errors.New("failed to fetch tickets due for escalation")
+	}
+
+	handled := 0
+	for _, id := range ids {
+		ok, err := t.escalate(id, now)
+		if err != nil {
+			logger.ErrorLog("Failed to escalate ticket", map[string]any{
+				"ticketId": id,
+				"error":    err.Error(),
+			})
+			continue
+		}
+		if ok {
+			handled++
+		}
+	}
+
+	return handled, nil
+}
+
+// escalate runs the stage the ticket reached. A ticket past its deadline
+// skips the warning and only runs the breach actions. Mails to owners go
+// out once the changes are committed.
+func (t *ticketSLAServiceImpl) escalate(ticketID uint, now time.Time) (bool, error) {
+	var notices []mail.OutgoingMail
+	handled := false
+
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		var ticket models.TicketModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status_category = ?", models.TicketStatusCategoryOpen).
+			Preload("SLAPolicy").
+			First(&ticket, ticketID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return errors.New("failed to fetch ticket")
+		}
+		before := ticket
+
+		var stage string
+		var actions []models.TicketEscalationAction
+		switch {
+		case ticket.SLADueAt != nil && !ticket.SLADueAt.After(now) && ticket.SLABreachedAt == nil:
+			stage = models.TicketSLAStageBreached
+			ticket.SLABreachedAt = &now
+			if ticket.SLAWarnedAt == nil {
+				ticket.SLAWarnedAt = &now
+			}
+			if ticket.SLAPolicy != nil {
+				actions = ticket.SLAPolicy.BreachActions
+			}
+		case ticket.SLAWarnAt != nil && !ticket.SLAWarnAt.After(now) && ticket.SLAWarnedAt == nil:
+			stage = models.TicketSLAStageWarning
+			ticket.SLAWarnedAt = &now
+			if ticket.SLAPolicy != nil {
+				actions = ticket.SLAPolicy.WarningActions
+			}
+		default:
+			return nil
+		}
+
+		results := make([]models.TicketEscalationResult, 0, len(actions))
+		for _, action := range actions {
+			result, notice := t.runAction(tx, &ticket, stage, action)
+			results = append(results, result)
+			if notice != nil {
+				notices = append(notices, *notice)
+			}
+		}
+
+		// the escalation is recorded against the deadline that was missed,
+		// a bumped priority can match another policy with other deadlines
+		policyID, dueAt := ticket.SLAPolicyID, *ticket.SLADueAt
+		if ticket.Priority != before.Priority {
+			if err := applyTicketSLA(tx, &ticket, now); err != nil {
+				return err
+			}
+		}
+
+		// only the columns an escalation may change are written
+		if err := tx.Model(&ticket).
+			Select("priority", "assignee_id", "sla_policy_id", "sla_warn_at", "sla_due_at", "sla_warned_at", "sla_breached_at", "updated_at").
+			Updates(&ticket).Error; err != nil {
+			return errors.New("failed to update ticket")
+		}
+		if err := recordTicketChanges(tx, &before, &ticket, nil); err != nil {
+			return err
+		}
+
+		if err := tx.Create(&models.TicketEscalationModel{
+			OrganizationID: ticket.OrganizationID,
+			TicketID:       ticket.ID,
+			PolicyID:       policyID,
+			Stage:          stage,
+			SLADueAt:       dueAt,
+			Results:        results,
+		}).Error; err != nil {
+			return errors.New("failed to record ticket escalation")
+		}
+
+		assigneeChanged := (ticket.AssigneeID == nil) != (before.AssigneeID == nil) ||
+			(ticket.AssigneeID != nil && *ticket.AssigneeID != *before.AssigneeID)
+		if ticket.Priority != before.Priority || assigneeChanged {
+			if err := publishEvent(tx, ticket.OrganizationID, models.EventTicketUpdated, ticketEvent(&ticket, nil)); err != nil {
+				return err
+			}
+		}
+
+		handled = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	for _, notice := range notices {
+		if err := t.mailer.Send(notice); err != nil {
+			logger.ErrorLog("Failed to notify owner of ticket escalation", map[string]any{
+				"ticketId": ticketID,
+				"to":       notice.To,
+				"error":    err.Error(),
+			})
+		}
+	}
+
+	return handled, nil
+}
+
+// runAction applies one escalation action to the ticket. A failing action
+// is recorded and does not stop the others.
+func (t *ticketSLAServiceImpl) runAction(tx *gorm.DB, ticket *models.TicketModel, stage string, action models.TicketEscalationAction) (models.TicketEscalationResult, *mail.OutgoingMail) {
+	result := models.TicketEscalationResult{Type: action.Type}
+	fail := func(err error) (models.TicketEscalationResult, *mail.OutgoingMail) {
+		message := err.Error()
+		result.Error = &message
+		return result, nil
+	}
+
+	switch action.Type {
+	case models.TicketEscalationNotifyOwner:
+		var organization models.OrganizationModel
+		if err := tx.Preload("Owner").First(&organization, ticket.OrganizationID).Error; err != nil || organization.Owner == nil {
+			return fail(errors.New("organization owner not found"))
+		}
+
+		what := "is about to breach"
+		if stage == models.TicketSLAStageBreached {
+			what = "breached"
+		}
+		notice := &mail.OutgoingMail{
+			To:      []string{organization.Owner.Email},
+			Subject: fmt.Sprintf("Ticket %s %s its SLA", ticket.TicketNumber, what),
+			Text: fmt.Sprintf(
+				"Ticket %s \"%s\" %s its SLA, it is due at %s.\n\nStatus: %s\nPriority: %s\n",
+				ticket.TicketNumber, ticket.Name, what, ticket.SLADueAt.UTC().Format(time.RFC1123), ticket.Status, ticket.Priority,
+			),
+		}
+		if organization.InboundEmail != nil {
+			notice.From = *organization.InboundEmail
+		}
+		result.Detail = organization.Owner.Email
+		return result, notice
+
+	case models.TicketEscalationBumpPriority:
+		next, ok := nextTicketPriority(ticket.Priority)
+		if !ok {
+			result.Detail = "priority is already " + ticket.Priority
+			return result, nil
+		}
+		result.Detail = ticket.Priority + " to " + next
+		ticket.Priority = next
+		return result, nil
+
+	case models.TicketEscalationReassign:
+		if action.AssigneeID == nil {
+			return fail(ErrTicketAssigneeInvalid)
+		}
+		if err := validateTicketAssignee(tx, ticket.OrganizationID, *action.AssigneeID); err != nil {
+			return fail(err)
+		}
+		ticket.AssigneeID = action.AssigneeID
+		ticket.Assignee = nil
+		result.Detail = fmt.Sprintf("assigned to user %d", *action.AssigneeID)
+		return result, nil
+
+	case models.TicketEscalationFireEvent:
+		eventType := models.EventTicketSLAWarning
+		if stage == models.TicketSLAStageBreached {
+			eventType = models.EventTicketSLABreached
+		}
+		var policyID uint
+		if ticket.SLAPolicyID != nil {
+			policyID = *ticket.SLAPolicyID
+		}
+		if err := publishEvent(tx, ticket.OrganizationID, eventType, eventdto.TicketSLAEvent{
+			Ticket:   ticketEvent(ticket, nil),
+			PolicyID: policyID,
+			Stage:    stage,
+			SLADueAt: *ticket.SLADueAt,
+		}); err != nil {
+			return fail(err)
+		}
+		result.Detail = eventType
+		return result, nil
+	}
+
+	return fail(fmt.Errorf("unknown action %s", action.Type))
+}
+
+func (t *ticketSLAServiceImpl) findPolicy(user *jwtLib.Claims, policyID uint) (*models.TicketSLAPolicyModel, error) {
+	var policy models.TicketSLAPolicyModel
+	if err := t.db.Scopes(organizationScope(user)).
+		First(&policy, policyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTicketSLAPolicyNotFound
+		}
+		return nil, errors.New("failed to fetch ticket SLA policy")
+	}
+	return &policy, nil
+}
+
+// fillPolicy copies the request into the policy after checking what the
+// validator can not.
+func (t *ticketSLAServiceImpl) fillPolicy(policy *models.TicketSLAPolicyModel, req requestdto.TicketSLAPolicyRequest) error {
+	if req.WarnMinutes >= req.ResolveMinutes {
+		return fmt.Errorf("%w: the warning must come before the deadline", ErrTicketSLAPolicyInvalid)
+	}
+
+	actions := func(requests []requestdto.TicketEscalationActionRequest) ([]models.TicketEscalationAction, error) {
+		result := make([]models.TicketEscalationAction, 0, len(requests))
+		for _, action := range requests {
+			if action.Type == models.TicketEscalationReassign {
+				if action.AssigneeID == nil {
+					return nil, fmt.Errorf("%w: reassign needs an assignee", ErrTicketSLAPolicyInvalid)
+				}
+				if err := validateTicketAssignee(t.db, policy.OrganizationID, *action.AssigneeID); err != nil {
+					return nil, err
+				}
+			}
+			result = append(result, models.TicketEscalationAction{Type: action.Type, AssigneeID: action.AssigneeID})
+		}
+		return result, nil
+	}
+
+	warningActions, err := actions(req.WarningActions)
+	if err != nil {
+		return err
+	}
+	breachActions, err := actions(req.BreachActions)
+	if err != nil {
+		return err
+	}
+
+	policy.Name = strings.TrimSpace(req.Name)
+	policy.Priority = req.Priority
+	policy.Category = nil
+	if req.Category != nil {
+		category := strings.TrimSpace(*req.Category)
+		policy.Category = &category
+	}
+	policy.ResolveMinutes = req.ResolveMinutes
+	policy.WarnMinutes = req.WarnMinutes
+	policy.WarningActions = warningActions
+	policy.BreachActions = breachActions
+	policy.Active = true
+	if req.Active != nil {
+		policy.Active = *req.Active
+	}
+	return nil
+}
+
+// checkDuplicate keeps one policy per priority and category, otherwise the
+// match would depend on the order of the rows.
+func (t *ticketSLAServiceImpl) checkDuplicate(tx *gorm.DB, policy *models.TicketSLAPolicyModel) error {
+	query := tx.Model(&models.TicketSLAPolicyModel{}).
+		Where("organization_id = ? AND id <> ?", policy.OrganizationID, policy.ID)
+	if policy.Priority != nil {
+		query = query.Where("priority = ?", *policy.Priority)
+	} else {
+		query = query.Where("priority IS NULL")
+	}
+	if policy.Category != nil {
+		query = query.Where("category = ?", *policy.Category)
+	} else {
+		query = query.Where("category IS NULL")
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return errors.New("failed to check ticket SLA policies")
+	}
+	if count > 0 {
+		return ErrTicketSLAPolicyDuplicate
+	}
+	return nil
+}
+
+func (t *ticketSLAServiceImpl) mapToPolicyResponse(policy *models.TicketSLAPolicyModel) *responsedto.TicketSLAPolicyResponse {
+	actions := func(values []models.TicketEscalationAction) []responsedto.TicketEscalationActionResponse {
+		result := make([]responsedto.TicketEscalationActionResponse, 0, len(values))
+		for _, action := range values {
+			result = append(result, responsedto.TicketEscalationActionResponse{
+				Type:       action.Type,
+				AssigneeID: action.AssigneeID,
+			})
+		}
+		return result
+	}
+
+	return &responsedto.TicketSLAPolicyResponse{
+		ID:             policy.ID,
+		Name:           policy.Name,
+		Priority:       policy.Priority,
+		Category:       policy.Category,
+		ResolveMinutes: policy.ResolveMinutes,
+		WarnMinutes:    policy.WarnMinutes,
+		WarningActions: actions(policy.WarningActions),
+		BreachActions:  actions(policy.BreachActions),
+		Active:         policy.Active,
+		CreatedAt:      policy.CreatedAt,
+		UpdatedAt:      policy.UpdatedAt,
+	}
+}
+
+func NewTicketSLAService(db *gorm.DB, mailer mail.Mailer) services.TicketSLAService {
+	return &ticketSLAServiceImpl{
+		db:     db,
+		mailer: mailer,
+	}
+}
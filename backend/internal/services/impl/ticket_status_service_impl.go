@@ -0,0 +1,302 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/models"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrTicketStatusLinkInvalid = errors.New("ticket status link is invalid or expired")
+
+// TicketStatusOptions configure the signed status links, URL is the public
+// page that reads the token from the query string. Links are not stored,
+// they work until they expire, the secret changes or the ticket's links are
+// revoked.
+type TicketStatusOptions struct {
+	URL    string
+	Secret string
+	TTL    time.Duration
+}
+
+type ticketStatusServiceImpl struct {
+	db      *gorm.DB
+	options TicketStatusOptions
+}
+
+// GetGuestTickets implements services.TicketStatusService. A guest sees
+// the tickets covering any of their conversations, newest change first.
+func (t *ticketStatusServiceImpl) GetGuestTickets(user *jwtLib.Claims, filter filtersdto.FiltersDto) (*responsedto.GuestTicketListResponse, error) {
+	var tickets []models.TicketModel
+	var total int64
+	offset := (*filter.Page - 1) * *filter.Limit
+
+	query := t.db.Model(&models.TicketModel{}).
+		Where("tickets.id IN (?)", t.guestTicketIDs(user))
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, errors.New("failed to count tickets")
+	}
+
+	if err := query.Offset(offset).Limit(*filter.Limit).
+		Preload("Organization").
+		Order("tickets.updated_at DESC, tickets.id DESC").
+		Find(&tickets).Error; err != nil {
+		return nil, errors.New("failed to fetch tickets")
+	}
+
+	data := make([]responsedto.GuestTicketResponse, 0, len(tickets))
+	workflows := map[uint]*models.TicketWorkflowModel{}
+	for i := range tickets {
+		ticket := &tickets[i]
+		workflow, ok := workflows[ticket.OrganizationID]
+		if !ok {
+			loaded, err := loadTicketWorkflow(t.db, ticket.OrganizationID)
+			if err != nil {
+				return nil, err
+			}
+			workflow = loaded
+			workflows[ticket.OrganizationID] = workflow
+		}
+		data = append(data, *t.mapToGuestTicketResponse(ticket, workflow))
+	}
+
+	return &responsedto.GuestTicketListResponse{
+		Tickets: data,
+		Metadata: responsedto.PaginateMetaData{
+			Total: int(total),
+			Page:  *filter.Page,
+			Limit: *filter.Limit,
+		},
+	}, nil
+}
+
+// GetGuestTicket implements services.TicketStatusService.
+func (t *ticketStatusServiceImpl) GetGuestTicket(user *jwtLib.Claims, ticketID uint) (*responsedto.GuestTicketResponse, error) {
+	var ticket models.TicketModel
+	if err := t.db.Where("tickets.id = ?", ticketID).
+		Where("tickets.id IN (?)", t.guestTicketIDs(user)).
+		Preload("Organization").
+		First(&ticket).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTicketNotFound
+		}
+		return nil, errors.New("failed to fetch ticket")
+	}
+
+	return t.ticketDetail(&ticket)
+}
+
+// GetTicketStatus implements services.TicketStatusService. A bad
+// signature, an expired link and a missing ticket all read the same.
+func (t *ticketStatusServiceImpl) GetTicketStatus(token string) (*responsedto.GuestTicketResponse, error) {
+	ticketID, version, ok := t.verifyStatusToken(strings.TrimSpace(token), time.Now())
+	if !ok {
+		return nil, ErrTicketStatusLinkInvalid
+	}
+
+	var ticket models.TicketModel
+	if err := t.db.Preload("Organization").First(&ticket, ticketID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTicketStatusLinkInvalid
+		}
+		return nil, errors.New("failed to fetch ticket")
+	}
+	if ticket.StatusLinkVersion != version {
+		return nil, ErrTicketStatusLinkInvalid
+	}
+
+	return t.ticketDetail(&ticket)
+}
+
+// CreateStatusLink implements services.TicketStatusService. Sending the
+// link goes out as a staff message of the ticket's conversation, on the
+// conversation's channel. Revoking the previous links raises the version
+// signed into them.
+func (t *ticketStatusServiceImpl) CreateStatusLink(user *jwtLib.Claims, ticketID uint, req requestdto.CreateTicketStatusLinkRequest) (*responsedto.TicketStatusLinkResponse, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+	access, err := ticketAccessScope(t.db, user)
+	if err != nil {
+		return nil, err
+	}
+
+	var ticket models.TicketModel
+	if err := t.db.Scopes(access).
+		First(&ticket, ticketID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTicketNotFound
+		}
+		return nil, errors.New("failed to fetch ticket")
+	}
+
+	if req.RevokePrevious {
+		if err := t.db.Model(&ticket).
+			UpdateColumn("status_link_version", gorm.Expr("status_link_version + 1")).Error; err != nil {
+			return nil, errors.New("failed to revoke ticket status links")
+		}
+		if err := t.db.Model(&ticket).
+			Select("status_link_version").
+			First(&ticket.StatusLinkVersion).Error; err != nil {
+			return nil, errors.New("failed to fetch ticket")
+		}
+	}
+
+	expiresAt := time.Now().Add(t.options.TTL)
+	token := t.signStatusToken(ticket.ID, ticket.StatusLinkVersion, expiresAt)
+	response := &responsedto.TicketStatusLinkResponse{
+		URL:       t.options.URL + "?token=" + url.QueryEscape(token),
+		Token:     token,
+		ExpiresAt: expiresAt.Truncate(time.Second),
+	}
+
+	if !req.Send {
+		return response, nil
+	}
+
+	var conversation models.ConversationModel
+	if err := t.db.Preload("Organization").
+		Preload("Guest").
+		Preload("Contact.Identities").
+		First(&conversation, ticket.ConversationID).Error; err != nil {
+		return nil, errors.New("failed to fetch conversation")
+	}
+
+	text := fmt.Sprintf("You can follow your ticket %s here: %s", ticket.TicketNumber, response.URL)
+	message, reply, err := prepareStaffReply(t.db, &conversation, user.UserID, text)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.db.Transaction(func(tx *gorm.DB) error {
+		return deliverStaffReply(tx, &conversation, message, reply)
+	}); err != nil {
+		return nil, err
+	}
+	response.MessageID = &message.ID
+
+	return response, nil
+}
+
+// guestTicketIDs selects the tickets covering a conversation of the guest,
+// the conversations of every contact linked to them included.
+func (t *ticketStatusServiceImpl) guestTicketIDs(user *jwtLib.Claims) *gorm.DB {
+	conversations := t.db.Model(&models.ConversationModel{}).
+		Select("id").
+		Scopes(guestConversationScope(user))
+
+	return t.db.Model(&models.TicketConversationModel{}).
+		Select("ticket_id").
+		Where("conversation_id IN (?)", conversations)
+}
+
+// ticketDetail adds the public comments to the customer's view of the
+// ticket.
+func (t *ticketStatusServiceImpl) ticketDetail(ticket *models.TicketModel) (*responsedto.GuestTicketResponse, error) {
+	workflow, err := loadTicketWorkflow(t.db, ticket.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []models.TicketCommentModel
+	if err := t.db.Where("ticket_id = ? AND visibility = ?", ticket.ID, models.TicketCommentPublic).
+		Preload("Author").
+		Order("created_at ASC, id ASC").
+		Find(&comments).Error; err != nil {
+		return nil, errors.New("failed to fetch ticket comments")
+	}
+
+	response := t.mapToGuestTicketResponse(ticket, workflow)
+	response.Comments = make([]responsedto.GuestTicketCommentResponse, 0, len(comments))
+	for _, comment := range comments {
+		item := responsedto.GuestTicketCommentResponse{
+			ID:        comment.ID,
+			Body:      comment.Body,
+			CreatedAt: comment.CreatedAt,
+		}
+		if comment.Author != nil {
+			item.AuthorName = comment.Author.Name
+		}
+		response.Comments = append(response.Comments, item)
+	}
+	return response, nil
+}
+
+// signStatusToken returns "<ticket id>.<version>.<expiry unix>.<signature>",
+// the signature is the url safe hmac-sha256 of the first three parts.
+func (t *ticketStatusServiceImpl) signStatusToken(ticketID uint, version uint, expiresAt time.Time) string {
+	payload := strconv.FormatUint(uint64(ticketID), 10) + "." +
+		strconv.FormatUint(uint64(version), 10) + "." +
+		strconv.FormatInt(expiresAt.Unix(), 10)
+	return payload + "." + t.statusSignature(payload)
+}
+
+func (t *ticketStatusServiceImpl) verifyStatusToken(token string, now time.Time) (uint, uint, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 {
+		return 0, 0, false
+	}
+	expected := t.statusSignature(strings.Join(parts[:3], "."))
+	if !hmac.Equal([]byte(parts[3]), []byte(expected)) {
+		return 0, 0, false
+	}
+
+	ticketID, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	version, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	expiresAt, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || !now.Before(time.Unix(expiresAt, 0)) {
+		return 0, 0, false
+	}
+	return uint(ticketID), uint(version), true
+}
+
+func (t *ticketStatusServiceImpl) statusSignature(payload string) string {
+	mac := hmac.New(sha256.New, []byte(t.options.Secret))
+	mac.Write([]byte("ticket-status:" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (t *ticketStatusServiceImpl) mapToGuestTicketResponse(ticket *models.TicketModel, workflow *models.TicketWorkflowModel) *responsedto.GuestTicketResponse {
+	response := &responsedto.GuestTicketResponse{
+		ID:             ticket.ID,
+		TicketNumber:   ticket.TicketNumber,
+		Name:           ticket.Name,
+		Status:         ticket.Status,
+		StatusName:     ticket.Status,
+		StatusCategory: ticket.StatusCategory,
+		CreatedAt:      ticket.CreatedAt,
+		UpdatedAt:      ticket.UpdatedAt,
+	}
+
+	if status, ok := workflowStatus(workflow, ticket.Status); ok {
+		response.StatusName = status.Name
+	}
+	if ticket.Organization != nil {
+		response.OrganizationName = ticket.Organization.Name
+	}
+
+	return response
+}
+
+func NewTicketStatusService(db *gorm.DB, options TicketStatusOptions) services.TicketStatusService {
+	return &ticketStatusServiceImpl{db: db, options: options}
+}
@@ -0,0 +1,425 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTicketTimeEntryNotFound = errors.New("ticket time entry not found")
+	ErrTicketTimeEntryInvalid  = errors.New("invalid ticket time entry")
+	ErrTicketTimerRunning      = errors.New("a timer is already running")
+	ErrTicketTimerNotRunning   = errors.New("no timer is running on this ticket")
+	ErrTicketTimeReportInvalid = errors.New("invalid time report range")
+)
+
+// ticketTimeReportMaxDays bounds the range of a time report.
+const ticketTimeReportMaxDays = 366
+
+type ticketTimeServiceImpl struct {
+	db *gorm.DB
+}
+
+// ticketTimeTotalsRow is one row of a time entry aggregate, Key is the
+// grouped user or ticket.
+type ticketTimeTotalsRow struct {
+	Key             uint
+	Entries         int
+	TotalSeconds    int64
+	BillableSeconds int64
+}
+
+const ticketTimeTotalsSelect = "COUNT(*) AS entries, " +
+	"COALESCE(SUM(duration_seconds), 0) AS total_seconds, " +
+	"COALESCE(SUM(CASE WHEN billable THEN duration_seconds ELSE 0 END), 0) AS billable_seconds"
+
+// GetEntries implements services.TicketTimeService.
+func (t *ticketTimeServiceImpl) GetEntries(user *jwtLib.Claims, ticketID uint) (*responsedto.TicketTimeEntryListResponse, error) {
+	ticket, err := t.findTicket(user, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []models.TicketTimeEntryModel
+	if err := t.db.Where("ticket_id = ?", ticket.ID).
+		Preload("User").
+		Order("started_at DESC, id DESC").
+		Find(&entries).Error; err != nil {
+		return nil, errors.New("failed to fetch ticket time entries")
+	}
+
+	response := &responsedto.TicketTimeEntryListResponse{
+		Entries: make([]responsedto.TicketTimeEntryResponse, 0, len(entries)),
+	}
+	for i := range entries {
+		entry := &entries[i]
+		response.Entries = append(response.Entries, *t.mapToTimeEntryResponse(entry))
+		if entry.EndedAt == nil {
+			continue
+		}
+		response.Totals.Entries++
+		response.Totals.TotalSeconds += int64(entry.DurationSeconds)
+		if entry.Billable {
+			response.Totals.BillableSeconds += int64(entry.DurationSeconds)
+		}
+	}
+	return response, nil
+}
+
+// CreateEntry implements services.TicketTimeService. The entry is logged
+// for the caller.
+func (t *ticketTimeServiceImpl) CreateEntry(user *jwtLib.Claims, ticketID uint, req requestdto.CreateTicketTimeEntryRequest) (*responsedto.TicketTimeEntryResponse, error) {
+	ticket, err := t.findTicket(user, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	duration := time.Duration(req.DurationMinutes) * time.Minute
+	startedAt := now.Add(-duration)
+	if req.StartedAt != nil {
+		startedAt = *req.StartedAt
+	}
+	endedAt := startedAt.Add(duration)
+	if endedAt.After(now) {
+		return nil, fmt.Errorf("%w: the entry can not end in the future", ErrTicketTimeEntryInvalid)
+	}
+
+	entry := models.TicketTimeEntryModel{
+		OrganizationID:  ticket.OrganizationID,
+		TicketID:        ticket.ID,
+		UserID:          user.UserID,
+		StartedAt:       startedAt,
+		EndedAt:         &endedAt,
+		DurationSeconds: int(duration.Seconds()),
+		Note:            ticketTimeNote(req.Note),
+		Billable:        req.Billable,
+	}
+	if err := t.db.Create(&entry).Error; err != nil {
+		return nil, errors.New("failed to create ticket time entry")
+	}
+
+	return t.loadTimeEntry(entry.ID)
+}
+
+// DeleteEntry implements services.TicketTimeService. Staff remove their
+// own entries, removing a running timer cancels it.
+func (t *ticketTimeServiceImpl) DeleteEntry(user *jwtLib.Claims, ticketID uint, entryID uint) error {
+	ticket, err := t.findTicket(user, ticketID)
+	if err != nil {
+		return err
+	}
+
+	result := t.db.Where("id = ? AND ticket_id = ? AND user_id = ?", entryID, ticket.ID, user.UserID).
+		Delete(&models.TicketTimeEntryModel{})
+	if result.Error != nil {
+		return errors.New("failed to delete ticket time entry")
+	}
+	if result.RowsAffected == 0 {
+		return ErrTicketTimeEntryNotFound
+	}
+	return nil
+}
+
+// StartTimer implements services.TicketTimeService. The caller's user row
+// is held while looking for a running timer, so two requests can not both
+// start one.
+func (t *ticketTimeServiceImpl) StartTimer(user *jwtLib.Claims, ticketID uint, req requestdto.StartTicketTimerRequest) (*responsedto.TicketTimeEntryResponse, error) {
+	ticket, err := t.findTicket(user, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := models.TicketTimeEntryModel{
+		OrganizationID: ticket.OrganizationID,
+		TicketID:       ticket.ID,
+		UserID:         user.UserID,
+		StartedAt:      time.Now(),
+		Note:           ticketTimeNote(req.Note),
+		Billable:       req.Billable,
+	}
+
+	if err := t.db.Transaction(func(tx *gorm.DB) error {
+		var staff models.UserModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&staff, user.UserID).Error; err != nil {
+			return errors.New("failed to fetch user")
+		}
+
+		var running models.TicketTimeEntryModel
+		err := tx.Preload("Ticket").
+			Where("user_id = ? AND ended_at IS NULL", user.UserID).
+			First(&running).Error
+		if err == nil {
+			if running.Ticket != nil {
+				return fmt.Errorf("%w on ticket %s", ErrTicketTimerRunning, running.Ticket.TicketNumber)
+			}
+			return ErrTicketTimerRunning
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("failed to fetch running timer")
+		}
+
+		if err := tx.Create(&entry).Error; err != nil {
+			return errors.New("failed to start timer")
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return t.loadTimeEntry(entry.ID)
+}
+
+// StopTimer implements services.TicketTimeService.
+func (t *ticketTimeServiceImpl) StopTimer(user *jwtLib.Claims, ticketID uint, req requestdto.StopTicketTimerRequest) (*responsedto.TicketTimeEntryResponse, error) {
+	ticket, err := t.findTicket(user, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry models.TicketTimeEntryModel
+	if err := t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("ticket_id = ? AND user_id = ? AND ended_at IS NULL", ticket.ID, user.UserID).
+			First(&entry).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrTicketTimerNotRunning
+			}
+			return errors.New("failed to fetch running timer")
+		}
+
+		now := time.Now()
+		entry.EndedAt = &now
+		entry.DurationSeconds = max(int(now.Sub(entry.StartedAt).Seconds()), 0)
+		if req.Note != nil {
+			entry.Note = ticketTimeNote(*req.Note)
+		}
+		if req.Billable != nil {
+			entry.Billable = *req.Billable
+		}
+
+		if err := tx.Save(&entry).Error; err != nil {
+			return errors.New("failed to stop timer")
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return t.loadTimeEntry(entry.ID)
+}
+
+// GetReport implements services.TicketTimeService. Entries count on the
+// day they started, running timers are left out until they are stopped.
+func (t *ticketTimeServiceImpl) GetReport(user *jwtLib.Claims, filter filtersdto.TicketTimeReportFiltersDto) (*responsedto.TicketTimeReportResponse, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+	organizationID := *user.OrganizationId
+
+	location, err := organizationLocation(t.db, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	from, err := time.ParseInLocation(time.DateOnly, filter.From, location)
+	if err != nil {
+		return nil, fmt.Errorf("%w: from must be YYYY-MM-DD", ErrTicketTimeReportInvalid)
+	}
+	to, err := time.ParseInLocation(time.DateOnly, filter.To, location)
+	if err != nil {
+		return nil, fmt.Errorf("%w: to must be YYYY-MM-DD", ErrTicketTimeReportInvalid)
+	}
+	end := to.AddDate(0, 0, 1)
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: to is before from", ErrTicketTimeReportInvalid)
+	}
+	if end.After(from.AddDate(0, 0, ticketTimeReportMaxDays)) {
+		return nil, fmt.Errorf("%w: the range can not be longer than %d days", ErrTicketTimeReportInvalid, ticketTimeReportMaxDays)
+	}
+
+	entries := func() *gorm.DB {
+		query := t.db.Model(&models.TicketTimeEntryModel{}).
+			Where("organization_id = ?", organizationID).
+			Where("ended_at IS NOT NULL").
+			Where("started_at >= ? AND started_at < ?", from, end)
+		if filter.UserID != nil {
+			query = query.Where("user_id = ?", *filter.UserID)
+		}
+		if filter.TicketID != nil {
+			query = query.Where("ticket_id = ?", *filter.TicketID)
+		}
+		return query
+	}
+
+	var total ticketTimeTotalsRow
+	if err := entries().Select(ticketTimeTotalsSelect).Scan(&total).Error; err != nil {
+		return nil, errors.New("failed to sum time entries")
+	}
+
+	var agentRows, ticketRows []ticketTimeTotalsRow
+	if err := entries().Select("user_id AS `key`, " + ticketTimeTotalsSelect).
+		Group("user_id").
+		Order("total_seconds DESC, user_id ASC").
+		Scan(&agentRows).Error; err != nil {
+		return nil, errors.New("failed to sum time entries per agent")
+	}
+	if err := entries().Select("ticket_id AS `key`, " + ticketTimeTotalsSelect).
+		Group("ticket_id").
+		Order("total_seconds DESC, ticket_id ASC").
+		Scan(&ticketRows).Error; err != nil {
+		return nil, errors.New("failed to sum time entries per ticket")
+	}
+
+	userIDs := make([]uint, 0, len(agentRows))
+	for _, row := range agentRows {
+		userIDs = append(userIDs, row.Key)
+	}
+	var users []models.UserModel
+	if len(userIDs) > 0 {
+		if err := t.db.Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+			return nil, errors.New("failed to fetch users")
+		}
+	}
+	usersByID := make(map[uint]*models.UserModel, len(users))
+	for i := range users {
+		usersByID[users[i].ID] = &users[i]
+	}
+
+	ticketIDs := make([]uint, 0, len(ticketRows))
+	for _, row := range ticketRows {
+		ticketIDs = append(ticketIDs, row.Key)
+	}
+	var tickets []models.TicketModel
+	if len(ticketIDs) > 0 {
+		if err := t.db.Where("id IN ?", ticketIDs).Find(&tickets).Error; err != nil {
+			return nil, errors.New("failed to fetch tickets")
+		}
+	}
+	ticketsByID := make(map[uint]*models.TicketModel, len(tickets))
+	for i := range tickets {
+		ticketsByID[tickets[i].ID] = &tickets[i]
+	}
+
+	response := &responsedto.TicketTimeReportResponse{
+		From:     filter.From,
+		To:       filter.To,
+		Timezone: location.String(),
+		Totals:   ticketTimeTotals(total),
+		Agents:   make([]responsedto.TicketTimeAgentReport, 0, len(agentRows)),
+		Tickets:  make([]responsedto.TicketTimeTicketReport, 0, len(ticketRows)),
+	}
+	for _, row := range agentRows {
+		agent := responsedto.TicketTimeAgentReport{
+			User:   responsedto.UserData{ID: row.Key},
+			Totals: ticketTimeTotals(row),
+		}
+		if staff := usersByID[row.Key]; staff != nil {
+			agent.User.Email = staff.Email
+			agent.User.Name = staff.Name
+		}
+		response.Agents = append(response.Agents, agent)
+	}
+	for _, row := range ticketRows {
+		report := responsedto.TicketTimeTicketReport{
+			Ticket: responsedto.TicketSummaryResponse{ID: row.Key},
+			Totals: ticketTimeTotals(row),
+		}
+		if ticket := ticketsByID[row.Key]; ticket != nil {
+			report.Ticket.TicketNumber = ticket.TicketNumber
+			report.Ticket.Name = ticket.Name
+			report.Ticket.Status = ticket.Status
+			report.Ticket.StatusCategory = ticket.StatusCategory
+		}
+		response.Tickets = append(response.Tickets, report)
+	}
+
+	return response, nil
+}
+
+// findTicket loads a ticket the caller may work on.
+func (t *ticketTimeServiceImpl) findTicket(user *jwtLib.Claims, ticketID uint) (*models.TicketModel, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+	access, err := ticketAccessScope(t.db, user)
+	if err != nil {
+		return nil, err
+	}
+
+	var ticket models.TicketModel
+	if err := t.db.Scopes(access).
+		First(&ticket, ticketID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTicketNotFound
+		}
+		return nil, errors.New("failed to fetch ticket")
+	}
+	return &ticket, nil
+}
+
+func (t *ticketTimeServiceImpl) loadTimeEntry(entryID uint) (*responsedto.TicketTimeEntryResponse, error) {
+	var entry models.TicketTimeEntryModel
+	if err := t.db.Preload("User").First(&entry, entryID).Error; err != nil {
+		return nil, errors.New("failed to load ticket time entry")
+	}
+	return t.mapToTimeEntryResponse(&entry), nil
+}
+
+func (t *ticketTimeServiceImpl) mapToTimeEntryResponse(entry *models.TicketTimeEntryModel) *responsedto.TicketTimeEntryResponse {
+	response := &responsedto.TicketTimeEntryResponse{
+		ID:              entry.ID,
+		TicketID:        entry.TicketID,
+		UserID:          entry.UserID,
+		StartedAt:       entry.StartedAt,
+		EndedAt:         entry.EndedAt,
+		DurationSeconds: entry.DurationSeconds,
+		Note:            entry.Note,
+		Billable:        entry.Billable,
+		Running:         entry.EndedAt == nil,
+		CreatedAt:       entry.CreatedAt,
+	}
+
+	if entry.User != nil {
+		response.User = &responsedto.UserData{
+			ID:    entry.User.ID,
+			Email: entry.User.Email,
+			Name:  entry.User.Name,
+		}
+	}
+
+	return response
+}
+
+func ticketTimeTotals(row ticketTimeTotalsRow) responsedto.TicketTimeTotals {
+	return responsedto.TicketTimeTotals{
+		Entries:         row.Entries,
+		TotalSeconds:    row.TotalSeconds,
+		BillableSeconds: row.BillableSeconds,
+	}
+}
+
+// ticketTimeNote trims the note, a blank note is not stored.
+func ticketTimeNote(note string) *string {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return nil
+	}
+	return &note
+}
+
+func NewTicketTimeService(db *gorm.DB) services.TicketTimeService {
+	return &ticketTimeServiceImpl{db: db}
+}
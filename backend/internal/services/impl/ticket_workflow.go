@@ -0,0 +1,158 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTicketWorkflowInvalid        = errors.New("invalid ticket workflow")
+	ErrTicketWorkflowStatusInUse    = errors.New("ticket status is still used by tickets")
+	ErrTicketStatusInvalid          = errors.New("ticket status is not part of the organization's workflow")
+	ErrTicketTransitionNotAllowed   = errors.New("ticket status change is not allowed by the workflow")
+	ErrTicketResolutionNoteRequired = errors.New("a resolution note is required for this status")
+)
+
+var ticketStatusKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// defaultTicketWorkflow matches how tickets worked before workflows were
+// configurable: pending, in_progress and done with every move allowed.
+func defaultTicketWorkflow(organizationID uint) *models.TicketWorkflowModel {
+	statuses := []models.TicketWorkflowStatus{
+		{Key: models.TicketStatusPending, Name: "Pending", Category: models.TicketStatusCategoryOpen},
+		{Key: models.TicketStatusInProgress, Name: "In Progress", Category: models.TicketStatusCategoryOpen},
+		{Key: models.TicketStatusDone, Name: "Done", Category: models.TicketStatusCategoryClosed},
+	}
+
+	var transitions []models.TicketWorkflowTransition
+	for _, from := range statuses {
+		for _, to := range statuses {
+			if from.Key != to.Key {
+				transitions = append(transitions, models.TicketWorkflowTransition{From: from.Key, To: to.Key})
+			}
+		}
+	}
+
+	return &models.TicketWorkflowModel{
+		OrganizationID: organizationID,
+		InitialStatus:  models.TicketStatusPending,
+		Statuses:       statuses,
+		Transitions:    transitions,
+	}
+}
+
+// loadTicketWorkflow returns the workflow of the organization, creating the
+// default one the first time.
+func loadTicketWorkflow(db *gorm.DB, organizationID uint) (*models.TicketWorkflowModel, error) {
+	var workflow models.TicketWorkflowModel
+	err := db.Where("organization_id = ?", organizationID).First(&workflow).Error
+	if err == nil {
+		return &workflow, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("failed to fetch ticket workflow")
+	}
+
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(defaultTicketWorkflow(organizationID)).Error; err != nil {
+		return nil, errors.New("failed to create ticket workflow")
+	}
+	if err := db.Where("organization_id = ?", organizationID).First(&workflow).Error; err != nil {
+		return nil, errors.New("failed to fetch ticket workflow")
+	}
+	return &workflow, nil
+}
+
+// lockTicketWorkflow reads the workflow with a shared lock, so it can not
+// change before the transaction ends. UpdateWorkflow takes the same row for
+// update before it checks the statuses in use, which orders the two.
+func lockTicketWorkflow(tx *gorm.DB, organizationID uint) (*models.TicketWorkflowModel, error) {
+	var workflow models.TicketWorkflowModel
+	err := tx.Clauses(clause.Locking{Strength: "SHARE"}).
+		Where("organization_id = ?", organizationID).
+		First(&workflow).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if _, err := loadTicketWorkflow(tx, organizationID); err != nil {
+			return nil, err
+		}
+		err = tx.Clauses(clause.Locking{Strength: "SHARE"}).
+			Where("organization_id = ?", organizationID).
+			First(&workflow).Error
+	}
+	if err != nil {
+		return nil, errors.New("failed to fetch ticket workflow")
+	}
+	return &workflow, nil
+}
+
+func workflowStatus(workflow *models.TicketWorkflowModel, key string) (*models.TicketWorkflowStatus, bool) {
+	for i := range workflow.Statuses {
+		if workflow.Statuses[i].Key == key {
+			return &workflow.Statuses[i], true
+		}
+	}
+	return nil, false
+}
+
+func workflowAllows(workflow *models.TicketWorkflowModel, from string, to string) bool {
+	for _, transition := range workflow.Transitions {
+		if transition.From == from && transition.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// buildTicketWorkflow checks the requested workflow and turns it into the
+// statuses and transitions to store.
+func buildTicketWorkflow(req requestdto.UpdateTicketWorkflowRequest) ([]models.TicketWorkflowStatus, []models.TicketWorkflowTransition, error) {
+	statuses := make([]models.TicketWorkflowStatus, 0, len(req.Statuses))
+	known := map[string]bool{}
+	for _, status := range req.Statuses {
+		if !ticketStatusKeyPattern.MatchString(status.Key) {
+			return nil, nil, fmt.Errorf("%w: status key %q must be lowercase letters, digits and underscores", ErrTicketWorkflowInvalid, status.Key)
+		}
+		if known[status.Key] {
+			return nil, nil, fmt.Errorf("%w: status %s is listed twice", ErrTicketWorkflowInvalid, status.Key)
+		}
+		known[status.Key] = true
+		statuses = append(statuses, models.TicketWorkflowStatus{
+			Key:                    status.Key,
+			Name:                   status.Name,
+			Category:               status.Category,
+			RequiresResolutionNote: status.RequiresResolutionNote,
+		})
+	}
+
+	initial, ok := workflowStatus(&models.TicketWorkflowModel{Statuses: statuses}, req.InitialStatus)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: initial status %s is not in the workflow", ErrTicketWorkflowInvalid, req.InitialStatus)
+	}
+	if initial.Category != models.TicketStatusCategoryOpen {
+		return nil, nil, fmt.Errorf("%w: initial status %s must be open", ErrTicketWorkflowInvalid, req.InitialStatus)
+	}
+
+	transitions := make([]models.TicketWorkflowTransition, 0, len(req.Transitions))
+	seen := map[models.TicketWorkflowTransition]bool{}
+	for _, transition := range req.Transitions {
+		if !known[transition.From] || !known[transition.To] {
+			return nil, nil, fmt.Errorf("%w: transition %s to %s uses an unknown status", ErrTicketWorkflowInvalid, transition.From, transition.To)
+		}
+		if transition.From == transition.To {
+			return nil, nil, fmt.Errorf("%w: transition %s to itself", ErrTicketWorkflowInvalid, transition.From)
+		}
+		value := models.TicketWorkflowTransition{From: transition.From, To: transition.To}
+		if !seen[value] {
+			seen[value] = true
+			transitions = append(transitions, value)
+		}
+	}
+
+	return statuses, transitions, nil
+}
@@ -0,0 +1,17 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// userRoleName returns the name of the role in the caller's claims.
+func userRoleName(db *gorm.DB, roleID uint) (string, error) {
+	var role models.UserRoleModel
+	if err := db.Select("name").First(&role, roleID).Error; err != nil {
+		return "", errors.New("failed to fetch user role")
+	}
+	return role.Name, nil
+}
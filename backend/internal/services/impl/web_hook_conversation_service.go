@@ -2,15 +2,32 @@ package impl
 
 import (
 	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
 	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/lib/logger"
 	"DewaSRY/sociomile-app/pkg/models"
+	"encoding/json"
 	"errors"
+	"strings"
+	"sync"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
 	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrInboxEventNotFound   = errors.New("webhook inbox event not found")
+	ErrInboxEventNotParked  = errors.New("only parked or failed events can be retried")
+)
+
+const (
+	webhookInboxMaxAttempts = 5
+	// webhookInboxClaimLease is how long a claimed event stays invisible to
+	// other workers, an event still processing after it is picked up again
+	webhookInboxClaimLease = 2 * time.Minute
 )
 
 type webHookConversationServiceImpl struct {
@@ -20,87 +37,270 @@ type webHookConversationServiceImpl struct {
 // ProcessConversation implements services.WebHookConversationService.
 func (t *webHookConversationServiceImpl) ProcessConversation(req requestdto.WebHooksRequest) error {
 	return t.db.Transaction(func(tx *gorm.DB) error {
-		var organization models.OrganizationModel
+		in := inboundMessage{
+			OrganizationID: req.OrganizationID,
+			Channel:        models.ConversationChannelWebhook,
+			Email:          req.Email,
+			Name:           req.Name,
+			Phone:          req.Phone,
+			Message:        req.Message,
+			Attributes:     req.Attributes,
+		}
+		if req.CallbackURL != "" {
+			// the callback hosts may have changed since the event was accepted
+			hosts, err := organizationCallbackHosts(tx, req.OrganizationID)
+			if err != nil {
+				return err
+			}
+			if webhookCallbackAllowed(hosts, req.CallbackURL) {
+				in.CallbackURL = &req.CallbackURL
+			} else {
+				logger.ErrorLog("Webhook callback url is not allowed", map[string]any{
+					"organization_id": req.OrganizationID,
+					"callback_url":    req.CallbackURL,
+				})
+			}
+		}
+		if req.AvatarURL != "" {
+			in.AvatarURL = &req.AvatarURL
+		}
+		if req.Source != "" {
+			in.Identities = append(in.Identities, newContactIdentity(req.Source, req.ExternalID))
+		}
+		_, err := ingestInboundMessage(tx, in)
+		return err
+	})
+}
 
-		if err := tx.Model(&models.OrganizationModel{}).
-			First(&organization, req.OrganizationID).Error; err != nil {
-			return ErrOrganizationNotFound
+// EnqueueConversation implements services.WebHookConversationService.
+func (t *webHookConversationServiceImpl) EnqueueConversation(req requestdto.WebHooksRequest) (*responsedto.WebhookAcceptedResponse, error) {
+	var organization models.OrganizationModel
+	if err := t.db.Select("id", "webhook_callback_hosts").First(&organization, req.OrganizationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
 		}
+		return nil, errors.New("failed to fetch organization")
+	}
+	if req.CallbackURL != "" && !webhookCallbackAllowed(organization.WebhookCallbackHosts, req.CallbackURL) {
+		return nil, ErrWebhookCallbackNotAllowed
+	}
 
-		user, err := t.findOrCreateUser(tx, req.Email)
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, errors.New("failed to encode webhook event")
+	}
 
-		if err != nil {
-			return errors.New("failed to create or find user")
-		}
+	event := models.WebhookInboxEventModel{
+		OrganizationID: organization.ID,
+		ContactKey:     strings.ToLower(strings.TrimSpace(req.Email)),
+		Payload:        payload,
+		Status:         models.WebhookInboxStatusPending,
+		NextAttemptAt:  time.Now(),
+	}
 
-		conversation, err := t.findOrCreateConversation(tx, user.ID, organization.ID)
+	if err := t.db.Create(&event).Error; err != nil {
+		return nil, errors.New("failed to store webhook event")
+	}
 
-		if err != nil {
-			return errors.New("failed to create or find conversation")
-		}
+	return &responsedto.WebhookAcceptedResponse{EventID: event.ID}, nil
+}
 
-		newMessages := models.ConversationMessageModel{
-			OrganizationID: conversation.OrganizationID,
-			CreatedByID:    user.ID,
-			Message:        req.Message,
-			ConversationID: conversation.ID,
-		}
+// ProcessInbox implements services.WebHookConversationService.
+func (t *webHookConversationServiceImpl) ProcessInbox(workerCount int, batchSize int) (int, error) {
+	events, err := t.claimInboxEvents(batchSize)
+	if err != nil {
+		return 0, err
+	}
 
-		if err := tx.Create(&newMessages).Error; err != nil {
-			return errors.New("failed to create message")
-		}
-		return nil
-	})
+	if workerCount < 1 {
+		workerCount = 1
+	}
+
+	// a batch holds at most one event per contact, so the workers can run
+	// in parallel without breaking the order of a contact's messages
+	jobs := make(chan *models.WebhookInboxEventModel)
+	var wg sync.WaitGroup
+	for i := 0; i < workerCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for event := range jobs {
+				t.processInboxEvent(event)
+			}
+		}()
+	}
+
+	for i := range events {
+		jobs <- &events[i]
+	}
+	close(jobs)
+	wg.Wait()
+
+	return len(events), nil
 }
 
-func (t *webHookConversationServiceImpl) findOrCreateUser(tx *gorm.DB, email string) (*models.UserModel, error) {
-	var userModel models.UserModel
+// GetInboxEvents implements services.WebHookConversationService.
+func (t *webHookConversationServiceImpl) GetInboxEvents(filter filtersdto.FiltersDto, status string) (*responsedto.WebhookInboxEventPaginateResponse, error) {
+	var events []models.WebhookInboxEventModel
+	var total int64
+	offset := (*filter.Page - 1) * *filter.Limit
+
+	query := t.db.Model(&models.WebhookInboxEventModel{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
 
-	err := tx.Where("email = ?", email).First(&userModel).Error
+	if err := query.Count(&total).Error; err != nil {
+		return nil, errors.New("failed to count webhook events")
+	}
 
-	if err != nil {
+	if err := query.Offset(offset).Limit(*filter.Limit).
+		Order("id DESC").
+		Find(&events).Error; err != nil {
+		return nil, errors.New("failed to fetch webhook events")
+	}
+
+	data := make([]responsedto.WebhookInboxEventResponse, 0, len(events))
+	for _, event := range events {
+		data = append(data, responsedto.WebhookInboxEventResponse{
+			ID:             event.ID,
+			OrganizationID: event.OrganizationID,
+			ContactKey:     event.ContactKey,
+			Status:         event.Status,
+			Attempts:       event.Attempts,
+			NextAttemptAt:  event.NextAttemptAt,
+			LastError:      event.LastError,
+			ProcessedAt:    event.ProcessedAt,
+			Payload:        event.Payload,
+			CreatedAt:      event.CreatedAt,
+		})
+	}
+
+	return &responsedto.WebhookInboxEventPaginateResponse{
+		Data: data,
+		Metadata: responsedto.PaginateMetaData{
+			Total: int(total),
+			Page:  *filter.Page,
+			Limit: *filter.Limit,
+		},
+	}, nil
+}
+
+// RetryInboxEvent implements services.WebHookConversationService.
+func (t *webHookConversationServiceImpl) RetryInboxEvent(eventID uint) error {
+	var event models.WebhookInboxEventModel
+	if err := t.db.First(&event, eventID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			userModel = models.UserModel{
-				Email:    email,
-				Password: "",
-				Name:     "",
-			}
-			if err := tx.Create(&userModel).Error; err != nil {
-				return nil, errors.New("failed to create user")
-			}
-		} else {
-			return nil, err
+			return ErrInboxEventNotFound
 		}
+		return errors.New("failed to fetch webhook event")
+	}
+
+	if event.Status != models.WebhookInboxStatusParked && event.Status != models.WebhookInboxStatusFailed {
+		return ErrInboxEventNotParked
 	}
-	return &userModel, nil
+
+	if err := t.db.Model(&event).Updates(map[string]any{
+		"status":          models.WebhookInboxStatusPending,
+		"attempts":        0,
+		"next_attempt_at": time.Now(),
+	}).Error; err != nil {
+		return errors.New("failed to requeue webhook event")
+	}
+
+	return nil
 }
 
-func (t *webHookConversationServiceImpl) findOrCreateConversation(tx *gorm.DB, userId uint, organizationId uint) (*models.ConversationModel, error) {
-	var conversation models.ConversationModel
+// claimInboxEvents picks the oldest due event of every contact that has no
+// older event still waiting, and leases them to this process.
+func (t *webHookConversationServiceImpl) claimInboxEvents(batchSize int) ([]models.WebhookInboxEventModel, error) {
+	var events []models.WebhookInboxEventModel
+	now := time.Now()
+	unfinished := []string{
+		models.WebhookInboxStatusPending,
+		models.WebhookInboxStatusFailed,
+		models.WebhookInboxStatusProcessing,
+	}
 
-	err := tx.
-		Where("guest_id = ?", userId).
-		Where("organization_id = ?", organizationId).
-		Where("status != ?", models.ConversationStatusDone).
-		First(&conversation).Error
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ?", unfinished).
+			Where("next_attempt_at <= ?", now).
+			Where(`NOT EXISTS (
+				SELECT 1 FROM webhook_inbox_events older
+				WHERE older.organization_id = webhook_inbox_events.organization_id
+				AND older.contact_key = webhook_inbox_events.contact_key
+				AND older.id < webhook_inbox_events.id
+				AND older.status IN ?
+				AND older.deleted_at IS NULL
+			)`, unfinished).
+			Order("id ASC").
+			Limit(batchSize).
+			Find(&events).Error; err != nil {
+			return err
+		}
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(events))
+		for _, event := range events {
+			ids = append(ids, event.ID)
+		}
 
+		return tx.Model(&models.WebhookInboxEventModel{}).
+			Where("id IN ?", ids).
+			Updates(map[string]any{
+				"status":          models.WebhookInboxStatusProcessing,
+				"next_attempt_at": now.Add(webhookInboxClaimLease),
+			}).Error
+	})
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			conversation = models.ConversationModel{
-				OrganizationID: organizationId,
-				GuestID:        userId,
-				Status:         models.ConversationStatusPending,
-			}
+		return nil, errors.New("failed to claim webhook events")
+	}
 
-			if err := tx.Create(&conversation).Error; err != nil {
-				return nil, err
-			}
+	return events, nil
+}
+
+func (t *webHookConversationServiceImpl) processInboxEvent(event *models.WebhookInboxEventModel) {
+	var req requestdto.WebHooksRequest
+	err := json.Unmarshal(event.Payload, &req)
+	if err == nil {
+		err = t.ProcessConversation(req)
+	}
+
+	event.Attempts++
+	if err == nil {
+		now := time.Now()
+		event.Status = models.WebhookInboxStatusProcessed
+		event.ProcessedAt = &now
+		event.LastError = nil
+	} else {
+		reason := err.Error()
+		event.LastError = &reason
+
+		// a missing organization never heals by itself, park it right away
+		if errors.Is(err, ErrOrganizationNotFound) || event.Attempts >= webhookInboxMaxAttempts {
+			event.Status = models.WebhookInboxStatusParked
 		} else {
-			return nil, err
+			event.Status = models.WebhookInboxStatusFailed
+			event.NextAttemptAt = time.Now().Add(outboundBackoff(event.Attempts))
 		}
+
+		logger.ErrorLog("Failed to process webhook event", map[string]any{
+			"event_id": event.ID,
+			"attempts": event.Attempts,
+			"error":    reason,
+		})
 	}
 
-	return &conversation, nil
+	if err := t.db.Save(event).Error; err != nil {
+		logger.ErrorLog("Failed to update webhook event", map[string]any{
+			"event_id": event.ID,
+			"error":    err.Error(),
+		})
+	}
 }
 
 func NewWebHookConversationService(db *gorm.DB) services.WebHookConversationService {
@@ -0,0 +1,183 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/outbound"
+	"DewaSRY/sociomile-app/pkg/models"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWebhookSignatureInvalid    = errors.New("webhook signature is missing or invalid")
+	ErrWebhookCallbackNotAllowed  = errors.New("callback url must be https on one of the organization's callback hosts")
+	ErrWebhookCallbackHostInvalid = errors.New("callback host must be a host name such as api.example.com or *.example.com")
+	ErrWebhookSecretNotConfigured = errors.New("rotate a webhook secret before requiring signed requests")
+)
+
+// webhookSignatureTolerance is how far the signed timestamp may be from now
+const webhookSignatureTolerance = 5 * time.Minute
+
+var webhookHostPattern = regexp.MustCompile(`^(\*\.)?([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}$`)
+
+// VerifySignature implements services.WebHookConversationService. An
+// unsigned request passes until the organization requires signatures, a
+// signed one is always checked.
+func (t *webHookConversationServiceImpl) VerifySignature(organizationID uint, signature string, body []byte) error {
+	var organization models.OrganizationModel
+	if err := t.db.Select("id", "webhook_secret", "webhook_signature_required").First(&organization, organizationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// unknown organizations answer like a bad signature so the
+			// route does not tell which ids exist
+			return ErrWebhookSignatureInvalid
+		}
+		return errors.New("failed to fetch organization")
+	}
+	if signature == "" && !organization.WebhookSignatureRequired {
+		return nil
+	}
+	if organization.WebhookSecret == nil {
+		return ErrWebhookSignatureInvalid
+	}
+
+	if err := outbound.VerifySignature(*organization.WebhookSecret, signature, body, webhookSignatureTolerance); err != nil {
+		return ErrWebhookSignatureInvalid
+	}
+	return nil
+}
+
+// GetSettings implements services.WebHookConversationService.
+func (t *webHookConversationServiceImpl) GetSettings(user *jwt.Claims) (*responsedto.WebhookSettingsResponse, error) {
+	organization, err := t.findSettingsOrganization(user)
+	if err != nil {
+		return nil, err
+	}
+	return mapToWebhookSettingsResponse(organization), nil
+}
+
+// UpdateSettings implements services.WebHookConversationService.
+func (t *webHookConversationServiceImpl) UpdateSettings(user *jwt.Claims, req requestdto.UpdateWebhookSettingsRequest) (*responsedto.WebhookSettingsResponse, error) {
+	hosts := make([]string, 0, len(req.CallbackHosts))
+	for _, raw := range req.CallbackHosts {
+		host := strings.ToLower(strings.TrimSpace(raw))
+		if !webhookHostPattern.MatchString(host) {
+			return nil, ErrWebhookCallbackHostInvalid
+		}
+		hosts = append(hosts, host)
+	}
+
+	organization, err := t.findSettingsOrganization(user)
+	if err != nil {
+		return nil, err
+	}
+
+	organization.WebhookCallbackHosts = hosts
+	if req.SignatureRequired != nil {
+		if *req.SignatureRequired && organization.WebhookSecret == nil {
+			return nil, ErrWebhookSecretNotConfigured
+		}
+		organization.WebhookSignatureRequired = *req.SignatureRequired
+	}
+	if err := t.db.Model(organization).
+		Select("webhook_callback_hosts", "webhook_signature_required").
+		Updates(organization).Error; err != nil {
+		return nil, errors.New("failed to update webhook settings")
+	}
+
+	return mapToWebhookSettingsResponse(organization), nil
+}
+
+// RotateSecret implements services.WebHookConversationService.
+func (t *webHookConversationServiceImpl) RotateSecret(user *jwt.Claims) (*responsedto.WebhookSettingsResponse, error) {
+	organization, err := t.findSettingsOrganization(user)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, errors.New("failed to create webhook secret")
+	}
+	secret := "whsec_" + hex.EncodeToString(raw)
+
+	organization.WebhookSecret = &secret
+	if err := t.db.Model(organization).
+		Select("webhook_secret").
+		Updates(organization).Error; err != nil {
+		return nil, errors.New("failed to rotate webhook secret")
+	}
+
+	response := mapToWebhookSettingsResponse(organization)
+	response.Secret = secret
+	return response, nil
+}
+
+func (t *webHookConversationServiceImpl) findSettingsOrganization(user *jwt.Claims) (*models.OrganizationModel, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	var organization models.OrganizationModel
+	if err := t.db.First(&organization, *user.OrganizationId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, errors.New("failed to fetch organization")
+	}
+	return &organization, nil
+}
+
+func mapToWebhookSettingsResponse(organization *models.OrganizationModel) *responsedto.WebhookSettingsResponse {
+	response := &responsedto.WebhookSettingsResponse{
+		OrganizationID:    organization.ID,
+		SecretConfigured:  organization.WebhookSecret != nil,
+		SignatureRequired: organization.WebhookSignatureRequired,
+		CallbackHosts:     organization.WebhookCallbackHosts,
+	}
+	if response.CallbackHosts == nil {
+		response.CallbackHosts = []string{}
+	}
+	return response
+}
+
+// webhookCallbackAllowed reports whether staff replies may be posted to
+// raw. The url must be https and its host one of the organization's
+// callback hosts, where *.example.com allows every subdomain.
+func webhookCallbackAllowed(hosts []string, raw string) bool {
+	parsed, err := outbound.CheckCallbackURL(raw)
+	if err != nil {
+		return false
+	}
+
+	hostname := strings.ToLower(parsed.Hostname())
+	for _, host := range hosts {
+		if suffix, wildcard := strings.CutPrefix(host, "*"); wildcard {
+			if strings.HasSuffix(hostname, suffix) {
+				return true
+			}
+			continue
+		}
+		if hostname == host {
+			return true
+		}
+	}
+	return false
+}
+
+// organizationCallbackHosts reads the callback hosts of the organization
+// as they are now, the list can change after a conversation stored its url.
+func organizationCallbackHosts(db *gorm.DB, organizationID uint) ([]string, error) {
+	var organization models.OrganizationModel
+	if err := db.Select("id", "webhook_callback_hosts").First(&organization, organizationID).Error; err != nil {
+		return nil, errors.New("failed to fetch organization")
+	}
+	return organization.WebhookCallbackHosts, nil
+}
@@ -0,0 +1,502 @@
+package impl
+
+import (
+	"DewaSRY/sociomile-app/internal/services"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/models"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrWidgetNotFound             = errors.New("widget not found")
+	ErrWidgetSessionInvalid       = errors.New("widget session is invalid or expired")
+	ErrWidgetConversationNotFound = errors.New("conversation not found")
+	ErrWidgetSlugInvalid          = errors.New("slug must be lowercase letters, digits and dashes")
+	ErrWidgetSlugTaken            = errors.New("slug is already used by another organization")
+	ErrWidgetOriginInvalid        = errors.New("allowed origins must be http or https origins without a path")
+)
+
+var widgetSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type widgetServiceImpl struct {
+	db         *gorm.DB
+	sessionTTL time.Duration
+}
+
+// AllowedOrigins implements services.WidgetService.
+func (t *widgetServiceImpl) AllowedOrigins(slug string) ([]string, error) {
+	organization, err := t.findOrganization(slug)
+	if err != nil {
+		return nil, err
+	}
+	return organization.WidgetAllowedOrigins, nil
+}
+
+// StartSession implements services.WidgetService. A visitor who still holds
+// a valid token of the organization keeps their contact, the session is
+// only extended.
+func (t *widgetServiceImpl) StartSession(slug string, currentToken string, req requestdto.StartWidgetSessionRequest) (*responsedto.WidgetSessionResponse, error) {
+	organization, err := t.findOrganization(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if currentToken != "" {
+		if session, err := t.Authenticate(slug, currentToken); err == nil {
+			expiresAt := time.Now().Add(t.sessionTTL)
+			if err := t.db.Model(&models.WidgetSessionModel{}).
+				Where("id = ?", session.ID).
+				Update("expires_at", expiresAt).Error; err != nil {
+				return nil, errors.New("failed to extend widget session")
+			}
+			session.ExpiresAt = expiresAt
+
+			if req.Email != nil {
+				return t.Identify(session, currentToken, requestdto.IdentifyWidgetVisitorRequest{Email: *req.Email, Name: req.Name})
+			}
+			return t.mapToSessionResponse(t.db, session, currentToken, organization)
+		}
+	}
+
+	token, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+
+	visitorID := make([]byte, 16)
+	if _, err := rand.Read(visitorID); err != nil {
+		return nil, errors.New("failed to create widget visitor")
+	}
+
+	var session models.WidgetSessionModel
+	err = t.db.Transaction(func(tx *gorm.DB) error {
+		name := ""
+		if req.Name != nil {
+			name = strings.TrimSpace(*req.Name)
+		}
+
+		contact, err := resolveContact(tx, organization.ID, name, nil, []contactIdentity{
+			{Type: models.ContactIdentityTypeWidget, Value: hex.EncodeToString(visitorID)},
+		})
+		if err != nil {
+			return err
+		}
+
+		session = models.WidgetSessionModel{
+			OrganizationID: organization.ID,
+			ContactID:      contact.ID,
+			TokenHash:      hashSecretToken(token),
+			ExpiresAt:      time.Now().Add(t.sessionTTL),
+		}
+		if err := tx.Create(&session).Error; err != nil {
+			return errors.New("failed to create widget session")
+		}
+
+		if req.Email != nil {
+			return t.identifyContact(tx, organization.ID, contact.ID, *req.Email, nil)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t.mapToSessionResponse(t.db, &session, token, organization)
+}
+
+// Authenticate implements services.WidgetService. Nothing proves who a
+// widget visitor is, so the session does not follow a merge of its contact:
+// it stops working until the merge is undone and the visitor starts over
+// with a contact of their own.
+func (t *widgetServiceImpl) Authenticate(slug string, token string) (*models.WidgetSessionModel, error) {
+	var session models.WidgetSessionModel
+	if err := t.db.Joins("JOIN organizations ON organizations.id = widget_sessions.organization_id AND organizations.deleted_at IS NULL").
+		Where("organizations.slug = ?", slug).
+		Where("widget_sessions.token_hash = ?", hashSecretToken(token)).
+		First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWidgetSessionInvalid
+		}
+		return nil, errors.New("failed to fetch widget session")
+	}
+
+	if !time.Now().Before(session.ExpiresAt) {
+		return nil, ErrWidgetSessionInvalid
+	}
+
+	var contact models.ContactModel
+	if err := t.db.Select("id").First(&contact, session.ContactID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWidgetSessionInvalid
+		}
+		return nil, errors.New("failed to fetch contact")
+	}
+
+	return &session, nil
+}
+
+// Identify implements services.WidgetService.
+func (t *widgetServiceImpl) Identify(session *models.WidgetSessionModel, token string, req requestdto.IdentifyWidgetVisitorRequest) (*responsedto.WidgetSessionResponse, error) {
+	var organization models.OrganizationModel
+	if err := t.db.First(&organization, session.OrganizationID).Error; err != nil {
+		return nil, ErrWidgetNotFound
+	}
+
+	if err := t.db.Transaction(func(tx *gorm.DB) error {
+		return t.identifyContact(tx, session.OrganizationID, session.ContactID, req.Email, req.Name)
+	}); err != nil {
+		return nil, err
+	}
+
+	return t.mapToSessionResponse(t.db, session, token, &organization)
+}
+
+// identifyContact stores the email the visitor gave as a claimed email
+// with the email match key. Anyone can type an address into the widget, so
+// it never becomes an email identity: mail and webhook messages from the
+// address do not end up in the visitor's contact, and the staff decide on
+// the merge suggestion.
+func (t *widgetServiceImpl) identifyContact(tx *gorm.DB, organizationID uint, contactID uint, email string, name *string) error {
+	identity := newContactIdentity(models.ContactIdentityTypeEmail, email)
+
+	if name != nil && strings.TrimSpace(*name) != "" {
+		if err := tx.Model(&models.ContactModel{}).
+			Where("id = ?", contactID).
+			Update("name", strings.TrimSpace(*name)).Error; err != nil {
+			return errors.New("failed to update contact")
+		}
+	}
+
+	claimed := models.ContactIdentityModel{
+		OrganizationID: organizationID,
+		ContactID:      contactID,
+		Type:           models.ContactIdentityTypeClaimedEmail,
+		Value:          identity.Value,
+		MatchKey:       identity.matchKey(),
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&claimed).Error; err != nil {
+		return errors.New("failed to store contact identity")
+	}
+	return nil
+}
+
+// GetConversations implements services.WidgetService.
+func (t *widgetServiceImpl) GetConversations(session *models.WidgetSessionModel, filter filtersdto.FiltersDto) (*responsedto.WidgetConversationPaginateResponse, error) {
+	var conversations []models.ConversationModel
+	var total int64
+	offset := (*filter.Page - 1) * *filter.Limit
+
+	query := t.db.Model(&models.ConversationModel{}).
+		Where("organization_id = ? AND contact_id = ?", session.OrganizationID, session.ContactID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, errors.New("failed to count conversations")
+	}
+
+	if err := query.Offset(offset).Limit(*filter.Limit).
+		Order("created_at DESC").
+		Find(&conversations).Error; err != nil {
+		return nil, errors.New("failed to fetch conversations")
+	}
+
+	data := make([]responsedto.WidgetConversationResponse, 0, len(conversations))
+	for i := range conversations {
+		data = append(data, *t.mapToConversationResponse(&conversations[i]))
+	}
+
+	return &responsedto.WidgetConversationPaginateResponse{
+		Data: data,
+		Metadata: responsedto.PaginateMetaData{
+			Total: int(total),
+			Page:  *filter.Page,
+			Limit: *filter.Limit,
+		},
+	}, nil
+}
+
+// CreateConversation implements services.WidgetService.
+func (t *widgetServiceImpl) CreateConversation(session *models.WidgetSessionModel, req requestdto.CreateWidgetConversationRequest) (*responsedto.WidgetConversationResponse, error) {
+	conversation := models.ConversationModel{
+		OrganizationID: session.OrganizationID,
+		ContactID:      session.ContactID,
+		Status:         models.ConversationStatusPending,
+		Channel:        models.ConversationChannelWidget,
+		Subject:        req.Subject,
+	}
+
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&conversation).Error; err != nil {
+			return errors.New("failed to create conversation")
+		}
+		if err := publishEvent(tx, conversation.OrganizationID, models.EventConversationCreated, conversationEvent(&conversation, nil)); err != nil {
+			return err
+		}
+
+		message := models.ConversationMessageModel{
+			OrganizationID: conversation.OrganizationID,
+			ConversationID: conversation.ID,
+			ContactID:      &conversation.ContactID,
+			Message:        req.Message,
+		}
+		if err := tx.Create(&message).Error; err != nil {
+			return errors.New("failed to create message")
+		}
+		return publishEvent(tx, message.OrganizationID, models.EventMessageCreated, messageEvent(&message))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t.mapToConversationResponse(&conversation), nil
+}
+
+// GetMessages implements services.WidgetService.
+func (t *widgetServiceImpl) GetMessages(session *models.WidgetSessionModel, conversationID uint, filter filtersdto.FiltersDto) (*responsedto.WidgetMessagePaginateResponse, error) {
+	conversation, err := t.findConversation(session, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []models.ConversationMessageModel
+	var total int64
+	offset := (*filter.Page - 1) * *filter.Limit
+
+	query := t.db.Model(&models.ConversationMessageModel{}).
+		Where("conversation_id = ?", conversation.ID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, errors.New("failed to count messages")
+	}
+
+	if err := query.Offset(offset).Limit(*filter.Limit).
+		Preload("CreatedBy").
+		Order("created_at ASC, id ASC").
+		Find(&messages).Error; err != nil {
+		return nil, errors.New("failed to fetch messages")
+	}
+
+	data := make([]responsedto.WidgetMessageResponse, 0, len(messages))
+	for i := range messages {
+		data = append(data, *t.mapToMessageResponse(&messages[i]))
+	}
+
+	return &responsedto.WidgetMessagePaginateResponse{
+		Data: data,
+		Metadata: responsedto.PaginateMetaData{
+			Total: int(total),
+			Page:  *filter.Page,
+			Limit: *filter.Limit,
+		},
+	}, nil
+}
+
+// SendMessage implements services.WidgetService.
+func (t *widgetServiceImpl) SendMessage(session *models.WidgetSessionModel, conversationID uint, req requestdto.WidgetMessageRequest) (*responsedto.WidgetMessageResponse, error) {
+	conversation, err := t.findConversation(session, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	message := models.ConversationMessageModel{
+		OrganizationID: conversation.OrganizationID,
+		ConversationID: conversation.ID,
+		ContactID:      &conversation.ContactID,
+		Message:        req.Message,
+	}
+
+	err = t.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&message).Error; err != nil {
+			return errors.New("failed to create message")
+		}
+		return publishEvent(tx, message.OrganizationID, models.EventMessageCreated, messageEvent(&message))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t.mapToMessageResponse(&message), nil
+}
+
+// GetSettings implements services.WidgetService.
+func (t *widgetServiceImpl) GetSettings(user *jwt.Claims) (*responsedto.WidgetSettingsResponse, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	var organization models.OrganizationModel
+	if err := t.db.First(&organization, *user.OrganizationId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, errors.New("failed to fetch organization")
+	}
+
+	return t.mapToSettingsResponse(&organization), nil
+}
+
+// UpdateSettings implements services.WidgetService.
+func (t *widgetServiceImpl) UpdateSettings(user *jwt.Claims, req requestdto.UpdateWidgetSettingsRequest) (*responsedto.WidgetSettingsResponse, error) {
+	if user.OrganizationId == nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if !widgetSlugPattern.MatchString(slug) {
+		return nil, ErrWidgetSlugInvalid
+	}
+
+	origins := make([]string, 0, len(req.AllowedOrigins))
+	for _, raw := range req.AllowedOrigins {
+		origin, err := normalizeWidgetOrigin(raw)
+		if err != nil {
+			return nil, err
+		}
+		origins = append(origins, origin)
+	}
+
+	var organization models.OrganizationModel
+	if err := t.db.First(&organization, *user.OrganizationId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, errors.New("failed to fetch organization")
+	}
+
+	var taken int64
+	if err := t.db.Model(&models.OrganizationModel{}).
+		Unscoped().
+		Where("slug = ? AND id <> ?", slug, organization.ID).
+		Count(&taken).Error; err != nil {
+		return nil, errors.New("failed to check slug")
+	}
+	if taken > 0 {
+		return nil, ErrWidgetSlugTaken
+	}
+
+	organization.Slug = &slug
+	organization.WidgetAllowedOrigins = origins
+	if err := t.db.Model(&organization).
+		Select("slug", "widget_allowed_origins").
+		Updates(&organization).Error; err != nil {
+		return nil, errors.New("failed to update widget settings")
+	}
+
+	return t.mapToSettingsResponse(&organization), nil
+}
+
+func (t *widgetServiceImpl) findOrganization(slug string) (*models.OrganizationModel, error) {
+	var organization models.OrganizationModel
+	if err := t.db.Where("slug = ?", slug).First(&organization).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWidgetNotFound
+		}
+		return nil, errors.New("failed to fetch organization")
+	}
+	return &organization, nil
+}
+
+func (t *widgetServiceImpl) findConversation(session *models.WidgetSessionModel, conversationID uint) (*models.ConversationModel, error) {
+	var conversation models.ConversationModel
+	if err := t.db.Where("organization_id = ? AND contact_id = ?", session.OrganizationID, session.ContactID).
+		First(&conversation, conversationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWidgetConversationNotFound
+		}
+		return nil, errors.New("failed to fetch conversation")
+	}
+	return &conversation, nil
+}
+
+func (t *widgetServiceImpl) mapToSessionResponse(tx *gorm.DB, session *models.WidgetSessionModel, token string, organization *models.OrganizationModel) (*responsedto.WidgetSessionResponse, error) {
+	var contact models.ContactModel
+	if err := tx.Preload("Identities").First(&contact, session.ContactID).Error; err != nil {
+		return nil, errors.New("failed to fetch contact")
+	}
+
+	response := &responsedto.WidgetSessionResponse{
+		Token:      token,
+		ExpiresAt:  session.ExpiresAt,
+		ContactID:  contact.ID,
+		Name:       contact.Name,
+		Identified: contactIdentityValue(&contact, models.ContactIdentityTypeClaimedEmail) != nil,
+		Organization: responsedto.WidgetOrganizationData{
+			ID:   organization.ID,
+			Name: organization.Name,
+		},
+	}
+	if organization.Slug != nil {
+		response.Organization.Slug = *organization.Slug
+	}
+	return response, nil
+}
+
+func (t *widgetServiceImpl) mapToConversationResponse(conversation *models.ConversationModel) *responsedto.WidgetConversationResponse {
+	return &responsedto.WidgetConversationResponse{
+		ID:        conversation.ID,
+		Status:    conversation.Status,
+		Subject:   conversation.Subject,
+		CreatedAt: conversation.CreatedAt,
+		UpdatedAt: conversation.UpdatedAt,
+	}
+}
+
+func (t *widgetServiceImpl) mapToMessageResponse(message *models.ConversationMessageModel) *responsedto.WidgetMessageResponse {
+	response := &responsedto.WidgetMessageResponse{
+		ID:             message.ID,
+		ConversationID: message.ConversationID,
+		Message:        message.Message,
+		FromVisitor:    message.ContactID != nil,
+		CreatedAt:      message.CreatedAt,
+	}
+	if message.CreatedBy != nil && message.ContactID == nil {
+		response.AuthorName = &message.CreatedBy.Name
+	}
+	return response
+}
+
+func (t *widgetServiceImpl) mapToSettingsResponse(organization *models.OrganizationModel) *responsedto.WidgetSettingsResponse {
+	response := &responsedto.WidgetSettingsResponse{
+		OrganizationID: organization.ID,
+		AllowedOrigins: organization.WidgetAllowedOrigins,
+	}
+	if organization.Slug != nil {
+		response.Slug = *organization.Slug
+	}
+	if response.AllowedOrigins == nil {
+		response.AllowedOrigins = []string{}
+	}
+	return response
+}
+
+// normalizeWidgetOrigin reduces an allowed origin to scheme://host[:port]
+// the way browsers send it in the Origin header. A leading *. in the host
+// allows every subdomain.
+func normalizeWidgetOrigin(raw string) (string, error) {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", ErrWidgetOriginInvalid
+	}
+	if (parsed.Path != "" && parsed.Path != "/") || parsed.RawQuery != "" || parsed.User != nil {
+		return "", ErrWidgetOriginInvalid
+	}
+	return strings.ToLower(parsed.Scheme + "://" + parsed.Host), nil
+}
+
+func NewWidgetService(db *gorm.DB, sessionTTL time.Duration) services.WidgetService {
+	return &widgetServiceImpl{
+		db:         db,
+		sessionTTL: sessionTTL,
+	}
+}
@@ -0,0 +1,11 @@
+package services
+
+import (
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+)
+
+type MagicLinkService interface {
+	RequestLink(req requestdto.MagicLinkRequest) error
+	VerifyLink(req requestdto.VerifyMagicLinkRequest) (*responsedto.AuthResponse, error)
+}
@@ -8,10 +8,12 @@ import (
 )
 
 type OrganizationConversationService interface{
-	GetConversationsList(user *jwt.Claims, filter filtersdto.FiltersDto) (*responsedto.ConversationListResponse, error)
+	GetConversationsList(user *jwt.Claims, filter filtersdto.FiltersDto, conversationFilter filtersdto.ConversationFiltersDto) (*responsedto.ConversationListResponse, error)
 
-	GetConversationByID(id uint) (*responsedto.ConversationResponse, error)
-	AssignConversation(conversationID uint, req requestdto.AssignConversationRequest) (*responsedto.ConversationResponse, error)
-	UpdateConversationStatus(conversationID uint, req requestdto.UpdateConversationRequest) ( error)
+	GetConversationByID(user *jwt.Claims, id uint) (*responsedto.ConversationResponse, error)
+	AssignConversation(user *jwt.Claims, conversationID uint, req requestdto.AssignConversationRequest) (*responsedto.ConversationResponse, error)
+	UpdateConversationStatus(user *jwt.Claims, conversationID uint, req requestdto.UpdateConversationRequest) ( error)
+	SendMessage(user *jwt.Claims, conversationID uint, req requestdto.CreateStaffMessageRequest) error
+	GetAttachment(user *jwt.Claims, conversationID uint, attachmentID uint) (*responsedto.AttachmentFileResponse, error)
 }
 
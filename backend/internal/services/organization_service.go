@@ -11,4 +11,6 @@ import (
 type OrganizationService interface{
 	CreateStaff(requestdto.RegisterRequest, *jwtLib.Claims) error
 	GetStaffList(filtersdto.FiltersDto, *jwtLib.Claims) (*responsedto.OrganizationStaffPagination, error)
+	GetSettings(*jwtLib.Claims) (*responsedto.OrganizationSettingsResponse, error)
+	UpdateSettings(*jwtLib.Claims, requestdto.UpdateOrganizationSettingsRequest) (*responsedto.OrganizationSettingsResponse, error)
 }
\ No newline at end of file
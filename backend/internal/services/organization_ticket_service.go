@@ -5,10 +5,29 @@ import (
 	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
 	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
 	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"io"
 )
 
 type OrganizationTicketService interface {
 	CreateTicket(user *jwtLib.Claims, req requestdto.CreateTicketRequest)error
-	GetTicketsList(user *jwtLib.Claims,filter filtersdto.FiltersDto) (*responsedto.TicketListResponse, error)
+	GetTicketsList(user *jwtLib.Claims,filter filtersdto.FiltersDto, ticketFilter filtersdto.TicketFiltersDto) (*responsedto.TicketListResponse, error)
 	UpdateTicket(user *jwtLib.Claims,ticketID uint, req requestdto.UpdateTicketRequest)error
+	GetTicket(user *jwtLib.Claims, ticketID uint) (*responsedto.TicketDetailResponse, error)
+
+	ExportTickets(user *jwtLib.Claims, ticketFilter filtersdto.TicketFiltersDto, w io.Writer) error
+	ImportTickets(user *jwtLib.Claims, r io.Reader, dryRun bool) (*responsedto.TicketImportResponse, error)
+
+	GetComments(user *jwtLib.Claims, ticketID uint, filter filtersdto.FiltersDto) (*responsedto.TicketCommentPaginateResponse, error)
+	CreateComment(user *jwtLib.Claims, ticketID uint, req requestdto.CreateTicketCommentRequest) (*responsedto.TicketCommentResponse, error)
+
+	CreateLink(user *jwtLib.Claims, ticketID uint, req requestdto.CreateTicketLinkRequest) (*responsedto.TicketLinkResponse, error)
+	DeleteLink(user *jwtLib.Claims, ticketID uint, linkID uint) error
+	AddConversation(user *jwtLib.Claims, ticketID uint, req requestdto.AddTicketConversationRequest) (*responsedto.ConversationResponse, error)
+	RemoveConversation(user *jwtLib.Claims, ticketID uint, conversationID uint) error
+
+	GetNumberFormat(user *jwtLib.Claims) (*responsedto.TicketNumberFormatResponse, error)
+	UpdateNumberFormat(user *jwtLib.Claims, req requestdto.UpdateTicketNumberFormatRequest) (*responsedto.TicketNumberFormatResponse, error)
+
+	GetWorkflow(user *jwtLib.Claims) (*responsedto.TicketWorkflowResponse, error)
+	UpdateWorkflow(user *jwtLib.Claims, req requestdto.UpdateTicketWorkflowRequest) (*responsedto.TicketWorkflowResponse, error)
 }
@@ -0,0 +1,12 @@
+package services
+
+import (
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/lib/jwt"
+)
+
+type OutboundDeliveryService interface {
+	ProcessDueDeliveries(limit int) (int, error)
+	HandleReceipt(req requestdto.DeliveryReceiptRequest) error
+	RetryDelivery(user *jwt.Claims, conversationID uint, messageID uint) error
+}
@@ -0,0 +1,19 @@
+package services
+
+import (
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/ratelimit"
+)
+
+type RateLimitService interface {
+	Allow(subject ratelimit.Subject) (ratelimit.Decision, string)
+	GuestConversationOrganizationID(user *jwt.Claims, conversationID uint) (uint, error)
+	FlushHits() error
+
+	GetHits(filter filtersdto.FiltersDto, organizationID *uint) (*responsedto.RateLimitHitPaginateResponse, error)
+	GetOrganizationLimits(organizationID uint) (*responsedto.OrganizationRateLimitResponse, error)
+	UpdateOrganizationLimits(organizationID uint, req requestdto.UpdateOrganizationRateLimitRequest) (*responsedto.OrganizationRateLimitResponse, error)
+}
@@ -0,0 +1,200 @@
+package tests
+
+import (
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+var testBulkOptions = impl.BulkOptions{SyncLimit: 10, ChunkSize: 2, MaxItems: 50}
+
+func TestBulkService_Tickets(t *testing.T) {
+	tx := SetupTestDB(t)
+	ticketService := impl.NewTicketService(tx)
+	service := impl.NewBulkService(tx, testBulkOptions)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	otherOrg, otherOwner := CreateTestOrganizationWithOwner(tx, t, "Other Org")
+	sales := createTicketStaff(tx, t, org.ID, "sales@test.com")
+	conv := createTicketConversation(tx, t, org.ID)
+	otherConv := createTicketConversation(tx, t, otherOrg.ID)
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+	otherClaims := &jwtLib.Claims{UserID: otherOwner.ID, RoleID: otherOwner.RoleID, OrganizationId: &otherOrg.ID}
+
+	for i := 0; i < 3; i++ {
+		if err := ticketService.CreateTicket(claims, requestdto.CreateTicketRequest{ConversationID: conv.ID, Name: fmt.Sprintf("Ticket %d", i)}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if err := ticketService.CreateTicket(otherClaims, requestdto.CreateTicketRequest{ConversationID: otherConv.ID, Name: "Other ticket"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var ids []uint
+	tx.Model(&models.TicketModel{}).Where("organization_id = ?", org.ID).Order("id").Pluck("id", &ids)
+	var otherTicket models.TicketModel
+	tx.Where("organization_id = ?", otherOrg.ID).First(&otherTicket)
+
+	if _, err := service.BulkTickets(claims, requestdto.BulkTicketRequest{Action: models.BulkActionStatus, Status: "in_progress"}); !errors.Is(err, impl.ErrBulkRequestInvalid) {
+		t.Errorf("expected ErrBulkRequestInvalid without ids or filter, got %v", err)
+	}
+
+	job, err := service.BulkTickets(claims, requestdto.BulkTicketRequest{
+		IDs:    append(ids, otherTicket.ID),
+		Action: models.BulkActionAssign, AssigneeID: &sales.ID,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if job.Status != models.BulkJobCompleted || job.Succeeded != 3 || job.Failed != 1 {
+		t.Fatalf("expected 3 succeeded and 1 failed, got %+v", job)
+	}
+	if failed := job.Results[3]; failed.ID != otherTicket.ID || failed.Success {
+		t.Errorf("expected the ticket of the other organization to fail, got %+v", failed)
+	}
+	tx.First(&otherTicket, otherTicket.ID)
+	if otherTicket.AssigneeID != nil {
+		t.Errorf("expected the ticket of the other organization to stay unassigned")
+	}
+
+	if _, err := service.BulkTickets(claims, requestdto.BulkTicketRequest{
+		IDs: ids[:2], Action: models.BulkActionAddTag, Tag: " VIP ",
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	page, limit := 1, 10
+	list, err := ticketService.GetTicketsList(claims, filtersdto.FiltersDto{Page: &page, Limit: &limit}, filtersdto.TicketFiltersDto{Tag: "vip"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(list.Tickets) != 2 || len(list.Tickets[0].Tags) != 1 || list.Tickets[0].Tags[0] != "vip" {
+		t.Errorf("expected 2 tickets tagged vip, got %+v", list.Tickets)
+	}
+
+	job, err = service.BulkTickets(claims, requestdto.BulkTicketRequest{
+		Filter: &filtersdto.TicketFiltersDto{Tag: "vip"},
+		Action: models.BulkActionClose,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if job.Total != 2 || job.Succeeded != 2 {
+		t.Errorf("expected the 2 tagged tickets to close, got %+v", job)
+	}
+	var open int64
+	tx.Model(&models.TicketModel{}).Where("organization_id = ? AND status_category = ?", org.ID, models.TicketStatusCategoryOpen).Count(&open)
+	if open != 1 {
+		t.Errorf("expected 1 open ticket left, got %d", open)
+	}
+
+	if _, err := service.GetJob(otherClaims, job.ID); !errors.Is(err, impl.ErrBulkJobNotFound) {
+		t.Errorf("expected ErrBulkJobNotFound, got %v", err)
+	}
+}
+
+func TestBulkService_BackgroundJob(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewBulkService(tx, impl.BulkOptions{SyncLimit: 2, ChunkSize: 2, MaxItems: 4})
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+
+	var ids []uint
+	for i := 0; i < 5; i++ {
+		ids = append(ids, createTicketConversation(tx, t, org.ID).ID)
+	}
+
+	if _, err := service.BulkConversations(claims, requestdto.BulkConversationRequest{
+		IDs: ids, Action: models.BulkActionClose,
+	}); !errors.Is(err, impl.ErrBulkTooManyItems) {
+		t.Errorf("expected ErrBulkTooManyItems, got %v", err)
+	}
+
+	job, err := service.BulkConversations(claims, requestdto.BulkConversationRequest{
+		IDs: ids[:3], Action: models.BulkActionClose,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if job.Status != models.BulkJobPending {
+		t.Fatalf("expected the job to be queued, got %s", job.Status)
+	}
+
+	processed, err := service.ProcessPendingJobs(5)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if processed != 1 {
+		t.Errorf("expected 1 processed job, got %d", processed)
+	}
+
+	job, err = service.GetJob(claims, job.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if job.Status != models.BulkJobCompleted || job.Processed != 3 || job.Succeeded != 3 {
+		t.Errorf("expected 3 closed conversations, got %+v", job)
+	}
+
+	var done int64
+	tx.Model(&models.ConversationModel{}).Where("id IN ? AND status = ?", ids[:3], models.ConversationStatusDone).Count(&done)
+	if done != 3 {
+		t.Errorf("expected 3 done conversations, got %d", done)
+	}
+}
+
+func TestBulkService_ReclaimStaleHeartbeat(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewBulkService(tx, impl.BulkOptions{SyncLimit: 0, ChunkSize: 2, MaxItems: 4})
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	ids := []uint{createTicketConversation(tx, t, org.ID).ID, createTicketConversation(tx, t, org.ID).ID}
+
+	// a job that started long ago but still beats is left to its worker,
+	// one whose heartbeat stopped is taken over
+	longAgo := time.Now().Add(-time.Hour)
+	recent := time.Now().Add(-time.Minute)
+	newJob := func(conversationID uint, startedAt time.Time, heartbeatAt time.Time) models.BulkJobModel {
+		job := models.BulkJobModel{
+			OrganizationID: org.ID,
+			CreatedByID:    owner.ID,
+			SubjectType:    models.TagSubjectConversation,
+			Action:         models.BulkActionClose,
+			ItemIDs:        []uint{conversationID},
+			Status:         models.BulkJobRunning,
+			Total:          1,
+			Results:        []models.BulkJobResult{},
+			StartedAt:      &startedAt,
+			HeartbeatAt:    &heartbeatAt,
+		}
+		if err := tx.Create(&job).Error; err != nil {
+			t.Fatalf("failed to create bulk job: %v", err)
+		}
+		return job
+	}
+	busy := newJob(ids[0], longAgo, recent)
+	stalled := newJob(ids[1], recent, longAgo)
+
+	processed, err := service.ProcessPendingJobs(5)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if processed != 1 {
+		t.Errorf("expected 1 processed job, got %d", processed)
+	}
+
+	tx.First(&busy, busy.ID)
+	tx.First(&stalled, stalled.ID)
+	if busy.Status != models.BulkJobRunning || busy.Processed != 0 {
+		t.Errorf("expected the beating job to be left alone, got %+v", busy)
+	}
+	if stalled.Status != models.BulkJobCompleted || stalled.Processed != 1 || stalled.HeartbeatAt == nil || !stalled.HeartbeatAt.After(longAgo) {
+		t.Errorf("expected the stalled job to be taken over, got %+v", stalled)
+	}
+}
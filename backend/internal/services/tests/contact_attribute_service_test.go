@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"testing"
+)
+
+func TestContactAttributeService_CreateAttribute(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewContactAttributeService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+	claims := &jwtLib.Claims{UserID: owner.ID, OrganizationId: &org.ID}
+
+	_, err := service.CreateAttribute(claims, requestdto.CreateContactAttributeRequest{
+		Key: "Order ID", Label: "Order ID", Type: models.ContactAttributeTypeString,
+	})
+	if !errors.Is(err, impl.ErrContactAttributeKeyInvalid) {
+		t.Errorf("expected ErrContactAttributeKeyInvalid, got %v", err)
+	}
+
+	_, err = service.CreateAttribute(claims, requestdto.CreateContactAttributeRequest{
+		Key: "tier", Label: "Tier", Type: models.ContactAttributeTypeEnum,
+	})
+	if !errors.Is(err, impl.ErrContactAttributeNoOptions) {
+		t.Errorf("expected ErrContactAttributeNoOptions, got %v", err)
+	}
+
+	attribute, err := service.CreateAttribute(claims, requestdto.CreateContactAttributeRequest{
+		Key: "tier", Label: "Tier", Type: models.ContactAttributeTypeEnum,
+		Options: []string{" gold ", "silver", "Gold", ""},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(attribute.Options) != 2 || attribute.Options[0] != "gold" {
+		t.Errorf("expected trimmed unique options, got %v", attribute.Options)
+	}
+
+	_, err = service.CreateAttribute(claims, requestdto.CreateContactAttributeRequest{
+		Key: "tier", Label: "Tier again", Type: models.ContactAttributeTypeString,
+	})
+	if !errors.Is(err, impl.ErrContactAttributeKeyTaken) {
+		t.Errorf("expected ErrContactAttributeKeyTaken, got %v", err)
+	}
+}
+
+func TestContactAttributeService_SetAttributes_Validates(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewContactAttributeService(tx)
+	contactService := impl.NewContactService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+	claims := &jwtLib.Claims{UserID: owner.ID, OrganizationId: &org.ID}
+
+	definitions := []requestdto.CreateContactAttributeRequest{
+		{Key: "tier", Label: "Tier", Type: models.ContactAttributeTypeEnum, Options: []string{"gold", "silver"}},
+		{Key: "orders", Label: "Orders", Type: models.ContactAttributeTypeNumber},
+		{Key: "customer_since", Label: "Customer since", Type: models.ContactAttributeTypeDate},
+		{Key: "vip", Label: "VIP", Type: models.ContactAttributeTypeBoolean},
+	}
+	for _, definition := range definitions {
+		if _, err := service.CreateAttribute(claims, definition); err != nil {
+			t.Fatalf("failed to create attribute: %v", err)
+		}
+	}
+
+	contact, _ := contactService.CreateContact(claims, requestdto.CreateContactRequest{Name: "Jane"})
+
+	updated, err := contactService.SetAttributes(claims, contact.ID, requestdto.SetContactAttributesRequest{
+		Attributes: map[string]any{
+			"tier":           "Gold",
+			"orders":         "12",
+			"customer_since": "2024-05-01T10:00:00Z",
+			"vip":            true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Attributes["tier"] != "gold" || updated.Attributes["orders"] != float64(12) ||
+		updated.Attributes["customer_since"] != "2024-05-01" || updated.Attributes["vip"] != true {
+		t.Errorf("expected normalized attributes, got %+v", updated.Attributes)
+	}
+
+	for _, attributes := range []map[string]any{
+		{"tier": "platinum"},
+		{"orders": "many"},
+		{"customer_since": "yesterday"},
+		{"city": "Jakarta"},
+	} {
+		_, err := contactService.SetAttributes(claims, contact.ID, requestdto.SetContactAttributesRequest{Attributes: attributes})
+		if !errors.Is(err, impl.ErrContactAttributeInvalid) && !errors.Is(err, impl.ErrContactAttributeUnknown) {
+			t.Errorf("expected %v to be rejected, got %v", attributes, err)
+		}
+	}
+
+	// an option in use cannot be removed
+	var tier models.ContactAttributeDefinitionModel
+	tx.Where("organization_id = ? AND `key` = ?", org.ID, "tier").First(&tier)
+	_, err = service.UpdateAttribute(claims, tier.ID, requestdto.UpdateContactAttributeRequest{Options: []string{"silver"}})
+	if !errors.Is(err, impl.ErrContactAttributeOptionInUse) {
+		t.Errorf("expected ErrContactAttributeOptionInUse, got %v", err)
+	}
+
+	updated, _ = contactService.SetAttributes(claims, contact.ID, requestdto.SetContactAttributesRequest{
+		Attributes: map[string]any{"vip": nil},
+	})
+	if _, ok := updated.Attributes["vip"]; ok {
+		t.Errorf("expected vip to be removed, got %+v", updated.Attributes)
+	}
+}
+
+func TestContactService_Notes(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewContactService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+	claims := &jwtLib.Claims{UserID: owner.ID, OrganizationId: &org.ID}
+
+	contact, _ := service.CreateContact(claims, requestdto.CreateContactRequest{Name: "Jane"})
+
+	note, err := service.CreateNote(claims, contact.ID, requestdto.ContactNoteRequest{Body: " prefers email "})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if note.Body != "prefers email" || note.Author == nil || note.Author.ID != owner.ID {
+		t.Errorf("unexpected note %+v", note)
+	}
+
+	if _, err := service.UpdateNote(claims, contact.ID, note.ID, requestdto.ContactNoteRequest{Body: "prefers whatsapp"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	page, limit := 1, 10
+	notes, _ := service.GetNotes(claims, contact.ID, filtersdto.FiltersDto{Page: &page, Limit: &limit})
+	if notes.Metadata.Total != 1 || notes.Data[0].Body != "prefers whatsapp" {
+		t.Errorf("expected the updated note, got %+v", notes.Data)
+	}
+
+	other, _ := service.CreateContact(claims, requestdto.CreateContactRequest{Name: "John"})
+	if err := service.DeleteNote(claims, other.ID, note.ID); !errors.Is(err, impl.ErrContactNoteNotFound) {
+		t.Errorf("expected ErrContactNoteNotFound for another contact, got %v", err)
+	}
+	if err := service.DeleteNote(claims, contact.ID, note.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,349 @@
+package tests
+
+import (
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestContactService_CreateContact_NormalizesIdentities(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewContactService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+	claims := &jwtLib.Claims{UserID: owner.ID, OrganizationId: &org.ID}
+
+	contact, err := service.CreateContact(claims, requestdto.CreateContactRequest{
+		Name: "Jane",
+		Identities: []requestdto.ContactIdentityRequest{
+			{Type: "email", Value: " Jane@Example.com "},
+			{Type: "phone", Value: "+62 (812) 000-111"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(contact.Identities) != 2 {
+		t.Fatalf("expected two identities, got %d", len(contact.Identities))
+	}
+	if contact.Identities[0].Value != "jane@example.com" || contact.Identities[1].Value != "+62812000111" {
+		t.Errorf("expected normalized identities, got %+v", contact.Identities)
+	}
+
+	_, err = service.CreateContact(claims, requestdto.CreateContactRequest{
+		Name: "Jane again",
+		Identities: []requestdto.ContactIdentityRequest{
+			{Type: "email", Value: "jane@example.com"},
+		},
+	})
+	if !errors.Is(err, impl.ErrContactIdentityTaken) {
+		t.Errorf("expected ErrContactIdentityTaken, got %v", err)
+	}
+
+	page, limit := 1, 10
+	result, err := service.GetContacts(claims, filtersdto.FiltersDto{Page: &page, Limit: &limit}, "000-111")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Metadata.Total != 0 {
+		t.Errorf("expected the raw phone not to match, got %d", result.Metadata.Total)
+	}
+
+	result, _ = service.GetContacts(claims, filtersdto.FiltersDto{Page: &page, Limit: &limit}, "jane@")
+	if result.Metadata.Total != 1 {
+		t.Errorf("expected one contact matching the email, got %d", result.Metadata.Total)
+	}
+}
+
+func TestContactService_GetContactByID_OtherOrganization(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewContactService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+	otherOrg, otherOwner := CreateTestOrganizationWithOwner(tx, t, "Other Organization")
+
+	contact, err := service.CreateContact(&jwtLib.Claims{UserID: owner.ID, OrganizationId: &org.ID},
+		requestdto.CreateContactRequest{Name: "Jane"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, err = service.GetContactByID(&jwtLib.Claims{UserID: otherOwner.ID, OrganizationId: &otherOrg.ID}, contact.ID)
+	if !errors.Is(err, impl.ErrContactNotFound) {
+		t.Errorf("expected ErrContactNotFound, got %v", err)
+	}
+}
+
+func TestContactService_LinkUser(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewContactService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+	claims := &jwtLib.Claims{UserID: owner.ID, OrganizationId: &org.ID}
+
+	guestRole, _ := GetOrCreateRole(tx, models.RoleGuest)
+	guest := models.UserModel{Email: "guest@test.com", Name: "Guest", Password: "password", RoleID: guestRole.ID}
+	tx.Create(&guest)
+
+	contact, _ := service.CreateContact(claims, requestdto.CreateContactRequest{Name: "Jane"})
+
+	linked, err := service.LinkUser(claims, contact.ID, requestdto.LinkContactUserRequest{Email: "guest@test.com"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if linked.UserID == nil || *linked.UserID != guest.ID {
+		t.Errorf("expected contact linked to the guest, got %+v", linked.UserID)
+	}
+
+	// staff accounts can not be linked to a contact
+	_, err = service.LinkUser(claims, contact.ID, requestdto.LinkContactUserRequest{Email: owner.Email})
+	if !errors.Is(err, impl.ErrGuestUserNotFound) {
+		t.Errorf("expected ErrGuestUserNotFound, got %v", err)
+	}
+
+	if err := service.UnlinkUser(claims, contact.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	unlinked, _ := service.GetContactByID(claims, contact.ID)
+	if unlinked.UserID != nil {
+		t.Errorf("expected contact to be unlinked, got %v", *unlinked.UserID)
+	}
+}
+
+func TestContactService_GetMergeSuggestions(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewContactService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+	claims := &jwtLib.Claims{UserID: owner.ID, OrganizationId: &org.ID}
+
+	requests := []requestdto.CreateContactRequest{
+		{Name: "Jane", Identities: []requestdto.ContactIdentityRequest{
+			{Type: "email", Value: "jane+shop@example.com"},
+			{Type: "phone", Value: "+62 812-3456-7890"},
+		}},
+		{Name: "Jane WhatsApp", Identities: []requestdto.ContactIdentityRequest{
+			{Type: "whatsapp", Value: "081234567890"},
+		}},
+		{Name: "Jane Email", Identities: []requestdto.ContactIdentityRequest{
+			{Type: "email", Value: "jane@example.com"},
+		}},
+		{Name: "Someone else", Identities: []requestdto.ContactIdentityRequest{
+			{Type: "instagram", Value: "jane"},
+		}},
+	}
+	for _, req := range requests {
+		if _, err := service.CreateContact(claims, req); err != nil {
+			t.Fatalf("failed to create contact: %v", err)
+		}
+	}
+
+	page, limit := 1, 10
+	result, err := service.GetMergeSuggestions(claims, filtersdto.FiltersDto{Page: &page, Limit: &limit})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Metadata.Total != 2 || len(result.Data) != 2 {
+		t.Fatalf("expected an email and a phone suggestion, got %+v", result.Data)
+	}
+
+	reasons := map[string]int{}
+	for _, suggestion := range result.Data {
+		reasons[suggestion.Reason] = len(suggestion.Contacts)
+	}
+	if reasons["email"] != 2 || reasons["phone"] != 2 {
+		t.Errorf("expected two contacts per suggestion, got %+v", reasons)
+	}
+}
+
+func TestContactService_MergeContact_AndUndo(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewContactService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+	claims := &jwtLib.Claims{UserID: owner.ID, OrganizationId: &org.ID}
+
+	target, _ := service.CreateContact(claims, requestdto.CreateContactRequest{
+		Name:       "Jane",
+		Identities: []requestdto.ContactIdentityRequest{{Type: "email", Value: "jane@example.com"}},
+	})
+	source, _ := service.CreateContact(claims, requestdto.CreateContactRequest{
+		Name:       "Jane WhatsApp",
+		Identities: []requestdto.ContactIdentityRequest{{Type: "whatsapp", Value: "6281234567890"}},
+	})
+
+	conversation := models.ConversationModel{
+		OrganizationID: org.ID,
+		ContactID:      source.ID,
+		Status:         models.ConversationStatusPending,
+	}
+	tx.Create(&conversation)
+	message := models.ConversationMessageModel{
+		OrganizationID: org.ID,
+		ConversationID: conversation.ID,
+		ContactID:      &source.ID,
+		Message:        "hello from whatsapp",
+	}
+	tx.Create(&message)
+	note, _ := service.CreateNote(claims, source.ID, requestdto.ContactNoteRequest{Body: "asked about shipping"})
+
+	_, err := service.MergeContact(claims, target.ID, requestdto.MergeContactRequest{SourceContactID: target.ID})
+	if !errors.Is(err, impl.ErrContactMergeSelf) {
+		t.Errorf("expected ErrContactMergeSelf, got %v", err)
+	}
+
+	merge, err := service.MergeContact(claims, target.ID, requestdto.MergeContactRequest{SourceContactID: source.ID})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(merge.IdentityIDs) != 1 || len(merge.ConversationIDs) != 1 || len(merge.NoteIDs) != 1 {
+		t.Errorf("expected the moved rows in the log, got %+v", merge)
+	}
+
+	merged, _ := service.GetContactByID(claims, target.ID)
+	if len(merged.Identities) != 2 {
+		t.Errorf("expected both identities on the target, got %+v", merged.Identities)
+	}
+	if _, err := service.GetContactByID(claims, source.ID); !errors.Is(err, impl.ErrContactNotFound) {
+		t.Errorf("expected the source to be removed, got %v", err)
+	}
+	tx.First(&conversation, conversation.ID)
+	tx.First(&message, message.ID)
+	if conversation.ContactID != target.ID || message.ContactID == nil || *message.ContactID != target.ID {
+		t.Errorf("expected conversation and message on the target, got %d and %v", conversation.ContactID, message.ContactID)
+	}
+	if _, err := service.UpdateNote(claims, target.ID, note.ID, requestdto.ContactNoteRequest{Body: "asked about shipping"}); err != nil {
+		t.Errorf("expected the note on the target, got %v", err)
+	}
+
+	if _, err := service.UndoMerge(claims, merge.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	restored, err := service.GetContactByID(claims, source.ID)
+	if err != nil || len(restored.Identities) != 1 {
+		t.Fatalf("expected the source restored with its identity, got %+v, %v", restored, err)
+	}
+	tx.First(&conversation, conversation.ID)
+	tx.First(&message, message.ID)
+	if conversation.ContactID != source.ID || *message.ContactID != source.ID {
+		t.Errorf("expected conversation and message back on the source, got %d and %d", conversation.ContactID, *message.ContactID)
+	}
+
+	if _, err := service.UndoMerge(claims, merge.ID); !errors.Is(err, impl.ErrContactMergeNotUndoable) {
+		t.Errorf("expected ErrContactMergeNotUndoable, got %v", err)
+	}
+}
+
+func TestContactService_GetTimeline_PagesNewestFirst(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewContactService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+
+	guestRole, _ := GetOrCreateRole(tx, models.RoleGuest)
+	guest := models.UserModel{
+		Email:    "guest@example.com",
+		Name:     "Guest User",
+		Password: "password123",
+		RoleID:   guestRole.ID,
+	}
+	tx.Create(&guest)
+	contact := CreateTestContact(tx, t, org.ID, &guest)
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	conv := models.ConversationModel{
+		CreatedAt:      start,
+		OrganizationID: org.ID,
+		ContactID:      contact.ID,
+		Status:         models.ConversationStatusDone,
+	}
+	tx.Create(&conv)
+	// the messages share the stored second, the pages must still list
+	// each of them once
+	for _, offset := range []time.Duration{0, 200 * time.Millisecond, 400 * time.Millisecond} {
+		tx.Create(&models.ConversationMessageModel{
+			CreatedAt:      start.Add(time.Minute + offset),
+			OrganizationID: org.ID,
+			ConversationID: conv.ID,
+			ContactID:      &contact.ID,
+			Message:        "My order never arrived",
+		})
+	}
+	ticket := models.TicketModel{
+		CreatedAt:      start.Add(2 * time.Minute),
+		OrganizationID: org.ID,
+		ConversationID: conv.ID,
+		CreatedByID:    owner.ID,
+		TicketNumber:   "TCK-TIMELINE-1",
+		Name:           "Missing order",
+		Status:         models.TicketStatusPending,
+	}
+	tx.Create(&ticket)
+	tx.Create(&models.TicketConversationModel{OrganizationID: org.ID, TicketID: ticket.ID, ConversationID: conv.ID})
+	// a ticket of another contact's conversation shows up once it is
+	// linked to one of this contact's conversations
+	other := createTicketConversation(tx, t, org.ID)
+	linked := models.TicketModel{
+		CreatedAt:      start.Add(2*time.Minute + 30*time.Second),
+		OrganizationID: org.ID,
+		ConversationID: other.ID,
+		CreatedByID:    owner.ID,
+		TicketNumber:   "TCK-TIMELINE-2",
+		Name:           "Courier lost parcels",
+		Status:         models.TicketStatusPending,
+	}
+	tx.Create(&linked)
+	tx.Create(&models.TicketConversationModel{OrganizationID: org.ID, TicketID: linked.ID, ConversationID: conv.ID})
+	tx.Create(&models.StatusChangeModel{
+		CreatedAt:      start.Add(3 * time.Minute),
+		OrganizationID: org.ID,
+		SubjectType:    models.StatusChangeSubjectConversation,
+		SubjectID:      conv.ID,
+		ConversationID: conv.ID,
+		FromStatus:     models.ConversationStatusPending,
+		ToStatus:       models.ConversationStatusDone,
+		ChangedByID:    &owner.ID,
+	})
+
+	var types []string
+	cursor := ""
+	for pages := 0; pages < 10; pages++ {
+		result, err := service.GetTimeline(claims, contact.ID, cursor, 2)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		for _, entry := range result.Data {
+			if entry.ConversationID != conv.ID && (entry.Ticket == nil || entry.Ticket.ID != linked.ID) {
+				t.Errorf("expected entry of the conversation, got %+v", entry)
+			}
+			types = append(types, entry.Type)
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		cursor = result.NextCursor
+	}
+
+	expected := []string{"status_changed", "ticket_created", "ticket_created", "message", "message", "message", "conversation_created"}
+	if len(types) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, types)
+	}
+	for i := range expected {
+		if types[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, types)
+			break
+		}
+	}
+
+	_, err := service.GetTimeline(claims, contact.ID, "not-a-cursor", 2)
+	if !errors.Is(err, impl.ErrTimelineCursorInvalid) {
+		t.Errorf("expected ErrTimelineCursorInvalid, got %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package tests
+
+import (
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/lib/mail"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const testInboundMail = "From: Jane Customer <jane@example.com>\r\n" +
+	"To: support@testorg.example.com\r\n" +
+	"Subject: Order problem\r\n" +
+	"Message-ID: <first@example.com>\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=\"mixed\"\r\n" +
+	"\r\n" +
+	"--mixed\r\n" +
+	"Content-Type: multipart/alternative; boundary=\"alt\"\r\n" +
+	"\r\n" +
+	"--alt\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"\r\n" +
+	"My order never arrived\r\n" +
+	"--alt\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<p>My order never arrived</p>\r\n" +
+	"--alt--\r\n" +
+	"--mixed\r\n" +
+	"Content-Type: text/plain; name=\"invoice.txt\"\r\n" +
+	"Content-Disposition: attachment; filename=\"invoice.txt\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"SU5WLTAwMQ==\r\n" +
+	"--mixed--\r\n"
+
+func TestEmailConversationService_ProcessInboundEmail_CreatesConversation(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewEmailConversationService(tx)
+
+	org, _ := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	inboundEmail := "support@testorg.example.com"
+	tx.Model(org).Update("inbound_email", inboundEmail)
+
+	if !service.AcceptRecipient(inboundEmail) {
+		t.Fatal("expected inbound address to be accepted")
+	}
+
+	inbound, err := mail.ParseInboundMail(strings.NewReader(testInboundMail))
+	if err != nil {
+		t.Fatalf("expected mail to parse, got %v", err)
+	}
+
+	if err := service.ProcessInboundEmail(inboundEmail, inbound); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var conversation models.ConversationModel
+	if err := tx.Where("organization_id = ?", org.ID).
+		Where("channel = ?", models.ConversationChannelEmail).
+		First(&conversation).Error; err != nil {
+		t.Fatalf("expected email conversation, got %v", err)
+	}
+
+	var message models.ConversationMessageModel
+	if err := tx.Preload("Attachments").
+		Where("conversation_id = ?", conversation.ID).
+		First(&message).Error; err != nil {
+		t.Fatalf("expected message, got %v", err)
+	}
+
+	if message.Message != "My order never arrived" {
+		t.Errorf("expected text part as message, got %q", message.Message)
+	}
+	if len(message.Attachments) != 1 || string(message.Attachments[0].Content) != "INV-001" {
+		t.Errorf("expected decoded attachment, got %+v", message.Attachments)
+	}
+}
+
+func TestEmailConversationService_ProcessInboundEmail_ThreadsReply(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewEmailConversationService(tx)
+
+	org, _ := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	inboundEmail := "support@testorg.example.com"
+	tx.Model(org).Update("inbound_email", inboundEmail)
+
+	first, _ := mail.ParseInboundMail(strings.NewReader(testInboundMail))
+	if err := service.ProcessInboundEmail(inboundEmail, first); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	reply := &mail.InboundMail{
+		MessageID:   "<second@example.com>",
+		InReplyTo:   "<first@example.com>",
+		FromAddress: "jane@example.com",
+		Subject:     "Re: Order problem",
+		Text:        "Any update?",
+	}
+	if err := service.ProcessInboundEmail(inboundEmail, reply); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var count int64
+	tx.Model(&models.ConversationModel{}).
+		Where("organization_id = ?", org.ID).
+		Count(&count)
+	if count != 1 {
+		t.Errorf("expected reply in the same conversation, got %d conversations", count)
+	}
+}
+
+func TestEmailConversationService_ProcessInboundEmail_ThreadOfAnotherSender(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewEmailConversationService(tx)
+
+	org, _ := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	inboundEmail := "support@testorg.example.com"
+	tx.Model(org).Update("inbound_email", inboundEmail)
+
+	first, _ := mail.ParseInboundMail(strings.NewReader(testInboundMail))
+	if err := service.ProcessInboundEmail(inboundEmail, first); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	injected := &mail.InboundMail{
+		MessageID:   "<injected@example.net>",
+		InReplyTo:   "<first@example.com>",
+		FromAddress: "mallory@example.net",
+		Subject:     "Re: Order problem",
+		Text:        "Please pay here",
+	}
+	if err := service.ProcessInboundEmail(inboundEmail, injected); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var conversations []models.ConversationModel
+	tx.Where("organization_id = ?", org.ID).Order("id ASC").Find(&conversations)
+	if len(conversations) != 2 || conversations[0].ContactID == conversations[1].ContactID {
+		t.Errorf("expected the reply of another sender in a conversation of its own, got %+v", conversations)
+	}
+}
+
+func TestEmailConversationService_HandleEnvelope_RetryWithoutMessageID(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewEmailConversationService(tx)
+
+	org, _ := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	inboundEmail := "support@testorg.example.com"
+	tx.Model(org).Update("inbound_email", inboundEmail)
+
+	envelope := mail.Envelope{
+		From:       "jane@example.com",
+		Recipients: []string{inboundEmail, inboundEmail},
+		Data: []byte("From: Jane Customer <jane@example.com>\r\n" +
+			"To: support@testorg.example.com\r\n" +
+			"Subject: No id\r\n" +
+			"\r\n" +
+			"Hello without a Message-ID\r\n"),
+	}
+	for range 2 {
+		if err := service.HandleEnvelope(envelope); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	var count int64
+	tx.Model(&models.ConversationMessageModel{}).
+		Where("organization_id = ?", org.ID).
+		Count(&count)
+	if count != 1 {
+		t.Errorf("expected the retried mail to be stored once, got %d messages", count)
+	}
+}
+
+func TestEmailConversationService_ParseInboundMail_NestingLimit(t *testing.T) {
+	nested := func(levels int) string {
+		part := "Content-Type: text/plain\r\n\r\nhello\r\n"
+		for depth := levels - 1; depth >= 0; depth-- {
+			part = fmt.Sprintf("Content-Type: multipart/mixed; boundary=\"b%d\"\r\n\r\n--b%d\r\n%s--b%d--\r\n", depth, depth, part, depth)
+		}
+		return "From: Jane Customer <jane@example.com>\r\nSubject: Nested\r\n" + part
+	}
+
+	inbound, err := mail.ParseInboundMail(strings.NewReader(nested(5)))
+	if err != nil || inbound.Text != "hello" {
+		t.Fatalf("expected regular nesting to parse, got %+v, %v", inbound, err)
+	}
+	if _, err := mail.ParseInboundMail(strings.NewReader(nested(20))); !errors.Is(err, mail.ErrInvalidMail) {
+		t.Errorf("expected ErrInvalidMail for deeply nested parts, got %v", err)
+	}
+}
+
+func TestEmailConversationService_AcceptRecipient_UnknownAddress(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewEmailConversationService(tx)
+
+	if service.AcceptRecipient("nobody@unknown.example.com") {
+		t.Error("expected unknown address to be rejected")
+	}
+}
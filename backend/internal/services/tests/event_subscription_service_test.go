@@ -0,0 +1,193 @@
+package tests
+
+import (
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/eventdto"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/outbound"
+	"DewaSRY/sociomile-app/pkg/models"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// fakeWebhookSender records the signed requests and answers with code
+type fakeWebhookSender struct {
+	code int
+	err  error
+	sent []outbound.SignedRequest
+}
+
+func (f *fakeWebhookSender) Send(req outbound.SignedRequest) (int, error) {
+	f.sent = append(f.sent, req)
+	return f.code, f.err
+}
+
+func TestEventSubscriptionService_CreateSubscription_ReturnsSecretOnce(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewEventSubscriptionService(tx, &fakeWebhookSender{code: 200}, 3)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	claims := &jwtLib.Claims{UserID: owner.ID, OrganizationId: &org.ID}
+
+	created, err := service.CreateSubscription(claims, requestdto.CreateEventSubscriptionRequest{
+		URL:        "https://crm.example.com/hooks",
+		EventTypes: []string{models.EventTicketCreated, models.EventTicketCreated},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if created.Secret == "" {
+		t.Error("expected secret in create response")
+	}
+	if len(created.EventTypes) != 1 {
+		t.Errorf("expected duplicated event types to be removed, got %v", created.EventTypes)
+	}
+
+	list, err := service.GetSubscriptions(claims)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(list.Data) != 1 || list.Data[0].Secret != "" {
+		t.Errorf("expected one subscription without secret, got %+v", list.Data)
+	}
+}
+
+func TestEventSubscriptionService_StatusChangePublishesSignedEvent(t *testing.T) {
+	tx := SetupTestDB(t)
+	sender := &fakeWebhookSender{code: 200}
+	service := impl.NewEventSubscriptionService(tx, sender, 3)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	claims := &jwtLib.Claims{UserID: owner.ID, OrganizationId: &org.ID}
+
+	subscription, err := service.CreateSubscription(claims, requestdto.CreateEventSubscriptionRequest{
+		URL:        "https://crm.example.com/hooks",
+		EventTypes: []string{models.EventConversationStatusChanged},
+	})
+	if err != nil {
+		t.Fatalf("failed to create subscription: %v", err)
+	}
+
+	guestRole, _ := GetOrCreateRole(tx, models.RoleGuest)
+	guest := models.UserModel{Email: "guest@test.com", Name: "Guest", Password: "password", RoleID: guestRole.ID}
+	tx.Create(&guest)
+
+	contact := CreateTestContact(tx, t, org.ID, &guest)
+	conv := models.ConversationModel{
+		OrganizationID: org.ID,
+		GuestID:        &guest.ID,
+		ContactID:      contact.ID,
+		Status:         models.ConversationStatusPending,
+	}
+	tx.Create(&conv)
+
+	err = impl.NewConversationService(tx).UpdateConversationStatus(claims, conv.ID, requestdto.UpdateConversationRequest{
+		Status: models.ConversationStatusDone,
+	})
+	if err != nil {
+		t.Fatalf("failed to update status: %v", err)
+	}
+
+	if _, err := service.ProcessDueDeliveries(10); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(sender.sent) != 1 {
+		t.Fatalf("expected one event sent, got %d", len(sender.sent))
+	}
+	if sender.sent[0].URL != subscription.URL || sender.sent[0].Secret != subscription.Secret {
+		t.Error("expected event to be signed with the subscription secret")
+	}
+
+	var envelope eventdto.Envelope
+	if err := json.Unmarshal(sender.sent[0].Body, &envelope); err != nil {
+		t.Fatalf("expected json envelope, got %v", err)
+	}
+	if envelope.Type != models.EventConversationStatusChanged || envelope.Version != models.EventPayloadVersion {
+		t.Errorf("unexpected envelope %s %s", envelope.Type, envelope.Version)
+	}
+
+	page, limit := 1, 10
+	log, err := service.GetDeliveries(claims, subscription.ID, filtersdto.FiltersDto{Page: &page, Limit: &limit})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(log.Data) != 1 || log.Data[0].Status != models.EventDeliveryStatusSucceeded {
+		t.Errorf("expected one succeeded delivery, got %+v", log.Data)
+	}
+}
+
+func TestEventSubscriptionService_UnsubscribedEventIsNotQueued(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewEventSubscriptionService(tx, &fakeWebhookSender{code: 200}, 3)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	claims := &jwtLib.Claims{UserID: owner.ID, OrganizationId: &org.ID}
+
+	if _, err := service.CreateSubscription(claims, requestdto.CreateEventSubscriptionRequest{
+		URL:        "https://crm.example.com/hooks",
+		EventTypes: []string{models.EventTicketCreated},
+	}); err != nil {
+		t.Fatalf("failed to create subscription: %v", err)
+	}
+
+	err := impl.NewWebHookConversationService(tx).ProcessConversation(requestdto.WebHooksRequest{
+		OrganizationID: org.ID,
+		Email:          "webhook@example.com",
+		Message:        "Hello",
+	})
+	if err != nil {
+		t.Fatalf("failed to process webhook: %v", err)
+	}
+
+	var count int64
+	tx.Model(&models.EventDeliveryModel{}).Where("organization_id = ?", org.ID).Count(&count)
+	if count != 0 {
+		t.Errorf("expected no deliveries, got %d", count)
+	}
+}
+
+func TestEventSubscriptionService_ReplayDelivery(t *testing.T) {
+	tx := SetupTestDB(t)
+	sender := &fakeWebhookSender{code: 500, err: errors.New("endpoint answered 500")}
+	service := impl.NewEventSubscriptionService(tx, sender, 1)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	claims := &jwtLib.Claims{UserID: owner.ID, OrganizationId: &org.ID}
+
+	subscription, _ := service.CreateSubscription(claims, requestdto.CreateEventSubscriptionRequest{
+		URL:        "https://crm.example.com/hooks",
+		EventTypes: []string{models.EventConversationCreated},
+	})
+
+	guestRole, _ := GetOrCreateRole(tx, models.RoleGuest)
+	guest := models.UserModel{Email: "guest@test.com", Name: "Guest", Password: "password", RoleID: guestRole.ID}
+	tx.Create(&guest)
+
+	err := impl.NewGuestConversationService(tx).CreateConversation(
+		&jwtLib.Claims{UserID: guest.ID},
+		requestdto.CreateConversationRequest{OrganizationID: org.ID},
+	)
+	if err != nil {
+		t.Fatalf("failed to create conversation: %v", err)
+	}
+
+	service.ProcessDueDeliveries(10)
+
+	var failed models.EventDeliveryModel
+	tx.Where("subscription_id = ?", subscription.ID).First(&failed)
+	if failed.Status != models.EventDeliveryStatusDeadLetter {
+		t.Fatalf("expected dead_letter, got %s", failed.Status)
+	}
+
+	replay, err := service.ReplayDelivery(claims, subscription.ID, failed.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if replay.EventID != failed.EventID || replay.ReplayOfID == nil || *replay.ReplayOfID != failed.ID {
+		t.Errorf("expected replay of %d with the same event id, got %+v", failed.ID, replay)
+	}
+}
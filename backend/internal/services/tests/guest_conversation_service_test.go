@@ -7,6 +7,7 @@ import (
 	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
 	"DewaSRY/sociomile-app/pkg/models"
 	"testing"
+	"time"
 )
 
 func TestGuestConversation_CreateConversation(t *testing.T) {
@@ -67,15 +68,18 @@ func TestGuestConversation_GetConversation(t *testing.T) {
 		t.Fatalf("failed to create guest user: %v", err)
 	}
 
+	contact := CreateTestContact(tx, t, org.ID, &guest)
 	conversations := []models.ConversationModel{
 		{
 			OrganizationID: org.ID,
-			GuestID:        guest.ID,
+			GuestID:        &guest.ID,
+			ContactID:      contact.ID,
 			Status:         models.ConversationStatusPending,
 		},
 		{
 			OrganizationID: org.ID,
-			GuestID:        guest.ID,
+			GuestID:        &guest.ID,
+			ContactID:      contact.ID,
 			Status:         models.ConversationStatusInProgress,
 		},
 	}
@@ -107,3 +111,97 @@ func TestGuestConversation_GetConversation(t *testing.T) {
 		t.Fatal("expected result, got nil")
 	}
 }
+
+func TestGuestConversation_GetConversation_IncludesLinkedContacts(t *testing.T) {
+	tx := SetupTestDB(t)
+	guestConversationService := impl.NewGuestConversationService(tx)
+	webHookService := impl.NewWebHookConversationService(tx)
+
+	org, _ := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+
+	if err := webHookService.ProcessConversation(requestdto.WebHooksRequest{
+		OrganizationID: org.ID,
+		Email:          "guest@example.com",
+		Message:        "written before registering",
+	}); err != nil {
+		t.Fatalf("failed to ingest webhook: %v", err)
+	}
+
+	guestRole, _ := GetOrCreateRole(tx, models.RoleGuest)
+	guest := models.UserModel{
+		Email:    "guest@example.com",
+		Name:     "Guest User",
+		Password: "password123",
+		RoleID:   guestRole.ID,
+	}
+	if err := tx.Create(&guest).Error; err != nil {
+		t.Fatalf("failed to create guest user: %v", err)
+	}
+
+	claims := &jwtLib.Claims{
+		UserID: guest.ID,
+		Email:  guest.Email,
+		RoleID: guestRole.ID,
+	}
+
+	// registering does not prove the email, the guest gets a contact of
+	// their own and only claims the address
+	if err := guestConversationService.CreateConversation(claims, requestdto.CreateConversationRequest{
+		OrganizationID: org.ID,
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var contacts []models.ContactModel
+	tx.Where("organization_id = ?", org.ID).Order("id ASC").Find(&contacts)
+	if len(contacts) != 2 || contacts[0].UserID != nil || contacts[1].UserID == nil || *contacts[1].UserID != guest.ID {
+		t.Fatalf("expected the existing contact to stay unlinked, got %+v", contacts)
+	}
+	var claimed int64
+	tx.Model(&models.ContactIdentityModel{}).
+		Where("contact_id = ? AND type = ? AND value = ?", contacts[1].ID, models.ContactIdentityTypeClaimedEmail, guest.Email).
+		Count(&claimed)
+	if claimed != 1 {
+		t.Errorf("expected the email to be claimed, got %d", claimed)
+	}
+
+	page, limit := 1, 10
+	result, err := guestConversationService.GetConversation(claims, filtersdto.FiltersDto{Page: &page, Limit: &limit})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Metadata.Total != 1 {
+		t.Errorf("expected only the portal conversation before the email is verified, got %d", result.Metadata.Total)
+	}
+
+	// a webhook message from the address afterwards is not filed under the guest
+	if err := webHookService.ProcessConversation(requestdto.WebHooksRequest{
+		OrganizationID: org.ID,
+		Email:          "guest@example.com",
+		Message:        "written after registering",
+	}); err != nil {
+		t.Fatalf("failed to ingest webhook: %v", err)
+	}
+
+	// signing in by magic link proves the email and links the contact
+	mailer := &fakeMailer{}
+	magicLinks := impl.NewMagicLinkService(tx, &MockJwtService{}, mailer, impl.MagicLinkOptions{
+		URL:     "http://localhost:3000/auth/magic-link",
+		TTL:     15 * time.Minute,
+		PerHour: 5,
+	})
+	if err := magicLinks.RequestLink(requestdto.MagicLinkRequest{Email: guest.Email}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := magicLinks.VerifyLink(requestdto.VerifyMagicLinkRequest{Token: magicLinkToken(t, mailer)}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	result, err = guestConversationService.GetConversation(claims, filtersdto.FiltersDto{Page: &page, Limit: &limit})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Metadata.Total != 2 {
+		t.Errorf("expected the webhook and the portal conversation, got %d", result.Metadata.Total)
+	}
+}
@@ -30,9 +30,11 @@ func TestGuestMessageService_SendConversationMessage(t *testing.T) {
 		t.Fatalf("failed to create guest user: %v", err)
 	}
 
+	contact := CreateTestContact(tx, t, org.ID, &guest)
 	conversation := models.ConversationModel{
 		OrganizationID: org.ID,
-		GuestID:        guest.ID,
+		GuestID:        &guest.ID,
+		ContactID:      contact.ID,
 		Status:         models.ConversationStatusPending,
 	}
 	if err := tx.Create(&conversation).Error; err != nil {
@@ -77,9 +79,11 @@ func TestGuestMessageService_GetConversationMessageList(t *testing.T) {
 		t.Fatalf("failed to create guest user: %v", err)
 	}
 
+	contact := CreateTestContact(tx, t, org.ID, &guest)
 	conversation := models.ConversationModel{
 		OrganizationID: org.ID,
-		GuestID:        guest.ID,
+		GuestID:        &guest.ID,
+		ContactID:      contact.ID,
 		Status:         models.ConversationStatusPending,
 	}
 	if err := tx.Create(&conversation).Error; err != nil {
@@ -91,7 +95,7 @@ func TestGuestMessageService_GetConversationMessageList(t *testing.T) {
 		message := models.ConversationMessageModel{
 			OrganizationID: org.ID,
 			ConversationID: conversation.ID,
-			CreatedByID:    guest.ID,
+			CreatedByID:    &guest.ID,
 			Message:        msg,
 		}
 		if err := tx.Create(&message).Error; err != nil {
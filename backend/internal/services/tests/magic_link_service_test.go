@@ -0,0 +1,190 @@
+package tests
+
+import (
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/models"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var magicLinkPattern = regexp.MustCompile(`https?://\S+`)
+
+func magicLinkToken(t *testing.T, mailer *fakeMailer) string {
+	sent := mailer.waitForMails(1)
+	if len(sent) == 0 {
+		t.Fatal("expected a magic link mail, got none")
+	}
+	link, err := url.Parse(magicLinkPattern.FindString(sent[len(sent)-1].Text))
+	if err != nil {
+		t.Fatalf("failed to parse magic link: %v", err)
+	}
+	return link.Query().Get("token")
+}
+
+func TestMagicLinkService_WebhookCustomerSignsIn(t *testing.T) {
+	tx := SetupTestDB(t)
+	mailer := &fakeMailer{}
+	service := impl.NewMagicLinkService(tx, &MockJwtService{}, mailer, impl.MagicLinkOptions{
+		URL:     "http://localhost:3000/auth/magic-link",
+		TTL:     15 * time.Minute,
+		PerHour: 5,
+	})
+
+	org, _ := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+	if _, err := GetOrCreateRole(tx, models.RoleGuest); err != nil {
+		t.Fatalf("failed to get guest role: %v", err)
+	}
+	if err := impl.NewWebHookConversationService(tx).ProcessConversation(requestdto.WebHooksRequest{
+		OrganizationID: org.ID,
+		Email:          "customer@example.com",
+		Name:           "Customer",
+		Message:        "where is my order?",
+	}); err != nil {
+		t.Fatalf("failed to ingest webhook: %v", err)
+	}
+
+	if err := service.RequestLink(requestdto.MagicLinkRequest{Email: "Customer@Example.com"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	token := magicLinkToken(t, mailer)
+
+	result, err := service.VerifyLink(requestdto.VerifyMagicLinkRequest{Token: token})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Token == "" || result.User.Name != "Customer" || result.User.RoleName != models.RoleGuest {
+		t.Errorf("expected a guest signed in as the contact, got %+v", result)
+	}
+
+	var contact models.ContactModel
+	tx.Where("organization_id = ?", org.ID).First(&contact)
+	if contact.UserID == nil || *contact.UserID != result.User.ID {
+		t.Errorf("expected the contact to be linked to the user, got %+v", contact)
+	}
+
+	_, err = service.VerifyLink(requestdto.VerifyMagicLinkRequest{Token: token})
+	if !errors.Is(err, impl.ErrMagicLinkInvalid) {
+		t.Errorf("expected the link to work once, got %v", err)
+	}
+}
+
+func TestMagicLinkService_UnknownEmailSendsNothing(t *testing.T) {
+	tx := SetupTestDB(t)
+	mailer := &fakeMailer{}
+	service := impl.NewMagicLinkService(tx, &MockJwtService{}, mailer, impl.MagicLinkOptions{
+		URL:     "http://localhost:3000/auth/magic-link",
+		TTL:     15 * time.Minute,
+		PerHour: 5,
+	})
+
+	if err := service.RequestLink(requestdto.MagicLinkRequest{Email: "nobody@example.com"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var links int64
+	tx.Model(&models.MagicLinkTokenModel{}).Where("email = ?", "nobody@example.com").Count(&links)
+	if links != 0 || len(mailer.sent) != 0 {
+		t.Errorf("expected no link and no mail, got %d links and %d mails", links, len(mailer.sent))
+	}
+}
+
+func TestMagicLinkService_RateLimitedAndExpired(t *testing.T) {
+	tx := SetupTestDB(t)
+	mailer := &fakeMailer{}
+	service := impl.NewMagicLinkService(tx, &MockJwtService{}, mailer, impl.MagicLinkOptions{
+		URL:     "http://localhost:3000/auth/magic-link",
+		TTL:     -time.Minute,
+		PerHour: 1,
+	})
+
+	guestRole, _ := GetOrCreateRole(tx, models.RoleGuest)
+	guest := models.UserModel{Email: "guest@example.com", Name: "Guest", Password: "secret", RoleID: guestRole.ID}
+	tx.Create(&guest)
+
+	for range 2 {
+		if err := service.RequestLink(requestdto.MagicLinkRequest{Email: guest.Email}); err != nil {
+			t.Fatalf("expected the same answer for every request, got %v", err)
+		}
+	}
+	_, err := service.VerifyLink(requestdto.VerifyMagicLinkRequest{Token: magicLinkToken(t, mailer)})
+	if !errors.Is(err, impl.ErrMagicLinkInvalid) {
+		t.Errorf("expected the expired link to be rejected, got %v", err)
+	}
+	if sent := mailer.waitForMails(1); len(sent) != 1 {
+		t.Errorf("expected the second request to be limited, got %d mails", len(sent))
+	}
+}
+
+func TestMagicLinkService_UnknownEmailCountsTowardsLimit(t *testing.T) {
+	tx := SetupTestDB(t)
+	mailer := &fakeMailer{}
+	service := impl.NewMagicLinkService(tx, &MockJwtService{}, mailer, impl.MagicLinkOptions{
+		URL:     "http://localhost:3000/auth/magic-link",
+		TTL:     15 * time.Minute,
+		PerHour: 2,
+	})
+
+	for range 3 {
+		if err := service.RequestLink(requestdto.MagicLinkRequest{Email: "later@example.com"}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	var requests int64
+	tx.Model(&models.MagicLinkRequestModel{}).Where("email = ?", "later@example.com").Count(&requests)
+	if requests != 2 {
+		t.Errorf("expected the requests up to the limit to be recorded, got %d", requests)
+	}
+
+	// the address becoming a customer does not reset its limit
+	guestRole, _ := GetOrCreateRole(tx, models.RoleGuest)
+	tx.Create(&models.UserModel{Email: "later@example.com", Name: "Later", Password: "secret", RoleID: guestRole.ID})
+	if err := service.RequestLink(requestdto.MagicLinkRequest{Email: "later@example.com"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var links int64
+	tx.Model(&models.MagicLinkTokenModel{}).Where("email = ?", "later@example.com").Count(&links)
+	if links != 0 || len(mailer.sent) != 0 {
+		t.Errorf("expected the request to be limited, got %d links and %d mails", links, len(mailer.sent))
+	}
+}
+
+func TestMagicLinkService_StaffEmailSendsNothing(t *testing.T) {
+	tx := SetupTestDB(t)
+	mailer := &fakeMailer{}
+	service := impl.NewMagicLinkService(tx, &MockJwtService{}, mailer, impl.MagicLinkOptions{
+		URL:     "http://localhost:3000/auth/magic-link",
+		TTL:     15 * time.Minute,
+		PerHour: 5,
+	})
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+	sales := createTicketStaff(tx, t, org.ID, "sales@example.com")
+	// staff that is also a contact of an organization stays out as well
+	CreateTestContact(tx, t, org.ID, sales)
+
+	for _, email := range []string{owner.Email, sales.Email} {
+		if err := service.RequestLink(requestdto.MagicLinkRequest{Email: email}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if len(mailer.sent) != 0 {
+		t.Errorf("expected no mail for staff, got %d", len(mailer.sent))
+	}
+
+	// a link issued before the address became staff does not sign them in
+	token := "staff-link"
+	hash := sha256.Sum256([]byte(token))
+	tx.Create(&models.MagicLinkTokenModel{
+		Email:     sales.Email,
+		TokenHash: hex.EncodeToString(hash[:]),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if _, err := service.VerifyLink(requestdto.VerifyMagicLinkRequest{Token: token}); !errors.Is(err, impl.ErrMagicLinkInvalid) {
+		t.Errorf("expected ErrMagicLinkInvalid for staff, got %v", err)
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
 	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
 	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/mail"
 	"DewaSRY/sociomile-app/pkg/models"
+	"encoding/json"
+	"errors"
 	"testing"
 )
 
@@ -24,15 +27,18 @@ func TestOrganizationConversationService_GetConversationsList(t *testing.T) {
 	}
 	tx.Create(&guest)
 
+	contact := CreateTestContact(tx, t, org.ID, &guest)
 	conv := models.ConversationModel{
 		OrganizationID: org.ID,
-		GuestID:        guest.ID,
+		GuestID:        &guest.ID,
+		ContactID:      contact.ID,
 		Status:         models.ConversationStatusPending,
 	}
 	tx.Create(&conv)
 
 	claims := &jwtLib.Claims{
 		UserID:         owner.ID,
+		RoleID:         owner.RoleID,
 		OrganizationId: &org.ID,
 	}
 
@@ -40,7 +46,7 @@ func TestOrganizationConversationService_GetConversationsList(t *testing.T) {
 	limit := 10
 	filter := filtersdto.FiltersDto{Page: &page, Limit: &limit}
 
-	result, err := service.GetConversationsList(claims, filter)
+	result, err := service.GetConversationsList(claims, filter, filtersdto.ConversationFiltersDto{})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -50,11 +56,63 @@ func TestOrganizationConversationService_GetConversationsList(t *testing.T) {
 	}
 }
 
+func TestOrganizationConversationService_GetConversationsList_FiltersByContactAttribute(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewConversationService(tx)
+	contactService := impl.NewContactService(tx)
+	attributeService := impl.NewContactAttributeService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+
+	if _, err := attributeService.CreateAttribute(claims, requestdto.CreateContactAttributeRequest{
+		Key:     "tier",
+		Label:   "Tier",
+		Type:    models.ContactAttributeTypeEnum,
+		Options: []string{"silver", "gold"},
+	}); err != nil {
+		t.Fatalf("failed to create attribute: %v", err)
+	}
+
+	for _, tier := range []string{"gold", "silver"} {
+		contact, _ := contactService.CreateContact(claims, requestdto.CreateContactRequest{Name: tier})
+		if _, err := contactService.SetAttributes(claims, contact.ID, requestdto.SetContactAttributesRequest{
+			Attributes: map[string]any{"tier": tier},
+		}); err != nil {
+			t.Fatalf("failed to set attributes: %v", err)
+		}
+		tx.Create(&models.ConversationModel{
+			OrganizationID: org.ID,
+			ContactID:      contact.ID,
+			Status:         models.ConversationStatusPending,
+		})
+	}
+
+	page, limit := 1, 10
+	filter := filtersdto.FiltersDto{Page: &page, Limit: &limit}
+
+	result, err := service.GetConversationsList(claims, filter, filtersdto.ConversationFiltersDto{Attributes: map[string]string{"tier": "GOLD"}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Metadata.Total != 1 || len(result.Conversations) != 1 {
+		t.Fatalf("expected one gold conversation, got %d", result.Metadata.Total)
+	}
+	if result.Conversations[0].Contact.Attributes["tier"] != "gold" {
+		t.Errorf("expected the contact attributes in the list, got %+v", result.Conversations[0].Contact)
+	}
+
+	_, err = service.GetConversationsList(claims, filter, filtersdto.ConversationFiltersDto{Attributes: map[string]string{"city": "Jakarta"}})
+	if !errors.Is(err, impl.ErrContactAttributeUnknown) {
+		t.Errorf("expected ErrContactAttributeUnknown, got %v", err)
+	}
+}
+
 func TestOrganizationConversationService_GetConversationByID(t *testing.T) {
 	tx := SetupTestDB(t)
 	service := impl.NewConversationService(tx)
 
-	org, _ := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
 
 	guestRole, _ := GetOrCreateRole(tx, models.RoleGuest)
 	guest := models.UserModel{
@@ -65,14 +123,16 @@ func TestOrganizationConversationService_GetConversationByID(t *testing.T) {
 	}
 	tx.Create(&guest)
 
+	contact := CreateTestContact(tx, t, org.ID, &guest)
 	conv := models.ConversationModel{
 		OrganizationID: org.ID,
-		GuestID:        guest.ID,
+		GuestID:        &guest.ID,
+		ContactID:      contact.ID,
 		Status:         models.ConversationStatusPending,
 	}
 	tx.Create(&conv)
 
-	result, err := service.GetConversationByID(conv.ID)
+	result, err := service.GetConversationByID(&jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}, conv.ID)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -82,6 +142,79 @@ func TestOrganizationConversationService_GetConversationByID(t *testing.T) {
 	}
 }
 
+func TestOrganizationConversationService_GetConversationByID_ContactSummary(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewConversationService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+
+	guestRole, _ := GetOrCreateRole(tx, models.RoleGuest)
+	guest := models.UserModel{
+		Email:    "guest@test.com",
+		Name:     "Guest",
+		Password: "password",
+		RoleID:   guestRole.ID,
+	}
+	tx.Create(&guest)
+
+	contact := CreateTestContact(tx, t, org.ID, &guest)
+	done := models.ConversationModel{
+		OrganizationID: org.ID,
+		ContactID:      contact.ID,
+		Status:         models.ConversationStatusDone,
+	}
+	tx.Create(&done)
+	open := models.ConversationModel{
+		OrganizationID: org.ID,
+		ContactID:      contact.ID,
+		Status:         models.ConversationStatusPending,
+	}
+	tx.Create(&open)
+
+	tx.Create(&models.ConversationMessageModel{
+		OrganizationID: org.ID,
+		ConversationID: open.ID,
+		ContactID:      &contact.ID,
+		Message:        "Any news?",
+	})
+	refund := models.TicketModel{
+		OrganizationID: org.ID,
+		ConversationID: done.ID,
+		CreatedByID:    owner.ID,
+		TicketNumber:   "TCK-SUMMARY-1",
+		Name:           "Refund",
+		Status:         models.TicketStatusInProgress,
+	}
+	tx.Create(&refund)
+	tx.Create(&models.TicketConversationModel{OrganizationID: org.ID, TicketID: refund.ID, ConversationID: open.ID})
+	courier := models.TicketModel{
+		OrganizationID: org.ID,
+		ConversationID: createTicketConversation(tx, t, org.ID).ID,
+		CreatedByID:    owner.ID,
+		TicketNumber:   "TCK-SUMMARY-2",
+		Name:           "Courier lost parcels",
+		Status:         models.TicketStatusPending,
+	}
+	tx.Create(&courier)
+	tx.Create(&models.TicketConversationModel{OrganizationID: org.ID, TicketID: courier.ID, ConversationID: open.ID})
+
+	result, err := service.GetConversationByID(&jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}, done.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	summary := result.ContactSummary
+	if summary == nil {
+		t.Fatal("expected contact summary, got nil")
+	}
+	if summary.TotalConversations != 2 || summary.OpenConversations != 1 || summary.OpenTickets != 2 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+	if summary.LastContactAt == nil {
+		t.Errorf("expected last contact, got %+v", summary)
+	}
+}
+
 func TestOrganizationConversationService_AssignConversation(t *testing.T) {
 	tx := SetupTestDB(t)
 	service := impl.NewConversationService(tx)
@@ -97,9 +230,11 @@ func TestOrganizationConversationService_AssignConversation(t *testing.T) {
 	}
 	tx.Create(&guest)
 
+	contact := CreateTestContact(tx, t, org.ID, &guest)
 	conv := models.ConversationModel{
 		OrganizationID: org.ID,
-		GuestID:        guest.ID,
+		GuestID:        &guest.ID,
+		ContactID:      contact.ID,
 		Status:         models.ConversationStatusPending,
 	}
 	tx.Create(&conv)
@@ -108,7 +243,7 @@ func TestOrganizationConversationService_AssignConversation(t *testing.T) {
 		OrganizationStaffID: owner.ID,
 	}
 
-	result, err := service.AssignConversation(conv.ID, req)
+	result, err := service.AssignConversation(&jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}, conv.ID, req)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -122,7 +257,7 @@ func TestOrganizationConversationService_UpdateConversationStatus(t *testing.T)
 	tx := SetupTestDB(t)
 	service := impl.NewConversationService(tx)
 
-	org, _ := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
 
 	guestRole, _ := GetOrCreateRole(tx, models.RoleGuest)
 	guest := models.UserModel{
@@ -133,9 +268,11 @@ func TestOrganizationConversationService_UpdateConversationStatus(t *testing.T)
 	}
 	tx.Create(&guest)
 
+	contact := CreateTestContact(tx, t, org.ID, &guest)
 	conv := models.ConversationModel{
 		OrganizationID: org.ID,
-		GuestID:        guest.ID,
+		GuestID:        &guest.ID,
+		ContactID:      contact.ID,
 		Status:         models.ConversationStatusPending,
 	}
 	tx.Create(&conv)
@@ -144,9 +281,86 @@ func TestOrganizationConversationService_UpdateConversationStatus(t *testing.T)
 		Status: models.ConversationStatusDone,
 	}
 
-	err := service.UpdateConversationStatus(conv.ID, req)
+	err := service.UpdateConversationStatus(&jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}, conv.ID, req)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
+
+	var changes []models.StatusChangeModel
+	tx.Where("conversation_id = ?", conv.ID).Find(&changes)
+	if len(changes) != 1 || changes[0].FromStatus != models.ConversationStatusPending ||
+		changes[0].ToStatus != models.ConversationStatusDone || changes[0].ChangedByID == nil || *changes[0].ChangedByID != owner.ID {
+		t.Errorf("expected the status change to be recorded, got %+v", changes)
+	}
 }
 
+
+func TestOrganizationConversationService_SendMessage_EmailReplyIsThreaded(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewConversationService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	tx.Model(org).Update("inbound_email", "support@testorg.example.com")
+
+	guestRole, _ := GetOrCreateRole(tx, models.RoleGuest)
+	guest := models.UserModel{
+		Email:    "guest@test.com",
+		Name:     "Guest",
+		Password: "password",
+		RoleID:   guestRole.ID,
+	}
+	tx.Create(&guest)
+
+	subject := "Order problem"
+	contact := CreateTestContact(tx, t, org.ID, &guest)
+	conv := models.ConversationModel{
+		OrganizationID: org.ID,
+		GuestID:        &guest.ID,
+		ContactID:      contact.ID,
+		Status:         models.ConversationStatusPending,
+		Channel:        models.ConversationChannelEmail,
+		Subject:        &subject,
+	}
+	tx.Create(&conv)
+
+	externalID := "<first@example.com>"
+	tx.Create(&models.ConversationMessageModel{
+		OrganizationID:    org.ID,
+		ConversationID:    conv.ID,
+		CreatedByID:       &guest.ID,
+		Message:           "My order never arrived",
+		ExternalMessageID: &externalID,
+	})
+
+	claims := &jwtLib.Claims{
+		UserID:         owner.ID,
+		RoleID:         owner.RoleID,
+		OrganizationId: &org.ID,
+	}
+
+	err := service.SendMessage(claims, conv.ID, requestdto.CreateStaffMessageRequest{
+		Message: "We are checking it",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var delivery models.OutboundDeliveryModel
+	if err := tx.Where("conversation_id = ?", conv.ID).First(&delivery).Error; err != nil {
+		t.Fatalf("expected a queued delivery, got %v", err)
+	}
+	if delivery.Status != models.OutboundStatusQueued {
+		t.Errorf("expected status queued, got %s", delivery.Status)
+	}
+
+	var reply mail.OutgoingMail
+	if err := json.Unmarshal(delivery.Payload, &reply); err != nil {
+		t.Fatalf("expected mail payload, got %v", err)
+	}
+	if reply.InReplyTo != externalID {
+		t.Errorf("expected In-Reply-To %s, got %s", externalID, reply.InReplyTo)
+	}
+	if reply.Subject != "Re: Order problem" {
+		t.Errorf("expected reply subject, got %s", reply.Subject)
+	}
+}
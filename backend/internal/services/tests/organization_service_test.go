@@ -6,6 +6,7 @@ import (
 	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
 	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
 	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
 	"testing"
 )
 
@@ -96,3 +97,31 @@ func TestOrganizationService_GetStaffList(t *testing.T) {
 		t.Fatal("expected result, got nil")
 	}
 }
+
+func TestOrganizationService_Settings(t *testing.T) {
+	tx := SetupTestDB(t)
+	orgService := impl.NewOrganizationService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+
+	settings, err := orgService.GetSettings(claims)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if settings.Timezone != "UTC" {
+		t.Errorf("expected UTC by default, got %s", settings.Timezone)
+	}
+
+	if _, err := orgService.UpdateSettings(claims, requestdto.UpdateOrganizationSettingsRequest{Timezone: "Mars/Olympus"}); !errors.Is(err, impl.ErrOrganizationTimezoneInvalid) {
+		t.Errorf("expected ErrOrganizationTimezoneInvalid, got %v", err)
+	}
+
+	settings, err = orgService.UpdateSettings(claims, requestdto.UpdateOrganizationSettingsRequest{Timezone: "Asia/Jakarta"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if settings.Timezone != "Asia/Jakarta" {
+		t.Errorf("expected Asia/Jakarta, got %s", settings.Timezone)
+	}
+}
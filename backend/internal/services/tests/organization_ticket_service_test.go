@@ -6,7 +6,16 @@ import (
 	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
 	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
 	"DewaSRY/sociomile-app/pkg/models"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"gorm.io/gorm"
 )
 
 func TestOrganizationTicketService_CreateTicket(t *testing.T) {
@@ -24,15 +33,18 @@ func TestOrganizationTicketService_CreateTicket(t *testing.T) {
 	}
 	tx.Create(&guest)
 
+	contact := CreateTestContact(tx, t, org.ID, &guest)
 	conv := models.ConversationModel{
 		OrganizationID: org.ID,
-		GuestID:        guest.ID,
+		GuestID:        &guest.ID,
+		ContactID:      contact.ID,
 		Status:         models.ConversationStatusPending,
 	}
 	tx.Create(&conv)
 
 	claims := &jwtLib.Claims{
 		UserID:         owner.ID,
+		RoleID:         owner.RoleID,
 		OrganizationId: &org.ID,
 	}
 
@@ -62,9 +74,11 @@ func TestOrganizationTicketService_GetTicketsList(t *testing.T) {
 	}
 	tx.Create(&guest)
 
+	contact := CreateTestContact(tx, t, org.ID, &guest)
 	conv := models.ConversationModel{
 		OrganizationID: org.ID,
-		GuestID:        guest.ID,
+		GuestID:        &guest.ID,
+		ContactID:      contact.ID,
 		Status:         models.ConversationStatusPending,
 	}
 	tx.Create(&conv)
@@ -81,6 +95,7 @@ func TestOrganizationTicketService_GetTicketsList(t *testing.T) {
 
 	claims := &jwtLib.Claims{
 		UserID:         owner.ID,
+		RoleID:         owner.RoleID,
 		OrganizationId: &org.ID,
 	}
 
@@ -88,7 +103,7 @@ func TestOrganizationTicketService_GetTicketsList(t *testing.T) {
 	limit := 10
 	filter := filtersdto.FiltersDto{Page: &page, Limit: &limit}
 
-	result, err := service.GetTicketsList(claims, filter)
+	result, err := service.GetTicketsList(claims, filter, filtersdto.TicketFiltersDto{})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -113,9 +128,11 @@ func TestOrganizationTicketService_UpdateTicket(t *testing.T) {
 	}
 	tx.Create(&guest)
 
+	contact := CreateTestContact(tx, t, org.ID, &guest)
 	conv := models.ConversationModel{
 		OrganizationID: org.ID,
-		GuestID:        guest.ID,
+		GuestID:        &guest.ID,
+		ContactID:      contact.ID,
 		Status:         models.ConversationStatusPending,
 	}
 	tx.Create(&conv)
@@ -132,6 +149,7 @@ func TestOrganizationTicketService_UpdateTicket(t *testing.T) {
 
 	claims := &jwtLib.Claims{
 		UserID:         owner.ID,
+		RoleID:         owner.RoleID,
 		OrganizationId: &org.ID,
 	}
 
@@ -145,3 +163,861 @@ func TestOrganizationTicketService_UpdateTicket(t *testing.T) {
 		t.Fatalf("expected no error, got %v", err)
 	}
 }
+
+func createTicketConversation(tx *gorm.DB, t *testing.T, orgID uint) *models.ConversationModel {
+	contact := models.ContactModel{OrganizationID: orgID, Name: "Customer"}
+	if err := tx.Create(&contact).Error; err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+	conv := models.ConversationModel{
+		OrganizationID: orgID,
+		ContactID:      contact.ID,
+		Status:         models.ConversationStatusPending,
+	}
+	if err := tx.Create(&conv).Error; err != nil {
+		t.Fatalf("failed to create conversation: %v", err)
+	}
+	return &conv
+}
+
+func TestOrganizationTicketService_NumberFormat(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewTicketService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	conv := createTicketConversation(tx, t, org.ID)
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+
+	format, err := service.GetNumberFormat(claims)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if want := "TKT-" + time.Now().Format("20060102") + "-0001"; format.NextNumber != want {
+		t.Errorf("expected the default format %s, got %s", want, format.NextNumber)
+	}
+
+	_, err = service.UpdateNumberFormat(claims, requestdto.UpdateTicketNumberFormatRequest{Prefix: "SUP TEAM", DatePart: "none", Padding: 3})
+	if !errors.Is(err, impl.ErrTicketNumberFormatInvalid) {
+		t.Errorf("expected ErrTicketNumberFormatInvalid, got %v", err)
+	}
+	format, err = service.UpdateNumberFormat(claims, requestdto.UpdateTicketNumberFormatRequest{Prefix: "SUP", DatePart: "none", Padding: 3})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if format.NextNumber != "SUP-001" {
+		t.Errorf("expected SUP-001, got %s", format.NextNumber)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := service.CreateTicket(claims, requestdto.CreateTicketRequest{ConversationID: conv.ID, Name: "Test Ticket"}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	// a deleted ticket neither frees its number nor shifts the next one
+	var first models.TicketModel
+	tx.Where("organization_id = ? AND ticket_number = ?", org.ID, "SUP-001").First(&first)
+	tx.Delete(&first)
+	if err := service.CreateTicket(claims, requestdto.CreateTicketRequest{ConversationID: conv.ID, Name: "Test Ticket"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var numbers []string
+	tx.Model(&models.TicketModel{}).Unscoped().
+		Where("organization_id = ?", org.ID).
+		Order("id ASC").
+		Pluck("ticket_number", &numbers)
+	if len(numbers) != 3 || numbers[0] != "SUP-001" || numbers[1] != "SUP-002" || numbers[2] != "SUP-003" {
+		t.Errorf("expected SUP-001 to SUP-003, got %v", numbers)
+	}
+}
+
+func TestOrganizationTicketService_NumberInOrganizationTimezone(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewTicketService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	conv := createTicketConversation(tx, t, org.ID)
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+
+	// fourteen hours ahead of UTC, the date differs for most of the day
+	if _, err := impl.NewOrganizationService(tx).UpdateSettings(claims, requestdto.UpdateOrganizationSettingsRequest{Timezone: "Pacific/Kiritimati"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	kiritimati, _ := time.LoadLocation("Pacific/Kiritimati")
+	want := "TKT-" + time.Now().In(kiritimati).Format("20060102") + "-0001"
+
+	format, err := service.GetNumberFormat(claims)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if format.NextNumber != want {
+		t.Errorf("expected %s, got %s", want, format.NextNumber)
+	}
+
+	if err := service.CreateTicket(claims, requestdto.CreateTicketRequest{ConversationID: conv.ID, Name: "Test Ticket"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var ticket models.TicketModel
+	tx.Where("organization_id = ?", org.ID).First(&ticket)
+	if ticket.TicketNumber != want {
+		t.Errorf("expected %s, got %s", want, ticket.TicketNumber)
+	}
+}
+
+func TestOrganizationTicketService_CreateTicket_Concurrent(t *testing.T) {
+	db := SetupCommittedTestDB(t)
+	service := impl.NewTicketService(db)
+
+	name := fmt.Sprintf("Concurrent Tickets %d", time.Now().UnixNano())
+	org, owner := CreateTestOrganizationWithOwner(db, t, name)
+	conv := createTicketConversation(db, t, org.ID)
+	t.Cleanup(func() {
+		db.Unscoped().Where("organization_id = ?", org.ID).Delete(&models.TicketModel{})
+		db.Where("organization_id = ?", org.ID).Delete(&models.TicketSequenceModel{})
+		db.Unscoped().Where("organization_id = ?", org.ID).Delete(&models.ConversationModel{})
+		db.Unscoped().Where("organization_id = ?", org.ID).Delete(&models.ContactModel{})
+		db.Unscoped().Delete(&models.OrganizationModel{}, org.ID)
+		db.Unscoped().Delete(&models.UserModel{}, owner.ID)
+	})
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+
+	const workers = 20
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- service.CreateTicket(claims, requestdto.CreateTicketRequest{ConversationID: conv.ID, Name: "Concurrent Ticket"})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	}
+
+	var numbers []string
+	db.Model(&models.TicketModel{}).
+		Where("organization_id = ?", org.ID).
+		Order("ticket_number ASC").
+		Pluck("ticket_number", &numbers)
+	if len(numbers) != workers {
+		t.Fatalf("expected %d tickets, got %d", workers, len(numbers))
+	}
+	prefix := "TKT-" + time.Now().Format("20060102") + "-"
+	for i, number := range numbers {
+		if want := fmt.Sprintf("%s%04d", prefix, i+1); number != want {
+			t.Errorf("expected %s, got %s", want, number)
+		}
+	}
+}
+
+func createTicketStaff(tx *gorm.DB, t *testing.T, orgID uint, email string) *models.UserModel {
+	salesRole, err := GetOrCreateRole(tx, models.RoleOrganizationSales)
+	if err != nil {
+		t.Fatalf("failed to get or create sales role: %v", err)
+	}
+	staff := models.UserModel{
+		Email:          email,
+		Name:           "Sales",
+		Password:       "password123",
+		RoleID:         salesRole.ID,
+		OrganizationID: &orgID,
+	}
+	if err := tx.Create(&staff).Error; err != nil {
+		t.Fatalf("failed to create staff: %v", err)
+	}
+	return &staff
+}
+
+func TestOrganizationTicketService_Assignee(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewTicketService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	otherOrg, _ := CreateTestOrganizationWithOwner(tx, t, "Other Org")
+	conv := createTicketConversation(tx, t, org.ID)
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+
+	sales := createTicketStaff(tx, t, org.ID, "sales@test.com")
+	outsider := createTicketStaff(tx, t, otherOrg.ID, "outsider@test.com")
+
+	err := service.CreateTicket(claims, requestdto.CreateTicketRequest{
+		ConversationID: conv.ID,
+		Name:           "Wrong assignee",
+		AssigneeID:     &outsider.ID,
+	})
+	if !errors.Is(err, impl.ErrTicketAssigneeInvalid) {
+		t.Errorf("expected ErrTicketAssigneeInvalid, got %v", err)
+	}
+
+	if err := service.CreateTicket(claims, requestdto.CreateTicketRequest{
+		ConversationID: conv.ID,
+		Name:           "Assigned",
+		AssigneeID:     &sales.ID,
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var ticket models.TicketModel
+	tx.Where("organization_id = ? AND name = ?", org.ID, "Assigned").First(&ticket)
+	if ticket.AssigneeID == nil || *ticket.AssigneeID != sales.ID || ticket.Priority != models.TicketPriorityNormal {
+		t.Errorf("expected a normal ticket assigned to sales, got %+v", ticket)
+	}
+
+	err = service.UpdateTicket(claims, ticket.ID, requestdto.UpdateTicketRequest{AssigneeID: &outsider.ID})
+	if !errors.Is(err, impl.ErrTicketAssigneeInvalid) {
+		t.Errorf("expected ErrTicketAssigneeInvalid, got %v", err)
+	}
+
+	unassign := uint(0)
+	if err := service.UpdateTicket(claims, ticket.ID, requestdto.UpdateTicketRequest{AssigneeID: &unassign}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	tx.First(&ticket, ticket.ID)
+	if ticket.AssigneeID != nil {
+		t.Errorf("expected the assignee to be removed, got %v", *ticket.AssigneeID)
+	}
+}
+
+func TestOrganizationTicketService_GetTicketsList_Filters(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewTicketService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	conv := createTicketConversation(tx, t, org.ID)
+	sales := createTicketStaff(tx, t, org.ID, "sales@test.com")
+	ownerClaims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+	salesClaims := &jwtLib.Claims{UserID: sales.ID, RoleID: sales.RoleID, OrganizationId: &org.ID}
+
+	yesterday := time.Now().Add(-24 * time.Hour)
+	billing := "billing"
+	tickets := []models.TicketModel{
+		{TicketNumber: "TEST-001", Name: "Late", Status: models.TicketStatusPending, Priority: models.TicketPriorityUrgent, DueAt: &yesterday, Category: &billing, AssigneeID: &sales.ID},
+		{TicketNumber: "TEST-002", Name: "Late but done", Status: models.TicketStatusDone, StatusCategory: models.TicketStatusCategoryClosed, Priority: models.TicketPriorityNormal, DueAt: &yesterday},
+		{TicketNumber: "TEST-003", Name: "Unassigned", Status: models.TicketStatusPending, Priority: models.TicketPriorityLow},
+	}
+	for i := range tickets {
+		tickets[i].OrganizationID = org.ID
+		tickets[i].ConversationID = conv.ID
+		tickets[i].CreatedByID = owner.ID
+		tx.Create(&tickets[i])
+	}
+
+	page, limit := 1, 10
+	filter := filtersdto.FiltersDto{Page: &page, Limit: &limit}
+	cases := []struct {
+		name   string
+		claims *jwtLib.Claims
+		filter filtersdto.TicketFiltersDto
+		want   []string
+	}{
+		{"owner sees all", ownerClaims, filtersdto.TicketFiltersDto{}, []string{"Late", "Late but done", "Unassigned"}},
+		{"sales sees own", salesClaims, filtersdto.TicketFiltersDto{}, []string{"Late"}},
+		{"sales picks assignee", salesClaims, filtersdto.TicketFiltersDto{AssigneeID: &owner.ID}, []string{}},
+		{"priority", ownerClaims, filtersdto.TicketFiltersDto{Priority: models.TicketPriorityLow}, []string{"Unassigned"}},
+		{"category", ownerClaims, filtersdto.TicketFiltersDto{Category: "billing"}, []string{"Late"}},
+		{"overdue", ownerClaims, filtersdto.TicketFiltersDto{Overdue: true}, []string{"Late"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := service.GetTicketsList(tc.claims, filter, tc.filter)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			names := map[string]bool{}
+			for _, ticket := range result.Tickets {
+				names[ticket.Name] = true
+			}
+			if len(names) != len(tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, names)
+			}
+			for _, name := range tc.want {
+				if !names[name] {
+					t.Errorf("expected %s in %v", name, names)
+				}
+			}
+		})
+	}
+
+	result, _ := service.GetTicketsList(ownerClaims, filter, filtersdto.TicketFiltersDto{Overdue: true})
+	if len(result.Tickets) == 1 && !result.Tickets[0].Overdue {
+		t.Errorf("expected the ticket to be flagged overdue, got %+v", result.Tickets[0])
+	}
+}
+
+func TestOrganizationTicketService_CommentsAndActivity(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewTicketService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	conv := createTicketConversation(tx, t, org.ID)
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+
+	if err := service.CreateTicket(claims, requestdto.CreateTicketRequest{ConversationID: conv.ID, Name: "Broken login"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var ticket models.TicketModel
+	tx.Where("organization_id = ?", org.ID).First(&ticket)
+
+	if err := service.UpdateTicket(claims, ticket.ID, requestdto.UpdateTicketRequest{
+		Status:   models.TicketStatusInProgress,
+		Priority: models.TicketPriorityHigh,
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, err := service.CreateComment(claims, ticket.ID, requestdto.CreateTicketCommentRequest{
+		Visibility: models.TicketCommentPublic,
+		Body:       " \n\t ",
+	}); !errors.Is(err, impl.ErrTicketCommentEmpty) {
+		t.Errorf("expected ErrTicketCommentEmpty for a blank body, got %v", err)
+	}
+
+	internal, err := service.CreateComment(claims, ticket.ID, requestdto.CreateTicketCommentRequest{
+		Visibility: models.TicketCommentInternal,
+		Body:       "looks like the reset mail bounced",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if internal.MessageID != nil {
+		t.Errorf("expected an internal comment to stay off the conversation, got message %d", *internal.MessageID)
+	}
+
+	public, err := service.CreateComment(claims, ticket.ID, requestdto.CreateTicketCommentRequest{
+		Visibility: models.TicketCommentPublic,
+		Body:       "We are looking into it.",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if public.MessageID == nil {
+		t.Fatal("expected the public comment to be sent to the customer")
+	}
+	var message models.ConversationMessageModel
+	tx.First(&message, *public.MessageID)
+	if message.ConversationID != conv.ID || message.Message != "We are looking into it." {
+		t.Errorf("expected the comment in the ticket's conversation, got %+v", message)
+	}
+
+	detail, err := service.GetTicket(claims, ticket.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(detail.Comments) != 2 || detail.Comments[0].Visibility != models.TicketCommentInternal {
+		t.Errorf("expected both comments oldest first, got %+v", detail.Comments)
+	}
+
+	changes := map[string]string{}
+	actions := map[string]int{}
+	for _, activity := range detail.Activity {
+		actions[activity.Action]++
+		if activity.ActorID == nil || *activity.ActorID != owner.ID {
+			t.Errorf("expected the owner as actor, got %+v", activity)
+		}
+		if activity.Field != nil && activity.NewValue != nil {
+			changes[*activity.Field] = *activity.NewValue
+		}
+	}
+	if actions[models.TicketActivityCreated] != 1 || actions[models.TicketActivityUpdated] != 2 || actions[models.TicketActivityCommented] != 2 {
+		t.Errorf("expected created, two updates and two comments, got %v", actions)
+	}
+	if changes["status"] != models.TicketStatusInProgress || changes["priority"] != models.TicketPriorityHigh {
+		t.Errorf("expected the status and priority changes, got %v", changes)
+	}
+
+	var activity models.TicketActivityModel
+	tx.Where("ticket_id = ?", ticket.ID).First(&activity)
+	if err := tx.Model(&activity).Update("action", "tampered").Error; !errors.Is(err, models.ErrTicketActivityImmutable) {
+		t.Errorf("expected ErrTicketActivityImmutable on update, got %v", err)
+	}
+	if err := tx.Delete(&activity).Error; !errors.Is(err, models.ErrTicketActivityImmutable) {
+		t.Errorf("expected ErrTicketActivityImmutable on delete, got %v", err)
+	}
+}
+
+func TestOrganizationTicketService_GetTicket_OtherOrganization(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewTicketService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	otherOrg, otherOwner := CreateTestOrganizationWithOwner(tx, t, "Other Org")
+	conv := createTicketConversation(tx, t, org.ID)
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+	otherClaims := &jwtLib.Claims{UserID: otherOwner.ID, RoleID: otherOwner.RoleID, OrganizationId: &otherOrg.ID}
+
+	if err := service.CreateTicket(claims, requestdto.CreateTicketRequest{ConversationID: conv.ID, Name: "Private ticket"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var ticket models.TicketModel
+	tx.Where("organization_id = ?", org.ID).First(&ticket)
+
+	if _, err := service.GetTicket(otherClaims, ticket.ID); !errors.Is(err, impl.ErrTicketNotFound) {
+		t.Errorf("expected ErrTicketNotFound, got %v", err)
+	}
+	if _, err := service.CreateComment(otherClaims, ticket.ID, requestdto.CreateTicketCommentRequest{
+		Visibility: models.TicketCommentPublic,
+		Body:       "hello",
+	}); !errors.Is(err, impl.ErrTicketNotFound) {
+		t.Errorf("expected ErrTicketNotFound, got %v", err)
+	}
+}
+
+func TestOrganizationTicketService_Workflow(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewTicketService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	conv := createTicketConversation(tx, t, org.ID)
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+
+	workflow, err := service.GetWorkflow(claims)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if workflow.InitialStatus != models.TicketStatusPending || len(workflow.Statuses) != 3 || len(workflow.Transitions) != 6 {
+		t.Errorf("expected the default workflow, got %+v", workflow)
+	}
+
+	if err := service.CreateTicket(claims, requestdto.CreateTicketRequest{ConversationID: conv.ID, Name: "Old pending"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	pipeline := requestdto.UpdateTicketWorkflowRequest{
+		InitialStatus: "new",
+		Statuses: []requestdto.TicketWorkflowStatusRequest{
+			{Key: "new", Name: "New", Category: models.TicketStatusCategoryOpen},
+			{Key: "triage", Name: "Triage", Category: models.TicketStatusCategoryOpen},
+			{Key: "waiting_on_customer", Name: "Waiting on customer", Category: models.TicketStatusCategoryOpen},
+			{Key: "resolved", Name: "Resolved", Category: models.TicketStatusCategoryClosed, RequiresResolutionNote: true},
+			{Key: "closed", Name: "Closed", Category: models.TicketStatusCategoryClosed},
+		},
+		Transitions: []requestdto.TicketWorkflowTransitionRequest{
+			{From: "new", To: "triage"},
+			{From: "triage", To: "waiting_on_customer"},
+			{From: "waiting_on_customer", To: "triage"},
+			{From: "triage", To: "resolved"},
+			{From: "resolved", To: "closed"},
+		},
+	}
+	if _, err := service.UpdateWorkflow(claims, pipeline); !errors.Is(err, impl.ErrTicketWorkflowStatusInUse) {
+		t.Fatalf("expected ErrTicketWorkflowStatusInUse, got %v", err)
+	}
+
+	pipeline.Statuses = append(pipeline.Statuses, requestdto.TicketWorkflowStatusRequest{
+		Key: models.TicketStatusPending, Name: "Pending", Category: models.TicketStatusCategoryOpen,
+	})
+	pipeline.Transitions = append(pipeline.Transitions, requestdto.TicketWorkflowTransitionRequest{From: models.TicketStatusPending, To: "triage"})
+	if _, err := service.UpdateWorkflow(claims, pipeline); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := service.CreateTicket(claims, requestdto.CreateTicketRequest{ConversationID: conv.ID, Name: "New pipeline"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var ticket models.TicketModel
+	tx.Where("organization_id = ? AND name = ?", org.ID, "New pipeline").First(&ticket)
+	if ticket.Status != "new" || ticket.StatusCategory != models.TicketStatusCategoryOpen {
+		t.Fatalf("expected the ticket to start as new, got %s (%s)", ticket.Status, ticket.StatusCategory)
+	}
+
+	if err := service.UpdateTicket(claims, ticket.ID, requestdto.UpdateTicketRequest{Status: "resolved"}); !errors.Is(err, impl.ErrTicketTransitionNotAllowed) {
+		t.Errorf("expected ErrTicketTransitionNotAllowed, got %v", err)
+	}
+	if err := service.UpdateTicket(claims, ticket.ID, requestdto.UpdateTicketRequest{Status: "escalated"}); !errors.Is(err, impl.ErrTicketStatusInvalid) {
+		t.Errorf("expected ErrTicketStatusInvalid, got %v", err)
+	}
+	if err := service.UpdateTicket(claims, ticket.ID, requestdto.UpdateTicketRequest{Status: "triage"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := service.UpdateTicket(claims, ticket.ID, requestdto.UpdateTicketRequest{Status: "resolved"}); !errors.Is(err, impl.ErrTicketResolutionNoteRequired) {
+		t.Errorf("expected ErrTicketResolutionNoteRequired, got %v", err)
+	}
+
+	note := "Replaced the broken part"
+	if err := service.UpdateTicket(claims, ticket.ID, requestdto.UpdateTicketRequest{Status: "resolved", ResolutionNote: &note}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	tx.First(&ticket, ticket.ID)
+	if ticket.StatusCategory != models.TicketStatusCategoryClosed || ticket.ResolutionNote == nil || *ticket.ResolutionNote != note {
+		t.Errorf("expected a closed ticket with the resolution note, got %+v", ticket)
+	}
+
+	invalid := pipeline
+	invalid.InitialStatus = "closed"
+	if _, err := service.UpdateWorkflow(claims, invalid); !errors.Is(err, impl.ErrTicketWorkflowInvalid) {
+		t.Errorf("expected ErrTicketWorkflowInvalid, got %v", err)
+	}
+}
+
+func TestOrganizationTicketService_Links(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewTicketService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	otherOrg, otherOwner := CreateTestOrganizationWithOwner(tx, t, "Other Org")
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+	otherClaims := &jwtLib.Claims{UserID: otherOwner.ID, RoleID: otherOwner.RoleID, OrganizationId: &otherOrg.ID}
+
+	tickets := map[string]models.TicketModel{}
+	for _, name := range []string{"Outage", "Report A", "Report B", "Elsewhere"} {
+		orgClaims, orgID := claims, org.ID
+		if name == "Elsewhere" {
+			orgClaims, orgID = otherClaims, otherOrg.ID
+		}
+		conv := createTicketConversation(tx, t, orgID)
+		if err := service.CreateTicket(orgClaims, requestdto.CreateTicketRequest{ConversationID: conv.ID, Name: name}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		var ticket models.TicketModel
+		tx.Where("organization_id = ? AND name = ?", orgID, name).First(&ticket)
+		tickets[name] = ticket
+	}
+	outage, reportA, reportB := tickets["Outage"], tickets["Report A"], tickets["Report B"]
+
+	if _, err := service.CreateLink(claims, reportA.ID, requestdto.CreateTicketLinkRequest{TicketID: outage.ID, Type: models.TicketLinkChildOf}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	link, err := service.CreateLink(claims, outage.ID, requestdto.CreateTicketLinkRequest{TicketID: reportB.ID, Type: models.TicketLinkParentOf})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if link.Type != models.TicketLinkParentOf || link.Ticket.ID != reportB.ID {
+		t.Errorf("expected outage to be the parent of report B, got %+v", link)
+	}
+
+	if _, err := service.CreateLink(claims, outage.ID, requestdto.CreateTicketLinkRequest{TicketID: reportA.ID, Type: models.TicketLinkRelatedTo}); !errors.Is(err, impl.ErrTicketLinkExists) {
+		t.Errorf("expected ErrTicketLinkExists, got %v", err)
+	}
+	if _, err := service.CreateLink(claims, reportA.ID, requestdto.CreateTicketLinkRequest{TicketID: outage.ID, Type: models.TicketLinkParentOf}); !errors.Is(err, impl.ErrTicketLinkExists) {
+		t.Errorf("expected ErrTicketLinkExists, got %v", err)
+	}
+	if _, err := service.CreateLink(claims, reportA.ID, requestdto.CreateTicketLinkRequest{TicketID: reportB.ID, Type: models.TicketLinkParentOf}); !errors.Is(err, impl.ErrTicketLinkInvalid) {
+		t.Errorf("expected ErrTicketLinkInvalid for a second parent, got %v", err)
+	}
+	if _, err := service.CreateLink(claims, outage.ID, requestdto.CreateTicketLinkRequest{TicketID: outage.ID, Type: models.TicketLinkRelatedTo}); !errors.Is(err, impl.ErrTicketLinkInvalid) {
+		t.Errorf("expected ErrTicketLinkInvalid for a self link, got %v", err)
+	}
+	if _, err := service.CreateLink(claims, outage.ID, requestdto.CreateTicketLinkRequest{TicketID: tickets["Elsewhere"].ID, Type: models.TicketLinkRelatedTo}); !errors.Is(err, impl.ErrTicketNotFound) {
+		t.Errorf("expected ErrTicketNotFound for another organization's ticket, got %v", err)
+	}
+
+	detail, err := service.GetTicket(claims, reportA.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(detail.Links) != 1 || detail.Links[0].Type != models.TicketLinkChildOf || detail.Links[0].Ticket.ID != outage.ID {
+		t.Errorf("expected report A to be a child of the outage, got %+v", detail.Links)
+	}
+
+	if err := service.DeleteLink(claims, reportB.ID, link.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := service.DeleteLink(claims, reportB.ID, link.ID); !errors.Is(err, impl.ErrTicketLinkNotFound) {
+		t.Errorf("expected ErrTicketLinkNotFound, got %v", err)
+	}
+}
+
+func TestOrganizationTicketService_CascadeAndNotify(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewTicketService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+
+	conversations := map[string]*models.ConversationModel{}
+	tickets := map[string]models.TicketModel{}
+	for _, name := range []string{"Outage", "Child report", "Duplicate report"} {
+		conv := createTicketConversation(tx, t, org.ID)
+		conversations[name] = conv
+		if err := service.CreateTicket(claims, requestdto.CreateTicketRequest{ConversationID: conv.ID, Name: name}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		var ticket models.TicketModel
+		tx.Where("organization_id = ? AND name = ?", org.ID, name).First(&ticket)
+		tickets[name] = ticket
+	}
+	outage := tickets["Outage"]
+
+	extra := createTicketConversation(tx, t, org.ID)
+	if _, err := service.AddConversation(claims, outage.ID, requestdto.AddTicketConversationRequest{ConversationID: extra.ID}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := service.AddConversation(claims, outage.ID, requestdto.AddTicketConversationRequest{ConversationID: extra.ID}); !errors.Is(err, impl.ErrTicketConversationExists) {
+		t.Errorf("expected ErrTicketConversationExists, got %v", err)
+	}
+	if err := service.RemoveConversation(claims, outage.ID, outage.ConversationID); !errors.Is(err, impl.ErrTicketConversationPrimary) {
+		t.Errorf("expected ErrTicketConversationPrimary, got %v", err)
+	}
+
+	if _, err := service.CreateLink(claims, outage.ID, requestdto.CreateTicketLinkRequest{TicketID: tickets["Child report"].ID, Type: models.TicketLinkParentOf}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := service.CreateLink(claims, tickets["Duplicate report"].ID, requestdto.CreateTicketLinkRequest{TicketID: outage.ID, Type: models.TicketLinkDuplicateOf}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := service.UpdateTicket(claims, outage.ID, requestdto.UpdateTicketRequest{Status: models.TicketStatusInProgress}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// customers are only told when asked for
+	if err := service.UpdateTicket(claims, outage.ID, requestdto.UpdateTicketRequest{Status: models.TicketStatusDone}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var sent int64
+	tx.Model(&models.ConversationMessageModel{}).
+		Where("conversation_id = ? AND created_by_id = ?", outage.ConversationID, owner.ID).
+		Count(&sent)
+	if sent != 0 {
+		t.Errorf("expected no message without notifyCustomers, got %d", sent)
+	}
+	if err := service.UpdateTicket(claims, outage.ID, requestdto.UpdateTicketRequest{Status: models.TicketStatusInProgress}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	note := "root cause was the staging database"
+	if err := service.UpdateTicket(claims, outage.ID, requestdto.UpdateTicketRequest{
+		Status:            models.TicketStatusDone,
+		ResolutionNote:    &note,
+		CascadeToChildren: true,
+		NotifyCustomers:   true,
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var child models.TicketModel
+	tx.First(&child, tickets["Child report"].ID)
+	if child.Status != models.TicketStatusDone || child.StatusCategory != models.TicketStatusCategoryClosed {
+		t.Errorf("expected the child to follow its parent, got %s (%s)", child.Status, child.StatusCategory)
+	}
+	var duplicate models.TicketModel
+	tx.First(&duplicate, tickets["Duplicate report"].ID)
+	if duplicate.Status == models.TicketStatusDone {
+		t.Error("expected the duplicate to keep its status")
+	}
+
+	for name, conv := range map[string]*models.ConversationModel{
+		"outage":    conversations["Outage"],
+		"extra":     extra,
+		"child":     conversations["Child report"],
+		"duplicate": conversations["Duplicate report"],
+	} {
+		var messages []models.ConversationMessageModel
+		tx.Where("conversation_id = ? AND created_by_id = ?", conv.ID, owner.ID).Find(&messages)
+		if len(messages) != 1 {
+			t.Errorf("expected the %s customer to be notified once, got %d messages", name, len(messages))
+			continue
+		}
+		if strings.Contains(messages[0].Message, note) {
+			t.Errorf("expected the %s message to leave out the resolution note, got %s", name, messages[0].Message)
+		}
+	}
+}
+
+func TestOrganizationTicketService_GetTicketsList_PaginationAndSort(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewTicketService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	sales := createTicketStaff(tx, t, org.ID, "sales@test.com")
+	conv := createTicketConversation(tx, t, org.ID)
+	other := createTicketConversation(tx, t, org.ID)
+	tx.Model(other).Update("organization_staff_id", sales.ID)
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+	salesClaims := &jwtLib.Claims{UserID: sales.ID, RoleID: sales.RoleID, OrganizationId: &org.ID}
+
+	priorities := []string{models.TicketPriorityLow, models.TicketPriorityUrgent, models.TicketPriorityNormal, models.TicketPriorityHigh, models.TicketPriorityNormal}
+	for i, priority := range priorities {
+		creator := claims
+		conversationID := conv.ID
+		if i == 4 {
+			creator, conversationID = salesClaims, other.ID
+		}
+		if err := service.CreateTicket(creator, requestdto.CreateTicketRequest{
+			ConversationID: conversationID,
+			Name:           fmt.Sprintf("Ticket %d", i+1),
+			Priority:       priority,
+			AssigneeID:     &sales.ID,
+		}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	page, limit := 2, 2
+	result, err := service.GetTicketsList(claims, filtersdto.FiltersDto{Page: &page, Limit: &limit}, filtersdto.TicketFiltersDto{Sort: "-priority,name"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Metadata.Total != 5 || result.Metadata.Page != 2 || result.Metadata.Limit != 2 {
+		t.Errorf("expected total 5 on page 2 of 2, got %+v", result.Metadata)
+	}
+	if len(result.Tickets) != 2 || result.Tickets[0].Name != "Ticket 3" || result.Tickets[1].Name != "Ticket 5" {
+		t.Errorf("expected the normal tickets on page 2, got %+v", result.Tickets)
+	}
+
+	page, limit = 1, 500
+	result, err = service.GetTicketsList(claims, filtersdto.FiltersDto{Page: &page, Limit: &limit}, filtersdto.TicketFiltersDto{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Metadata.Limit != 100 {
+		t.Errorf("expected the page size to be capped at 100, got %d", result.Metadata.Limit)
+	}
+
+	cases := []struct {
+		name     string
+		filter   filtersdto.TicketFiltersDto
+		expected int
+	}{
+		{"creator", filtersdto.TicketFiltersDto{CreatedByID: &sales.ID}, 1},
+		{"conversation", filtersdto.TicketFiltersDto{ConversationID: &other.ID}, 1},
+		{"status", filtersdto.TicketFiltersDto{Status: []string{models.TicketStatusPending, models.TicketStatusDone}}, 5},
+		{"status category", filtersdto.TicketFiltersDto{StatusCategory: models.TicketStatusCategoryClosed}, 0},
+		{"number prefix", filtersdto.TicketFiltersDto{NumberPrefix: models.TicketNumberDefaultPrefix}, 5},
+		{"number prefix wildcard", filtersdto.TicketFiltersDto{NumberPrefix: "%"}, 0},
+		{"created range", filtersdto.TicketFiltersDto{CreatedFrom: ptrTime(time.Now().Add(-time.Hour)), CreatedTo: ptrTime(time.Now().Add(time.Hour))}, 5},
+		{"created in the future", filtersdto.TicketFiltersDto{CreatedFrom: ptrTime(time.Now().Add(time.Hour))}, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := service.GetTicketsList(claims, filtersdto.FiltersDto{Page: &page, Limit: &limit}, tc.filter)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if result.Metadata.Total != tc.expected || len(result.Tickets) != tc.expected {
+				t.Errorf("expected %d tickets, got total %d and %d on the page", tc.expected, result.Metadata.Total, len(result.Tickets))
+			}
+		})
+	}
+
+	if _, err := service.GetTicketsList(claims, filtersdto.FiltersDto{Page: &page, Limit: &limit}, filtersdto.TicketFiltersDto{Sort: "password"}); !errors.Is(err, impl.ErrTicketSortInvalid) {
+		t.Errorf("expected ErrTicketSortInvalid, got %v", err)
+	}
+}
+
+func ptrTime(value time.Time) *time.Time {
+	return &value
+}
+
+func TestOrganizationTicketService_CSVExportImport(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewTicketService(tx)
+	orgService := impl.NewOrganizationService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	otherOrg, _ := CreateTestOrganizationWithOwner(tx, t, "Other Org")
+	sales := createTicketStaff(tx, t, org.ID, "sales@test.com")
+	conv := createTicketConversation(tx, t, org.ID)
+	otherConv := createTicketConversation(tx, t, otherOrg.ID)
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+
+	if _, err := orgService.UpdateSettings(claims, requestdto.UpdateOrganizationSettingsRequest{Timezone: "Asia/Jakarta"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	input := strings.Join([]string{
+		"conversation_id,name,priority,due_at,assignee_email",
+		fmt.Sprintf("%d,Refund order,high,2026-03-01 09:00:00,SALES@test.com", conv.ID),
+		fmt.Sprintf("%d,Other org,low,,", otherConv.ID),
+		fmt.Sprintf("%d,No,urgent,tomorrow,nobody@test.com", conv.ID),
+		"",
+	}, "\n")
+
+	result, err := service.ImportTickets(claims, strings.NewReader(input), false)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Total != 3 || result.Valid != 1 || result.Created != 0 {
+		t.Errorf("expected 1 valid row of 3 and nothing created, got %+v", result)
+	}
+	columns := map[string]int{}
+	for _, rowError := range result.Errors {
+		columns[fmt.Sprintf("%d:%s", rowError.Row, rowError.Column)]++
+	}
+	for _, expected := range []string{"3:conversation_id", "4:name", "4:due_at", "4:assignee_email"} {
+		if columns[expected] != 1 {
+			t.Errorf("expected an error at %s, got %+v", expected, result.Errors)
+		}
+	}
+
+	valid := strings.Join(strings.Split(input, "\n")[:2], "\n")
+	result, err = service.ImportTickets(claims, strings.NewReader(valid), true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var count int64
+	tx.Model(&models.TicketModel{}).Where("organization_id = ?", org.ID).Count(&count)
+	if result.Valid != 1 || result.Created != 0 || count != 0 {
+		t.Errorf("expected a dry run to create nothing, got %+v and %d tickets", result, count)
+	}
+
+	result, err = service.ImportTickets(claims, strings.NewReader(valid), false)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Created != 1 {
+		t.Fatalf("expected 1 created ticket, got %+v", result)
+	}
+
+	var ticket models.TicketModel
+	tx.Where("organization_id = ?", org.ID).First(&ticket)
+	if ticket.TicketNumber == "" || ticket.AssigneeID == nil || *ticket.AssigneeID != sales.ID || ticket.Priority != models.TicketPriorityHigh {
+		t.Errorf("expected a numbered high priority ticket assigned to sales, got %+v", ticket)
+	}
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	if ticket.DueAt == nil || !ticket.DueAt.Equal(time.Date(2026, 3, 1, 9, 0, 0, 0, jakarta)) {
+		t.Errorf("expected the due date in the organization timezone, got %v", ticket.DueAt)
+	}
+
+	if _, err := service.ImportTickets(claims, strings.NewReader("name\nMissing conversation"), true); !errors.Is(err, impl.ErrTicketImportInvalid) {
+		t.Errorf("expected ErrTicketImportInvalid, got %v", err)
+	}
+
+	var output bytes.Buffer
+	if err := service.ExportTickets(claims, filtersdto.TicketFiltersDto{}, &output); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	records, err := csv.NewReader(&output).ReadAll()
+	if err != nil {
+		t.Fatalf("expected valid CSV, got %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected a header and 1 row, got %d records", len(records))
+	}
+	row := map[string]string{}
+	for i, column := range records[0] {
+		row[column] = records[1][i]
+	}
+	if row["ticket_number"] != ticket.TicketNumber || row["assignee_email"] != sales.Email || row["due_at"] != "2026-03-01 09:00:00" {
+		t.Errorf("expected the ticket with times in the organization timezone, got %v", row)
+	}
+
+	// an existing number is skipped, a number twice in the file is an error
+	numbered := strings.Join([]string{
+		"ticket_number,conversation_id,name",
+		fmt.Sprintf("%s,%d,Refund order", ticket.TicketNumber, conv.ID),
+		fmt.Sprintf("TCK-OLD-1,%d,Old ticket", conv.ID),
+		fmt.Sprintf("TCK-OLD-1,%d,Old ticket again", conv.ID),
+	}, "\n")
+	result, err = service.ImportTickets(claims, strings.NewReader(numbered), true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].Row != 2 || result.Skipped[0].TicketID != ticket.ID {
+		t.Errorf("expected row 2 to be skipped as the existing ticket, got %+v", result.Skipped)
+	}
+	if result.Total != 3 || result.Valid != 1 || len(result.Errors) != 1 || result.Errors[0].Row != 4 || result.Errors[0].Column != "ticket_number" {
+		t.Errorf("expected the repeated number on row 4 to fail, got %+v", result)
+	}
+
+	if err := service.ExportTickets(claims, filtersdto.TicketFiltersDto{Sort: "password"}, &output); !errors.Is(err, impl.ErrTicketSortInvalid) {
+		t.Errorf("expected ErrTicketSortInvalid, got %v", err)
+	}
+}
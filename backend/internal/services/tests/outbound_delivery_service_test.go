@@ -0,0 +1,238 @@
+package tests
+
+import (
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/outbound"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// fakeDispatcher answers every delivery with the configured error
+type fakeDispatcher struct {
+	channel    string
+	err        error
+	dispatched []outbound.Delivery
+}
+
+func (f *fakeDispatcher) Channel() string {
+	return f.channel
+}
+
+func (f *fakeDispatcher) Dispatch(delivery outbound.Delivery) (*outbound.Result, error) {
+	f.dispatched = append(f.dispatched, delivery)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &outbound.Result{ProviderMessageID: "provider-1"}, nil
+}
+
+func createWebhookReply(tx *gorm.DB, t *testing.T) (*jwtLib.Claims, *models.ConversationModel) {
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	setWebhookCallbackHosts(tx, t, org, "integration.example.com")
+
+	guestRole, _ := GetOrCreateRole(tx, models.RoleGuest)
+	guest := models.UserModel{
+		Email:  "guest@test.com",
+		Name:   "Guest",
+		RoleID: guestRole.ID,
+	}
+	tx.Create(&guest)
+
+	callbackURL := "https://integration.example.com/replies"
+	contact := CreateTestContact(tx, t, org.ID, &guest)
+	conv := models.ConversationModel{
+		OrganizationID: org.ID,
+		GuestID:        &guest.ID,
+		ContactID:      contact.ID,
+		Status:         models.ConversationStatusPending,
+		Channel:        models.ConversationChannelWebhook,
+		CallbackURL:    &callbackURL,
+	}
+	tx.Create(&conv)
+
+	claims := &jwtLib.Claims{
+		UserID:         owner.ID,
+		RoleID:         owner.RoleID,
+		OrganizationId: &org.ID,
+	}
+
+	err := impl.NewConversationService(tx).SendMessage(claims, conv.ID, requestdto.CreateStaffMessageRequest{
+		Message: "Thanks for reaching out",
+	})
+	if err != nil {
+		t.Fatalf("failed to send message: %v", err)
+	}
+
+	return claims, &conv
+}
+
+func TestOutboundDeliveryService_ProcessDueDeliveries_MarksSent(t *testing.T) {
+	tx := SetupTestDB(t)
+	_, conv := createWebhookReply(tx, t)
+
+	dispatcher := &fakeDispatcher{channel: models.ConversationChannelWebhook}
+	service := impl.NewOutboundDeliveryService(tx, outbound.NewRegistry(dispatcher), 3)
+
+	if _, err := service.ProcessDueDeliveries(10); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(dispatcher.dispatched) != 1 {
+		t.Fatalf("expected one dispatch, got %d", len(dispatcher.dispatched))
+	}
+	if dispatcher.dispatched[0].Destination != *conv.CallbackURL {
+		t.Errorf("expected destination %s, got %s", *conv.CallbackURL, dispatcher.dispatched[0].Destination)
+	}
+
+	var delivery models.OutboundDeliveryModel
+	tx.Where("conversation_id = ?", conv.ID).First(&delivery)
+	if delivery.Status != models.OutboundStatusSent {
+		t.Errorf("expected status sent, got %s", delivery.Status)
+	}
+}
+
+func TestOutboundDeliveryService_ProcessDueDeliveries_DeadLetterAfterMaxAttempts(t *testing.T) {
+	tx := SetupTestDB(t)
+	_, conv := createWebhookReply(tx, t)
+
+	dispatcher := &fakeDispatcher{
+		channel: models.ConversationChannelWebhook,
+		err:     errors.New("callback answered 500"),
+	}
+	service := impl.NewOutboundDeliveryService(tx, outbound.NewRegistry(dispatcher), 2)
+
+	service.ProcessDueDeliveries(10)
+
+	var delivery models.OutboundDeliveryModel
+	tx.Where("conversation_id = ?", conv.ID).First(&delivery)
+	if delivery.Status != models.OutboundStatusFailed {
+		t.Fatalf("expected status failed, got %s", delivery.Status)
+	}
+	if delivery.LastError == nil || *delivery.LastError != "callback answered 500" {
+		t.Errorf("expected provider error to be recorded, got %v", delivery.LastError)
+	}
+
+	// make the retry due right away instead of waiting for the backoff
+	tx.Model(&delivery).Update("next_attempt_at", delivery.CreatedAt)
+	service.ProcessDueDeliveries(10)
+
+	tx.First(&delivery, delivery.ID)
+	if delivery.Status != models.OutboundStatusDeadLetter {
+		t.Errorf("expected status dead_letter, got %s", delivery.Status)
+	}
+	if delivery.Attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", delivery.Attempts)
+	}
+}
+
+func TestOutboundDeliveryService_HandleReceipt(t *testing.T) {
+	tx := SetupTestDB(t)
+	_, conv := createWebhookReply(tx, t)
+
+	service := impl.NewOutboundDeliveryService(tx, outbound.NewRegistry(), 3)
+
+	var delivery models.OutboundDeliveryModel
+	tx.Where("conversation_id = ?", conv.ID).First(&delivery)
+
+	err := service.HandleReceipt(requestdto.DeliveryReceiptRequest{
+		DeliveryID: delivery.ID,
+		Token:      "wrong-token",
+		Status:     models.OutboundStatusDelivered,
+	})
+	if !errors.Is(err, impl.ErrInvalidReceiptToken) {
+		t.Fatalf("expected invalid token error, got %v", err)
+	}
+
+	err = service.HandleReceipt(requestdto.DeliveryReceiptRequest{
+		DeliveryID: delivery.ID,
+		Token:      delivery.ReceiptToken,
+		Status:     models.OutboundStatusDelivered,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tx.First(&delivery, delivery.ID)
+	if delivery.Status != models.OutboundStatusDelivered {
+		t.Errorf("expected status delivered, got %s", delivery.Status)
+	}
+}
+
+func TestOutboundDeliveryService_RetryDelivery(t *testing.T) {
+	tx := SetupTestDB(t)
+	claims, conv := createWebhookReply(tx, t)
+
+	service := impl.NewOutboundDeliveryService(tx, outbound.NewRegistry(), 3)
+
+	var delivery models.OutboundDeliveryModel
+	tx.Where("conversation_id = ?", conv.ID).First(&delivery)
+
+	if err := service.RetryDelivery(claims, conv.ID, delivery.MessageID); !errors.Is(err, impl.ErrDeliveryNotRetryable) {
+		t.Fatalf("expected not retryable error, got %v", err)
+	}
+
+	tx.Model(&delivery).Updates(map[string]any{"status": models.OutboundStatusDeadLetter, "attempts": 3})
+
+	if err := service.RetryDelivery(claims, conv.ID, delivery.MessageID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tx.First(&delivery, delivery.ID)
+	if delivery.Status != models.OutboundStatusQueued || delivery.Attempts != 0 {
+		t.Errorf("expected delivery to be requeued, got %s with %d attempts", delivery.Status, delivery.Attempts)
+	}
+}
+
+func TestOutboundDeliveryService_EmailDispatcherSendsQueuedReply(t *testing.T) {
+	tx := SetupTestDB(t)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	guestRole, _ := GetOrCreateRole(tx, models.RoleGuest)
+	guest := models.UserModel{
+		Email:  "guest@test.com",
+		Name:   "Guest",
+		RoleID: guestRole.ID,
+	}
+	tx.Create(&guest)
+
+	contact := CreateTestContact(tx, t, org.ID, &guest)
+	conv := models.ConversationModel{
+		OrganizationID: org.ID,
+		GuestID:        &guest.ID,
+		ContactID:      contact.ID,
+		Status:         models.ConversationStatusPending,
+		Channel:        models.ConversationChannelEmail,
+	}
+	tx.Create(&conv)
+
+	claims := &jwtLib.Claims{
+		UserID:         owner.ID,
+		RoleID:         owner.RoleID,
+		OrganizationId: &org.ID,
+	}
+	if err := impl.NewConversationService(tx).SendMessage(claims, conv.ID, requestdto.CreateStaffMessageRequest{
+		Message: "Hello from support",
+	}); err != nil {
+		t.Fatalf("failed to send message: %v", err)
+	}
+
+	mailer := &fakeMailer{}
+	registry := outbound.NewRegistry(outbound.NewEmailDispatcher(models.ConversationChannelEmail, mailer))
+	service := impl.NewOutboundDeliveryService(tx, registry, 3)
+
+	if _, err := service.ProcessDueDeliveries(10); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(mailer.sent) != 1 {
+		t.Fatalf("expected one mail sent, got %d", len(mailer.sent))
+	}
+	if mailer.sent[0].To[0] != guest.Email {
+		t.Errorf("expected mail to %s, got %v", guest.Email, mailer.sent[0].To)
+	}
+}
@@ -0,0 +1,175 @@
+package tests
+
+import (
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/ratelimit"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"testing"
+)
+
+func TestRateLimitService_Allow_DeniesContactOverLimit(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewRateLimitService(tx, ratelimit.NewMemoryStore(), impl.RateLimitDefaults{
+		OrganizationPerMinute: 100,
+		ContactPerMinute:      2,
+		IPPerMinute:           100,
+	})
+
+	org, _ := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+
+	subject := ratelimit.Subject{
+		Route:          "webhook_conversations",
+		OrganizationID: org.ID,
+		ContactKey:     "spam@example.com",
+		IP:             "10.0.0.1",
+	}
+
+	for i := 0; i < 2; i++ {
+		if decision, _ := service.Allow(subject); !decision.Allowed {
+			t.Fatalf("expected request %d to be allowed", i+1)
+		}
+	}
+
+	decision, scope := service.Allow(subject)
+	if decision.Allowed {
+		t.Fatal("expected third request to be denied")
+	}
+	if scope != models.RateLimitScopeContact {
+		t.Errorf("expected contact scope, got %s", scope)
+	}
+	if decision.RetryAfterSeconds() < 1 {
+		t.Errorf("expected retry after of at least one second, got %d", decision.RetryAfterSeconds())
+	}
+
+	subject.ContactKey = "other@example.com"
+	if decision, _ := service.Allow(subject); !decision.Allowed {
+		t.Error("expected another contact to be allowed")
+	}
+}
+
+func TestRateLimitService_Allow_ContactOverLimitKeepsOrganizationTokens(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewRateLimitService(tx, ratelimit.NewMemoryStore(), impl.RateLimitDefaults{
+		OrganizationPerMinute: 3,
+		ContactPerMinute:      1,
+		IPPerMinute:           0,
+	})
+
+	org, _ := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+
+	spammer := ratelimit.Subject{Route: "webhook_conversations", OrganizationID: org.ID, ContactKey: "spam@example.com"}
+	for i := 0; i < 10; i++ {
+		service.Allow(spammer)
+	}
+
+	// the spammer took one organization token, the denied requests none
+	for _, contact := range []string{"a@example.com", "b@example.com"} {
+		subject := ratelimit.Subject{Route: "webhook_conversations", OrganizationID: org.ID, ContactKey: contact}
+		if decision, scope := service.Allow(subject); !decision.Allowed {
+			t.Errorf("expected %s to be allowed, denied by the %s limit", contact, scope)
+		}
+	}
+}
+
+func TestRateLimitService_GuestConversationOrganizationID(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewRateLimitService(tx, ratelimit.NewMemoryStore(), impl.RateLimitDefaults{})
+
+	org, _ := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+	guestRole, _ := GetOrCreateRole(tx, models.RoleGuest)
+	guest := models.UserModel{Email: "guest@test.com", Name: "Guest", Password: "password", RoleID: guestRole.ID}
+	tx.Create(&guest)
+	intruder := models.UserModel{Email: "intruder@test.com", Name: "Intruder", Password: "password", RoleID: guestRole.ID}
+	tx.Create(&intruder)
+
+	contact := CreateTestContact(tx, t, org.ID, &guest)
+	conversation := models.ConversationModel{
+		OrganizationID: org.ID,
+		GuestID:        &guest.ID,
+		ContactID:      contact.ID,
+		Status:         models.ConversationStatusPending,
+	}
+	tx.Create(&conversation)
+
+	organizationID, err := service.GuestConversationOrganizationID(&jwtLib.Claims{UserID: guest.ID, RoleID: guest.RoleID}, conversation.ID)
+	if err != nil || organizationID != org.ID {
+		t.Errorf("expected the organization of the guest's conversation, got %d, %v", organizationID, err)
+	}
+	if organizationID, err := service.GuestConversationOrganizationID(&jwtLib.Claims{UserID: intruder.ID, RoleID: intruder.RoleID}, conversation.ID); err == nil {
+		t.Errorf("expected no organization for another guest's conversation, got %d", organizationID)
+	}
+}
+
+func TestRateLimitService_UpdateOrganizationLimits_AppliesOverride(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewRateLimitService(tx, ratelimit.NewMemoryStore(), impl.RateLimitDefaults{
+		OrganizationPerMinute: 100,
+		ContactPerMinute:      100,
+		IPPerMinute:           0,
+	})
+
+	org, _ := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+
+	limit := 1
+	result, err := service.UpdateOrganizationLimits(org.ID, requestdto.UpdateOrganizationRateLimitRequest{
+		OrganizationPerMinute: &limit,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !result.Custom || result.OrganizationPerMinute != 1 || result.ContactPerMinute != 100 {
+		t.Errorf("unexpected limits %+v", result)
+	}
+
+	subject := ratelimit.Subject{Route: "guest_messages", OrganizationID: org.ID, ContactKey: "1"}
+	if decision, _ := service.Allow(subject); !decision.Allowed {
+		t.Fatal("expected first request to be allowed")
+	}
+	decision, scope := service.Allow(subject)
+	if decision.Allowed {
+		t.Fatal("expected second request to be denied")
+	}
+	if scope != models.RateLimitScopeOrganization {
+		t.Errorf("expected organization scope, got %s", scope)
+	}
+
+	if _, err := service.GetOrganizationLimits(org.ID + 1000); !errors.Is(err, impl.ErrOrganizationNotFound) {
+		t.Errorf("expected ErrOrganizationNotFound, got %v", err)
+	}
+}
+
+func TestRateLimitService_FlushHits_PersistsCounts(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewRateLimitService(tx, ratelimit.NewMemoryStore(), impl.RateLimitDefaults{
+		OrganizationPerMinute: 1,
+		ContactPerMinute:      0,
+		IPPerMinute:           0,
+	})
+
+	org, _ := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+
+	subject := ratelimit.Subject{Route: "webhook_conversations", OrganizationID: org.ID}
+	for i := 0; i < 4; i++ {
+		service.Allow(subject)
+	}
+
+	if err := service.FlushHits(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	page, limit := 1, 10
+	result, err := service.GetHits(filtersdto.FiltersDto{Page: &page, Limit: &limit}, &org.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result.Data) != 1 {
+		t.Fatalf("expected one hit row, got %d", len(result.Data))
+	}
+	if result.Data[0].Hits != 3 || result.Data[0].Scope != models.RateLimitScopeOrganization {
+		t.Errorf("unexpected hit row %+v", result.Data[0])
+	}
+}
@@ -0,0 +1,290 @@
+package tests
+
+import (
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/outbound"
+	"DewaSRY/sociomile-app/pkg/models"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStaffAccess_ConversationViews(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewConversationService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	sales := createTicketStaff(tx, t, org.ID, "sales@test.com")
+	colleague := createTicketStaff(tx, t, org.ID, "colleague@test.com")
+	ownerClaims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+	salesClaims := &jwtLib.Claims{UserID: sales.ID, RoleID: sales.RoleID, OrganizationId: &org.ID}
+
+	mine := createTicketConversation(tx, t, org.ID)
+	tx.Model(mine).Update("organization_staff_id", sales.ID)
+	theirs := createTicketConversation(tx, t, org.ID)
+	tx.Model(theirs).Update("organization_staff_id", colleague.ID)
+	unassigned := createTicketConversation(tx, t, org.ID)
+
+	page, limit := 1, 10
+	filter := filtersdto.FiltersDto{Page: &page, Limit: &limit}
+	tests := []struct {
+		name   string
+		claims *jwtLib.Claims
+		view   string
+		want   []uint
+	}{
+		{"owner default", ownerClaims, "", []uint{mine.ID, theirs.ID, unassigned.ID}},
+		{"owner all", ownerClaims, "all", []uint{mine.ID, theirs.ID, unassigned.ID}},
+		{"owner unassigned", ownerClaims, "unassigned", []uint{unassigned.ID}},
+		{"owner mine", ownerClaims, "mine", []uint{}},
+		{"sales default", salesClaims, "", []uint{mine.ID}},
+		{"sales mine", salesClaims, "mine", []uint{mine.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.GetConversationsList(tt.claims, filter, filtersdto.ConversationFiltersDto{View: tt.view})
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			got := map[uint]bool{}
+			for _, conversation := range result.Conversations {
+				got[conversation.ID] = true
+			}
+			if result.Metadata.Total != len(tt.want) || len(got) != len(tt.want) {
+				t.Fatalf("expected %d conversations, got %+v", len(tt.want), result.Conversations)
+			}
+			for _, id := range tt.want {
+				if !got[id] {
+					t.Errorf("expected conversation %d in the view", id)
+				}
+			}
+		})
+	}
+
+	for _, view := range []string{"unassigned", "all"} {
+		if _, err := service.GetConversationsList(salesClaims, filter, filtersdto.ConversationFiltersDto{View: view}); !errors.Is(err, impl.ErrConversationViewNotPermitted) {
+			t.Errorf("%s: expected ErrConversationViewNotPermitted for sales, got %v", view, err)
+		}
+	}
+
+	guestRole, _ := GetOrCreateRole(tx, models.RoleGuest)
+	guestClaims := &jwtLib.Claims{UserID: owner.ID, RoleID: guestRole.ID, OrganizationId: &org.ID}
+	if _, err := service.GetConversationsList(guestClaims, filter, filtersdto.ConversationFiltersDto{}); !errors.Is(err, impl.ErrConversationViewNotPermitted) {
+		t.Errorf("expected ErrConversationViewNotPermitted for a guest, got %v", err)
+	}
+	if _, err := service.GetConversationByID(guestClaims, mine.ID); !errors.Is(err, impl.ErrConversationNotFound) {
+		t.Errorf("expected ErrConversationNotFound for a guest, got %v", err)
+	}
+}
+
+func TestStaffAccess_SalesConversations(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewConversationService(tx)
+	deliveries := impl.NewOutboundDeliveryService(tx, outbound.NewRegistry(), 3)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	sales := createTicketStaff(tx, t, org.ID, "sales@test.com")
+	colleague := createTicketStaff(tx, t, org.ID, "colleague@test.com")
+	ownerClaims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+	salesClaims := &jwtLib.Claims{UserID: sales.ID, RoleID: sales.RoleID, OrganizationId: &org.ID}
+
+	mine := createTicketConversation(tx, t, org.ID)
+	tx.Model(mine).Update("organization_staff_id", sales.ID)
+	theirs := createTicketConversation(tx, t, org.ID)
+	tx.Model(theirs).Update("organization_staff_id", colleague.ID)
+	unassigned := createTicketConversation(tx, t, org.ID)
+
+	message := models.ConversationMessageModel{OrganizationID: org.ID, ConversationID: theirs.ID, CreatedByID: &colleague.ID, Message: "Hello"}
+	tx.Create(&message)
+	tx.Create(&models.OutboundDeliveryModel{
+		OrganizationID: org.ID,
+		ConversationID: theirs.ID,
+		MessageID:      message.ID,
+		Channel:        models.ConversationChannelEmail,
+		Destination:    "customer@example.com",
+		Payload:        []byte("{}"),
+		Status:         models.OutboundStatusFailed,
+		NextAttemptAt:  time.Now(),
+	})
+	if err := deliveries.RetryDelivery(salesClaims, theirs.ID, message.ID); !errors.Is(err, impl.ErrDeliveryNotFound) {
+		t.Errorf("expected ErrDeliveryNotFound for a colleague's conversation, got %v", err)
+	}
+	if err := deliveries.RetryDelivery(ownerClaims, theirs.ID, message.ID); err != nil {
+		t.Errorf("expected the owner to retry the delivery, got %v", err)
+	}
+
+	if _, err := service.GetConversationByID(salesClaims, mine.ID); err != nil {
+		t.Fatalf("expected the assigned conversation, got %v", err)
+	}
+	if err := service.SendMessage(salesClaims, mine.ID, requestdto.CreateStaffMessageRequest{Message: "On it"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for _, conversationID := range []uint{theirs.ID, unassigned.ID} {
+		for name, check := range map[string]func() error{
+			"get": func() error { _, err := service.GetConversationByID(salesClaims, conversationID); return err },
+			"assign": func() error {
+				_, err := service.AssignConversation(salesClaims, conversationID, requestdto.AssignConversationRequest{OrganizationStaffID: sales.ID})
+				return err
+			},
+			"status": func() error {
+				return service.UpdateConversationStatus(salesClaims, conversationID, requestdto.UpdateConversationRequest{Status: models.ConversationStatusDone})
+			},
+			"send": func() error {
+				return service.SendMessage(salesClaims, conversationID, requestdto.CreateStaffMessageRequest{Message: "Hello"})
+			},
+		} {
+			if err := check(); !errors.Is(err, impl.ErrConversationNotFound) {
+				t.Errorf("%s %d: expected ErrConversationNotFound, got %v", name, conversationID, err)
+			}
+		}
+	}
+}
+
+func TestStaffAccess_SalesTickets(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewTicketService(tx)
+	times := impl.NewTicketTimeService(tx)
+	statuses := impl.NewTicketStatusService(tx, testTicketStatusOptions)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	sales := createTicketStaff(tx, t, org.ID, "sales@test.com")
+	ownerClaims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+	salesClaims := &jwtLib.Claims{UserID: sales.ID, RoleID: sales.RoleID, OrganizationId: &org.ID}
+
+	mine := createTicketConversation(tx, t, org.ID)
+	tx.Model(mine).Update("organization_staff_id", sales.ID)
+	unassigned := createTicketConversation(tx, t, org.ID)
+
+	for _, req := range []requestdto.CreateTicketRequest{
+		{ConversationID: mine.ID, Name: "Assigned to sales", AssigneeID: &sales.ID},
+		{ConversationID: unassigned.ID, Name: "Assigned to owner", AssigneeID: &owner.ID},
+	} {
+		if err := service.CreateTicket(ownerClaims, req); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	var assigned, other models.TicketModel
+	tx.Where("organization_id = ? AND name = ?", org.ID, "Assigned to sales").First(&assigned)
+	tx.Where("organization_id = ? AND name = ?", org.ID, "Assigned to owner").First(&other)
+
+	page, limit := 1, 10
+	for name, check := range map[string]struct {
+		filter filtersdto.TicketFiltersDto
+		want   int
+	}{
+		"default":        {filtersdto.TicketFiltersDto{}, 1},
+		"owner assignee": {filtersdto.TicketFiltersDto{AssigneeID: &owner.ID}, 0},
+	} {
+		result, err := service.GetTicketsList(salesClaims, filtersdto.FiltersDto{Page: &page, Limit: &limit}, check.filter)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", name, err)
+		}
+		if result.Metadata.Total != check.want || len(result.Tickets) != check.want {
+			t.Errorf("%s: expected %d tickets, got %+v", name, check.want, result.Tickets)
+		}
+		for _, ticket := range result.Tickets {
+			if ticket.ID != assigned.ID {
+				t.Errorf("%s: expected only the assigned ticket, got %+v", name, ticket)
+			}
+		}
+	}
+
+	var exported bytes.Buffer
+	if err := service.ExportTickets(salesClaims, filtersdto.TicketFiltersDto{}, &exported); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(exported.String(), assigned.TicketNumber) || strings.Contains(exported.String(), "Assigned to owner") {
+		t.Errorf("expected only the assigned ticket in the export, got %s", exported.String())
+	}
+
+	if _, err := service.GetTicket(salesClaims, assigned.ID); err != nil {
+		t.Fatalf("expected the assigned ticket, got %v", err)
+	}
+	for name, check := range map[string]func() error{
+		"get": func() error { _, err := service.GetTicket(salesClaims, other.ID); return err },
+		"update": func() error {
+			return service.UpdateTicket(salesClaims, other.ID, requestdto.UpdateTicketRequest{Name: "Taken over"})
+		},
+		"comment": func() error {
+			_, err := service.CreateComment(salesClaims, other.ID, requestdto.CreateTicketCommentRequest{Visibility: models.TicketCommentInternal, Body: "mine now"})
+			return err
+		},
+		"timer": func() error {
+			_, err := times.StartTimer(salesClaims, other.ID, requestdto.StartTicketTimerRequest{})
+			return err
+		},
+		"status link": func() error {
+			_, err := statuses.CreateStatusLink(salesClaims, other.ID, requestdto.CreateTicketStatusLinkRequest{})
+			return err
+		},
+	} {
+		if err := check(); !errors.Is(err, impl.ErrTicketNotFound) {
+			t.Errorf("%s: expected ErrTicketNotFound, got %v", name, err)
+		}
+	}
+
+	if err := service.CreateTicket(salesClaims, requestdto.CreateTicketRequest{ConversationID: unassigned.ID, Name: "Not my customer"}); !errors.Is(err, impl.ErrTicketConversationNotFound) {
+		t.Errorf("expected ErrTicketConversationNotFound for an unassigned conversation, got %v", err)
+	}
+	if _, err := service.AddConversation(salesClaims, assigned.ID, requestdto.AddTicketConversationRequest{ConversationID: unassigned.ID}); !errors.Is(err, impl.ErrTicketConversationNotFound) {
+		t.Errorf("expected ErrTicketConversationNotFound when linking an unassigned conversation, got %v", err)
+	}
+
+	// a ticket sales create without an assignee is theirs
+	if err := service.CreateTicket(salesClaims, requestdto.CreateTicketRequest{ConversationID: mine.ID, Name: "Created by sales"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var created models.TicketModel
+	tx.Where("organization_id = ? AND name = ?", org.ID, "Created by sales").First(&created)
+	if created.AssigneeID == nil || *created.AssigneeID != sales.ID {
+		t.Errorf("expected the ticket to be assigned to its creator, got %v", created.AssigneeID)
+	}
+	if _, err := service.GetTicket(salesClaims, created.ID); err != nil {
+		t.Errorf("expected sales to see the ticket they created, got %v", err)
+	}
+}
+
+func TestStaffAccess_SalesContactTimeline(t *testing.T) {
+	tx := SetupTestDB(t)
+	contacts := impl.NewContactService(tx)
+	conversations := impl.NewConversationService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	sales := createTicketStaff(tx, t, org.ID, "sales@test.com")
+	colleague := createTicketStaff(tx, t, org.ID, "colleague@test.com")
+	ownerClaims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+	salesClaims := &jwtLib.Claims{UserID: sales.ID, RoleID: sales.RoleID, OrganizationId: &org.ID}
+
+	mine := createTicketConversation(tx, t, org.ID)
+	tx.Model(mine).Update("organization_staff_id", sales.ID)
+	theirs := models.ConversationModel{OrganizationID: org.ID, ContactID: mine.ContactID, OrganizationStaffID: &colleague.ID, Status: models.ConversationStatusPending}
+	tx.Create(&theirs)
+	tx.Create(&models.ConversationMessageModel{OrganizationID: org.ID, ConversationID: theirs.ID, ContactID: &mine.ContactID, Message: "private"})
+
+	timeline, err := contacts.GetTimeline(salesClaims, mine.ContactID, "", 50)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, entry := range timeline.Data {
+		if entry.ConversationID != mine.ID {
+			t.Errorf("expected only the assigned conversation in the timeline, got %+v", entry)
+		}
+	}
+	if timeline, _ := contacts.GetTimeline(ownerClaims, mine.ContactID, "", 50); len(timeline.Data) != 3 {
+		t.Errorf("expected the owner to see both conversations and the message, got %+v", timeline.Data)
+	}
+
+	detail, err := conversations.GetConversationByID(salesClaims, mine.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if detail.ContactSummary == nil || detail.ContactSummary.TotalConversations != 1 {
+		t.Errorf("expected the summary to count the assigned conversation only, got %+v", detail.ContactSummary)
+	}
+}
@@ -0,0 +1,526 @@
+package tests
+
+import (
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/outbound"
+	"DewaSRY/sociomile-app/pkg/models"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// tenantFixture is an organization with one row behind every organization
+// route, the other tenant tries to reach them by id.
+type tenantFixture struct {
+	org          *models.OrganizationModel
+	owner        *models.UserModel
+	claims       *jwtLib.Claims
+	conversation *models.ConversationModel
+	ticket       models.TicketModel
+	otherTicket  models.TicketModel
+	linkID       uint
+	messageID    uint
+	attachmentID uint
+	timeEntryID  uint
+	contact      uint
+	identityID   uint
+	noteID       uint
+	mergeID      uint
+	attributeID  uint
+	fieldID      uint
+	policyID     uint
+	subscription uint
+	deliveryID   uint
+	bulkJobID    uint
+}
+
+func createTenantFixture(tx *gorm.DB, t *testing.T, name string) *tenantFixture {
+	org, owner := CreateTestOrganizationWithOwner(tx, t, name)
+	f := &tenantFixture{
+		org:          org,
+		owner:        owner,
+		claims:       &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID},
+		conversation: createTicketConversation(tx, t, org.ID),
+	}
+
+	tickets := impl.NewTicketService(tx)
+	for _, ticketName := range []string{name + " ticket", name + " other ticket"} {
+		if err := tickets.CreateTicket(f.claims, requestdto.CreateTicketRequest{ConversationID: f.conversation.ID, Name: ticketName}); err != nil {
+			t.Fatalf("failed to create ticket: %v", err)
+		}
+	}
+	tx.Where("organization_id = ? AND name = ?", org.ID, name+" ticket").First(&f.ticket)
+	tx.Where("organization_id = ? AND name = ?", org.ID, name+" other ticket").First(&f.otherTicket)
+
+	link, err := tickets.CreateLink(f.claims, f.ticket.ID, requestdto.CreateTicketLinkRequest{TicketID: f.otherTicket.ID, Type: models.TicketLinkRelated})
+	if err != nil {
+		t.Fatalf("failed to link tickets: %v", err)
+	}
+	f.linkID = link.ID
+
+	comment, err := tickets.CreateComment(f.claims, f.ticket.ID, requestdto.CreateTicketCommentRequest{Visibility: models.TicketCommentPublic, Body: "We are on it"})
+	if err != nil || comment.MessageID == nil {
+		t.Fatalf("failed to comment: %v", err)
+	}
+	f.messageID = *comment.MessageID
+
+	attachment := models.ConversationMessageAttachmentModel{
+		OrganizationID: org.ID,
+		MessageID:      f.messageID,
+		FileName:       "invoice.pdf",
+		ContentType:    "application/pdf",
+		Size:           3,
+		Content:        []byte("pdf"),
+	}
+	tx.Create(&attachment)
+	f.attachmentID = attachment.ID
+
+	entry, err := impl.NewTicketTimeService(tx).CreateEntry(f.claims, f.ticket.ID, requestdto.CreateTicketTimeEntryRequest{DurationMinutes: 15})
+	if err != nil {
+		t.Fatalf("failed to log time: %v", err)
+	}
+	f.timeEntryID = entry.ID
+
+	contacts := impl.NewContactService(tx)
+	contact, err := contacts.CreateContact(f.claims, requestdto.CreateContactRequest{
+		Name:       name + " customer",
+		Identities: []requestdto.ContactIdentityRequest{{Type: "email", Value: strings.ToLower(strings.ReplaceAll(name, " ", "")) + "@example.com"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+	f.contact = contact.ID
+	f.identityID = contact.Identities[0].ID
+	note, err := contacts.CreateNote(f.claims, contact.ID, requestdto.ContactNoteRequest{Body: "vip"})
+	if err != nil {
+		t.Fatalf("failed to create note: %v", err)
+	}
+	f.noteID = note.ID
+	duplicate, _ := contacts.CreateContact(f.claims, requestdto.CreateContactRequest{Name: name + " duplicate"})
+	merge, err := contacts.MergeContact(f.claims, contact.ID, requestdto.MergeContactRequest{SourceContactID: duplicate.ID})
+	if err != nil {
+		t.Fatalf("failed to merge contacts: %v", err)
+	}
+	f.mergeID = merge.ID
+
+	attribute, err := impl.NewContactAttributeService(tx).CreateAttribute(f.claims, requestdto.CreateContactAttributeRequest{Key: "tier", Label: "Tier", Type: "string"})
+	if err != nil {
+		t.Fatalf("failed to create attribute: %v", err)
+	}
+	f.attributeID = attribute.ID
+
+	field, err := impl.NewTicketFieldService(tx).CreateField(f.claims, requestdto.CreateTicketFieldRequest{Key: "plan", Label: "Plan", Type: "string"})
+	if err != nil {
+		t.Fatalf("failed to create field: %v", err)
+	}
+	f.fieldID = field.ID
+
+	policy, err := impl.NewTicketSLAService(tx, &fakeMailer{}).CreatePolicy(f.claims, requestdto.TicketSLAPolicyRequest{Name: "Default", ResolveMinutes: 60})
+	if err != nil {
+		t.Fatalf("failed to create policy: %v", err)
+	}
+	f.policyID = policy.ID
+
+	subscription, err := impl.NewEventSubscriptionService(tx, &fakeWebhookSender{code: 200}, 3).CreateSubscription(f.claims, requestdto.CreateEventSubscriptionRequest{
+		URL:        "https://example.com/hook",
+		EventTypes: []string{models.EventTicketCreated},
+	})
+	if err != nil {
+		t.Fatalf("failed to create subscription: %v", err)
+	}
+	f.subscription = subscription.ID
+	delivery := models.EventDeliveryModel{
+		OrganizationID: org.ID,
+		SubscriptionID: subscription.ID,
+		EventID:        name + "-event",
+		EventType:      models.EventTicketCreated,
+		Payload:        []byte("{}"),
+	}
+	tx.Create(&delivery)
+	f.deliveryID = delivery.ID
+
+	job, err := impl.NewBulkService(tx, testBulkOptions).BulkTickets(f.claims, requestdto.BulkTicketRequest{IDs: []uint{f.ticket.ID}, Action: models.BulkActionAddTag, Tag: "vip"})
+	if err != nil {
+		t.Fatalf("failed to run bulk job: %v", err)
+	}
+	f.bulkJobID = job.ID
+
+	return f
+}
+
+func TestTenantIsolation_Tickets(t *testing.T) {
+	tx := SetupTestDB(t)
+	tickets := impl.NewTicketService(tx)
+	times := impl.NewTicketTimeService(tx)
+	statuses := impl.NewTicketStatusService(tx, testTicketStatusOptions)
+	bulk := impl.NewBulkService(tx, testBulkOptions)
+
+	a := createTenantFixture(tx, t, "Tenant A")
+	b := createTenantFixture(tx, t, "Tenant B")
+	page, limit := 1, 50
+
+	checks := map[string]struct {
+		run  func() error
+		want error
+	}{
+		"create ticket on their conversation": {func() error {
+			return tickets.CreateTicket(b.claims, requestdto.CreateTicketRequest{ConversationID: a.conversation.ID, Name: "hijacked"})
+		}, impl.ErrTicketConversationNotFound},
+		"get ticket": {func() error { _, err := tickets.GetTicket(b.claims, a.ticket.ID); return err }, impl.ErrTicketNotFound},
+		"update ticket": {func() error {
+			return tickets.UpdateTicket(b.claims, a.ticket.ID, requestdto.UpdateTicketRequest{Name: "hijacked"})
+		}, impl.ErrTicketNotFound},
+		"get comments": {func() error {
+			_, err := tickets.GetComments(b.claims, a.ticket.ID, filtersdto.FiltersDto{Page: &page, Limit: &limit})
+			return err
+		}, impl.ErrTicketNotFound},
+		"create comment": {func() error {
+			_, err := tickets.CreateComment(b.claims, a.ticket.ID, requestdto.CreateTicketCommentRequest{Visibility: models.TicketCommentInternal, Body: "hijacked"})
+			return err
+		}, impl.ErrTicketNotFound},
+		"link to their ticket": {func() error {
+			_, err := tickets.CreateLink(b.claims, b.ticket.ID, requestdto.CreateTicketLinkRequest{TicketID: a.ticket.ID, Type: models.TicketLinkRelated})
+			return err
+		}, impl.ErrTicketNotFound},
+		"delete link":                     {func() error { return tickets.DeleteLink(b.claims, a.ticket.ID, a.linkID) }, impl.ErrTicketNotFound},
+		"delete their link on own ticket": {func() error { return tickets.DeleteLink(b.claims, b.ticket.ID, a.linkID) }, impl.ErrTicketLinkNotFound},
+		"add their conversation": {func() error {
+			_, err := tickets.AddConversation(b.claims, b.ticket.ID, requestdto.AddTicketConversationRequest{ConversationID: a.conversation.ID})
+			return err
+		}, impl.ErrTicketConversationNotFound},
+		"remove conversation": {func() error {
+			return tickets.RemoveConversation(b.claims, a.ticket.ID, a.conversation.ID)
+		}, impl.ErrTicketNotFound},
+		"get time entries": {func() error { _, err := times.GetEntries(b.claims, a.ticket.ID); return err }, impl.ErrTicketNotFound},
+		"log time": {func() error {
+			_, err := times.CreateEntry(b.claims, a.ticket.ID, requestdto.CreateTicketTimeEntryRequest{DurationMinutes: 5})
+			return err
+		}, impl.ErrTicketNotFound},
+		"delete time entry":                     {func() error { return times.DeleteEntry(b.claims, a.ticket.ID, a.timeEntryID) }, impl.ErrTicketNotFound},
+		"delete their time entry on own ticket": {func() error { return times.DeleteEntry(b.claims, b.ticket.ID, a.timeEntryID) }, impl.ErrTicketTimeEntryNotFound},
+		"start timer": {func() error {
+			_, err := times.StartTimer(b.claims, a.ticket.ID, requestdto.StartTicketTimerRequest{})
+			return err
+		}, impl.ErrTicketNotFound},
+		"stop timer": {func() error {
+			_, err := times.StopTimer(b.claims, a.ticket.ID, requestdto.StopTicketTimerRequest{})
+			return err
+		}, impl.ErrTicketNotFound},
+		"status link": {func() error {
+			_, err := statuses.CreateStatusLink(b.claims, a.ticket.ID, requestdto.CreateTicketStatusLinkRequest{})
+			return err
+		}, impl.ErrTicketNotFound},
+		"get bulk job": {func() error { _, err := bulk.GetJob(b.claims, a.bulkJobID); return err }, impl.ErrBulkJobNotFound},
+	}
+	for name, check := range checks {
+		if err := check.run(); !errors.Is(err, check.want) {
+			t.Errorf("%s: expected %v, got %v", name, check.want, err)
+		}
+	}
+
+	job, err := bulk.BulkTickets(b.claims, requestdto.BulkTicketRequest{IDs: []uint{a.ticket.ID}, Action: models.BulkActionStatus, Status: "in_progress"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if job.Failed != 1 || job.Succeeded != 0 {
+		t.Errorf("expected the bulk item of another tenant to fail, got %+v", job)
+	}
+
+	list, err := tickets.GetTicketsList(b.claims, filtersdto.FiltersDto{Page: &page, Limit: &limit}, filtersdto.TicketFiltersDto{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, ticket := range list.Tickets {
+		if ticket.ID == a.ticket.ID || ticket.ID == a.otherTicket.ID {
+			t.Errorf("expected only own tickets in the list, got %+v", ticket)
+		}
+	}
+
+	var exported bytes.Buffer
+	if err := tickets.ExportTickets(b.claims, filtersdto.TicketFiltersDto{}, &exported); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Contains(exported.String(), a.ticket.Name) {
+		t.Errorf("expected the export to leave out other tenants, got %s", exported.String())
+	}
+
+	var ticket models.TicketModel
+	tx.First(&ticket, a.ticket.ID)
+	if ticket.Name != a.ticket.Name || ticket.Status != a.ticket.Status {
+		t.Errorf("expected the ticket of the other tenant untouched, got %+v", ticket)
+	}
+}
+
+func TestTenantIsolation_Conversations(t *testing.T) {
+	tx := SetupTestDB(t)
+	conversations := impl.NewConversationService(tx)
+	deliveries := impl.NewOutboundDeliveryService(tx, outbound.NewRegistry(), 3)
+	bulk := impl.NewBulkService(tx, testBulkOptions)
+
+	a := createTenantFixture(tx, t, "Tenant A")
+	b := createTenantFixture(tx, t, "Tenant B")
+	staffA := createTicketStaff(tx, t, a.org.ID, "tenant-a-sales@test.com")
+	staffB := createTicketStaff(tx, t, b.org.ID, "tenant-b-sales@test.com")
+	page, limit := 1, 50
+
+	checks := map[string]struct {
+		run  func() error
+		want error
+	}{
+		"get conversation": {func() error { _, err := conversations.GetConversationByID(b.claims, a.conversation.ID); return err }, impl.ErrConversationNotFound},
+		"assign conversation": {func() error {
+			_, err := conversations.AssignConversation(b.claims, a.conversation.ID, requestdto.AssignConversationRequest{OrganizationStaffID: staffB.ID})
+			return err
+		}, impl.ErrConversationNotFound},
+		"update status": {func() error {
+			return conversations.UpdateConversationStatus(b.claims, a.conversation.ID, requestdto.UpdateConversationRequest{Status: models.ConversationStatusDone})
+		}, impl.ErrConversationNotFound},
+		"send message": {func() error {
+			return conversations.SendMessage(b.claims, a.conversation.ID, requestdto.CreateStaffMessageRequest{Message: "hijacked"})
+		}, impl.ErrConversationNotFound},
+		"retry delivery": {func() error { return deliveries.RetryDelivery(b.claims, a.conversation.ID, a.messageID) }, impl.ErrDeliveryNotFound},
+	}
+	for name, check := range checks {
+		if err := check.run(); !errors.Is(err, check.want) {
+			t.Errorf("%s: expected %v, got %v", name, check.want, err)
+		}
+	}
+
+	if _, err := conversations.GetAttachment(b.claims, a.conversation.ID, a.attachmentID); err == nil {
+		t.Error("expected the attachment of another tenant to be hidden")
+	}
+	if _, err := conversations.AssignConversation(a.claims, a.conversation.ID, requestdto.AssignConversationRequest{OrganizationStaffID: staffB.ID}); err == nil {
+		t.Error("expected staff of another tenant to be refused")
+	}
+	if _, err := conversations.AssignConversation(a.claims, a.conversation.ID, requestdto.AssignConversationRequest{OrganizationStaffID: staffA.ID}); err != nil {
+		t.Fatalf("expected own staff to be assigned, got %v", err)
+	}
+
+	list, err := conversations.GetConversationsList(b.claims, filtersdto.FiltersDto{Page: &page, Limit: &limit}, filtersdto.ConversationFiltersDto{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, conversation := range list.Conversations {
+		if conversation.ID == a.conversation.ID {
+			t.Errorf("expected only own conversations in the list, got %+v", conversation)
+		}
+	}
+
+	job, err := bulk.BulkConversations(b.claims, requestdto.BulkConversationRequest{IDs: []uint{a.conversation.ID}, Action: models.BulkActionClose})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if job.Failed != 1 || job.Succeeded != 0 {
+		t.Errorf("expected the bulk item of another tenant to fail, got %+v", job)
+	}
+
+	var conversation models.ConversationModel
+	tx.First(&conversation, a.conversation.ID)
+	if conversation.Status != models.ConversationStatusInProgress || conversation.OrganizationStaffID == nil || *conversation.OrganizationStaffID != staffA.ID {
+		t.Errorf("expected the conversation of the other tenant untouched, got %+v", conversation)
+	}
+}
+
+func TestTenantIsolation_Contacts(t *testing.T) {
+	tx := SetupTestDB(t)
+	contacts := impl.NewContactService(tx)
+
+	a := createTenantFixture(tx, t, "Tenant A")
+	b := createTenantFixture(tx, t, "Tenant B")
+	page, limit := 1, 50
+
+	checks := map[string]struct {
+		run  func() error
+		want error
+	}{
+		"get contact": {func() error { _, err := contacts.GetContactByID(b.claims, a.contact); return err }, impl.ErrContactNotFound},
+		"update contact": {func() error {
+			_, err := contacts.UpdateContact(b.claims, a.contact, requestdto.UpdateContactRequest{Name: "hijacked"})
+			return err
+		}, impl.ErrContactNotFound},
+		"add identity": {func() error {
+			_, err := contacts.AddIdentity(b.claims, a.contact, requestdto.ContactIdentityRequest{Type: "phone", Value: "+6281200000000"})
+			return err
+		}, impl.ErrContactNotFound},
+		"remove identity": {func() error { return contacts.RemoveIdentity(b.claims, a.contact, a.identityID) }, impl.ErrContactNotFound},
+		"link user": {func() error {
+			_, err := contacts.LinkUser(b.claims, a.contact, requestdto.LinkContactUserRequest{Email: "guest@test.com"})
+			return err
+		}, impl.ErrContactNotFound},
+		"unlink user": {func() error { return contacts.UnlinkUser(b.claims, a.contact) }, impl.ErrContactNotFound},
+		"set attributes": {func() error {
+			_, err := contacts.SetAttributes(b.claims, a.contact, requestdto.SetContactAttributesRequest{Attributes: map[string]any{"tier": "gold"}})
+			return err
+		}, impl.ErrContactNotFound},
+		"get notes": {func() error {
+			_, err := contacts.GetNotes(b.claims, a.contact, filtersdto.FiltersDto{Page: &page, Limit: &limit})
+			return err
+		}, impl.ErrContactNotFound},
+		"create note": {func() error {
+			_, err := contacts.CreateNote(b.claims, a.contact, requestdto.ContactNoteRequest{Body: "hijacked"})
+			return err
+		}, impl.ErrContactNotFound},
+		"update note": {func() error {
+			_, err := contacts.UpdateNote(b.claims, a.contact, a.noteID, requestdto.ContactNoteRequest{Body: "hijacked"})
+			return err
+		}, impl.ErrContactNoteNotFound},
+		"delete note": {func() error { return contacts.DeleteNote(b.claims, a.contact, a.noteID) }, impl.ErrContactNoteNotFound},
+		"timeline":    {func() error { _, err := contacts.GetTimeline(b.claims, a.contact, "", 20); return err }, impl.ErrContactNotFound},
+		"merge their contact": {func() error {
+			_, err := contacts.MergeContact(b.claims, b.contact, requestdto.MergeContactRequest{SourceContactID: a.contact})
+			return err
+		}, impl.ErrContactNotFound},
+		"merges":     {func() error { _, err := contacts.GetContactMerges(b.claims, a.contact); return err }, impl.ErrContactNotFound},
+		"undo merge": {func() error { _, err := contacts.UndoMerge(b.claims, a.mergeID); return err }, impl.ErrContactMergeNotFound},
+	}
+	for name, check := range checks {
+		if err := check.run(); !errors.Is(err, check.want) {
+			t.Errorf("%s: expected %v, got %v", name, check.want, err)
+		}
+	}
+
+	list, err := contacts.GetContacts(b.claims, filtersdto.FiltersDto{Page: &page, Limit: &limit}, "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, contact := range list.Data {
+		if contact.ID == a.contact {
+			t.Errorf("expected only own contacts in the list, got %+v", contact)
+		}
+	}
+
+	contact, _ := contacts.GetContactByID(a.claims, a.contact)
+	if contact.Name != "Tenant A customer" || len(contact.Identities) != 1 {
+		t.Errorf("expected the contact of the other tenant untouched, got %+v", contact)
+	}
+}
+
+func TestTenantIsolation_Settings(t *testing.T) {
+	tx := SetupTestDB(t)
+	attributes := impl.NewContactAttributeService(tx)
+	fields := impl.NewTicketFieldService(tx)
+	policies := impl.NewTicketSLAService(tx, &fakeMailer{})
+	subscriptions := impl.NewEventSubscriptionService(tx, &fakeWebhookSender{code: 200}, 3)
+	staff := impl.NewOrganizationService(tx)
+
+	a := createTenantFixture(tx, t, "Tenant A")
+	b := createTenantFixture(tx, t, "Tenant B")
+	page, limit := 1, 50
+	active := false
+
+	checks := map[string]struct {
+		run  func() error
+		want error
+	}{
+		"update attribute": {func() error {
+			_, err := attributes.UpdateAttribute(b.claims, a.attributeID, requestdto.UpdateContactAttributeRequest{Label: "hijacked"})
+			return err
+		}, impl.ErrContactAttributeNotFound},
+		"delete attribute": {func() error { return attributes.DeleteAttribute(b.claims, a.attributeID) }, impl.ErrContactAttributeNotFound},
+		"update field": {func() error {
+			_, err := fields.UpdateField(b.claims, a.fieldID, requestdto.UpdateTicketFieldRequest{Label: "hijacked"})
+			return err
+		}, impl.ErrTicketFieldNotFound},
+		"delete field": {func() error { return fields.DeleteField(b.claims, a.fieldID) }, impl.ErrTicketFieldNotFound},
+		"update policy": {func() error {
+			_, err := policies.UpdatePolicy(b.claims, a.policyID, requestdto.TicketSLAPolicyRequest{Name: "hijacked", ResolveMinutes: 5})
+			return err
+		}, impl.ErrTicketSLAPolicyNotFound},
+		"delete policy": {func() error { return policies.DeletePolicy(b.claims, a.policyID) }, impl.ErrTicketSLAPolicyNotFound},
+		"update subscription": {func() error {
+			_, err := subscriptions.UpdateSubscription(b.claims, a.subscription, requestdto.UpdateEventSubscriptionRequest{Active: &active})
+			return err
+		}, impl.ErrSubscriptionNotFound},
+		"delete subscription": {func() error { return subscriptions.DeleteSubscription(b.claims, a.subscription) }, impl.ErrSubscriptionNotFound},
+		"get deliveries": {func() error {
+			_, err := subscriptions.GetDeliveries(b.claims, a.subscription, filtersdto.FiltersDto{Page: &page, Limit: &limit})
+			return err
+		}, impl.ErrSubscriptionNotFound},
+		"replay delivery": {func() error {
+			_, err := subscriptions.ReplayDelivery(b.claims, a.subscription, a.deliveryID)
+			return err
+		}, impl.ErrSubscriptionNotFound},
+		"replay their delivery on own subscription": {func() error {
+			_, err := subscriptions.ReplayDelivery(b.claims, b.subscription, a.deliveryID)
+			return err
+		}, impl.ErrEventDeliveryNotFound},
+	}
+	for name, check := range checks {
+		if err := check.run(); !errors.Is(err, check.want) {
+			t.Errorf("%s: expected %v, got %v", name, check.want, err)
+		}
+	}
+
+	attributeList, _ := attributes.GetAttributes(b.claims)
+	fieldList, _ := fields.GetFields(b.claims)
+	policyList, _ := policies.GetPolicies(b.claims)
+	subscriptionList, _ := subscriptions.GetSubscriptions(b.claims)
+	if len(attributeList) != 1 || attributeList[0].ID == a.attributeID ||
+		len(fieldList) != 1 || fieldList[0].ID == a.fieldID ||
+		len(policyList.Policies) != 1 || policyList.Policies[0].ID == a.policyID ||
+		len(subscriptionList.Data) != 1 || subscriptionList.Data[0].ID == a.subscription {
+		t.Error("expected only the settings of the own organization")
+	}
+
+	staffList, err := staff.GetStaffList(filtersdto.FiltersDto{Page: &page, Limit: &limit}, b.claims)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, member := range staffList.Data {
+		if member.ID == a.owner.ID {
+			t.Errorf("expected only own staff in the list, got %+v", member)
+		}
+	}
+
+	// a caller without an organization reaches nothing
+	if _, err := impl.NewTicketService(tx).GetTicket(&jwtLib.Claims{UserID: a.owner.ID}, a.ticket.ID); !errors.Is(err, impl.ErrTicketNotFound) {
+		t.Errorf("expected ErrTicketNotFound without an organization, got %v", err)
+	}
+}
+
+func TestTenantIsolation_GuestMessages(t *testing.T) {
+	tx := SetupTestDB(t)
+	messages := impl.NewGuestMessageService(tx)
+
+	org, _ := CreateTestOrganizationWithOwner(tx, t, "Tenant A")
+	guestRole, _ := GetOrCreateRole(tx, models.RoleGuest)
+	guest := models.UserModel{Email: "guest@test.com", Name: "Guest", Password: "password", RoleID: guestRole.ID}
+	tx.Create(&guest)
+	intruder := models.UserModel{Email: "intruder@test.com", Name: "Intruder", Password: "password", RoleID: guestRole.ID}
+	tx.Create(&intruder)
+
+	contact := CreateTestContact(tx, t, org.ID, &guest)
+	conversation := models.ConversationModel{
+		OrganizationID: org.ID,
+		GuestID:        &guest.ID,
+		ContactID:      contact.ID,
+		Status:         models.ConversationStatusPending,
+	}
+	tx.Create(&conversation)
+
+	page, limit := 1, 10
+	intruderClaims := &jwtLib.Claims{UserID: intruder.ID, RoleID: intruder.RoleID}
+	if _, err := messages.GetConversationMessageList(intruderClaims, filtersdto.FiltersDto{Page: &page, Limit: &limit}, conversation.ID); !errors.Is(err, impl.ErrGuestConversationNotFound) {
+		t.Errorf("expected ErrGuestConversationNotFound reading another guest's messages, got %v", err)
+	}
+	if err := messages.SendConversationMessage(intruderClaims, requestdto.CreateConversationMessageRequest{ConversationID: conversation.ID, Message: "hijacked"}); !errors.Is(err, impl.ErrGuestConversationNotFound) {
+		t.Errorf("expected ErrGuestConversationNotFound writing to another guest's conversation, got %v", err)
+	}
+
+	var count int64
+	tx.Model(&models.ConversationMessageModel{}).Where("conversation_id = ?", conversation.ID).Count(&count)
+	if count != 0 {
+		t.Errorf("expected no message from the intruder, got %d", count)
+	}
+
+	guestClaims := &jwtLib.Claims{UserID: guest.ID, RoleID: guest.RoleID}
+	if err := messages.SendConversationMessage(guestClaims, requestdto.CreateConversationMessageRequest{ConversationID: conversation.ID, Message: "hello"}); err != nil {
+		t.Fatalf("expected the guest to write to their own conversation, got %v", err)
+	}
+}
@@ -4,18 +4,18 @@ import (
 	"DewaSRY/sociomile-app/internal/config"
 	"DewaSRY/sociomile-app/internal/database"
 	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"DewaSRY/sociomile-app/pkg/lib/mail"
 	"DewaSRY/sociomile-app/pkg/models"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/joho/godotenv"
 	"gorm.io/gorm"
 )
 
 func SetupTestDB(t *testing.T) *gorm.DB {
-	godotenv.Load("../../../.env")
-	config.Load()
-	logger.Init()
-	db := database.Connect()
+	db := SetupCommittedTestDB(t)
 
 	tx := db.Begin()
 	if tx.Error != nil {
@@ -29,6 +29,16 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 	return tx
 }
 
+// SetupCommittedTestDB connects without the rolled back transaction, for
+// tests that need several connections at once. The test removes what it
+// created itself.
+func SetupCommittedTestDB(t *testing.T) *gorm.DB {
+	godotenv.Load("../../../.env")
+	config.Load()
+	logger.Init()
+	return database.Connect()
+}
+
 func GetOrCreateRole(tx *gorm.DB, roleName string) (*models.UserRoleModel, error) {
 	var role models.UserRoleModel
 	err := tx.Where("name = ?", roleName).FirstOrCreate(&role, models.UserRoleModel{
@@ -68,3 +78,51 @@ func CreateTestOrganizationWithOwner(tx *gorm.DB, t *testing.T, orgName string)
 
 	return &org, &owner
 }
+
+// fakeMailer records outgoing mail instead of talking to a relay
+type fakeMailer struct {
+	mu   sync.Mutex
+	sent []mail.OutgoingMail
+}
+
+func (f *fakeMailer) Send(msg mail.OutgoingMail) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+// waitForMails returns the sent mails once there are at least want of them
+// or after two seconds, for mails that go out in the background
+func (f *fakeMailer) waitForMails(want int) []mail.OutgoingMail {
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		f.mu.Lock()
+		sent := append([]mail.OutgoingMail(nil), f.sent...)
+		f.mu.Unlock()
+		if len(sent) >= want || time.Now().After(deadline) {
+			return sent
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// CreateTestContact creates the contact of a guest user in the organization
+func CreateTestContact(tx *gorm.DB, t *testing.T, organizationID uint, guest *models.UserModel) *models.ContactModel {
+	contact := models.ContactModel{
+		OrganizationID: organizationID,
+		Name:           guest.Name,
+		UserID:         &guest.ID,
+		Identities: []models.ContactIdentityModel{
+			{
+				OrganizationID: organizationID,
+				Type:           models.ContactIdentityTypeEmail,
+				Value:          guest.Email,
+			},
+		},
+	}
+	if err := tx.Create(&contact).Error; err != nil {
+		t.Fatalf("failed to create contact: %v", err)
+	}
+	return &contact
+}
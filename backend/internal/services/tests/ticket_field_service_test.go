@@ -0,0 +1,200 @@
+package tests
+
+import (
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"testing"
+)
+
+func TestTicketFieldService_CreateField(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewTicketFieldService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+	claims := &jwtLib.Claims{UserID: owner.ID, OrganizationId: &org.ID}
+
+	_, err := service.CreateField(claims, requestdto.CreateTicketFieldRequest{
+		Key: "Order ID", Label: "Order ID", Type: models.TicketFieldTypeString,
+	})
+	if !errors.Is(err, impl.ErrTicketFieldKeyInvalid) {
+		t.Errorf("expected ErrTicketFieldKeyInvalid, got %v", err)
+	}
+
+	_, err = service.CreateField(claims, requestdto.CreateTicketFieldRequest{
+		Key: "plan", Label: "Plan", Type: models.TicketFieldTypeEnum,
+	})
+	if !errors.Is(err, impl.ErrTicketFieldNoOptions) {
+		t.Errorf("expected ErrTicketFieldNoOptions, got %v", err)
+	}
+
+	low, high := 10.0, 1.0
+	_, err = service.CreateField(claims, requestdto.CreateTicketFieldRequest{
+		Key: "refund", Label: "Refund", Type: models.TicketFieldTypeNumber, Min: &low, Max: &high,
+	})
+	if !errors.Is(err, impl.ErrTicketFieldRuleInvalid) {
+		t.Errorf("expected ErrTicketFieldRuleInvalid for min above max, got %v", err)
+	}
+
+	pattern := "["
+	_, err = service.CreateField(claims, requestdto.CreateTicketFieldRequest{
+		Key: "order_id", Label: "Order ID", Type: models.TicketFieldTypeString, Pattern: &pattern,
+	})
+	if !errors.Is(err, impl.ErrTicketFieldRuleInvalid) {
+		t.Errorf("expected ErrTicketFieldRuleInvalid for a broken pattern, got %v", err)
+	}
+
+	field, err := service.CreateField(claims, requestdto.CreateTicketFieldRequest{
+		Key: "plan", Label: "Plan", Type: models.TicketFieldTypeEnum, Required: true,
+		Options: []string{" basic ", "pro", "Basic"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !field.Required || len(field.Options) != 2 || field.Options[0] != "basic" {
+		t.Errorf("expected a required field with trimmed unique options, got %+v", field)
+	}
+
+	_, err = service.CreateField(claims, requestdto.CreateTicketFieldRequest{
+		Key: "plan", Label: "Plan again", Type: models.TicketFieldTypeString,
+	})
+	if !errors.Is(err, impl.ErrTicketFieldKeyTaken) {
+		t.Errorf("expected ErrTicketFieldKeyTaken, got %v", err)
+	}
+}
+
+func TestTicketFieldService_TicketValues(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewTicketFieldService(tx)
+	ticketService := impl.NewTicketService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+	conv := createTicketConversation(tx, t, org.ID)
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+
+	minRefund, maxRefund := 0.0, 500.0
+	pattern := `^ORD-\d+$`
+	definitions := []requestdto.CreateTicketFieldRequest{
+		{Key: "plan", Label: "Plan", Type: models.TicketFieldTypeEnum, Required: true, Options: []string{"basic", "pro"}},
+		{Key: "refund", Label: "Refund", Type: models.TicketFieldTypeNumber, Min: &minRefund, Max: &maxRefund},
+		{Key: "order_id", Label: "Order ID", Type: models.TicketFieldTypeString, Pattern: &pattern},
+	}
+	for _, definition := range definitions {
+		if _, err := service.CreateField(claims, definition); err != nil {
+			t.Fatalf("failed to create field: %v", err)
+		}
+	}
+
+	err := ticketService.CreateTicket(claims, requestdto.CreateTicketRequest{ConversationID: conv.ID, Name: "No plan"})
+	if !errors.Is(err, impl.ErrTicketFieldRequired) {
+		t.Errorf("expected ErrTicketFieldRequired, got %v", err)
+	}
+
+	for _, fields := range []map[string]any{
+		{"plan": "enterprise"},
+		{"plan": "pro", "refund": 900.0},
+		{"plan": "pro", "order_id": "12345"},
+		{"plan": "pro", "color": "red"},
+	} {
+		err := ticketService.CreateTicket(claims, requestdto.CreateTicketRequest{ConversationID: conv.ID, Name: "Bad fields", Fields: fields})
+		if !errors.Is(err, impl.ErrTicketFieldInvalid) && !errors.Is(err, impl.ErrTicketFieldUnknown) {
+			t.Errorf("expected %v to be rejected, got %v", fields, err)
+		}
+	}
+
+	if err := ticketService.CreateTicket(claims, requestdto.CreateTicketRequest{
+		ConversationID: conv.ID,
+		Name:           "Refund request",
+		Fields:         map[string]any{"plan": "Pro", "refund": "120.5", "order_id": "ORD-42"},
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := ticketService.CreateTicket(claims, requestdto.CreateTicketRequest{
+		ConversationID: conv.ID,
+		Name:           "Question",
+		Fields:         map[string]any{"plan": "basic"},
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var ticket models.TicketModel
+	tx.Where("organization_id = ? AND name = ?", org.ID, "Refund request").First(&ticket)
+
+	detail, err := ticketService.GetTicket(claims, ticket.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	fields := detail.Ticket.Fields
+	if fields["plan"] != "pro" || fields["refund"] != 120.5 || fields["order_id"] != "ORD-42" {
+		t.Errorf("expected normalized fields, got %+v", fields)
+	}
+
+	// a required field cannot be cleared, an optional one can
+	if err := ticketService.UpdateTicket(claims, ticket.ID, requestdto.UpdateTicketRequest{
+		Fields: map[string]any{"plan": nil},
+	}); !errors.Is(err, impl.ErrTicketFieldRequired) {
+		t.Errorf("expected ErrTicketFieldRequired, got %v", err)
+	}
+	if err := ticketService.UpdateTicket(claims, ticket.ID, requestdto.UpdateTicketRequest{
+		Fields: map[string]any{"refund": nil, "plan": "basic"},
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	detail, _ = ticketService.GetTicket(claims, ticket.ID)
+	if _, ok := detail.Ticket.Fields["refund"]; ok || detail.Ticket.Fields["plan"] != "basic" {
+		t.Errorf("expected refund cleared and plan changed, got %+v", detail.Ticket.Fields)
+	}
+	changed := false
+	for _, activity := range detail.Activity {
+		if activity.Field != nil && *activity.Field == "fields.plan" && activity.NewValue != nil && *activity.NewValue == "basic" {
+			changed = true
+		}
+	}
+	if !changed {
+		t.Error("expected the plan change in the activity")
+	}
+
+	page, limit := 1, 10
+	list, err := ticketService.GetTicketsList(claims, filtersdto.FiltersDto{Page: &page, Limit: &limit}, filtersdto.TicketFiltersDto{
+		Fields: map[string]string{"order_id": "ORD-42"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if list.Metadata.Total != 1 || list.Tickets[0].ID != ticket.ID {
+		t.Errorf("expected only the refund ticket, got %+v", list.Tickets)
+	}
+
+	list, _ = ticketService.GetTicketsList(claims, filtersdto.FiltersDto{Page: &page, Limit: &limit}, filtersdto.TicketFiltersDto{
+		Fields: map[string]string{"plan": "basic"},
+	})
+	if list.Metadata.Total != 2 {
+		t.Errorf("expected both tickets on the basic plan, got %d", list.Metadata.Total)
+	}
+
+	if _, err := ticketService.GetTicketsList(claims, filtersdto.FiltersDto{Page: &page, Limit: &limit}, filtersdto.TicketFiltersDto{
+		Fields: map[string]string{"color": "red"},
+	}); !errors.Is(err, impl.ErrTicketFieldUnknown) {
+		t.Errorf("expected ErrTicketFieldUnknown for an unknown filter, got %v", err)
+	}
+
+	// an option in use cannot be removed
+	var plan models.TicketFieldDefinitionModel
+	tx.Where("organization_id = ? AND `key` = ?", org.ID, "plan").First(&plan)
+	_, err = service.UpdateField(claims, plan.ID, requestdto.UpdateTicketFieldRequest{Required: true, Options: []string{"pro"}})
+	if !errors.Is(err, impl.ErrTicketFieldOptionInUse) {
+		t.Errorf("expected ErrTicketFieldOptionInUse, got %v", err)
+	}
+
+	if err := service.DeleteField(claims, plan.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	detail, _ = ticketService.GetTicket(claims, ticket.ID)
+	if _, ok := detail.Ticket.Fields["plan"]; ok {
+		t.Errorf("expected the deleted field to be gone, got %+v", detail.Ticket.Fields)
+	}
+}
@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"DewaSRY/sociomile-app/internal/handlers"
+	"DewaSRY/sociomile-app/internal/routers"
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/models"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+// newTicketRouter mounts the organization ticket routes the way the server
+// does, content type checks included.
+func newTicketRouter(tx *gorm.DB) (chi.Router, jwtLib.JwtService) {
+	jwtService := jwtLib.NewJwtService()
+	router := routers.OrganizationRouter{
+		JwtService:       jwtService,
+		AuthorizeService: impl.NewAuthorizeService(tx),
+		OrgTicketHandler: *handlers.NewOrganizationTicketHandler(jwtService, impl.NewTicketService(tx)),
+	}
+	r := chi.NewRouter()
+	router.Register(r)
+	return r, jwtService
+}
+
+func TestTicketImportHandler_ContentTypes(t *testing.T) {
+	tx := SetupTestDB(t)
+	r, jwtService := newTicketRouter(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	conv := createTicketConversation(tx, t, org.ID)
+	token, err := jwtService.GenerateToken(owner.ID, owner.Email, owner.RoleID, &org.ID)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	body := fmt.Sprintf("conversation_id,name\n%d,Imported over HTTP\n", conv.ID)
+
+	send := func(contentType string, payload []byte, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/organizations/ticket/import"+query, bytes.NewReader(payload))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := send("text/csv", []byte(body), "?dryRun=false"); w.Code != http.StatusOK {
+		t.Fatalf("expected the CSV body to be imported, got %d %s", w.Code, w.Body.String())
+	}
+	var count int64
+	tx.Model(&models.TicketModel{}).Where("organization_id = ? AND name = ?", org.ID, "Imported over HTTP").Count(&count)
+	if count != 1 {
+		t.Errorf("expected the imported ticket, got %d", count)
+	}
+
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	file, _ := writer.CreateFormFile("file", "tickets.csv")
+	file.Write([]byte(body))
+	writer.Close()
+	if w := send(writer.FormDataContentType(), form.Bytes(), ""); w.Code != http.StatusOK {
+		t.Errorf("expected the form upload to be checked, got %d %s", w.Code, w.Body.String())
+	}
+
+	if w := send("application/xml", []byte(body), ""); w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("expected 415 for another content type, got %d", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/organizations/ticket/", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "text/csv")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("expected the JSON routes to refuse CSV, got %d", w.Code)
+	}
+}
+
+func TestTicketImportHandler_ExportRoundTrip(t *testing.T) {
+	tx := SetupTestDB(t)
+	r, jwtService := newTicketRouter(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	conv := createTicketConversation(tx, t, org.ID)
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+	description := "-2 items missing"
+	if err := impl.NewTicketService(tx).CreateTicket(claims, requestdto.CreateTicketRequest{
+		ConversationID: conv.ID,
+		Name:           "=HYPERLINK(\"x\")",
+		Description:    &description,
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	token, _ := jwtService.GenerateToken(owner.ID, owner.Email, owner.RoleID, &org.ID)
+
+	req := httptest.NewRequest(http.MethodGet, "/organizations/ticket/export", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	exported := httptest.NewRecorder()
+	r.ServeHTTP(exported, req)
+	if exported.Code != http.StatusOK || !bytes.Contains(exported.Body.Bytes(), []byte("'=HYPERLINK")) {
+		t.Fatalf("expected the name quoted in the export, got %d %s", exported.Code, exported.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/organizations/ticket/import?dryRun=false", bytes.NewReader(exported.Body.Bytes()))
+	req.Header.Set("Content-Type", "text/csv")
+	req.Header.Set("Authorization", "Bearer "+token)
+	imported := httptest.NewRecorder()
+	r.ServeHTTP(imported, req)
+	if imported.Code != http.StatusOK {
+		t.Fatalf("expected the export to import, got %d %s", imported.Code, imported.Body.String())
+	}
+
+	var result responsedto.TicketImportResponse
+	json.Unmarshal(imported.Body.Bytes(), &result)
+	var tickets []models.TicketModel
+	tx.Where("organization_id = ?", org.ID).Order("id ASC").Find(&tickets)
+	if len(tickets) != 1 || result.Created != 0 || len(result.Skipped) != 1 ||
+		result.Skipped[0].TicketID != tickets[0].ID || result.Skipped[0].TicketNumber != tickets[0].TicketNumber {
+		t.Fatalf("expected the exported ticket to be skipped, got %d tickets and %+v", len(tickets), result)
+	}
+
+	// without its number the row is a new ticket
+	records, err := csv.NewReader(bytes.NewReader(exported.Body.Bytes())).ReadAll()
+	if err != nil {
+		t.Fatalf("expected valid CSV, got %v", err)
+	}
+	for _, record := range records[1:] {
+		record[slices.Index(records[0], "ticket_number")] = ""
+	}
+	var edited bytes.Buffer
+	csv.NewWriter(&edited).WriteAll(records)
+
+	req = httptest.NewRequest(http.MethodPost, "/organizations/ticket/import?dryRun=false", &edited)
+	req.Header.Set("Content-Type", "text/csv")
+	req.Header.Set("Authorization", "Bearer "+token)
+	imported = httptest.NewRecorder()
+	r.ServeHTTP(imported, req)
+	if imported.Code != http.StatusOK {
+		t.Fatalf("expected the edited export to import, got %d %s", imported.Code, imported.Body.String())
+	}
+
+	tickets = nil
+	tx.Where("organization_id = ?", org.ID).Order("id ASC").Find(&tickets)
+	if len(tickets) != 2 {
+		t.Fatalf("expected the ticket and its copy, got %d", len(tickets))
+	}
+	copied := tickets[1]
+	if copied.Name != "=HYPERLINK(\"x\")" || copied.Description == nil || *copied.Description != description {
+		t.Errorf("expected the copy unchanged, got %q %v", copied.Name, copied.Description)
+	}
+	if copied.TicketNumber == tickets[0].TicketNumber {
+		t.Errorf("expected a new number for the copy, got %s", copied.TicketNumber)
+	}
+}
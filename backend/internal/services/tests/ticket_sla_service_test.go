@@ -0,0 +1,268 @@
+package tests
+
+import (
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTicketSLAService_Policies(t *testing.T) {
+	tx := SetupTestDB(t)
+	ticketService := impl.NewTicketService(tx)
+	service := impl.NewTicketSLAService(tx, &fakeMailer{})
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	otherOrg, _ := CreateTestOrganizationWithOwner(tx, t, "Other Org")
+	outsider := createTicketStaff(tx, t, otherOrg.ID, "outsider@test.com")
+	conv := createTicketConversation(tx, t, org.ID)
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+
+	urgent := models.TicketPriorityUrgent
+	billing := "billing"
+
+	if _, err := service.CreatePolicy(claims, requestdto.TicketSLAPolicyRequest{
+		Name: "Too late warning", ResolveMinutes: 60, WarnMinutes: 60,
+	}); !errors.Is(err, impl.ErrTicketSLAPolicyInvalid) {
+		t.Errorf("expected ErrTicketSLAPolicyInvalid, got %v", err)
+	}
+	if _, err := service.CreatePolicy(claims, requestdto.TicketSLAPolicyRequest{
+		Name: "Outsider", ResolveMinutes: 60,
+		BreachActions: []requestdto.TicketEscalationActionRequest{{Type: models.TicketEscalationReassign, AssigneeID: &outsider.ID}},
+	}); !errors.Is(err, impl.ErrTicketAssigneeInvalid) {
+		t.Errorf("expected ErrTicketAssigneeInvalid, got %v", err)
+	}
+
+	if _, err := service.CreatePolicy(claims, requestdto.TicketSLAPolicyRequest{
+		Name: "Default", ResolveMinutes: 24 * 60, WarnMinutes: 60,
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := service.CreatePolicy(claims, requestdto.TicketSLAPolicyRequest{
+		Name: "Urgent", Priority: &urgent, ResolveMinutes: 4 * 60, WarnMinutes: 30,
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	billingPolicy, err := service.CreatePolicy(claims, requestdto.TicketSLAPolicyRequest{
+		Name: "Billing", Category: &billing, ResolveMinutes: 8 * 60,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := service.CreatePolicy(claims, requestdto.TicketSLAPolicyRequest{
+		Name: "Billing again", Category: &billing, ResolveMinutes: 60,
+	}); !errors.Is(err, impl.ErrTicketSLAPolicyDuplicate) {
+		t.Errorf("expected ErrTicketSLAPolicyDuplicate, got %v", err)
+	}
+
+	for _, req := range []requestdto.CreateTicketRequest{
+		{ConversationID: conv.ID, Name: "Plain ticket"},
+		{ConversationID: conv.ID, Name: "Urgent ticket", Priority: urgent},
+		{ConversationID: conv.ID, Name: "Billing ticket", Priority: urgent, Category: &billing},
+	} {
+		if err := ticketService.CreateTicket(claims, req); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	expected := map[string]time.Duration{
+		"Plain ticket":   24 * time.Hour,
+		"Urgent ticket":  4 * time.Hour,
+		"Billing ticket": 8 * time.Hour,
+	}
+	for name, resolve := range expected {
+		var ticket models.TicketModel
+		tx.Where("organization_id = ? AND name = ?", org.ID, name).First(&ticket)
+		if ticket.SLADueAt == nil || ticket.SLADueAt.Sub(ticket.CreatedAt).Round(time.Minute) != resolve {
+			t.Errorf("expected %s to be due %s after creation, got %v", name, resolve, ticket.SLADueAt)
+		}
+	}
+
+	if err := service.DeletePolicy(claims, billingPolicy.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var ticket models.TicketModel
+	tx.Where("organization_id = ? AND name = ?", org.ID, "Billing ticket").First(&ticket)
+	if ticket.SLADueAt == nil || ticket.SLADueAt.Sub(ticket.CreatedAt).Round(time.Minute) != 4*time.Hour {
+		t.Errorf("expected the billing ticket to fall back to the urgent policy, got %v", ticket.SLADueAt)
+	}
+
+	otherClaims := &jwtLib.Claims{UserID: outsider.ID, OrganizationId: &otherOrg.ID}
+	if err := service.DeletePolicy(otherClaims, billingPolicy.ID); !errors.Is(err, impl.ErrTicketSLAPolicyNotFound) {
+		t.Errorf("expected ErrTicketSLAPolicyNotFound, got %v", err)
+	}
+}
+
+func TestTicketSLAService_ProcessDueTickets(t *testing.T) {
+	tx := SetupTestDB(t)
+	ticketService := impl.NewTicketService(tx)
+	mailer := &fakeMailer{}
+	service := impl.NewTicketSLAService(tx, mailer)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	lead := createTicketStaff(tx, t, org.ID, "lead@test.com")
+	conv := createTicketConversation(tx, t, org.ID)
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+
+	defaultPolicy, err := service.CreatePolicy(claims, requestdto.TicketSLAPolicyRequest{
+		Name: "Default", ResolveMinutes: 60, WarnMinutes: 15,
+		WarningActions: []requestdto.TicketEscalationActionRequest{{Type: models.TicketEscalationNotifyOwner}},
+		BreachActions: []requestdto.TicketEscalationActionRequest{
+			{Type: models.TicketEscalationBumpPriority},
+			{Type: models.TicketEscalationReassign, AssigneeID: &lead.ID},
+			{Type: models.TicketEscalationFireEvent},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// the bumped ticket moves over to the deadlines of its new priority
+	high := models.TicketPriorityHigh
+	highPolicy, err := service.CreatePolicy(claims, requestdto.TicketSLAPolicyRequest{
+		Name: "High", Priority: &high, ResolveMinutes: 30,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := ticketService.CreateTicket(claims, requestdto.CreateTicketRequest{ConversationID: conv.ID, Name: "Stuck ticket"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var ticket models.TicketModel
+	tx.Where("organization_id = ? AND name = ?", org.ID, "Stuck ticket").First(&ticket)
+
+	past := time.Now().Add(-time.Minute)
+	tx.Model(&ticket).Update("sla_warn_at", past)
+	if _, err := service.ProcessDueTickets(100); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	tx.First(&ticket, ticket.ID)
+	if ticket.SLAWarnedAt == nil || ticket.SLABreachedAt != nil {
+		t.Fatalf("expected the ticket to be warned only, got %+v", ticket)
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0].To[0] != owner.Email {
+		t.Errorf("expected the owner to be notified, got %+v", mailer.sent)
+	}
+
+	started := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	tx.Model(&ticket).Updates(map[string]any{"sla_started_at": started, "sla_due_at": past})
+	if _, err := service.ProcessDueTickets(100); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	tx.First(&ticket, ticket.ID)
+	if ticket.SLABreachedAt == nil {
+		t.Fatal("expected the ticket to be breached")
+	}
+	if ticket.Priority != models.TicketPriorityHigh {
+		t.Errorf("expected priority high, got %s", ticket.Priority)
+	}
+	if ticket.AssigneeID == nil || *ticket.AssigneeID != lead.ID {
+		t.Errorf("expected the ticket to be reassigned to the lead, got %v", ticket.AssigneeID)
+	}
+	if ticket.SLAPolicyID == nil || *ticket.SLAPolicyID != highPolicy.ID || ticket.SLADueAt == nil || !ticket.SLADueAt.Equal(started.Add(30*time.Minute)) {
+		t.Errorf("expected the deadlines of the high policy, got %v %v", ticket.SLAPolicyID, ticket.SLADueAt)
+	}
+	if ticket.Name != "Stuck ticket" {
+		t.Errorf("expected the other columns untouched, got %+v", ticket)
+	}
+
+	if _, err := service.ProcessDueTickets(100); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	detail, err := ticketService.GetTicket(claims, ticket.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(detail.Escalations) != 2 {
+		t.Fatalf("expected a warning and a breach escalation, got %+v", detail.Escalations)
+	}
+	breach := detail.Escalations[1]
+	if breach.Stage != models.TicketSLAStageBreached || len(breach.Results) != 3 {
+		t.Errorf("expected the breach with three results, got %+v", breach)
+	}
+	if breach.PolicyID == nil || *breach.PolicyID != defaultPolicy.ID {
+		t.Errorf("expected the breach of the default policy, got %v", breach.PolicyID)
+	}
+	for _, result := range breach.Results {
+		if result.Error != nil {
+			t.Errorf("expected %s to succeed, got %s", result.Type, *result.Error)
+		}
+	}
+	if !detail.Ticket.SLABreached {
+		t.Error("expected the ticket response to show the breach")
+	}
+}
+
+func TestTicketSLAService_ReopenAndReapply(t *testing.T) {
+	tx := SetupTestDB(t)
+	ticketService := impl.NewTicketService(tx)
+	service := impl.NewTicketSLAService(tx, &fakeMailer{})
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	conv := createTicketConversation(tx, t, org.ID)
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+
+	if _, err := service.CreatePolicy(claims, requestdto.TicketSLAPolicyRequest{
+		Name: "Default", ResolveMinutes: 60, WarnMinutes: 15,
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := ticketService.CreateTicket(claims, requestdto.CreateTicketRequest{ConversationID: conv.ID, Name: "Old ticket"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var ticket models.TicketModel
+	tx.Where("organization_id = ? AND name = ?", org.ID, "Old ticket").First(&ticket)
+
+	// the ticket breached long ago and was closed
+	past := time.Now().Add(-48 * time.Hour)
+	tx.Model(&ticket).UpdateColumns(map[string]any{
+		"created_at":      past,
+		"updated_at":      past,
+		"sla_warn_at":     past.Add(45 * time.Minute),
+		"sla_due_at":      past.Add(time.Hour),
+		"sla_warned_at":   past.Add(45 * time.Minute),
+		"sla_breached_at": past.Add(time.Hour),
+	})
+
+	// a policy the ticket does not match leaves it alone
+	billing := "billing"
+	if _, err := service.CreatePolicy(claims, requestdto.TicketSLAPolicyRequest{
+		Name: "Billing", Category: &billing, ResolveMinutes: 8 * 60,
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	tx.First(&ticket, ticket.ID)
+	if ticket.UpdatedAt.Sub(past).Abs() > time.Second || ticket.SLABreachedAt == nil {
+		t.Errorf("expected the unchanged ticket not to be written, got updated at %v", ticket.UpdatedAt)
+	}
+
+	// an update of another field keeps the escalation stamps
+	if err := ticketService.UpdateTicket(claims, ticket.ID, requestdto.UpdateTicketRequest{Name: "Old ticket renamed"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	tx.First(&ticket, ticket.ID)
+	if ticket.SLAWarnedAt == nil || ticket.SLABreachedAt == nil {
+		t.Errorf("expected the escalation stamps to be kept, got %+v", ticket)
+	}
+
+	for _, status := range []string{models.TicketStatusDone, models.TicketStatusPending} {
+		if err := ticketService.UpdateTicket(claims, ticket.ID, requestdto.UpdateTicketRequest{Status: status}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	tx.First(&ticket, ticket.ID)
+	if ticket.SLAStartedAt == nil || time.Since(*ticket.SLAStartedAt) > time.Minute {
+		t.Fatalf("expected the SLA clock to restart on reopen, got %v", ticket.SLAStartedAt)
+	}
+	if ticket.SLADueAt == nil || ticket.SLADueAt.Sub(*ticket.SLAStartedAt).Round(time.Minute) != time.Hour {
+		t.Errorf("expected the reopened ticket to be due an hour after the reopen, got %v", ticket.SLADueAt)
+	}
+	if ticket.SLAWarnedAt != nil || ticket.SLABreachedAt != nil {
+		t.Errorf("expected the escalation stamps to be cleared, got %+v", ticket)
+	}
+}
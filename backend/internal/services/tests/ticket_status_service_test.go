@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testTicketStatusOptions = impl.TicketStatusOptions{
+	URL:    "http://localhost:3000/ticket-status",
+	Secret: "status-secret",
+	TTL:    time.Hour,
+}
+
+func TestTicketStatusService_GuestTickets(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewTicketStatusService(tx, testTicketStatusOptions)
+	ticketService := impl.NewTicketService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+
+	guestRole, _ := GetOrCreateRole(tx, models.RoleGuest)
+	guest := models.UserModel{Email: "guest@test.com", Name: "Guest", Password: "password", RoleID: guestRole.ID}
+	tx.Create(&guest)
+	stranger := models.UserModel{Email: "stranger@test.com", Name: "Stranger", Password: "password", RoleID: guestRole.ID}
+	tx.Create(&stranger)
+
+	contact := CreateTestContact(tx, t, org.ID, &guest)
+	conv := models.ConversationModel{
+		OrganizationID: org.ID,
+		GuestID:        &guest.ID,
+		ContactID:      contact.ID,
+		Status:         models.ConversationStatusPending,
+	}
+	tx.Create(&conv)
+
+	if err := ticketService.CreateTicket(claims, requestdto.CreateTicketRequest{ConversationID: conv.ID, Name: "Refund not received"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var ticket models.TicketModel
+	tx.Where("organization_id = ?", org.ID).First(&ticket)
+
+	for _, comment := range []requestdto.CreateTicketCommentRequest{
+		{Visibility: models.TicketCommentInternal, Body: "bank says it bounced"},
+		{Visibility: models.TicketCommentPublic, Body: "We sent the refund again."},
+	} {
+		if _, err := ticketService.CreateComment(claims, ticket.ID, comment); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	guestClaims := &jwtLib.Claims{UserID: guest.ID, RoleID: guest.RoleID}
+	page, limit := 1, 10
+	list, err := service.GetGuestTickets(guestClaims, filtersdto.FiltersDto{Page: &page, Limit: &limit})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if list.Metadata.Total != 1 || list.Tickets[0].TicketNumber != ticket.TicketNumber ||
+		list.Tickets[0].StatusName != "Pending" || list.Tickets[0].OrganizationName != org.Name {
+		t.Errorf("expected the guest's ticket, got %+v", list.Tickets)
+	}
+
+	detail, err := service.GetGuestTicket(guestClaims, ticket.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(detail.Comments) != 1 || detail.Comments[0].Body != "We sent the refund again." || detail.Comments[0].AuthorName != owner.Name {
+		t.Errorf("expected only the public comment, got %+v", detail.Comments)
+	}
+
+	strangerClaims := &jwtLib.Claims{UserID: stranger.ID, RoleID: stranger.RoleID}
+	list, _ = service.GetGuestTickets(strangerClaims, filtersdto.FiltersDto{Page: &page, Limit: &limit})
+	if list.Metadata.Total != 0 {
+		t.Errorf("expected no tickets for another guest, got %+v", list.Tickets)
+	}
+	if _, err := service.GetGuestTicket(strangerClaims, ticket.ID); !errors.Is(err, impl.ErrTicketNotFound) {
+		t.Errorf("expected ErrTicketNotFound for another guest, got %v", err)
+	}
+}
+
+func TestTicketStatusService_StatusLink(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewTicketStatusService(tx, testTicketStatusOptions)
+	ticketService := impl.NewTicketService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	conv := createTicketConversation(tx, t, org.ID)
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+
+	if err := ticketService.CreateTicket(claims, requestdto.CreateTicketRequest{ConversationID: conv.ID, Name: "Broken checkout"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var ticket models.TicketModel
+	tx.Where("organization_id = ?", org.ID).First(&ticket)
+
+	link, err := service.CreateStatusLink(claims, ticket.ID, requestdto.CreateTicketStatusLinkRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(link.URL, testTicketStatusOptions.URL+"?token=") || link.MessageID != nil {
+		t.Errorf("expected an unsent link to the status page, got %+v", link)
+	}
+
+	status, err := service.GetTicketStatus(link.Token)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if status.ID != ticket.ID || status.Name != "Broken checkout" || status.Comments == nil {
+		t.Errorf("expected the ticket of the link, got %+v", status)
+	}
+
+	parts := strings.Split(link.Token, ".")
+	otherTicket := "1" + strings.Join(parts, ".")
+	otherVersion := parts[0] + ".1." + strings.Join(parts[2:], ".")
+	rotated := impl.NewTicketStatusService(tx, impl.TicketStatusOptions{URL: testTicketStatusOptions.URL, Secret: "rotated", TTL: time.Hour})
+	expired := impl.NewTicketStatusService(tx, impl.TicketStatusOptions{URL: testTicketStatusOptions.URL, Secret: testTicketStatusOptions.Secret, TTL: -time.Minute})
+	expiredLink, _ := expired.CreateStatusLink(claims, ticket.ID, requestdto.CreateTicketStatusLinkRequest{})
+
+	for name, check := range map[string]func() error{
+		"garbage":        func() error { _, err := service.GetTicketStatus("not-a-token"); return err },
+		"other ticket":   func() error { _, err := service.GetTicketStatus(otherTicket); return err },
+		"other version":  func() error { _, err := service.GetTicketStatus(otherVersion); return err },
+		"rotated secret": func() error { _, err := rotated.GetTicketStatus(link.Token); return err },
+		"expired":        func() error { _, err := service.GetTicketStatus(expiredLink.Token); return err },
+	} {
+		if err := check(); !errors.Is(err, impl.ErrTicketStatusLinkInvalid) {
+			t.Errorf("%s: expected ErrTicketStatusLinkInvalid, got %v", name, err)
+		}
+	}
+
+	// a new link that revokes the previous ones
+	fresh, err := service.CreateStatusLink(claims, ticket.ID, requestdto.CreateTicketStatusLinkRequest{RevokePrevious: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := service.GetTicketStatus(link.Token); !errors.Is(err, impl.ErrTicketStatusLinkInvalid) {
+		t.Errorf("expected the revoked link to stop working, got %v", err)
+	}
+	if _, err := service.GetTicketStatus(fresh.Token); err != nil {
+		t.Errorf("expected the new link to work, got %v", err)
+	}
+
+	sent, err := service.CreateStatusLink(claims, ticket.ID, requestdto.CreateTicketStatusLinkRequest{Send: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if sent.MessageID == nil {
+		t.Fatal("expected the link to be sent to the customer")
+	}
+	var message models.ConversationMessageModel
+	tx.First(&message, *sent.MessageID)
+	if message.ConversationID != conv.ID || !strings.Contains(message.Message, sent.URL) {
+		t.Errorf("expected the link in the ticket's conversation, got %+v", message)
+	}
+
+	otherOrg, otherOwner := CreateTestOrganizationWithOwner(tx, t, "Other Org")
+	otherClaims := &jwtLib.Claims{UserID: otherOwner.ID, RoleID: otherOwner.RoleID, OrganizationId: &otherOrg.ID}
+	if _, err := service.CreateStatusLink(otherClaims, ticket.ID, requestdto.CreateTicketStatusLinkRequest{}); !errors.Is(err, impl.ErrTicketNotFound) {
+		t.Errorf("expected ErrTicketNotFound for another organization, got %v", err)
+	}
+}
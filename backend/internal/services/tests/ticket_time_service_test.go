@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTicketTimeService_Timer(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewTicketTimeService(tx)
+	ticketService := impl.NewTicketService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	conv := createTicketConversation(tx, t, org.ID)
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+
+	for _, name := range []string{"First", "Second"} {
+		if err := ticketService.CreateTicket(claims, requestdto.CreateTicketRequest{ConversationID: conv.ID, Name: name}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	var first, second models.TicketModel
+	tx.Where("organization_id = ? AND name = ?", org.ID, "First").First(&first)
+	tx.Where("organization_id = ? AND name = ?", org.ID, "Second").First(&second)
+
+	started, err := service.StartTimer(claims, first.ID, requestdto.StartTicketTimerRequest{Note: " debugging ", Billable: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !started.Running || started.EndedAt != nil || started.Note == nil || *started.Note != "debugging" {
+		t.Errorf("expected a running timer, got %+v", started)
+	}
+
+	if _, err := service.StartTimer(claims, second.ID, requestdto.StartTicketTimerRequest{}); !errors.Is(err, impl.ErrTicketTimerRunning) {
+		t.Errorf("expected ErrTicketTimerRunning, got %v", err)
+	}
+	if _, err := service.StopTimer(claims, second.ID, requestdto.StopTicketTimerRequest{}); !errors.Is(err, impl.ErrTicketTimerNotRunning) {
+		t.Errorf("expected ErrTicketTimerNotRunning on the other ticket, got %v", err)
+	}
+
+	// pretend the timer ran for ten minutes
+	tx.Model(&models.TicketTimeEntryModel{}).Where("id = ?", started.ID).
+		Update("started_at", time.Now().Add(-10*time.Minute))
+
+	billable := false
+	stopped, err := service.StopTimer(claims, first.ID, requestdto.StopTicketTimerRequest{Billable: &billable})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if stopped.Running || stopped.DurationSeconds < 595 || stopped.DurationSeconds > 605 || stopped.Billable {
+		t.Errorf("expected a stopped non billable ten minute entry, got %+v", stopped)
+	}
+
+	if _, err := service.StartTimer(claims, second.ID, requestdto.StartTicketTimerRequest{}); err != nil {
+		t.Fatalf("expected a new timer once the first stopped, got %v", err)
+	}
+
+	if _, err := service.CreateEntry(claims, first.ID, requestdto.CreateTicketTimeEntryRequest{DurationMinutes: 30, Billable: true}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	future := time.Now().Add(time.Hour)
+	if _, err := service.CreateEntry(claims, first.ID, requestdto.CreateTicketTimeEntryRequest{DurationMinutes: 30, StartedAt: &future}); !errors.Is(err, impl.ErrTicketTimeEntryInvalid) {
+		t.Errorf("expected ErrTicketTimeEntryInvalid for a future entry, got %v", err)
+	}
+
+	entries, err := service.GetEntries(claims, first.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(entries.Entries) != 2 || entries.Totals.Entries != 2 || entries.Totals.BillableSeconds != 1800 {
+		t.Errorf("expected two entries with thirty billable minutes, got %+v", entries)
+	}
+
+	// running timers do not count yet
+	entries, _ = service.GetEntries(claims, second.ID)
+	if len(entries.Entries) != 1 || !entries.Entries[0].Running || entries.Totals.Entries != 0 {
+		t.Errorf("expected only the running timer, got %+v", entries)
+	}
+
+	staff := createTicketStaff(tx, t, org.ID, "time-sales@test.com")
+	staffClaims := &jwtLib.Claims{UserID: staff.ID, RoleID: staff.RoleID, OrganizationId: &org.ID}
+	if err := service.DeleteEntry(staffClaims, first.ID, stopped.ID); !errors.Is(err, impl.ErrTicketTimeEntryNotFound) {
+		t.Errorf("expected ErrTicketTimeEntryNotFound for another staff member's entry, got %v", err)
+	}
+	if err := service.DeleteEntry(claims, first.ID, stopped.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	otherOrg, otherOwner := CreateTestOrganizationWithOwner(tx, t, "Other Org")
+	otherClaims := &jwtLib.Claims{UserID: otherOwner.ID, RoleID: otherOwner.RoleID, OrganizationId: &otherOrg.ID}
+	if _, err := service.StartTimer(otherClaims, first.ID, requestdto.StartTicketTimerRequest{}); !errors.Is(err, impl.ErrTicketNotFound) {
+		t.Errorf("expected ErrTicketNotFound for another organization, got %v", err)
+	}
+}
+
+func TestTicketTimeService_Report(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewTicketTimeService(tx)
+	ticketService := impl.NewTicketService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	conv := createTicketConversation(tx, t, org.ID)
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+	staff := createTicketStaff(tx, t, org.ID, "report-sales@test.com")
+	staffClaims := &jwtLib.Claims{UserID: staff.ID, RoleID: staff.RoleID, OrganizationId: &org.ID}
+
+	for _, name := range []string{"Billing", "Outage"} {
+		if err := ticketService.CreateTicket(claims, requestdto.CreateTicketRequest{ConversationID: conv.ID, Name: name}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	var billing, outage models.TicketModel
+	tx.Where("organization_id = ? AND name = ?", org.ID, "Billing").First(&billing)
+	tx.Where("organization_id = ? AND name = ?", org.ID, "Outage").First(&outage)
+	tx.Model(&outage).Update("assignee_id", staff.ID)
+
+	at := func(value string) *time.Time {
+		parsed, _ := time.Parse(time.RFC3339, value)
+		return &parsed
+	}
+	entries := []struct {
+		claims   *jwtLib.Claims
+		ticketID uint
+		start    string
+		minutes  int
+		billable bool
+	}{
+		{claims, billing.ID, "2025-03-10T09:00:00Z", 30, true},
+		{claims, outage.ID, "2025-03-10T12:00:00Z", 20, false},
+		{staffClaims, outage.ID, "2025-03-10T23:30:00Z", 45, true},
+		{claims, billing.ID, "2025-03-12T10:00:00Z", 15, true},
+	}
+	for _, entry := range entries {
+		if _, err := service.CreateEntry(entry.claims, entry.ticketID, requestdto.CreateTicketTimeEntryRequest{
+			DurationMinutes: entry.minutes,
+			StartedAt:       at(entry.start),
+			Billable:        entry.billable,
+		}); err != nil {
+			t.Fatalf("failed to log time: %v", err)
+		}
+	}
+
+	report, err := service.GetReport(claims, filtersdto.TicketTimeReportFiltersDto{From: "2025-03-10", To: "2025-03-11"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if report.Totals.Entries != 3 || report.Totals.TotalSeconds != 95*60 || report.Totals.BillableSeconds != 75*60 {
+		t.Errorf("expected three entries of 95 minutes, 75 billable, got %+v", report.Totals)
+	}
+	if len(report.Agents) != 2 || report.Agents[0].User.ID != owner.ID || report.Agents[0].Totals.TotalSeconds != 50*60 {
+		t.Errorf("expected the owner first with 50 minutes, got %+v", report.Agents)
+	}
+	if len(report.Tickets) != 2 || report.Tickets[0].Ticket.ID != outage.ID || report.Tickets[0].Totals.TotalSeconds != 65*60 {
+		t.Errorf("expected the outage first with 65 minutes, got %+v", report.Tickets)
+	}
+
+	report, _ = service.GetReport(claims, filtersdto.TicketTimeReportFiltersDto{From: "2025-03-10", To: "2025-03-12", UserID: &staff.ID})
+	if report.Totals.Entries != 1 || len(report.Agents) != 1 || report.Agents[0].User.ID != staff.ID {
+		t.Errorf("expected only the staff member's entry, got %+v", report)
+	}
+
+	// the days follow the timezone of the organization, 23:30 UTC is the
+	// next morning in Jakarta
+	tx.Model(&models.OrganizationModel{}).Where("id = ?", org.ID).Update("timezone", "Asia/Jakarta")
+	report, _ = service.GetReport(claims, filtersdto.TicketTimeReportFiltersDto{From: "2025-03-10", To: "2025-03-10"})
+	if report.Timezone != "Asia/Jakarta" || report.Totals.Entries != 2 {
+		t.Errorf("expected two entries on the 10th in Jakarta, got %+v", report)
+	}
+
+	if _, err := service.GetReport(claims, filtersdto.TicketTimeReportFiltersDto{From: "2025-03-12", To: "2025-03-10"}); !errors.Is(err, impl.ErrTicketTimeReportInvalid) {
+		t.Errorf("expected ErrTicketTimeReportInvalid for a reversed range, got %v", err)
+	}
+	if _, err := service.GetReport(claims, filtersdto.TicketTimeReportFiltersDto{From: "2024-01-01", To: "2025-03-10"}); !errors.Is(err, impl.ErrTicketTimeReportInvalid) {
+		t.Errorf("expected ErrTicketTimeReportInvalid for a range over a year, got %v", err)
+	}
+}
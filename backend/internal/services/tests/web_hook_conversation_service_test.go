@@ -3,6 +3,8 @@ package tests
 import (
 	"DewaSRY/sociomile-app/internal/services/impl"
 	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
 	"testing"
 )
 
@@ -23,4 +25,211 @@ func TestWebHookConversationService_ProcessConversation_NoInternalError(t *testi
 	if err != nil {
 		t.Errorf("Expected no internal error, got: %v", err)
 	}
-}
\ No newline at end of file
+}
+
+func TestWebHookConversationService_ProcessConversation_FilesUnderContact(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewWebHookConversationService(tx)
+
+	org, _ := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+
+	requests := []requestdto.WebHooksRequest{
+		{
+			OrganizationID: org.ID,
+			Email:          "Customer@Example.com",
+			Name:           "Customer",
+			Message:        "first",
+		},
+		{
+			OrganizationID: org.ID,
+			Email:          "customer@example.com",
+			Phone:          "+62 812-3456-789",
+			Source:         "whatsapp",
+			ExternalID:     "62812345678",
+			Message:        "second",
+		},
+	}
+	for _, req := range requests {
+		if err := service.ProcessConversation(req); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	var userCount int64
+	tx.Model(&models.UserModel{}).Where("email = ?", "customer@example.com").Count(&userCount)
+	if userCount != 0 {
+		t.Errorf("expected no login user for the customer, got %d", userCount)
+	}
+
+	var contacts []models.ContactModel
+	tx.Where("organization_id = ?", org.ID).Preload("Identities").Find(&contacts)
+	if len(contacts) != 1 {
+		t.Fatalf("expected one contact, got %d", len(contacts))
+	}
+	if contacts[0].Name != "Customer" || len(contacts[0].Identities) != 3 {
+		t.Errorf("unexpected contact %+v", contacts[0])
+	}
+
+	var conversations []models.ConversationModel
+	tx.Where("organization_id = ?", org.ID).Find(&conversations)
+	if len(conversations) != 1 || conversations[0].ContactID != contacts[0].ID || conversations[0].GuestID != nil {
+		t.Errorf("expected one conversation of the contact, got %+v", conversations)
+	}
+
+	var messages []models.ConversationMessageModel
+	tx.Where("conversation_id = ?", conversations[0].ID).Find(&messages)
+	for _, message := range messages {
+		if message.ContactID == nil || *message.ContactID != contacts[0].ID || message.CreatedByID != nil {
+			t.Errorf("expected message written by the contact, got %+v", message)
+		}
+	}
+}
+
+func TestWebHookConversationService_EnqueueConversation_StoresPendingEvent(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewWebHookConversationService(tx)
+
+	org, _ := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+
+	accepted, err := service.EnqueueConversation(requestdto.WebHooksRequest{
+		OrganizationID: org.ID,
+		Email:          "Webhook@Example.com",
+		Message:        "Test webhook message",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var event models.WebhookInboxEventModel
+	if err := tx.First(&event, accepted.EventID).Error; err != nil {
+		t.Fatalf("expected stored event, got %v", err)
+	}
+	if event.Status != models.WebhookInboxStatusPending {
+		t.Errorf("expected status pending, got %s", event.Status)
+	}
+	if event.ContactKey != "webhook@example.com" {
+		t.Errorf("expected normalized contact key, got %s", event.ContactKey)
+	}
+
+	var count int64
+	tx.Model(&models.ConversationModel{}).Where("organization_id = ?", org.ID).Count(&count)
+	if count != 0 {
+		t.Errorf("expected no conversation before processing, got %d", count)
+	}
+}
+
+func TestWebHookConversationService_EnqueueConversation_UnknownOrganization(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewWebHookConversationService(tx)
+
+	_, err := service.EnqueueConversation(requestdto.WebHooksRequest{
+		OrganizationID: 999999,
+		Email:          "webhook@example.com",
+		Message:        "Test webhook message",
+	})
+	if !errors.Is(err, impl.ErrOrganizationNotFound) {
+		t.Errorf("expected organization not found, got %v", err)
+	}
+}
+
+func TestWebHookConversationService_ProcessInbox_KeepsContactOrder(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewWebHookConversationService(tx)
+
+	org, _ := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+
+	for _, message := range []string{"first", "second"} {
+		if _, err := service.EnqueueConversation(requestdto.WebHooksRequest{
+			OrganizationID: org.ID,
+			Email:          "webhook@example.com",
+			Message:        message,
+		}); err != nil {
+			t.Fatalf("failed to enqueue: %v", err)
+		}
+	}
+
+	processed, err := service.ProcessInbox(1, 10)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if processed != 1 {
+		t.Fatalf("expected only the oldest event of the contact, got %d", processed)
+	}
+
+	processed, _ = service.ProcessInbox(1, 10)
+	if processed != 1 {
+		t.Fatalf("expected the second event on the next run, got %d", processed)
+	}
+
+	var messages []models.ConversationMessageModel
+	tx.Where("organization_id = ?", org.ID).Order("id ASC").Find(&messages)
+	if len(messages) != 2 || messages[0].Message != "first" || messages[1].Message != "second" {
+		t.Errorf("expected messages in order, got %+v", messages)
+	}
+}
+
+func TestWebHookConversationService_ProcessInbox_ParksAndRetries(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewWebHookConversationService(tx)
+
+	org, _ := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+
+	accepted, err := service.EnqueueConversation(requestdto.WebHooksRequest{
+		OrganizationID: org.ID,
+		Email:          "webhook@example.com",
+		Message:        "Test webhook message",
+	})
+	if err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+
+	// break the payload so processing fails permanently
+	tx.Model(&models.WebhookInboxEventModel{}).Where("id = ?", accepted.EventID).
+		Update("payload", []byte(`{"organizationId":999999,"email":"webhook@example.com","message":"x"}`))
+
+	service.ProcessInbox(1, 10)
+
+	var event models.WebhookInboxEventModel
+	tx.First(&event, accepted.EventID)
+	if event.Status != models.WebhookInboxStatusParked {
+		t.Fatalf("expected status parked, got %s", event.Status)
+	}
+
+	if err := service.RetryInboxEvent(event.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tx.First(&event, accepted.EventID)
+	if event.Status != models.WebhookInboxStatusPending || event.Attempts != 0 {
+		t.Errorf("expected event back in queue, got %s with %d attempts", event.Status, event.Attempts)
+	}
+}
+
+func TestWebHookConversationService_ProcessConversation_SetsAttributes(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewWebHookConversationService(tx)
+
+	org, _ := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+	tx.Create(&models.ContactAttributeDefinitionModel{
+		OrganizationID: org.ID,
+		Key:            "order_id",
+		Label:          "Order ID",
+		Type:           models.ContactAttributeTypeString,
+	})
+
+	err := service.ProcessConversation(requestdto.WebHooksRequest{
+		OrganizationID: org.ID,
+		Email:          "customer@example.com",
+		Message:        "where is my order?",
+		Attributes:     map[string]any{"order_id": "INV-42", "unknown": "dropped"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var values []models.ContactAttributeValueModel
+	tx.Where("organization_id = ?", org.ID).Find(&values)
+	if len(values) != 1 || values[0].Value != "INV-42" {
+		t.Errorf("expected only the known attribute to be stored, got %+v", values)
+	}
+}
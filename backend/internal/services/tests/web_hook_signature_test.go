@@ -0,0 +1,286 @@
+package tests
+
+import (
+	"DewaSRY/sociomile-app/internal/handlers"
+	"DewaSRY/sociomile-app/internal/routers"
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/outbound"
+	"DewaSRY/sociomile-app/pkg/lib/ratelimit"
+	"DewaSRY/sociomile-app/pkg/models"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+func setWebhookCallbackHosts(tx *gorm.DB, t *testing.T, org *models.OrganizationModel, hosts ...string) {
+	org.WebhookCallbackHosts = hosts
+	if err := tx.Model(org).Select("webhook_callback_hosts").Updates(org).Error; err != nil {
+		t.Fatalf("failed to set callback hosts: %v", err)
+	}
+}
+
+func signWebhook(secret string, body []byte) string {
+	timestamp := time.Now().Unix()
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, outbound.SignPayload(secret, timestamp, body))
+}
+
+func TestWebHookSignature_Route(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewWebHookConversationService(tx)
+	router := routers.WebHook{
+		WebHookHandler: *handlers.NewWebHookHandler(service, impl.NewOutboundDeliveryService(tx, outbound.NewRegistry(), 3)),
+		RateLimitService: impl.NewRateLimitService(tx, ratelimit.NewMemoryStore(), impl.RateLimitDefaults{
+			OrganizationPerMinute: 100,
+			ContactPerMinute:      100,
+			IPPerMinute:           100,
+		}),
+		WebHookService: service,
+	}
+	r := chi.NewRouter()
+	router.Register(r)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	other, _ := CreateTestOrganizationWithOwner(tx, t, "Other Org")
+	settings, err := service.RotateSecret(&jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID})
+	if err != nil || settings.Secret == "" || !settings.SecretConfigured {
+		t.Fatalf("expected a new secret, got %+v %v", settings, err)
+	}
+
+	send := func(organizationID uint, signature string, body []byte) int {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/conversations", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Sociomile-Organization", strconv.FormatUint(uint64(organizationID), 10))
+		req.Header.Set(outbound.SignatureHeader, signature)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	body := []byte(fmt.Sprintf(`{"organizationId":%d,"email":"customer@example.com","message":"hello"}`, org.ID))
+	if code := send(org.ID, signWebhook(settings.Secret, body), body); code != http.StatusAccepted {
+		t.Errorf("expected the signed request to be accepted, got %d", code)
+	}
+	if code := send(org.ID, signWebhook("whsec_wrong", body), body); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for another secret, got %d", code)
+	}
+	stale := time.Now().Add(-time.Hour).Unix()
+	if code := send(org.ID, fmt.Sprintf("t=%d,v1=%s", stale, outbound.SignPayload(settings.Secret, stale, body)), body); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for an old signature, got %d", code)
+	}
+
+	// the body cannot name an organization other than the signed one
+	foreign := []byte(fmt.Sprintf(`{"organizationId":%d,"email":"customer@example.com","message":"hello"}`, other.ID))
+	if code := send(org.ID, signWebhook(settings.Secret, foreign), foreign); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for another organization in the body, got %d", code)
+	}
+	// an organization without a secret takes no signed requests
+	if code := send(other.ID, signWebhook("", foreign), foreign); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for an organization without a secret, got %d", code)
+	}
+}
+
+func TestWebHookSignature_RequiredOptIn(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewWebHookConversationService(tx)
+	router := routers.WebHook{
+		WebHookHandler: *handlers.NewWebHookHandler(service, impl.NewOutboundDeliveryService(tx, outbound.NewRegistry(), 3)),
+		RateLimitService: impl.NewRateLimitService(tx, ratelimit.NewMemoryStore(), impl.RateLimitDefaults{
+			OrganizationPerMinute: 100,
+			ContactPerMinute:      100,
+			IPPerMinute:           100,
+		}),
+		WebHookService: service,
+	}
+	r := chi.NewRouter()
+	router.Register(r)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+
+	// integrations set up before signing post without any header
+	send := func(signature string, body []byte) int {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/conversations", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if signature != "" {
+			req.Header.Set(outbound.SignatureHeader, signature)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	body := []byte(fmt.Sprintf(`{"organizationId":%d,"email":"customer@example.com","message":"hello"}`, org.ID))
+	if code := send("", body); code != http.StatusAccepted {
+		t.Errorf("expected an unsigned request to be accepted, got %d", code)
+	}
+
+	required := true
+	if _, err := service.UpdateSettings(claims, requestdto.UpdateWebhookSettingsRequest{SignatureRequired: &required}); !errors.Is(err, impl.ErrWebhookSecretNotConfigured) {
+		t.Errorf("expected ErrWebhookSecretNotConfigured, got %v", err)
+	}
+
+	settings, err := service.RotateSecret(claims)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if code := send("", body); code != http.StatusAccepted {
+		t.Errorf("expected unsigned requests until signatures are required, got %d", code)
+	}
+	if code := send(signWebhook(settings.Secret, body), body); code != http.StatusAccepted {
+		t.Errorf("expected the signed request to be accepted, got %d", code)
+	}
+
+	updated, err := service.UpdateSettings(claims, requestdto.UpdateWebhookSettingsRequest{SignatureRequired: &required})
+	if err != nil || !updated.SignatureRequired {
+		t.Fatalf("expected signatures to be required, got %+v %v", updated, err)
+	}
+	if code := send("", body); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a signature, got %d", code)
+	}
+	if code := send(signWebhook(settings.Secret, body), body); code != http.StatusAccepted {
+		t.Errorf("expected the signed request to be accepted, got %d", code)
+	}
+
+	// updating the hosts alone keeps signatures required
+	updated, err = service.UpdateSettings(claims, requestdto.UpdateWebhookSettingsRequest{CallbackHosts: []string{"api.example.com"}})
+	if err != nil || !updated.SignatureRequired {
+		t.Errorf("expected signatures to stay required, got %+v %v", updated, err)
+	}
+}
+
+func TestWebHookSignature_RateLimit(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewWebHookConversationService(tx)
+	router := routers.WebHook{
+		WebHookHandler: *handlers.NewWebHookHandler(service, impl.NewOutboundDeliveryService(tx, outbound.NewRegistry(), 3)),
+		RateLimitService: impl.NewRateLimitService(tx, ratelimit.NewMemoryStore(), impl.RateLimitDefaults{
+			OrganizationPerMinute: 1,
+			ContactPerMinute:      100,
+			IPPerMinute:           3,
+		}),
+		WebHookService: service,
+	}
+	r := chi.NewRouter()
+	router.Register(r)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+	settings, err := service.RotateSecret(claims)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	required := true
+	if _, err := service.UpdateSettings(claims, requestdto.UpdateWebhookSettingsRequest{SignatureRequired: &required}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	send := func(remoteAddr, signature string, body []byte) int {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/conversations", bytes.NewReader(body))
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Sociomile-Organization", strconv.FormatUint(uint64(org.ID), 10))
+		req.Header.Set(outbound.SignatureHeader, signature)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// unsigned requests naming the organization do not use up its limit
+	body := []byte(fmt.Sprintf(`{"organizationId":%d,"email":"customer@example.com","message":"hello"}`, org.ID))
+	for i, want := range []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests} {
+		if code := send("203.0.113.7:4000", "", body); code != want {
+			t.Errorf("unsigned request %d: expected %d, got %d", i+1, want, code)
+		}
+	}
+
+	if code := send("198.51.100.1:4000", signWebhook(settings.Secret, body), body); code != http.StatusAccepted {
+		t.Errorf("expected the signed request to be accepted, got %d", code)
+	}
+	if code := send("198.51.100.2:4000", signWebhook(settings.Secret, body), body); code != http.StatusTooManyRequests {
+		t.Errorf("expected the organization limit from another address, got %d", code)
+	}
+}
+
+func TestWebHookSignature_CallbackHosts(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewWebHookConversationService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Org")
+	claims := &jwtLib.Claims{UserID: owner.ID, RoleID: owner.RoleID, OrganizationId: &org.ID}
+	if _, err := service.UpdateSettings(claims, requestdto.UpdateWebhookSettingsRequest{CallbackHosts: []string{"https://bad"}}); !errors.Is(err, impl.ErrWebhookCallbackHostInvalid) {
+		t.Errorf("expected ErrWebhookCallbackHostInvalid, got %v", err)
+	}
+	if _, err := service.UpdateSettings(claims, requestdto.UpdateWebhookSettingsRequest{CallbackHosts: []string{"*.Example.com"}}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for _, callback := range []string{"http://api.example.com/replies", "https://example.org/replies", "https://127.0.0.1/replies"} {
+		_, err := service.EnqueueConversation(requestdto.WebHooksRequest{
+			OrganizationID: org.ID,
+			Email:          "customer@example.com",
+			Message:        "hello",
+			CallbackURL:    callback,
+		})
+		if !errors.Is(err, impl.ErrWebhookCallbackNotAllowed) {
+			t.Errorf("%s: expected ErrWebhookCallbackNotAllowed, got %v", callback, err)
+		}
+	}
+
+	first := "https://api.example.com/replies"
+	for _, callback := range []string{first, "https://hooks.example.com/other"} {
+		if err := service.ProcessConversation(requestdto.WebHooksRequest{
+			OrganizationID: org.ID,
+			Email:          "customer@example.com",
+			Message:        "hello",
+			CallbackURL:    callback,
+		}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	var conversation models.ConversationModel
+	tx.Where("organization_id = ?", org.ID).First(&conversation)
+	if conversation.CallbackURL == nil || *conversation.CallbackURL != first {
+		t.Errorf("expected the first callback url to be kept, got %v", conversation.CallbackURL)
+	}
+
+	// a host removed later no longer receives the replies
+	setWebhookCallbackHosts(tx, t, org, "other.example.net")
+	if err := impl.NewConversationService(tx).SendMessage(claims, conversation.ID, requestdto.CreateStaffMessageRequest{Message: "Thanks"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var delivery models.OutboundDeliveryModel
+	tx.Where("conversation_id = ?", conversation.ID).First(&delivery)
+	if delivery.Destination != "" {
+		t.Errorf("expected no destination for a removed host, got %s", delivery.Destination)
+	}
+}
+
+func TestWebHookSignature_DispatcherRefusesInternalAddresses(t *testing.T) {
+	dispatcher := outbound.NewWebhookDispatcher(models.ConversationChannelWebhook, time.Second)
+
+	for destination, want := range map[string]error{
+		"http://example.com/replies":     outbound.ErrCallbackNotHTTPS,
+		"https://127.0.0.1/replies":      outbound.ErrCallbackAddressBlocked,
+		"https://localhost/replies":      outbound.ErrCallbackAddressBlocked,
+		"https://10.0.0.8/replies":       outbound.ErrCallbackAddressBlocked,
+		"https://169.254.169.254/latest": outbound.ErrCallbackAddressBlocked,
+		"https://[::1]/replies":          outbound.ErrCallbackAddressBlocked,
+		"https://[fd00::1]:8443/replies": outbound.ErrCallbackAddressBlocked,
+	} {
+		_, err := dispatcher.Dispatch(outbound.Delivery{ID: 1, Channel: models.ConversationChannelWebhook, Destination: destination, Payload: []byte("{}")})
+		if !errors.Is(err, want) {
+			t.Errorf("%s: expected %v, got %v", destination, want, err)
+		}
+	}
+}
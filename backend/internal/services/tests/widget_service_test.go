@@ -0,0 +1,243 @@
+package tests
+
+import (
+	"DewaSRY/sociomile-app/internal/services/impl"
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/lib/mail"
+	"DewaSRY/sociomile-app/pkg/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWidgetService_VisitorConversation(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewWidgetService(tx, time.Hour)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+	claims := &jwtLib.Claims{UserID: owner.ID, OrganizationId: &org.ID}
+
+	settings, err := service.UpdateSettings(claims, requestdto.UpdateWidgetSettingsRequest{
+		Slug:           "test-widget-org",
+		AllowedOrigins: []string{"https://Shop.Example.com/", "https://*.example.org"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(settings.AllowedOrigins) != 2 || settings.AllowedOrigins[0] != "https://shop.example.com" {
+		t.Errorf("expected normalized origins, got %+v", settings.AllowedOrigins)
+	}
+
+	session, err := service.StartSession("test-widget-org", "", requestdto.StartWidgetSessionRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if session.Token == "" || session.Identified || session.Organization.ID != org.ID {
+		t.Errorf("expected an anonymous session of the organization, got %+v", session)
+	}
+
+	again, err := service.StartSession("test-widget-org", session.Token, requestdto.StartWidgetSessionRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if again.Token != session.Token || again.ContactID != session.ContactID {
+		t.Errorf("expected the valid session to be kept, got %+v", again)
+	}
+
+	visitor, err := service.Authenticate("test-widget-org", session.Token)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	conversation, err := service.CreateConversation(visitor, requestdto.CreateWidgetConversationRequest{Message: "hi, is this in stock?"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if conversation.Status != models.ConversationStatusPending {
+		t.Errorf("expected a pending conversation, got %s", conversation.Status)
+	}
+
+	reply := models.ConversationMessageModel{
+		OrganizationID: org.ID,
+		ConversationID: conversation.ID,
+		CreatedByID:    &owner.ID,
+		Message:        "yes it is",
+	}
+	tx.Create(&reply)
+	if _, err := service.SendMessage(visitor, conversation.ID, requestdto.WidgetMessageRequest{Message: "great"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	page, limit := 1, 10
+	messages, err := service.GetMessages(visitor, conversation.ID, filtersdto.FiltersDto{Page: &page, Limit: &limit})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if messages.Metadata.Total != 3 || !messages.Data[0].FromVisitor || messages.Data[1].FromVisitor {
+		t.Errorf("expected visitor, staff and visitor messages, got %+v", messages.Data)
+	}
+	if messages.Data[1].AuthorName == nil || *messages.Data[1].AuthorName != owner.Name {
+		t.Errorf("expected the staff name on the reply, got %+v", messages.Data[1])
+	}
+
+	var stored models.ConversationModel
+	tx.First(&stored, conversation.ID)
+	if stored.Channel != models.ConversationChannelWidget || stored.ContactID != session.ContactID {
+		t.Errorf("expected a widget conversation of the visitor, got %+v", stored)
+	}
+}
+
+func TestWidgetService_OtherVisitorCannotRead(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewWidgetService(tx, time.Hour)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+	claims := &jwtLib.Claims{UserID: owner.ID, OrganizationId: &org.ID}
+	if _, err := service.UpdateSettings(claims, requestdto.UpdateWidgetSettingsRequest{Slug: "test-widget-org"}); err != nil {
+		t.Fatalf("failed to set slug: %v", err)
+	}
+
+	first, _ := service.StartSession("test-widget-org", "", requestdto.StartWidgetSessionRequest{})
+	second, _ := service.StartSession("test-widget-org", "", requestdto.StartWidgetSessionRequest{})
+	if first.ContactID == second.ContactID {
+		t.Fatalf("expected two visitors, got the same contact")
+	}
+
+	firstVisitor, _ := service.Authenticate("test-widget-org", first.Token)
+	secondVisitor, _ := service.Authenticate("test-widget-org", second.Token)
+	conversation, _ := service.CreateConversation(firstVisitor, requestdto.CreateWidgetConversationRequest{Message: "private"})
+
+	page, limit := 1, 10
+	_, err := service.GetMessages(secondVisitor, conversation.ID, filtersdto.FiltersDto{Page: &page, Limit: &limit})
+	if !errors.Is(err, impl.ErrWidgetConversationNotFound) {
+		t.Errorf("expected ErrWidgetConversationNotFound, got %v", err)
+	}
+
+	if _, err := service.Authenticate("other-org", first.Token); !errors.Is(err, impl.ErrWidgetSessionInvalid) {
+		t.Errorf("expected the token to be scoped to its organization, got %v", err)
+	}
+	if _, err := service.Authenticate("test-widget-org", "not-a-token"); !errors.Is(err, impl.ErrWidgetSessionInvalid) {
+		t.Errorf("expected ErrWidgetSessionInvalid, got %v", err)
+	}
+}
+
+func TestWidgetService_IdentifyVisitor(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewWidgetService(tx, time.Hour)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+	claims := &jwtLib.Claims{UserID: owner.ID, OrganizationId: &org.ID}
+	if _, err := service.UpdateSettings(claims, requestdto.UpdateWidgetSettingsRequest{Slug: "test-widget-org"}); err != nil {
+		t.Fatalf("failed to set slug: %v", err)
+	}
+
+	session, _ := service.StartSession("test-widget-org", "", requestdto.StartWidgetSessionRequest{})
+	visitor, _ := service.Authenticate("test-widget-org", session.Token)
+
+	name := "Jane"
+	identified, err := service.Identify(visitor, session.Token, requestdto.IdentifyWidgetVisitorRequest{Email: "Jane@Example.com", Name: &name})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !identified.Identified || identified.Name != "Jane" {
+		t.Errorf("expected an identified visitor named Jane, got %+v", identified)
+	}
+
+	// an address nobody owns yet is still only claimed
+	var identity models.ContactIdentityModel
+	if err := tx.Where("contact_id = ? AND type = ?", session.ContactID, models.ContactIdentityTypeClaimedEmail).
+		First(&identity).Error; err != nil {
+		t.Fatalf("expected a claimed email, got %v", err)
+	}
+	if identity.MatchKey == nil || *identity.MatchKey != "email:jane@example.com" {
+		t.Errorf("expected the claimed email to be suggested for a merge, got %v", identity.MatchKey)
+	}
+	var emails int64
+	tx.Model(&models.ContactIdentityModel{}).
+		Where("organization_id = ? AND type = ?", org.ID, models.ContactIdentityTypeEmail).
+		Count(&emails)
+	if emails != 0 {
+		t.Errorf("expected no email identity from the widget, got %d", emails)
+	}
+
+	// mail from the address later goes to a contact of its own
+	tx.Model(org).Update("inbound_email", "support@testorg.example.com")
+	if err := impl.NewEmailConversationService(tx).ProcessInboundEmail("support@testorg.example.com", &mail.InboundMail{
+		MessageID:   "<order@example.com>",
+		FromAddress: "jane@example.com",
+		Subject:     "Order",
+		Text:        "Where is my order?",
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var conversation models.ConversationModel
+	tx.Where("organization_id = ? AND channel = ?", org.ID, models.ConversationChannelEmail).First(&conversation)
+	if conversation.ID == 0 || conversation.ContactID == session.ContactID {
+		t.Errorf("expected the mail outside the visitor's contact, got %+v", conversation)
+	}
+}
+
+func TestWidgetService_SessionEndsOnMerge(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewWidgetService(tx, time.Hour)
+	contactService := impl.NewContactService(tx)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+	claims := &jwtLib.Claims{UserID: owner.ID, OrganizationId: &org.ID}
+	if _, err := service.UpdateSettings(claims, requestdto.UpdateWidgetSettingsRequest{Slug: "test-widget-org"}); err != nil {
+		t.Fatalf("failed to set slug: %v", err)
+	}
+
+	session, _ := service.StartSession("test-widget-org", "", requestdto.StartWidgetSessionRequest{})
+	target, _ := contactService.CreateContact(claims, requestdto.CreateContactRequest{
+		Name:       "Jane",
+		Identities: []requestdto.ContactIdentityRequest{{Type: "email", Value: "jane@example.com"}},
+	})
+
+	merge, err := contactService.MergeContact(claims, target.ID, requestdto.MergeContactRequest{SourceContactID: session.ContactID})
+	if err != nil {
+		t.Fatalf("failed to merge: %v", err)
+	}
+	if _, err := service.Authenticate("test-widget-org", session.Token); !errors.Is(err, impl.ErrWidgetSessionInvalid) {
+		t.Fatalf("expected the session to end on the merge, got %v", err)
+	}
+
+	if _, err := contactService.UndoMerge(claims, merge.ID); err != nil {
+		t.Fatalf("failed to undo merge: %v", err)
+	}
+	visitor, err := service.Authenticate("test-widget-org", session.Token)
+	if err != nil || visitor.ContactID != session.ContactID {
+		t.Errorf("expected the session back on the visitor, got %+v, %v", visitor, err)
+	}
+}
+
+func TestWidgetService_UpdateSettings_Validation(t *testing.T) {
+	tx := SetupTestDB(t)
+	service := impl.NewWidgetService(tx, time.Hour)
+
+	org, owner := CreateTestOrganizationWithOwner(tx, t, "Test Organization")
+	claims := &jwtLib.Claims{UserID: owner.ID, OrganizationId: &org.ID}
+	otherOrg, otherOwner := CreateTestOrganizationWithOwner(tx, t, "Other Organization")
+	otherClaims := &jwtLib.Claims{UserID: otherOwner.ID, OrganizationId: &otherOrg.ID}
+
+	if _, err := service.UpdateSettings(claims, requestdto.UpdateWidgetSettingsRequest{Slug: "Not A Slug"}); !errors.Is(err, impl.ErrWidgetSlugInvalid) {
+		t.Errorf("expected ErrWidgetSlugInvalid, got %v", err)
+	}
+	if _, err := service.UpdateSettings(claims, requestdto.UpdateWidgetSettingsRequest{
+		Slug:           "test-widget-org",
+		AllowedOrigins: []string{"https://shop.example.com/checkout"},
+	}); !errors.Is(err, impl.ErrWidgetOriginInvalid) {
+		t.Errorf("expected ErrWidgetOriginInvalid, got %v", err)
+	}
+	if _, err := service.UpdateSettings(claims, requestdto.UpdateWidgetSettingsRequest{Slug: "test-widget-org"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := service.UpdateSettings(otherClaims, requestdto.UpdateWidgetSettingsRequest{Slug: "test-widget-org"}); !errors.Is(err, impl.ErrWidgetSlugTaken) {
+		t.Errorf("expected ErrWidgetSlugTaken, got %v", err)
+	}
+	if _, err := service.AllowedOrigins("missing-org"); !errors.Is(err, impl.ErrWidgetNotFound) {
+		t.Errorf("expected ErrWidgetNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+package services
+
+import (
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/lib/jwt"
+)
+
+type TicketFieldService interface {
+	GetFields(user *jwt.Claims) ([]responsedto.TicketFieldResponse, error)
+	CreateField(user *jwt.Claims, req requestdto.CreateTicketFieldRequest) (*responsedto.TicketFieldResponse, error)
+	UpdateField(user *jwt.Claims, fieldID uint, req requestdto.UpdateTicketFieldRequest) (*responsedto.TicketFieldResponse, error)
+	DeleteField(user *jwt.Claims, fieldID uint) error
+}
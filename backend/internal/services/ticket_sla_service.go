@@ -0,0 +1,18 @@
+package services
+
+import (
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+)
+
+type TicketSLAService interface {
+	GetPolicies(user *jwtLib.Claims) (*responsedto.TicketSLAPolicyListResponse, error)
+	CreatePolicy(user *jwtLib.Claims, req requestdto.TicketSLAPolicyRequest) (*responsedto.TicketSLAPolicyResponse, error)
+	UpdatePolicy(user *jwtLib.Claims, policyID uint, req requestdto.TicketSLAPolicyRequest) (*responsedto.TicketSLAPolicyResponse, error)
+	DeletePolicy(user *jwtLib.Claims, policyID uint) error
+
+	// ProcessDueTickets runs the warning and breach stages of the tickets
+	// whose deadlines passed and returns how many it handled.
+	ProcessDueTickets(limit int) (int, error)
+}
@@ -0,0 +1,18 @@
+package services
+
+import (
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+)
+
+type TicketStatusService interface {
+	GetGuestTickets(user *jwtLib.Claims, filter filtersdto.FiltersDto) (*responsedto.GuestTicketListResponse, error)
+	GetGuestTicket(user *jwtLib.Claims, ticketID uint) (*responsedto.GuestTicketResponse, error)
+
+	// GetTicketStatus reads the ticket of a signed status link, no account
+	// is needed.
+	GetTicketStatus(token string) (*responsedto.GuestTicketResponse, error)
+	CreateStatusLink(user *jwtLib.Claims, ticketID uint, req requestdto.CreateTicketStatusLinkRequest) (*responsedto.TicketStatusLinkResponse, error)
+}
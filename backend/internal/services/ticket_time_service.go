@@ -0,0 +1,19 @@
+package services
+
+import (
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	jwtLib "DewaSRY/sociomile-app/pkg/lib/jwt"
+)
+
+type TicketTimeService interface {
+	GetEntries(user *jwtLib.Claims, ticketID uint) (*responsedto.TicketTimeEntryListResponse, error)
+	CreateEntry(user *jwtLib.Claims, ticketID uint, req requestdto.CreateTicketTimeEntryRequest) (*responsedto.TicketTimeEntryResponse, error)
+	DeleteEntry(user *jwtLib.Claims, ticketID uint, entryID uint) error
+
+	StartTimer(user *jwtLib.Claims, ticketID uint, req requestdto.StartTicketTimerRequest) (*responsedto.TicketTimeEntryResponse, error)
+	StopTimer(user *jwtLib.Claims, ticketID uint, req requestdto.StopTicketTimerRequest) (*responsedto.TicketTimeEntryResponse, error)
+
+	GetReport(user *jwtLib.Claims, filter filtersdto.TicketTimeReportFiltersDto) (*responsedto.TicketTimeReportResponse, error)
+}
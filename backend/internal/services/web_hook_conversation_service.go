@@ -1,8 +1,26 @@
 package services
 
-import "DewaSRY/sociomile-app/pkg/dtos/requestdto"
+import (
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/lib/jwt"
+)
 
 
 type WebHookConversationService interface{
 	ProcessConversation(requestdto.WebHooksRequest) (error)
-}
\ No newline at end of file
+
+	EnqueueConversation(req requestdto.WebHooksRequest) (*responsedto.WebhookAcceptedResponse, error)
+	ProcessInbox(workerCount int, batchSize int) (int, error)
+	GetInboxEvents(filter filtersdto.FiltersDto, status string) (*responsedto.WebhookInboxEventPaginateResponse, error)
+	RetryInboxEvent(eventID uint) error
+
+	// VerifySignature checks the signature header of a webhook request
+	// against the secret of the organization it claims to come from,
+	// unsigned requests pass until the organization requires signatures
+	VerifySignature(organizationID uint, signature string, body []byte) error
+	GetSettings(user *jwt.Claims) (*responsedto.WebhookSettingsResponse, error)
+	UpdateSettings(user *jwt.Claims, req requestdto.UpdateWebhookSettingsRequest) (*responsedto.WebhookSettingsResponse, error)
+	RotateSecret(user *jwt.Claims) (*responsedto.WebhookSettingsResponse, error)
+}
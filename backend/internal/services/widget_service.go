@@ -0,0 +1,24 @@
+package services
+
+import (
+	"DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+	"DewaSRY/sociomile-app/pkg/dtos/requestdto"
+	"DewaSRY/sociomile-app/pkg/dtos/responsedto"
+	"DewaSRY/sociomile-app/pkg/lib/jwt"
+	"DewaSRY/sociomile-app/pkg/models"
+)
+
+type WidgetService interface {
+	AllowedOrigins(slug string) ([]string, error)
+	StartSession(slug string, currentToken string, req requestdto.StartWidgetSessionRequest) (*responsedto.WidgetSessionResponse, error)
+	Authenticate(slug string, token string) (*models.WidgetSessionModel, error)
+	Identify(session *models.WidgetSessionModel, token string, req requestdto.IdentifyWidgetVisitorRequest) (*responsedto.WidgetSessionResponse, error)
+
+	GetConversations(session *models.WidgetSessionModel, filter filtersdto.FiltersDto) (*responsedto.WidgetConversationPaginateResponse, error)
+	CreateConversation(session *models.WidgetSessionModel, req requestdto.CreateWidgetConversationRequest) (*responsedto.WidgetConversationResponse, error)
+	GetMessages(session *models.WidgetSessionModel, conversationID uint, filter filtersdto.FiltersDto) (*responsedto.WidgetMessagePaginateResponse, error)
+	SendMessage(session *models.WidgetSessionModel, conversationID uint, req requestdto.WidgetMessageRequest) (*responsedto.WidgetMessageResponse, error)
+
+	GetSettings(user *jwt.Claims) (*responsedto.WidgetSettingsResponse, error)
+	UpdateSettings(user *jwt.Claims, req requestdto.UpdateWidgetSettingsRequest) (*responsedto.WidgetSettingsResponse, error)
+}
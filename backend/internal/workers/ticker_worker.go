@@ -0,0 +1,64 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+)
+
+// tickerWorker runs a task every interval until it is shut down. A run that
+// is in progress during shutdown is allowed to finish.
+type tickerWorker struct {
+	name     string
+	interval time.Duration
+	task     func()
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func NewTickerWorker(name string, interval time.Duration, task func()) Worker {
+	return &tickerWorker{
+		name:     name,
+		interval: interval,
+		task:     task,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start implements Worker.
+func (t *tickerWorker) Start() {
+	logger.InfoLog("Worker running", map[string]any{
+		"worker":   t.name,
+		"interval": t.interval.String(),
+	})
+
+	go func() {
+		defer close(t.done)
+
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-t.stop:
+				return
+			case <-ticker.C:
+				t.task()
+			}
+		}
+	}()
+}
+
+// Shutdown implements Worker.
+func (t *tickerWorker) Shutdown(ctx context.Context) error {
+	close(t.stop)
+
+	select {
+	case <-t.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
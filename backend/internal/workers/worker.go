@@ -0,0 +1,10 @@
+package workers
+
+import "context"
+
+// Worker is a long running process started next to the http server and
+// stopped during graceful shutdown.
+type Worker interface {
+	Start()
+	Shutdown(ctx context.Context) error
+}
@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+ALTER TABLE organizations
+    ADD COLUMN inbound_email VARCHAR(255) NULL,
+    ADD UNIQUE INDEX idx_organizations_inbound_email (inbound_email);
+
+ALTER TABLE conversations
+    ADD COLUMN channel VARCHAR(50) NOT NULL DEFAULT 'web',
+    ADD COLUMN subject VARCHAR(998) NULL,
+    ADD INDEX idx_conversations_channel (channel);
+
+-- customers created by the webhook never got a password
+UPDATE conversations
+    JOIN users ON users.id = conversations.guest_id
+    SET conversations.channel = 'webhook'
+    WHERE users.password = '';
+
+ALTER TABLE conversation_messages
+    ADD COLUMN html_message MEDIUMTEXT NULL,
+    ADD COLUMN external_message_id VARCHAR(998) NULL,
+    ADD INDEX idx_conversation_messages_external_message_id (external_message_id(255));
+
+CREATE TABLE conversation_message_attachments (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    message_id BIGINT UNSIGNED NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size INT NOT NULL,
+    content LONGBLOB NOT NULL,
+    INDEX idx_conversation_message_attachments_deleted_at (deleted_at),
+    INDEX idx_conversation_message_attachments_organization_id (organization_id),
+    INDEX idx_conversation_message_attachments_message_id (message_id),
+    CONSTRAINT fk_conversation_message_attachments_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_conversation_message_attachments_message_id FOREIGN KEY (message_id) REFERENCES conversation_messages(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE IF EXISTS conversation_message_attachments;
+
+ALTER TABLE conversation_messages
+    DROP INDEX idx_conversation_messages_external_message_id,
+    DROP COLUMN external_message_id,
+    DROP COLUMN html_message;
+
+ALTER TABLE conversations
+    DROP INDEX idx_conversations_channel,
+    DROP COLUMN subject,
+    DROP COLUMN channel;
+
+ALTER TABLE organizations
+    DROP INDEX idx_organizations_inbound_email,
+    DROP COLUMN inbound_email;
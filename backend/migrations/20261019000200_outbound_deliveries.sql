@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+ALTER TABLE conversations
+    ADD COLUMN callback_url VARCHAR(2048) NULL;
+
+CREATE TABLE outbound_deliveries (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    conversation_id BIGINT UNSIGNED NOT NULL,
+    message_id BIGINT UNSIGNED NOT NULL,
+    channel VARCHAR(50) NOT NULL,
+    destination VARCHAR(2048) NOT NULL,
+    payload MEDIUMBLOB NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'queued',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT NULL,
+    receipt_token VARCHAR(64) NOT NULL,
+    provider_message_id VARCHAR(255) NULL,
+    sent_at TIMESTAMP NULL DEFAULT NULL,
+    delivered_at TIMESTAMP NULL DEFAULT NULL,
+    UNIQUE INDEX idx_outbound_deliveries_message_id (message_id),
+    INDEX idx_outbound_deliveries_deleted_at (deleted_at),
+    INDEX idx_outbound_deliveries_organization_id (organization_id),
+    INDEX idx_outbound_deliveries_conversation_id (conversation_id),
+    INDEX idx_outbound_deliveries_due (status, next_attempt_at),
+    CONSTRAINT fk_outbound_deliveries_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_outbound_deliveries_conversation_id FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_outbound_deliveries_message_id FOREIGN KEY (message_id) REFERENCES conversation_messages(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE IF EXISTS outbound_deliveries;
+
+ALTER TABLE conversations
+    DROP COLUMN callback_url;
@@ -0,0 +1,54 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+CREATE TABLE event_subscriptions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    url VARCHAR(2048) NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    event_types JSON NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    INDEX idx_event_subscriptions_deleted_at (deleted_at),
+    INDEX idx_event_subscriptions_organization_id (organization_id),
+    CONSTRAINT fk_event_subscriptions_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
+
+CREATE TABLE event_deliveries (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    subscription_id BIGINT UNSIGNED NOT NULL,
+    event_id VARCHAR(64) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload MEDIUMBLOB NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    response_code INT NULL,
+    last_error TEXT NULL,
+    delivered_at TIMESTAMP NULL DEFAULT NULL,
+    replay_of_id BIGINT UNSIGNED NULL,
+    INDEX idx_event_deliveries_deleted_at (deleted_at),
+    INDEX idx_event_deliveries_organization_id (organization_id),
+    INDEX idx_event_deliveries_subscription_id (subscription_id),
+    INDEX idx_event_deliveries_event_id (event_id),
+    INDEX idx_event_deliveries_replay_of_id (replay_of_id),
+    INDEX idx_event_deliveries_due (status, next_attempt_at),
+    CONSTRAINT fk_event_deliveries_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_event_deliveries_subscription_id FOREIGN KEY (subscription_id) REFERENCES event_subscriptions(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE IF EXISTS event_deliveries;
+DROP TABLE IF EXISTS event_subscriptions;
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+CREATE TABLE webhook_inbox_events (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    contact_key VARCHAR(255) NOT NULL,
+    payload MEDIUMBLOB NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT NULL,
+    processed_at TIMESTAMP NULL DEFAULT NULL,
+    INDEX idx_webhook_inbox_events_deleted_at (deleted_at),
+    INDEX idx_webhook_inbox_events_organization_id (organization_id),
+    INDEX idx_webhook_inbox_events_contact (organization_id, contact_key, status),
+    INDEX idx_webhook_inbox_events_due (status, next_attempt_at),
+    CONSTRAINT fk_webhook_inbox_events_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE IF EXISTS webhook_inbox_events;
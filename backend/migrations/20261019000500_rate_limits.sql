@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+CREATE TABLE organization_rate_limits (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    organization_per_minute INT NULL DEFAULT NULL,
+    contact_per_minute INT NULL DEFAULT NULL,
+    INDEX idx_organization_rate_limits_deleted_at (deleted_at),
+    UNIQUE INDEX idx_organization_rate_limits_organization_id (organization_id),
+    CONSTRAINT fk_organization_rate_limits_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
+
+CREATE TABLE rate_limit_hits (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    organization_id BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    scope VARCHAR(50) NOT NULL,
+    route VARCHAR(100) NOT NULL,
+    window_start TIMESTAMP NOT NULL,
+    hits INT NOT NULL DEFAULT 0,
+    UNIQUE INDEX idx_rate_limit_hits_window (organization_id, scope, route, window_start),
+    INDEX idx_rate_limit_hits_window_start (window_start)
+);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE IF EXISTS rate_limit_hits;
+DROP TABLE IF EXISTS organization_rate_limits;
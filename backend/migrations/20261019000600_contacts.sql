@@ -0,0 +1,119 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+CREATE TABLE contacts (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    avatar_url VARCHAR(2048) NULL,
+    user_id BIGINT UNSIGNED NULL,
+    INDEX idx_contacts_deleted_at (deleted_at),
+    INDEX idx_contacts_organization_id (organization_id),
+    INDEX idx_contacts_user_id (user_id),
+    CONSTRAINT fk_contacts_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_contacts_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE TABLE contact_identities (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    contact_id BIGINT UNSIGNED NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    value VARCHAR(255) NOT NULL,
+    UNIQUE INDEX idx_contact_identities_value (organization_id, type, value),
+    INDEX idx_contact_identities_contact_id (contact_id),
+    CONSTRAINT fk_contact_identities_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_contact_identities_contact_id FOREIGN KEY (contact_id) REFERENCES contacts(id) ON DELETE CASCADE
+);
+
+-- one contact per organization for every user that opened a conversation
+INSERT INTO contacts (organization_id, name, user_id)
+SELECT DISTINCT conversations.organization_id, users.name, users.id
+FROM conversations
+JOIN users ON users.id = conversations.guest_id;
+
+INSERT INTO contact_identities (organization_id, contact_id, type, value)
+SELECT contacts.organization_id, contacts.id, 'email', LOWER(users.email)
+FROM contacts
+JOIN users ON users.id = contacts.user_id;
+
+ALTER TABLE conversations
+    DROP FOREIGN KEY fk_conversations_guest_id;
+
+ALTER TABLE conversations
+    MODIFY guest_id BIGINT UNSIGNED NULL,
+    ADD COLUMN contact_id BIGINT UNSIGNED NULL AFTER guest_id,
+    ADD INDEX idx_conversations_contact_id (contact_id);
+
+UPDATE conversations
+    JOIN contacts ON contacts.organization_id = conversations.organization_id
+        AND contacts.user_id = conversations.guest_id
+    SET conversations.contact_id = contacts.id;
+
+ALTER TABLE conversation_messages
+    MODIFY created_by_id BIGINT UNSIGNED NULL,
+    ADD COLUMN contact_id BIGINT UNSIGNED NULL AFTER created_by_id,
+    ADD INDEX idx_conversation_messages_contact_id (contact_id);
+
+UPDATE conversation_messages
+    JOIN conversations ON conversations.id = conversation_messages.conversation_id
+    SET conversation_messages.contact_id = conversations.contact_id
+    WHERE conversation_messages.created_by_id = conversations.guest_id;
+
+-- customers created by the webhook never got a password and cannot log in,
+-- from now on they only exist as contacts. The user rows are kept, they are
+-- only detached from the conversations.
+UPDATE conversation_messages
+    JOIN users ON users.id = conversation_messages.created_by_id
+    SET conversation_messages.created_by_id = NULL
+    WHERE users.password = '';
+
+UPDATE conversations
+    JOIN users ON users.id = conversations.guest_id
+    SET conversations.guest_id = NULL
+    WHERE users.password = '';
+
+UPDATE contacts
+    JOIN users ON users.id = contacts.user_id
+    SET contacts.user_id = NULL
+    WHERE users.password = '';
+
+ALTER TABLE conversations
+    MODIFY contact_id BIGINT UNSIGNED NOT NULL,
+    ADD CONSTRAINT fk_conversations_guest_id FOREIGN KEY (guest_id) REFERENCES users(id) ON DELETE SET NULL,
+    ADD CONSTRAINT fk_conversations_contact_id FOREIGN KEY (contact_id) REFERENCES contacts(id) ON DELETE CASCADE;
+
+ALTER TABLE conversation_messages
+    ADD CONSTRAINT fk_conversation_messages_contact_id FOREIGN KEY (contact_id) REFERENCES contacts(id) ON DELETE SET NULL;
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+-- the passwordless users are not attached to their conversations again,
+-- guest_id and created_by_id stay nullable for the rows that pointed to them
+
+ALTER TABLE conversation_messages
+    DROP FOREIGN KEY fk_conversation_messages_contact_id,
+    DROP INDEX idx_conversation_messages_contact_id,
+    DROP COLUMN contact_id;
+
+ALTER TABLE conversations
+    DROP FOREIGN KEY fk_conversations_contact_id,
+    DROP FOREIGN KEY fk_conversations_guest_id;
+
+ALTER TABLE conversations
+    DROP INDEX idx_conversations_contact_id,
+    DROP COLUMN contact_id,
+    ADD CONSTRAINT fk_conversations_guest_id FOREIGN KEY (guest_id) REFERENCES users(id) ON DELETE CASCADE;
+
+DROP TABLE IF EXISTS contact_identities;
+DROP TABLE IF EXISTS contacts;
@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+ALTER TABLE contact_identities
+    ADD COLUMN match_key VARCHAR(255) NULL,
+    ADD INDEX idx_contact_identities_match_key (organization_id, match_key);
+
+-- emails match without their +tag
+UPDATE contact_identities
+SET match_key = CONCAT(
+    'email:',
+    SUBSTRING_INDEX(SUBSTRING_INDEX(value, '@', 1), '+', 1),
+    '@',
+    SUBSTRING_INDEX(value, '@', -1)
+)
+WHERE type = 'email' AND value LIKE '_%@_%';
+
+-- phone numbers and numeric channel ids match on their last nine digits
+UPDATE contact_identities
+SET match_key = CONCAT('phone:', RIGHT(REGEXP_REPLACE(value, '[^0-9]', ''), 9))
+WHERE type <> 'email'
+    AND value REGEXP '^[+]?[0-9]+$'
+    AND CHAR_LENGTH(REGEXP_REPLACE(value, '[^0-9]', '')) >= 9;
+
+CREATE TABLE contact_merges (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    target_contact_id BIGINT UNSIGNED NOT NULL,
+    source_contact_id BIGINT UNSIGNED NOT NULL,
+    merged_by_id BIGINT UNSIGNED NOT NULL,
+    identity_ids JSON NOT NULL,
+    conversation_ids JSON NOT NULL,
+    user_moved BOOLEAN NOT NULL DEFAULT FALSE,
+    undone_at TIMESTAMP NULL DEFAULT NULL,
+    undone_by_id BIGINT UNSIGNED NULL,
+    INDEX idx_contact_merges_organization_id (organization_id),
+    INDEX idx_contact_merges_target_contact_id (target_contact_id),
+    INDEX idx_contact_merges_source_contact_id (source_contact_id),
+    CONSTRAINT fk_contact_merges_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_contact_merges_target_contact_id FOREIGN KEY (target_contact_id) REFERENCES contacts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_contact_merges_source_contact_id FOREIGN KEY (source_contact_id) REFERENCES contacts(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE IF EXISTS contact_merges;
+
+ALTER TABLE contact_identities
+    DROP INDEX idx_contact_identities_match_key,
+    DROP COLUMN match_key;
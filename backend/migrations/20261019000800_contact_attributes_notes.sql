@@ -0,0 +1,75 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+CREATE TABLE contact_attribute_definitions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    `key` VARCHAR(64) NOT NULL,
+    label VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    options JSON NULL,
+    UNIQUE INDEX idx_contact_attribute_definitions_key (organization_id, `key`),
+    CONSTRAINT fk_contact_attribute_definitions_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
+
+CREATE TABLE contact_attribute_values (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    contact_id BIGINT UNSIGNED NOT NULL,
+    definition_id BIGINT UNSIGNED NOT NULL,
+    value VARCHAR(1000) NOT NULL,
+    UNIQUE INDEX idx_contact_attribute_values_contact (contact_id, definition_id),
+    INDEX idx_contact_attribute_values_organization_id (organization_id),
+    -- conversation filters look values up by attribute
+    INDEX idx_contact_attribute_values_definition_value (definition_id, value(191)),
+    CONSTRAINT fk_contact_attribute_values_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_contact_attribute_values_contact_id FOREIGN KEY (contact_id) REFERENCES contacts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_contact_attribute_values_definition_id FOREIGN KEY (definition_id) REFERENCES contact_attribute_definitions(id) ON DELETE CASCADE
+);
+
+CREATE TABLE contact_notes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    contact_id BIGINT UNSIGNED NOT NULL,
+    author_id BIGINT UNSIGNED NOT NULL,
+    body TEXT NOT NULL,
+    INDEX idx_contact_notes_deleted_at (deleted_at),
+    INDEX idx_contact_notes_organization_id (organization_id),
+    INDEX idx_contact_notes_contact_id (contact_id),
+    CONSTRAINT fk_contact_notes_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_contact_notes_contact_id FOREIGN KEY (contact_id) REFERENCES contacts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_contact_notes_author_id FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- merges also move notes and attribute values, older merges moved none
+ALTER TABLE contact_merges
+    ADD COLUMN note_ids JSON NULL,
+    ADD COLUMN attribute_value_ids JSON NULL;
+
+UPDATE contact_merges SET note_ids = JSON_ARRAY(), attribute_value_ids = JSON_ARRAY();
+
+ALTER TABLE contact_merges
+    MODIFY note_ids JSON NOT NULL,
+    MODIFY attribute_value_ids JSON NOT NULL;
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+ALTER TABLE contact_merges
+    DROP COLUMN attribute_value_ids,
+    DROP COLUMN note_ids;
+
+DROP TABLE IF EXISTS contact_notes;
+DROP TABLE IF EXISTS contact_attribute_values;
+DROP TABLE IF EXISTS contact_attribute_definitions;
@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+CREATE TABLE status_changes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    subject_type VARCHAR(20) NOT NULL,
+    subject_id BIGINT UNSIGNED NOT NULL,
+    conversation_id BIGINT UNSIGNED NOT NULL,
+    from_status VARCHAR(50) NOT NULL,
+    to_status VARCHAR(50) NOT NULL,
+    changed_by_id BIGINT UNSIGNED NULL,
+    INDEX idx_status_changes_created_at (created_at),
+    INDEX idx_status_changes_organization_id (organization_id),
+    INDEX idx_status_changes_subject (subject_type, subject_id),
+    INDEX idx_status_changes_conversation_id (conversation_id),
+    CONSTRAINT fk_status_changes_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_status_changes_conversation_id FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_status_changes_changed_by_id FOREIGN KEY (changed_by_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE TABLE conversation_ratings (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    conversation_id BIGINT UNSIGNED NOT NULL,
+    score INT NOT NULL,
+    comment TEXT NULL,
+    UNIQUE INDEX idx_conversation_ratings_conversation_id (conversation_id),
+    INDEX idx_conversation_ratings_organization_id (organization_id),
+    CONSTRAINT fk_conversation_ratings_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_conversation_ratings_conversation_id FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE IF EXISTS conversation_ratings;
+DROP TABLE IF EXISTS status_changes;
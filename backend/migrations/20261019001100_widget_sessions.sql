@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+ALTER TABLE organizations
+    ADD COLUMN slug VARCHAR(64) NULL,
+    ADD COLUMN widget_allowed_origins JSON NULL;
+
+-- existing organizations get a slug from their name, the later duplicates
+-- are told apart by their id
+UPDATE organizations
+    SET slug = TRIM(BOTH '-' FROM LEFT(REGEXP_REPLACE(LOWER(name), '[^a-z0-9]+', '-'), 56));
+
+UPDATE organizations
+    SET slug = CONCAT('organization-', id)
+    WHERE slug = '';
+
+UPDATE organizations
+    JOIN (
+        SELECT slug, MIN(id) AS first_id
+        FROM organizations
+        GROUP BY slug
+        HAVING COUNT(*) > 1
+    ) AS duplicates ON duplicates.slug = organizations.slug
+    SET organizations.slug = CONCAT(organizations.slug, '-', organizations.id)
+    WHERE organizations.id <> duplicates.first_id;
+
+ALTER TABLE organizations
+    ADD UNIQUE INDEX idx_organizations_slug (slug);
+
+CREATE TABLE widget_sessions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    contact_id BIGINT UNSIGNED NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    UNIQUE INDEX idx_widget_sessions_token_hash (token_hash),
+    INDEX idx_widget_sessions_organization_id (organization_id),
+    INDEX idx_widget_sessions_contact_id (contact_id),
+    CONSTRAINT fk_widget_sessions_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_widget_sessions_contact_id FOREIGN KEY (contact_id) REFERENCES contacts(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE IF EXISTS widget_sessions;
+
+ALTER TABLE organizations
+    DROP INDEX idx_organizations_slug,
+    DROP COLUMN widget_allowed_origins,
+    DROP COLUMN slug;
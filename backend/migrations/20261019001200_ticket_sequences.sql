@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+CREATE TABLE ticket_sequences (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    prefix VARCHAR(20) NOT NULL DEFAULT 'TKT',
+    date_part VARCHAR(10) NOT NULL DEFAULT 'day',
+    padding INT NOT NULL DEFAULT 4,
+    period VARCHAR(8) NOT NULL DEFAULT '',
+    last_value INT UNSIGNED NOT NULL DEFAULT 0,
+    UNIQUE INDEX idx_ticket_sequences_organization_id (organization_id),
+    CONSTRAINT fk_ticket_sequences_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
+
+-- numbers no longer carry the organization id, they are unique per
+-- organization instead
+ALTER TABLE tickets
+    DROP INDEX ticket_number,
+    ADD UNIQUE INDEX idx_tickets_organization_ticket_number (organization_id, ticket_number);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+-- the global unique index is not restored, organizations may share a
+-- number by now and adding it back would fail
+ALTER TABLE tickets
+    DROP INDEX idx_tickets_organization_ticket_number;
+
+DROP TABLE IF EXISTS ticket_sequences;
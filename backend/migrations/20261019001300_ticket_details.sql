@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+ALTER TABLE tickets
+    ADD COLUMN assignee_id BIGINT UNSIGNED NULL,
+    ADD COLUMN priority VARCHAR(20) NOT NULL DEFAULT 'normal',
+    ADD COLUMN due_at TIMESTAMP NULL,
+    ADD COLUMN category VARCHAR(100) NULL,
+    ADD COLUMN description TEXT NULL,
+    ADD INDEX idx_tickets_assignee_id (assignee_id),
+    ADD INDEX idx_tickets_priority (priority),
+    ADD INDEX idx_tickets_due_at (due_at),
+    ADD INDEX idx_tickets_category (category),
+    ADD CONSTRAINT fk_tickets_assignee_id FOREIGN KEY (assignee_id) REFERENCES users(id) ON DELETE SET NULL;
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+ALTER TABLE tickets
+    DROP FOREIGN KEY fk_tickets_assignee_id,
+    DROP INDEX idx_tickets_assignee_id,
+    DROP INDEX idx_tickets_priority,
+    DROP INDEX idx_tickets_due_at,
+    DROP INDEX idx_tickets_category,
+    DROP COLUMN assignee_id,
+    DROP COLUMN priority,
+    DROP COLUMN due_at,
+    DROP COLUMN category,
+    DROP COLUMN description;
@@ -0,0 +1,54 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+CREATE TABLE ticket_comments (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    ticket_id BIGINT UNSIGNED NOT NULL,
+    author_id BIGINT UNSIGNED NOT NULL,
+    visibility VARCHAR(20) NOT NULL DEFAULT 'internal',
+    body TEXT NOT NULL,
+    message_id BIGINT UNSIGNED NULL,
+    INDEX idx_ticket_comments_created_at (created_at),
+    INDEX idx_ticket_comments_deleted_at (deleted_at),
+    INDEX idx_ticket_comments_organization_id (organization_id),
+    INDEX idx_ticket_comments_ticket_id (ticket_id),
+    CONSTRAINT fk_ticket_comments_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ticket_comments_ticket_id FOREIGN KEY (ticket_id) REFERENCES tickets(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ticket_comments_author_id FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ticket_comments_message_id FOREIGN KEY (message_id) REFERENCES conversation_messages(id) ON DELETE SET NULL
+);
+
+-- rows are only ever inserted, the service has no way to change them
+CREATE TABLE ticket_activities (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    ticket_id BIGINT UNSIGNED NOT NULL,
+    actor_id BIGINT UNSIGNED NULL,
+    action VARCHAR(20) NOT NULL,
+    field VARCHAR(50) NULL,
+    old_value TEXT NULL,
+    new_value TEXT NULL,
+    comment_id BIGINT UNSIGNED NULL,
+    INDEX idx_ticket_activities_created_at (created_at),
+    INDEX idx_ticket_activities_organization_id (organization_id),
+    INDEX idx_ticket_activities_ticket_id (ticket_id),
+    CONSTRAINT fk_ticket_activities_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ticket_activities_ticket_id FOREIGN KEY (ticket_id) REFERENCES tickets(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ticket_activities_actor_id FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_ticket_activities_comment_id FOREIGN KEY (comment_id) REFERENCES ticket_comments(id) ON DELETE SET NULL
+);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE IF EXISTS ticket_activities;
+DROP TABLE IF EXISTS ticket_comments;
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+CREATE TABLE ticket_workflows (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    initial_status VARCHAR(50) NOT NULL,
+    statuses JSON NOT NULL,
+    transitions JSON NOT NULL,
+    UNIQUE INDEX idx_ticket_workflows_organization_id (organization_id),
+    CONSTRAINT fk_ticket_workflows_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
+
+-- existing organizations keep pending, in_progress and done with every
+-- move allowed, as before
+INSERT INTO ticket_workflows (organization_id, initial_status, statuses, transitions)
+SELECT id, 'pending',
+    '[{"key":"pending","name":"Pending","category":"open","requires_resolution_note":false},{"key":"in_progress","name":"In Progress","category":"open","requires_resolution_note":false},{"key":"done","name":"Done","category":"closed","requires_resolution_note":false}]',
+    '[{"from":"pending","to":"in_progress"},{"from":"pending","to":"done"},{"from":"in_progress","to":"pending"},{"from":"in_progress","to":"done"},{"from":"done","to":"pending"},{"from":"done","to":"in_progress"}]'
+FROM organizations;
+
+ALTER TABLE tickets
+    ADD COLUMN status_category VARCHAR(20) NOT NULL DEFAULT 'open',
+    ADD COLUMN resolution_note TEXT NULL,
+    ADD INDEX idx_tickets_status_category (status_category);
+
+UPDATE tickets SET status_category = 'closed' WHERE status = 'done';
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+ALTER TABLE tickets
+    DROP INDEX idx_tickets_status_category,
+    DROP COLUMN status_category,
+    DROP COLUMN resolution_note;
+
+DROP TABLE IF EXISTS ticket_workflows;
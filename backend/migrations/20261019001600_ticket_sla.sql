@@ -0,0 +1,70 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+CREATE TABLE ticket_sla_policies (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    priority VARCHAR(20) NULL,
+    category VARCHAR(100) NULL,
+    resolve_minutes INT NOT NULL,
+    warn_minutes INT NOT NULL DEFAULT 0,
+    warning_actions JSON NOT NULL,
+    breach_actions JSON NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    INDEX idx_ticket_sla_policies_deleted_at (deleted_at),
+    INDEX idx_ticket_sla_policies_organization_id (organization_id),
+    CONSTRAINT fk_ticket_sla_policies_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
+
+CREATE TABLE ticket_escalations (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    ticket_id BIGINT UNSIGNED NOT NULL,
+    policy_id BIGINT UNSIGNED NULL,
+    stage VARCHAR(20) NOT NULL,
+    sla_due_at TIMESTAMP NOT NULL,
+    results JSON NOT NULL,
+    INDEX idx_ticket_escalations_created_at (created_at),
+    INDEX idx_ticket_escalations_organization_id (organization_id),
+    INDEX idx_ticket_escalations_ticket_id (ticket_id),
+    CONSTRAINT fk_ticket_escalations_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ticket_escalations_ticket_id FOREIGN KEY (ticket_id) REFERENCES tickets(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ticket_escalations_policy_id FOREIGN KEY (policy_id) REFERENCES ticket_sla_policies(id) ON DELETE SET NULL
+);
+
+ALTER TABLE tickets
+    ADD COLUMN sla_policy_id BIGINT UNSIGNED NULL,
+    ADD COLUMN sla_warn_at TIMESTAMP NULL,
+    ADD COLUMN sla_due_at TIMESTAMP NULL,
+    ADD COLUMN sla_warned_at TIMESTAMP NULL,
+    ADD COLUMN sla_breached_at TIMESTAMP NULL,
+    ADD INDEX idx_tickets_sla_policy_id (sla_policy_id),
+    ADD INDEX idx_tickets_sla_warn_at (sla_warn_at),
+    ADD INDEX idx_tickets_sla_due_at (sla_due_at),
+    ADD CONSTRAINT fk_tickets_sla_policy_id FOREIGN KEY (sla_policy_id) REFERENCES ticket_sla_policies(id) ON DELETE SET NULL;
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+ALTER TABLE tickets
+    DROP FOREIGN KEY fk_tickets_sla_policy_id,
+    DROP INDEX idx_tickets_sla_policy_id,
+    DROP INDEX idx_tickets_sla_warn_at,
+    DROP INDEX idx_tickets_sla_due_at,
+    DROP COLUMN sla_policy_id,
+    DROP COLUMN sla_warn_at,
+    DROP COLUMN sla_due_at,
+    DROP COLUMN sla_warned_at,
+    DROP COLUMN sla_breached_at;
+
+DROP TABLE IF EXISTS ticket_escalations;
+DROP TABLE IF EXISTS ticket_sla_policies;
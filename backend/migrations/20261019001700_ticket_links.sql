@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- parent and duplicate rows point from the parent and from the duplicate
+CREATE TABLE ticket_links (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    ticket_id BIGINT UNSIGNED NOT NULL,
+    linked_ticket_id BIGINT UNSIGNED NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    created_by_id BIGINT UNSIGNED NULL,
+    UNIQUE INDEX idx_ticket_links_pair (ticket_id, linked_ticket_id),
+    INDEX idx_ticket_links_organization_id (organization_id),
+    INDEX idx_ticket_links_linked_ticket_id (linked_ticket_id),
+    CONSTRAINT fk_ticket_links_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ticket_links_ticket_id FOREIGN KEY (ticket_id) REFERENCES tickets(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ticket_links_linked_ticket_id FOREIGN KEY (linked_ticket_id) REFERENCES tickets(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ticket_links_created_by_id FOREIGN KEY (created_by_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE TABLE ticket_conversations (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    ticket_id BIGINT UNSIGNED NOT NULL,
+    conversation_id BIGINT UNSIGNED NOT NULL,
+    UNIQUE INDEX idx_ticket_conversations_pair (ticket_id, conversation_id),
+    INDEX idx_ticket_conversations_organization_id (organization_id),
+    INDEX idx_ticket_conversations_conversation_id (conversation_id),
+    CONSTRAINT fk_ticket_conversations_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ticket_conversations_ticket_id FOREIGN KEY (ticket_id) REFERENCES tickets(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ticket_conversations_conversation_id FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE
+);
+
+INSERT INTO ticket_conversations (created_at, organization_id, ticket_id, conversation_id)
+SELECT created_at, organization_id, id, conversation_id
+FROM tickets;
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE IF EXISTS ticket_conversations;
+DROP TABLE IF EXISTS ticket_links;
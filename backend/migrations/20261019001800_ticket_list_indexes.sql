@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- the ticket list pages through one organization at a time
+CREATE INDEX idx_tickets_organization_created_at ON tickets (organization_id, created_at);
+CREATE INDEX idx_tickets_organization_status ON tickets (organization_id, status);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP INDEX idx_tickets_organization_created_at ON tickets;
+DROP INDEX idx_tickets_organization_status ON tickets;
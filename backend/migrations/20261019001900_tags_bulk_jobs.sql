@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- a tag points at a ticket or a conversation by subject_type and subject_id
+CREATE TABLE tags (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    subject_type VARCHAR(20) NOT NULL,
+    subject_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    UNIQUE INDEX idx_tags_subject_name (subject_type, subject_id, name),
+    INDEX idx_tags_organization_name (organization_id, name),
+    CONSTRAINT fk_tags_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
+
+CREATE TABLE bulk_jobs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    created_by_id BIGINT UNSIGNED NOT NULL,
+    subject_type VARCHAR(20) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    payload JSON NOT NULL,
+    item_ids JSON NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    total INT NOT NULL,
+    processed INT NOT NULL DEFAULT 0,
+    succeeded INT NOT NULL DEFAULT 0,
+    failed INT NOT NULL DEFAULT 0,
+    results JSON NOT NULL,
+    error TEXT NULL,
+    started_at TIMESTAMP NULL,
+    finished_at TIMESTAMP NULL,
+    INDEX idx_bulk_jobs_organization_id (organization_id),
+    INDEX idx_bulk_jobs_status (status),
+    CONSTRAINT fk_bulk_jobs_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_bulk_jobs_created_by_id FOREIGN KEY (created_by_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE IF EXISTS bulk_jobs;
+DROP TABLE IF EXISTS tags;
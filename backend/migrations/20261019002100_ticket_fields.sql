@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+CREATE TABLE ticket_field_definitions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    `key` VARCHAR(64) NOT NULL,
+    label VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    options JSON NULL,
+    min DOUBLE NULL,
+    max DOUBLE NULL,
+    pattern VARCHAR(255) NULL,
+    UNIQUE INDEX idx_ticket_field_definitions_key (organization_id, `key`),
+    CONSTRAINT fk_ticket_field_definitions_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
+
+CREATE TABLE ticket_field_values (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    ticket_id BIGINT UNSIGNED NOT NULL,
+    definition_id BIGINT UNSIGNED NOT NULL,
+    value VARCHAR(1000) NOT NULL,
+    UNIQUE INDEX idx_ticket_field_values_ticket (ticket_id, definition_id),
+    INDEX idx_ticket_field_values_organization_id (organization_id),
+    -- ticket list filters look values up by field
+    INDEX idx_ticket_field_values_definition_value (definition_id, value(191)),
+    CONSTRAINT fk_ticket_field_values_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ticket_field_values_ticket_id FOREIGN KEY (ticket_id) REFERENCES tickets(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ticket_field_values_definition_id FOREIGN KEY (definition_id) REFERENCES ticket_field_definitions(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE IF EXISTS ticket_field_values;
+DROP TABLE IF EXISTS ticket_field_definitions;
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- a running timer has no ended_at yet
+CREATE TABLE ticket_time_entries (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    organization_id BIGINT UNSIGNED NOT NULL,
+    ticket_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP NULL,
+    duration_seconds INT NOT NULL DEFAULT 0,
+    note VARCHAR(1000) NULL,
+    billable BOOLEAN NOT NULL DEFAULT FALSE,
+    INDEX idx_ticket_time_entries_ticket_id (ticket_id),
+    INDEX idx_ticket_time_entries_user_ended (user_id, ended_at),
+    -- time reports sum the entries of a date range
+    INDEX idx_ticket_time_entries_organization_started (organization_id, started_at),
+    CONSTRAINT fk_ticket_time_entries_organization_id FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ticket_time_entries_ticket_id FOREIGN KEY (ticket_id) REFERENCES tickets(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ticket_time_entries_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+DROP TABLE IF EXISTS ticket_time_entries;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- the webhook integration signs its requests with the secret and staff
+-- replies only go to the listed https hosts
+ALTER TABLE organizations
+    ADD COLUMN webhook_secret VARCHAR(128) NULL,
+    ADD COLUMN webhook_callback_hosts JSON NULL;
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+ALTER TABLE organizations
+    DROP COLUMN webhook_secret,
+    DROP COLUMN webhook_callback_hosts;
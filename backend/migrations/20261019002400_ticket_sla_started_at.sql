@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- a reopened ticket counts its SLA deadlines from the reopen
+ALTER TABLE tickets ADD COLUMN sla_started_at TIMESTAMP NULL;
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+ALTER TABLE tickets DROP COLUMN sla_started_at;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- signed into the public status links so they can be revoked per ticket
+ALTER TABLE tickets ADD COLUMN status_link_version INT UNSIGNED NOT NULL DEFAULT 0;
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+ALTER TABLE tickets DROP COLUMN status_link_version;
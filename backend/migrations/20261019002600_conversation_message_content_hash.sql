@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- recognizes a retried delivery of a mail that has no Message-ID
+ALTER TABLE conversation_messages
+    ADD COLUMN content_hash CHAR(64) NULL,
+    ADD INDEX idx_conversation_messages_content_hash (organization_id, content_hash);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+ALTER TABLE conversation_messages
+    DROP INDEX idx_conversation_messages_content_hash,
+    DROP COLUMN content_hash;
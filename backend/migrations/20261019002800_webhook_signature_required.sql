@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- unsigned webhook requests keep working until the organization opts in:
+-- rotate a secret, sign every request with it, then turn this on
+ALTER TABLE organizations
+    ADD COLUMN webhook_signature_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+ALTER TABLE organizations
+    DROP COLUMN webhook_signature_required;
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+
+-- running jobs are taken over once their heartbeat stops, the jobs running
+-- now start from their last progress
+ALTER TABLE bulk_jobs
+    ADD COLUMN heartbeat_at TIMESTAMP NULL,
+    ADD INDEX idx_bulk_jobs_heartbeat_at (heartbeat_at);
+
+UPDATE bulk_jobs SET heartbeat_at = updated_at, updated_at = updated_at WHERE status = 'running';
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+
+ALTER TABLE bulk_jobs
+    DROP INDEX idx_bulk_jobs_heartbeat_at,
+    DROP COLUMN heartbeat_at;
@@ -0,0 +1,53 @@
+package eventdto
+
+import "time"
+
+// Envelope is the versioned body every subscription endpoint receives.
+type Envelope struct {
+	ID             string    `json:"id"`
+	Type           string    `json:"type"`
+	Version        string    `json:"version"`
+	OrganizationID uint      `json:"organizationId"`
+	OccurredAt     time.Time `json:"occurredAt"`
+	Data           any       `json:"data"`
+}
+
+type ConversationEvent struct {
+	ID                  uint    `json:"id"`
+	ContactID           uint    `json:"contactId"`
+	GuestID             *uint   `json:"guestId,omitempty"`
+	OrganizationStaffID *uint   `json:"organizationStaffId,omitempty"`
+	Channel             string  `json:"channel"`
+	Status              string  `json:"status"`
+	PreviousStatus      *string `json:"previousStatus,omitempty"`
+}
+
+type MessageEvent struct {
+	ID             uint      `json:"id"`
+	ConversationID uint      `json:"conversationId"`
+	CreatedByID    *uint     `json:"createdById,omitempty"`
+	ContactID      *uint     `json:"contactId,omitempty"`
+	Message        string    `json:"message"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+type TicketEvent struct {
+	ID             uint       `json:"id"`
+	ConversationID uint       `json:"conversationId"`
+	TicketNumber   string     `json:"ticketNumber"`
+	Name           string     `json:"name"`
+	Status         string     `json:"status"`
+	PreviousStatus *string    `json:"previousStatus,omitempty"`
+	AssigneeID     *uint      `json:"assigneeId,omitempty"`
+	Priority       string     `json:"priority"`
+	DueAt          *time.Time `json:"dueAt,omitempty"`
+	Category       *string    `json:"category,omitempty"`
+}
+
+// TicketSLAEvent is sent by the fire_event action of an SLA policy.
+type TicketSLAEvent struct {
+	Ticket   TicketEvent `json:"ticket"`
+	PolicyID uint        `json:"policyId"`
+	Stage    string      `json:"stage"`
+	SLADueAt time.Time   `json:"slaDueAt"`
+}
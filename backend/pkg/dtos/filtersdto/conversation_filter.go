@@ -0,0 +1,12 @@
+package filtersdto
+
+// ConversationFiltersDto narrows the conversation list. Owners open any
+// view and see every conversation by default, sales only open mine.
+type ConversationFiltersDto struct {
+	// View is the inbox to list: mine, unassigned or all
+	View string `json:"view" validate:"omitempty,oneof=mine unassigned all"`
+
+	// Attributes keeps the conversations whose contact attributes have
+	// the given values, keyed by attribute key
+	Attributes map[string]string `json:"attributes"`
+}
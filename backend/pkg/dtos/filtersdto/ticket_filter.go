@@ -0,0 +1,30 @@
+package filtersdto
+
+import "time"
+
+// TicketFiltersDto narrows the ticket list, empty fields do not filter.
+// Sales only ever see the tickets assigned to them.
+type TicketFiltersDto struct {
+	AssigneeID     *uint      `json:"assigneeId"`
+	CreatedByID    *uint      `json:"createdById"`
+	ConversationID *uint      `json:"conversationId"`
+	Status         []string   `json:"status" validate:"omitempty,max=20,dive,max=50"`
+	StatusCategory string     `json:"statusCategory" validate:"omitempty,oneof=open closed"`
+	Priority       string     `json:"priority" validate:"omitempty,oneof=low normal high urgent"`
+	Category       string     `json:"category"`
+	Tag            string     `json:"tag" validate:"omitempty,max=50"`
+	Overdue        bool       `json:"overdue"`
+	NumberPrefix   string     `json:"numberPrefix" validate:"omitempty,max=50"`
+	CreatedFrom    *time.Time `json:"createdFrom"`
+	CreatedTo      *time.Time `json:"createdTo"`
+	DueFrom        *time.Time `json:"dueFrom"`
+	DueTo          *time.Time `json:"dueTo"`
+
+	// Fields keeps the tickets whose custom fields have the given values,
+	// keyed by field key
+	Fields map[string]string `json:"fields"`
+
+	// Sort is a comma separated list of fields, a leading - sorts the
+	// field descending, e.g. -priority,createdAt
+	Sort string `json:"sort" validate:"omitempty,max=200"`
+}
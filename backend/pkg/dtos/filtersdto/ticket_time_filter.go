@@ -0,0 +1,11 @@
+package filtersdto
+
+// TicketTimeReportFiltersDto picks the time entries of a report by the day
+// they started, From and To are inclusive YYYY-MM-DD dates read in the
+// timezone of the organization.
+type TicketTimeReportFiltersDto struct {
+	From     string `json:"from" validate:"required,datetime=2006-01-02"`
+	To       string `json:"to" validate:"required,datetime=2006-01-02"`
+	UserID   *uint  `json:"userId"`
+	TicketID *uint  `json:"ticketId"`
+}
@@ -14,3 +14,11 @@ type LoginRequest struct {
 type RefreshTokenRequest struct {
 	Token string `json:"token" validate:"required"`
 }
+
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type VerifyMagicLinkRequest struct {
+	Token string `json:"token" validate:"required"`
+}
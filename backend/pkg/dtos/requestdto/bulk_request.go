@@ -0,0 +1,40 @@
+package requestdto
+
+import "DewaSRY/sociomile-app/pkg/dtos/filtersdto"
+
+// BulkTicketRequest applies an action to the tickets in IDs or to every
+// ticket matching Filter, exactly one of them is given. Status needs
+// Status, assign needs AssigneeID where 0 removes the assignee, the tag
+// actions need Tag and close moves open tickets to a closed status of the
+// workflow.
+type BulkTicketRequest struct {
+	IDs            []uint                       `json:"ids,omitempty" validate:"omitempty,max=10000"`
+	Filter         *filtersdto.TicketFiltersDto `json:"filter,omitempty"`
+	Action         string                       `json:"action" validate:"required,oneof=status assign add_tag remove_tag close"`
+	Status         string                       `json:"status,omitempty" validate:"omitempty,max=50"`
+	AssigneeID     *uint                        `json:"assigneeId,omitempty"`
+	Tag            string                       `json:"tag,omitempty" validate:"omitempty,max=50"`
+	ResolutionNote *string                      `json:"resolutionNote,omitempty" validate:"omitempty,max=10000"`
+}
+
+// BulkConversationRequest applies an action to the conversations in IDs
+// or to every conversation matching Filter, exactly one of them is given.
+// Close moves the conversations to done.
+type BulkConversationRequest struct {
+	IDs                 []uint                  `json:"ids,omitempty" validate:"omitempty,max=10000"`
+	Filter              *BulkConversationFilter `json:"filter,omitempty"`
+	Action              string                  `json:"action" validate:"required,oneof=status assign add_tag remove_tag close"`
+	Status              string                  `json:"status,omitempty" validate:"omitempty,oneof=pending in_progress done"`
+	OrganizationStaffID *uint                   `json:"organizationStaffId,omitempty"`
+	Tag                 string                  `json:"tag,omitempty" validate:"omitempty,max=50"`
+}
+
+// BulkConversationFilter selects conversations, empty fields do not
+// filter.
+type BulkConversationFilter struct {
+	Status              string `json:"status,omitempty" validate:"omitempty,oneof=pending in_progress done"`
+	Channel             string `json:"channel,omitempty" validate:"omitempty,oneof=web webhook email widget"`
+	OrganizationStaffID *uint  `json:"organizationStaffId,omitempty"`
+	Unassigned          bool   `json:"unassigned,omitempty"`
+	Tag                 string `json:"tag,omitempty" validate:"omitempty,max=50"`
+}
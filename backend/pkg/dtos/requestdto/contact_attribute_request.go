@@ -0,0 +1,28 @@
+package requestdto
+
+// CreateContactAttributeRequest key is lowercase letters, digits and
+// underscores, it is what webhook payloads and filters refer to. Options are
+// required for an enum and ignored for the other types.
+type CreateContactAttributeRequest struct {
+	Key     string   `json:"key" validate:"required,max=64"`
+	Label   string   `json:"label" validate:"required,max=255"`
+	Type    string   `json:"type" validate:"required,oneof=string number date enum boolean"`
+	Options []string `json:"options" validate:"omitempty,dive,required,max=255"`
+}
+
+// UpdateContactAttributeRequest cannot change the key or the type, values
+// already stored on contacts depend on them.
+type UpdateContactAttributeRequest struct {
+	Label   string   `json:"label" validate:"omitempty,max=255"`
+	Options []string `json:"options" validate:"omitempty,dive,required,max=255"`
+}
+
+// SetContactAttributesRequest values are keyed by attribute key, a null
+// value removes the attribute from the contact.
+type SetContactAttributesRequest struct {
+	Attributes map[string]any `json:"attributes" validate:"required"`
+}
+
+type ContactNoteRequest struct {
+	Body string `json:"body" validate:"required,max=10000"`
+}
@@ -0,0 +1,31 @@
+package requestdto
+
+type CreateContactRequest struct {
+	Name       string                   `json:"name" validate:"required,max=255"`
+	AvatarURL  *string                  `json:"avatarUrl" validate:"omitempty,url,max=2048"`
+	Identities []ContactIdentityRequest `json:"identities" validate:"omitempty,dive"`
+}
+
+type UpdateContactRequest struct {
+	Name      string  `json:"name" validate:"omitempty,max=255"`
+	AvatarURL *string `json:"avatarUrl" validate:"omitempty,url,max=2048"`
+}
+
+// ContactIdentityRequest type is email, phone or the name of an external
+// channel such as whatsapp.
+type ContactIdentityRequest struct {
+	Type  string `json:"type" validate:"required,alphanum,max=50"`
+	Value string `json:"value" validate:"required,max=255"`
+}
+
+// LinkContactUserRequest links a contact to the registered guest user with
+// this email.
+type LinkContactUserRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// MergeContactRequest merges the source contact into the contact of the path,
+// the source is removed and can be restored by undoing the merge.
+type MergeContactRequest struct {
+	SourceContactID uint `json:"sourceContactId" validate:"required"`
+}
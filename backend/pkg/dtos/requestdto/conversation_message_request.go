@@ -4,3 +4,7 @@ type CreateConversationMessageRequest struct {
 	ConversationID uint   `json:"conversationId" validate:"required"`
 	Message        string `json:"message" validate:"required,min=1,max=5000"`
 }
+
+type CreateStaffMessageRequest struct {
+	Message string `json:"message" validate:"required,min=1,max=5000"`
+}
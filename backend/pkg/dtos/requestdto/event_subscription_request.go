@@ -0,0 +1,12 @@
+package requestdto
+
+type CreateEventSubscriptionRequest struct {
+	URL        string   `json:"url" validate:"required,url,max=2048"`
+	EventTypes []string `json:"eventTypes" validate:"required,min=1,dive,oneof=conversation.created conversation.assigned conversation.status_changed message.created ticket.created ticket.updated ticket.sla_warning ticket.sla_breached"`
+}
+
+type UpdateEventSubscriptionRequest struct {
+	URL        string   `json:"url" validate:"omitempty,url,max=2048"`
+	EventTypes []string `json:"eventTypes" validate:"omitempty,min=1,dive,oneof=conversation.created conversation.assigned conversation.status_changed message.created ticket.created ticket.updated ticket.sla_warning ticket.sla_breached"`
+	Active     *bool    `json:"active"`
+}
@@ -5,6 +5,8 @@ type RegisterOrganizationRequest struct {
 	Email     string `json:"email" validate:"required,email"`
 	OwnerName string `json:"ownerName" validate:"required,min=3,max=100"`
 	Password  string `json:"password" validate:"required,min=6"`
+	// InboundEmail is optional, set it to receive customer mail through the email channel
+	InboundEmail string `json:"inboundEmail,omitempty" validate:"omitempty,email"`
 }
 
 type PutOrganizationRequest struct {
@@ -8,3 +8,9 @@ type CreateOrganizationRequest struct {
 type UpdateOrganizationRequest struct {
 	Name string `json:"name" validate:"required,min=3,max=100"`
 }
+
+// UpdateOrganizationSettingsRequest changes the settings of the caller's
+// organization, Timezone is an IANA zone name such as Asia/Jakarta.
+type UpdateOrganizationSettingsRequest struct {
+	Timezone string `json:"timezone" validate:"required,max=64"`
+}
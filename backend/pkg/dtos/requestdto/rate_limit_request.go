@@ -0,0 +1,6 @@
+package requestdto
+
+type UpdateOrganizationRateLimitRequest struct {
+	OrganizationPerMinute *int `json:"organizationPerMinute" validate:"omitempty,min=1"`
+	ContactPerMinute      *int `json:"contactPerMinute" validate:"omitempty,min=1"`
+}
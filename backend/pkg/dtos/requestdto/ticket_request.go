@@ -1,11 +1,172 @@
 package requestdto
 
+import "time"
+
 type CreateTicketRequest struct {
-	ConversationID uint   `json:"conversationId" validate:"required"`
-	Name           string `json:"name" validate:"required,min=3,max=200"`
+	ConversationID uint       `json:"conversationId" validate:"required"`
+	Name           string     `json:"name" validate:"required,min=3,max=200"`
+	AssigneeID     *uint      `json:"assigneeId,omitempty"`
+	Priority       string     `json:"priority,omitempty" validate:"omitempty,oneof=low normal high urgent"`
+	DueAt          *time.Time `json:"dueAt,omitempty"`
+	Category       *string    `json:"category,omitempty" validate:"omitempty,max=100"`
+	Description    *string    `json:"description,omitempty" validate:"omitempty,max=10000"`
+
+	// Fields are the custom fields of the ticket keyed by field key, the
+	// required ones must be given
+	Fields map[string]any `json:"fields,omitempty"`
 }
 
+// UpdateTicketRequest changes the given fields only. AssigneeID 0 removes
+// the assignee, ClearDueAt removes the due date. Status must be a status of
+// the organization's workflow reachable from the current one.
 type UpdateTicketRequest struct {
-	Name   string `json:"name,omitempty" validate:"omitempty,min=3,max=200"`
-	Status string `json:"status,omitempty" validate:"omitempty,oneof=pending in_progress done"`
+	Name        string     `json:"name,omitempty" validate:"omitempty,min=3,max=200"`
+	Status      string     `json:"status,omitempty" validate:"omitempty,max=50"`
+	AssigneeID  *uint      `json:"assigneeId,omitempty"`
+	Priority    string     `json:"priority,omitempty" validate:"omitempty,oneof=low normal high urgent"`
+	DueAt       *time.Time `json:"dueAt,omitempty"`
+	ClearDueAt  bool       `json:"clearDueAt,omitempty"`
+	Category    *string    `json:"category,omitempty" validate:"omitempty,max=100"`
+	Description *string    `json:"description,omitempty" validate:"omitempty,max=10000"`
+
+	// ResolutionNote is kept with the status change, the workflow can
+	// require it for some statuses
+	ResolutionNote *string `json:"resolutionNote,omitempty" validate:"omitempty,max=10000"`
+
+	// CascadeToChildren moves the child tickets to the new status as well.
+	// NotifyCustomers tells the customers of the ticket, its children and
+	// its duplicates when it closes, the resolution note is not sent.
+	CascadeToChildren bool `json:"cascadeToChildren,omitempty"`
+	NotifyCustomers   bool `json:"notifyCustomers,omitempty"`
+
+	// Fields sets the given custom fields, a null value clears a field
+	// that is not required
+	Fields map[string]any `json:"fields,omitempty"`
+}
+
+// UpdateTicketNumberFormatRequest sets how new ticket numbers look, e.g.
+// prefix TKT, date part day and padding 4 give TKT-20260214-0001.
+type UpdateTicketNumberFormatRequest struct {
+	Prefix   string `json:"prefix" validate:"required,max=20"`
+	DatePart string `json:"datePart" validate:"required,oneof=none year month day"`
+	Padding  int    `json:"padding" validate:"required,min=1,max=10"`
+}
+
+// CreateTicketCommentRequest adds a comment to a ticket, public comments
+// are sent to the customer as well.
+type CreateTicketCommentRequest struct {
+	Visibility string `json:"visibility" validate:"required,oneof=internal public"`
+	Body       string `json:"body" validate:"required,max=10000"`
+}
+
+// CreateTicketLinkRequest links the ticket to another ticket, Type is read
+// from the ticket, e.g. child_of makes the other ticket its parent.
+type CreateTicketLinkRequest struct {
+	TicketID uint   `json:"ticketId" validate:"required"`
+	Type     string `json:"type" validate:"required,oneof=parent_of child_of duplicate_of duplicated_by related_to"`
+}
+
+type AddTicketConversationRequest struct {
+	ConversationID uint `json:"conversationId" validate:"required"`
+}
+
+// UpdateTicketWorkflowRequest replaces the ticket workflow of the
+// organization, statuses still used by tickets can not be removed.
+type UpdateTicketWorkflowRequest struct {
+	InitialStatus string                            `json:"initialStatus" validate:"required,max=50"`
+	Statuses      []TicketWorkflowStatusRequest     `json:"statuses" validate:"required,min=1,max=30,dive"`
+	Transitions   []TicketWorkflowTransitionRequest `json:"transitions" validate:"max=500,dive"`
+}
+
+type TicketWorkflowStatusRequest struct {
+	Key                    string `json:"key" validate:"required,max=50"`
+	Name                   string `json:"name" validate:"required,max=100"`
+	Category               string `json:"category" validate:"required,oneof=open closed"`
+	RequiresResolutionNote bool   `json:"requiresResolutionNote"`
+}
+
+type TicketWorkflowTransitionRequest struct {
+	From string `json:"from" validate:"required,max=50"`
+	To   string `json:"to" validate:"required,max=50"`
+}
+
+// TicketSLAPolicyRequest creates or replaces an SLA policy. Leaving
+// Priority or Category out matches every ticket, WarnMinutes is how long
+// before the deadline the warning actions run.
+type TicketSLAPolicyRequest struct {
+	Name           string                          `json:"name" validate:"required,max=100"`
+	Priority       *string                         `json:"priority,omitempty" validate:"omitempty,oneof=low normal high urgent"`
+	Category       *string                         `json:"category,omitempty" validate:"omitempty,min=1,max=100"`
+	ResolveMinutes int                             `json:"resolveMinutes" validate:"required,min=1,max=525600"`
+	WarnMinutes    int                             `json:"warnMinutes" validate:"min=0,max=525600"`
+	WarningActions []TicketEscalationActionRequest `json:"warningActions" validate:"max=10,dive"`
+	BreachActions  []TicketEscalationActionRequest `json:"breachActions" validate:"max=10,dive"`
+	Active         *bool                           `json:"active,omitempty"`
+}
+
+// TicketEscalationActionRequest is an action of an SLA stage, reassign
+// needs the AssigneeID.
+type TicketEscalationActionRequest struct {
+	Type       string `json:"type" validate:"required,oneof=notify_owner bump_priority reassign fire_event"`
+	AssigneeID *uint  `json:"assigneeId,omitempty"`
+}
+
+// CreateTicketFieldRequest key is lowercase letters, digits and
+// underscores, it is what ticket requests and filters refer to. Options are
+// required for an enum and ignored for the other types. Min and Max bound a
+// number or the length of a string, Pattern is a regular expression a
+// string must match.
+type CreateTicketFieldRequest struct {
+	Key      string   `json:"key" validate:"required,max=64"`
+	Label    string   `json:"label" validate:"required,max=255"`
+	Type     string   `json:"type" validate:"required,oneof=string number date enum boolean"`
+	Required bool     `json:"required"`
+	Options  []string `json:"options" validate:"omitempty,dive,required,max=255"`
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"`
+	Pattern  *string  `json:"pattern,omitempty" validate:"omitempty,max=255"`
+}
+
+// UpdateTicketFieldRequest cannot change the key or the type, values
+// already stored on tickets depend on them. The rules replace the current
+// ones.
+type UpdateTicketFieldRequest struct {
+	Label    string   `json:"label" validate:"omitempty,max=255"`
+	Required bool     `json:"required"`
+	Options  []string `json:"options" validate:"omitempty,dive,required,max=255"`
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"`
+	Pattern  *string  `json:"pattern,omitempty" validate:"omitempty,max=255"`
+}
+
+// CreateTicketTimeEntryRequest logs time spent on a ticket by hand.
+// StartedAt defaults to DurationMinutes before now, the entry can not end
+// in the future.
+type CreateTicketTimeEntryRequest struct {
+	DurationMinutes int        `json:"durationMinutes" validate:"required,min=1,max=1440"`
+	StartedAt       *time.Time `json:"startedAt,omitempty"`
+	Note            string     `json:"note" validate:"max=1000"`
+	Billable        bool       `json:"billable"`
+}
+
+// StartTicketTimerRequest starts the caller's timer on a ticket, a staff
+// member runs one timer at a time.
+type StartTicketTimerRequest struct {
+	Note     string `json:"note" validate:"max=1000"`
+	Billable bool   `json:"billable"`
+}
+
+// StopTicketTimerRequest stops the caller's timer on a ticket, a given
+// Note or Billable replaces the one set when the timer started.
+type StopTicketTimerRequest struct {
+	Note     *string `json:"note,omitempty" validate:"omitempty,max=1000"`
+	Billable *bool   `json:"billable,omitempty"`
+}
+
+// CreateTicketStatusLinkRequest makes a status link of the ticket, Send
+// also posts it to the customer on the channel of the ticket's
+// conversation. RevokePrevious stops every link of the ticket made before.
+type CreateTicketStatusLinkRequest struct {
+	Send           bool `json:"send"`
+	RevokePrevious bool `json:"revokePrevious"`
 }
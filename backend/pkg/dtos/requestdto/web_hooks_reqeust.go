@@ -6,4 +6,32 @@ type WebHooksRequest struct{
 	OrganizationID uint `json:"organizationId" validate:"required"`
 	Message        string `json:"message" validate:"required,min=1,max=5000"`
 	Email    string `json:"email" validate:"required,email"`
-}
\ No newline at end of file
+	// CallbackURL receives the staff replies of this conversation
+	CallbackURL string `json:"callbackUrl" validate:"omitempty,url"`
+
+	// optional contact details, stored on the contact the message is filed under
+	Name      string `json:"name" validate:"omitempty,max=255"`
+	Phone     string `json:"phone" validate:"omitempty,max=32"`
+	AvatarURL string `json:"avatarUrl" validate:"omitempty,url,max=2048"`
+	// Source and ExternalID identify the contact on the provider, e.g. whatsapp
+	Source     string `json:"source" validate:"omitempty,alphanum,max=50"`
+	ExternalID string `json:"externalId" validate:"required_with=Source,max=255"`
+	// Attributes are custom contact attributes by key, unknown keys and
+	// invalid values are skipped
+	Attributes map[string]any `json:"attributes"`
+}
+
+type DeliveryReceiptRequest struct {
+	DeliveryID uint   `json:"deliveryId" validate:"required"`
+	Token      string `json:"token" validate:"required"`
+	Status     string `json:"status" validate:"required,oneof=delivered failed"`
+	Error      string `json:"error" validate:"max=2000"`
+}
+
+// UpdateWebhookSettingsRequest lists the https hosts staff replies may be
+// sent to, *.example.com allows every subdomain. SignatureRequired is left
+// as it is when omitted.
+type UpdateWebhookSettingsRequest struct {
+	CallbackHosts     []string `json:"callbackHosts" validate:"max=20,dive,required,max=255"`
+	SignatureRequired *bool    `json:"signatureRequired"`
+}
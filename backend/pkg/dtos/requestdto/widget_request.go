@@ -0,0 +1,25 @@
+package requestdto
+
+type StartWidgetSessionRequest struct {
+	Name  *string `json:"name" validate:"omitempty,max=255"`
+	Email *string `json:"email" validate:"omitempty,email"`
+}
+
+type IdentifyWidgetVisitorRequest struct {
+	Email string  `json:"email" validate:"required,email"`
+	Name  *string `json:"name" validate:"omitempty,max=255"`
+}
+
+type CreateWidgetConversationRequest struct {
+	Subject *string `json:"subject" validate:"omitempty,max=255"`
+	Message string  `json:"message" validate:"required,max=5000"`
+}
+
+type WidgetMessageRequest struct {
+	Message string `json:"message" validate:"required,max=5000"`
+}
+
+type UpdateWidgetSettingsRequest struct {
+	Slug           string   `json:"slug" validate:"required,min=3,max=64"`
+	AllowedOrigins []string `json:"allowedOrigins" validate:"max=20,dive,required,url"`
+}
@@ -0,0 +1,27 @@
+package responsedto
+
+import "time"
+
+// BulkJobResponse is the progress of a bulk action, Results has one entry
+// per handled item in the order they were handled.
+type BulkJobResponse struct {
+	ID          uint                    `json:"id"`
+	SubjectType string                  `json:"subjectType"`
+	Action      string                  `json:"action"`
+	Status      string                  `json:"status"`
+	Total       int                     `json:"total"`
+	Processed   int                     `json:"processed"`
+	Succeeded   int                     `json:"succeeded"`
+	Failed      int                     `json:"failed"`
+	Results     []BulkJobResultResponse `json:"results"`
+	Error       *string                 `json:"error,omitempty"`
+	CreatedAt   time.Time               `json:"createdAt"`
+	StartedAt   *time.Time              `json:"startedAt,omitempty"`
+	FinishedAt  *time.Time              `json:"finishedAt,omitempty"`
+}
+
+type BulkJobResultResponse struct {
+	ID      uint    `json:"id"`
+	Success bool    `json:"success"`
+	Error   *string `json:"error,omitempty"`
+}
@@ -0,0 +1,28 @@
+package responsedto
+
+import "time"
+
+type ContactAttributeResponse struct {
+	ID        uint      `json:"id"`
+	Key       string    `json:"key"`
+	Label     string    `json:"label"`
+	Type      string    `json:"type"`
+	Options   []string  `json:"options,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type ContactNoteResponse struct {
+	ID        uint      `json:"id"`
+	ContactID uint      `json:"contactId"`
+	AuthorID  uint      `json:"authorId"`
+	Author    *UserData `json:"author,omitempty"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type ContactNotePaginateResponse struct {
+	Data     []ContactNoteResponse `json:"data"`
+	Metadata PaginateMetaData      `json:"metadata"`
+}
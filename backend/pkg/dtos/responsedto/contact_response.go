@@ -0,0 +1,67 @@
+package responsedto
+
+import "time"
+
+type ContactResponse struct {
+	ID             uint                      `json:"id"`
+	OrganizationID uint                      `json:"organizationId"`
+	Name           string                    `json:"name"`
+	AvatarURL      *string                   `json:"avatarUrl,omitempty"`
+	UserID         *uint                     `json:"userId,omitempty"`
+	User           *UserData                 `json:"user,omitempty"`
+	Identities     []ContactIdentityResponse `json:"identities"`
+	Attributes     map[string]any            `json:"attributes"`
+	CreatedAt      time.Time                 `json:"createdAt"`
+	UpdatedAt      time.Time                 `json:"updatedAt"`
+}
+
+type ContactIdentityResponse struct {
+	ID    uint   `json:"id"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// ContactData is the short form of a contact embedded in conversations and
+// messages.
+type ContactData struct {
+	ID         uint                      `json:"id"`
+	Name       string                    `json:"name"`
+	Email      *string                   `json:"email,omitempty"`
+	Phone      *string                   `json:"phone,omitempty"`
+	AvatarURL  *string                   `json:"avatarUrl,omitempty"`
+	Identities []ContactIdentityResponse `json:"identities,omitempty"`
+	Attributes map[string]any            `json:"attributes,omitempty"`
+}
+
+type ContactPaginateResponse struct {
+	Data     []ContactResponse `json:"data"`
+	Metadata PaginateMetaData  `json:"metadata"`
+}
+
+type ContactMergeResponse struct {
+	ID                uint       `json:"id"`
+	TargetContactID   uint       `json:"targetContactId"`
+	SourceContactID   uint       `json:"sourceContactId"`
+	MergedByID        uint       `json:"mergedById"`
+	IdentityIDs       []uint     `json:"identityIds"`
+	ConversationIDs   []uint     `json:"conversationIds"`
+	NoteIDs           []uint     `json:"noteIds"`
+	AttributeValueIDs []uint     `json:"attributeValueIds"`
+	UserMoved         bool       `json:"userMoved"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	UndoneAt          *time.Time `json:"undoneAt,omitempty"`
+	UndoneByID        *uint      `json:"undoneById,omitempty"`
+}
+
+// ContactMergeSuggestionResponse lists contacts sharing an email or a phone
+// number, Reason is email or phone.
+type ContactMergeSuggestionResponse struct {
+	MatchKey string            `json:"matchKey"`
+	Reason   string            `json:"reason"`
+	Contacts []ContactResponse `json:"contacts"`
+}
+
+type ContactMergeSuggestionPaginateResponse struct {
+	Data     []ContactMergeSuggestionResponse `json:"data"`
+	Metadata PaginateMetaData                 `json:"metadata"`
+}
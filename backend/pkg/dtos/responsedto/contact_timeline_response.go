@@ -0,0 +1,52 @@
+package responsedto
+
+import "time"
+
+// ContactTimelineEntry is one event in the history of a contact, Type tells
+// which of the detail fields is set.
+type ContactTimelineEntry struct {
+	Type           string                `json:"type"`
+	OccurredAt     time.Time             `json:"occurredAt"`
+	ConversationID uint                  `json:"conversationId"`
+	Conversation   *TimelineConversation `json:"conversation,omitempty"`
+	Message        *TimelineMessage      `json:"message,omitempty"`
+	Ticket         *TimelineTicket       `json:"ticket,omitempty"`
+	StatusChange   *TimelineStatusChange `json:"statusChange,omitempty"`
+}
+
+type TimelineConversation struct {
+	ID      uint    `json:"id"`
+	Channel string  `json:"channel"`
+	Status  string  `json:"status"`
+	Subject *string `json:"subject,omitempty"`
+}
+
+// TimelineMessage FromContact is true for messages the customer wrote.
+type TimelineMessage struct {
+	ID          uint      `json:"id"`
+	Message     string    `json:"message"`
+	FromContact bool      `json:"fromContact"`
+	CreatedBy   *UserData `json:"createdBy,omitempty"`
+}
+
+type TimelineTicket struct {
+	ID           uint   `json:"id"`
+	TicketNumber string `json:"ticketNumber"`
+	Name         string `json:"name"`
+	Status       string `json:"status"`
+}
+
+type TimelineStatusChange struct {
+	SubjectType string    `json:"subjectType"`
+	SubjectID   uint      `json:"subjectId"`
+	FromStatus  string    `json:"fromStatus"`
+	ToStatus    string    `json:"toStatus"`
+	ChangedBy   *UserData `json:"changedBy,omitempty"`
+}
+
+// ContactTimelineResponse is newest first, pass NextCursor as cursor to get
+// the following page, it is empty on the last page.
+type ContactTimelineResponse struct {
+	Data       []ContactTimelineEntry `json:"data"`
+	NextCursor string                 `json:"nextCursor,omitempty"`
+}
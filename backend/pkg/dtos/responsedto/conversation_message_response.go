@@ -3,15 +3,39 @@ package responsedto
 import "time"
 
 type ConversationMessageResponse struct {
-	ID             uint                  `json:"id"`
-	OrganizationID uint                  `json:"organizationId"`
-	ConversationID uint                  `json:"conversationId"`
-	Conversation   *ConversationResponse `json:"conversation,omitempty"`
-	CreatedByID    uint                  `json:"createdById"`
-	CreatedBy      *UserData             `json:"createdBy,omitempty"`
-	Message        string                `json:"message"`
-	CreatedAt      time.Time             `json:"createdAt"`
-	UpdatedAt      time.Time             `json:"updatedAt"`
+	ID             uint                     `json:"id"`
+	OrganizationID uint                     `json:"organizationId"`
+	ConversationID uint                     `json:"conversationId"`
+	Conversation   *ConversationResponse    `json:"conversation,omitempty"`
+	CreatedByID    *uint                    `json:"createdById,omitempty"`
+	CreatedBy      *UserData                `json:"createdBy,omitempty"`
+	ContactID      *uint                    `json:"contactId,omitempty"`
+	Contact        *ContactData             `json:"contact,omitempty"`
+	Message        string                   `json:"message"`
+	HTMLMessage    *string                  `json:"htmlMessage,omitempty"`
+	Attachments    []AttachmentResponse     `json:"attachments,omitempty"`
+	Delivery       *MessageDeliveryResponse `json:"delivery,omitempty"`
+	CreatedAt      time.Time                `json:"createdAt"`
+	UpdatedAt      time.Time                `json:"updatedAt"`
+}
+
+type AttachmentResponse struct {
+	ID          uint   `json:"id"`
+	FileName    string `json:"fileName"`
+	ContentType string `json:"contentType"`
+	Size        int    `json:"size"`
+}
+
+// MessageDeliveryResponse tells the agent whether a reply reached the
+// customer's channel.
+type MessageDeliveryResponse struct {
+	Channel       string     `json:"channel"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     *string    `json:"lastError,omitempty"`
+	NextAttemptAt time.Time  `json:"nextAttemptAt"`
+	SentAt        *time.Time `json:"sentAt,omitempty"`
+	DeliveredAt   *time.Time `json:"deliveredAt,omitempty"`
 }
 
 type ConversationMessageListResponse struct {
@@ -23,3 +47,9 @@ type ConversationMessagePaginateResponse struct {
 	Data     []ConversationMessageResponse `json:"data"`
 	Metadata PaginateMetaData              `json:"metadata"`
 }
+
+type AttachmentFileResponse struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
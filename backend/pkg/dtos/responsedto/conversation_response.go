@@ -6,12 +6,18 @@ type ConversationResponse struct {
 	ID                  uint                          `json:"id"`
 	OrganizationID      uint                          `json:"organizationId"`
 	Organization        *OrganizationResponse         `json:"organization,omitempty"`
-	GuestID             uint                          `json:"guestId"`
+	GuestID             *uint                         `json:"guestId,omitempty"`
 	Guest               *UserData                     `json:"guest,omitempty"`
+	ContactID           uint                          `json:"contactId"`
+	Contact             *ContactData                  `json:"contact,omitempty"`
 	OrganizationStaffID *uint                         `json:"organizationStaffId,omitempty"`
 	OrganizationStaff   *UserData                     `json:"organizationStaff,omitempty"`
 	Status              string                        `json:"status"`
+	Channel             string                        `json:"channel"`
+	Tags                []string                      `json:"tags,omitempty"`
+	Subject             *string                       `json:"subject,omitempty"`
 	Messages            []ConversationMessageResponse `json:"messages"`
+	ContactSummary      *ContactSummaryData           `json:"contactSummary,omitempty"`
 	CreatedAt           time.Time                     `json:"createdAt"`
 	UpdatedAt           time.Time                     `json:"updatedAt"`
 }
@@ -25,3 +31,12 @@ type ConversationListPaginateResponse struct {
 	Data     []ConversationResponse `json:"data"`
 	Metadata PaginateMetaData       `json:"metadata"`
 }
+
+// ContactSummaryData is the history of the conversation's contact at a
+// glance, LastContactAt is the last message the customer wrote.
+type ContactSummaryData struct {
+	TotalConversations int64      `json:"totalConversations"`
+	OpenConversations  int64      `json:"openConversations"`
+	OpenTickets        int64      `json:"openTickets"`
+	LastContactAt      *time.Time `json:"lastContactAt,omitempty"`
+}
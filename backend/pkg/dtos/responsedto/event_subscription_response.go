@@ -0,0 +1,43 @@
+package responsedto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type EventSubscriptionResponse struct {
+	ID             uint     `json:"id"`
+	OrganizationID uint     `json:"organizationId"`
+	URL            string   `json:"url"`
+	EventTypes     []string `json:"eventTypes"`
+	Active         bool     `json:"active"`
+	// Secret is only returned once, when the subscription is created
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type EventSubscriptionListResponse struct {
+	Data []EventSubscriptionResponse `json:"data"`
+}
+
+type EventDeliveryResponse struct {
+	ID             uint            `json:"id"`
+	SubscriptionID uint            `json:"subscriptionId"`
+	EventID        string          `json:"eventId"`
+	EventType      string          `json:"eventType"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt"`
+	ResponseCode   *int            `json:"responseCode,omitempty"`
+	LastError      *string         `json:"lastError,omitempty"`
+	DeliveredAt    *time.Time      `json:"deliveredAt,omitempty"`
+	ReplayOfID     *uint           `json:"replayOfId,omitempty"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	CreatedAt      time.Time       `json:"createdAt"`
+}
+
+type EventDeliveryPaginateResponse struct {
+	Data     []EventDeliveryResponse `json:"data"`
+	Metadata PaginateMetaData        `json:"metadata"`
+}
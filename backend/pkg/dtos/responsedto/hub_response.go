@@ -11,11 +11,12 @@ type OrganizationRecord struct {
 }
 
 type HubOrganizationRecord struct {
-	ID        uint      `json:"id"`
-	Name      string    `json:"name"`
-	OwnerName string    `json:"ownerName"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID           uint      `json:"id"`
+	Name         string    `json:"name"`
+	OwnerName    string    `json:"ownerName"`
+	InboundEmail *string   `json:"inboundEmail,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
 }
 
 type OrganizationPaginateResponse struct {
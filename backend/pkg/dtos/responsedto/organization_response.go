@@ -11,6 +11,10 @@ type OrganizationResponse struct {
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
+type OrganizationSettingsResponse struct {
+	Timezone string `json:"timezone"`
+}
+
 type OrganizationListResponse struct {
 	Organizations []OrganizationResponse `json:"organizations"`
 	Metadata      PaginateMetaData       `json:"metadata"`
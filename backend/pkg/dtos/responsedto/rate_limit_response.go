@@ -0,0 +1,25 @@
+package responsedto
+
+import "time"
+
+type OrganizationRateLimitResponse struct {
+	OrganizationID        uint `json:"organizationId"`
+	OrganizationPerMinute int  `json:"organizationPerMinute"`
+	ContactPerMinute      int  `json:"contactPerMinute"`
+	IPPerMinute           int  `json:"ipPerMinute"`
+	// Custom is false when the organization uses the configured defaults
+	Custom bool `json:"custom"`
+}
+
+type RateLimitHitResponse struct {
+	OrganizationID uint      `json:"organizationId"`
+	Scope          string    `json:"scope"`
+	Route          string    `json:"route"`
+	WindowStart    time.Time `json:"windowStart"`
+	Hits           int       `json:"hits"`
+}
+
+type RateLimitHitPaginateResponse struct {
+	Data     []RateLimitHitResponse `json:"data"`
+	Metadata PaginateMetaData       `json:"metadata"`
+}
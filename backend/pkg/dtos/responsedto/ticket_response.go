@@ -13,6 +13,20 @@ type TicketResponse struct {
 	TicketNumber   string                `json:"ticketNumber"`
 	Name           string                `json:"name"`
 	Status         string                `json:"status"`
+	StatusCategory string                `json:"statusCategory"`
+	ResolutionNote *string               `json:"resolutionNote,omitempty"`
+	AssigneeID     *uint                 `json:"assigneeId,omitempty"`
+	Assignee       *UserData             `json:"assignee,omitempty"`
+	Priority       string                `json:"priority"`
+	DueAt          *time.Time            `json:"dueAt,omitempty"`
+	Overdue        bool                  `json:"overdue"`
+	Category       *string               `json:"category,omitempty"`
+	Description    *string               `json:"description,omitempty"`
+	SLAPolicyID    *uint                 `json:"slaPolicyId,omitempty"`
+	SLADueAt       *time.Time            `json:"slaDueAt,omitempty"`
+	SLABreached    bool                  `json:"slaBreached"`
+	Tags           []string              `json:"tags"`
+	Fields         map[string]any        `json:"fields"`
 	CreatedAt      time.Time             `json:"createdAt"`
 	UpdatedAt      time.Time             `json:"updatedAt"`
 }
@@ -21,3 +35,254 @@ type TicketListResponse struct {
 	Tickets  []TicketResponse `json:"tickets"`
 	Metadata PaginateMetaData `json:"metadata"`
 }
+
+// TicketNumberFormatResponse is the numbering format of the organization,
+// NextNumber is the number the next ticket gets.
+type TicketNumberFormatResponse struct {
+	Prefix     string `json:"prefix"`
+	DatePart   string `json:"datePart"`
+	Padding    int    `json:"padding"`
+	NextNumber string `json:"nextNumber"`
+}
+
+type TicketCommentResponse struct {
+	ID         uint      `json:"id"`
+	TicketID   uint      `json:"ticketId"`
+	AuthorID   uint      `json:"authorId"`
+	Author     *UserData `json:"author,omitempty"`
+	Visibility string    `json:"visibility"`
+	Body       string    `json:"body"`
+	MessageID  *uint     `json:"messageId,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type TicketCommentPaginateResponse struct {
+	Data     []TicketCommentResponse `json:"data"`
+	Metadata PaginateMetaData        `json:"metadata"`
+}
+
+// TicketActivityResponse is one entry of the ticket history, Field,
+// OldValue and NewValue are set on updates and CommentID on comments.
+type TicketActivityResponse struct {
+	ID        uint      `json:"id"`
+	ActorID   *uint     `json:"actorId,omitempty"`
+	Actor     *UserData `json:"actor,omitempty"`
+	Action    string    `json:"action"`
+	Field     *string   `json:"field,omitempty"`
+	OldValue  *string   `json:"oldValue,omitempty"`
+	NewValue  *string   `json:"newValue,omitempty"`
+	CommentID *uint     `json:"commentId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TicketDetailResponse is a ticket with its comments, history and SLA
+// escalations, all oldest first, its linked tickets and the conversations
+// it covers.
+type TicketDetailResponse struct {
+	Ticket        TicketResponse             `json:"ticket"`
+	Comments      []TicketCommentResponse    `json:"comments"`
+	Activity      []TicketActivityResponse   `json:"activity"`
+	Escalations   []TicketEscalationResponse `json:"escalations"`
+	Links         []TicketLinkResponse       `json:"links"`
+	Conversations []ConversationResponse     `json:"conversations"`
+}
+
+// TicketLinkResponse is a link as seen from the requested ticket, e.g.
+// child_of means Ticket is its parent.
+type TicketLinkResponse struct {
+	ID        uint                  `json:"id"`
+	Type      string                `json:"type"`
+	Ticket    TicketSummaryResponse `json:"ticket"`
+	CreatedAt time.Time             `json:"createdAt"`
+}
+
+type TicketSummaryResponse struct {
+	ID             uint   `json:"id"`
+	TicketNumber   string `json:"ticketNumber"`
+	Name           string `json:"name"`
+	Status         string `json:"status"`
+	StatusCategory string `json:"statusCategory"`
+}
+
+// TicketEscalationResponse is a ticket reaching the warning or the breach
+// of its SLA policy with the outcome of every action that ran.
+type TicketEscalationResponse struct {
+	ID        uint                             `json:"id"`
+	PolicyID  *uint                            `json:"policyId,omitempty"`
+	Stage     string                           `json:"stage"`
+	SLADueAt  time.Time                        `json:"slaDueAt"`
+	Results   []TicketEscalationResultResponse `json:"results"`
+	CreatedAt time.Time                        `json:"createdAt"`
+}
+
+type TicketEscalationResultResponse struct {
+	Type   string  `json:"type"`
+	Detail string  `json:"detail"`
+	Error  *string `json:"error,omitempty"`
+}
+
+type TicketSLAPolicyResponse struct {
+	ID             uint                             `json:"id"`
+	Name           string                           `json:"name"`
+	Priority       *string                          `json:"priority,omitempty"`
+	Category       *string                          `json:"category,omitempty"`
+	ResolveMinutes int                              `json:"resolveMinutes"`
+	WarnMinutes    int                              `json:"warnMinutes"`
+	WarningActions []TicketEscalationActionResponse `json:"warningActions"`
+	BreachActions  []TicketEscalationActionResponse `json:"breachActions"`
+	Active         bool                             `json:"active"`
+	CreatedAt      time.Time                        `json:"createdAt"`
+	UpdatedAt      time.Time                        `json:"updatedAt"`
+}
+
+type TicketEscalationActionResponse struct {
+	Type       string `json:"type"`
+	AssigneeID *uint  `json:"assigneeId,omitempty"`
+}
+
+type TicketSLAPolicyListResponse struct {
+	Policies []TicketSLAPolicyResponse `json:"policies"`
+}
+
+type TicketWorkflowResponse struct {
+	InitialStatus string                             `json:"initialStatus"`
+	Statuses      []TicketWorkflowStatusResponse     `json:"statuses"`
+	Transitions   []TicketWorkflowTransitionResponse `json:"transitions"`
+}
+
+type TicketWorkflowStatusResponse struct {
+	Key                    string `json:"key"`
+	Name                   string `json:"name"`
+	Category               string `json:"category"`
+	RequiresResolutionNote bool   `json:"requiresResolutionNote"`
+}
+
+type TicketWorkflowTransitionResponse struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// TicketImportResponse reports a ticket import, rows are numbered as in a
+// spreadsheet with the header on row 1. Nothing is created while a row has
+// errors.
+type TicketImportResponse struct {
+	DryRun  bool                     `json:"dryRun"`
+	Total   int                      `json:"total"`
+	Valid   int                      `json:"valid"`
+	Created int                      `json:"created"`
+	Skipped []TicketImportRowSkipped `json:"skipped"`
+	Errors  []TicketImportRowError   `json:"errors"`
+}
+
+// TicketImportRowSkipped is a row left out because its ticket_number is
+// already a ticket of the organization.
+type TicketImportRowSkipped struct {
+	Row          int    `json:"row"`
+	TicketNumber string `json:"ticketNumber"`
+	TicketID     uint   `json:"ticketId"`
+}
+
+type TicketImportRowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+type TicketFieldResponse struct {
+	ID        uint      `json:"id"`
+	Key       string    `json:"key"`
+	Label     string    `json:"label"`
+	Type      string    `json:"type"`
+	Required  bool      `json:"required"`
+	Options   []string  `json:"options,omitempty"`
+	Min       *float64  `json:"min,omitempty"`
+	Max       *float64  `json:"max,omitempty"`
+	Pattern   *string   `json:"pattern,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TicketTimeEntryResponse is logged time on a ticket, Running is true
+// while its timer has not been stopped.
+type TicketTimeEntryResponse struct {
+	ID              uint       `json:"id"`
+	TicketID        uint       `json:"ticketId"`
+	UserID          uint       `json:"userId"`
+	User            *UserData  `json:"user,omitempty"`
+	StartedAt       time.Time  `json:"startedAt"`
+	EndedAt         *time.Time `json:"endedAt,omitempty"`
+	DurationSeconds int        `json:"durationSeconds"`
+	Note            *string    `json:"note,omitempty"`
+	Billable        bool       `json:"billable"`
+	Running         bool       `json:"running"`
+	CreatedAt       time.Time  `json:"createdAt"`
+}
+
+// TicketTimeTotals sums stopped time entries, running timers are left out.
+type TicketTimeTotals struct {
+	Entries         int   `json:"entries"`
+	TotalSeconds    int64 `json:"totalSeconds"`
+	BillableSeconds int64 `json:"billableSeconds"`
+}
+
+type TicketTimeEntryListResponse struct {
+	Entries []TicketTimeEntryResponse `json:"entries"`
+	Totals  TicketTimeTotals          `json:"totals"`
+}
+
+// TicketTimeReportResponse sums the time entries that started between From
+// and To for the organization, per agent and per ticket.
+type TicketTimeReportResponse struct {
+	From     string                   `json:"from"`
+	To       string                   `json:"to"`
+	Timezone string                   `json:"timezone"`
+	Totals   TicketTimeTotals         `json:"totals"`
+	Agents   []TicketTimeAgentReport  `json:"agents"`
+	Tickets  []TicketTimeTicketReport `json:"tickets"`
+}
+
+type TicketTimeAgentReport struct {
+	User   UserData         `json:"user"`
+	Totals TicketTimeTotals `json:"totals"`
+}
+
+type TicketTimeTicketReport struct {
+	Ticket TicketSummaryResponse `json:"ticket"`
+	Totals TicketTimeTotals      `json:"totals"`
+}
+
+// GuestTicketResponse is what a customer sees of a ticket, Comments are
+// the public comments oldest first and are left out of lists.
+type GuestTicketResponse struct {
+	ID               uint                         `json:"id"`
+	TicketNumber     string                       `json:"ticketNumber"`
+	Name             string                       `json:"name"`
+	Status           string                       `json:"status"`
+	StatusName       string                       `json:"statusName"`
+	StatusCategory   string                       `json:"statusCategory"`
+	OrganizationName string                       `json:"organizationName"`
+	Comments         []GuestTicketCommentResponse `json:"comments,omitempty"`
+	CreatedAt        time.Time                    `json:"createdAt"`
+	UpdatedAt        time.Time                    `json:"updatedAt"`
+}
+
+type GuestTicketCommentResponse struct {
+	ID         uint      `json:"id"`
+	AuthorName string    `json:"authorName"`
+	Body       string    `json:"body"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type GuestTicketListResponse struct {
+	Tickets  []GuestTicketResponse `json:"tickets"`
+	Metadata PaginateMetaData      `json:"metadata"`
+}
+
+// TicketStatusLinkResponse is a signed link to the public status page of
+// a ticket, MessageID is the message that sent it to the customer.
+type TicketStatusLinkResponse struct {
+	URL       string    `json:"url"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	MessageID *uint     `json:"messageId,omitempty"`
+}
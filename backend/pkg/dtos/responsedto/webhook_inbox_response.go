@@ -0,0 +1,37 @@
+package responsedto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type WebhookAcceptedResponse struct {
+	EventID uint `json:"eventId"`
+}
+
+// WebhookSettingsResponse only carries the secret right after it is rotated
+type WebhookSettingsResponse struct {
+	OrganizationID    uint     `json:"organizationId"`
+	SecretConfigured  bool     `json:"secretConfigured"`
+	SignatureRequired bool     `json:"signatureRequired"`
+	Secret            string   `json:"secret,omitempty"`
+	CallbackHosts     []string `json:"callbackHosts"`
+}
+
+type WebhookInboxEventResponse struct {
+	ID             uint            `json:"id"`
+	OrganizationID uint            `json:"organizationId"`
+	ContactKey     string          `json:"contactKey"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt"`
+	LastError      *string         `json:"lastError,omitempty"`
+	ProcessedAt    *time.Time      `json:"processedAt,omitempty"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	CreatedAt      time.Time       `json:"createdAt"`
+}
+
+type WebhookInboxEventPaginateResponse struct {
+	Data     []WebhookInboxEventResponse `json:"data"`
+	Metadata PaginateMetaData            `json:"metadata"`
+}
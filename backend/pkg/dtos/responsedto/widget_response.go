@@ -0,0 +1,54 @@
+package responsedto
+
+import "time"
+
+type WidgetOrganizationData struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// WidgetSessionResponse carries the visitor token, it is sent back as a
+// bearer token on the widget routes of the same organization.
+type WidgetSessionResponse struct {
+	Token        string                 `json:"token"`
+	ExpiresAt    time.Time              `json:"expiresAt"`
+	ContactID    uint                   `json:"contactId"`
+	Name         string                 `json:"name"`
+	Identified   bool                   `json:"identified"`
+	Organization WidgetOrganizationData `json:"organization"`
+}
+
+type WidgetConversationResponse struct {
+	ID        uint      `json:"id"`
+	Status    string    `json:"status"`
+	Subject   *string   `json:"subject,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type WidgetConversationPaginateResponse struct {
+	Data     []WidgetConversationResponse `json:"data"`
+	Metadata PaginateMetaData             `json:"metadata"`
+}
+
+// WidgetMessageResponse leaves out staff emails, the widget is public.
+type WidgetMessageResponse struct {
+	ID             uint      `json:"id"`
+	ConversationID uint      `json:"conversationId"`
+	Message        string    `json:"message"`
+	FromVisitor    bool      `json:"fromVisitor"`
+	AuthorName     *string   `json:"authorName,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+type WidgetMessagePaginateResponse struct {
+	Data     []WidgetMessageResponse `json:"data"`
+	Metadata PaginateMetaData        `json:"metadata"`
+}
+
+type WidgetSettingsResponse struct {
+	OrganizationID uint     `json:"organizationId"`
+	Slug           string   `json:"slug"`
+	AllowedOrigins []string `json:"allowedOrigins"`
+}
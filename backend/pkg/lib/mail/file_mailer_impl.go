@@ -0,0 +1,49 @@
+package mail
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// fileMailerImpl writes every mail as an .eml file into a directory, it can
+// be opened with any mail client while developing.
+type fileMailerImpl struct {
+	dir  string
+	from string
+}
+
+// Send implements Mailer.
+func (t *fileMailerImpl) Send(msg OutgoingMail) error {
+	if len(msg.To) == 0 {
+		return errors.New("mail has no recipient")
+	}
+	if msg.From == "" {
+		msg.From = t.from
+	}
+	if msg.MessageID == "" {
+		msg.MessageID = NewMessageID(msg.From)
+	}
+
+	body, err := buildMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create mail directory: %w", err)
+	}
+
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102T150405.000000000"), hex.EncodeToString(suffix))
+	return os.WriteFile(filepath.Join(t.dir, name), body, 0o644)
+}
+
+func NewFileMailer(dir string, from string) Mailer {
+	return &fileMailerImpl{dir: dir, from: from}
+}
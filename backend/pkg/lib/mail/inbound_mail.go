@@ -0,0 +1,189 @@
+package mail
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	netMail "net/mail"
+	"regexp"
+	"strings"
+)
+
+type InboundAttachment struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+type InboundMail struct {
+	MessageID   string
+	InReplyTo   string
+	References  []string
+	FromName    string
+	FromAddress string
+	To          []string
+	Subject     string
+	Text        string
+	HTML        string
+	Attachments []InboundAttachment
+	// ContentHash is the hex sha256 of the raw message, a retried delivery
+	// of a mail without Message-ID is recognized by it
+	ContentHash string
+}
+
+// maxMultipartDepth bounds how deep multiparts may nest, real mail rarely
+// goes past three levels.
+const maxMultipartDepth = 10
+
+var (
+	ErrInvalidMail = errors.New("invalid mail message")
+
+	htmlTagPattern = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankPattern   = regexp.MustCompile(`\n{3,}`)
+)
+
+// ParseInboundMail reads a raw RFC 5322 message and collects the text and html
+// bodies together with the attachments of every nested multipart.
+func ParseInboundMail(r io.Reader) (*InboundMail, error) {
+	hash := sha256.New()
+	r = io.TeeReader(r, hash)
+
+	message, err := netMail.ReadMessage(r)
+	if err != nil {
+		return nil, ErrInvalidMail
+	}
+
+	decoder := new(mime.WordDecoder)
+	subject, err := decoder.DecodeHeader(message.Header.Get("Subject"))
+	if err != nil {
+		subject = message.Header.Get("Subject")
+	}
+
+	result := &InboundMail{
+		MessageID:  strings.TrimSpace(message.Header.Get("Message-ID")),
+		InReplyTo:  strings.TrimSpace(message.Header.Get("In-Reply-To")),
+		References: strings.Fields(message.Header.Get("References")),
+		Subject:    subject,
+	}
+
+	from, err := netMail.ParseAddress(message.Header.Get("From"))
+	if err != nil {
+		return nil, ErrInvalidMail
+	}
+	result.FromName = from.Name
+	result.FromAddress = strings.ToLower(from.Address)
+
+	if to, err := message.Header.AddressList("To"); err == nil {
+		for _, address := range to {
+			result.To = append(result.To, strings.ToLower(address.Address))
+		}
+	}
+
+	if err := result.readPart(
+		message.Header.Get("Content-Type"),
+		message.Header.Get("Content-Transfer-Encoding"),
+		"",
+		message.Body,
+		0,
+	); err != nil {
+		return nil, err
+	}
+
+	if result.Text == "" && result.HTML != "" {
+		result.Text = htmlToText(result.HTML)
+	}
+	result.Text = strings.TrimSpace(result.Text)
+
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return nil, ErrInvalidMail
+	}
+	result.ContentHash = hex.EncodeToString(hash.Sum(nil))
+
+	return result, nil
+}
+
+func (t *InboundMail) readPart(contentType, transferEncoding, disposition string, body io.Reader, depth int) error {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+		params = map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		if depth >= maxMultipartDepth {
+			return ErrInvalidMail
+		}
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return ErrInvalidMail
+			}
+			if err := t.readPart(
+				part.Header.Get("Content-Type"),
+				part.Header.Get("Content-Transfer-Encoding"),
+				part.Header.Get("Content-Disposition"),
+				part,
+				depth+1,
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	content, err := io.ReadAll(decodeTransfer(transferEncoding, body))
+	if err != nil {
+		return ErrInvalidMail
+	}
+
+	dispositionType, dispositionParams, _ := mime.ParseMediaType(disposition)
+	fileName := dispositionParams["filename"]
+	if fileName == "" {
+		fileName = params["name"]
+	}
+
+	isAttachment := dispositionType == "attachment" || fileName != ""
+	switch {
+	case !isAttachment && mediaType == "text/plain" && t.Text == "":
+		t.Text = string(content)
+	case !isAttachment && mediaType == "text/html" && t.HTML == "":
+		t.HTML = string(content)
+	default:
+		if fileName == "" {
+			fileName = "attachment"
+		}
+		t.Attachments = append(t.Attachments, InboundAttachment{
+			FileName:    fileName,
+			ContentType: mediaType,
+			Content:     content,
+		})
+	}
+	return nil
+}
+
+func decodeTransfer(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		// the decoder skips the line breaks base64 bodies are wrapped with
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+func htmlToText(content string) string {
+	content = strings.NewReplacer("<br>", "\n", "<br/>", "\n", "<br />", "\n", "</p>", "\n\n", "</div>", "\n").Replace(content)
+	content = html.UnescapeString(htmlTagPattern.ReplaceAllString(content, ""))
+	return blankPattern.ReplaceAllString(content, "\n\n")
+}
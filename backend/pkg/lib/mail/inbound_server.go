@@ -0,0 +1,359 @@
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+)
+
+type Envelope struct {
+	RemoteAddr string
+	From       string
+	Recipients []string
+	Data       []byte
+}
+
+// InboundHandler receives every accepted message once the DATA command is done.
+// Returning an error makes the server answer with a transient failure so the
+// sending MTA retries later.
+type InboundHandler interface {
+	AcceptRecipient(address string) bool
+	HandleEnvelope(envelope Envelope) error
+}
+
+// InboundOptions configure the smtp listener. ReadTimeout bounds the wait
+// for each line of the client and SessionTimeout the whole connection, so a
+// slow client can not hold a connection open.
+type InboundOptions struct {
+	Addr            string
+	Hostname        string
+	MaxMessageBytes int
+	MaxRecipients   int
+	ReadTimeout     time.Duration
+	SessionTimeout  time.Duration
+}
+
+// maxCommandBytes caps a command line, RFC 5321 section 4.5.3.1.4 allows
+// 512 octets.
+const maxCommandBytes = 512
+
+// InboundServer is a small SMTP listener that only speaks the subset of the
+// protocol needed to receive mail from a relay or directly from an MTA.
+type InboundServer struct {
+	options InboundOptions
+	handler InboundHandler
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+	closed   bool
+}
+
+func NewInboundServer(options InboundOptions, handler InboundHandler) *InboundServer {
+	return &InboundServer{
+		options: options,
+		handler: handler,
+		conns:   map[net.Conn]struct{}{},
+	}
+}
+
+// Start implements workers.Worker.
+func (t *InboundServer) Start() {
+	listener, err := net.Listen("tcp", t.options.Addr)
+	if err != nil {
+		logger.ErrorLog("Failed to start smtp listener", map[string]any{
+			"addr":  t.options.Addr,
+			"error": err.Error(),
+		})
+		return
+	}
+
+	t.mu.Lock()
+	t.listener = listener
+	t.mu.Unlock()
+
+	logger.InfoLog("Smtp listener running", map[string]any{
+		"addr": t.options.Addr,
+	})
+
+	go t.serve(listener)
+}
+
+// Shutdown implements workers.Worker.
+func (t *InboundServer) Shutdown(ctx context.Context) error {
+	t.mu.Lock()
+	t.closed = true
+	if t.listener != nil {
+		t.listener.Close()
+	}
+	t.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		t.mu.Lock()
+		for conn := range t.conns {
+			conn.Close()
+		}
+		t.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+func (t *InboundServer) serve(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			t.mu.Lock()
+			closed := t.closed
+			t.mu.Unlock()
+			if closed {
+				return
+			}
+			logger.ErrorLog("Failed to accept smtp connection", map[string]any{
+				"error": err.Error(),
+			})
+			continue
+		}
+
+		t.mu.Lock()
+		if t.closed {
+			t.mu.Unlock()
+			conn.Close()
+			return
+		}
+		t.conns[conn] = struct{}{}
+		t.wg.Add(1)
+		t.mu.Unlock()
+
+		go func() {
+			defer func() {
+				t.mu.Lock()
+				delete(t.conns, conn)
+				t.mu.Unlock()
+				conn.Close()
+				t.wg.Done()
+			}()
+			t.handleConn(conn)
+		}()
+	}
+}
+
+func (t *InboundServer) handleConn(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	writer := bufio.NewWriter(conn)
+
+	sessionEnd := time.Now().Add(t.options.SessionTimeout)
+	extend := func() {
+		deadline := time.Now().Add(t.options.ReadTimeout)
+		if deadline.After(sessionEnd) {
+			deadline = sessionEnd
+		}
+		conn.SetDeadline(deadline)
+	}
+
+	reply := func(format string, args ...any) {
+		fmt.Fprintf(writer, format+"\r\n", args...)
+		writer.Flush()
+	}
+
+	// started is set by MAIL, the reverse path may be empty for bounces so
+	// envelope.From can not tell
+	envelope := Envelope{RemoteAddr: conn.RemoteAddr().String()}
+	started := false
+	reset := func() {
+		envelope = Envelope{RemoteAddr: envelope.RemoteAddr}
+		started = false
+	}
+
+	extend()
+	reply("220 %s ESMTP sociomile", t.options.Hostname)
+
+	for {
+		extend()
+		line, err := readCommand(reader)
+		if errors.Is(err, errLineTooLong) {
+			reply("500 line too long")
+			return
+		}
+		if err != nil {
+			return
+		}
+
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "HELO":
+			reply("250 %s", t.options.Hostname)
+		case "EHLO":
+			fmt.Fprintf(writer, "250-%s\r\n", t.options.Hostname)
+			fmt.Fprintf(writer, "250-SIZE %d\r\n", t.options.MaxMessageBytes)
+			fmt.Fprintf(writer, "250-8BITMIME\r\n")
+			reply("250 PIPELINING")
+		case "MAIL":
+			address, ok := parsePathArgument(arg, "FROM:")
+			if !ok {
+				reply("501 syntax error in MAIL command")
+				continue
+			}
+			reset()
+			envelope.From = address
+			started = true
+			reply("250 OK")
+		case "RCPT":
+			if !started {
+				reply("503 bad sequence of commands, need MAIL command first")
+				continue
+			}
+			address, ok := parsePathArgument(arg, "TO:")
+			if !ok {
+				reply("501 syntax error in RCPT command")
+				continue
+			}
+			if len(envelope.Recipients) >= t.options.MaxRecipients {
+				reply("452 too many recipients")
+				continue
+			}
+			if !t.handler.AcceptRecipient(address) {
+				reply("550 no such mailbox here")
+				continue
+			}
+			envelope.Recipients = append(envelope.Recipients, address)
+			reply("250 OK")
+		case "DATA":
+			if len(envelope.Recipients) == 0 {
+				reply("503 need RCPT command first")
+				continue
+			}
+			reply("354 end data with <CR><LF>.<CR><LF>")
+
+			data, err := t.readData(reader, extend)
+			if errors.Is(err, errMessageTooLarge) {
+				reply("552 message exceeds fixed maximum message size")
+				reset()
+				continue
+			}
+			if err != nil {
+				return
+			}
+
+			envelope.Data = data
+			if err := t.handler.HandleEnvelope(envelope); err != nil {
+				logger.ErrorLog("Failed to process inbound mail", map[string]any{
+					"from":  envelope.From,
+					"error": err.Error(),
+				})
+				reply("451 requested action aborted: local error in processing")
+			} else {
+				reply("250 OK queued")
+			}
+			reset()
+		case "RSET":
+			reset()
+			reply("250 OK")
+		case "NOOP":
+			reply("250 OK")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 command not implemented")
+		}
+	}
+}
+
+var (
+	errMessageTooLarge = errors.New("message too large")
+	errLineTooLong     = errors.New("line too long")
+)
+
+// readCommand reads one command line without its line break.
+func readCommand(reader *bufio.Reader) (string, error) {
+	var line []byte
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if len(line)+len(chunk) > maxCommandBytes {
+			return "", errLineTooLong
+		}
+		line = append(line, chunk...)
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(line), "\r\n"), nil
+	}
+}
+
+// readData reads the message up to the lone dot. It is read in pieces of
+// the reader's buffer so a long line is counted against the size limit
+// before it is held in memory, extend moves the deadline on every piece.
+func (t *InboundServer) readData(reader *bufio.Reader, extend func()) ([]byte, error) {
+	var buf bytes.Buffer
+	tooLarge := false
+	lineStart := true
+
+	for {
+		extend()
+		chunk, err := reader.ReadSlice('\n')
+		complete := err == nil
+		if err != nil && !errors.Is(err, bufio.ErrBufferFull) {
+			if errors.Is(err, io.EOF) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+
+		if lineStart && complete && bytes.Equal(bytes.TrimRight(chunk, "\r\n"), []byte(".")) {
+			break
+		}
+		// undo the dot stuffing from RFC 5321 section 4.5.2
+		if lineStart && bytes.HasPrefix(chunk, []byte("..")) {
+			chunk = chunk[1:]
+		}
+		lineStart = complete
+
+		if tooLarge || buf.Len()+len(chunk) > t.options.MaxMessageBytes {
+			tooLarge = true
+			continue
+		}
+		buf.Write(chunk)
+	}
+
+	if tooLarge {
+		return nil, errMessageTooLarge
+	}
+	return buf.Bytes(), nil
+}
+
+func parsePathArgument(arg, prefix string) (string, bool) {
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return "", false
+	}
+
+	path := strings.TrimSpace(arg[len(prefix):])
+	if idx := strings.Index(path, ">"); idx >= 0 {
+		path = path[:idx+1]
+	}
+	path = strings.Trim(path, "<>")
+
+	return strings.ToLower(path), true
+}
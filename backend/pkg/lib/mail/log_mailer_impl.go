@@ -0,0 +1,34 @@
+package mail
+
+import (
+	"DewaSRY/sociomile-app/pkg/lib/logger"
+	"errors"
+)
+
+// logMailerImpl writes the mail to the application log instead of sending
+// it, for local development without a relay.
+type logMailerImpl struct {
+	from string
+}
+
+// Send implements Mailer.
+func (t *logMailerImpl) Send(msg OutgoingMail) error {
+	if len(msg.To) == 0 {
+		return errors.New("mail has no recipient")
+	}
+	if msg.From == "" {
+		msg.From = t.from
+	}
+
+	logger.InfoLog("Mail not sent, log mailer", map[string]any{
+		"from":    msg.From,
+		"to":      msg.To,
+		"subject": msg.Subject,
+		"text":    msg.Text,
+	})
+	return nil
+}
+
+func NewLogMailer(from string) Mailer {
+	return &logMailerImpl{from: from}
+}
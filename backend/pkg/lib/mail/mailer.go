@@ -0,0 +1,36 @@
+package mail
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+type OutgoingMail struct {
+	From       string
+	To         []string
+	Subject    string
+	Text       string
+	HTML       string
+	MessageID  string
+	InReplyTo  string
+	References []string
+}
+
+type Mailer interface {
+	Send(msg OutgoingMail) error
+}
+
+// NewMessageID builds an RFC 5322 Message-ID using the domain part of the
+// given address, so replies from the customer can be threaded back.
+func NewMessageID(address string) string {
+	domain := "sociomile.local"
+	if at := strings.LastIndex(address, "@"); at >= 0 && at < len(address)-1 {
+		domain = strings.Trim(address[at+1:], "<> ")
+	}
+
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(buf), domain)
+}
@@ -0,0 +1,129 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+type smtpMailerImpl struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+// Send implements Mailer.
+func (t *smtpMailerImpl) Send(msg OutgoingMail) error {
+	if len(msg.To) == 0 {
+		return errors.New("mail has no recipient")
+	}
+	if msg.From == "" {
+		msg.From = t.from
+	}
+	if msg.MessageID == "" {
+		msg.MessageID = NewMessageID(msg.From)
+	}
+
+	body, err := buildMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if t.username != "" {
+		auth = smtp.PlainAuth("", t.username, t.password, t.host)
+	}
+
+	return smtp.SendMail(t.addr, auth, msg.From, msg.To, body)
+}
+
+func buildMessage(msg OutgoingMail) ([]byte, error) {
+	var buf bytes.Buffer
+
+	headers := []string{
+		"From: " + msg.From,
+		"To: " + strings.Join(msg.To, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: " + msg.MessageID,
+		"MIME-Version: 1.0",
+	}
+	if msg.InReplyTo != "" {
+		headers = append(headers, "In-Reply-To: "+msg.InReplyTo)
+	}
+	if len(msg.References) > 0 {
+		headers = append(headers, "References: "+strings.Join(msg.References, " "))
+	}
+
+	if msg.HTML == "" {
+		headers = append(headers,
+			"Content-Type: text/plain; charset=utf-8",
+			"Content-Transfer-Encoding: quoted-printable",
+		)
+		buf.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+		if err := writeQuotedPrintable(&buf, msg.Text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	writer := multipart.NewWriter(&buf)
+	headers = append(headers, fmt.Sprintf("Content-Type: multipart/alternative; boundary=%q", writer.Boundary()))
+	header := []byte(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return append(header, buf.Bytes()...), nil
+}
+
+func writeQuotedPrintable(buf *bytes.Buffer, content string) error {
+	qp := quotedprintable.NewWriter(buf)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func NewSmtpMailer(host, port, username, password, from string) Mailer {
+	return &smtpMailerImpl{
+		addr:     net.JoinHostPort(host, port),
+		host:     host,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
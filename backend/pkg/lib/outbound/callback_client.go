@@ -0,0 +1,80 @@
+package outbound
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrCallbackNotHTTPS       = errors.New("callback url must use https")
+	ErrCallbackAddressBlocked = errors.New("callback url resolves to a private address")
+)
+
+// sharedAddressSpace is the carrier grade nat range, it is not covered by
+// netip.Addr.IsPrivate but is just as internal.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// CheckCallbackURL refuses urls that are not https. The address the host
+// resolves to is checked when the connection is made, see newCallbackClient.
+func CheckCallbackURL(raw string) (*url.URL, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Hostname() == "" {
+		return nil, fmt.Errorf("invalid callback url")
+	}
+	if parsed.Scheme != "https" {
+		return nil, ErrCallbackNotHTTPS
+	}
+	return parsed, nil
+}
+
+// publicAddress reports whether the integration may be reached at addr, it
+// keeps callbacks away from the server itself and the network it runs in.
+func publicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// newCallbackClient is the http client for urls given by integrations. The
+// address is checked after dns resolution, right before the connection is
+// made, so a host that resolves to an internal address is refused even when
+// its record changes between the check and the request. Redirects are not
+// followed and proxies from the environment are not used.
+func newCallbackClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !publicAddress(addrPort.Addr()) {
+				return ErrCallbackAddressBlocked
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,53 @@
+package outbound
+
+import "errors"
+
+// Delivery is one queued outbound message handed to the dispatcher of the
+// conversation's origin channel. Payload is the channel specific body that
+// was prepared when the message was queued.
+type Delivery struct {
+	ID          uint
+	Channel     string
+	Destination string
+	Payload     []byte
+}
+
+// Result carries what the provider told us about an accepted delivery.
+type Result struct {
+	ProviderMessageID string
+	// Delivered is true when the provider already confirmed the recipient got it
+	Delivered bool
+}
+
+type Dispatcher interface {
+	Channel() string
+	Dispatch(delivery Delivery) (*Result, error)
+}
+
+var ErrNoDispatcher = errors.New("no outbound dispatcher for channel")
+
+// Registry resolves the dispatcher by the conversation's origin channel.
+type Registry struct {
+	dispatchers map[string]Dispatcher
+}
+
+func NewRegistry(dispatchers ...Dispatcher) *Registry {
+	registry := &Registry{dispatchers: map[string]Dispatcher{}}
+	for _, dispatcher := range dispatchers {
+		registry.dispatchers[dispatcher.Channel()] = dispatcher
+	}
+	return registry
+}
+
+func (t *Registry) Supports(channel string) bool {
+	_, ok := t.dispatchers[channel]
+	return ok
+}
+
+func (t *Registry) Dispatch(delivery Delivery) (*Result, error) {
+	dispatcher, ok := t.dispatchers[delivery.Channel]
+	if !ok {
+		return nil, ErrNoDispatcher
+	}
+	return dispatcher.Dispatch(delivery)
+}
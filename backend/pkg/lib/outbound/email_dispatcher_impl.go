@@ -0,0 +1,35 @@
+package outbound
+
+import (
+	"encoding/json"
+	"errors"
+
+	"DewaSRY/sociomile-app/pkg/lib/mail"
+)
+
+type emailDispatcherImpl struct {
+	channel string
+	mailer  mail.Mailer
+}
+
+func (t *emailDispatcherImpl) Channel() string {
+	return t.channel
+}
+
+// Dispatch expects the payload to be a json encoded mail.OutgoingMail.
+func (t *emailDispatcherImpl) Dispatch(delivery Delivery) (*Result, error) {
+	var msg mail.OutgoingMail
+	if err := json.Unmarshal(delivery.Payload, &msg); err != nil {
+		return nil, errors.New("invalid email payload")
+	}
+
+	if err := t.mailer.Send(msg); err != nil {
+		return nil, err
+	}
+
+	return &Result{ProviderMessageID: msg.MessageID}, nil
+}
+
+func NewEmailDispatcher(channel string, mailer mail.Mailer) Dispatcher {
+	return &emailDispatcherImpl{channel: channel, mailer: mailer}
+}
@@ -0,0 +1,126 @@
+package outbound
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const SignatureHeader = "X-Sociomile-Signature"
+
+var (
+	ErrSignatureInvalid = errors.New("webhook signature is invalid")
+	ErrSignatureExpired = errors.New("webhook signature is too old")
+)
+
+type SignedRequest struct {
+	URL     string
+	Secret  string
+	Headers map[string]string
+	Body    []byte
+}
+
+// WebhookSender posts json bodies signed with the subscription secret.
+type WebhookSender interface {
+	Send(req SignedRequest) (int, error)
+}
+
+type webhookSenderImpl struct {
+	client *http.Client
+}
+
+// Send returns the response status code even when it is not a 2xx so it can
+// be shown in the delivery log.
+func (t *webhookSenderImpl) Send(req SignedRequest) (int, error) {
+	destination, err := CheckCallbackURL(req.URL)
+	if err != nil {
+		return 0, err
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, destination.String(), bytes.NewReader(req.Body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(SignatureHeader, fmt.Sprintf("t=%d,v1=%s", timestamp, SignPayload(req.Secret, timestamp, req.Body)))
+	for key, value := range req.Headers {
+		httpReq.Header.Set(key, value)
+	}
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint answered %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return resp.StatusCode, nil
+}
+
+// SignPayload is the hex hmac-sha256 of "<timestamp>.<body>". Receivers
+// recompute it with their secret and reject old timestamps to stop replays.
+func SignPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks a "t=<timestamp>,v1=<hex>" header against the body.
+// Timestamps further than tolerance from now are refused so a captured
+// request cannot be replayed later.
+func VerifySignature(secret string, header string, body []byte, tolerance time.Duration) error {
+	var timestamp int64
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "t":
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return ErrSignatureInvalid
+			}
+			timestamp = parsed
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if secret == "" || timestamp == 0 || len(signatures) == 0 {
+		return ErrSignatureInvalid
+	}
+
+	age := time.Since(time.Unix(timestamp, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrSignatureExpired
+	}
+
+	expected := []byte(SignPayload(secret, timestamp, body))
+	for _, signature := range signatures {
+		if hmac.Equal(expected, []byte(signature)) {
+			return nil
+		}
+	}
+	return ErrSignatureInvalid
+}
+
+func NewWebhookSender(timeout time.Duration) WebhookSender {
+	return &webhookSenderImpl{client: newCallbackClient(timeout)}
+}
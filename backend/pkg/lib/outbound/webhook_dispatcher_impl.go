@@ -0,0 +1,58 @@
+package outbound
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type webhookDispatcherImpl struct {
+	channel string
+	client  *http.Client
+}
+
+func (t *webhookDispatcherImpl) Channel() string {
+	return t.channel
+}
+
+// Dispatch posts the payload to the callback url the integration registered
+// for the conversation. Any 2xx answer counts as sent.
+func (t *webhookDispatcherImpl) Dispatch(delivery Delivery) (*Result, error) {
+	if delivery.Destination == "" {
+		return nil, fmt.Errorf("missing callback url")
+	}
+	destination, err := CheckCallbackURL(delivery.Destination)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, destination.String(), bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sociomile-Delivery", fmt.Sprint(delivery.ID))
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("callback answered %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return &Result{ProviderMessageID: resp.Header.Get("X-Message-Id")}, nil
+}
+
+func NewWebhookDispatcher(channel string, timeout time.Duration) Dispatcher {
+	return &webhookDispatcherImpl{
+		channel: channel,
+		client:  newCallbackClient(timeout),
+	}
+}
@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens   float64
+	updated  time.Time
+	capacity float64
+	refill   float64
+}
+
+type memoryStoreImpl struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// Take implements Store.
+func (t *memoryStoreImpl) Take(key string, limit Limit) Decision {
+	if limit.Disabled() {
+		return Decision{Allowed: true}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.sweep(now)
+
+	capacity := float64(limit.Requests)
+	refill := capacity / limit.Per.Seconds()
+
+	current, ok := t.buckets[key]
+	if !ok || current.capacity != capacity || current.refill != refill {
+		current = &bucket{tokens: capacity, updated: now, capacity: capacity, refill: refill}
+		t.buckets[key] = current
+	}
+
+	current.tokens += now.Sub(current.updated).Seconds() * current.refill
+	if current.tokens > current.capacity {
+		current.tokens = current.capacity
+	}
+	current.updated = now
+
+	if current.tokens < 1 {
+		missing := 1 - current.tokens
+		return Decision{
+			Allowed:    false,
+			RetryAfter: time.Duration(missing / current.refill * float64(time.Second)),
+		}
+	}
+
+	current.tokens--
+	return Decision{Allowed: true, Remaining: int(current.tokens)}
+}
+
+// sweep drops the buckets that are full again, they behave the same as a
+// missing bucket and would otherwise grow the map forever.
+func (t *memoryStoreImpl) sweep(now time.Time) {
+	if now.Sub(t.lastSweep) < time.Minute {
+		return
+	}
+	t.lastSweep = now
+
+	for key, current := range t.buckets {
+		if current.tokens+now.Sub(current.updated).Seconds()*current.refill >= current.capacity {
+			delete(t.buckets, key)
+		}
+	}
+}
+
+func NewMemoryStore() Store {
+	return &memoryStoreImpl{
+		buckets:   map[string]*bucket{},
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
@@ -0,0 +1,43 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// Limit is a token bucket that holds at most Requests tokens and refills
+// them evenly over Per.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+func (t Limit) Disabled() bool {
+	return t.Requests <= 0 || t.Per <= 0
+}
+
+type Decision struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets. The in memory store only works for a single
+// instance, a shared store (e.g. redis) can implement the same interface.
+type Store interface {
+	Take(key string, limit Limit) Decision
+}
+
+// RetryAfterSeconds rounds up so clients never retry too early.
+func (t Decision) RetryAfterSeconds() int {
+	return int(math.Ceil(t.RetryAfter.Seconds()))
+}
+
+// Subject identifies who is calling a rate limited route. Empty fields are
+// not limited.
+type Subject struct {
+	Route          string
+	OrganizationID uint
+	ContactKey     string
+	IP             string
+}
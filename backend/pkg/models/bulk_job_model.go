@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// BulkJobModel applies one action to many tickets or conversations. The
+// items are resolved when the job is created and handled in chunks,
+// Processed is how many of them are done so a job picks up where it
+// stopped.
+type BulkJobModel struct {
+	ID             uint            `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	OrganizationID uint            `gorm:"not null;index" json:"organization_id"`
+	CreatedByID    uint            `gorm:"not null" json:"created_by_id"`
+	SubjectType    string          `gorm:"type:varchar(20);not null" json:"subject_type"`
+	Action         string          `gorm:"type:varchar(20);not null" json:"action"`
+	Payload        BulkJobPayload  `gorm:"type:json;serializer:json;not null" json:"payload"`
+	ItemIDs        []uint          `gorm:"type:json;serializer:json;not null" json:"item_ids"`
+	Status         string          `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	Total          int             `gorm:"not null" json:"total"`
+	Processed      int             `gorm:"not null;default:0" json:"processed"`
+	Succeeded      int             `gorm:"not null;default:0" json:"succeeded"`
+	Failed         int             `gorm:"not null;default:0" json:"failed"`
+	Results        []BulkJobResult `gorm:"type:json;serializer:json;not null" json:"results"`
+	Error          *string         `gorm:"type:text" json:"error,omitempty"`
+	StartedAt      *time.Time      `json:"started_at,omitempty"`
+	FinishedAt     *time.Time      `json:"finished_at,omitempty"`
+	// HeartbeatAt is refreshed when a worker claims the job and after every
+	// chunk, a running job whose heartbeat stopped is taken over
+	HeartbeatAt *time.Time `gorm:"index" json:"heartbeat_at,omitempty"`
+}
+
+func (BulkJobModel) TableName() string {
+	return "bulk_jobs"
+}
+
+// BulkJobPayload holds the fields the action needs, AssigneeID 0 removes
+// the assignee of a ticket.
+type BulkJobPayload struct {
+	Status         string  `json:"status,omitempty"`
+	AssigneeID     *uint   `json:"assignee_id,omitempty"`
+	Tag            string  `json:"tag,omitempty"`
+	ResolutionNote *string `json:"resolution_note,omitempty"`
+}
+
+type BulkJobResult struct {
+	ID    uint    `json:"id"`
+	Error *string `json:"error,omitempty"`
+}
+
+// Constants for the bulk job statuses
+const (
+	BulkJobPending   = "pending"
+	BulkJobRunning   = "running"
+	BulkJobCompleted = "completed"
+	BulkJobFailed    = "failed"
+)
+
+// Constants for the bulk actions
+const (
+	BulkActionStatus    = "status"
+	BulkActionAssign    = "assign"
+	BulkActionAddTag    = "add_tag"
+	BulkActionRemoveTag = "remove_tag"
+	BulkActionClose     = "close"
+)
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ContactAttributeDefinitionModel is a custom field the organization stores
+// on its contacts, e.g. customer tier or city. Key is what webhooks and
+// filters refer to, Options lists the allowed values of an enum.
+type ContactAttributeDefinitionModel struct {
+	ID             uint               `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+	OrganizationID uint               `gorm:"not null;uniqueIndex:idx_contact_attribute_definitions_key" json:"organization_id"`
+	Organization   *OrganizationModel `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
+	Key            string             `gorm:"not null;uniqueIndex:idx_contact_attribute_definitions_key" json:"key"`
+	Label          string             `gorm:"not null" json:"label"`
+	Type           string             `gorm:"not null" json:"type"`
+	Options        []string           `gorm:"type:json;serializer:json" json:"options,omitempty"`
+}
+
+func (ContactAttributeDefinitionModel) TableName() string {
+	return "contact_attribute_definitions"
+}
+
+// ContactAttributeValueModel is the value of one attribute on a contact,
+// stored normalized as text so filters can compare it directly.
+type ContactAttributeValueModel struct {
+	ID             uint                             `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time                        `json:"created_at"`
+	UpdatedAt      time.Time                        `json:"updated_at"`
+	OrganizationID uint                             `gorm:"not null;index" json:"organization_id"`
+	ContactID      uint                             `gorm:"not null;uniqueIndex:idx_contact_attribute_values_contact" json:"contact_id"`
+	DefinitionID   uint                             `gorm:"not null;uniqueIndex:idx_contact_attribute_values_contact" json:"definition_id"`
+	Definition     *ContactAttributeDefinitionModel `gorm:"foreignKey:DefinitionID" json:"definition,omitempty"`
+	Value          string                           `gorm:"type:varchar(1000);not null" json:"value"`
+}
+
+func (ContactAttributeValueModel) TableName() string {
+	return "contact_attribute_values"
+}
+
+// ContactNoteModel is a free-form note staff keeps on a contact.
+type ContactNoteModel struct {
+	ID             uint           `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+	OrganizationID uint           `gorm:"not null;index" json:"organization_id"`
+	ContactID      uint           `gorm:"not null;index" json:"contact_id"`
+	AuthorID       uint           `gorm:"not null" json:"author_id"`
+	Author         *UserModel     `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
+	Body           string         `gorm:"type:text;not null" json:"body"`
+}
+
+func (ContactNoteModel) TableName() string {
+	return "contact_notes"
+}
+
+// Constants for the contact attribute types
+const (
+	ContactAttributeTypeString  = "string"
+	ContactAttributeTypeNumber  = "number"
+	ContactAttributeTypeDate    = "date"
+	ContactAttributeTypeEnum    = "enum"
+	ContactAttributeTypeBoolean = "boolean"
+)
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ContactModel is an external customer of one organization. Contacts never
+// log in, a registered guest user can be linked to any number of them.
+type ContactModel struct {
+	ID             uint                   `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt         `gorm:"index" json:"-"`
+	OrganizationID uint                   `gorm:"not null;index" json:"organization_id"`
+	Organization   *OrganizationModel     `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
+	Name           string                 `gorm:"not null;default:''" json:"name"`
+	AvatarURL      *string                `gorm:"type:varchar(2048)" json:"avatar_url,omitempty"`
+	UserID         *uint                  `gorm:"index" json:"user_id,omitempty"`
+	User           *UserModel             `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Identities     []ContactIdentityModel `gorm:"foreignKey:ContactID" json:"identities,omitempty"`
+
+	AttributeValues []ContactAttributeValueModel `gorm:"foreignKey:ContactID" json:"attribute_values,omitempty"`
+}
+
+func (ContactModel) TableName() string {
+	return "contacts"
+}
+
+// ContactIdentityModel is one way to reach a contact, an email address, a
+// phone number or the id of the contact on an external channel. A value is
+// unique per organization and type, MatchKey groups values that probably
+// belong to the same person and drives the merge suggestions.
+type ContactIdentityModel struct {
+	ID             uint          `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+	OrganizationID uint          `gorm:"not null;uniqueIndex:idx_contact_identities_value;index:idx_contact_identities_match_key" json:"organization_id"`
+	ContactID      uint          `gorm:"not null;index" json:"contact_id"`
+	Contact        *ContactModel `gorm:"foreignKey:ContactID" json:"contact,omitempty"`
+	Type           string        `gorm:"not null;uniqueIndex:idx_contact_identities_value" json:"type"`
+	Value          string        `gorm:"not null;uniqueIndex:idx_contact_identities_value" json:"value"`
+	MatchKey       *string       `gorm:"index:idx_contact_identities_match_key" json:"match_key,omitempty"`
+}
+
+func (ContactIdentityModel) TableName() string {
+	return "contact_identities"
+}
+
+// Constants for the contact identity types, any other type is the name of
+// the external channel the value belongs to, e.g. whatsapp
+const (
+	ContactIdentityTypeEmail = "email"
+	ContactIdentityTypePhone = "phone"
+	// ContactIdentityTypeWidget is the random id of an anonymous widget visitor
+	ContactIdentityTypeWidget = "widget"
+	// ContactIdentityTypeClaimedEmail is an email nobody proved, e.g. one a
+	// widget visitor gave, it only feeds the merge suggestions
+	ContactIdentityTypeClaimedEmail = "claimed_email"
+)
+
+// ContactMergeModel logs the merge of the source contact into the target.
+// The moved identities, conversations, notes and attribute values are kept so
+// the merge can be undone, the source contact stays soft deleted until then.
+// Source attribute values the target already had a value for are not moved.
+type ContactMergeModel struct {
+	ID                uint          `gorm:"primarykey" json:"id"`
+	CreatedAt         time.Time     `json:"created_at"`
+	UpdatedAt         time.Time     `json:"updated_at"`
+	OrganizationID    uint          `gorm:"not null;index" json:"organization_id"`
+	TargetContactID   uint          `gorm:"not null;index" json:"target_contact_id"`
+	TargetContact     *ContactModel `gorm:"foreignKey:TargetContactID" json:"target_contact,omitempty"`
+	SourceContactID   uint          `gorm:"not null;index" json:"source_contact_id"`
+	SourceContact     *ContactModel `gorm:"foreignKey:SourceContactID" json:"source_contact,omitempty"`
+	MergedByID        uint          `gorm:"not null" json:"merged_by_id"`
+	IdentityIDs       []uint        `gorm:"type:json;serializer:json;not null" json:"identity_ids"`
+	ConversationIDs   []uint        `gorm:"type:json;serializer:json;not null" json:"conversation_ids"`
+	NoteIDs           []uint        `gorm:"type:json;serializer:json;not null" json:"note_ids"`
+	AttributeValueIDs []uint        `gorm:"type:json;serializer:json;not null" json:"attribute_value_ids"`
+	UserMoved         bool          `gorm:"not null;default:false" json:"user_moved"`
+	UndoneAt          *time.Time    `json:"undone_at,omitempty"`
+	UndoneByID        *uint         `json:"undone_by_id,omitempty"`
+}
+
+func (ContactMergeModel) TableName() string {
+	return "contact_merges"
+}
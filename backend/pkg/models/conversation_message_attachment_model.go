@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ConversationMessageAttachmentModel struct {
+	ID             uint                      `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time                 `json:"created_at"`
+	UpdatedAt      time.Time                 `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt            `gorm:"index" json:"-"`
+	OrganizationID uint                      `gorm:"not null;index" json:"organization_id"`
+	MessageID      uint                      `gorm:"not null;index" json:"message_id"`
+	Message        *ConversationMessageModel `gorm:"foreignKey:MessageID" json:"message,omitempty"`
+	FileName       string                    `gorm:"not null" json:"file_name"`
+	ContentType    string                    `gorm:"not null" json:"content_type"`
+	Size           int                       `gorm:"not null" json:"size"`
+	Content        []byte                    `gorm:"type:longblob;not null" json:"-"`
+}
+
+func (ConversationMessageAttachmentModel) TableName() string {
+	return "conversation_message_attachments"
+}
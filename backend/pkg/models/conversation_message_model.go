@@ -15,9 +15,22 @@ type ConversationMessageModel struct {
 	Organization   *OrganizationModel `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
 	ConversationID uint               `gorm:"not null;index" json:"conversation_id"`
 	Conversation   *ConversationModel `gorm:"foreignKey:ConversationID" json:"conversation,omitempty"`
-	CreatedByID    uint               `gorm:"not null;index" json:"created_by_id"`
+	CreatedByID    *uint              `gorm:"index" json:"created_by_id,omitempty"`
 	CreatedBy      *UserModel         `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
 	Message        string             `gorm:"type:text;not null" json:"message"`
+	HTMLMessage    *string            `gorm:"type:mediumtext" json:"html_message,omitempty"`
+
+	// ContactID is set on messages written by the customer
+	ContactID *uint         `gorm:"index" json:"contact_id,omitempty"`
+	Contact   *ContactModel `gorm:"foreignKey:ContactID" json:"contact,omitempty"`
+
+	// ExternalMessageID keeps the provider message id, e.g. the email Message-ID header
+	ExternalMessageID *string                              `gorm:"index" json:"external_message_id,omitempty"`
+	Attachments       []ConversationMessageAttachmentModel `gorm:"foreignKey:MessageID" json:"attachments,omitempty"`
+	Delivery          *OutboundDeliveryModel               `gorm:"foreignKey:MessageID" json:"delivery,omitempty"`
+
+	// ContentHash is the sha256 of a raw inbound mail without Message-ID
+	ContentHash *string `gorm:"type:char(64)" json:"-"`
 }
 
 func (ConversationMessageModel) TableName() string {
@@ -13,12 +13,18 @@ type ConversationModel struct {
 	DeletedAt            gorm.DeletedAt     `gorm:"index" json:"-"`
 	OrganizationID       uint               `gorm:"not null;index" json:"organization_id"`
 	Organization         *OrganizationModel `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
-	GuestID              uint               `gorm:"not null;index" json:"guest_id"`
+	// GuestID is the registered user who opened the conversation from the guest portal
+	GuestID              *uint              `gorm:"index" json:"guest_id,omitempty"`
 	Guest                *UserModel         `gorm:"foreignKey:GuestID" json:"guest,omitempty"`
+	ContactID            uint               `gorm:"not null;index" json:"contact_id"`
+	Contact              *ContactModel      `gorm:"foreignKey:ContactID" json:"contact,omitempty"`
 	OrganizationStaffID  *uint              `gorm:"index" json:"organization_staff_id,omitempty"`
 	OrganizationStaff    *UserModel         `gorm:"foreignKey:OrganizationStaffID" json:"organization_staff,omitempty"`
 	ConversationMessages  []ConversationMessageModel `gorm:"foreignKey:ConversationID"`
 	Status               string             `gorm:"not null;default:'pending'" json:"status"`
+	Channel              string             `gorm:"not null;default:'web';index" json:"channel"`
+	Subject              *string            `json:"subject,omitempty"`
+	CallbackURL          *string            `gorm:"type:varchar(2048)" json:"callback_url,omitempty"`
 }
 
 func (ConversationModel) TableName() string {
@@ -31,3 +37,11 @@ const (
 	ConversationStatusInProgress = "in_progress"
 	ConversationStatusDone       = "done"
 )
+
+// Constants for the channel a conversation originates from
+const (
+	ConversationChannelWeb     = "web"
+	ConversationChannelWebhook = "webhook"
+	ConversationChannelEmail   = "email"
+	ConversationChannelWidget  = "widget"
+)
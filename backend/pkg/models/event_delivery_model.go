@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventDeliveryModel is one attempt chain of sending an event to a
+// subscription, it doubles as the delivery log shown to the owner.
+type EventDeliveryModel struct {
+	ID             uint                    `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time               `json:"created_at"`
+	UpdatedAt      time.Time               `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt          `gorm:"index" json:"-"`
+	OrganizationID uint                    `gorm:"not null;index" json:"organization_id"`
+	SubscriptionID uint                    `gorm:"not null;index" json:"subscription_id"`
+	Subscription   *EventSubscriptionModel `gorm:"foreignKey:SubscriptionID" json:"subscription,omitempty"`
+	EventID        string                  `gorm:"not null;index" json:"event_id"`
+	EventType      string                  `gorm:"not null" json:"event_type"`
+	Payload        []byte                  `gorm:"type:mediumblob;not null" json:"-"`
+	Status         string                  `gorm:"not null;default:'pending';index" json:"status"`
+	Attempts       int                     `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time               `gorm:"not null;index" json:"next_attempt_at"`
+	ResponseCode   *int                    `json:"response_code,omitempty"`
+	LastError      *string                 `gorm:"type:text" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time              `json:"delivered_at,omitempty"`
+
+	// ReplayOfID points to the delivery that was manually replayed
+	ReplayOfID *uint `gorm:"index" json:"replay_of_id,omitempty"`
+}
+
+func (EventDeliveryModel) TableName() string {
+	return "event_deliveries"
+}
+
+// Constants for event delivery status
+const (
+	EventDeliveryStatusPending    = "pending"
+	EventDeliveryStatusSucceeded  = "succeeded"
+	EventDeliveryStatusFailed     = "failed"
+	EventDeliveryStatusDeadLetter = "dead_letter"
+)
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventSubscriptionModel is an http endpoint registered by an organization
+// owner to receive the events of the selected types.
+type EventSubscriptionModel struct {
+	ID             uint               `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt     `gorm:"index" json:"-"`
+	OrganizationID uint               `gorm:"not null;index" json:"organization_id"`
+	Organization   *OrganizationModel `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
+	URL            string             `gorm:"type:varchar(2048);not null" json:"url"`
+	Secret         string             `gorm:"not null" json:"-"`
+	EventTypes     []string           `gorm:"type:json;serializer:json;not null" json:"event_types"`
+	Active         bool               `gorm:"not null;default:true" json:"active"`
+}
+
+func (EventSubscriptionModel) TableName() string {
+	return "event_subscriptions"
+}
+
+// Constants for the event types an organization can subscribe to
+const (
+	EventConversationCreated       = "conversation.created"
+	EventConversationAssigned      = "conversation.assigned"
+	EventConversationStatusChanged = "conversation.status_changed"
+	EventMessageCreated            = "message.created"
+	EventTicketCreated             = "ticket.created"
+	EventTicketUpdated             = "ticket.updated"
+	EventTicketSLAWarning          = "ticket.sla_warning"
+	EventTicketSLABreached         = "ticket.sla_breached"
+)
+
+// EventPayloadVersion is bumped whenever the shape of an event payload changes
+const EventPayloadVersion = "2026-10-19"
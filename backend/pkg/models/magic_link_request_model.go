@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// MagicLinkRequestModel records a magic link request of an address, known
+// or not, so the hourly limit counts requests rather than sent links.
+type MagicLinkRequestModel struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `gorm:"index:idx_magic_link_requests_email_created,priority:2" json:"created_at"`
+	Email     string    `gorm:"not null;index:idx_magic_link_requests_email_created,priority:1" json:"email"`
+}
+
+func (MagicLinkRequestModel) TableName() string {
+	return "magic_link_requests"
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// MagicLinkTokenModel is a single-use login link sent by email. Only the
+// sha256 of the token is stored, the token itself lives in the mail.
+type MagicLinkTokenModel struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	Email     string     `gorm:"not null;index" json:"email"`
+	TokenHash string     `gorm:"type:char(64);not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+}
+
+func (MagicLinkTokenModel) TableName() string {
+	return "magic_link_tokens"
+}
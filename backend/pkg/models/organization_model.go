@@ -13,6 +13,26 @@ type OrganizationModel struct {
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 	Name      string         `gorm:"not null" json:"name"`
 	OwnerID   uint           `gorm:"not null" json:"owner_id"`
+
+	// InboundEmail is the address the embedded smtp listener accepts mail for
+	InboundEmail *string `gorm:"uniqueIndex" json:"inbound_email,omitempty"`
+
+	// Slug names the organization in public urls such as the chat widget
+	Slug *string `gorm:"type:varchar(64);uniqueIndex" json:"slug,omitempty"`
+	// WidgetAllowedOrigins are the websites allowed to embed the chat widget
+	WidgetAllowedOrigins []string `gorm:"serializer:json" json:"widget_allowed_origins,omitempty"`
+
+	// Timezone is an IANA zone name, exports show times in it
+	Timezone string `gorm:"type:varchar(64);not null;default:'UTC'" json:"timezone"`
+
+	// WebhookSecret signs the requests of the webhook integration, a signed
+	// request is always checked against it
+	WebhookSecret *string `gorm:"type:varchar(128)" json:"-"`
+	// WebhookSignatureRequired refuses unsigned webhook requests, it is
+	// turned on by the organization once its integration signs every request
+	WebhookSignatureRequired bool `gorm:"not null;default:false" json:"webhook_signature_required"`
+	// WebhookCallbackHosts are the https hosts staff replies may be sent to
+	WebhookCallbackHosts []string `gorm:"serializer:json" json:"webhook_callback_hosts,omitempty"`
 	
 	Owner     *UserModel     `gorm:"foreignKey:OwnerID" json:"owner,omitempty"`
 }
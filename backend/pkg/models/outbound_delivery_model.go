@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OutboundDeliveryModel is the persistent queue entry for a staff reply that
+// must be pushed back to the conversation's origin channel.
+type OutboundDeliveryModel struct {
+	ID             uint                      `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time                 `json:"created_at"`
+	UpdatedAt      time.Time                 `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt            `gorm:"index" json:"-"`
+	OrganizationID uint                      `gorm:"not null;index" json:"organization_id"`
+	ConversationID uint                      `gorm:"not null;index" json:"conversation_id"`
+	MessageID      uint                      `gorm:"not null;uniqueIndex" json:"message_id"`
+	Message        *ConversationMessageModel `gorm:"foreignKey:MessageID" json:"message,omitempty"`
+	Channel        string                    `gorm:"not null" json:"channel"`
+	Destination    string                    `gorm:"not null" json:"destination"`
+	Payload        []byte                    `gorm:"type:mediumblob;not null" json:"-"`
+	Status         string                    `gorm:"not null;default:'queued';index" json:"status"`
+	Attempts       int                       `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time                 `gorm:"not null;index" json:"next_attempt_at"`
+	LastError      *string                   `gorm:"type:text" json:"last_error,omitempty"`
+
+	// ReceiptToken authenticates delivery receipts sent back by the provider
+	ReceiptToken      string     `gorm:"not null" json:"-"`
+	ProviderMessageID *string    `json:"provider_message_id,omitempty"`
+	SentAt            *time.Time `json:"sent_at,omitempty"`
+	DeliveredAt       *time.Time `json:"delivered_at,omitempty"`
+}
+
+func (OutboundDeliveryModel) TableName() string {
+	return "outbound_deliveries"
+}
+
+// Constants for outbound delivery status
+const (
+	OutboundStatusQueued     = "queued"
+	OutboundStatusSent       = "sent"
+	OutboundStatusDelivered  = "delivered"
+	OutboundStatusFailed     = "failed"
+	OutboundStatusDeadLetter = "dead_letter"
+)
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OrganizationRateLimitModel overrides the default ingest limits of one
+// organization, a nil column falls back to the configured default.
+type OrganizationRateLimitModel struct {
+	ID                    uint               `gorm:"primarykey" json:"id"`
+	CreatedAt             time.Time          `json:"created_at"`
+	UpdatedAt             time.Time          `json:"updated_at"`
+	DeletedAt             gorm.DeletedAt     `gorm:"index" json:"-"`
+	OrganizationID        uint               `gorm:"not null;uniqueIndex" json:"organization_id"`
+	Organization          *OrganizationModel `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
+	OrganizationPerMinute *int               `json:"organization_per_minute,omitempty"`
+	ContactPerMinute      *int               `json:"contact_per_minute,omitempty"`
+}
+
+func (OrganizationRateLimitModel) TableName() string {
+	return "organization_rate_limits"
+}
+
+// RateLimitHitModel counts the rejected requests per minute window.
+// OrganizationID is 0 when the request was rejected before the
+// organization was known.
+type RateLimitHitModel struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	OrganizationID uint      `gorm:"not null;default:0;uniqueIndex:idx_rate_limit_hits_window" json:"organization_id"`
+	Scope          string    `gorm:"not null;uniqueIndex:idx_rate_limit_hits_window" json:"scope"`
+	Route          string    `gorm:"not null;uniqueIndex:idx_rate_limit_hits_window" json:"route"`
+	WindowStart    time.Time `gorm:"not null;uniqueIndex:idx_rate_limit_hits_window" json:"window_start"`
+	Hits           int       `gorm:"not null;default:0" json:"hits"`
+}
+
+func (RateLimitHitModel) TableName() string {
+	return "rate_limit_hits"
+}
+
+// Constants for the rate limit scopes
+const (
+	RateLimitScopeOrganization = "organization"
+	RateLimitScopeContact      = "contact"
+	RateLimitScopeIP           = "ip"
+)
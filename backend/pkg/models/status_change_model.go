@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// StatusChangeModel records a status transition of a conversation or a
+// ticket. ConversationID is the conversation itself or the one the ticket
+// was opened from, it ties the change to the contact timeline.
+type StatusChangeModel struct {
+	ID             uint               `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time          `gorm:"index" json:"created_at"`
+	OrganizationID uint               `gorm:"not null;index" json:"organization_id"`
+	SubjectType    string             `gorm:"not null;index:idx_status_changes_subject" json:"subject_type"`
+	SubjectID      uint               `gorm:"not null;index:idx_status_changes_subject" json:"subject_id"`
+	ConversationID uint               `gorm:"not null;index" json:"conversation_id"`
+	Conversation   *ConversationModel `gorm:"foreignKey:ConversationID" json:"conversation,omitempty"`
+	FromStatus     string             `gorm:"not null" json:"from_status"`
+	ToStatus       string             `gorm:"not null" json:"to_status"`
+	ChangedByID    *uint              `json:"changed_by_id,omitempty"`
+	ChangedBy      *UserModel         `gorm:"foreignKey:ChangedByID" json:"changed_by,omitempty"`
+}
+
+func (StatusChangeModel) TableName() string {
+	return "status_changes"
+}
+
+// Constants for what a status change belongs to
+const (
+	StatusChangeSubjectConversation = "conversation"
+	StatusChangeSubjectTicket       = "ticket"
+)
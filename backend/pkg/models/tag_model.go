@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// TagModel is a tag on a ticket or a conversation, names are stored in
+// lower case.
+type TagModel struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time `json:"created_at"`
+	OrganizationID uint      `gorm:"not null;index:idx_tags_organization_name" json:"organization_id"`
+	SubjectType    string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_tags_subject_name" json:"subject_type"`
+	SubjectID      uint      `gorm:"not null;uniqueIndex:idx_tags_subject_name" json:"subject_id"`
+	Name           string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_tags_subject_name;index:idx_tags_organization_name" json:"name"`
+}
+
+func (TagModel) TableName() string {
+	return "tags"
+}
+
+// Constants for what a tag belongs to
+const (
+	TagSubjectTicket       = "ticket"
+	TagSubjectConversation = "conversation"
+)
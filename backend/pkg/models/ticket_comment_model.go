@@ -0,0 +1,80 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TicketCommentModel is a staff comment on a ticket. Public comments are
+// also sent to the customer as a message of the ticket's conversation,
+// MessageID points at that message.
+type TicketCommentModel struct {
+	ID             uint                      `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time                 `gorm:"index" json:"created_at"`
+	UpdatedAt      time.Time                 `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt            `gorm:"index" json:"-"`
+	OrganizationID uint                      `gorm:"not null;index" json:"organization_id"`
+	TicketID       uint                      `gorm:"not null;index" json:"ticket_id"`
+	Ticket         *TicketModel              `gorm:"foreignKey:TicketID" json:"ticket,omitempty"`
+	AuthorID       uint                      `gorm:"not null" json:"author_id"`
+	Author         *UserModel                `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
+	Visibility     string                    `gorm:"type:varchar(20);not null;default:'internal'" json:"visibility"`
+	Body           string                    `gorm:"type:text;not null" json:"body"`
+	MessageID      *uint                     `json:"message_id,omitempty"`
+	Message        *ConversationMessageModel `gorm:"foreignKey:MessageID" json:"message,omitempty"`
+}
+
+func (TicketCommentModel) TableName() string {
+	return "ticket_comments"
+}
+
+// Constants for who can read a ticket comment
+const (
+	TicketCommentInternal = "internal"
+	TicketCommentPublic   = "public"
+)
+
+var ErrTicketActivityImmutable = errors.New("ticket activity can not be changed")
+
+// TicketActivityModel is one entry of a ticket's history. Updates record
+// a row per changed field with the old and the new value, the rows are
+// never changed afterwards.
+type TicketActivityModel struct {
+	ID             uint       `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time  `gorm:"index" json:"created_at"`
+	OrganizationID uint       `gorm:"not null;index" json:"organization_id"`
+	TicketID       uint       `gorm:"not null;index" json:"ticket_id"`
+	ActorID        *uint      `json:"actor_id,omitempty"`
+	Actor          *UserModel `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+	Action         string     `gorm:"type:varchar(20);not null" json:"action"`
+	Field          *string    `gorm:"type:varchar(50)" json:"field,omitempty"`
+	OldValue       *string    `gorm:"type:text" json:"old_value,omitempty"`
+	NewValue       *string    `gorm:"type:text" json:"new_value,omitempty"`
+	CommentID      *uint      `json:"comment_id,omitempty"`
+}
+
+func (TicketActivityModel) TableName() string {
+	return "ticket_activities"
+}
+
+func (a *TicketActivityModel) BeforeUpdate(tx *gorm.DB) error {
+	return ErrTicketActivityImmutable
+}
+
+func (a *TicketActivityModel) BeforeDelete(tx *gorm.DB) error {
+	return ErrTicketActivityImmutable
+}
+
+// Constants for the ticket activity actions
+const (
+	TicketActivityCreated   = "created"
+	TicketActivityUpdated   = "updated"
+	TicketActivityCommented = "commented"
+
+	// Field is the link type or conversation, the values the linked
+	// ticket number or conversation id
+	TicketActivityLinked   = "linked"
+	TicketActivityUnlinked = "unlinked"
+)
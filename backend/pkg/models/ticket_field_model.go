@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// TicketFieldDefinitionModel is a custom field the organization tracks on
+// its tickets, e.g. order number or refund amount. Options lists the
+// allowed values of an enum. Min and Max bound a number, or the length of
+// a string, and Pattern is a regular expression a string must match.
+type TicketFieldDefinitionModel struct {
+	ID             uint               `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+	OrganizationID uint               `gorm:"not null;uniqueIndex:idx_ticket_field_definitions_key" json:"organization_id"`
+	Organization   *OrganizationModel `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
+	Key            string             `gorm:"type:varchar(64);not null;uniqueIndex:idx_ticket_field_definitions_key" json:"key"`
+	Label          string             `gorm:"not null" json:"label"`
+	Type           string             `gorm:"type:varchar(20);not null" json:"type"`
+	Required       bool               `gorm:"not null;default:false" json:"required"`
+	Options        []string           `gorm:"type:json;serializer:json" json:"options,omitempty"`
+	Min            *float64           `json:"min,omitempty"`
+	Max            *float64           `json:"max,omitempty"`
+	Pattern        *string            `gorm:"type:varchar(255)" json:"pattern,omitempty"`
+}
+
+func (TicketFieldDefinitionModel) TableName() string {
+	return "ticket_field_definitions"
+}
+
+// TicketFieldValueModel is the value of one custom field on a ticket,
+// stored normalized as text so filters can compare it directly.
+type TicketFieldValueModel struct {
+	ID             uint                        `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time                   `json:"created_at"`
+	UpdatedAt      time.Time                   `json:"updated_at"`
+	OrganizationID uint                        `gorm:"not null;index" json:"organization_id"`
+	TicketID       uint                        `gorm:"not null;uniqueIndex:idx_ticket_field_values_ticket" json:"ticket_id"`
+	DefinitionID   uint                        `gorm:"not null;uniqueIndex:idx_ticket_field_values_ticket" json:"definition_id"`
+	Definition     *TicketFieldDefinitionModel `gorm:"foreignKey:DefinitionID" json:"definition,omitempty"`
+	Value          string                      `gorm:"type:varchar(1000);not null" json:"value"`
+}
+
+func (TicketFieldValueModel) TableName() string {
+	return "ticket_field_values"
+}
+
+// Constants for the ticket field types, the same as the contact attribute
+// types
+const (
+	TicketFieldTypeString  = ContactAttributeTypeString
+	TicketFieldTypeNumber  = ContactAttributeTypeNumber
+	TicketFieldTypeDate    = ContactAttributeTypeDate
+	TicketFieldTypeEnum    = ContactAttributeTypeEnum
+	TicketFieldTypeBoolean = ContactAttributeTypeBoolean
+)
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// TicketLinkModel relates two tickets of an organization. For a parent
+// link TicketID is the parent of LinkedTicketID, for a duplicate link
+// TicketID is a duplicate of LinkedTicketID. Related links go both ways.
+type TicketLinkModel struct {
+	ID             uint         `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time    `json:"created_at"`
+	OrganizationID uint         `gorm:"not null;index" json:"organization_id"`
+	TicketID       uint         `gorm:"not null;uniqueIndex:idx_ticket_links_pair" json:"ticket_id"`
+	Ticket         *TicketModel `gorm:"foreignKey:TicketID" json:"ticket,omitempty"`
+	LinkedTicketID uint         `gorm:"not null;uniqueIndex:idx_ticket_links_pair;index" json:"linked_ticket_id"`
+	LinkedTicket   *TicketModel `gorm:"foreignKey:LinkedTicketID" json:"linked_ticket,omitempty"`
+	Type           string       `gorm:"type:varchar(20);not null" json:"type"`
+	CreatedByID    *uint        `json:"created_by_id,omitempty"`
+}
+
+func (TicketLinkModel) TableName() string {
+	return "ticket_links"
+}
+
+// Constants for the stored ticket link types
+const (
+	TicketLinkParent    = "parent"
+	TicketLinkDuplicate = "duplicate"
+	TicketLinkRelated   = "related"
+)
+
+// Constants for a link as seen from one of its tickets
+const (
+	TicketLinkParentOf     = "parent_of"
+	TicketLinkChildOf      = "child_of"
+	TicketLinkDuplicateOf  = "duplicate_of"
+	TicketLinkDuplicatedBy = "duplicated_by"
+	TicketLinkRelatedTo    = "related_to"
+)
+
+// TicketConversationModel is a conversation the ticket covers. The
+// conversation the ticket was created from is always one of them.
+type TicketConversationModel struct {
+	ID             uint               `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time          `json:"created_at"`
+	OrganizationID uint               `gorm:"not null;index" json:"organization_id"`
+	TicketID       uint               `gorm:"not null;uniqueIndex:idx_ticket_conversations_pair" json:"ticket_id"`
+	ConversationID uint               `gorm:"not null;uniqueIndex:idx_ticket_conversations_pair;index" json:"conversation_id"`
+	Conversation   *ConversationModel `gorm:"foreignKey:ConversationID" json:"conversation,omitempty"`
+}
+
+func (TicketConversationModel) TableName() string {
+	return "ticket_conversations"
+}
@@ -8,27 +8,59 @@ import (
 
 type TicketModel struct {
 	ID             uint               `gorm:"primarykey" json:"id"`
-	CreatedAt      time.Time          `json:"created_at"`
+	CreatedAt      time.Time          `gorm:"index:idx_tickets_organization_created_at,priority:2" json:"created_at"`
 	UpdatedAt      time.Time          `json:"updated_at"`
 	DeletedAt      gorm.DeletedAt     `gorm:"index" json:"-"`
-	OrganizationID uint               `gorm:"not null;index" json:"organization_id"`
+	OrganizationID uint               `gorm:"not null;index;uniqueIndex:idx_tickets_organization_ticket_number;index:idx_tickets_organization_created_at,priority:1;index:idx_tickets_organization_status,priority:1" json:"organization_id"`
 	Organization   *OrganizationModel `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
 	ConversationID uint               `gorm:"not null;index" json:"conversation_id"`
 	Conversation   *ConversationModel `gorm:"foreignKey:ConversationID" json:"conversation,omitempty"`
 	CreatedByID    uint               `gorm:"not null;index" json:"created_by_id"`
 	CreatedBy      *UserModel         `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
-	TicketNumber   string             `gorm:"uniqueIndex;not null" json:"ticket_number"`
+	TicketNumber   string             `gorm:"uniqueIndex:idx_tickets_organization_ticket_number;not null" json:"ticket_number"`
 	Name           string             `gorm:"not null" json:"name"`
-	Status         string             `gorm:"not null;default:'pending'" json:"status"`
+	Status         string             `gorm:"not null;default:'pending';index:idx_tickets_organization_status,priority:2" json:"status"`
+	StatusCategory string             `gorm:"type:varchar(20);not null;default:'open';index" json:"status_category"`
+	ResolutionNote *string            `gorm:"type:text" json:"resolution_note,omitempty"`
+	AssigneeID     *uint              `gorm:"index" json:"assignee_id,omitempty"`
+	Assignee       *UserModel         `gorm:"foreignKey:AssigneeID" json:"assignee,omitempty"`
+	Priority       string             `gorm:"type:varchar(20);not null;default:'normal';index" json:"priority"`
+	DueAt          *time.Time         `gorm:"index" json:"due_at,omitempty"`
+	Category       *string            `gorm:"type:varchar(100);index" json:"category,omitempty"`
+	Description    *string            `gorm:"type:text" json:"description,omitempty"`
+
+	// SLA deadlines of the matching policy, the warned and breached times
+	// are set once the scheduler handled the stage
+	SLAPolicyID   *uint                 `gorm:"index" json:"sla_policy_id,omitempty"`
+	SLAPolicy     *TicketSLAPolicyModel `gorm:"foreignKey:SLAPolicyID" json:"sla_policy,omitempty"`
+	SLAWarnAt     *time.Time            `gorm:"index" json:"sla_warn_at,omitempty"`
+	SLADueAt      *time.Time            `gorm:"index" json:"sla_due_at,omitempty"`
+	SLAWarnedAt   *time.Time            `json:"sla_warned_at,omitempty"`
+	SLABreachedAt *time.Time            `json:"sla_breached_at,omitempty"`
+	// SLAStartedAt restarts the clock of a reopened ticket, the deadlines
+	// count from the creation while it is not set
+	SLAStartedAt *time.Time `json:"sla_started_at,omitempty"`
+
+	// StatusLinkVersion is signed into the public status links, raising it
+	// stops every link handed out before
+	StatusLinkVersion uint `gorm:"not null;default:0" json:"-"`
 }
 
 func (TicketModel) TableName() string {
 	return "tickets"
 }
 
-// Constants for ticket status
+// Constants for the statuses of the default ticket workflow
 const (
 	TicketStatusPending    = "pending"
 	TicketStatusInProgress = "in_progress"
 	TicketStatusDone       = "done"
 )
+
+// Constants for ticket priority
+const (
+	TicketPriorityLow    = "low"
+	TicketPriorityNormal = "normal"
+	TicketPriorityHigh   = "high"
+	TicketPriorityUrgent = "urgent"
+)
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// TicketSequenceModel numbers the tickets of one organization and holds the
+// format of the numbers. The counter starts over when the date part of the
+// number changes, Period is the date part of the last number issued.
+type TicketSequenceModel struct {
+	ID             uint               `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+	OrganizationID uint               `gorm:"not null;uniqueIndex" json:"organization_id"`
+	Organization   *OrganizationModel `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
+	Prefix         string             `gorm:"type:varchar(20);not null;default:'TKT'" json:"prefix"`
+	DatePart       string             `gorm:"type:varchar(10);not null;default:'day'" json:"date_part"`
+	Padding        int                `gorm:"not null;default:4" json:"padding"`
+	Period         string             `gorm:"type:varchar(8);not null;default:''" json:"period"`
+	LastValue      uint               `gorm:"not null;default:0" json:"last_value"`
+}
+
+func (TicketSequenceModel) TableName() string {
+	return "ticket_sequences"
+}
+
+// Constants for the date part of a ticket number
+const (
+	TicketDatePartNone  = "none"
+	TicketDatePartYear  = "year"
+	TicketDatePartMonth = "month"
+	TicketDatePartDay   = "day"
+)
+
+// Defaults of a new ticket sequence
+const (
+	TicketNumberDefaultPrefix  = "TKT"
+	TicketNumberDefaultPadding = 4
+)
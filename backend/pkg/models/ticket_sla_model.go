@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TicketSLAPolicyModel sets how long tickets of a priority and a category
+// may stay open. An empty Priority or Category matches every ticket, the
+// most specific policy wins.
+type TicketSLAPolicyModel struct {
+	ID             uint                     `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time                `json:"created_at"`
+	UpdatedAt      time.Time                `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt           `gorm:"index" json:"-"`
+	OrganizationID uint                     `gorm:"not null;index" json:"organization_id"`
+	Name           string                   `gorm:"type:varchar(100);not null" json:"name"`
+	Priority       *string                  `gorm:"type:varchar(20)" json:"priority,omitempty"`
+	Category       *string                  `gorm:"type:varchar(100)" json:"category,omitempty"`
+	ResolveMinutes int                      `gorm:"not null" json:"resolve_minutes"`
+	WarnMinutes    int                      `gorm:"not null;default:0" json:"warn_minutes"`
+	WarningActions []TicketEscalationAction `gorm:"type:json;serializer:json;not null" json:"warning_actions"`
+	BreachActions  []TicketEscalationAction `gorm:"type:json;serializer:json;not null" json:"breach_actions"`
+	Active         bool                     `gorm:"not null;default:true" json:"active"`
+}
+
+func (TicketSLAPolicyModel) TableName() string {
+	return "ticket_sla_policies"
+}
+
+// TicketEscalationAction is what happens when a ticket reaches a stage of
+// its policy, AssigneeID is the new assignee of a reassign action.
+type TicketEscalationAction struct {
+	Type       string `json:"type"`
+	AssigneeID *uint  `json:"assignee_id,omitempty"`
+}
+
+// Constants for the escalation action types
+const (
+	TicketEscalationNotifyOwner  = "notify_owner"
+	TicketEscalationBumpPriority = "bump_priority"
+	TicketEscalationReassign     = "reassign"
+	TicketEscalationFireEvent    = "fire_event"
+)
+
+// TicketEscalationModel records a ticket reaching the warning or the breach
+// of its policy and the outcome of every action that ran.
+type TicketEscalationModel struct {
+	ID             uint                     `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time                `gorm:"index" json:"created_at"`
+	OrganizationID uint                     `gorm:"not null;index" json:"organization_id"`
+	TicketID       uint                     `gorm:"not null;index" json:"ticket_id"`
+	PolicyID       *uint                    `json:"policy_id,omitempty"`
+	Stage          string                   `gorm:"type:varchar(20);not null" json:"stage"`
+	SLADueAt       time.Time                `json:"sla_due_at"`
+	Results        []TicketEscalationResult `gorm:"type:json;serializer:json;not null" json:"results"`
+}
+
+func (TicketEscalationModel) TableName() string {
+	return "ticket_escalations"
+}
+
+type TicketEscalationResult struct {
+	Type   string  `json:"type"`
+	Detail string  `json:"detail"`
+	Error  *string `json:"error,omitempty"`
+}
+
+// Constants for the stages of a ticket SLA
+const (
+	TicketSLAStageWarning  = "warning"
+	TicketSLAStageBreached = "breached"
+)
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// TicketTimeEntryModel is time a staff member spent on a ticket, logged by
+// hand or by a timer. A running timer has no EndedAt yet and its duration
+// is filled in when it is stopped.
+type TicketTimeEntryModel struct {
+	ID              uint         `gorm:"primarykey" json:"id"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+	OrganizationID  uint         `gorm:"not null;index:idx_ticket_time_entries_organization_started" json:"organization_id"`
+	TicketID        uint         `gorm:"not null;index" json:"ticket_id"`
+	Ticket          *TicketModel `gorm:"foreignKey:TicketID" json:"ticket,omitempty"`
+	UserID          uint         `gorm:"not null;index:idx_ticket_time_entries_user_ended" json:"user_id"`
+	User            *UserModel   `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	StartedAt       time.Time    `gorm:"not null;index:idx_ticket_time_entries_organization_started" json:"started_at"`
+	EndedAt         *time.Time   `gorm:"index:idx_ticket_time_entries_user_ended" json:"ended_at,omitempty"`
+	DurationSeconds int          `gorm:"not null;default:0" json:"duration_seconds"`
+	Note            *string      `gorm:"type:varchar(1000)" json:"note,omitempty"`
+	Billable        bool         `gorm:"not null;default:false" json:"billable"`
+}
+
+func (TicketTimeEntryModel) TableName() string {
+	return "ticket_time_entries"
+}
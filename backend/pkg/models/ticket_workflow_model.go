@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// TicketWorkflowModel is the ticket pipeline of an organization: the
+// statuses a ticket can be in, the moves allowed between them and the
+// status new tickets start in.
+type TicketWorkflowModel struct {
+	ID             uint                       `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time                  `json:"created_at"`
+	UpdatedAt      time.Time                  `json:"updated_at"`
+	OrganizationID uint                       `gorm:"not null;uniqueIndex" json:"organization_id"`
+	InitialStatus  string                     `gorm:"type:varchar(50);not null" json:"initial_status"`
+	Statuses       []TicketWorkflowStatus     `gorm:"type:json;serializer:json;not null" json:"statuses"`
+	Transitions    []TicketWorkflowTransition `gorm:"type:json;serializer:json;not null" json:"transitions"`
+}
+
+func (TicketWorkflowModel) TableName() string {
+	return "ticket_workflows"
+}
+
+// TicketWorkflowStatus is a status of a workflow. Closed statuses end the
+// work on a ticket, moving into a status with RequiresResolutionNote needs
+// a resolution note.
+type TicketWorkflowStatus struct {
+	Key                    string `json:"key"`
+	Name                   string `json:"name"`
+	Category               string `json:"category"`
+	RequiresResolutionNote bool   `json:"requires_resolution_note"`
+}
+
+type TicketWorkflowTransition struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Constants for the category of a ticket status
+const (
+	TicketStatusCategoryOpen   = "open"
+	TicketStatusCategoryClosed = "closed"
+)
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WebhookInboxEventModel is the raw webhook request persisted before it is
+// turned into a conversation message by the inbox workers.
+type WebhookInboxEventModel struct {
+	ID             uint               `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt     `gorm:"index" json:"-"`
+	OrganizationID uint               `gorm:"not null;index" json:"organization_id"`
+	Organization   *OrganizationModel `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
+	// ContactKey groups the events of one sender so they are processed in order
+	ContactKey    string     `gorm:"not null;index" json:"contact_key"`
+	Payload       []byte     `gorm:"type:mediumblob;not null" json:"-"`
+	Status        string     `gorm:"not null;default:'pending';index" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"not null;index" json:"next_attempt_at"`
+	LastError     *string    `gorm:"type:text" json:"last_error,omitempty"`
+	ProcessedAt   *time.Time `json:"processed_at,omitempty"`
+}
+
+func (WebhookInboxEventModel) TableName() string {
+	return "webhook_inbox_events"
+}
+
+// Constants for webhook inbox event status
+const (
+	WebhookInboxStatusPending    = "pending"
+	WebhookInboxStatusProcessing = "processing"
+	WebhookInboxStatusProcessed  = "processed"
+	WebhookInboxStatusFailed     = "failed"
+	WebhookInboxStatusParked     = "parked"
+)
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// WidgetSessionModel is the session of an anonymous chat widget visitor,
+// it is scoped to one organization and acts as the visitor's contact. Only
+// the sha256 of the token is stored.
+type WidgetSessionModel struct {
+	ID             uint               `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+	OrganizationID uint               `gorm:"not null;index" json:"organization_id"`
+	Organization   *OrganizationModel `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
+	ContactID      uint               `gorm:"not null;index" json:"contact_id"`
+	Contact        *ContactModel      `gorm:"foreignKey:ContactID" json:"contact,omitempty"`
+	TokenHash      string             `gorm:"type:char(64);not null;uniqueIndex" json:"-"`
+	ExpiresAt      time.Time          `gorm:"not null" json:"expires_at"`
+}
+
+func (WidgetSessionModel) TableName() string {
+	return "widget_sessions"
+}
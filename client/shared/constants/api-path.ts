@@ -3,12 +3,15 @@ export const BASE_API = "/api/v1";
 export const API_AUTH_SIGNIN = BASE_API + "/auth/login";
 export const API_AUTH_SIGNUP = BASE_API + "/auth/register";
 export const API_AUTH_REFRESH = BASE_API + "/auth/refresh";
+export const API_AUTH_MAGIC_LINK = BASE_API + "/auth/magic-link";
+export const API_AUTH_MAGIC_LINK_VERIFY = BASE_API + "/auth/magic-link/verify";
 
 export const API_AUTH_PROFILE = BASE_API + "/auth/profile";
 
 export const API_GUEST_CONVERSATION = BASE_API + "/guest/conversations";
 export const API_GUEST_CONVERSATION_MESSAGES =
   BASE_API + "/guest/conversations/messages";
+export const API_GUEST_TICKETS = BASE_API + "/guest/tickets";
 
 export const HUB_ORGANIZATION = BASE_API + "/hub/organizations";
 
@@ -17,5 +20,21 @@ export const ORGANIZATION = BASE_API + "/organization";
 export const ORG_CONVERSATION = BASE_API + "/organization/conversations";
 export const ORG_STAFF = BASE_API + "/organization/conversations";
 export const ORG_TICKET = BASE_API + "/organization/ticket";
-
-
+export const ORG_TICKET_EXPORT = BASE_API + "/organizations/ticket/export";
+export const ORG_TICKET_IMPORT = BASE_API + "/organizations/ticket/import";
+export const ORG_SETTINGS = BASE_API + "/organizations/settings";
+export const ORG_TICKET_NUMBERING = BASE_API + "/organizations/ticket-numbering";
+export const ORG_TICKET_WORKFLOW = BASE_API + "/organizations/ticket-workflow";
+export const ORG_TICKET_SLA_POLICIES = BASE_API + "/organizations/ticket-sla-policies";
+export const ORG_TICKET_FIELDS = BASE_API + "/organizations/ticket-fields";
+export const ORG_TIME_REPORT = BASE_API + "/organizations/time-report";
+export const ORG_TICKET_BULK = BASE_API + "/organizations/ticket/bulk";
+export const ORG_CONVERSATION_BULK = BASE_API + "/organizations/conversations/bulk";
+export const ORG_BULK_JOB = (id: number) =>
+  BASE_API + "/organizations/bulk-jobs/" + id;
+export const ORG_WIDGET = BASE_API + "/organizations/widget";
+
+export const API_WIDGET = (orgSlug: string) =>
+  BASE_API + "/widget/" + encodeURIComponent(orgSlug);
+export const API_TICKET_STATUS = (token: string) =>
+  BASE_API + "/ticket-status/" + encodeURIComponent(token);
@@ -3,13 +3,31 @@ import { UserDataSchema } from "./auth-response.schema";
 import { PaginateMetaDataSchema } from "./pagination-response.schema";
 import { OrganizationResponseSchema } from "./organization-response.schema";
 
+export const ContactIdentitySchema = z.object({
+  id: z.number().int().nonnegative(),
+  type: z.string(),
+  value: z.string(),
+});
+
+export const ContactDataSchema = z.object({
+  id: z.number().int().nonnegative(),
+  name: z.string(),
+  email: z.string().nullable().optional(),
+  phone: z.string().nullable().optional(),
+  avatarUrl: z.string().nullable().optional(),
+  identities: z.array(ContactIdentitySchema).optional(),
+  attributes: z.record(z.string(), z.unknown()).optional(),
+});
+
 export const ConversationMessageResponseSchema = z.object({
   id: z.number().int().nonnegative(),
   organizationId: z.number().int().nonnegative(),
   conversationId: z.number().int().nonnegative(),
 
-  createdById: z.number().int().nonnegative(),
+  createdById: z.number().int().nonnegative().nullable().optional(),
   createdBy: UserDataSchema.optional(),
+  contactId: z.number().int().nonnegative().nullable().optional(),
+  contact: ContactDataSchema.nullable().optional(),
 
   message: z.string(),
 
@@ -27,16 +45,38 @@ export type ConversationMessagePaginate = z.infer<
 >;
 
 
+export const ConversationRatingSchema = z.object({
+  conversationId: z.number().int().nonnegative(),
+  score: z.number().int().min(1).max(5),
+  comment: z.string().nullable().optional(),
+  createdAt: z.coerce.date(),
+  updatedAt: z.coerce.date(),
+});
+
+export type ConversationRating = z.infer<typeof ConversationRatingSchema>;
+
+export const ContactSummarySchema = z.object({
+  totalConversations: z.number().int().nonnegative(),
+  openConversations: z.number().int().nonnegative(),
+  openTickets: z.number().int().nonnegative(),
+  averageRating: z.number().nullable().optional(),
+  lastContactAt: z.coerce.date().nullable().optional(),
+});
+
 export const ConversationResponseSchema = z.object({
   id: z.number().int().nonnegative(),
   organizationId: z.number().int().nonnegative(),
   organization: OrganizationResponseSchema.nullable().optional(),
-  guestId: z.number().int().nonnegative(),
+  guestId: z.number().int().nonnegative().nullable().optional(),
   guest: UserDataSchema.nullable().optional(),
+  contactId: z.number().int().nonnegative(),
+  contact: ContactDataSchema.nullable().optional(),
   organizationStaffId: z.number().int().nonnegative().nullable().optional(),
   organizationStaff: UserDataSchema.nullable().optional(),
   messages: ConversationMessageResponseSchema.array().default([]),
   status: z.string(),
+  rating: ConversationRatingSchema.nullable().optional(),
+  contactSummary: ContactSummarySchema.nullable().optional(),
   createdAt: z.coerce.date(),
   updatedAt: z.coerce.date(),
 });
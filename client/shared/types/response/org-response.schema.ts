@@ -37,6 +37,23 @@ export const TicketResponseSchema = z.object({
   ticketNumber: z.string(),
   name: z.string(),
   status: z.string(),
+  statusCategory: z.enum(["open", "closed"]),
+  resolutionNote: z.string().optional(),
+
+  assigneeId: z.number().int().nonnegative().optional(),
+  assignee: UserDataSchema.optional(),
+  priority: z.enum(["low", "normal", "high", "urgent"]),
+  dueAt: z.coerce.date().optional(),
+  overdue: z.boolean(),
+  category: z.string().optional(),
+  description: z.string().optional(),
+
+  slaPolicyId: z.number().int().nonnegative().optional(),
+  slaDueAt: z.coerce.date().optional(),
+  slaBreached: z.boolean(),
+
+  tags: z.array(z.string()),
+  fields: z.record(z.any()),
 
   createdAt: z.coerce.date(),
   updatedAt: z.coerce.date(),